@@ -0,0 +1,90 @@
+package analytics
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// OptOuts reads the analytics preferences of users
+type OptOuts interface {
+	// OptedOut returns the IDs among userIDs of the users who opted out of analytics
+	OptedOut(ctx context.Context, userIDs []string) (map[string]bool, error)
+}
+
+// UserOptOuts reads the analytics_opt_out flag of the users in the primary database, with one query per
+// batch of events
+type UserOptOuts struct {
+	mongoDB    *database.MongoDB
+	postgresDB *database.PostgresDB
+}
+
+// NewUserOptOuts reads the preferences from PostgreSQL when both databases are configured
+func NewUserOptOuts(mongoDB *database.MongoDB, postgresDB *database.PostgresDB) *UserOptOuts {
+	return &UserOptOuts{mongoDB: mongoDB, postgresDB: postgresDB}
+}
+
+// OptedOut implements OptOuts. IDs that are not valid for the database belong to no user and are not
+// reported; deleted users keep their preference.
+func (o *UserOptOuts) OptedOut(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	optedOut := make(map[string]bool)
+
+	// PostgreSQL implementation
+	if o.postgresDB != nil {
+		var ids []string
+		for _, userID := range userIDs {
+			if o.postgresDB.ValidUserID(userID) {
+				ids = append(ids, userID)
+			}
+		}
+		if len(ids) == 0 {
+			return optedOut, nil
+		}
+		var found []string
+		err := o.postgresDB.WithContext(ctx).Unscoped().Model(&models.User{}).
+			Where("id IN ? AND analytics_opt_out", ids).Pluck("id", &found).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range found {
+			optedOut[id] = true
+		}
+		return optedOut, nil
+	}
+
+	// MongoDB implementation
+	if o.mongoDB != nil {
+		var ids []primitive.ObjectID
+		for _, userID := range userIDs {
+			if id, err := primitive.ObjectIDFromHex(userID); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return optedOut, nil
+		}
+		cursor, err := o.mongoDB.Collection("users").Find(ctx,
+			bson.M{"_id": bson.M{"$in": ids}, "analytics_opt_out": true},
+			options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return nil, err
+		}
+		var found []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &found); err != nil {
+			return nil, err
+		}
+		for _, user := range found {
+			optedOut[user.ID.Hex()] = true
+		}
+		return optedOut, nil
+	}
+
+	return optedOut, nil
+}
@@ -0,0 +1,233 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm/clause"
+
+	"go-backend-template/database"
+	"go-backend-template/httpclient"
+	"go-backend-template/models"
+)
+
+// Sink is a destination for batches of product events
+type Sink interface {
+	Write(ctx context.Context, events []models.AnalyticsEvent) error
+	Close() error
+}
+
+// NoopSink discards all events
+type NoopSink struct{}
+
+// Write discards the events
+func (NoopSink) Write(ctx context.Context, events []models.AnalyticsEvent) error { return nil }
+
+// Close is a no-op
+func (NoopSink) Close() error { return nil }
+
+// PostgresSink stores events in the analytics_events table of PostgreSQL
+type PostgresSink struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresSink creates a PostgreSQL-backed sink; the table is created by the migrations
+func NewPostgresSink(db *database.PostgresDB) *PostgresSink {
+	return &PostgresSink{db: db}
+}
+
+// Write inserts the events in one statement, skipping IDs that are already stored
+func (s *PostgresSink) Write(ctx context.Context, events []models.AnalyticsEvent) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&events).Error
+}
+
+// Close is a no-op; the database is closed by its owner
+func (s *PostgresSink) Close() error { return nil }
+
+// MongoSink stores events in the analytics_events collection of MongoDB
+type MongoSink struct {
+	collection *mongo.Collection
+}
+
+// NewMongoSink creates a MongoDB-backed sink and ensures the indexes of event name, user, and time exist
+func NewMongoSink(ctx context.Context, db *database.MongoDB) (*MongoSink, error) {
+	collection := db.Collection("analytics_events")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "occurred_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "occurred_at", Value: -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoSink{collection: collection}, nil
+}
+
+// Write inserts the events in one unordered bulk insert; duplicate IDs are skipped
+func (s *MongoSink) Write(ctx context.Context, events []models.AnalyticsEvent) error {
+	documents := make([]interface{}, len(events))
+	for i, event := range events {
+		documents[i] = event
+	}
+	_, err := s.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicates(err) {
+		return err
+	}
+	return nil
+}
+
+// Close is a no-op; the database is closed by its owner
+func (s *MongoSink) Close() error { return nil }
+
+// onlyDuplicates reports whether every write of a bulk insert failed with a duplicate key error
+func onlyDuplicates(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
+
+// SegmentSink sends events to the batch endpoint of the Segment HTTP Tracking API
+type SegmentSink struct {
+	endpoint string
+	writeKey string
+	client   *http.Client
+}
+
+// NewSegmentSink creates a sink posting to endpoint (https://api.segment.io, or a regional one) with the
+// write key of a Segment source
+func NewSegmentSink(endpoint, writeKey string, timeout time.Duration) *SegmentSink {
+	return &SegmentSink{
+		endpoint: strings.TrimSuffix(endpoint, "/") + "/v1/batch",
+		writeKey: writeKey,
+		client:   httpclient.New("segment", timeout),
+	}
+}
+
+// Write sends the events as track calls; events without a user use their ID as anonymous ID
+func (s *SegmentSink) Write(ctx context.Context, events []models.AnalyticsEvent) error {
+	type track struct {
+		Type        string                 `json:"type"`
+		MessageID   string                 `json:"messageId"`
+		UserID      string                 `json:"userId,omitempty"`
+		AnonymousID string                 `json:"anonymousId,omitempty"`
+		Event       string                 `json:"event"`
+		Properties  map[string]interface{} `json:"properties,omitempty"`
+		Timestamp   time.Time              `json:"timestamp"`
+	}
+	batch := make([]track, len(events))
+	for i, event := range events {
+		batch[i] = track{
+			Type:       "track",
+			MessageID:  event.ID,
+			UserID:     event.UserID,
+			Event:      event.Name,
+			Properties: event.Properties,
+			Timestamp:  event.OccurredAt,
+		}
+		if event.UserID == "" {
+			batch[i].AnonymousID = event.ID
+		}
+	}
+
+	return postJSON(ctx, s.client, s.endpoint, map[string]interface{}{"batch": batch, "sentAt": time.Now().UTC()},
+		func(req *http.Request) { req.SetBasicAuth(s.writeKey, "") })
+}
+
+// Close is a no-op for the Segment sink
+func (s *SegmentSink) Close() error { return nil }
+
+// PostHogSink sends events to the batch endpoint of PostHog
+type PostHogSink struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewPostHogSink creates a sink posting to host (https://us.i.posthog.com, https://eu.i.posthog.com, or
+// a self-hosted instance) with the project API key
+func NewPostHogSink(host, apiKey string, timeout time.Duration) *PostHogSink {
+	return &PostHogSink{
+		endpoint: strings.TrimSuffix(host, "/") + "/batch/",
+		apiKey:   apiKey,
+		client:   httpclient.New("posthog", timeout),
+	}
+}
+
+// Write sends the events as captures; events without a user use their ID as distinct ID and create no
+// person profile
+func (s *PostHogSink) Write(ctx context.Context, events []models.AnalyticsEvent) error {
+	type capture struct {
+		UUID       string                 `json:"uuid"`
+		Event      string                 `json:"event"`
+		DistinctID string                 `json:"distinct_id"`
+		Properties map[string]interface{} `json:"properties"`
+		Timestamp  time.Time              `json:"timestamp"`
+	}
+	batch := make([]capture, len(events))
+	for i, event := range events {
+		properties := make(map[string]interface{}, len(event.Properties)+1)
+		for key, value := range event.Properties {
+			properties[key] = value
+		}
+		distinctID := event.UserID
+		if distinctID == "" {
+			distinctID = event.ID
+			properties["$process_person_profile"] = false
+		}
+		batch[i] = capture{
+			UUID:       event.ID,
+			Event:      event.Name,
+			DistinctID: distinctID,
+			Properties: properties,
+			Timestamp:  event.OccurredAt,
+		}
+	}
+
+	return postJSON(ctx, s.client, s.endpoint, map[string]interface{}{"api_key": s.apiKey, "batch": batch}, nil)
+}
+
+// Close is a no-op for the PostHog sink
+func (s *PostHogSink) Close() error { return nil }
+
+// postJSON posts body as JSON, letting authorize add credentials, and fails on a non-2xx status
+func postJSON(ctx context.Context, client *http.Client, url string, body interface{}, authorize func(*http.Request)) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorize != nil {
+		authorize(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send analytics events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("analytics endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Package analytics records product events, such as signups, logins, and the use of features, and sends
+// them in batches to a sink: the database, Segment, or PostHog. Events of users who opted out, and of
+// requests sent with Do Not Track or Global Privacy Control, are not recorded.
+package analytics
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// Product event names
+const (
+	EventSignup      = "signup"
+	EventLogin       = "login"
+	EventFeatureUsed = "feature_used"
+)
+
+// Options configures a Tracker
+type Options struct {
+	// BatchSize is the most events sent to the sink at once
+	BatchSize int
+	// FlushInterval is the longest an event waits for its batch to fill
+	FlushInterval time.Duration
+	// QueueSize is how many events may wait to be sent; further events are dropped
+	QueueSize int
+}
+
+// Tracker queues events and sends them to the sink from a background goroutine. Delivery is at most once:
+// events are dropped when the queue is full or the sink fails. A nil Tracker discards events.
+type Tracker struct {
+	sink    Sink
+	optOuts OptOuts
+	opts    Options
+	logger  utils.Logger
+
+	queue     chan models.AnalyticsEvent
+	stop      chan struct{}
+	done      chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// NewTracker creates a tracker sending to sink. Before each batch is sent, optOuts removes the events of
+// users who opted out; it may be nil when events are not tied to stored users.
+func NewTracker(sink Sink, optOuts OptOuts, opts Options, logger utils.Logger) *Tracker {
+	opts.BatchSize = max(opts.BatchSize, 1)
+	opts.QueueSize = max(opts.QueueSize, opts.BatchSize)
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 10 * time.Second
+	}
+	return &Tracker{
+		sink:    sink,
+		optOuts: optOuts,
+		opts:    opts,
+		logger:  logger,
+		queue:   make(chan models.AnalyticsEvent, opts.QueueSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Track queues an event without blocking, filling in its ID and time when unset
+func (t *Tracker) Track(event models.AnalyticsEvent) {
+	if t == nil {
+		return
+	}
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	select {
+	case t.queue <- event:
+	default:
+		t.logger.Warn("Analytics queue is full; dropping event", "event", event.Name)
+	}
+}
+
+// TrackRequest queues an event of the user behind a request, unless the client asked not to be tracked
+// with DNT: 1 or Sec-GPC: 1
+func (t *Tracker) TrackRequest(c *gin.Context, name, userID string, properties map[string]interface{}) {
+	if t == nil || DoNotTrack(c.Request.Header.Get("DNT"), c.Request.Header.Get("Sec-GPC")) {
+		return
+	}
+	t.Track(models.AnalyticsEvent{Name: name, UserID: userID, Properties: properties})
+}
+
+// DoNotTrack reports whether the DNT or Sec-GPC header values ask not to be tracked
+func DoNotTrack(dnt, gpc string) bool {
+	return strings.TrimSpace(dnt) == "1" || strings.TrimSpace(gpc) == "1"
+}
+
+// Start sends the queued events in the background until Close is called
+func (t *Tracker) Start() {
+	if t == nil {
+		return
+	}
+	t.startOnce.Do(func() {
+		go t.run()
+	})
+}
+
+// Close stops the background goroutine once it has sent the queued events, waiting at most until ctx is
+// done, and closes the sink
+func (t *Tracker) Close(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.Start()
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		t.logger.Warn("Analytics events were not all sent before shutdown", "queued", len(t.queue))
+	}
+	return t.sink.Close()
+}
+
+// run collects events into batches, sending one when it is full or FlushInterval has passed
+func (t *Tracker) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.AnalyticsEvent, 0, t.opts.BatchSize)
+	for {
+		select {
+		case event := <-t.queue:
+			batch = append(batch, event)
+			if len(batch) >= t.opts.BatchSize {
+				batch = t.flush(batch)
+			}
+		case <-ticker.C:
+			batch = t.flush(batch)
+		case <-t.stop:
+			for {
+				select {
+				case event := <-t.queue:
+					batch = append(batch, event)
+					if len(batch) >= t.opts.BatchSize {
+						batch = t.flush(batch)
+					}
+				default:
+					t.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush sends the events of users who did not opt out and returns the emptied batch
+func (t *Tracker) flush(batch []models.AnalyticsEvent) []models.AnalyticsEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	events := t.consented(ctx, batch)
+	if len(events) > 0 {
+		if err := t.sink.Write(ctx, events); err != nil {
+			t.logger.Error("Failed to send analytics events", "events", len(events), "error", err)
+		}
+	}
+	return batch[:0]
+}
+
+// consented returns the events whose users did not opt out. When the preferences cannot be read, only the
+// events without a user are kept.
+func (t *Tracker) consented(ctx context.Context, batch []models.AnalyticsEvent) []models.AnalyticsEvent {
+	if t.optOuts == nil {
+		return append([]models.AnalyticsEvent(nil), batch...)
+	}
+
+	var userIDs []string
+	seen := make(map[string]bool)
+	for _, event := range batch {
+		if event.UserID != "" && !seen[event.UserID] {
+			seen[event.UserID] = true
+			userIDs = append(userIDs, event.UserID)
+		}
+	}
+
+	optedOut := map[string]bool{}
+	if len(userIDs) > 0 {
+		var err error
+		optedOut, err = t.optOuts.OptedOut(ctx, userIDs)
+		if err != nil {
+			t.logger.Error("Failed to read analytics opt-outs; dropping events of users", "error", err)
+			optedOut = seen
+		}
+	}
+
+	events := make([]models.AnalyticsEvent, 0, len(batch))
+	for _, event := range batch {
+		if !optedOut[event.UserID] {
+			events = append(events, event)
+		}
+	}
+	return events
+}
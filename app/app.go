@@ -0,0 +1,1023 @@
+// Package app wires the application together. New constructs every component from the configuration in
+// dependency order (logger, secrets, databases, stores, services, handlers, and routes) and registers
+// lifecycle hooks for the ones that run in the background or hold resources; Run starts them, serves
+// HTTP, and stops them on shutdown. The run mode (config.ModeAll and the others) selects which of them a
+// process wires.
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/analytics"
+	"go-backend-template/audit"
+	"go-backend-template/billing"
+	"go-backend-template/config"
+	"go-backend-template/database"
+	"go-backend-template/handlers"
+	"go-backend-template/hooks"
+	"go-backend-template/httpclient"
+	"go-backend-template/idempotency"
+	"go-backend-template/jobs"
+	"go-backend-template/middleware"
+	"go-backend-template/migrate"
+	"go-backend-template/migrations"
+	"go-backend-template/models"
+	"go-backend-template/oauth"
+	"go-backend-template/operations"
+	"go-backend-template/posts"
+	"go-backend-template/pwned"
+	"go-backend-template/realtime"
+	"go-backend-template/routes"
+	"go-backend-template/secrets"
+	"go-backend-template/security"
+	"go-backend-template/services"
+	"go-backend-template/sessions"
+	"go-backend-template/suppression"
+	"go-backend-template/transfer"
+	"go-backend-template/translations"
+	"go-backend-template/usage"
+	"go-backend-template/utils"
+	"go-backend-template/webhooks"
+)
+
+// App holds the wired components. Optional components are nil when disabled by the configuration.
+type App struct {
+	Config      *config.Config
+	Hooks       *hooks.Registry
+	Logger      utils.Logger
+	Localizer   *utils.Localizer
+	SecurityLog *security.EventLogger
+	Throttle    *security.LoginThrottle
+	Bots        *security.BotDetector
+	Secrets     *secrets.Manager
+	JWT         *utils.JWTUtils
+
+	MongoDB      *database.MongoDB
+	PostgresDB   *database.PostgresDB
+	Migrator     *migrate.Migrator
+	QueryStats   *database.QueryStats
+	TimeoutStats *middleware.TimeoutStats
+	Concurrency  *middleware.ConcurrencyLimiter
+
+	Idempotency  idempotency.Store
+	Sessions     *sessions.Manager
+	Billing      *billing.Service
+	Meter        *usage.Meter
+	Analytics    *analytics.Tracker
+	Posts        posts.Store
+	Audit        *audit.Recorder
+	ReadOnly     *middleware.ReadOnlyMode
+	Webhooks     *webhooks.Receiver
+	Suppressions *suppression.List
+	Translations *translations.Manager
+	Operations   *operations.Queue
+	Hub          *realtime.Hub
+	OAuth        *oauth.Provider
+
+	AuthService  services.AuthService
+	UserService  services.UserService
+	StatsService services.StatsService
+	Setup        *services.SetupService
+
+	Handlers       Handlers
+	Router         *gin.Engine
+	Server         *http.Server
+	RedirectServer *http.Server
+
+	routeOptions routes.Options
+	registrars   []routes.RouteRegistrar
+	hooks        []Hook
+	started      int
+	serveErr     chan error
+}
+
+// Option customizes how New wires the application
+type Option func(*App)
+
+// WithRouteOptions customizes the middleware of the API routes, such as disabling rate limiting, setting
+// timeouts per route group, or adding middleware
+func WithRouteOptions(opts routes.Options) Option {
+	return func(a *App) {
+		a.routeOptions = opts
+	}
+}
+
+// WithHooks runs the hooks of registry around registration, token issuance, and profile updates
+func WithHooks(registry *hooks.Registry) Option {
+	return func(a *App) {
+		a.Hooks = registry
+	}
+}
+
+// WithRoutes mounts the routes of additional feature modules on every API version, after the built-in ones
+func WithRoutes(registrars ...routes.RouteRegistrar) Option {
+	return func(a *App) {
+		a.registrars = append(a.registrars, registrars...)
+	}
+}
+
+// Handlers are the HTTP handlers whose routes routes.SetupRoutes mounts
+type Handlers struct {
+	Auth        *handlers.AuthHandler
+	User        *handlers.UserHandler
+	Post        *handlers.PostHandler
+	Health      *handlers.HealthHandler
+	Realtime    *handlers.RealtimeHandler
+	Billing     *handlers.BillingHandler
+	Usage       *handlers.UsageHandler
+	Migration   *handlers.MigrationHandler
+	Translation *handlers.TranslationHandler
+	OAuth       *handlers.OAuthHandler
+	Stats       *handlers.StatsHandler
+	Audit       *handlers.AuditHandler
+	Backup      *handlers.BackupHandler
+	ReadOnly    *handlers.ReadOnlyHandler
+	Webhook     *handlers.WebhookHandler
+	Operation   *handlers.OperationHandler
+	Setup       *handlers.SetupHandler
+	Bot         *handlers.BotHandler
+	Metrics     *handlers.MetricsHandler
+	Profiling   *handlers.ProfilingHandler
+}
+
+// Registrars returns the feature modules of the handlers, leaving out those of disabled features
+func (h Handlers) Registrars() []routes.RouteRegistrar {
+	registrars := []routes.RouteRegistrar{
+		routes.HealthRoutes(h.Health),
+		routes.AuthRoutes(h.Auth, h.Setup, h.Bot),
+		routes.UserRoutes(h.User, h.Usage),
+		routes.PostsRoutes(h.Post),
+		routes.AdminRoutes(h.Stats, h.Migration, h.Audit, h.Backup, h.ReadOnly),
+		routes.RealtimeRoutes(h.Realtime),
+	}
+	if h.Billing != nil {
+		registrars = append(registrars, routes.BillingRoutes(h.Billing))
+	}
+	if h.Translation != nil {
+		registrars = append(registrars, routes.TranslationRoutes(h.Translation))
+	}
+	if h.OAuth != nil {
+		registrars = append(registrars, routes.OAuthRoutes(h.OAuth))
+	}
+	if h.Webhook != nil {
+		registrars = append(registrars, routes.WebhookRoutes(h.Webhook))
+	}
+	if h.Operation != nil {
+		registrars = append(registrars, routes.OperationRoutes(h.Operation))
+	}
+	return registrars
+}
+
+// New wires the application for cfg. Secrets are resolved into cfg before it is validated. If wiring
+// fails, the resources acquired so far are released.
+func New(cfg *config.Config, options ...Option) (_ *App, err error) {
+	a := &App{Config: cfg, serveErr: make(chan error, 2)}
+	for _, option := range options {
+		option(a)
+	}
+	defer func() {
+		if err != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			a.Stop(ctx)
+		}
+	}()
+
+	for _, step := range a.steps() {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// steps returns the wiring steps of the run mode. The migrate mode only connects to the databases; the
+// worker and scheduler modes serve health checks instead of the API.
+func (a *App) steps() []func() error {
+	switch a.Config.Mode {
+	case config.ModeMigrate:
+		return []func() error{a.initLogger, a.initSecrets, a.initDatabases}
+	case config.ModeWorker, config.ModeScheduler:
+		return []func() error{
+			a.initLogger,
+			a.initSecrets,
+			a.initSecurity,
+			a.initDatabases,
+			a.initStores,
+			a.initJobs,
+			a.initServices,
+			a.initOperations,
+			a.initProbeRouter,
+			a.initServer,
+		}
+	}
+	return []func() error{
+		a.initLogger,
+		a.initSecrets,
+		a.initLocalizer,
+		a.initSecurity,
+		a.initDatabases,
+		a.initStores,
+		a.initJobs,
+		a.initServices,
+		a.initOperations,
+		a.bootstrap,
+		a.initHandlers,
+		a.initRouter,
+		a.initServer,
+	}
+}
+
+// initLogger creates the application logger
+func (a *App) initLogger() error {
+	cfg := a.Config
+	logger, err := utils.NewLogger(utils.LoggerOptions{
+		Level:      cfg.LogLevel,
+		Format:     cfg.Log.Format,
+		Output:     cfg.Log.Output,
+		FilePath:   cfg.Log.FilePath,
+		MaxSizeMB:  cfg.Log.MaxSizeMB,
+		MaxAgeDays: cfg.Log.MaxAgeDays,
+		MaxBackups: cfg.Log.MaxBackups,
+		Fields: map[string]string{
+			"service": cfg.ServiceName,
+			"env":     cfg.Environment,
+			"mode":    cfg.Mode,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	a.Logger = logger
+	return nil
+}
+
+// initSecrets resolves credentials from the secrets manager, if configured, and validates the
+// configuration before any dependency is touched
+func (a *App) initSecrets() error {
+	cfg := a.Config
+
+	// Every call to another service, starting with those of the secrets provider, shares these settings
+	httpclient.Configure(httpclient.Options{
+		Timeout:          cfg.HTTPClient.Timeout,
+		MaxRetries:       cfg.HTTPClient.MaxRetries,
+		RetryDelay:       cfg.HTTPClient.RetryDelay,
+		MaxRetryDelay:    cfg.HTTPClient.MaxRetryDelay,
+		BreakerThreshold: cfg.HTTPClient.BreakerThreshold,
+		BreakerCooldown:  cfg.HTTPClient.BreakerCooldown,
+	}, a.Logger)
+
+	secretsProvider, err := secrets.NewProviderFromConfig(&cfg.Secrets)
+	if err != nil {
+		return fmt.Errorf("failed to initialize secrets provider: %w", err)
+	}
+
+	if secretsProvider != nil {
+		a.Secrets = secrets.NewManager(secretsProvider, cfg.Secrets.RefreshInterval, a.Logger)
+		if err := a.Secrets.Resolve(context.Background(), cfg); err != nil {
+			return fmt.Errorf("failed to resolve secrets: %w", err)
+		}
+		a.Logger.Info("Secrets resolved", "provider", cfg.Secrets.Provider)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	a.Logger.Info("Effective configuration", "config", cfg.Redacted())
+	return nil
+}
+
+// initLocalizer loads the embedded translations and those in LOCALES_DIR, which are reloaded when they
+// change if configured
+func (a *App) initLocalizer() error {
+	cfg := a.Config
+	localizer, err := utils.NewLocalizer(utils.LocalizerOptions{
+		DefaultLanguage: cfg.DefaultLanguage,
+		Supported:       cfg.Languages,
+		Dir:             cfg.Locales.Dir,
+		ReloadInterval:  cfg.Locales.ReloadInterval,
+		Logger:          a.Logger,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize localizer: %w", err)
+	}
+	a.Localizer = localizer
+	a.background("translation reload", localizer.Start)
+	return nil
+}
+
+// initSecurity opens the security event log and creates the JWT signer. The signing secret is shared by
+// token issuance and verification and follows secret rotation.
+func (a *App) initSecurity() error {
+	cfg := a.Config
+	securitySink, err := security.NewSinkFromConfig(&cfg.SecurityLog)
+	if err != nil {
+		return fmt.Errorf("failed to initialize security event log: %w", err)
+	}
+	a.SecurityLog = security.NewEventLogger(securitySink, a.Logger)
+	a.Append(Hook{Name: "security event log", OnStop: func(context.Context) error {
+		return a.SecurityLog.Close()
+	}})
+
+	if cfg.LoginThrottle.Enabled {
+		a.Throttle = security.NewLoginThrottle(cfg.LoginThrottle)
+	}
+	if cfg.BotDetection.Enabled {
+		a.Bots = security.NewBotDetector(cfg.BotDetection)
+	}
+
+	a.JWT = utils.NewJWTUtils(cfg.JWTSecret)
+	if a.Secrets != nil {
+		if cfg.Secrets.JWTSecretRef != "" {
+			a.Secrets.Watch(cfg.Secrets.JWTSecretRef, a.JWT.SetSecret)
+		}
+		a.background("secrets refresh", a.Secrets.Start)
+	}
+	return nil
+}
+
+// initDatabases connects to the enabled databases with retry logic, applies or checks the PostgreSQL
+// migrations, and instruments queries
+func (a *App) initDatabases() error {
+	cfg, logger := a.Config, a.Logger
+	var err error
+
+	if cfg.MongoDB.Enabled {
+		a.MongoDB, err = database.ConnectWithRetry(context.Background(), cfg.DBConnect, "MongoDB", logger, func() (*database.MongoDB, error) {
+			return database.NewMongoDB(&cfg.MongoDB)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to connect to MongoDB after retries: %w", err)
+		}
+		a.Append(Hook{Name: "MongoDB", OnStop: func(context.Context) error {
+			return a.MongoDB.Disconnect()
+		}})
+		logger.Info("Connected to MongoDB")
+
+		// Create unique and search indexes; otherwise run `make mongo-indexes` or the migrate mode when
+		// deploying
+		if cfg.MongoDB.EnsureIndexes || cfg.Mode == config.ModeMigrate {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			err := a.MongoDB.EnsureIndexes(ctx)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("failed to create MongoDB indexes: %w", err)
+			}
+		}
+	}
+
+	// SQLite stands in for PostgreSQL in local development and tests; the SQL code paths are shared
+	if cfg.SQLite.Enabled {
+		a.PostgresDB, err = database.NewSQLiteDB(&cfg.SQLite, database.NewGormLogger(logger, cfg.LogLevel))
+		if err != nil {
+			return fmt.Errorf("failed to open SQLite database: %w", err)
+		}
+		sqliteDB := a.PostgresDB
+		a.Append(Hook{Name: "SQLite", OnStop: func(context.Context) error {
+			return sqliteDB.Close()
+		}})
+		logger.Info("Opened SQLite database", "path", cfg.SQLite.Path)
+	}
+
+	if cfg.PostgresDB.Enabled {
+		a.PostgresDB, err = database.ConnectWithRetry(context.Background(), cfg.DBConnect, "PostgreSQL", logger, func() (*database.PostgresDB, error) {
+			return database.NewPostgresDB(&cfg.PostgresDB, database.NewGormLogger(logger, cfg.LogLevel))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to connect to PostgreSQL after retries: %w", err)
+		}
+		postgresDB := a.PostgresDB
+		a.Append(Hook{Name: "PostgreSQL", OnStop: func(context.Context) error {
+			return postgresDB.Close()
+		}})
+		logger.Info("Connected to PostgreSQL")
+
+		if err := a.migrate(); err != nil {
+			return err
+		}
+	}
+
+	// Count the queries of each request and log slow ones; the per-route counts are served at /metrics
+	a.QueryStats = database.NewQueryStats()
+	if a.PostgresDB != nil {
+		if err := a.PostgresDB.InstrumentQueries(cfg.QueryLog.SlowThreshold, logger); err != nil {
+			return fmt.Errorf("failed to instrument SQL queries: %w", err)
+		}
+		// Tag statements with the request ID, so the database's logs can be matched with ours
+		if cfg.QueryLog.TagRequests {
+			if err := a.PostgresDB.TagQueries(); err != nil {
+				return fmt.Errorf("failed to tag SQL queries: %w", err)
+			}
+		}
+	}
+	if a.MongoDB != nil {
+		a.MongoDB.InstrumentQueries(cfg.QueryLog.SlowThreshold, logger)
+	}
+	return nil
+}
+
+// initJobs registers the background jobs of the run mode
+func (a *App) initJobs() error {
+	cfg, logger := a.Config, a.Logger
+
+	// Log database outages and recoveries; the drivers reconnect automatically
+	a.background("connection monitor", jobs.NewConnectionMonitor(a.MongoDB, a.PostgresDB, cfg.DBConnect.CheckInterval, logger).Start)
+
+	// Remove soft-deleted users, audit log entries, and analytics events once they outlive their retention
+	if cfg.RunsScheduler() {
+		a.background("retention", jobs.NewRetention(a.MongoDB, a.PostgresDB, a.Audit, cfg.Retention, logger).Start)
+	}
+	return nil
+}
+
+// migrate applies pending PostgreSQL migrations when configured or in the migrate mode, then refuses to
+// serve against a schema this binary was not built for or whose users.id is not POSTGRES_USER_ID_TYPE;
+// run `make db-migrate-up` when deploying
+func (a *App) migrate() error {
+	var err error
+	a.Migrator, err = migrate.New(a.PostgresDB, migrations.FS)
+	if err != nil {
+		return fmt.Errorf("failed to load PostgreSQL migrations: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if a.Config.PostgresDB.MigrateOnStart || a.Config.Mode == config.ModeMigrate {
+		applied, err := a.Migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to apply PostgreSQL migrations: %w", err)
+		}
+		if applied > 0 {
+			a.Logger.Info("Applied PostgreSQL migrations", "count", applied, "version", a.Migrator.Latest())
+		}
+	}
+
+	if err := a.Migrator.Check(ctx); err != nil {
+		return fmt.Errorf("PostgreSQL schema is not up to date: %w", err)
+	}
+	return a.PostgresDB.CheckUserIDType(ctx)
+}
+
+// initStores creates the stores of the optional features; each uses the primary database (PostgreSQL
+// when both are configured) unless configured for in-memory use
+func (a *App) initStores() error {
+	cfg := a.Config
+	var err error
+
+	// Idempotency-Key storage
+	a.Idempotency = idempotency.NewMemoryStore()
+	if cfg.Idempotency.Store == "database" {
+		if a.PostgresDB != nil {
+			a.Idempotency = idempotency.NewPostgresStore(a.PostgresDB)
+		} else if a.MongoDB != nil {
+			mongoStore, err := idempotency.NewMongoStore(context.Background(), a.MongoDB)
+			if err != nil {
+				return fmt.Errorf("failed to initialize idempotency store: %w", err)
+			}
+			a.Idempotency = mongoStore
+		}
+	}
+
+	// Sessions behind refresh tokens are stored in the primary database
+	if cfg.Sessions.Enabled {
+		var sessionStore sessions.Store
+		if a.PostgresDB != nil {
+			sessionStore = sessions.NewPostgresStore(a.PostgresDB)
+		} else {
+			mongoStore, err := sessions.NewMongoStore(context.Background(), a.MongoDB)
+			if err != nil {
+				return fmt.Errorf("failed to initialize session store: %w", err)
+			}
+			sessionStore = mongoStore
+		}
+		a.Sessions = sessions.NewManager(cfg.Sessions, sessionStore)
+	}
+
+	// Stripe billing: subscriptions are stored in the primary database
+	if cfg.Billing.Enabled {
+		var billingStore billing.Store
+		if a.PostgresDB != nil {
+			billingStore = billing.NewPostgresStore(a.PostgresDB)
+		} else {
+			mongoStore, err := billing.NewMongoStore(context.Background(), a.MongoDB)
+			if err != nil {
+				return fmt.Errorf("failed to initialize billing store: %w", err)
+			}
+			billingStore = mongoStore
+		}
+
+		a.Billing, err = billing.NewService(cfg.Billing, billingStore, a.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize billing: %w", err)
+		}
+		a.Logger.Info("Billing enabled", "plans", len(a.Billing.Catalog().Plans()))
+	}
+
+	// Usage metering and plan quotas
+	if cfg.Usage.Enabled {
+		var usageStore usage.Store = usage.NewMemoryStore()
+		if cfg.Usage.Store == "database" {
+			if a.PostgresDB != nil {
+				usageStore = usage.NewPostgresStore(a.PostgresDB)
+			} else if a.MongoDB != nil {
+				mongoStore, err := usage.NewMongoStore(context.Background(), a.MongoDB)
+				if err != nil {
+					return fmt.Errorf("failed to initialize usage store: %w", err)
+				}
+				usageStore = mongoStore
+			}
+		}
+
+		a.Meter, err = usage.NewMeter(usageStore, cfg.Usage.Quotas, a.Billing)
+		if err != nil {
+			return fmt.Errorf("failed to initialize usage metering: %w", err)
+		}
+	}
+
+	// Product analytics, sent in batches; a nil tracker records nothing
+	if cfg.Analytics.Sink != "none" {
+		var sink analytics.Sink
+		switch cfg.Analytics.Sink {
+		case "database":
+			if a.PostgresDB != nil {
+				sink = analytics.NewPostgresSink(a.PostgresDB)
+			} else if a.MongoDB != nil {
+				mongoSink, err := analytics.NewMongoSink(context.Background(), a.MongoDB)
+				if err != nil {
+					return fmt.Errorf("failed to initialize analytics sink: %w", err)
+				}
+				sink = mongoSink
+			} else {
+				return fmt.Errorf("ANALYTICS_SINK=database requires a database")
+			}
+		case "segment":
+			sink = analytics.NewSegmentSink(cfg.Analytics.SegmentEndpoint, cfg.Analytics.SegmentWriteKey, 10*time.Second)
+		case "posthog":
+			sink = analytics.NewPostHogSink(cfg.Analytics.PostHogHost, cfg.Analytics.PostHogAPIKey, 10*time.Second)
+		}
+
+		a.Analytics = analytics.NewTracker(sink, analytics.NewUserOptOuts(a.MongoDB, a.PostgresDB), analytics.Options{
+			BatchSize:     cfg.Analytics.BatchSize,
+			FlushInterval: cfg.Analytics.FlushInterval,
+			QueueSize:     cfg.Analytics.QueueSize,
+		}, a.Logger)
+		a.Append(Hook{
+			Name: "analytics",
+			OnStart: func(context.Context) error {
+				a.Analytics.Start()
+				return nil
+			},
+			OnStop: a.Analytics.Close,
+		})
+	}
+
+	// Audit log of the admin-only endpoints, in the primary database
+	if cfg.AuditLog.Enabled {
+		var auditStore audit.Store = audit.NewMemoryStore()
+		if a.PostgresDB != nil {
+			auditStore = audit.NewPostgresStore(a.PostgresDB)
+		} else if a.MongoDB != nil {
+			mongoStore, err := audit.NewMongoStore(context.Background(), a.MongoDB)
+			if err != nil {
+				return fmt.Errorf("failed to initialize audit log store: %w", err)
+			}
+			auditStore = mongoStore
+		}
+
+		opts := audit.Options{HashChain: cfg.AuditLog.HashChain}
+		if cfg.AuditLog.Forward {
+			opts.Forward = a.SecurityLog
+		}
+		a.Audit = audit.NewRecorder(auditStore, opts)
+	}
+
+	// Addresses that bounced or complained, marked on the users by the webhooks of the email provider
+	a.Suppressions = suppression.NewList(a.MongoDB, a.PostgresDB, a.Logger)
+
+	// Webhook receiver: deliveries are stored in the primary database to process each once
+	if cfg.Webhooks.Enabled {
+		var webhookStore webhooks.Store = webhooks.NewMemoryStore()
+		if a.PostgresDB != nil {
+			webhookStore = webhooks.NewPostgresStore(a.PostgresDB)
+		} else if a.MongoDB != nil {
+			mongoStore, err := webhooks.NewMongoStore(context.Background(), a.MongoDB)
+			if err != nil {
+				return fmt.Errorf("failed to initialize webhook store: %w", err)
+			}
+			webhookStore = mongoStore
+		}
+
+		a.Webhooks = webhooks.NewReceiver(webhookStore, cfg.Webhooks.MaxAttempts, a.Logger)
+		if a.Billing != nil {
+			a.Webhooks.Register("stripe", webhooks.NewStripeProvider(cfg.Billing.StripeWebhookSecret))
+			a.Webhooks.Handle("stripe", func(ctx context.Context, delivery webhooks.Delivery) error {
+				return a.Billing.HandleEvent(ctx, delivery.Payload)
+			})
+		}
+		if cfg.Webhooks.SendGridPublicKey != "" {
+			provider, err := webhooks.NewSendGridProvider(cfg.Webhooks.SendGridPublicKey)
+			if err != nil {
+				return err
+			}
+			a.Webhooks.Register("sendgrid", provider)
+			a.Webhooks.Handle("sendgrid", a.Suppressions.HandleSendGrid)
+		}
+		for _, entry := range cfg.Webhooks.Secrets {
+			name, secret, _ := strings.Cut(entry, "=")
+			provider, err := webhooks.NewStandardProvider(secret)
+			if err != nil {
+				return fmt.Errorf("WEBHOOK_SECRETS: %s: %w", name, err)
+			}
+			a.Webhooks.Register(name, provider)
+		}
+		a.Logger.Info("Webhooks enabled", "providers", a.Webhooks.Providers())
+	}
+
+	// Posts live in the primary database, next to the users who own them
+	a.Posts = posts.NewMemoryStore()
+	if a.PostgresDB != nil {
+		a.Posts = posts.NewPostgresStore(a.PostgresDB)
+	} else if a.MongoDB != nil {
+		a.Posts = posts.NewMongoStore(a.MongoDB)
+	}
+
+	// Translation overrides edited by admins, applied on top of the translation files; only the API
+	// modes localize responses
+	if a.Localizer != nil {
+		var translationStore translations.Store = translations.NewMemoryStore()
+		if a.PostgresDB != nil {
+			translationStore = translations.NewPostgresStore(a.PostgresDB)
+		} else if a.MongoDB != nil {
+			mongoStore, err := translations.NewMongoStore(context.Background(), a.MongoDB)
+			if err != nil {
+				return fmt.Errorf("failed to initialize translation store: %w", err)
+			}
+			translationStore = mongoStore
+		}
+
+		a.Translations = translations.NewManager(translationStore, a.Localizer, cfg.Locales.SyncInterval, a.Logger)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := a.Translations.Load(ctx)
+		cancel()
+		if err != nil {
+			return err
+		}
+		a.background("translation sync", a.Translations.Start)
+	}
+	return nil
+}
+
+// initServices creates the realtime hub and the services behind the auth, user, and statistics endpoints
+func (a *App) initServices() error {
+	// Realtime hub pushes events to connected WebSocket and SSE clients
+	a.Hub = realtime.NewHub(a.Config.Realtime.BufferSize, a.Config.Realtime.HistorySize, a.Logger)
+
+	// Registrations are checked against Have I Been Pwned and for mail servers when enabled
+	var checks services.RegistrationChecks
+	if a.Config.PasswordBreach.Enabled {
+		checks.Passwords = pwned.NewChecker(a.Config.PasswordBreach, a.Logger)
+	}
+	if a.Config.Email.CheckMX {
+		checks.Emails = utils.NewMXChecker(a.Config.Email.MXTimeout)
+	}
+	a.AuthService = services.NewAuthService(a.MongoDB, a.PostgresDB, a.JWT, a.Hooks, checks, a.Sessions)
+	a.UserService = services.NewUserService(a.MongoDB, a.PostgresDB, a.Hub, a.Hooks)
+	a.StatsService = services.NewStatsService(a.MongoDB, a.PostgresDB, a.Config.AdminStats.CacheTTL)
+
+	// The services use PostgreSQL when both databases are enabled; dual-write mode copies the users they
+	// change to MongoDB as well
+	if a.Config.DualWrite {
+		mirror := transfer.NewMirror(transfer.NewPostgresStore(a.PostgresDB), transfer.NewMongoStore(a.MongoDB), a.Logger)
+		a.AuthService = mirror.AuthService(a.AuthService)
+		a.UserService = mirror.UserService(a.UserService)
+		a.Logger.Info("Dual-write mode: user changes are mirrored to MongoDB")
+	}
+
+	// OpenID Connect provider: clients, codes, and consents are stored in the primary database
+	if a.Config.OAuth.Enabled {
+		var oauthStore oauth.Store
+		if a.PostgresDB != nil {
+			oauthStore = oauth.NewPostgresStore(a.PostgresDB)
+		} else {
+			mongoStore, err := oauth.NewMongoStore(context.Background(), a.MongoDB)
+			if err != nil {
+				return fmt.Errorf("failed to initialize OAuth store: %w", err)
+			}
+			oauthStore = mongoStore
+		}
+
+		var err error
+		a.OAuth, err = oauth.NewProvider(a.Config.OAuth, oauthStore, a.UserService, a.JWT, a.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize OAuth provider: %w", err)
+		}
+		a.Logger.Info("OpenID Connect provider enabled", "issuer", a.Config.OAuth.Issuer)
+	}
+	return nil
+}
+
+// initOperations creates the queue of the requests run in the background, in the primary database, and
+// starts its workers in the modes that run them. The API modes enqueue operations for the workers of any
+// process to run.
+func (a *App) initOperations() error {
+	cfg := a.Config
+
+	var store operations.Store = operations.NewMemoryStore()
+	if a.PostgresDB != nil {
+		store = operations.NewPostgresStore(a.PostgresDB)
+	} else if a.MongoDB != nil {
+		mongoStore, err := operations.NewMongoStore(context.Background(), a.MongoDB)
+		if err != nil {
+			return fmt.Errorf("failed to initialize operation store: %w", err)
+		}
+		store = mongoStore
+	}
+
+	a.Operations = operations.NewQueue(store, operations.Options{
+		Workers:      cfg.Operations.Workers,
+		PollInterval: cfg.Operations.PollInterval,
+		StaleAfter:   cfg.Operations.StaleAfter,
+		MaxAttempts:  cfg.Operations.MaxAttempts,
+	}, a.Logger)
+	a.Operations.Register(jobs.OperationBulkUsers, jobs.NewBulkUsers(a.UserService, a.SecurityLog, a.Logger).Task())
+	// Backups are registered whenever they can run, so a worker process runs those the API enqueues
+	if store := a.transferStore(); store != nil && cfg.Backup.EncryptionKey != "" {
+		a.Operations.Register(jobs.OperationBackup, jobs.BackupTask(store, cfg.Backup.EncryptionKey))
+		a.Operations.Register(jobs.OperationRestore, jobs.RestoreTask(store, cfg.Backup.EncryptionKey, a.Logger))
+	}
+
+	if cfg.RunsWorkers() {
+		var cancel context.CancelFunc
+		a.Append(Hook{
+			Name: "operations",
+			OnStart: func(context.Context) error {
+				var ctx context.Context
+				ctx, cancel = context.WithCancel(context.Background())
+				a.Operations.Start(ctx)
+				return nil
+			},
+			// Stopping waits for the running operations to be put back in the queue
+			OnStop: func(ctx context.Context) error {
+				cancel()
+				done := make(chan struct{})
+				go func() {
+					a.Operations.Wait()
+					close(done)
+				}()
+				select {
+				case <-done:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			},
+		})
+	}
+	return nil
+}
+
+// transferStore returns the users of the primary database, which the user service also prefers, for
+// backups and restores; nil without a database
+func (a *App) transferStore() transfer.Store {
+	if a.PostgresDB != nil {
+		return transfer.NewPostgresStore(a.PostgresDB)
+	} else if a.MongoDB != nil {
+		return transfer.NewMongoStore(a.MongoDB)
+	}
+	return nil
+}
+
+// bootstrap creates the first superadmin of an empty database from the configuration, or generates the
+// setup token that lets POST /setup create it
+func (a *App) bootstrap() error {
+	cfg := a.Config.Bootstrap
+	a.Setup = services.NewSetupService(services.NewAdminService(a.MongoDB, a.PostgresDB), a.Logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	admin := models.RegisterRequest{
+		Email:     cfg.AdminEmail,
+		Username:  cfg.AdminUsername,
+		Password:  cfg.AdminPassword,
+		FirstName: "Admin",
+		LastName:  "User",
+	}
+	return a.Setup.Bootstrap(ctx, admin, cfg.SetupToken)
+}
+
+// initHandlers creates the HTTP handlers; those of disabled features stay nil
+func (a *App) initHandlers() error {
+	cfg, logger, localizer := a.Config, a.Logger, a.Localizer
+
+	metadata, err := utils.ParseMetadataSchema(cfg.UserMetadata.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to parse USER_METADATA_SCHEMA: %w", err)
+	}
+
+	a.Handlers = Handlers{
+		Auth:     handlers.NewAuthHandler(cfg.Auth, a.AuthService, a.Sessions, logger, localizer, a.SecurityLog, a.Throttle, a.Analytics),
+		User:     handlers.NewUserHandler(a.UserService, metadata, logger, localizer, a.SecurityLog, a.Operations),
+		Post:     handlers.NewPostHandler(a.Posts, logger, localizer),
+		Health:   handlers.NewHealthHandler(cfg.Health, a.MongoDB, a.PostgresDB, logger),
+		Realtime: handlers.NewRealtimeHandler(cfg.Realtime, a.Hub, logger, localizer),
+		Stats:    handlers.NewStatsHandler(a.StatsService, a.SecurityLog, logger, localizer),
+	}
+	if a.Setup.Pending() {
+		a.Handlers.Setup = handlers.NewSetupHandler(a.Setup, a.SecurityLog, logger, localizer)
+	}
+	if a.Audit != nil {
+		a.Handlers.Audit = handlers.NewAuditHandler(a.Audit, logger, localizer)
+	}
+	// Read-only mode for maintenance windows, toggled by superadmins
+	a.ReadOnly = middleware.NewReadOnlyMode(cfg.ReadOnly.Enabled, cfg.ReadOnly.Message)
+	a.Handlers.ReadOnly = handlers.NewReadOnlyHandler(a.ReadOnly, logger, localizer)
+	a.Handlers.Operation = handlers.NewOperationHandler(a.Operations, logger, localizer)
+	// Backups of the users of the primary database, which the user service also prefers
+	if cfg.Backup.Endpoints {
+		if store := a.transferStore(); store != nil {
+			a.Handlers.Backup = handlers.NewBackupHandler(store, cfg.Backup.EncryptionKey, a.Operations, logger, localizer)
+		}
+	}
+	if a.Bots != nil {
+		a.Handlers.Bot = handlers.NewBotHandler(a.Bots, a.SecurityLog, logger, localizer)
+	}
+	if a.Billing != nil {
+		a.Handlers.Billing = handlers.NewBillingHandler(a.Billing, logger, localizer)
+	}
+	if a.Webhooks != nil {
+		a.Handlers.Webhook = handlers.NewWebhookHandler(a.Webhooks, logger, localizer)
+	}
+	if a.Meter != nil {
+		a.Handlers.Usage = handlers.NewUsageHandler(a.Meter, logger, localizer)
+	}
+	if a.Migrator != nil {
+		a.Handlers.Migration = handlers.NewMigrationHandler(a.Migrator, logger, localizer)
+	}
+	if a.Translations != nil {
+		a.Handlers.Translation = handlers.NewTranslationHandler(a.Translations, logger, localizer)
+	}
+	if a.OAuth != nil {
+		a.Handlers.OAuth = handlers.NewOAuthHandler(a.OAuth, logger, localizer)
+	}
+	// Count the requests that run out of time per route; the counts are served at /metrics
+	a.TimeoutStats = middleware.NewTimeoutStats()
+	// MAX_IN_FLIGHT_REQUESTS, RESERVED_IN_FLIGHT_REQUESTS, and GROUP_MAX_IN_FLIGHT_REQUESTS apply where the route options set no limiter
+	a.Concurrency = a.routeOptions.Concurrency
+	if a.Concurrency == nil && (cfg.Concurrency.MaxInFlight > 0 || len(cfg.Concurrency.Groups) > 0) {
+		groups, err := routes.ParseGroupLimits(cfg.Concurrency.Groups)
+		if err != nil {
+			return err
+		}
+		a.Concurrency = middleware.NewConcurrencyLimiter(cfg.Concurrency.MaxInFlight, cfg.Concurrency.Reserved, groups, cfg.Concurrency.RetryAfter)
+	}
+	if cfg.Metrics.Enabled {
+		a.Handlers.Metrics = handlers.NewMetricsHandler(cfg.Metrics.Token, a.MongoDB, a.PostgresDB, a.QueryStats, a.TimeoutStats, a.Concurrency, a.Throttle, a.Bots)
+	}
+	if cfg.Profiling.Enabled {
+		a.Handlers.Profiling = handlers.NewProfilingHandler(cfg.Profiling.Token)
+	}
+	return nil
+}
+
+// initRouter creates the Gin engine with the global middleware and the routes
+func (a *App) initRouter() error {
+	cfg, logger := a.Config, a.Logger
+
+	gin.SetMode(cfg.Router.Mode)
+
+	utils.SetupValidator()
+	utils.BlockEmailDomains(cfg.Email.BlockedDomains...)
+	if cfg.Email.BlocklistFile != "" {
+		domains, err := utils.LoadEmailBlocklist(cfg.Email.BlocklistFile)
+		if err != nil {
+			return fmt.Errorf("failed to load EMAIL_BLOCKLIST_FILE: %w", err)
+		}
+		utils.BlockEmailDomains(domains...)
+		logger.Info("Loaded the disposable email blocklist", "domains", len(domains))
+	}
+	router := gin.New()
+
+	// Only trust forwarding headers from configured proxies so clients cannot spoof their IP
+	if err := router.SetTrustedProxies(cfg.Proxy.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.TrustedPlatform = cfg.Proxy.TrustedPlatform
+	router.RemoteIPHeaders = cfg.Proxy.RemoteIPHeaders
+	router.MaxMultipartMemory = cfg.Limits.MaxMultipartMemory
+	// Match /api//v1/users like /api/v1/users, and answer a known path with the wrong method 405 with the
+	// allowed methods in Allow instead of 404
+	router.RemoveExtraSlash = cfg.Router.RemoveExtraSlash
+	router.HandleMethodNotAllowed = cfg.Router.MethodNotAllowed
+
+	// Add middleware
+	router.Use(middleware.RealIP())
+	router.Use(middleware.Logger(logger))
+	router.Use(middleware.Recovery(logger, cfg.Environment == "development"))
+	router.Use(middleware.CORS())
+	router.Use(middleware.Localization(a.Localizer))
+	router.Use(middleware.RequestID(logger))
+	router.Use(middleware.QueryStats(a.QueryStats, cfg.QueryLog.WarnPerRequest, logger))
+	if cfg.Profiling.Enabled {
+		router.Use(middleware.ProfilerLabels())
+	}
+
+	h := a.Handlers
+	registrars := append(h.Registrars(), a.registrars...)
+	var serviceTokens middleware.TokenVerifier
+	if a.OAuth != nil {
+		serviceTokens = a.OAuth.Verify
+	}
+	// Every admin-only request is audited, including those the custom admin middleware rejects
+	opts := a.routeOptions
+	// REQUEST_TIMEOUT and ROUTE_TIMEOUTS apply where the route options set no deadline
+	if opts.Timeout == 0 {
+		opts.Timeout = cfg.Timeouts.Request
+		if opts.Timeout == 0 {
+			opts.Timeout = -1
+		}
+	}
+	groupTimeouts, err := routes.ParseGroupTimeouts(cfg.Timeouts.Routes)
+	if err != nil {
+		return err
+	}
+	for prefix, timeout := range opts.GroupTimeouts {
+		groupTimeouts[prefix] = timeout
+	}
+	opts.GroupTimeouts, opts.TimeoutStats, opts.Concurrency = groupTimeouts, a.TimeoutStats, a.Concurrency
+	if opts.PriorityRoutes == nil && len(cfg.Concurrency.PriorityRoutes) > 0 {
+		opts.PriorityRoutes = cfg.Concurrency.PriorityRoutes
+	}
+	if opts.PriorityRoles == nil && len(cfg.Concurrency.PriorityRoles) > 0 {
+		opts.PriorityRoles = cfg.Concurrency.PriorityRoles
+	}
+	if a.Audit != nil {
+		opts.AdminMiddleware = append([]gin.HandlerFunc{middleware.Audit(a.Audit, logger)}, opts.AdminMiddleware...)
+	}
+	opts.ReadOnly = a.ReadOnly
+	err = routes.SetupRoutes(router, cfg, opts, a.JWT, serviceTokens, a.Idempotency, a.Meter, a.Analytics, registrars, h.Metrics, h.Profiling, logger)
+	if err != nil {
+		return err
+	}
+	router.NoRoute(middleware.NotFound())
+	router.NoMethod(middleware.MethodNotAllowed())
+	a.Router = router
+	return nil
+}
+
+// initProbeRouter creates the router of the worker and scheduler modes, which only serves the health
+// check for liveness and readiness probes
+func (a *App) initProbeRouter() error {
+	cfg, logger := a.Config, a.Logger
+
+	gin.SetMode(cfg.Router.Mode)
+
+	a.Handlers.Health = handlers.NewHealthHandler(cfg.Health, a.MongoDB, a.PostgresDB, logger)
+	router := gin.New()
+	router.Use(middleware.Recovery(logger, cfg.Environment == "development"))
+	router.GET("/api/v1/health", middleware.DisableNegotiation(), a.Handlers.Health.HealthCheck)
+	a.Router = router
+	return nil
+}
+
+// initServer creates the HTTP server (and the HTTP->HTTPS redirect server when TLS is enabled) and the
+// hooks that start and gracefully stop them
+func (a *App) initServer() error {
+	cfg, logger := a.Config, a.Logger
+	a.Server, a.RedirectServer = newServers(cfg, a.Router)
+
+	a.Append(Hook{
+		Name: "HTTP server",
+		OnStart: func(context.Context) error {
+			if a.RedirectServer != nil {
+				startRedirectServer(a.RedirectServer, logger)
+			}
+			go func() {
+				logger.Info("Server starting", "port", cfg.Port, "tls", cfg.TLS.Enabled, "http2", cfg.TLS.HTTP2)
+				if err := serve(a.Server, cfg); err != nil && err != http.ErrServerClosed {
+					a.serveErr <- err
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if a.RedirectServer != nil {
+				if err := a.RedirectServer.Shutdown(ctx); err != nil {
+					logger.Error("HTTP redirect server forced to shutdown", "error", err)
+				}
+			}
+			return a.Server.Shutdown(ctx)
+		},
+	})
+
+	// Hijacked WebSocket connections are not tracked by http.Server, so they are drained before it stops
+	a.Append(Hook{Name: "realtime hub", OnStop: a.Hub.Shutdown})
+	return nil
+}
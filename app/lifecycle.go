@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-backend-template/config"
+)
+
+// Hook is a pair of lifecycle callbacks. OnStart runs when the application starts, in the order the hooks
+// were appended; OnStop runs in reverse order when it stops. A hook without OnStart releases a resource
+// acquired while wiring and also runs when wiring fails.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Append adds a hook; components that must stop before earlier ones are appended after them
+func (a *App) Append(hook Hook) {
+	a.hooks = append(a.hooks, hook)
+}
+
+// background appends a hook running a background job until the application stops; the job's context is
+// independent of the start context, which only bounds starting
+func (a *App) background(name string, start func(ctx context.Context)) {
+	var cancel context.CancelFunc
+	a.Append(Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			start(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// Start runs the OnStart hooks in order. If one fails, the hooks started so far are stopped.
+func (a *App) Start(ctx context.Context) error {
+	for i, hook := range a.hooks {
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				return errors.Join(fmt.Errorf("failed to start %s: %w", hook.Name, err), a.Stop(ctx))
+			}
+		}
+		a.started = i + 1
+	}
+	return nil
+}
+
+// Stop runs the OnStop hooks in reverse order, skipping hooks that were never started, and returns the
+// errors of all of them
+func (a *App) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(a.hooks) - 1; i >= 0; i-- {
+		hook := a.hooks[i]
+		if hook.OnStop == nil || (hook.OnStart != nil && i >= a.started) {
+			continue
+		}
+		if err := hook.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", hook.Name, err))
+		}
+	}
+	a.hooks = a.hooks[:0]
+	a.started = 0
+	return errors.Join(errs...)
+}
+
+// Run starts the application and serves until SIGINT or SIGTERM, or until a server fails, then stops it
+// gracefully within 30 seconds. In the migrate mode, whose work is done by New, it releases the database
+// connections and returns.
+func (a *App) Run() error {
+	if a.Config.Mode == config.ModeMigrate {
+		a.Logger.Info("Migrations complete")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return a.Stop(ctx)
+	}
+
+	if err := a.Start(context.Background()); err != nil {
+		return err
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	var runErr error
+	select {
+	case <-quit:
+	case runErr = <-a.serveErr:
+		a.Logger.Error("Server failed", "error", runErr)
+	}
+	a.Logger.Info("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := a.Stop(ctx); err != nil {
+		runErr = errors.Join(runErr, err)
+	}
+
+	a.Logger.Info("Server exited")
+	return runErr
+}
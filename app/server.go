@@ -0,0 +1,100 @@
+package app
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"go-backend-template/config"
+	"go-backend-template/utils"
+)
+
+// newServers builds the main API server and, when TLS is enabled, an optional plain-HTTP
+// server that redirects to HTTPS and answers ACME HTTP-01 challenges
+func newServers(cfg *config.Config, handler http.Handler) (*http.Server, *http.Server) {
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%s", cfg.Port),
+		Handler: handler,
+	}
+
+	// HTTP/2 is negotiated over TLS via ALPN; without TLS it is served as cleartext h2c
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	if cfg.TLS.HTTP2 {
+		if cfg.TLS.Enabled {
+			protocols.SetHTTP2(true)
+		} else {
+			protocols.SetUnencryptedHTTP2(true)
+		}
+	}
+	server.Protocols = protocols
+
+	if !cfg.TLS.Enabled {
+		return server, nil
+	}
+
+	var redirectHandler http.Handler = http.HandlerFunc(redirectToHTTPS(cfg.Port))
+
+	if cfg.TLS.Autocert {
+		certManager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLS.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.TLS.AutocertCache),
+			Email:      cfg.TLS.AutocertEmail,
+		}
+		server.TLSConfig = certManager.TLSConfig()
+		redirectHandler = certManager.HTTPHandler(redirectHandler)
+	} else {
+		server.TLSConfig = &tls.Config{}
+	}
+	server.TLSConfig.MinVersion = tls.VersionTLS12
+
+	// Autocert needs the HTTP listener for challenges even when redirects are disabled
+	if !cfg.TLS.RedirectHTTP && !cfg.TLS.Autocert {
+		return server, nil
+	}
+
+	redirectServer := &http.Server{
+		Addr:    fmt.Sprintf(":%s", cfg.TLS.HTTPPort),
+		Handler: redirectHandler,
+	}
+	return server, redirectServer
+}
+
+// serve starts server with TLS when enabled, using certificate files unless autocert supplies them
+func serve(server *http.Server, cfg *config.Config) error {
+	if !cfg.TLS.Enabled {
+		return server.ListenAndServe()
+	}
+	if cfg.TLS.Autocert {
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+}
+
+// startRedirectServer runs the plain-HTTP redirect/ACME server in the background
+func startRedirectServer(server *http.Server, logger utils.Logger) {
+	go func() {
+		logger.Info("HTTP redirect server starting", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("HTTP redirect server failed", "error", err)
+		}
+	}()
+}
+
+// redirectToHTTPS returns a handler that permanently redirects requests to the HTTPS port
+func redirectToHTTPS(httpsPort string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	}
+}
@@ -0,0 +1,203 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend-template/models"
+	"go-backend-template/security"
+)
+
+// maxChainAttempts bounds the retries of an append that lost the race for the next sequence number to
+// another instance
+const maxChainAttempts = 5
+
+// Options configure a Recorder
+type Options struct {
+	// HashChain links every entry to the one before it
+	HashChain bool
+	// Forward also writes every entry as a security event, for a copy outside the database
+	Forward *security.EventLogger
+}
+
+// Recorder writes the audit log and checks it for tampering
+type Recorder struct {
+	store Store
+	opts  Options
+}
+
+// NewRecorder creates a recorder writing to store
+func NewRecorder(store Store, opts Options) *Recorder {
+	return &Recorder{store: store, opts: opts}
+}
+
+// Record completes entry with its ID, time, and hash, chaining it when enabled, and appends it
+func (r *Recorder) Record(ctx context.Context, entry *models.AuditLog) error {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	entry.ID = id.String()
+	// Truncated to what every backend stores, so the hash can be recomputed from the stored entry
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+
+	if !r.opts.HashChain {
+		entry.Hash = Hash(entry)
+		err = r.store.Append(ctx, entry)
+	} else {
+		err = r.appendChained(ctx, entry)
+	}
+	if err != nil {
+		return err
+	}
+
+	if r.opts.Forward != nil {
+		r.opts.Forward.Log(ctx, security.Event{
+			Type:      security.EventAdminAction,
+			Outcome:   outcome(entry.Status),
+			Timestamp: entry.CreatedAt,
+			ActorID:   entry.ActorID,
+			ClientIP:  entry.ClientIP,
+			RequestID: entry.RequestID,
+			Details: map[string]string{
+				"audit_id":     entry.ID,
+				"method":       entry.Method,
+				"route":        entry.Route,
+				"path":         entry.Path,
+				"status":       strconv.Itoa(entry.Status),
+				"payload_hash": entry.PayloadHash,
+				"hash":         entry.Hash,
+			},
+		})
+	}
+	return nil
+}
+
+// appendChained links entry to the last entry of the chain, retrying with the new last entry when
+// another instance appended first
+func (r *Recorder) appendChained(ctx context.Context, entry *models.AuditLog) error {
+	for range maxChainAttempts {
+		seq, prevHash := int64(1), ""
+		last, err := r.store.Last(ctx)
+		if err == nil {
+			seq, prevHash = *last.Seq+1, last.Hash
+		} else if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		entry.Seq, entry.PrevHash = &seq, prevHash
+		entry.Hash = Hash(entry)
+		if err := r.store.Append(ctx, entry); !errors.Is(err, ErrSeqTaken) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to chain the audit log entry after %d attempts", maxChainAttempts)
+}
+
+// List returns a page of the matching entries, newest first, and the number of matches
+func (r *Recorder) List(ctx context.Context, opts ListOptions) ([]models.AuditLog, int64, error) {
+	return r.store.List(ctx, opts)
+}
+
+// Each calls fn with every matching entry, newest first, reading the entries as fn consumes them
+func (r *Recorder) Each(ctx context.Context, opts ListOptions, fn func(*models.AuditLog) error) error {
+	return r.store.Each(ctx, opts, fn)
+}
+
+// Prune removes the entries created before cutoff, or only counts them when dryRun; the chain stays
+// verifiable
+func (r *Recorder) Prune(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	return r.store.Prune(ctx, cutoff, dryRun)
+}
+
+// Verify recomputes the hash of every entry and follows the chain, stopping at the first entry that
+// fails. The chain may start past 1 once old entries are removed, but must have no gaps after that.
+func (r *Recorder) Verify(ctx context.Context) (models.AuditVerification, error) {
+	result := models.AuditVerification{Valid: true}
+	var prev *models.AuditLog
+	errBroken := errors.New("broken")
+
+	err := r.store.Walk(ctx, func(entry *models.AuditLog) error {
+		result.Checked++
+		reason := ""
+		switch {
+		case entry.Hash != Hash(entry):
+			reason = "the entry does not match its hash"
+		case entry.Seq == nil && entry.PrevHash != "":
+			reason = "the entry links to the chain without a sequence number"
+		case entry.Seq == nil:
+		case prev == nil && *entry.Seq == 1 && entry.PrevHash != "":
+			reason = "the first entry of the chain links to another entry"
+		case prev != nil && *entry.Seq != *prev.Seq+1:
+			reason = fmt.Sprintf("entries %d to %d of the chain are missing", *prev.Seq+1, *entry.Seq-1)
+		case prev != nil && entry.PrevHash != prev.Hash:
+			reason = "the entry does not link to the entry before it"
+		}
+		if reason != "" {
+			result.Valid, result.BrokenAt, result.Reason = false, entry.ID, reason
+			return errBroken
+		}
+		if entry.Seq != nil {
+			prev = entry
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBroken) {
+		return models.AuditVerification{}, err
+	}
+	return result, nil
+}
+
+// hashedFields are the fields of an entry its hash covers, in a fixed order
+type hashedFields struct {
+	ID          string `json:"id"`
+	Seq         *int64 `json:"seq"`
+	ActorID     string `json:"actor_id"`
+	ActorRole   string `json:"actor_role"`
+	Method      string `json:"method"`
+	Route       string `json:"route"`
+	Path        string `json:"path"`
+	Status      int    `json:"status"`
+	ClientIP    string `json:"client_ip"`
+	RequestID   string `json:"request_id"`
+	PayloadHash string `json:"payload_hash"`
+	PrevHash    string `json:"prev_hash"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// Hash returns the hex SHA-256 of the fields of entry other than Hash
+func Hash(entry *models.AuditLog) string {
+	data, _ := json.Marshal(hashedFields{
+		ID:          entry.ID,
+		Seq:         entry.Seq,
+		ActorID:     entry.ActorID,
+		ActorRole:   entry.ActorRole,
+		Method:      entry.Method,
+		Route:       entry.Route,
+		Path:        entry.Path,
+		Status:      entry.Status,
+		ClientIP:    entry.ClientIP,
+		RequestID:   entry.RequestID,
+		PayloadHash: entry.PayloadHash,
+		PrevHash:    entry.PrevHash,
+		CreatedAt:   entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// outcome is the security event outcome of a response status
+func outcome(status int) string {
+	if status >= 400 {
+		return security.OutcomeFailure
+	}
+	return security.OutcomeSuccess
+}
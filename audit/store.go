@@ -0,0 +1,527 @@
+// Package audit records the calls to admin-only endpoints in an append-only log, optionally hash-chained
+// so that removed or altered entries can be detected
+package audit
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// ErrNotFound is returned by Last when the log has no chained entry
+var ErrNotFound = errors.New("audit log entry not found")
+
+// ErrSeqTaken is returned by Append when another entry already has the sequence number of the entry
+var ErrSeqTaken = errors.New("audit log sequence number already taken")
+
+// ListOptions selects a page of entries
+type ListOptions struct {
+	Page     int
+	PageSize int
+	ActorID  string
+	Method   string
+	Route    string
+	From     *time.Time
+	To       *time.Time
+}
+
+// Store persists audit log entries. It has no way to change them, and removes them only for retention.
+type Store interface {
+	// Append inserts an entry, or returns ErrSeqTaken when its sequence number is taken
+	Append(ctx context.Context, entry *models.AuditLog) error
+	// Last returns the chained entry with the highest sequence number, or ErrNotFound
+	Last(ctx context.Context) (*models.AuditLog, error)
+	// List returns a page of the matching entries, newest first, and the number of matches
+	List(ctx context.Context, opts ListOptions) ([]models.AuditLog, int64, error)
+	// Each calls fn with every matching entry, newest first, reading the entries as fn consumes them; Page
+	// and PageSize are ignored
+	Each(ctx context.Context, opts ListOptions, fn func(*models.AuditLog) error) error
+	// Walk calls fn with every entry: those outside the chain by time, then the chain in order
+	Walk(ctx context.Context, fn func(*models.AuditLog) error) error
+	// Prune removes the entries created before cutoff, or only counts them when dryRun. The chain is cut
+	// before its first entry created at or after cutoff, and its last entry is always kept, so what
+	// remains still verifies and the next entry continues the numbering.
+	Prune(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error)
+}
+
+// MemoryStore is an in-process Store, suitable for development and tests; the log is lost on restart
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries []models.AuditLog
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append adds the entry unless its sequence number is taken
+func (s *MemoryStore) Append(ctx context.Context, entry *models.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry.Seq != nil {
+		for _, existing := range s.entries {
+			if existing.Seq != nil && *existing.Seq == *entry.Seq {
+				return ErrSeqTaken
+			}
+		}
+	}
+	s.entries = append(s.entries, *entry)
+	return nil
+}
+
+// Last returns the chained entry with the highest sequence number
+func (s *MemoryStore) Last(ctx context.Context) (*models.AuditLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var last *models.AuditLog
+	for i := range s.entries {
+		if entry := &s.entries[i]; entry.Seq != nil && (last == nil || *entry.Seq > *last.Seq) {
+			last = entry
+		}
+	}
+	if last == nil {
+		return nil, ErrNotFound
+	}
+	entry := *last
+	return &entry, nil
+}
+
+// List returns a page of the matching entries
+func (s *MemoryStore) List(ctx context.Context, opts ListOptions) ([]models.AuditLog, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matches := s.matching(opts)
+	total := int64(len(matches))
+	start := min((opts.Page-1)*opts.PageSize, len(matches))
+	end := min(start+opts.PageSize, len(matches))
+	return matches[start:end], total, nil
+}
+
+// Each calls fn with copies of the matching entries, so fn may append to the store
+func (s *MemoryStore) Each(ctx context.Context, opts ListOptions, fn func(*models.AuditLog) error) error {
+	s.mu.Lock()
+	matches := s.matching(opts)
+	s.mu.Unlock()
+
+	for i := range matches {
+		if err := fn(&matches[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matching returns copies of the entries that match opts, newest first; the caller holds the lock
+func (s *MemoryStore) matching(opts ListOptions) []models.AuditLog {
+	matches := []models.AuditLog{}
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+		if opts.ActorID != "" && entry.ActorID != opts.ActorID ||
+			opts.Method != "" && entry.Method != opts.Method ||
+			opts.Route != "" && entry.Route != opts.Route ||
+			opts.From != nil && entry.CreatedAt.Before(*opts.From) ||
+			opts.To != nil && !entry.CreatedAt.Before(*opts.To) {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+	return matches
+}
+
+// Walk calls fn with a copy of the entries, so fn may append to the store
+func (s *MemoryStore) Walk(ctx context.Context, fn func(*models.AuditLog) error) error {
+	s.mu.Lock()
+	entries := slices.Clone(s.entries)
+	s.mu.Unlock()
+
+	slices.SortStableFunc(entries, func(a, b models.AuditLog) int {
+		switch {
+		case a.Seq == nil && b.Seq == nil:
+			return 0
+		case a.Seq == nil:
+			return -1
+		case b.Seq == nil:
+			return 1
+		}
+		return cmp.Compare(*a.Seq, *b.Seq)
+	})
+	for i := range entries {
+		if err := fn(&entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prune removes the entries created before cutoff, cutting the chain as Store describes
+func (s *MemoryStore) Prune(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var boundary *int64
+	for i := range s.entries {
+		entry := &s.entries[i]
+		if entry.Seq != nil && !entry.CreatedAt.Before(cutoff) && (boundary == nil || *entry.Seq < *boundary) {
+			boundary = entry.Seq
+		}
+	}
+	if boundary == nil {
+		for i := range s.entries {
+			if seq := s.entries[i].Seq; seq != nil && (boundary == nil || *seq > *boundary) {
+				boundary = seq
+			}
+		}
+	}
+
+	var kept []models.AuditLog
+	var pruned int64
+	for _, entry := range s.entries {
+		if entry.Seq == nil && entry.CreatedAt.Before(cutoff) || entry.Seq != nil && *entry.Seq < *boundary {
+			pruned++
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	if !dryRun {
+		s.entries = kept
+	}
+	return pruned, nil
+}
+
+// PostgresStore persists the audit log in PostgreSQL, where a trigger refuses changes and any deletes but
+// those of Prune
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the table is created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Append inserts the entry, relying on the unique index on seq to reject a second entry with its number
+func (s *PostgresStore) Append(ctx context.Context, entry *models.AuditLog) error {
+	err := s.db.WithContext(ctx).Create(entry).Error
+	if _, duplicate := database.DuplicateKeyIndex(err); duplicate && entry.Seq != nil {
+		return ErrSeqTaken
+	}
+	return err
+}
+
+// Last returns the chained entry with the highest sequence number
+func (s *PostgresStore) Last(ctx context.Context) (*models.AuditLog, error) {
+	var entry models.AuditLog
+	err := s.db.WithContext(ctx).Where("seq IS NOT NULL").Order("seq DESC").First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List returns a page of the matching entries
+func (s *PostgresStore) List(ctx context.Context, opts ListOptions) ([]models.AuditLog, int64, error) {
+	db := s.filtered(ctx, opts)
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	entries := []models.AuditLog{}
+	err := db.Order("created_at DESC, id DESC").Offset((opts.Page - 1) * opts.PageSize).Limit(opts.PageSize).Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// Each streams the matching entries from a single query on the replica
+func (s *PostgresStore) Each(ctx context.Context, opts ListOptions, fn func(*models.AuditLog) error) error {
+	db := s.filtered(ctx, opts)
+	rows, err := db.Order("created_at DESC, id DESC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AuditLog
+		if err := db.ScanRows(rows, &entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filtered selects the entries that match opts, on the replica when one is configured
+func (s *PostgresStore) filtered(ctx context.Context, opts ListOptions) *gorm.DB {
+	db := s.db.Replica().WithContext(ctx).Model(&models.AuditLog{})
+	if opts.ActorID != "" {
+		db = db.Where("actor_id = ?", opts.ActorID)
+	}
+	if opts.Method != "" {
+		db = db.Where("method = ?", opts.Method)
+	}
+	if opts.Route != "" {
+		db = db.Where("route = ?", opts.Route)
+	}
+	if opts.From != nil {
+		db = db.Where("created_at >= ?", *opts.From)
+	}
+	if opts.To != nil {
+		db = db.Where("created_at < ?", *opts.To)
+	}
+	return db
+}
+
+// Walk streams the entries from a single query; NULL sequence numbers sort first
+func (s *PostgresStore) Walk(ctx context.Context, fn func(*models.AuditLog) error) error {
+	db := s.db.WithContext(ctx)
+	rows, err := db.Model(&models.AuditLog{}).Order("seq ASC NULLS FIRST, created_at ASC, id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AuditLog
+		if err := db.ScanRows(rows, &entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Prune removes the entries created before cutoff, cutting the chain as Store describes. The trigger lets
+// the DELETE through because the transaction sets audit_logs.retention; SQLite has no trigger.
+func (s *PostgresStore) Prune(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	where := func(db *gorm.DB) *gorm.DB {
+		return db.Where("(seq IS NULL AND created_at < ?) OR seq < COALESCE("+
+			"(SELECT MIN(seq) FROM audit_logs WHERE created_at >= ?), (SELECT MAX(seq) FROM audit_logs))", cutoff, cutoff)
+	}
+
+	if dryRun {
+		var count int64
+		err := where(s.db.WithContext(ctx).Model(&models.AuditLog{})).Count(&count).Error
+		return count, err
+	}
+
+	var pruned int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if !s.db.IsSQLite() {
+			if err := tx.Exec("SET LOCAL audit_logs.retention = 'on'").Error; err != nil {
+				return err
+			}
+		}
+		result := where(tx).Delete(&models.AuditLog{})
+		pruned = result.RowsAffected
+		return result.Error
+	})
+	return pruned, err
+}
+
+// walkBatch is the number of entries MongoStore.Walk and Each read at a time
+const walkBatch = 500
+
+// MongoStore persists the audit log in MongoDB. MongoDB has no triggers, so grant the application
+// user only the find and insert actions on the collection to make it append-only.
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store and ensures its indexes exist
+func NewMongoStore(ctx context.Context, db *database.MongoDB) (*MongoStore, error) {
+	collection := db.Collection("audit_logs")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "seq", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"seq": bson.M{"$exists": true}}),
+		},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}}},
+		{Keys: bson.D{{Key: "route", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection}, nil
+}
+
+// Append inserts the entry, relying on the unique index on seq to reject a second entry with its number
+func (s *MongoStore) Append(ctx context.Context, entry *models.AuditLog) error {
+	_, err := s.collection.InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) && entry.Seq != nil {
+		return ErrSeqTaken
+	}
+	return err
+}
+
+// Last returns the chained entry with the highest sequence number
+func (s *MongoStore) Last(ctx context.Context) (*models.AuditLog, error) {
+	var entry models.AuditLog
+	err := s.collection.FindOne(ctx, bson.M{"seq": bson.M{"$exists": true}},
+		options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}})).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List returns a page of the matching entries
+func (s *MongoStore) List(ctx context.Context, opts ListOptions) ([]models.AuditLog, int64, error) {
+	filter := mongoFilter(opts)
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((opts.Page - 1) * opts.PageSize)).
+		SetLimit(int64(opts.PageSize))
+	cursor, err := s.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.AuditLog{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// Each streams the matching entries with a cursor
+func (s *MongoStore) Each(ctx context.Context, opts ListOptions, fn func(*models.AuditLog) error) error {
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetBatchSize(walkBatch)
+	cursor, err := s.collection.Find(ctx, mongoFilter(opts), findOpts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry models.AuditLog
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// mongoFilter selects the entries that match opts
+func mongoFilter(opts ListOptions) bson.M {
+	filter := bson.M{}
+	if opts.ActorID != "" {
+		filter["actor_id"] = opts.ActorID
+	}
+	if opts.Method != "" {
+		filter["method"] = opts.Method
+	}
+	if opts.Route != "" {
+		filter["route"] = opts.Route
+	}
+	if opts.From != nil || opts.To != nil {
+		createdAt := bson.M{}
+		if opts.From != nil {
+			createdAt["$gte"] = *opts.From
+		}
+		if opts.To != nil {
+			createdAt["$lt"] = *opts.To
+		}
+		filter["created_at"] = createdAt
+	}
+	return filter
+}
+
+// Walk streams the entries with a cursor; missing sequence numbers sort first
+func (s *MongoStore) Walk(ctx context.Context, fn func(*models.AuditLog) error) error {
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "seq", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetBatchSize(walkBatch)
+	cursor, err := s.collection.Find(ctx, bson.M{}, findOpts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry models.AuditLog
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Prune removes the entries created before cutoff, cutting the chain as Store describes
+func (s *MongoStore) Prune(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	filter := bson.M{"seq": bson.M{"$exists": false}, "created_at": bson.M{"$lt": cutoff}}
+
+	var first models.AuditLog
+	err := s.collection.FindOne(ctx, bson.M{"seq": bson.M{"$exists": true}, "created_at": bson.M{"$gte": cutoff}},
+		options.FindOne().SetSort(bson.D{{Key: "seq", Value: 1}})).Decode(&first)
+	var boundary *int64
+	switch {
+	case err == nil:
+		boundary = first.Seq
+	case errors.Is(err, mongo.ErrNoDocuments):
+		last, err := s.Last(ctx)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return 0, err
+		}
+		if last != nil {
+			boundary = last.Seq
+		}
+	default:
+		return 0, err
+	}
+	if boundary != nil {
+		filter = bson.M{"$or": bson.A{filter, bson.M{"seq": bson.M{"$lt": *boundary}}}}
+	}
+
+	if dryRun {
+		return s.collection.CountDocuments(ctx, filter)
+	}
+	result, err := s.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,226 @@
+// Package backup exports the users of either database to an encrypted archive and restores them from one,
+// for operational backups of small deployments. Archives are built and read in memory. Users are restored
+// by email like transfer.Copy, so an archive made from MongoDB restores into PostgreSQL and back; users
+// missing from the database get new IDs, so data keyed by user ID, such as posts, does not follow them.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+	"gorm.io/gorm"
+
+	"go-backend-template/models"
+	"go-backend-template/transfer"
+)
+
+// Version is the version of the archive contents this package writes and reads
+const Version = 1
+
+// magic starts every archive, followed by the scrypt salt and the AES-GCM nonce
+const magic = "GOBTBAK1"
+
+// Key derivation and batch sizes
+const (
+	saltSize  = 16
+	keySize   = 32
+	batchSize = 500
+)
+
+// ErrFormat is returned for data that is not an archive of a version this package reads
+var ErrFormat = errors.New("not a backup archive")
+
+// ErrDecrypt is returned when an archive does not decrypt with the key: the key is wrong, or the archive
+// was altered
+var ErrDecrypt = errors.New("the backup archive does not decrypt with this key")
+
+// Manifest describes the contents of an archive
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Source names the database the users were read from
+	Source string `json:"source"`
+	Users  int    `json:"users"`
+}
+
+// Result is what a restore wrote
+type Result struct {
+	Manifest Manifest
+	Created  int
+	Updated  int
+}
+
+// contents is the plaintext of an archive
+type contents struct {
+	Manifest Manifest `json:"manifest"`
+	Users    []record `json:"users"`
+}
+
+// record is a user with the fields the API never returns, which a restore needs
+type record struct {
+	models.User
+	Password    string     `json:"password"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Export reads every user of source, soft-deleted ones included, into an archive encrypted with key
+func Export(ctx context.Context, source transfer.Store, key string) ([]byte, Manifest, error) {
+	data := contents{Manifest: Manifest{Version: Version, CreatedAt: time.Now().UTC(), Source: source.Name()}}
+	err := source.Scan(ctx, batchSize, func(users []models.User) error {
+		for _, user := range users {
+			data.Users = append(data.Users, toRecord(user))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, Manifest{}, fmt.Errorf("failed to read the users of %s: %w", source.Name(), err)
+	}
+	data.Manifest.Users = len(data.Users)
+
+	var plaintext bytes.Buffer
+	zw := gzip.NewWriter(&plaintext)
+	if err := json.NewEncoder(zw).Encode(data); err != nil {
+		return nil, Manifest{}, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, Manifest{}, err
+	}
+
+	archive, err := seal(plaintext.Bytes(), key)
+	if err != nil {
+		return nil, Manifest{}, err
+	}
+	return archive, data.Manifest, nil
+}
+
+// Inspect decrypts an archive and returns its manifest without restoring it
+func Inspect(archive []byte, key string) (Manifest, error) {
+	data, err := open(archive, key)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return data.Manifest, nil
+}
+
+// Restore writes the users of an archive encrypted with key to target, matching them by email: users in
+// the archive are created or overwritten, and users created since the archive was made are kept
+func Restore(ctx context.Context, target transfer.Store, archive []byte, key string) (Result, error) {
+	data, err := open(archive, key)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Manifest: data.Manifest}
+	for start := 0; start < len(data.Users); start += batchSize {
+		batch := data.Users[start:min(start+batchSize, len(data.Users))]
+		users := make([]models.User, len(batch))
+		for i, rec := range batch {
+			users[i] = rec.user()
+		}
+		created, updated, err := target.Upsert(ctx, users)
+		if err != nil {
+			return result, fmt.Errorf("failed to write users to %s: %w", target.Name(), err)
+		}
+		result.Created += created
+		result.Updated += updated
+	}
+	return result, nil
+}
+
+// toRecord converts a user for the archive
+func toRecord(user models.User) record {
+	rec := record{User: user, Password: user.Password, LastLoginAt: user.LastLoginAt}
+	if user.DeletedAt.Valid {
+		deletedAt := user.DeletedAt.Time
+		rec.DeletedAt = &deletedAt
+	}
+	return rec
+}
+
+// user converts a record back to a user
+func (rec record) user() models.User {
+	user := rec.User
+	user.Password, user.LastLoginAt = rec.Password, rec.LastLoginAt
+	if rec.DeletedAt != nil {
+		user.DeletedAt = gorm.DeletedAt{Time: *rec.DeletedAt, Valid: true}
+	}
+	return user
+}
+
+// seal encrypts plaintext with AES-256-GCM under a key derived from the passphrase and a random salt;
+// the header is authenticated along with the ciphertext
+func seal(plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := append(append([]byte(magic), salt...), nonce...)
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+// open decrypts and decodes an archive
+func open(archive []byte, passphrase string) (*contents, error) {
+	if len(archive) < len(magic)+saltSize || string(archive[:len(magic)]) != magic {
+		return nil, ErrFormat
+	}
+	salt := archive[len(magic) : len(magic)+saltSize]
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	headerSize := len(magic) + saltSize + aead.NonceSize()
+	if len(archive) < headerSize {
+		return nil, ErrFormat
+	}
+
+	header := archive[:headerSize]
+	plaintext, err := aead.Open(nil, header[len(magic)+saltSize:], archive[headerSize:], header)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, ErrFormat
+	}
+	var data contents
+	if err := json.NewDecoder(zr).Decode(&data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	if data.Manifest.Version != Version {
+		return nil, fmt.Errorf("%w: version %d is not supported", ErrFormat, data.Manifest.Version)
+	}
+	return &data, nil
+}
+
+// newAEAD derives the archive key from the passphrase with scrypt
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,74 @@
+// Package billing manages Stripe customers, checkout, and the subscription plan of each user
+package billing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FreePlan is the plan of users without an active subscription
+const FreePlan = "free"
+
+// Plan is a purchasable tier; a higher Rank includes every lower plan's entitlements
+type Plan struct {
+	ID      string
+	PriceID string
+	Rank    int
+}
+
+// Catalog is the ordered set of plans, cheapest first, starting with the implicit free plan
+type Catalog struct {
+	plans []Plan
+}
+
+// NewCatalog parses "plan=stripe_price_id" entries, listed from the lowest tier to the highest
+func NewCatalog(entries []string) (*Catalog, error) {
+	catalog := &Catalog{plans: []Plan{{ID: FreePlan}}}
+	for _, entry := range entries {
+		id, priceID, ok := strings.Cut(entry, "=")
+		id, priceID = strings.TrimSpace(id), strings.TrimSpace(priceID)
+		if !ok || id == "" || priceID == "" {
+			return nil, fmt.Errorf("invalid plan %q: expected plan=stripe_price_id", entry)
+		}
+		if _, exists := catalog.Plan(id); exists {
+			return nil, fmt.Errorf("duplicate plan %q", id)
+		}
+		catalog.plans = append(catalog.plans, Plan{ID: id, PriceID: priceID, Rank: len(catalog.plans)})
+	}
+	return catalog, nil
+}
+
+// Plans returns every plan, cheapest first
+func (c *Catalog) Plans() []Plan {
+	return c.plans
+}
+
+// Plan looks up a plan by ID
+func (c *Catalog) Plan(id string) (Plan, bool) {
+	for _, plan := range c.plans {
+		if plan.ID == id {
+			return plan, true
+		}
+	}
+	return Plan{}, false
+}
+
+// PlanForPrice looks up the plan sold at a Stripe price
+func (c *Catalog) PlanForPrice(priceID string) (Plan, bool) {
+	for _, plan := range c.plans {
+		if plan.PriceID != "" && plan.PriceID == priceID {
+			return plan, true
+		}
+	}
+	return Plan{}, false
+}
+
+// Includes reports whether planID is at least as high a tier as requiredID
+func (c *Catalog) Includes(planID, requiredID string) bool {
+	plan, ok := c.Plan(planID)
+	if !ok {
+		return false
+	}
+	required, ok := c.Plan(requiredID)
+	return ok && plan.Rank >= required.Rank
+}
@@ -0,0 +1,212 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-backend-template/config"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// ErrUnknownPlan is returned when checking out a plan that is not for sale
+var ErrUnknownPlan = errors.New("unknown plan")
+
+// Service connects the plan catalog, Stripe, and the subscription store
+type Service struct {
+	catalog       *Catalog
+	stripe        *StripeClient
+	store         Store
+	webhookSecret string
+	successURL    string
+	cancelURL     string
+	logger        utils.Logger
+}
+
+// NewService creates a billing service from configuration
+func NewService(cfg config.BillingConfig, store Store, logger utils.Logger) (*Service, error) {
+	catalog, err := NewCatalog(cfg.Plans)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		catalog:       catalog,
+		stripe:        NewStripeClient(cfg.StripeSecretKey),
+		store:         store,
+		webhookSecret: cfg.StripeWebhookSecret,
+		successURL:    cfg.SuccessURL,
+		cancelURL:     cfg.CancelURL,
+		logger:        logger,
+	}, nil
+}
+
+// Catalog returns the configured plans
+func (s *Service) Catalog() *Catalog {
+	return s.catalog
+}
+
+// Subscription returns the user's subscription; users who never subscribed are on an active free plan
+func (s *Service) Subscription(ctx context.Context, userID string) (*models.Subscription, error) {
+	subscription, err := s.store.Get(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return &models.Subscription{UserID: userID, PlanID: FreePlan, Status: "active"}, nil
+	}
+	return subscription, err
+}
+
+// CurrentPlan returns the plan the user is entitled to: the subscribed plan while it is active or
+// trialing, otherwise the free plan
+func (s *Service) CurrentPlan(ctx context.Context, userID string) (string, error) {
+	subscription, err := s.Subscription(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if subscription.Status != "active" && subscription.Status != "trialing" {
+		return FreePlan, nil
+	}
+	return subscription.PlanID, nil
+}
+
+// HasPlan reports whether the user's current plan includes required
+func (s *Service) HasPlan(ctx context.Context, userID, required string) (bool, error) {
+	planID, err := s.CurrentPlan(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return s.catalog.Includes(planID, required), nil
+}
+
+// Checkout creates a Stripe Checkout session for planID, creating the user's Stripe customer on first use
+func (s *Service) Checkout(ctx context.Context, userID, email, planID string) (*CheckoutSession, error) {
+	plan, ok := s.catalog.Plan(planID)
+	if !ok || plan.PriceID == "" {
+		return nil, ErrUnknownPlan
+	}
+
+	subscription, err := s.store.Get(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		subscription = &models.Subscription{UserID: userID, PlanID: FreePlan, Status: "active"}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if subscription.CustomerID == "" {
+		customerID, err := s.stripe.CreateCustomer(ctx, email, userID)
+		if err != nil {
+			return nil, err
+		}
+		subscription.CustomerID = customerID
+		subscription.UpdatedAt = time.Now()
+		if err := s.store.Save(ctx, subscription); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.stripe.CreateCheckoutSession(ctx, subscription.CustomerID, plan.PriceID, userID, s.successURL, s.cancelURL)
+}
+
+// HandleWebhook verifies a Stripe webhook and records any subscription change it carries.
+// It returns ErrInvalidSignature for requests that did not come from Stripe.
+func (s *Service) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := ConstructEvent(payload, signature, s.webhookSecret)
+	if err != nil {
+		return err
+	}
+	return s.handle(ctx, event)
+}
+
+// HandleEvent records any subscription change carried by a Stripe event whose signature was verified
+// already, as the webhooks receiver does
+func (s *Service) HandleEvent(ctx context.Context, payload []byte) error {
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+	return s.handle(ctx, &event)
+}
+
+// handle records any subscription change event carries
+func (s *Service) handle(ctx context.Context, event *WebhookEvent) error {
+	switch event.Type {
+	case "checkout.session.completed":
+		var session CheckoutSession
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return fmt.Errorf("failed to decode checkout session: %w", err)
+		}
+		if session.Subscription == "" {
+			return nil
+		}
+		subscription, err := s.stripe.GetSubscription(ctx, session.Subscription)
+		if err != nil {
+			return err
+		}
+		return s.apply(ctx, session.ClientReferenceID, subscription)
+
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+		var subscription StripeSubscription
+		if err := json.Unmarshal(event.Data.Object, &subscription); err != nil {
+			return fmt.Errorf("failed to decode subscription: %w", err)
+		}
+		return s.apply(ctx, subscription.Metadata["user_id"], &subscription)
+
+	default:
+		s.logger.Debug("Ignoring Stripe event", "event_id", event.ID, "type", event.Type)
+		return nil
+	}
+}
+
+// apply stores the state of a Stripe subscription for its user, found by ID or else by Stripe customer
+func (s *Service) apply(ctx context.Context, userID string, subscription *StripeSubscription) error {
+	var record *models.Subscription
+	var err error
+	if userID != "" {
+		record, err = s.store.Get(ctx, userID)
+	} else {
+		record, err = s.store.GetByCustomer(ctx, subscription.Customer)
+	}
+	if errors.Is(err, ErrNotFound) {
+		if userID == "" {
+			s.logger.Warn("Stripe subscription for unknown customer", "customer_id", subscription.Customer, "subscription_id", subscription.ID)
+			return nil
+		}
+		record = &models.Subscription{UserID: userID}
+	} else if err != nil {
+		return err
+	}
+
+	// A superseded subscription being canceled must not downgrade the user's newer one
+	if record.SubscriptionID != "" && record.SubscriptionID != subscription.ID && subscription.Status == "canceled" {
+		return nil
+	}
+
+	record.CustomerID = subscription.Customer
+	record.SubscriptionID = subscription.ID
+	record.Status = subscription.Status
+	record.CancelAtPeriodEnd = subscription.CancelAtPeriodEnd
+	record.PlanID = FreePlan
+	record.CurrentPeriodEnd = nil
+
+	periodEnd := subscription.CurrentPeriodEnd
+	if len(subscription.Items.Data) > 0 {
+		item := subscription.Items.Data[0]
+		if plan, ok := s.catalog.PlanForPrice(item.Price.ID); ok {
+			record.PlanID = plan.ID
+		} else {
+			s.logger.Warn("Stripe subscription for unknown price", "price_id", item.Price.ID, "subscription_id", subscription.ID)
+		}
+		if periodEnd == 0 {
+			periodEnd = item.CurrentPeriodEnd
+		}
+	}
+	if periodEnd > 0 {
+		end := time.Unix(periodEnd, 0).UTC()
+		record.CurrentPeriodEnd = &end
+	}
+	record.UpdatedAt = time.Now()
+
+	s.logger.Info("Subscription updated", "user_id", record.UserID, "plan", record.PlanID, "status", record.Status)
+	return s.store.Save(ctx, record)
+}
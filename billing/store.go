@@ -0,0 +1,109 @@
+package billing
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// ErrNotFound is returned when a user has no billing record
+var ErrNotFound = errors.New("subscription not found")
+
+// Store persists each user's subscription
+type Store interface {
+	// Get returns the subscription for userID or ErrNotFound
+	Get(ctx context.Context, userID string) (*models.Subscription, error)
+	// GetByCustomer returns the subscription for a Stripe customer or ErrNotFound
+	GetByCustomer(ctx context.Context, customerID string) (*models.Subscription, error)
+	// Save creates or replaces the subscription
+	Save(ctx context.Context, subscription *models.Subscription) error
+}
+
+// PostgresStore persists subscriptions in PostgreSQL
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the table is created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Get returns the subscription for userID
+func (s *PostgresStore) Get(ctx context.Context, userID string) (*models.Subscription, error) {
+	return s.first(ctx, "user_id = ?", userID)
+}
+
+// GetByCustomer returns the subscription for a Stripe customer
+func (s *PostgresStore) GetByCustomer(ctx context.Context, customerID string) (*models.Subscription, error) {
+	return s.first(ctx, "customer_id = ?", customerID)
+}
+
+// Save upserts the subscription by user ID
+func (s *PostgresStore) Save(ctx context.Context, subscription *models.Subscription) error {
+	return s.db.WithContext(ctx).Save(subscription).Error
+}
+
+func (s *PostgresStore) first(ctx context.Context, query string, arg string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := s.db.WithContext(ctx).Where(query, arg).First(&subscription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// MongoStore persists subscriptions in MongoDB, keyed by user ID
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store and ensures the customer index exists
+func NewMongoStore(ctx context.Context, db *database.MongoDB) (*MongoStore, error) {
+	collection := db.Collection("subscriptions")
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "customer_id", Value: 1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection}, nil
+}
+
+// Get returns the subscription for userID
+func (s *MongoStore) Get(ctx context.Context, userID string) (*models.Subscription, error) {
+	return s.findOne(ctx, bson.M{"_id": userID})
+}
+
+// GetByCustomer returns the subscription for a Stripe customer
+func (s *MongoStore) GetByCustomer(ctx context.Context, customerID string) (*models.Subscription, error) {
+	return s.findOne(ctx, bson.M{"customer_id": customerID})
+}
+
+// Save upserts the subscription by user ID
+func (s *MongoStore) Save(ctx context.Context, subscription *models.Subscription) error {
+	_, err := s.collection.ReplaceOne(ctx, bson.M{"_id": subscription.UserID}, subscription, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s *MongoStore) findOne(ctx context.Context, filter bson.M) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := s.collection.FindOne(ctx, filter).Decode(&subscription)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
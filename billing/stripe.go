@@ -0,0 +1,213 @@
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend-template/httpclient"
+)
+
+// stripeAPI is the Stripe REST endpoint
+const stripeAPI = "https://api.stripe.com"
+
+// webhookTolerance is how old a signed webhook may be before it is rejected as a replay
+const webhookTolerance = 5 * time.Minute
+
+// ErrInvalidSignature is returned for webhooks whose Stripe-Signature does not verify
+var ErrInvalidSignature = errors.New("invalid Stripe webhook signature")
+
+// StripeClient calls the subset of the Stripe API used for subscriptions
+type StripeClient struct {
+	secretKey string
+	baseURL   string
+	client    *http.Client
+}
+
+// NewStripeClient creates a client authenticated with a secret API key
+func NewStripeClient(secretKey string) *StripeClient {
+	return &StripeClient{
+		secretKey: secretKey,
+		baseURL:   stripeAPI,
+		client:    httpclient.New("stripe", 30*time.Second),
+	}
+}
+
+// CheckoutSession is a Stripe-hosted payment page
+type CheckoutSession struct {
+	ID                string `json:"id"`
+	URL               string `json:"url"`
+	Customer          string `json:"customer"`
+	Subscription      string `json:"subscription"`
+	ClientReferenceID string `json:"client_reference_id"`
+}
+
+// StripeSubscription is the part of a Stripe subscription object the service stores
+type StripeSubscription struct {
+	ID                string            `json:"id"`
+	Customer          string            `json:"customer"`
+	Status            string            `json:"status"`
+	CurrentPeriodEnd  int64             `json:"current_period_end"`
+	CancelAtPeriodEnd bool              `json:"cancel_at_period_end"`
+	Metadata          map[string]string `json:"metadata"`
+	Items             struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+			Price            struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// WebhookEvent is a verified Stripe event; Data.Object holds the affected object
+type WebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// CreateCustomer creates a Stripe customer tagged with the user's ID
+func (s *StripeClient) CreateCustomer(ctx context.Context, email, userID string) (string, error) {
+	form := url.Values{}
+	form.Set("email", email)
+	form.Set("metadata[user_id]", userID)
+
+	var customer struct {
+		ID string `json:"id"`
+	}
+	if err := s.post(ctx, "/v1/customers", form, &customer); err != nil {
+		return "", err
+	}
+	return customer.ID, nil
+}
+
+// CreateCheckoutSession starts a subscription checkout for one price
+func (s *StripeClient) CreateCheckoutSession(ctx context.Context, customerID, priceID, userID, successURL, cancelURL string) (*CheckoutSession, error) {
+	form := url.Values{}
+	form.Set("mode", "subscription")
+	form.Set("customer", customerID)
+	form.Set("client_reference_id", userID)
+	form.Set("line_items[0][price]", priceID)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("subscription_data[metadata][user_id]", userID)
+	form.Set("success_url", successURL)
+	form.Set("cancel_url", cancelURL)
+
+	var session CheckoutSession
+	if err := s.post(ctx, "/v1/checkout/sessions", form, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetSubscription fetches a subscription by ID
+func (s *StripeClient) GetSubscription(ctx context.Context, id string) (*StripeSubscription, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/v1/subscriptions/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscription StripeSubscription
+	if err := s.do(req, &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// post sends a form-encoded request, the encoding the Stripe API expects
+func (s *StripeClient) post(ctx context.Context, path string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// Stripe runs a request once per key, which makes retrying it safe
+	req.Header.Set("Idempotency-Key", uuid.NewString())
+	return s.do(req, out)
+}
+
+// do authenticates and sends req, decoding the JSON response into out
+func (s *StripeClient) do(req *http.Request, out interface{}) error {
+	req.SetBasicAuth(s.secretKey, "")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Stripe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return fmt.Errorf("stripe returned status %d: %s", resp.StatusCode, body.Error.Message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Stripe response: %w", err)
+	}
+	return nil
+}
+
+// ConstructEvent verifies a webhook's Stripe-Signature header ("t=<unix>,v1=<hex hmac>,...") against the
+// endpoint secret and decodes the event. Signatures older than webhookTolerance are rejected.
+func ConstructEvent(payload []byte, signatureHeader, secret string) (*WebhookEvent, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signatureHeader, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return nil, ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > webhookTolerance || age < -webhookTolerance {
+		return nil, ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	verified := false
+	for _, signature := range signatures {
+		if decoded, err := hex.DecodeString(signature); err == nil && hmac.Equal(decoded, expected) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrInvalidSignature
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+	return &event, nil
+}
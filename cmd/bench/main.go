@@ -0,0 +1,212 @@
+// Command bench runs the API benchmarks: login, registration, the profile, and a page of users, each sent
+// through the full middleware stack and routes built by testutil.NewAPI. Results are compared with a
+// baseline file, and the command exits with status 1 when the memory a request allocates (B/op or
+// allocs/op) grows by more than -threshold. Time per request depends on the machine, so it is reported
+// but only checked with -check-time.
+//
+// Usage:
+//
+//	bench [-baseline cmd/bench/baseline.json] [-threshold 0.2] [-benchtime 1s] [-check-time] [-update]
+//
+// -update writes the results as the new baseline. Record a baseline on the machine that checks it when
+// using -check-time.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"text/tabwriter"
+
+	"go-backend-template/config"
+	"go-backend-template/models"
+	"go-backend-template/testutil"
+)
+
+// result is one benchmark's measurements, as stored in the baseline file
+type result struct {
+	Name        string  `json:"name"`
+	NsPerOp     int64   `json:"ns_per_op"`
+	BytesPerOp  int64   `json:"bytes_per_op"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+	OpsPerSec   float64 `json:"ops_per_sec"`
+}
+
+// benchmark is a named request loop
+type benchmark struct {
+	name string
+	run  func(b *testing.B)
+}
+
+// clients numbers the simulated clients; each request comes from its own address so the per-IP rate
+// limiter does not throttle the loop
+var clients atomic.Uint32
+
+func main() {
+	log.SetFlags(0)
+	testing.Init()
+	baselinePath := flag.String("baseline", "cmd/bench/baseline.json", "baseline file to compare with")
+	threshold := flag.Float64("threshold", 0.2, "allowed relative growth over the baseline")
+	benchtime := flag.String("benchtime", "1s", "run time or iteration count (such as 500x) of each benchmark")
+	checkTime := flag.Bool("check-time", false, "also fail when ns/op grows beyond the threshold")
+	update := flag.Bool("update", false, "write the results as the new baseline")
+	flag.Parse()
+
+	if err := flag.Set("test.benchtime", *benchtime); err != nil {
+		log.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// The loops send far more requests than a plan's monthly quota
+	cfg.Usage.Quotas = []string{"free=0"}
+	api, err := testutil.NewAPI(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var results []result
+	for _, bm := range benchmarks(api) {
+		r := testing.Benchmark(bm.run)
+		if r.N == 0 {
+			log.Fatalf("%s: benchmark failed", bm.name)
+		}
+		results = append(results, result{
+			Name:        bm.name,
+			NsPerOp:     r.NsPerOp(),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+			AllocsPerOp: r.AllocsPerOp(),
+			OpsPerSec:   float64(r.N) / r.T.Seconds(),
+		})
+	}
+
+	if *update {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(*baselinePath, append(data, '\n'), 0o644); err != nil {
+			log.Fatal(err)
+		}
+		report(results, nil, *threshold, *checkTime)
+		log.Printf("wrote %s", *baselinePath)
+		return
+	}
+
+	baseline := map[string]result{}
+	if data, err := os.ReadFile(*baselinePath); err == nil {
+		var stored []result
+		if err := json.Unmarshal(data, &stored); err != nil {
+			log.Fatalf("failed to parse %s: %v", *baselinePath, err)
+		}
+		for _, r := range stored {
+			baseline[r.Name] = r
+		}
+	} else if !os.IsNotExist(err) {
+		log.Fatal(err)
+	}
+
+	if regressions := report(results, baseline, *threshold, *checkTime); regressions > 0 {
+		log.Fatalf("%d benchmark(s) regressed by more than %.0f%% over %s", regressions, *threshold*100, *baselinePath)
+	}
+}
+
+// report prints the results next to the baseline and returns the number of regressions
+func report(results []result, baseline map[string]result, threshold float64, checkTime bool) int {
+	regressions := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "benchmark\tns/op\tB/op\tallocs/op\treq/s\t")
+	for _, r := range results {
+		base, ok := baseline[r.Name]
+		cell := func(current, previous int64, checked bool) string {
+			if !ok || previous == 0 {
+				return fmt.Sprint(current)
+			}
+			delta := float64(current-previous) / float64(previous)
+			mark := ""
+			if checked && delta > threshold {
+				mark = " !"
+				regressions++
+			}
+			return fmt.Sprintf("%d (%+.0f%%)%s", current, delta*100, mark)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.0f\t\n", r.Name,
+			cell(r.NsPerOp, base.NsPerOp, checkTime),
+			cell(r.BytesPerOp, base.BytesPerOp, true),
+			cell(r.AllocsPerOp, base.AllocsPerOp, true),
+			r.OpsPerSec)
+	}
+	w.Flush()
+	return regressions
+}
+
+// benchmarks seeds an admin, a user, and a hundred other users, and returns the request loops; register
+// runs last because it adds users
+func benchmarks(api *testutil.API) []benchmark {
+	admin := api.Users.Add(testutil.NewUser().Admin().Model()).ID
+	aliceBuilder := testutil.NewUser()
+	alice := api.Users.Add(aliceBuilder.Model()).ID
+	for i := 0; i < 100; i++ {
+		api.Users.Add(testutil.NewUser().Model())
+	}
+	login := aliceBuilder.LoginRequest()
+
+	// send serves one request and stops the run on an unexpected status
+	send := func(b *testing.B, method, target string, body interface{}, token string, want int) {
+		req := testutil.NewRequest(b, method, "/api/v1"+target, body)
+		n := clients.Add(1)
+		req.RemoteAddr = fmt.Sprintf("10.%d.%d.%d:40000", n>>16&255, n>>8&255, n&255)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		api.Router.ServeHTTP(rec, req)
+		if rec.Code != want {
+			log.Fatalf("%s %s returned %d, want %d: %s", method, target, rec.Code, want, rec.Body.String())
+		}
+	}
+
+	return []benchmark{
+		{"login", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				send(b, http.MethodPost, "/auth/login", login, "", http.StatusOK)
+			}
+		}},
+		{"get_profile", func(b *testing.B) {
+			b.ReportAllocs()
+			token := api.Tokens.Token(b, alice, "user")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				send(b, http.MethodGet, "/users/profile", nil, token, http.StatusOK)
+			}
+		}},
+		{"list_users", func(b *testing.B) {
+			b.ReportAllocs()
+			token := api.Tokens.Token(b, admin, "admin")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				send(b, http.MethodGet, "/users?page_size=20", nil, token, http.StatusOK)
+			}
+		}},
+		{"register", func(b *testing.B) {
+			b.ReportAllocs()
+			requests := make([]models.RegisterRequest, b.N)
+			for i := range requests {
+				requests[i] = testutil.NewUser().RegisterRequest()
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				send(b, http.MethodPost, "/auth/register", requests[i], "", http.StatusCreated)
+			}
+		}},
+	}
+}
@@ -0,0 +1,185 @@
+// Command cli administers the application without going through the HTTP API. It reads the same
+// environment, config files, and secrets as the server and connects to the configured databases.
+//
+// Usage:
+//
+//	cli create-admin --email EMAIL --username NAME [--superadmin]    create an administrator account
+//	cli reset-password --email EMAIL                                 set a user's password
+//	cli list-users [--search TEXT] [--role ROLE] [--json]            list users
+//	cli migrate up | down [N|all] | status                           manage the PostgreSQL schema
+//	cli seed [--users N] [--posts N]                                 create sample users and posts
+//	cli generate-jwt --email EMAIL | --user-id ID --role ROLE        sign an access token
+//	cli transfer-users --from DATABASE --to DATABASE [--verify-only]  copy users between MongoDB and PostgreSQL
+//	cli backup --out FILE [--from DATABASE]                          export users to an encrypted archive
+//	cli restore --in FILE [--to DATABASE] [--inspect]                restore users from an archive
+//	cli retention [--dry-run]                                        remove data past its retention period
+//
+// Passwords are read from standard input when --password is not given, so they stay out of the shell
+// history.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+
+	"go-backend-template/config"
+	"go-backend-template/database"
+	"go-backend-template/secrets"
+	"go-backend-template/utils"
+)
+
+// env is the configuration and connections the commands share
+type env struct {
+	cfg        *config.Config
+	logger     utils.Logger
+	mongoDB    *database.MongoDB
+	postgresDB *database.PostgresDB
+}
+
+func main() {
+	e := &env{}
+	root := &cobra.Command{
+		Use:           "cli",
+		Short:         "Administer the API from the command line",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return e.load(cmd.Context())
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			e.close()
+		},
+	}
+	root.AddCommand(
+		createAdminCommand(e),
+		resetPasswordCommand(e),
+		listUsersCommand(e),
+		migrateCommand(e),
+		seedCommand(e),
+		generateJWTCommand(e),
+		transferUsersCommand(e),
+		backupCommand(e),
+		restoreCommand(e),
+		retentionCommand(e),
+	)
+
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		e.close()
+		os.Exit(1)
+	}
+}
+
+// load reads the configuration and resolves its secrets the way the server does
+func (e *env) load(ctx context.Context) error {
+	_ = godotenv.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	logger, err := utils.NewLogger(utils.LoggerOptions{Level: "warn", Format: "text", Output: "stderr"})
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	provider, err := secrets.NewProviderFromConfig(&cfg.Secrets)
+	if err != nil {
+		return fmt.Errorf("failed to initialize secrets provider: %w", err)
+	}
+	if provider != nil {
+		if err := secrets.NewManager(provider, cfg.Secrets.RefreshInterval, logger).Resolve(ctx, cfg); err != nil {
+			return fmt.Errorf("failed to resolve secrets: %w", err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	e.cfg, e.logger = cfg, logger
+	return nil
+}
+
+// connect opens the configured databases; PostgreSQL (or SQLite) is preferred when both are enabled
+func (e *env) connect() error {
+	cfg := e.cfg
+	var err error
+
+	if cfg.MongoDB.Enabled {
+		if e.mongoDB, err = database.NewMongoDB(&cfg.MongoDB); err != nil {
+			return fmt.Errorf("failed to connect to MongoDB: %w", err)
+		}
+	}
+	if cfg.SQLite.Enabled {
+		if e.postgresDB, err = database.NewSQLiteDB(&cfg.SQLite, database.NewGormLogger(e.logger, "warn")); err != nil {
+			return fmt.Errorf("failed to open SQLite database: %w", err)
+		}
+	}
+	if cfg.PostgresDB.Enabled {
+		if e.postgresDB, err = database.NewPostgresDB(&cfg.PostgresDB, database.NewGormLogger(e.logger, "warn")); err != nil {
+			return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+		}
+	}
+	if e.mongoDB == nil && e.postgresDB == nil {
+		return errors.New("no database is enabled")
+	}
+	return nil
+}
+
+// database returns name, or the database the server uses when name is empty: postgres (which also names
+// SQLite) when it is connected, mongodb otherwise
+func (e *env) database(name string) string {
+	switch {
+	case name != "":
+		return name
+	case e.postgresDB != nil:
+		return "postgres"
+	default:
+		return "mongodb"
+	}
+}
+
+// close releases the connections
+func (e *env) close() {
+	if e.postgresDB != nil {
+		e.postgresDB.Close()
+		e.postgresDB = nil
+	}
+	if e.mongoDB != nil {
+		e.mongoDB.Disconnect()
+		e.mongoDB = nil
+	}
+}
+
+// readPassword returns the flag value, or reads a line from standard input when it is empty
+func readPassword(cmd *cobra.Command, flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	fmt.Fprint(cmd.ErrOrStderr(), "Password: ")
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("a password is required")
+	}
+	return password, nil
+}
+
+// checkPassword applies the password rule of registration
+func checkPassword(password string) error {
+	if !utils.IsStrongPassword(password) {
+		return errors.New("the password must be at least 8 characters with upper-case, lower-case, and a digit")
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go-backend-template/audit"
+	"go-backend-template/jobs"
+	"go-backend-template/jwt"
+	"go-backend-template/migrate"
+	"go-backend-template/migrations"
+	"go-backend-template/models"
+	"go-backend-template/posts"
+	"go-backend-template/services"
+)
+
+// migrateCommand manages the PostgreSQL schema like cmd/migrate; SQLite creates its schema on open
+func migrateCommand(e *env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate up | down [N|all] | status",
+		Short: "Apply, roll back, or list the PostgreSQL migrations",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !e.cfg.PostgresDB.Enabled || e.cfg.SQLite.Enabled {
+				return errors.New("migrations apply to PostgreSQL only; set POSTGRES_ENABLED=true")
+			}
+			if err := e.connect(); err != nil {
+				return err
+			}
+			migrator, err := migrate.New(e.postgresDB, migrations.FS)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+
+			switch args[0] {
+			case "up":
+				applied, err := migrator.Up(cmd.Context())
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Applied %d migrations\n", applied)
+			case "down":
+				steps := 1
+				if len(args) > 1 {
+					if args[1] == "all" {
+						steps = 0
+					} else if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+						return fmt.Errorf("invalid number of steps %q", args[1])
+					}
+				}
+				rolledBack, err := migrator.Down(cmd.Context(), steps)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Rolled back %d migrations\n", rolledBack)
+			case "status":
+				status, err := migrator.Status(cmd.Context())
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "version %d of %d", status.Version, status.Latest)
+				if status.Dirty {
+					fmt.Fprint(out, " (dirty)")
+				}
+				fmt.Fprintln(out)
+				for _, migration := range status.Migrations {
+					state := "pending"
+					if migration.Applied {
+						state = "applied"
+					}
+					fmt.Fprintf(out, "  %06d_%s\t%s\n", migration.Version, migration.Name, state)
+				}
+			default:
+				return fmt.Errorf("unknown migrate command %q", args[0])
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// seedCommand creates sample users, each with a few posts; users that already exist are skipped, so
+// running it again is harmless
+func seedCommand(e *env) *cobra.Command {
+	var users, postsPerUser int
+	var password string
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Create sample users and posts for development",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if e.cfg.IsProduction() {
+				return errors.New("refusing to seed a production database")
+			}
+			if err := checkPassword(password); err != nil {
+				return err
+			}
+			if err := e.connect(); err != nil {
+				return err
+			}
+
+			admin := services.NewAdminService(e.mongoDB, e.postgresDB)
+			var store posts.Store
+			if e.postgresDB != nil {
+				store = posts.NewPostgresStore(e.postgresDB)
+			} else {
+				store = posts.NewMongoStore(e.mongoDB)
+			}
+
+			created, skipped := 0, 0
+			for i := 1; i <= users; i++ {
+				email := fmt.Sprintf("seed-user-%d@example.com", i)
+				if _, err := admin.FindByEmail(cmd.Context(), email); err == nil {
+					skipped++
+					continue
+				} else if !errors.Is(err, services.ErrUserNotFound) {
+					return err
+				}
+				user, err := admin.CreateUser(cmd.Context(), models.RegisterRequest{
+					Email:     email,
+					Username:  fmt.Sprintf("seed_user_%d", i),
+					Password:  password,
+					FirstName: "Seed",
+					LastName:  fmt.Sprintf("User %d", i),
+				}, "user")
+				if errors.Is(err, services.ErrDuplicateEmail) || errors.Is(err, services.ErrDuplicateUsername) {
+					skipped++
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				for j := 1; j <= postsPerUser; j++ {
+					_, err := store.Create(cmd.Context(), user.ID, models.CreatePostRequest{
+						Title: fmt.Sprintf("Sample post %d", j),
+						Body:  fmt.Sprintf("Post %d of %s, created by the seed command.", j, user.Username),
+					})
+					if err != nil {
+						return err
+					}
+				}
+				created++
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created %d users with %d posts each, skipped %d existing\n", created, postsPerUser, skipped)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&users, "users", 10, "number of users")
+	cmd.Flags().IntVar(&postsPerUser, "posts", 3, "posts per user")
+	cmd.Flags().StringVar(&password, "password", "Password123", "password of every seeded user")
+	return cmd
+}
+
+// generateJWTCommand signs an access token for a stored user, or for explicit claims without a database
+func generateJWTCommand(e *env) *cobra.Command {
+	var email, userID, username, role, locale, orgID string
+	var memberships []string
+	cmd := &cobra.Command{
+		Use:   "generate-jwt",
+		Short: "Sign an access token with JWT_SECRET",
+		Long: "Sign an access token with JWT_SECRET. With --email the claims are read from the user's account; " +
+			"otherwise --user-id and --role are used as given, without connecting to a database. " +
+			"--membership ORG=ROLE (member, admin, or owner) adds an organization, and --org selects the default one.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := userID
+			if email != "" {
+				if err := e.connect(); err != nil {
+					return err
+				}
+				user, err := services.NewAdminService(e.mongoDB, e.postgresDB).FindByEmail(cmd.Context(), email)
+				if err != nil {
+					return err
+				}
+				id, username, role, locale = user.ID, user.Username, user.Role, user.Locale
+			} else if userID == "" {
+				return errors.New("either --email or --user-id is required")
+			}
+
+			claims := jwt.NewClaims(id, email, username, strings.ToLower(role), locale)
+			for _, membership := range memberships {
+				org, orgRole, ok := strings.Cut(membership, "=")
+				if !ok {
+					return fmt.Errorf("invalid membership %q; use ORG=ROLE", membership)
+				}
+				if claims.Memberships == nil {
+					claims.Memberships = make(map[string]string)
+				}
+				claims.Memberships[org] = strings.ToLower(orgRole)
+			}
+			claims.OrgID = orgID
+
+			token, expiresAt, err := jwt.Sign(e.cfg.JWTSecret, claims)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), token)
+			fmt.Fprintf(cmd.ErrOrStderr(), "Expires %s\n", expiresAt.Format(time.RFC3339))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "email address of the user to sign a token for")
+	cmd.Flags().StringVar(&userID, "user-id", "", "user ID claim, when --email is not given")
+	cmd.Flags().StringVar(&username, "username", "", "username claim, when --email is not given")
+	cmd.Flags().StringVar(&role, "role", "user", "role claim, when --email is not given")
+	cmd.Flags().StringArrayVar(&memberships, "membership", nil, "organization membership as ORG=ROLE; repeat for several")
+	cmd.Flags().StringVar(&orgID, "org", "", "default organization of the token; must be one of the memberships")
+	cmd.MarkFlagsMutuallyExclusive("email", "user-id")
+	return cmd
+}
+
+// retentionCommand applies the retention policies once, like the scheduled job
+func retentionCommand(e *env) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "retention [--dry-run]",
+		Short: "Remove the data older than the retention policies allow",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := e.connect(); err != nil {
+				return err
+			}
+
+			var auditLog *audit.Recorder
+			if e.cfg.AuditLog.Enabled {
+				var store audit.Store
+				if e.postgresDB != nil {
+					store = audit.NewPostgresStore(e.postgresDB)
+				} else {
+					mongoStore, err := audit.NewMongoStore(cmd.Context(), e.mongoDB)
+					if err != nil {
+						return err
+					}
+					store = mongoStore
+				}
+				auditLog = audit.NewRecorder(store, audit.Options{})
+			}
+
+			results, err := jobs.NewRetention(e.mongoDB, e.postgresDB, auditLog, e.cfg.Retention, e.logger).Run(cmd.Context(), dryRun)
+
+			verb := "REMOVED"
+			if dryRun {
+				verb = "WOULD REMOVE"
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "POLICY\tOLDER THAN\t%s\n", verb)
+			for _, result := range results {
+				fmt.Fprintf(w, "%s\t%s\t%d\n", result.Policy, result.Cutoff.Format(time.RFC3339), result.Count)
+			}
+			w.Flush()
+			if len(results) == 0 && err == nil {
+				fmt.Fprintln(cmd.OutOrStdout(), "No retention policy is enabled")
+			}
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only report what would be removed")
+	return cmd
+}
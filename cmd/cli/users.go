@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go-backend-template/backup"
+	"go-backend-template/models"
+	"go-backend-template/services"
+	"go-backend-template/transfer"
+	"go-backend-template/utils"
+)
+
+// createAdminCommand creates an administrator account
+func createAdminCommand(e *env) *cobra.Command {
+	var req models.RegisterRequest
+	var superadmin bool
+	cmd := &cobra.Command{
+		Use:   "create-admin",
+		Short: "Create an administrator account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !utils.IsValidEmail(req.Email) {
+				return fmt.Errorf("%q is not a valid email address", req.Email)
+			}
+			if !utils.IsValidUsername(req.Username) {
+				return errors.New("the username must be 3-32 letters, digits, dots, underscores, or hyphens")
+			}
+			password, err := readPassword(cmd, req.Password)
+			if err != nil {
+				return err
+			}
+			if err := checkPassword(password); err != nil {
+				return err
+			}
+			req.Password = password
+
+			role := "admin"
+			if superadmin {
+				role = "superadmin"
+			}
+			if err := e.connect(); err != nil {
+				return err
+			}
+			user, err := services.NewAdminService(e.mongoDB, e.postgresDB).CreateUser(cmd.Context(), req, role)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created %s %s (ID %s)\n", role, user.Email, user.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&req.Email, "email", "", "email address (required)")
+	cmd.Flags().StringVar(&req.Username, "username", "", "username (required)")
+	cmd.Flags().StringVar(&req.Password, "password", "", "password; read from standard input when empty")
+	cmd.Flags().StringVar(&req.FirstName, "first-name", "Admin", "first name")
+	cmd.Flags().StringVar(&req.LastName, "last-name", "User", "last name")
+	cmd.Flags().BoolVar(&superadmin, "superadmin", false, "grant the superadmin role instead of admin")
+	cmd.MarkFlagRequired("email")
+	cmd.MarkFlagRequired("username")
+	return cmd
+}
+
+// resetPasswordCommand sets a user's password
+func resetPasswordCommand(e *env) *cobra.Command {
+	var email, password string
+	cmd := &cobra.Command{
+		Use:   "reset-password",
+		Short: "Set a user's password",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			password, err := readPassword(cmd, password)
+			if err != nil {
+				return err
+			}
+			if err := checkPassword(password); err != nil {
+				return err
+			}
+			if err := e.connect(); err != nil {
+				return err
+			}
+			if err := services.NewAdminService(e.mongoDB, e.postgresDB).ResetPassword(cmd.Context(), email, password); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Password of %s updated\n", email)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "email address of the user (required)")
+	cmd.Flags().StringVar(&password, "password", "", "new password; read from standard input when empty")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+// listUsersCommand prints a page of users
+func listUsersCommand(e *env) *cobra.Command {
+	query := services.ListUsersQuery{Sort: utils.DefaultUserSort}
+	var role string
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "list-users",
+		Short: "List users, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if query.Page < 1 || query.PageSize < 1 {
+				return errors.New("--page and --page-size must be positive")
+			}
+			if role != "" {
+				query.Filters = []utils.Filter{{Column: "role", Operator: utils.FilterEq, Value: role}}
+			}
+			if err := e.connect(); err != nil {
+				return err
+			}
+			users, total, err := services.NewUserService(e.mongoDB, e.postgresDB, nil, nil).ListUsers(cmd.Context(), query)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(models.PaginatedResponse{
+					Data:       users,
+					Pagination: models.Pagination{Page: query.Page, PageSize: query.PageSize, Total: total, TotalPage: int((total + int64(query.PageSize) - 1) / int64(query.PageSize))},
+				})
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tEMAIL\tUSERNAME\tROLE\tACTIVE\tCREATED")
+			for _, user := range users {
+				fmt.Fprintf(w, "%v\t%s\t%s\t%s\t%t\t%s\n", user.ID, user.Email, user.Username, user.Role, user.IsActive,
+					user.CreatedAt.Format("2006-01-02 15:04"))
+			}
+			w.Flush()
+			fmt.Fprintf(cmd.OutOrStdout(), "%d of %d users\n", len(users), total)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&query.Search, "search", "", "match names, emails, and usernames")
+	cmd.Flags().StringVar(&role, "role", "", "only list users with this role")
+	cmd.Flags().IntVar(&query.Page, "page", 1, "page number")
+	cmd.Flags().IntVar(&query.PageSize, "page-size", 20, "users per page")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print the page as JSON")
+	return cmd
+}
+
+// transferUsersCommand copies the users of one database to the other and reports whether they match
+func transferUsersCommand(e *env) *cobra.Command {
+	var from, to string
+	var batchSize int
+	var verifyOnly, asJSON bool
+	cmd := &cobra.Command{
+		Use:   "transfer-users --from DATABASE --to DATABASE",
+		Short: "Copy users between MongoDB and PostgreSQL and verify the copy",
+		Long: "Copy every user, soft-deleted ones included, from one database to the other, matching users by " +
+			"email, then compare both and report missing and differing users. Both databases must be enabled; " +
+			"postgres also names SQLite. Running it again brings the target up to date. With --verify-only " +
+			"nothing is written. The command fails when the databases do not match.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == to {
+				return errors.New("--from and --to must name different databases")
+			}
+			if batchSize < 1 {
+				return errors.New("--batch-size must be positive")
+			}
+			if err := e.connect(); err != nil {
+				return err
+			}
+			source, err := transfer.NewStore(from, e.mongoDB, e.postgresDB)
+			if err != nil {
+				return err
+			}
+			target, err := transfer.NewStore(to, e.mongoDB, e.postgresDB)
+			if err != nil {
+				return err
+			}
+
+			var report *transfer.Report
+			if verifyOnly {
+				report, err = transfer.Verify(cmd.Context(), source, target, batchSize)
+			} else {
+				report, err = transfer.Copy(cmd.Context(), source, target, batchSize)
+			}
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				report.WriteText(cmd.OutOrStdout())
+			}
+			if !report.Verified() {
+				return fmt.Errorf("%s and %s do not hold the same users", report.Source, report.Target)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "database to read users from: postgres or mongodb (required)")
+	cmd.Flags().StringVar(&to, "to", "", "database to write users to: postgres or mongodb (required)")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 500, "users read and written at once")
+	cmd.Flags().BoolVar(&verifyOnly, "verify-only", false, "only compare the databases")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print the report as JSON")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+// backupCommand exports the users to an encrypted archive
+func backupCommand(e *env) *cobra.Command {
+	var from, out string
+	cmd := &cobra.Command{
+		Use:   "backup --out FILE",
+		Short: "Export every user to an archive encrypted with BACKUP_ENCRYPTION_KEY",
+		Long: "Export every user, soft-deleted ones and password hashes included, to a compressed archive " +
+			"encrypted with BACKUP_ENCRYPTION_KEY. --from defaults to the database the server uses.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if e.cfg.Backup.EncryptionKey == "" {
+				return errors.New("set BACKUP_ENCRYPTION_KEY to encrypt the archive with")
+			}
+			if err := e.connect(); err != nil {
+				return err
+			}
+			source, err := transfer.NewStore(e.database(from), e.mongoDB, e.postgresDB)
+			if err != nil {
+				return err
+			}
+
+			archive, manifest, err := backup.Export(cmd.Context(), source, e.cfg.Backup.EncryptionKey)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(out, archive, 0o600); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Backed up %d users from %s to %s\n", manifest.Users, manifest.Source, out)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "database to read users from: postgres or mongodb")
+	cmd.Flags().StringVar(&out, "out", "", "file to write the archive to (required)")
+	cmd.MarkFlagRequired("out")
+	return cmd
+}
+
+// restoreCommand writes the users of an encrypted archive back to a database
+func restoreCommand(e *env) *cobra.Command {
+	var to, in string
+	var inspect bool
+	cmd := &cobra.Command{
+		Use:   "restore --in FILE",
+		Short: "Restore the users of an archive made by backup",
+		Long: "Write the users of an archive made by backup to a database, matching them by email: users in the " +
+			"archive are created or overwritten, and users created since are kept. --to defaults to the " +
+			"database the server uses. With --inspect, only the manifest of the archive is printed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if e.cfg.Backup.EncryptionKey == "" {
+				return errors.New("set BACKUP_ENCRYPTION_KEY to the key the archive was encrypted with")
+			}
+			archive, err := os.ReadFile(in)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+
+			if inspect {
+				manifest, err := backup.Inspect(archive, e.cfg.Backup.EncryptionKey)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "%d users from %s, made %s (version %d)\n", manifest.Users, manifest.Source,
+					manifest.CreatedAt.Format(time.RFC3339), manifest.Version)
+				return nil
+			}
+
+			if err := e.connect(); err != nil {
+				return err
+			}
+			target, err := transfer.NewStore(e.database(to), e.mongoDB, e.postgresDB)
+			if err != nil {
+				return err
+			}
+			result, err := backup.Restore(cmd.Context(), target, archive, e.cfg.Backup.EncryptionKey)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Restored %d users from %s made %s: %d created, %d updated\n", result.Manifest.Users,
+				result.Manifest.Source, result.Manifest.CreatedAt.Format(time.RFC3339), result.Created, result.Updated)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&to, "to", "", "database to write users to: postgres or mongodb")
+	cmd.Flags().StringVar(&in, "in", "", "archive to restore (required)")
+	cmd.Flags().BoolVar(&inspect, "inspect", false, "only print the manifest of the archive")
+	cmd.MarkFlagRequired("in")
+	return cmd
+}
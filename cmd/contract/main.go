@@ -0,0 +1,400 @@
+// Command contract checks the handlers against their Swagger annotations. It builds the router with the
+// in-memory fakes from testutil, sends requests covering the documented operations and their documented
+// outcomes, and validates every response against docs/swagger.json with the contract package. It exits
+// with status 1 when a response drifts from the document, a request does not get the expected status, or
+// an error response has no code from the errcodes registry.
+//
+// Usage:
+//
+//	contract [-spec docs/swagger.json] [-strict]
+//
+// Operations that need external services (billing, migrations) are listed as not exercised; -strict
+// fails on those too.
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/config"
+	"go-backend-template/contract"
+	"go-backend-template/errcodes"
+	"go-backend-template/models"
+	"go-backend-template/testutil"
+	"go-backend-template/webhooks"
+)
+
+// runner sends requests to the router and collects contract violations and unexpected statuses. It
+// stands in for the testing.TB the testutil helpers take; they only call Helper and Fatalf.
+type runner struct {
+	testing.TB
+	router   *gin.Engine
+	tokens   *testutil.TokenFactory
+	failures []string
+	sent     int
+}
+
+// Helper implements testing.TB
+func (r *runner) Helper() {}
+
+// Fatalf implements testing.TB; a helper failing outside a request is a broken scenario
+func (r *runner) Fatalf(format string, args ...interface{}) {
+	log.Fatalf(format, args...)
+}
+
+func main() {
+	log.SetFlags(0)
+	specPath := flag.String("spec", "docs/swagger.json", "Swagger 2.0 document generated by swag")
+	strict := flag.Bool("strict", false, "fail when a documented operation is not exercised")
+	flag.Parse()
+
+	raw, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spec, err := contract.Load(raw)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.UserMetadata.Schema = []string{"plan=string", "seats=number"}
+	cfg.Webhooks.MaxAttempts = 2
+	r := &runner{}
+	api, err := testutil.NewAPI(cfg, spec.Middleware(func(v *contract.Violation) {
+		r.failures = append(r.failures, v.Error())
+	}))
+	if err != nil {
+		log.Fatal(err)
+	}
+	r.router, r.tokens = api.Router, api.Tokens
+
+	run(r, api.Users)
+	runWebhooks(r, api.Users, api.Webhooks)
+
+	unexercised := spec.Unexercised()
+	for _, key := range unexercised {
+		log.Printf("not exercised: %s", key)
+	}
+	for _, failure := range r.failures {
+		log.Printf("FAIL %s", failure)
+	}
+	if len(r.failures) > 0 || (*strict && len(unexercised) > 0) {
+		os.Exit(1)
+	}
+	log.Print("all responses match the API documentation")
+}
+
+// do sends a request as the user (no token when userID is empty) and records a failure when the status
+// is not want or an error envelope lacks a registered code
+func (r *runner) do(method, target string, body interface{}, userID, role string, want int, headers ...string) *httptest.ResponseRecorder {
+	req := testutil.NewRequest(r, method, "/api/v1"+target, body)
+	// Spread the scenario over client addresses so it stays under the per-IP rate limit
+	req.RemoteAddr = fmt.Sprintf("192.0.2.%d:1234", r.sent/50+1)
+	r.sent++
+	if userID != "" {
+		req.Header.Set("Authorization", r.tokens.Header(r, userID, role))
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	rec := testutil.Serve(r.router, req)
+	if rec.Code != want {
+		r.failures = append(r.failures, fmt.Sprintf("%s %s returned %d, want %d: %s", method, target, rec.Code, want,
+			strings.TrimSpace(rec.Body.String())))
+	}
+	var response models.APIResponse
+	if rec.Code >= http.StatusBadRequest && json.Unmarshal(rec.Body.Bytes(), &response) == nil && response.Message != "" {
+		if _, ok := errcodes.Lookup(response.Code); !ok {
+			r.failures = append(r.failures, fmt.Sprintf("%s %s returned %d without a registered error code: %s", method, target,
+				rec.Code, strings.TrimSpace(rec.Body.String())))
+		}
+	}
+	return rec
+}
+
+// run sends the scenario: the seeded users are a superadmin, an admin, alice and bob, and a deleted user
+func run(r *runner, users *testutil.UserRepository) {
+	superadmin := users.Add(testutil.NewUser().WithRole("superadmin").Model()).ID
+	admin := users.Add(testutil.NewUser().Admin().Model()).ID
+	aliceBuilder := testutil.NewUser()
+	alice := users.Add(aliceBuilder.Model()).ID
+	bobInfo := users.Add(testutil.NewUser().Model())
+	bob := bobInfo.ID
+	deleted := users.Add(testutil.NewUser().Deleted().Model()).ID
+	const (
+		get   = http.MethodGet
+		post  = http.MethodPost
+		put   = http.MethodPut
+		del   = http.MethodDelete
+		patch = http.MethodPatch
+	)
+
+	// Health has no dependencies to check without databases
+	r.do(get, "/health", nil, "", "", http.StatusOK)
+	r.do(get, "/languages", nil, "", "", http.StatusOK)
+
+	// Unrouted requests get the error envelope with the request ID, and a 405 lists the allowed methods
+	notFound := r.do(get, "/no-such-route", nil, "", "", http.StatusNotFound)
+	notAllowed := r.do(del, "/auth/login", nil, "", "", http.StatusMethodNotAllowed)
+	for _, rec := range []*httptest.ResponseRecorder{notFound, notAllowed} {
+		if response := testutil.DecodeResponse(r, rec); response.RequestID == "" {
+			r.failures = append(r.failures, fmt.Sprintf("unrouted request returned %d without a request ID", rec.Code))
+		}
+	}
+	if allow := notAllowed.Header().Get("Allow"); allow != http.MethodPost {
+		r.failures = append(r.failures, fmt.Sprintf("DELETE /auth/login returned Allow %q, want POST", allow))
+	}
+
+	// Authentication
+	newUser := testutil.NewUser()
+	r.do(post, "/auth/register", newUser.RegisterRequest(), "", "", http.StatusCreated)
+	r.do(post, "/auth/register", newUser.RegisterRequest(), "", "", http.StatusConflict)
+	r.do(post, "/auth/register", map[string]string{}, "", "", http.StatusBadRequest)
+	r.do(post, "/auth/login", aliceBuilder.LoginRequest(), "", "", http.StatusOK)
+	r.do(post, "/auth/login", models.LoginRequest{Email: "nobody@example.com", Password: "Password123"}, "", "", http.StatusUnauthorized)
+	r.do(post, "/auth/login", "{", "", "", http.StatusBadRequest)
+	r.do(post, "/auth/logout", nil, "", "", http.StatusOK)
+
+	// Profile
+	r.do(get, "/users/profile", nil, "", "", http.StatusUnauthorized)
+	profile := r.do(get, "/users/profile", nil, alice, "user", http.StatusOK)
+	r.do(get, "/users/profile", nil, alice, "user", http.StatusNotModified, "If-None-Match", profile.Header().Get("ETag"))
+	r.do(get, "/users/profile?fields=email", nil, alice, "user", http.StatusOK)
+	r.do(get, "/users/profile?fields=password", nil, alice, "user", http.StatusBadRequest)
+	r.do(get, "/users/profile", nil, deleted, "user", http.StatusNotFound)
+	r.do(put, "/users/profile", models.UpdateUserRequest{FirstName: "Alice"}, alice, "user", http.StatusOK)
+	optOut := true
+	r.do(put, "/users/profile", models.UpdateUserRequest{AnalyticsOptOut: &optOut}, alice, "user", http.StatusOK)
+	phone, birthday := "+14155550123", models.Date("1990-05-17")
+	r.do(put, "/users/profile", models.UpdateUserRequest{Phone: &phone, Birthday: &birthday,
+		Address: &models.Address{City: "San Francisco", Country: "US"}}, alice, "user", http.StatusOK)
+	cleared := ""
+	r.do(put, "/users/profile", models.UpdateUserRequest{Phone: &cleared, AvatarURL: &cleared}, alice, "user", http.StatusOK)
+	badPhone := "555-0123"
+	r.do(put, "/users/profile", models.UpdateUserRequest{Phone: &badPhone}, alice, "user", http.StatusBadRequest)
+	r.do(put, "/users/profile", models.UpdateUserRequest{Metadata: models.Metadata{"plan": "pro", "seats": 5}}, alice, "user", http.StatusOK)
+	r.do(put, "/users/profile", models.UpdateUserRequest{Metadata: models.Metadata{"seats": "five"}}, alice, "user", http.StatusBadRequest)
+	r.do(put, "/users/profile", models.UpdateUserRequest{Metadata: models.Metadata{"unknown": true}}, alice, "user", http.StatusBadRequest)
+	r.do(put, "/users/profile", models.UpdateUserRequest{LastName: "Smith"}, alice, "user", http.StatusPreconditionFailed, "If-Match", `"stale"`)
+	r.do(put, "/users/profile", models.UpdateUserRequest{Email: bobInfo.Email}, alice, "user", http.StatusConflict)
+	r.do(put, "/users/profile", models.UpdateUserRequest{Email: "not-an-email"}, alice, "user", http.StatusBadRequest)
+	r.do(put, "/users/profile", models.UpdateUserRequest{FirstName: "Ghost"}, deleted, "user", http.StatusNotFound)
+	r.do(get, "/users/usage", nil, alice, "user", http.StatusOK)
+
+	// User administration
+	list := r.do(get, "/users", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/users", nil, admin, "admin", http.StatusNotModified, "If-None-Match", list.Header().Get("ETag"))
+	r.do(get, "/users?search=alice", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/users?metadata.seats[gt]=3&metadata.plan=pro", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/users?email_status=bounced", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/users?metadata.seats=many", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/users?cursor=&page_size=2&sort=email", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/users?sort=password", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/users", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/users", nil, "", "", http.StatusUnauthorized)
+	r.do(get, "/admin/users/export", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/admin/users/export?format=csv&fields=id,email,metadata&role=user", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/admin/users/export?format=xml", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/admin/users/export?fields=password", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/admin/users/export", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/admin/users/export", nil, "", "", http.StatusUnauthorized)
+	r.do(del, "/admin/users/"+bob, nil, admin, "admin", http.StatusOK)
+	r.do(del, "/admin/users/"+bob, nil, admin, "admin", http.StatusNotFound)
+	r.do(del, "/admin/users/abc", nil, admin, "admin", http.StatusBadRequest)
+	r.do(del, "/admin/users/"+bob, nil, alice, "user", http.StatusForbidden)
+	r.do(del, "/admin/users/"+bob, nil, "", "", http.StatusUnauthorized)
+	r.do(post, "/admin/users/"+bob+"/restore", nil, admin, "admin", http.StatusOK)
+	r.do(post, "/admin/users/"+bob+"/restore", nil, admin, "admin", http.StatusNotFound)
+	r.do(post, "/admin/users/abc/restore", nil, admin, "admin", http.StatusBadRequest)
+	r.do(post, "/admin/users/"+bob+"/restore", nil, alice, "user", http.StatusForbidden)
+	r.do(post, "/admin/users/"+bob+"/restore", nil, "", "", http.StatusUnauthorized)
+	promote := models.ChangeRoleRequest{Role: "admin", Reason: "Joined the support team"}
+	r.do(patch, "/admin/users/"+bob+"/role", promote, superadmin, "superadmin", http.StatusOK)
+	r.do(patch, "/admin/users/"+bob+"/role", promote, admin, "admin", http.StatusForbidden)
+	r.do(patch, "/admin/users/"+bob+"/role", models.ChangeRoleRequest{Role: "owner", Reason: "Typo"}, superadmin, "superadmin", http.StatusBadRequest)
+	r.do(patch, "/admin/users/"+deleted+"/role", promote, superadmin, "superadmin", http.StatusNotFound)
+	r.do(patch, "/admin/users/"+superadmin+"/role", models.ChangeRoleRequest{Role: "user", Reason: "Stepping down"}, superadmin, "superadmin", http.StatusConflict)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "deactivate", IDs: []string{bob, deleted, "abc", admin}}, admin, "admin", http.StatusOK)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "activate", Filter: map[string]string{"is_active": "false"}}, admin, "admin", http.StatusOK)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "role", IDs: []string{bob}, Role: "user", Reason: "Left the support team"}, superadmin, "superadmin", http.StatusOK)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "role", IDs: []string{bob}, Role: "user", Reason: "Left the support team"}, admin, "admin", http.StatusForbidden)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "role", IDs: []string{bob}, Role: "user"}, superadmin, "superadmin", http.StatusBadRequest)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "delete", Filter: map[string]string{"rol": "user"}}, admin, "admin", http.StatusBadRequest)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "delete"}, admin, "admin", http.StatusBadRequest)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "delete", IDs: []string{bob, bob}}, admin, "admin", http.StatusBadRequest)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "delete", IDs: []string{bob}}, alice, "user", http.StatusForbidden)
+	r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "delete", IDs: []string{bob}}, "", "", http.StatusUnauthorized)
+
+	// Bulk actions run in the background on request; their operation is polled by its owner or an admin
+	accepted := r.do(post, "/admin/users/bulk", models.BulkUsersRequest{Action: "activate", IDs: []string{bob}}, admin, "admin",
+		http.StatusAccepted, "Prefer", "respond-async")
+	var op models.Operation
+	testutil.DecodeData(r, accepted, &op)
+	r.do(get, "/operations/"+op.ID, nil, admin, "admin", http.StatusOK)
+	r.do(get, "/operations/"+op.ID, nil, superadmin, "superadmin", http.StatusOK)
+	r.do(get, "/operations/"+op.ID, nil, alice, "user", http.StatusNotFound)
+	r.do(get, "/operations/01912f6e-0000-7000-8000-000000000000", nil, admin, "admin", http.StatusNotFound)
+	r.do(get, "/operations/"+op.ID, nil, "", "", http.StatusUnauthorized)
+	r.do(post, "/admin/broadcast", models.BroadcastRequest{Message: "Maintenance at 22:00"}, admin, "admin", http.StatusAccepted)
+	r.do(post, "/admin/broadcast", map[string]string{}, admin, "admin", http.StatusBadRequest)
+	r.do(post, "/admin/broadcast", models.BroadcastRequest{Message: "Hi"}, alice, "user", http.StatusForbidden)
+	r.do(post, "/admin/broadcast", models.BroadcastRequest{Message: "Hi"}, "", "", http.StatusUnauthorized)
+	r.do(get, "/admin/stats?days=7", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/admin/stats?days=0", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/admin/stats", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/admin/stats", nil, "", "", http.StatusUnauthorized)
+	r.do(get, "/admin/audit-logs?method=PATCH&route=/api/v1/admin/users/:id/role", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/admin/audit-logs?from=yesterday", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/admin/audit-logs/export?format=csv&method=PATCH", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/admin/audit-logs/export?to=tomorrow", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/admin/audit-logs/export", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/admin/audit-logs", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/admin/audit-logs", nil, "", "", http.StatusUnauthorized)
+	r.do(get, "/admin/audit-logs/verify", nil, superadmin, "superadmin", http.StatusOK)
+	r.do(get, "/admin/audit-logs/verify", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/admin/audit-logs/verify", nil, "", "", http.StatusUnauthorized)
+	readOnly, writable := true, false
+	r.do(get, "/admin/read-only", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/admin/read-only", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/admin/read-only", nil, "", "", http.StatusUnauthorized)
+	r.do(put, "/admin/read-only", models.SetReadOnlyRequest{Enabled: &readOnly, Reason: "Database upgrade"}, superadmin, "superadmin", http.StatusOK)
+	r.do(get, "/admin/read-only", nil, admin, "admin", http.StatusOK)
+	r.do(put, "/admin/read-only", models.SetReadOnlyRequest{Enabled: &writable}, superadmin, "superadmin", http.StatusOK)
+	r.do(put, "/admin/read-only", map[string]string{}, superadmin, "superadmin", http.StatusBadRequest)
+	r.do(put, "/admin/read-only", models.SetReadOnlyRequest{Enabled: &readOnly}, admin, "admin", http.StatusForbidden)
+	r.do(put, "/admin/read-only", models.SetReadOnlyRequest{Enabled: &readOnly}, "", "", http.StatusUnauthorized)
+
+	// Translation overrides
+	r.do(put, "/admin/translations/de/welcome", models.SetTranslationRequest{Text: "Herzlich willkommen"}, admin, "admin", http.StatusOK)
+	r.do(put, "/admin/translations/de/no.such.key", models.SetTranslationRequest{Text: "Hallo"}, admin, "admin", http.StatusBadRequest)
+	r.do(put, "/admin/translations/de/welcome", models.SetTranslationRequest{Text: "Hallo"}, alice, "user", http.StatusForbidden)
+	r.do(put, "/admin/translations/de/welcome", models.SetTranslationRequest{Text: "Hallo"}, "", "", http.StatusUnauthorized)
+	r.do(get, "/admin/translations/overrides?language=de", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/admin/translations/overrides?language=!", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/admin/translations/overrides", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/admin/translations/overrides", nil, "", "", http.StatusUnauthorized)
+	r.do(get, "/admin/translations/missing", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/admin/translations/missing?language=!", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/admin/translations/missing", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/admin/translations/missing", nil, "", "", http.StatusUnauthorized)
+	r.do(get, "/admin/translations/de/export", nil, admin, "admin", http.StatusOK)
+	r.do(get, "/admin/translations/!/export", nil, admin, "admin", http.StatusBadRequest)
+	r.do(get, "/admin/translations/de/export", nil, alice, "user", http.StatusForbidden)
+	r.do(get, "/admin/translations/de/export", nil, "", "", http.StatusUnauthorized)
+	bundle := map[string]interface{}{"user_deleted": "Benutzer entfernt", "validation": map[string]string{"required": "{field} fehlt"}}
+	r.do(post, "/admin/translations/de/import?replace=true", bundle, admin, "admin", http.StatusOK)
+	r.do(post, "/admin/translations/de/import", map[string]int{"welcome": 1}, admin, "admin", http.StatusBadRequest)
+	r.do(post, "/admin/translations/de/import", bundle, alice, "user", http.StatusForbidden)
+	r.do(post, "/admin/translations/de/import", bundle, "", "", http.StatusUnauthorized)
+	r.do(del, "/admin/translations/de/user_deleted", nil, admin, "admin", http.StatusOK)
+	r.do(del, "/admin/translations/de/user_deleted", nil, admin, "admin", http.StatusNotFound)
+	r.do(del, "/admin/translations/!/user_deleted", nil, admin, "admin", http.StatusBadRequest)
+	r.do(del, "/admin/translations/de/user_deleted", nil, alice, "user", http.StatusForbidden)
+	r.do(del, "/admin/translations/de/user_deleted", nil, "", "", http.StatusUnauthorized)
+
+	// Posts, which only their owner or an admin may change
+	created := r.do(post, "/posts", models.CreatePostRequest{Title: "Hello", Body: "First post"}, alice, "user", http.StatusCreated)
+	var item models.PostInfo
+	testutil.DecodeData(r, created, &item)
+	r.do(post, "/posts", map[string]string{}, alice, "user", http.StatusBadRequest)
+	r.do(post, "/posts", models.CreatePostRequest{Title: "Hello", Body: "First post"}, "", "", http.StatusUnauthorized)
+	r.do(get, "/posts?owner_id=me", nil, alice, "user", http.StatusOK)
+	r.do(get, "/posts?search="+strings.Repeat("x", 201), nil, alice, "user", http.StatusBadRequest)
+	r.do(get, "/posts", nil, "", "", http.StatusUnauthorized)
+	r.do(get, "/posts/"+item.ID, nil, bob, "user", http.StatusOK)
+	r.do(get, "/posts/999999", nil, bob, "user", http.StatusNotFound)
+	r.do(get, "/posts/"+item.ID, nil, "", "", http.StatusUnauthorized)
+	title := "Hello again"
+	r.do(put, "/posts/"+item.ID, models.UpdatePostRequest{Title: &title}, alice, "user", http.StatusOK)
+	r.do(put, "/posts/"+item.ID, models.UpdatePostRequest{Title: &title}, bob, "user", http.StatusForbidden)
+	r.do(put, "/posts/"+item.ID, map[string]string{"title": strings.Repeat("x", 201)}, alice, "user", http.StatusBadRequest)
+	r.do(put, "/posts/999999", models.UpdatePostRequest{Title: &title}, alice, "user", http.StatusNotFound)
+	r.do(put, "/posts/"+item.ID, models.UpdatePostRequest{Title: &title}, "", "", http.StatusUnauthorized)
+	r.do(del, "/posts/"+item.ID, nil, bob, "user", http.StatusForbidden)
+	r.do(del, "/posts/"+item.ID, nil, "", "", http.StatusUnauthorized)
+	r.do(del, "/posts/"+item.ID, nil, alice, "user", http.StatusOK)
+	r.do(del, "/posts/"+item.ID, nil, alice, "user", http.StatusNotFound)
+}
+
+// webhookSecret signs the deliveries of the example provider of runWebhooks
+const webhookSecret = "contract-webhook-secret"
+
+// runWebhooks delivers to an example provider whose handler fails the failing events until fixed, so a
+// delivery becomes a dead letter that an admin then replays
+func runWebhooks(r *runner, users *testutil.UserRepository, receiver *webhooks.Receiver) {
+	admin := users.Add(testutil.NewUser().Admin().Model()).ID
+	alice := users.Add(testutil.NewUser().Model()).ID
+	provider, err := webhooks.NewStandardProvider(webhookSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fixed := false
+	receiver.Register("example", provider)
+	receiver.Handle("example", func(ctx context.Context, delivery webhooks.Delivery) error {
+		if delivery.Type == "example.failing" && !fixed {
+			return errors.New("the example handler failed")
+		}
+		return nil
+	})
+
+	ok := []byte(`{"type":"example.created"}`)
+	failing := []byte(`{"type":"example.failing"}`)
+	r.do(http.MethodPost, "/webhooks/example", ok, "", "", http.StatusOK, signWebhook("msg_1", ok)...)
+	r.do(http.MethodPost, "/webhooks/example", ok, "", "", http.StatusOK, signWebhook("msg_1", ok)...)
+	r.do(http.MethodPost, "/webhooks/example", ok, "", "", http.StatusBadRequest, signWebhook("msg_2", failing)...)
+	r.do(http.MethodPost, "/webhooks/unknown", ok, "", "", http.StatusNotFound, signWebhook("msg_3", ok)...)
+	r.do(http.MethodPost, "/webhooks/example", failing, "", "", http.StatusInternalServerError, signWebhook("msg_4", failing)...)
+	r.do(http.MethodPost, "/webhooks/example", failing, "", "", http.StatusOK, signWebhook("msg_4", failing)...)
+
+	// The second failure made the delivery a dead letter
+	listed := r.do(http.MethodGet, "/admin/webhooks?status=dead", nil, admin, "admin", http.StatusOK)
+	var page struct {
+		Data []models.WebhookEvent `json:"data"`
+	}
+	testutil.DecodeData(r, listed, &page)
+	if len(page.Data) != 1 {
+		log.Fatalf("want 1 dead letter, got %d", len(page.Data))
+	}
+	dead := page.Data[0].ID
+	r.do(http.MethodGet, "/admin/webhooks?status=gone", nil, admin, "admin", http.StatusBadRequest)
+	r.do(http.MethodGet, "/admin/webhooks", nil, alice, "user", http.StatusForbidden)
+	r.do(http.MethodGet, "/admin/webhooks", nil, "", "", http.StatusUnauthorized)
+	r.do(http.MethodPost, "/admin/webhooks/"+dead+"/replay", nil, admin, "admin", http.StatusOK)
+	fixed = true
+	r.do(http.MethodPost, "/admin/webhooks/"+dead+"/replay", nil, admin, "admin", http.StatusOK)
+	r.do(http.MethodPost, "/admin/webhooks/"+dead+"/replay", nil, admin, "admin", http.StatusConflict)
+	r.do(http.MethodPost, "/admin/webhooks/missing/replay", nil, admin, "admin", http.StatusNotFound)
+	r.do(http.MethodPost, "/admin/webhooks/"+dead+"/replay", nil, alice, "user", http.StatusForbidden)
+	r.do(http.MethodPost, "/admin/webhooks/"+dead+"/replay", nil, "", "", http.StatusUnauthorized)
+}
+
+// signWebhook returns the Standard Webhooks headers of a delivery of the example provider
+func signWebhook(id string, payload []byte) []string {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(webhookSecret))
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(payload)
+	signature := "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return []string{"webhook-id", id, "webhook-timestamp", timestamp, "webhook-signature", signature}
+}
@@ -0,0 +1,232 @@
+// Command gen scaffolds code in the style of the existing user endpoints. Run it from the repository root.
+//
+// Usage:
+//
+//	gen resource NAME    write the model, store, handler, routes, handler tests, and migration for NAME
+//
+// NAME is a singular CamelCase noun such as Post or BlogPost. Existing files are left alone unless -force
+// is given; -dry-run only lists the files. The generated code compiles on its own, and the command prints
+// the remaining wiring steps.
+package main
+
+import (
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"go-backend-template/migrate"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// resourceName restricts names to singular CamelCase identifiers
+var resourceName = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// resource holds the spellings of a resource name used by the templates
+type resource struct {
+	Name        string // BlogPost
+	Plural      string // BlogPosts
+	VarPlural   string // blogPosts
+	Package     string // blogposts
+	Table       string // blog_posts
+	Route       string // /blog-posts
+	Tag         string // blog-posts
+	Human       string // blog post
+	HumanPlural string // blog posts
+	Title       string // Blog post
+	Article     string // a
+	Migration   string // 000005_create_blog_posts
+}
+
+// output is a generated file and the template it comes from
+type output struct {
+	template string
+	path     string
+}
+
+func main() {
+	log.SetFlags(0)
+	force := flag.Bool("force", false, "overwrite existing files")
+	dryRun := flag.Bool("dry-run", false, "list the files without writing them")
+	migrationsDir := flag.String("migrations", "migrations", "directory of the migration files")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: gen [-force] [-dry-run] resource NAME")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 || flag.Arg(0) != "resource" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if !resourceName.MatchString(flag.Arg(1)) {
+		log.Fatal("NAME must be a singular CamelCase noun such as Post or BlogPost")
+	}
+
+	existing, err := migrate.Load(os.DirFS(*migrationsDir))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var version uint = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+	res := newResource(flag.Arg(1), version)
+
+	snake := toSnake(res.Name)
+	outputs := []output{
+		{"model.go.tmpl", filepath.Join("models", snake+".go")},
+		{"store.go.tmpl", filepath.Join(res.Package, "store.go")},
+		{"handler.go.tmpl", filepath.Join("handlers", snake+".go")},
+		{"handler_test.go.tmpl", filepath.Join("handlers", snake+"_test.go")},
+		{"routes.go.tmpl", filepath.Join("routes", snake+".go")},
+		{"migration.up.sql.tmpl", filepath.Join(*migrationsDir, res.Migration+".up.sql")},
+		{"migration.down.sql.tmpl", filepath.Join(*migrationsDir, res.Migration+".down.sql")},
+	}
+
+	if !*force {
+		for _, out := range outputs {
+			if _, err := os.Stat(out.path); err == nil {
+				log.Fatalf("%s already exists; use -force to overwrite it", out.path)
+			}
+		}
+	}
+
+	for _, out := range outputs {
+		content, err := render(out.template, res)
+		if err != nil {
+			log.Fatalf("failed to render %s: %v", out.path, err)
+		}
+		if *dryRun {
+			log.Printf("would write %s", out.path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(out.path), 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(out.path, content, 0o644); err != nil {
+			log.Fatalf("failed to write %s: %v", out.path, err)
+		}
+		log.Printf("wrote %s", out.path)
+	}
+
+	printNextSteps(res)
+}
+
+// newResource derives the spellings of name
+func newResource(name string, version uint) resource {
+	words := splitWords(name)
+	plural := pluralize(name)
+	pluralWords := splitWords(plural)
+
+	human := strings.ToLower(strings.Join(words, " "))
+	article := "a"
+	if strings.ContainsRune("aeiou", rune(human[0])) {
+		article = "an"
+	}
+
+	return resource{
+		Name:        name,
+		Plural:      plural,
+		VarPlural:   strings.ToLower(plural[:1]) + plural[1:],
+		Package:     strings.ToLower(plural),
+		Table:       toSnake(plural),
+		Route:       "/" + strings.ToLower(strings.Join(pluralWords, "-")),
+		Tag:         strings.ToLower(strings.Join(pluralWords, "-")),
+		Human:       human,
+		HumanPlural: strings.ToLower(strings.Join(pluralWords, " ")),
+		Title:       strings.ToUpper(human[:1]) + human[1:],
+		Article:     article,
+		Migration:   fmt.Sprintf("%06d_create_%s", version, toSnake(plural)),
+	}
+}
+
+// splitWords splits a CamelCase name at its capitals, keeping acronyms together
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		upper := unicode.IsUpper(runes[i])
+		boundary := upper && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+		if boundary {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+// toSnake converts a CamelCase name to snake_case
+func toSnake(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "_"))
+}
+
+// pluralize applies the regular English plural rules, which cover typical resource names
+func pluralize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
+
+// render executes a template and gofmts Go output
+func render(name string, res resource) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, "templates/"+name)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, res); err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(name, ".go.tmpl") {
+		return format.Source(buf.Bytes())
+	}
+	return buf.Bytes(), nil
+}
+
+// printNextSteps lists the wiring the generator leaves to the developer
+func printNextSteps(res resource) {
+	store := strings.ToLower(res.Name[:1]) + res.Name[1:] + "Store"
+	fmt.Printf(`
+Next steps:
+
+1. Add a %[3]s field to app.Handlers and create the store and handler in initHandlers of app/app.go:
+
+	var %[1]s %[2]s.Store = %[2]s.NewMemoryStore()
+	if a.PostgresDB != nil {
+		%[1]s = %[2]s.NewPostgresStore(a.PostgresDB)
+	} else if a.MongoDB != nil {
+		%[1]s = %[2]s.NewMongoStore(a.MongoDB)
+	}
+	a.Handlers.%[3]s = handlers.New%[3]sHandler(%[1]s, logger, localizer)
+
+2. Mount its routes by adding the registrar to Handlers.Registrars:
+
+	routes.%[4]sRoutes(h.%[3]s),
+
+3. Add &models.%[3]s{} to sqliteModels in database/database.go.
+
+4. Regenerate the API docs and clients with make sdk, then apply the migration with make db-migrate-up.
+
+5. Run go test ./handlers/ and adjust the fields in models/%[5]s.go, the store, and the migration to your resource.
+`, store, res.Package, res.Name, res.Plural, toSnake(res.Name))
+}
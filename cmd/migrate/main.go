@@ -0,0 +1,157 @@
+// Command migrate manages the PostgreSQL schema with the migrations in migrations/. It reads the same
+// environment and config files as the server.
+//
+// Usage:
+//
+//	migrate up                apply all pending migrations
+//	migrate down [N]          roll back the last N migrations (default 1, "all" for every one)
+//	migrate status            show the applied version and pending migrations
+//	migrate force VERSION     record VERSION as applied without running anything, to recover a dirty schema
+//	migrate create NAME       write empty up and down files for the next version
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"go-backend-template/config"
+	"go-backend-template/database"
+	"go-backend-template/migrate"
+	"go-backend-template/migrations"
+	"go-backend-template/utils"
+)
+
+// migrationName restricts new migration names to what the loader accepts
+var migrationName = regexp.MustCompile(`^\w+$`)
+
+func main() {
+	dir := flag.String("dir", "migrations", "directory new migrations are created in")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: migrate [-dir migrations] up | down [N|all] | status | force VERSION | create NAME")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	command, args := flag.Arg(0), flag.Args()[1:]
+	if command == "create" {
+		if len(args) != 1 || !migrationName.MatchString(args[0]) {
+			log.Fatal("create needs a NAME of letters, digits, and underscores")
+		}
+		create(*dir, args[0])
+		return
+	}
+
+	_ = godotenv.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+
+	logger, err := utils.NewLogger(utils.LoggerOptions{Level: cfg.LogLevel, Format: "text", Output: "stderr"})
+	if err != nil {
+		log.Fatalf("failed to initialize logger: %v", err)
+	}
+
+	postgresDB, err := database.NewPostgresDB(&cfg.PostgresDB, database.NewGormLogger(logger, cfg.LogLevel))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer postgresDB.Close()
+
+	migrator, err := migrate.New(postgresDB, migrations.FS)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("applied %d migrations", applied)
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			if args[0] == "all" {
+				steps = 0
+			} else if steps, err = strconv.Atoi(args[0]); err != nil || steps < 1 {
+				log.Fatalf("invalid number of steps %q", args[0])
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("rolled back %d migrations", rolledBack)
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("version %d of %d", status.Version, status.Latest)
+		if status.Dirty {
+			fmt.Print(" (dirty)")
+		}
+		fmt.Println()
+		for _, migration := range status.Migrations {
+			state := "pending"
+			if migration.Applied {
+				state = "applied"
+			}
+			fmt.Printf("  %06d_%s\t%s\n", migration.Version, migration.Name, state)
+		}
+	case "force":
+		if len(args) != 1 {
+			log.Fatal("force needs a VERSION")
+		}
+		version, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			log.Fatalf("invalid version %q", args[0])
+		}
+		if err := migrator.Force(ctx, uint(version)); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("schema version forced to %d", version)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// create writes the up and down files for the version after the newest one in dir
+func create(dir, name string) {
+	existing, err := migrate.Load(os.DirFS(dir))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var version uint = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", version, name, direction))
+		header := fmt.Sprintf("-- %06d_%s (%s)\n", version, name, direction)
+		if err := os.WriteFile(path, []byte(header), 0o644); err != nil {
+			log.Fatalf("failed to write %s: %v", path, err)
+		}
+		log.Printf("wrote %s", path)
+	}
+}
@@ -0,0 +1,37 @@
+// Command mongoindexes creates the MongoDB indexes the API relies on, for deployments that run with
+// MONGODB_ENSURE_INDEXES=false. It reads the same environment and config files as the server.
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"go-backend-template/config"
+	"go-backend-template/database"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+
+	mongoDB, err := database.NewMongoDB(&cfg.MongoDB)
+	if err != nil {
+		log.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+	defer mongoDB.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := mongoDB.EnsureIndexes(ctx); err != nil {
+		log.Fatalf("failed to create indexes: %v", err)
+	}
+	log.Printf("MongoDB indexes are up to date on %s", cfg.MongoDB.Database)
+}
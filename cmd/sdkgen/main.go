@@ -0,0 +1,49 @@
+// Command sdkgen writes the typed Go and TypeScript API clients from the generated OpenAPI document.
+// Run it after regenerating the swag docs (make sdk does both).
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	_ "go-backend-template/docs"
+	"go-backend-template/openapi"
+	"go-backend-template/sdkgen"
+)
+
+func main() {
+	goOut := flag.String("go", "sdk/client/client.go", "output file for the Go client")
+	goPackage := flag.String("package", "client", "package name of the Go client")
+	tsOut := flag.String("ts", "sdk/typescript/client.ts", "output file for the TypeScript client")
+	flag.Parse()
+
+	document, err := openapi.NewSpec().Document()
+	if err != nil {
+		log.Fatalf("failed to build OpenAPI document: %v", err)
+	}
+
+	api, err := sdkgen.Load(document)
+	if err != nil {
+		log.Fatalf("failed to read OpenAPI document: %v", err)
+	}
+
+	goSource, err := sdkgen.GenerateGo(api, *goPackage)
+	if err != nil {
+		log.Fatal(err)
+	}
+	write(*goOut, goSource)
+	write(*tsOut, sdkgen.GenerateTypeScript(api))
+}
+
+// write creates the output directory and writes data to path
+func write(path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		log.Fatalf("failed to write %s: %v", path, err)
+	}
+	log.Printf("wrote %s", path)
+}
@@ -1,80 +1,835 @@
 package config
 
 import (
-	"os"
 	"strconv"
 	"strings"
+	"time"
+)
+
+// DefaultJWTSecret is the placeholder secret used when JWT_SECRET is unset
+const DefaultJWTSecret = "your-secret-key-change-this-in-production"
+
+// Run modes select what a process of the single binary does
+const (
+	// ModeAll serves the API and runs the workers and scheduled jobs in one process
+	ModeAll = "all"
+	// ModeServe serves the HTTP API only
+	ModeServe = "serve"
+	// ModeWorker runs the background workers and serves health checks
+	ModeWorker = "worker"
+	// ModeScheduler runs the scheduled jobs and serves health checks
+	ModeScheduler = "scheduler"
+	// ModeMigrate applies the PostgreSQL migrations and MongoDB indexes, then exits
+	ModeMigrate = "migrate"
+)
+
+const (
+	defaultMongoPassword    = "4jClkoZfth8Jq4lB"
+	defaultPostgresPassword = "password"
 )
 
 type Config struct {
 	Environment     string
+	ServiceName     string
+	Mode            string
 	Port            string
+	TLS             TLSConfig
+	Proxy           ProxyConfig
+	Router          RouterConfig
+	Limits          LimitsConfig
+	Timeouts        TimeoutsConfig
+	Concurrency     ConcurrencyConfig
+	Idempotency     IdempotencyConfig
+	ErrorFormat     string
+	APIDocs         bool
+	APIVersions     APIVersionsConfig
+	Realtime        RealtimeConfig
+	Billing         BillingConfig
+	Usage           UsageConfig
+	Retention       RetentionConfig
+	UserMetadata    UserMetadataConfig
+	AdminStats      AdminStatsConfig
+	Bootstrap       BootstrapConfig
+	Metrics         MetricsConfig
+	Profiling       ProfilingConfig
 	LogLevel        string
+	Log             LogConfig
+	SecurityLog     SecurityLogConfig
+	AuditLog        AuditLogConfig
+	Backup          BackupConfig
+	Operations      OperationsConfig
+	ReadOnly        ReadOnlyConfig
+	Webhooks        WebhooksConfig
+	Analytics       AnalyticsConfig
+	Secrets         SecretsConfig
 	DefaultLanguage string
+	Languages       []string
+	Locales         LocalesConfig
 	JWTSecret       string
+	Auth            AuthConfig
+	Sessions        SessionsConfig
+	LoginThrottle   LoginThrottleConfig
+	BotDetection    BotDetectionConfig
+	PasswordBreach  PasswordBreachConfig
+	Email           EmailConfig
+	OAuth           OAuthConfig
 	MongoDB         MongoDBConfig
 	PostgresDB      PostgresDBConfig
+	SQLite          SQLiteConfig
+	DualWrite       bool
+	DBConnect       DBConnectConfig
+	QueryLog        QueryLogConfig
+	Health          HealthConfig
+	HTTPClient      HTTPClientConfig
+
+	// parseErrors holds values that could not be parsed; reported by Validate
+	parseErrors []error
 }
 
-type MongoDBConfig struct {
+type TLSConfig struct {
+	Enabled         bool
+	CertFile        string
+	KeyFile         string
+	Autocert        bool
+	AutocertDomains []string
+	AutocertEmail   string
+	AutocertCache   string
+	RedirectHTTP    bool
+	HTTPPort        string
+	HTTP2           bool
+}
+
+type ProxyConfig struct {
+	TrustedProxies  []string
+	TrustedPlatform string
+	RemoteIPHeaders []string
+}
+
+type RouterConfig struct {
+	Mode             string
+	RemoveExtraSlash bool
+	MethodNotAllowed bool
+}
+
+type LimitsConfig struct {
+	MaxBodySize        int64
+	MaxUploadSize      int64
+	MaxMultipartMemory int64
+}
+
+type TimeoutsConfig struct {
+	Request time.Duration
+	Routes  []string
+}
+
+type ConcurrencyConfig struct {
+	MaxInFlight    int
+	Reserved       int
+	PriorityRoutes []string
+	PriorityRoles  []string
+	Groups         []string
+	RetryAfter     time.Duration
+}
+
+type APIVersionsConfig struct {
+	Default    string
+	Deprecated []string
+}
+
+type IdempotencyConfig struct {
+	Store string
+	TTL   time.Duration
+}
+
+type RealtimeConfig struct {
+	AllowedOrigins []string
+	BufferSize     int
+	HistorySize    int
+	PingInterval   time.Duration
+	Heartbeat      time.Duration
+}
+
+type BillingConfig struct {
+	Enabled             bool
+	StripeSecretKey     string
+	StripeWebhookSecret string
+	SuccessURL          string
+	CancelURL           string
+	Plans               []string
+}
+
+type UsageConfig struct {
+	Enabled bool
+	Store   string
+	Quotas  []string
+}
+
+type RetentionConfig struct {
+	Interval        time.Duration
+	DryRun          bool
+	DeletedUsers    time.Duration
+	AuditLogs       time.Duration
+	LoginHistory    time.Duration
+	AnalyticsEvents time.Duration
+	WebhookEvents   time.Duration
+	Operations      time.Duration
+}
+
+type UserMetadataConfig struct {
+	Schema []string
+}
+
+type AdminStatsConfig struct {
+	CacheTTL time.Duration
+}
+
+type BootstrapConfig struct {
+	AdminEmail    string
+	AdminUsername string
+	AdminPassword string
+	SetupToken    bool
+}
+
+type MetricsConfig struct {
+	Enabled bool
+	Token   string
+}
+
+type ProfilingConfig struct {
+	Enabled bool
+	Token   string
+}
+
+type LogConfig struct {
+	Format     string
+	Output     string
+	FilePath   string
+	MaxSizeMB  int
+	MaxAgeDays int
+	MaxBackups int
+}
+
+type SecurityLogConfig struct {
+	Sink          string
+	FilePath      string
+	MaxSizeMB     int
+	MaxAgeDays    int
+	MaxBackups    int
+	SyslogNetwork string
+	SyslogAddress string
+	SyslogTag     string
+	HTTPURL       string
+	HTTPToken     string
+}
+
+type AuditLogConfig struct {
+	Enabled   bool
+	HashChain bool
+	Forward   bool
+}
+
+type BackupConfig struct {
+	EncryptionKey string
+	Endpoints     bool
+}
+
+type OperationsConfig struct {
+	Workers      int
+	PollInterval time.Duration
+	StaleAfter   time.Duration
+	MaxAttempts  int
+}
+
+type ReadOnlyConfig struct {
+	Enabled      bool
+	Message      string
+	ExemptRoutes []string
+}
+
+type WebhooksConfig struct {
+	Enabled           bool
+	MaxAttempts       int
+	SendGridPublicKey string
+	Secrets           []string
+}
+
+type AnalyticsConfig struct {
+	Sink            string
+	BatchSize       int
+	FlushInterval   time.Duration
+	QueueSize       int
+	SegmentEndpoint string
+	SegmentWriteKey string
+	PostHogHost     string
+	PostHogAPIKey   string
+}
+
+type AuthConfig struct {
+	TokenDelivery  string
+	CookieName     string
+	CookieDomain   string
+	CookiePath     string
+	CookieSecure   bool
+	CookieSameSite string
+	// RefreshCookieName is the cookie the refresh token is delivered in with the auth cookie
+	RefreshCookieName string
+}
+
+// SessionsConfig sets the lifetimes of the refresh token sessions. A session ends once it goes unused for
+// its idle timeout, each refresh sliding it forward, and at the latest its maximum lifetime after login.
+type SessionsConfig struct {
+	Enabled               bool
+	IdleTimeout           time.Duration
+	MaxLifetime           time.Duration
+	RememberMeIdleTimeout time.Duration
+	RememberMeMaxLifetime time.Duration
+}
+
+type LoginThrottleConfig struct {
+	Enabled       bool
+	FreeAttempts  int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	BlockAfter    int
+	BlockDuration time.Duration
+	Window        time.Duration
+}
+
+type BotDetectionConfig struct {
+	Enabled           bool
+	Routes            []string
+	HoneypotField     string
+	HeaderChecks      bool
+	BlockedUserAgents []string
+	ChallengeRoutes   []string
+	ChallengeSecret   string
+	ChallengeMinAge   time.Duration
+	ChallengeTTL      time.Duration
+}
+
+type PasswordBreachConfig struct {
 	Enabled  bool
-	URI      string
-	Database string
-	Username string
-	Password string
-	Host     string
-	Port     string
+	APIURL   string
+	Timeout  time.Duration
+	FailOpen bool
+}
+
+type EmailConfig struct {
+	BlockedDomains []string
+	BlocklistFile  string
+	CheckMX        bool
+	MXTimeout      time.Duration
+}
+
+type OAuthConfig struct {
+	Enabled        bool
+	Issuer         string
+	SigningKeyFile string
+	ConsentURL     string
+	CodeTTL        time.Duration
+	AccessTokenTTL time.Duration
+	ServiceScopes  []string
+
+	DeviceVerificationURL string
+	DeviceCodeTTL         time.Duration
+	DevicePollInterval    time.Duration
+}
+
+type SecretsConfig struct {
+	Provider            string
+	RefreshInterval     time.Duration
+	VaultAddress        string
+	VaultToken          string
+	VaultNamespace      string
+	AWSRegion           string
+	GCPProject          string
+	JWTSecretRef        string
+	MongoPasswordRef    string
+	MongoURIRef         string
+	PostgresPasswordRef string
+}
+
+type MongoDBConfig struct {
+	Enabled         bool
+	URI             string
+	Database        string
+	Username        string
+	Password        string
+	Host            string
+	Port            string
+	EnsureIndexes   bool
+	QueryTimeout    time.Duration
+	MaxPoolSize     int
+	MinPoolSize     int
+	MaxConnIdleTime time.Duration
 }
 
 type PostgresDBConfig struct {
-	Enabled  bool
-	Host     string
-	Port     string
-	Username string
-	Password string
-	Database string
-	SSLMode  string
-}
-
-func Load() *Config {
-	return &Config{
-		Environment:     getEnv("ENVIRONMENT", "development"),
-		Port:            getEnv("PORT", "8080"),
-		LogLevel:        getEnv("LOG_LEVEL", "info"),
-		DefaultLanguage: getEnv("DEFAULT_LANGUAGE", "en"),
-		JWTSecret:       getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+	Enabled         bool
+	Host            string
+	Port            string
+	Username        string
+	Password        string
+	Database        string
+	SSLMode         string
+	MigrateOnStart  bool
+	QueryTimeout    time.Duration
+	ReplicaDSNs     []string
+	UserIDType      string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+type DBConnectConfig struct {
+	Attempts      int
+	InitialDelay  time.Duration
+	MaxDelay      time.Duration
+	CheckInterval time.Duration
+}
+
+type QueryLogConfig struct {
+	SlowThreshold  time.Duration
+	WarnPerRequest int
+	TagRequests    bool
+}
+
+type LocalesConfig struct {
+	Dir            string
+	ReloadInterval time.Duration
+	SyncInterval   time.Duration
+}
+
+type HealthConfig struct {
+	Timeout         time.Duration
+	DegradedLatency time.Duration
+}
+
+type HTTPClientConfig struct {
+	Timeout          time.Duration
+	MaxRetries       int
+	RetryDelay       time.Duration
+	MaxRetryDelay    time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+type SQLiteConfig struct {
+	Enabled bool
+	Path    string
+}
+
+// Load builds the configuration from defaults, config files, and environment variables.
+//
+// Precedence (highest first):
+//  1. Environment variables
+//  2. Per-environment file (config.<ENVIRONMENT>.yaml|yml|toml)
+//  3. Base file (CONFIG_FILE, or config.yaml|yml|toml in CONFIG_DIR)
+//  4. Built-in defaults
+func Load() (*Config, error) {
+	src, err := loadFileValues()
+	if err != nil {
+		return nil, err
+	}
+
+	environment := src.getEnv("ENVIRONMENT", "development")
+
+	// Translation files in LOCALES_DIR are reloaded when they change during development
+	var localesReload time.Duration
+	if environment == "development" {
+		localesReload = 2 * time.Second
+	}
+
+	// gin prints its routes and debug warnings outside production
+	ginMode := "debug"
+	if environment == "production" {
+		ginMode = "release"
+	}
+
+	cfg := &Config{
+		Environment: environment,
+		ServiceName: src.getEnv("SERVICE_NAME", "backend-template"),
+		Mode:        src.getEnv("RUN_MODE", ModeAll),
+		Port:        src.getEnv("PORT", "8080"),
+		TLS: TLSConfig{
+			Enabled:         src.getBoolEnv("TLS_ENABLED", false),
+			CertFile:        src.getEnv("TLS_CERT_FILE", ""),
+			KeyFile:         src.getEnv("TLS_KEY_FILE", ""),
+			Autocert:        src.getBoolEnv("TLS_AUTOCERT", false),
+			AutocertDomains: src.getListEnv("TLS_AUTOCERT_DOMAINS", nil),
+			AutocertEmail:   src.getEnv("TLS_AUTOCERT_EMAIL", ""),
+			AutocertCache:   src.getEnv("TLS_AUTOCERT_CACHE_DIR", "certs"),
+			RedirectHTTP:    src.getBoolEnv("TLS_REDIRECT_HTTP", true),
+			HTTPPort:        src.getEnv("TLS_HTTP_PORT", "80"),
+			HTTP2:           src.getBoolEnv("HTTP2_ENABLED", true),
+		},
+		Proxy: ProxyConfig{
+			TrustedProxies:  src.getListEnv("TRUSTED_PROXIES", nil),
+			TrustedPlatform: src.getEnv("TRUSTED_PLATFORM", ""),
+			RemoteIPHeaders: src.getListEnv("REMOTE_IP_HEADERS", []string{"X-Forwarded-For", "X-Real-IP"}),
+		},
+		Router: RouterConfig{
+			Mode:             src.getEnv("GIN_MODE", ginMode),
+			RemoveExtraSlash: src.getBoolEnv("ROUTER_REMOVE_EXTRA_SLASH", true),
+			MethodNotAllowed: src.getBoolEnv("ROUTER_METHOD_NOT_ALLOWED", true),
+		},
+		Limits: LimitsConfig{
+			MaxBodySize:        src.getByteSizeEnv("MAX_BODY_SIZE", 1<<20),
+			MaxUploadSize:      src.getByteSizeEnv("MAX_FILE_SIZE", 10<<20),
+			MaxMultipartMemory: src.getByteSizeEnv("MAX_MULTIPART_MEMORY", 8<<20),
+		},
+		Timeouts: TimeoutsConfig{
+			Request: src.getDurationEnv("REQUEST_TIMEOUT", 30*time.Second),
+			Routes:  src.getListEnv("ROUTE_TIMEOUTS", nil),
+		},
+		Concurrency: ConcurrencyConfig{
+			MaxInFlight:    src.getIntEnv("MAX_IN_FLIGHT_REQUESTS", 0),
+			Reserved:       src.getIntEnv("RESERVED_IN_FLIGHT_REQUESTS", 0),
+			PriorityRoutes: src.getListEnv("PRIORITY_ROUTES", nil),
+			PriorityRoles:  src.getListEnv("PRIORITY_ROLES", nil),
+			Groups:         src.getListEnv("GROUP_MAX_IN_FLIGHT_REQUESTS", nil),
+			RetryAfter:     src.getDurationEnv("OVERLOAD_RETRY_AFTER", time.Second),
+		},
+		Idempotency: IdempotencyConfig{
+			Store: src.getEnv("IDEMPOTENCY_STORE", "database"),
+			TTL:   src.getDurationEnv("IDEMPOTENCY_TTL", 24*time.Hour),
+		},
+		ErrorFormat: src.getEnv("ERROR_FORMAT", "default"),
+		APIDocs:     src.getBoolEnv("API_DOCS_ENABLED", environment != "production"),
+		APIVersions: APIVersionsConfig{
+			Default:    src.getEnv("API_DEFAULT_VERSION", "v1"),
+			Deprecated: src.getListEnv("API_DEPRECATED_VERSIONS", nil),
+		},
+		Realtime: RealtimeConfig{
+			AllowedOrigins: src.getListEnv("WS_ALLOWED_ORIGINS", nil),
+			BufferSize:     src.getIntEnv("REALTIME_BUFFER_SIZE", 64),
+			HistorySize:    src.getIntEnv("REALTIME_HISTORY_SIZE", 256),
+			PingInterval:   src.getDurationEnv("WS_PING_INTERVAL", 30*time.Second),
+			Heartbeat:      src.getDurationEnv("SSE_HEARTBEAT_INTERVAL", 15*time.Second),
+		},
+		Billing: BillingConfig{
+			Enabled:             src.getBoolEnv("BILLING_ENABLED", false),
+			StripeSecretKey:     src.getEnv("STRIPE_SECRET_KEY", ""),
+			StripeWebhookSecret: src.getEnv("STRIPE_WEBHOOK_SECRET", ""),
+			SuccessURL:          src.getEnv("BILLING_SUCCESS_URL", ""),
+			CancelURL:           src.getEnv("BILLING_CANCEL_URL", ""),
+			Plans:               src.getListEnv("BILLING_PLANS", nil),
+		},
+		Usage: UsageConfig{
+			Enabled: src.getBoolEnv("USAGE_ENABLED", false),
+			Store:   src.getEnv("USAGE_STORE", "database"),
+			Quotas:  src.getListEnv("USAGE_QUOTAS", []string{"free=10000"}),
+		},
+		Retention: RetentionConfig{
+			Interval:        src.getDurationEnv("RETENTION_INTERVAL", src.getDurationEnv("USER_PURGE_INTERVAL", time.Hour)),
+			DryRun:          src.getBoolEnv("RETENTION_DRY_RUN", false),
+			DeletedUsers:    src.getDurationEnv("USER_PURGE_AFTER", 30*24*time.Hour),
+			AuditLogs:       src.getDurationEnv("RETENTION_AUDIT_LOGS", 0),
+			LoginHistory:    src.getDurationEnv("RETENTION_LOGIN_HISTORY", 0),
+			AnalyticsEvents: src.getDurationEnv("RETENTION_ANALYTICS_EVENTS", 0),
+			WebhookEvents:   src.getDurationEnv("RETENTION_WEBHOOK_EVENTS", 30*24*time.Hour),
+			Operations:      src.getDurationEnv("RETENTION_OPERATIONS", 7*24*time.Hour),
+		},
+		UserMetadata: UserMetadataConfig{
+			Schema: src.getListEnv("USER_METADATA_SCHEMA", nil),
+		},
+		AdminStats: AdminStatsConfig{
+			CacheTTL: src.getDurationEnv("ADMIN_STATS_CACHE_TTL", time.Minute),
+		},
+		Bootstrap: BootstrapConfig{
+			AdminEmail:    src.getEnv("BOOTSTRAP_ADMIN_EMAIL", ""),
+			AdminUsername: src.getEnv("BOOTSTRAP_ADMIN_USERNAME", "admin"),
+			AdminPassword: src.getEnv("BOOTSTRAP_ADMIN_PASSWORD", ""),
+			SetupToken:    src.getBoolEnv("BOOTSTRAP_SETUP_TOKEN", true),
+		},
+		Metrics: MetricsConfig{
+			Enabled: src.getBoolEnv("METRICS_ENABLED", true),
+			Token:   src.getEnv("METRICS_TOKEN", ""),
+		},
+		Profiling: ProfilingConfig{
+			Enabled: src.getBoolEnv("PPROF_ENABLED", false),
+			Token:   src.getEnv("PPROF_TOKEN", ""),
+		},
+		LogLevel: src.getEnv("LOG_LEVEL", "info"),
+		Log: LogConfig{
+			Format:     src.getEnv("LOG_FORMAT", "json"),
+			Output:     src.getEnv("LOG_OUTPUT", "stdout"),
+			FilePath:   src.getEnv("LOG_FILE_PATH", "logs/app.log"),
+			MaxSizeMB:  src.getIntEnv("LOG_MAX_SIZE_MB", 100),
+			MaxAgeDays: src.getIntEnv("LOG_MAX_AGE_DAYS", 7),
+			MaxBackups: src.getIntEnv("LOG_MAX_BACKUPS", 5),
+		},
+		SecurityLog: SecurityLogConfig{
+			Sink:          src.getEnv("SECURITY_LOG_SINK", "none"),
+			FilePath:      src.getEnv("SECURITY_LOG_FILE_PATH", "logs/security.log"),
+			MaxSizeMB:     src.getIntEnv("SECURITY_LOG_MAX_SIZE_MB", 100),
+			MaxAgeDays:    src.getIntEnv("SECURITY_LOG_MAX_AGE_DAYS", 90),
+			MaxBackups:    src.getIntEnv("SECURITY_LOG_MAX_BACKUPS", 30),
+			SyslogNetwork: src.getEnv("SECURITY_LOG_SYSLOG_NETWORK", ""),
+			SyslogAddress: src.getEnv("SECURITY_LOG_SYSLOG_ADDRESS", ""),
+			SyslogTag:     src.getEnv("SECURITY_LOG_SYSLOG_TAG", "backend-template"),
+			HTTPURL:       src.getEnv("SECURITY_LOG_HTTP_URL", ""),
+			HTTPToken:     src.getEnv("SECURITY_LOG_HTTP_TOKEN", ""),
+		},
+		AuditLog: AuditLogConfig{
+			Enabled:   src.getBoolEnv("AUDIT_LOG_ENABLED", true),
+			HashChain: src.getBoolEnv("AUDIT_LOG_HASH_CHAIN", false),
+			Forward:   src.getBoolEnv("AUDIT_LOG_FORWARD", false),
+		},
+		Backup: BackupConfig{
+			EncryptionKey: src.getEnv("BACKUP_ENCRYPTION_KEY", ""),
+			Endpoints:     src.getBoolEnv("BACKUP_ENDPOINTS_ENABLED", false),
+		},
+		Operations: OperationsConfig{
+			Workers:      src.getIntEnv("OPERATIONS_WORKERS", 2),
+			PollInterval: src.getDurationEnv("OPERATIONS_POLL_INTERVAL", time.Second),
+			StaleAfter:   src.getDurationEnv("OPERATIONS_STALE_AFTER", time.Minute),
+			MaxAttempts:  src.getIntEnv("OPERATIONS_MAX_ATTEMPTS", 3),
+		},
+		ReadOnly: ReadOnlyConfig{
+			Enabled:      src.getBoolEnv("READ_ONLY_MODE", false),
+			Message:      src.getEnv("READ_ONLY_MESSAGE", ""),
+			ExemptRoutes: src.getListEnv("READ_ONLY_EXEMPT_ROUTES", []string{"/auth/login", "/auth/logout", "/auth/refresh", "/oauth/token"}),
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:           src.getBoolEnv("WEBHOOKS_ENABLED", false),
+			MaxAttempts:       src.getIntEnv("WEBHOOK_MAX_ATTEMPTS", 5),
+			SendGridPublicKey: src.getEnv("SENDGRID_WEBHOOK_PUBLIC_KEY", ""),
+			Secrets:           src.getListEnv("WEBHOOK_SECRETS", nil),
+		},
+		Analytics: AnalyticsConfig{
+			Sink:            src.getEnv("ANALYTICS_SINK", "none"),
+			BatchSize:       src.getIntEnv("ANALYTICS_BATCH_SIZE", 100),
+			FlushInterval:   src.getDurationEnv("ANALYTICS_FLUSH_INTERVAL", 10*time.Second),
+			QueueSize:       src.getIntEnv("ANALYTICS_QUEUE_SIZE", 10000),
+			SegmentEndpoint: src.getEnv("SEGMENT_ENDPOINT", "https://api.segment.io"),
+			SegmentWriteKey: src.getEnv("SEGMENT_WRITE_KEY", ""),
+			PostHogHost:     src.getEnv("POSTHOG_HOST", "https://us.i.posthog.com"),
+			PostHogAPIKey:   src.getEnv("POSTHOG_API_KEY", ""),
+		},
+		Secrets: SecretsConfig{
+			Provider:            src.getEnv("SECRETS_PROVIDER", "none"),
+			RefreshInterval:     src.getDurationEnv("SECRETS_REFRESH_INTERVAL", 5*time.Minute),
+			VaultAddress:        src.getEnv("VAULT_ADDR", "http://localhost:8200"),
+			VaultToken:          src.getEnv("VAULT_TOKEN", ""),
+			VaultNamespace:      src.getEnv("VAULT_NAMESPACE", ""),
+			AWSRegion:           src.getEnv("SECRETS_AWS_REGION", ""),
+			GCPProject:          src.getEnv("SECRETS_GCP_PROJECT", ""),
+			JWTSecretRef:        src.getEnv("SECRETS_JWT_SECRET_REF", ""),
+			MongoPasswordRef:    src.getEnv("SECRETS_MONGODB_PASSWORD_REF", ""),
+			MongoURIRef:         src.getEnv("SECRETS_MONGODB_URI_REF", ""),
+			PostgresPasswordRef: src.getEnv("SECRETS_POSTGRES_PASSWORD_REF", ""),
+		},
+		DefaultLanguage: src.getEnv("DEFAULT_LANGUAGE", "en"),
+		Languages:       src.getListEnv("SUPPORTED_LANGUAGES", nil),
+		Locales: LocalesConfig{
+			Dir:            src.getEnv("LOCALES_DIR", ""),
+			ReloadInterval: src.getDurationEnv("LOCALES_RELOAD_INTERVAL", localesReload),
+			SyncInterval:   src.getDurationEnv("LOCALES_SYNC_INTERVAL", time.Minute),
+		},
+		JWTSecret: src.getEnv("JWT_SECRET", DefaultJWTSecret),
+		Auth: AuthConfig{
+			TokenDelivery:     src.getEnv("AUTH_TOKEN_DELIVERY", "header"),
+			CookieName:        src.getEnv("AUTH_COOKIE_NAME", "access_token"),
+			CookieDomain:      src.getEnv("AUTH_COOKIE_DOMAIN", ""),
+			CookiePath:        src.getEnv("AUTH_COOKIE_PATH", "/"),
+			CookieSecure:      src.getBoolEnv("AUTH_COOKIE_SECURE", true),
+			CookieSameSite:    src.getEnv("AUTH_COOKIE_SAMESITE", "lax"),
+			RefreshCookieName: src.getEnv("AUTH_REFRESH_COOKIE_NAME", "refresh_token"),
+		},
+		Sessions: SessionsConfig{
+			Enabled:               src.getBoolEnv("SESSIONS_ENABLED", true),
+			IdleTimeout:           src.getDurationEnv("SESSION_IDLE_TIMEOUT", 7*24*time.Hour),
+			MaxLifetime:           src.getDurationEnv("SESSION_MAX_LIFETIME", 30*24*time.Hour),
+			RememberMeIdleTimeout: src.getDurationEnv("REMEMBER_ME_IDLE_TIMEOUT", 30*24*time.Hour),
+			RememberMeMaxLifetime: src.getDurationEnv("REMEMBER_ME_MAX_LIFETIME", 90*24*time.Hour),
+		},
+		LoginThrottle: LoginThrottleConfig{
+			Enabled:       src.getBoolEnv("LOGIN_THROTTLE_ENABLED", true),
+			FreeAttempts:  src.getIntEnv("LOGIN_THROTTLE_FREE_ATTEMPTS", 3),
+			BaseDelay:     src.getDurationEnv("LOGIN_THROTTLE_BASE_DELAY", time.Second),
+			MaxDelay:      src.getDurationEnv("LOGIN_THROTTLE_MAX_DELAY", 30*time.Second),
+			BlockAfter:    src.getIntEnv("LOGIN_THROTTLE_BLOCK_AFTER", 10),
+			BlockDuration: src.getDurationEnv("LOGIN_THROTTLE_BLOCK_DURATION", 15*time.Minute),
+			Window:        src.getDurationEnv("LOGIN_THROTTLE_WINDOW", 15*time.Minute),
+		},
+		BotDetection: BotDetectionConfig{
+			Enabled:           src.getBoolEnv("BOT_DETECTION_ENABLED", false),
+			Routes:            src.getListEnv("BOT_DETECTION_ROUTES", []string{"/auth/login", "/auth/register"}),
+			HoneypotField:     src.getEnv("BOT_HONEYPOT_FIELD", "website"),
+			HeaderChecks:      src.getBoolEnv("BOT_HEADER_CHECKS", true),
+			BlockedUserAgents: src.getListEnv("BOT_BLOCKED_USER_AGENTS", []string{"python-requests", "python-urllib", "scrapy", "libwww-perl", "headlesschrome", "phantomjs"}),
+			ChallengeRoutes:   src.getListEnv("BOT_CHALLENGE_ROUTES", nil),
+			ChallengeSecret:   src.getEnv("BOT_CHALLENGE_SECRET", ""),
+			ChallengeMinAge:   src.getDurationEnv("BOT_CHALLENGE_MIN_AGE", 2*time.Second),
+			ChallengeTTL:      src.getDurationEnv("BOT_CHALLENGE_TTL", 10*time.Minute),
+		},
+		PasswordBreach: PasswordBreachConfig{
+			Enabled:  src.getBoolEnv("PASSWORD_BREACH_CHECK", false),
+			APIURL:   src.getEnv("PASSWORD_BREACH_API_URL", "https://api.pwnedpasswords.com"),
+			Timeout:  src.getDurationEnv("PASSWORD_BREACH_TIMEOUT", 2*time.Second),
+			FailOpen: src.getBoolEnv("PASSWORD_BREACH_FAIL_OPEN", true),
+		},
+		Email: EmailConfig{
+			BlockedDomains: src.getListEnv("EMAIL_BLOCKED_DOMAINS", nil),
+			BlocklistFile:  src.getEnv("EMAIL_BLOCKLIST_FILE", ""),
+			CheckMX:        src.getBoolEnv("EMAIL_CHECK_MX", false),
+			MXTimeout:      src.getDurationEnv("EMAIL_MX_TIMEOUT", 3*time.Second),
+		},
+		OAuth: OAuthConfig{
+			Enabled:        src.getBoolEnv("OAUTH_ENABLED", false),
+			Issuer:         src.getEnv("OAUTH_ISSUER", ""),
+			SigningKeyFile: src.getEnv("OAUTH_SIGNING_KEY_FILE", ""),
+			ConsentURL:     src.getEnv("OAUTH_CONSENT_URL", ""),
+			CodeTTL:        src.getDurationEnv("OAUTH_CODE_TTL", 5*time.Minute),
+			AccessTokenTTL: src.getDurationEnv("OAUTH_ACCESS_TOKEN_TTL", time.Hour),
+			ServiceScopes:  src.getListEnv("OAUTH_SERVICE_SCOPES", nil),
+
+			DeviceVerificationURL: src.getEnv("OAUTH_DEVICE_VERIFICATION_URL", ""),
+			DeviceCodeTTL:         src.getDurationEnv("OAUTH_DEVICE_CODE_TTL", 10*time.Minute),
+			DevicePollInterval:    src.getDurationEnv("OAUTH_DEVICE_POLL_INTERVAL", 5*time.Second),
+		},
 		MongoDB: MongoDBConfig{
-			Enabled:  getBoolEnv("MONGODB_ENABLED", true),
-			URI:      getEnv("MONGODB_URI", ""),
-			Database: getEnv("MONGODB_DATABASE", "mygo"),
-			Username: getEnv("MONGODB_USERNAME", "root"),
-			Password: getEnv("MONGODB_PASSWORD", "4jClkoZfth8Jq4lB"),
-			Host:     getEnv("MONGODB_HOST", "localhost"),
-			Port:     getEnv("MONGODB_PORT", "27017"),
+			Enabled:         src.getBoolEnv("MONGODB_ENABLED", true),
+			URI:             src.getEnv("MONGODB_URI", ""),
+			Database:        src.getEnv("MONGODB_DATABASE", "mygo"),
+			Username:        src.getEnv("MONGODB_USERNAME", "root"),
+			Password:        src.getEnv("MONGODB_PASSWORD", defaultMongoPassword),
+			Host:            src.getEnv("MONGODB_HOST", "localhost"),
+			Port:            src.getEnv("MONGODB_PORT", "27017"),
+			EnsureIndexes:   src.getBoolEnv("MONGODB_ENSURE_INDEXES", true),
+			QueryTimeout:    src.getDurationEnv("MONGODB_QUERY_TIMEOUT", 5*time.Second),
+			MaxPoolSize:     src.getIntEnv("MONGODB_MAX_POOL_SIZE", 100),
+			MinPoolSize:     src.getIntEnv("MONGODB_MIN_POOL_SIZE", 0),
+			MaxConnIdleTime: src.getDurationEnv("MONGODB_MAX_CONN_IDLE_TIME", 0),
 		},
 		PostgresDB: PostgresDBConfig{
-			Enabled:  getBoolEnv("POSTGRES_ENABLED", false),
-			Host:     getEnv("POSTGRES_HOST", "localhost"),
-			Port:     getEnv("POSTGRES_PORT", "5432"),
-			Username: getEnv("POSTGRES_USERNAME", "postgres"),
-			Password: getEnv("POSTGRES_PASSWORD", "password"),
-			Database: getEnv("POSTGRES_DATABASE", "backend_template"),
-			SSLMode:  getEnv("POSTGRES_SSLMODE", "disable"),
+			Enabled:         src.getBoolEnv("POSTGRES_ENABLED", false),
+			Host:            src.getEnv("POSTGRES_HOST", "localhost"),
+			Port:            src.getEnv("POSTGRES_PORT", "5432"),
+			Username:        src.getEnv("POSTGRES_USERNAME", "postgres"),
+			Password:        src.getEnv("POSTGRES_PASSWORD", defaultPostgresPassword),
+			Database:        src.getEnv("POSTGRES_DATABASE", "backend_template"),
+			SSLMode:         src.getEnv("POSTGRES_SSLMODE", "disable"),
+			MigrateOnStart:  src.getBoolEnv("POSTGRES_MIGRATE_ON_START", true),
+			QueryTimeout:    src.getDurationEnv("POSTGRES_QUERY_TIMEOUT", 5*time.Second),
+			ReplicaDSNs:     src.getListEnv("POSTGRES_REPLICA_DSNS", nil),
+			UserIDType:      src.getEnv("POSTGRES_USER_ID_TYPE", "bigserial"),
+			MaxOpenConns:    src.getIntEnv("POSTGRES_MAX_OPEN_CONNS", 100),
+			MaxIdleConns:    src.getIntEnv("POSTGRES_MAX_IDLE_CONNS", 10),
+			ConnMaxLifetime: src.getDurationEnv("POSTGRES_CONN_MAX_LIFETIME", time.Hour),
+			ConnMaxIdleTime: src.getDurationEnv("POSTGRES_CONN_MAX_IDLE_TIME", 0),
+		},
+		DBConnect: DBConnectConfig{
+			Attempts:      src.getIntEnv("DB_CONNECT_ATTEMPTS", 10),
+			InitialDelay:  src.getDurationEnv("DB_CONNECT_INITIAL_DELAY", time.Second),
+			MaxDelay:      src.getDurationEnv("DB_CONNECT_MAX_DELAY", 30*time.Second),
+			CheckInterval: src.getDurationEnv("DB_HEALTH_CHECK_INTERVAL", 15*time.Second),
+		},
+		QueryLog: QueryLogConfig{
+			SlowThreshold:  src.getDurationEnv("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+			WarnPerRequest: src.getIntEnv("DB_QUERIES_PER_REQUEST_WARN", 25),
+			TagRequests:    src.getBoolEnv("DB_TAG_QUERIES", true),
+		},
+		Health: HealthConfig{
+			Timeout:         src.getDurationEnv("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			DegradedLatency: src.getDurationEnv("HEALTH_DEGRADED_LATENCY", 500*time.Millisecond),
+		},
+		HTTPClient: HTTPClientConfig{
+			Timeout:          src.getDurationEnv("HTTP_CLIENT_TIMEOUT", 10*time.Second),
+			MaxRetries:       src.getIntEnv("HTTP_CLIENT_MAX_RETRIES", 2),
+			RetryDelay:       src.getDurationEnv("HTTP_CLIENT_RETRY_DELAY", 200*time.Millisecond),
+			MaxRetryDelay:    src.getDurationEnv("HTTP_CLIENT_MAX_RETRY_DELAY", 2*time.Second),
+			BreakerThreshold: src.getIntEnv("HTTP_CLIENT_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:  src.getDurationEnv("HTTP_CLIENT_BREAKER_COOLDOWN", 30*time.Second),
 		},
+		SQLite: SQLiteConfig{
+			Enabled: src.getBoolEnv("SQLITE_ENABLED", false),
+			Path:    src.getEnv("SQLITE_PATH", "backend_template.db"),
+		},
+		DualWrite: src.getBoolEnv("DUAL_WRITE_ENABLED", false),
 	}
+	cfg.parseErrors = src.errs
+
+	return cfg, nil
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+func (src *source) getEnv(key, defaultValue string) string {
+	if value := src.lookup(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
-func getBoolEnv(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
+func (src *source) getIntEnv(key string, defaultValue int) int {
+	if value := src.lookup(key); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			src.invalid(key, value, "integer")
+			return defaultValue
+		}
+		return parsed
+	}
+	return defaultValue
+}
+
+func (src *source) getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := src.lookup(key); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			src.invalid(key, value, "duration")
+			return defaultValue
+		}
+		return parsed
+	}
+	return defaultValue
+}
+
+func (src *source) getListEnv(key string, defaultValue []string) []string {
+	if value := src.lookup(key); value != "" {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return defaultValue
+}
+
+// getByteSizeEnv parses sizes such as "512", "64KB", "10MB", or "1GB"
+func (src *source) getByteSizeEnv(key string, defaultValue int64) int64 {
+	if value := src.lookup(key); value != "" {
+		upper := strings.ToUpper(strings.TrimSpace(value))
+		multiplier := int64(1)
+		for _, unit := range []struct {
+			suffix string
+			factor int64
+		}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+			if strings.HasSuffix(upper, unit.suffix) {
+				upper = strings.TrimSpace(strings.TrimSuffix(upper, unit.suffix))
+				multiplier = unit.factor
+				break
+			}
+		}
+
+		parsed, err := strconv.ParseInt(upper, 10, 64)
+		if err != nil || parsed < 0 {
+			src.invalid(key, value, "byte size")
+			return defaultValue
+		}
+		return parsed * multiplier
+	}
+	return defaultValue
+}
+
+func (src *source) getBoolEnv(key string, defaultValue bool) bool {
+	if value := src.lookup(key); value != "" {
 		parsed, err := strconv.ParseBool(strings.ToLower(value))
 		if err != nil {
+			src.invalid(key, value, "boolean")
 			return defaultValue
 		}
 		return parsed
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// configExtensions lists supported config file extensions in lookup order
+var configExtensions = []string{".yaml", ".yml", ".toml"}
+
+// source resolves configuration keys from the environment, falling back to values read from config files
+type source struct {
+	values map[string]string
+	errs   []error
+}
+
+// lookup returns the environment value for key, or the config file value if the variable is unset
+func (src *source) lookup(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return src.values[key]
+}
+
+// invalid records a value that could not be parsed so validation can report it
+func (src *source) invalid(key, value, kind string) {
+	src.errs = append(src.errs, fmt.Errorf("%s: %q is not a valid %s", key, value, kind))
+}
+
+// loadFileValues reads the base and per-environment config files and flattens them into env-style keys.
+// Nested keys are joined with underscores and upper-cased, so `postgres: {host: db}` maps to POSTGRES_HOST.
+func loadFileValues() (*source, error) {
+	values := &source{values: map[string]string{}}
+	dir := os.Getenv("CONFIG_DIR")
+	if dir == "" {
+		dir = "."
+	}
+
+	basePath := os.Getenv("CONFIG_FILE")
+	if basePath == "" {
+		basePath = findConfigFile(dir, "config")
+	} else if _, err := os.Stat(basePath); err != nil {
+		return nil, fmt.Errorf("config file %s not found: %w", basePath, err)
+	}
+
+	if basePath != "" {
+		if err := readConfigFile(basePath, values); err != nil {
+			return nil, err
+		}
+	}
+
+	// The environment name may itself come from the base file
+	environment := values.lookup("ENVIRONMENT")
+	if environment == "" {
+		environment = "development"
+	}
+
+	if envPath := findConfigFile(dir, "config."+environment); envPath != "" {
+		if err := readConfigFile(envPath, values); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// findConfigFile returns the first existing file named base with a supported extension in dir
+func findConfigFile(dir, base string) string {
+	for _, ext := range configExtensions {
+		path := filepath.Join(dir, base+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// readConfigFile parses a YAML or TOML file and merges its flattened keys into values
+func readConfigFile(path string, values *source) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	raw := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		err = yaml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	flatten("", raw, values.values)
+	return nil
+}
+
+// flatten converts nested maps into upper-case underscore-joined keys
+func flatten(prefix string, raw map[string]interface{}, values map[string]string) {
+	for key, value := range raw {
+		name := strings.ToUpper(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(name, v, values)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[name] = strings.Join(items, ",")
+		case nil:
+			values[name] = ""
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+}
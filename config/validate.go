@@ -0,0 +1,663 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minProductionSecretLength is the minimum JWT secret length accepted in production
+const minProductionSecretLength = 32
+
+// minBackupKeyLength is the minimum length of the passphrase backup archives are encrypted with
+const minBackupKeyLength = 16
+
+// serviceScopePattern is the resource:action form of the scopes of service tokens, which keeps them
+// apart from the OpenID Connect scopes
+var serviceScopePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*:[a-z][a-z0-9_-]*$`)
+
+// webhookProviderName is the form of the provider names in WEBHOOK_SECRETS, which appear in the webhook URL
+var webhookProviderName = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// botGuardedRoutes are the routes bot detection can guard, given without the API version
+var botGuardedRoutes = []string{"/auth/login", "/auth/register"}
+
+// insecureSecrets are placeholder values shipped in examples that must never reach production
+var insecureSecrets = map[string]bool{
+	DefaultJWTSecret: true,
+	"your-super-secret-jwt-key-change-this-in-production": true,
+}
+
+// insecurePasswords are default database passwords from the template, compose file, and examples
+var insecurePasswords = map[string]bool{
+	defaultMongoPassword:    true,
+	defaultPostgresPassword: true,
+	"password123":           true,
+}
+
+// UsesCookies reports whether auth tokens are delivered as cookies
+func (a AuthConfig) UsesCookies() bool {
+	return a.TokenDelivery == "cookie" || a.TokenDelivery == "both"
+}
+
+// UsesHeader reports whether auth tokens are returned in the response body for use in the Authorization header
+func (a AuthConfig) UsesHeader() bool {
+	return a.TokenDelivery == "header" || a.TokenDelivery == "both"
+}
+
+// IsProduction reports whether the application runs in production
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
+}
+
+// ServesAPI reports whether the run mode serves the HTTP API
+func (c *Config) ServesAPI() bool {
+	return c.Mode == ModeAll || c.Mode == ModeServe
+}
+
+// RunsWorkers reports whether the run mode runs the background workers
+func (c *Config) RunsWorkers() bool {
+	return c.Mode == ModeAll || c.Mode == ModeWorker
+}
+
+// RunsScheduler reports whether the run mode runs the scheduled jobs
+func (c *Config) RunsScheduler() bool {
+	return c.Mode == ModeAll || c.Mode == ModeScheduler
+}
+
+// Validate checks the configuration and returns all problems found
+func (c *Config) Validate() error {
+	errs := append([]error{}, c.parseErrors...)
+
+	if c.JWTSecret == "" {
+		errs = append(errs, errors.New("JWT_SECRET is required"))
+	}
+	if !oneOf(c.Auth.TokenDelivery, "header", "cookie", "both") {
+		errs = append(errs, fmt.Errorf("AUTH_TOKEN_DELIVERY: %q must be header, cookie, or both", c.Auth.TokenDelivery))
+	}
+	if !oneOf(c.Auth.CookieSameSite, "lax", "strict", "none") {
+		errs = append(errs, fmt.Errorf("AUTH_COOKIE_SAMESITE: %q must be lax, strict, or none", c.Auth.CookieSameSite))
+	}
+	// Browsers send a SameSite=None cookie with requests from any site, and nothing else stops a forged
+	// cross-site request authenticated by the cookie
+	if strings.EqualFold(c.Auth.CookieSameSite, "none") && c.Auth.UsesCookies() {
+		errs = append(errs, errors.New("AUTH_COOKIE_SAMESITE must be lax or strict when AUTH_TOKEN_DELIVERY is cookie or both"))
+	}
+	if c.Sessions.Enabled {
+		if c.Sessions.IdleTimeout <= 0 || c.Sessions.MaxLifetime < c.Sessions.IdleTimeout {
+			errs = append(errs, errors.New("SESSION_IDLE_TIMEOUT must be positive and at most SESSION_MAX_LIFETIME"))
+		}
+		if c.Sessions.RememberMeIdleTimeout <= 0 || c.Sessions.RememberMeMaxLifetime < c.Sessions.RememberMeIdleTimeout {
+			errs = append(errs, errors.New("REMEMBER_ME_IDLE_TIMEOUT must be positive and at most REMEMBER_ME_MAX_LIFETIME"))
+		}
+	}
+	if c.LoginThrottle.Enabled {
+		if c.LoginThrottle.FreeAttempts < 0 || c.LoginThrottle.BlockAfter <= c.LoginThrottle.FreeAttempts {
+			errs = append(errs, errors.New("LOGIN_THROTTLE_BLOCK_AFTER must be greater than LOGIN_THROTTLE_FREE_ATTEMPTS, which must not be negative"))
+		}
+		if c.LoginThrottle.BaseDelay <= 0 || c.LoginThrottle.MaxDelay < c.LoginThrottle.BaseDelay {
+			errs = append(errs, errors.New("LOGIN_THROTTLE_BASE_DELAY must be positive and at most LOGIN_THROTTLE_MAX_DELAY"))
+		}
+		if c.LoginThrottle.BlockDuration <= 0 || c.LoginThrottle.Window <= 0 {
+			errs = append(errs, errors.New("LOGIN_THROTTLE_BLOCK_DURATION and LOGIN_THROTTLE_WINDOW must be positive"))
+		}
+	}
+	if c.BotDetection.Enabled {
+		for _, route := range append(c.BotDetection.Routes, c.BotDetection.ChallengeRoutes...) {
+			if !oneOf(route, botGuardedRoutes...) {
+				errs = append(errs, fmt.Errorf("BOT_DETECTION_ROUTES and BOT_CHALLENGE_ROUTES: %q must be one of %s", route, strings.Join(botGuardedRoutes, ", ")))
+			}
+		}
+		if len(c.BotDetection.ChallengeRoutes) > 0 {
+			if len(c.BotDetection.ChallengeSecret) < 32 {
+				errs = append(errs, errors.New("BOT_CHALLENGE_SECRET must be at least 32 characters when BOT_CHALLENGE_ROUTES is set"))
+			}
+			if c.BotDetection.ChallengeMinAge < 0 || c.BotDetection.ChallengeTTL <= c.BotDetection.ChallengeMinAge {
+				errs = append(errs, errors.New("BOT_CHALLENGE_TTL must be greater than BOT_CHALLENGE_MIN_AGE, which must not be negative"))
+			}
+		}
+	}
+	if c.PasswordBreach.Enabled {
+		if err := validateURL("PASSWORD_BREACH_API_URL", c.PasswordBreach.APIURL, "http", "https"); err != nil {
+			errs = append(errs, err)
+		}
+		if c.PasswordBreach.Timeout <= 0 {
+			errs = append(errs, errors.New("PASSWORD_BREACH_TIMEOUT must be positive"))
+		}
+	}
+	if c.Email.CheckMX && c.Email.MXTimeout <= 0 {
+		errs = append(errs, errors.New("EMAIL_MX_TIMEOUT must be positive"))
+	}
+	if c.OAuth.Enabled {
+		if err := validateURL("OAUTH_ISSUER", c.OAuth.Issuer, "http", "https"); err != nil {
+			errs = append(errs, err)
+		}
+		if err := validateURL("OAUTH_CONSENT_URL", c.OAuth.ConsentURL, "http", "https"); err != nil {
+			errs = append(errs, err)
+		}
+		if c.OAuth.CodeTTL <= 0 || c.OAuth.CodeTTL > 10*time.Minute {
+			errs = append(errs, errors.New("OAUTH_CODE_TTL must be positive and at most 10m"))
+		}
+		if c.OAuth.AccessTokenTTL <= 0 {
+			errs = append(errs, errors.New("OAUTH_ACCESS_TOKEN_TTL must be positive"))
+		}
+		if c.OAuth.DeviceVerificationURL != "" {
+			if err := validateURL("OAUTH_DEVICE_VERIFICATION_URL", c.OAuth.DeviceVerificationURL, "http", "https"); err != nil {
+				errs = append(errs, err)
+			}
+			if c.OAuth.DeviceCodeTTL <= 0 || c.OAuth.DeviceCodeTTL > 30*time.Minute {
+				errs = append(errs, errors.New("OAUTH_DEVICE_CODE_TTL must be positive and at most 30m"))
+			}
+			if c.OAuth.DevicePollInterval < time.Second {
+				errs = append(errs, errors.New("OAUTH_DEVICE_POLL_INTERVAL must be at least 1s"))
+			}
+		}
+		for _, scope := range c.OAuth.ServiceScopes {
+			if !serviceScopePattern.MatchString(scope) {
+				errs = append(errs, fmt.Errorf("OAUTH_SERVICE_SCOPES: %q must look like resource:action", scope))
+			}
+		}
+	}
+	if !oneOf(c.Mode, ModeAll, ModeServe, ModeWorker, ModeScheduler, ModeMigrate) {
+		errs = append(errs, fmt.Errorf("RUN_MODE: %q must be all, serve, worker, scheduler, or migrate", c.Mode))
+	}
+	if len(c.Languages) > 0 && !oneOf(c.DefaultLanguage, c.Languages...) {
+		errs = append(errs, fmt.Errorf("SUPPORTED_LANGUAGES must include DEFAULT_LANGUAGE %q", c.DefaultLanguage))
+	}
+	if c.Locales.SyncInterval < 0 {
+		errs = append(errs, errors.New("LOCALES_SYNC_INTERVAL must not be negative"))
+	}
+	if c.AdminStats.CacheTTL < 0 {
+		errs = append(errs, errors.New("ADMIN_STATS_CACHE_TTL must not be negative"))
+	}
+	if (c.Bootstrap.AdminEmail == "") != (c.Bootstrap.AdminPassword == "") {
+		errs = append(errs, errors.New("BOOTSTRAP_ADMIN_EMAIL and BOOTSTRAP_ADMIN_PASSWORD must be set together"))
+	}
+	if err := validatePort("PORT", c.Port); err != nil {
+		errs = append(errs, err)
+	}
+	if c.TLS.Enabled {
+		if c.TLS.Autocert {
+			if len(c.TLS.AutocertDomains) == 0 {
+				errs = append(errs, errors.New("TLS_AUTOCERT_DOMAINS is required when TLS_AUTOCERT is enabled"))
+			}
+		} else if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+			errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE are required when TLS is enabled without autocert"))
+		}
+		if c.TLS.RedirectHTTP || c.TLS.Autocert {
+			if err := validatePort("TLS_HTTP_PORT", c.TLS.HTTPPort); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	switch c.Router.Mode {
+	case "debug", "release", "test":
+	default:
+		errs = append(errs, fmt.Errorf("GIN_MODE must be debug, release, or test, got %q", c.Router.Mode))
+	}
+	for _, proxy := range c.Proxy.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				errs = append(errs, fmt.Errorf("TRUSTED_PROXIES: %q is not a valid IP or CIDR", proxy))
+			}
+		}
+	}
+	if c.Limits.MaxBodySize <= 0 {
+		errs = append(errs, errors.New("MAX_BODY_SIZE must be greater than zero"))
+	}
+	if c.Timeouts.Request < 0 {
+		errs = append(errs, errors.New("REQUEST_TIMEOUT must not be negative"))
+	}
+	for _, entry := range c.Timeouts.Routes {
+		prefix, value, ok := strings.Cut(entry, "=")
+		if timeout, err := time.ParseDuration(value); !ok || !strings.HasPrefix(prefix, "/") || err != nil || timeout < 0 {
+			errs = append(errs, fmt.Errorf("ROUTE_TIMEOUTS: %q must be /path=duration", entry))
+		}
+	}
+	if c.Concurrency.MaxInFlight < 0 {
+		errs = append(errs, errors.New("MAX_IN_FLIGHT_REQUESTS must not be negative"))
+	}
+	if c.Concurrency.Reserved < 0 || c.Concurrency.Reserved > 0 && c.Concurrency.Reserved >= c.Concurrency.MaxInFlight {
+		errs = append(errs, errors.New("RESERVED_IN_FLIGHT_REQUESTS must be less than MAX_IN_FLIGHT_REQUESTS"))
+	}
+	for _, prefix := range c.Concurrency.PriorityRoutes {
+		if !strings.HasPrefix(prefix, "/") {
+			errs = append(errs, fmt.Errorf("PRIORITY_ROUTES: %q must be a /path prefix", prefix))
+		}
+	}
+	for _, role := range c.Concurrency.PriorityRoles {
+		if role != "user" && role != "admin" && role != "superadmin" {
+			errs = append(errs, fmt.Errorf("PRIORITY_ROLES: %q must be user, admin, or superadmin", role))
+		}
+	}
+	for _, entry := range c.Concurrency.Groups {
+		prefix, value, ok := strings.Cut(entry, "=")
+		if limit, err := strconv.Atoi(value); !ok || !strings.HasPrefix(prefix, "/") || err != nil || limit < 1 {
+			errs = append(errs, fmt.Errorf("GROUP_MAX_IN_FLIGHT_REQUESTS: %q must be /path=limit", entry))
+		}
+	}
+	if c.Concurrency.RetryAfter <= 0 {
+		errs = append(errs, errors.New("OVERLOAD_RETRY_AFTER must be positive"))
+	}
+	if c.Limits.MaxUploadSize < c.Limits.MaxBodySize {
+		errs = append(errs, errors.New("MAX_FILE_SIZE must not be smaller than MAX_BODY_SIZE"))
+	}
+	if !oneOf(c.Idempotency.Store, "memory", "database") {
+		errs = append(errs, fmt.Errorf("IDEMPOTENCY_STORE: %q must be memory or database", c.Idempotency.Store))
+	}
+	if c.Idempotency.TTL <= 0 {
+		errs = append(errs, errors.New("IDEMPOTENCY_TTL must be greater than zero"))
+	}
+	if !oneOf(c.ErrorFormat, "default", "problem") {
+		errs = append(errs, fmt.Errorf("ERROR_FORMAT: %q must be default or problem", c.ErrorFormat))
+	}
+	if !validAPIVersion(c.APIVersions.Default) {
+		errs = append(errs, fmt.Errorf("API_DEFAULT_VERSION: %q must be v followed by a number, such as v1", c.APIVersions.Default))
+	}
+	for _, entry := range c.APIVersions.Deprecated {
+		if !validDeprecation(entry) {
+			errs = append(errs, fmt.Errorf("API_DEPRECATED_VERSIONS: %q must be version=YYYY-MM-DD or version=YYYY-MM-DD/YYYY-MM-DD (deprecated/sunset)", entry))
+		}
+	}
+	if c.Realtime.BufferSize <= 0 {
+		errs = append(errs, errors.New("REALTIME_BUFFER_SIZE must be greater than zero"))
+	}
+	if c.Realtime.HistorySize < 0 {
+		errs = append(errs, errors.New("REALTIME_HISTORY_SIZE must not be negative"))
+	}
+	if c.Realtime.PingInterval <= 0 {
+		errs = append(errs, errors.New("WS_PING_INTERVAL must be greater than zero"))
+	}
+	if c.Realtime.Heartbeat <= 0 {
+		errs = append(errs, errors.New("SSE_HEARTBEAT_INTERVAL must be greater than zero"))
+	}
+	if c.Retention.Interval <= 0 {
+		errs = append(errs, errors.New("RETENTION_INTERVAL must be greater than zero"))
+	}
+	for _, policy := range []struct {
+		name      string
+		retention time.Duration
+	}{
+		{"USER_PURGE_AFTER", c.Retention.DeletedUsers},
+		{"RETENTION_AUDIT_LOGS", c.Retention.AuditLogs},
+		{"RETENTION_LOGIN_HISTORY", c.Retention.LoginHistory},
+		{"RETENTION_ANALYTICS_EVENTS", c.Retention.AnalyticsEvents},
+		{"RETENTION_WEBHOOK_EVENTS", c.Retention.WebhookEvents},
+		{"RETENTION_OPERATIONS", c.Retention.Operations},
+	} {
+		if policy.retention < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", policy.name))
+		}
+	}
+	for _, attribute := range c.UserMetadata.Schema {
+		name, kind, ok := strings.Cut(attribute, "=")
+		if !ok || name == "" || !oneOf(kind, "string", "number", "boolean") {
+			errs = append(errs, fmt.Errorf("USER_METADATA_SCHEMA: %q must be name=string, name=number, or name=boolean", attribute))
+		}
+	}
+	if !oneOf(c.Usage.Store, "memory", "database") {
+		errs = append(errs, fmt.Errorf("USAGE_STORE: %q must be memory or database", c.Usage.Store))
+	}
+	for _, quota := range c.Usage.Quotas {
+		plan, limit, ok := strings.Cut(quota, "=")
+		if n, err := strconv.ParseInt(limit, 10, 64); !ok || plan == "" || err != nil || n < 0 {
+			errs = append(errs, fmt.Errorf("USAGE_QUOTAS: %q must be plan=monthly_request_limit", quota))
+		}
+	}
+	if c.Billing.Enabled {
+		if c.Billing.StripeSecretKey == "" || c.Billing.StripeWebhookSecret == "" {
+			errs = append(errs, errors.New("STRIPE_SECRET_KEY and STRIPE_WEBHOOK_SECRET are required when billing is enabled"))
+		}
+		if c.Billing.SuccessURL == "" || c.Billing.CancelURL == "" {
+			errs = append(errs, errors.New("BILLING_SUCCESS_URL and BILLING_CANCEL_URL are required when billing is enabled"))
+		}
+		if len(c.Billing.Plans) == 0 {
+			errs = append(errs, errors.New("BILLING_PLANS is required when billing is enabled"))
+		}
+		for _, plan := range c.Billing.Plans {
+			if id, price, ok := strings.Cut(plan, "="); !ok || id == "" || price == "" {
+				errs = append(errs, fmt.Errorf("BILLING_PLANS: %q must be plan=stripe_price_id", plan))
+			}
+		}
+	}
+	if !oneOf(c.Log.Format, "json", "text") {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT: %q must be json or text", c.Log.Format))
+	}
+	if !oneOf(c.Log.Output, "stdout", "stderr", "file", "both") {
+		errs = append(errs, fmt.Errorf("LOG_OUTPUT: %q must be stdout, stderr, file, or both", c.Log.Output))
+	}
+	if !oneOf(c.SecurityLog.Sink, "none", "file", "syslog", "http") {
+		errs = append(errs, fmt.Errorf("SECURITY_LOG_SINK: %q must be none, file, syslog, or http", c.SecurityLog.Sink))
+	}
+	if c.AuditLog.Enabled && c.AuditLog.Forward && strings.EqualFold(c.SecurityLog.Sink, "none") {
+		errs = append(errs, errors.New("AUDIT_LOG_FORWARD needs a SECURITY_LOG_SINK to forward to"))
+	}
+	if c.Backup.EncryptionKey != "" && len(c.Backup.EncryptionKey) < minBackupKeyLength {
+		errs = append(errs, fmt.Errorf("BACKUP_ENCRYPTION_KEY must be at least %d characters", minBackupKeyLength))
+	}
+	if c.Backup.Endpoints && c.Backup.EncryptionKey == "" {
+		errs = append(errs, errors.New("BACKUP_ENDPOINTS_ENABLED requires BACKUP_ENCRYPTION_KEY"))
+	}
+	if c.Operations.Workers < 1 {
+		errs = append(errs, errors.New("OPERATIONS_WORKERS must be at least 1"))
+	}
+	if c.Operations.PollInterval <= 0 {
+		errs = append(errs, errors.New("OPERATIONS_POLL_INTERVAL must be greater than zero"))
+	}
+	if c.Operations.StaleAfter < 10*time.Second {
+		errs = append(errs, errors.New("OPERATIONS_STALE_AFTER must be at least 10s"))
+	}
+	if c.Operations.MaxAttempts < 1 {
+		errs = append(errs, errors.New("OPERATIONS_MAX_ATTEMPTS must be at least 1"))
+	}
+	for _, route := range c.ReadOnly.ExemptRoutes {
+		if !strings.HasPrefix(route, "/") {
+			errs = append(errs, fmt.Errorf("READ_ONLY_EXEMPT_ROUTES: %q must start with /", route))
+		}
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		errs = append(errs, errors.New("WEBHOOK_MAX_ATTEMPTS must be at least 1"))
+	}
+	for _, entry := range c.Webhooks.Secrets {
+		if name, secret, ok := strings.Cut(entry, "="); !ok || !webhookProviderName.MatchString(name) || secret == "" {
+			errs = append(errs, errors.New("WEBHOOK_SECRETS: entries must be provider=secret with a lowercase provider name"))
+		} else if name == "stripe" || name == "sendgrid" {
+			errs = append(errs, fmt.Errorf("WEBHOOK_SECRETS: %q is a built-in provider", name))
+		}
+	}
+	if c.SecurityLog.HTTPURL != "" {
+		if err := validateURL("SECURITY_LOG_HTTP_URL", c.SecurityLog.HTTPURL, "http", "https"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if !oneOf(c.Analytics.Sink, "none", "database", "segment", "posthog") {
+		errs = append(errs, fmt.Errorf("ANALYTICS_SINK: %q must be none, database, segment, or posthog", c.Analytics.Sink))
+	}
+	if c.Analytics.BatchSize < 1 || c.Analytics.QueueSize < c.Analytics.BatchSize {
+		errs = append(errs, errors.New("ANALYTICS_BATCH_SIZE must be positive and ANALYTICS_QUEUE_SIZE at least as large"))
+	}
+	if c.Analytics.FlushInterval <= 0 {
+		errs = append(errs, errors.New("ANALYTICS_FLUSH_INTERVAL must be positive"))
+	}
+	switch c.Analytics.Sink {
+	case "segment":
+		if c.Analytics.SegmentWriteKey == "" {
+			errs = append(errs, errors.New("SEGMENT_WRITE_KEY is required when ANALYTICS_SINK is segment"))
+		}
+		if err := validateURL("SEGMENT_ENDPOINT", c.Analytics.SegmentEndpoint, "http", "https"); err != nil {
+			errs = append(errs, err)
+		}
+	case "posthog":
+		if c.Analytics.PostHogAPIKey == "" {
+			errs = append(errs, errors.New("POSTHOG_API_KEY is required when ANALYTICS_SINK is posthog"))
+		}
+		if err := validateURL("POSTHOG_HOST", c.Analytics.PostHogHost, "http", "https"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if !oneOf(c.Secrets.Provider, "none", "vault", "aws", "gcp") {
+		errs = append(errs, fmt.Errorf("SECRETS_PROVIDER: %q must be none, vault, aws, or gcp", c.Secrets.Provider))
+	}
+	if c.Secrets.Provider == "vault" {
+		if err := validateURL("VAULT_ADDR", c.Secrets.VaultAddress, "http", "https"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if !c.MongoDB.Enabled && !c.PostgresDB.Enabled && !c.SQLite.Enabled {
+		errs = append(errs, errors.New("at least one of MONGODB_ENABLED, POSTGRES_ENABLED, or SQLITE_ENABLED must be true"))
+	}
+	if c.DualWrite && (!c.MongoDB.Enabled || !c.PostgresDB.Enabled && !c.SQLite.Enabled) {
+		errs = append(errs, errors.New("DUAL_WRITE_ENABLED requires MongoDB and PostgreSQL (or SQLite) to be enabled"))
+	}
+
+	if c.MongoDB.Enabled {
+		if c.MongoDB.URI != "" {
+			if err := validateURL("MONGODB_URI", c.MongoDB.URI, "mongodb", "mongodb+srv"); err != nil {
+				errs = append(errs, err)
+			}
+		} else {
+			if c.MongoDB.Host == "" {
+				errs = append(errs, errors.New("MONGODB_HOST is required when MongoDB is enabled"))
+			}
+			if err := validatePort("MONGODB_PORT", c.MongoDB.Port); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if c.MongoDB.Database == "" {
+			errs = append(errs, errors.New("MONGODB_DATABASE is required when MongoDB is enabled"))
+		}
+		if c.MongoDB.QueryTimeout < 0 {
+			errs = append(errs, errors.New("MONGODB_QUERY_TIMEOUT must not be negative"))
+		}
+		if c.MongoDB.MaxPoolSize < 0 || c.MongoDB.MinPoolSize < 0 {
+			errs = append(errs, errors.New("MONGODB_MAX_POOL_SIZE and MONGODB_MIN_POOL_SIZE must not be negative"))
+		} else if c.MongoDB.MaxPoolSize > 0 && c.MongoDB.MinPoolSize > c.MongoDB.MaxPoolSize {
+			errs = append(errs, errors.New("MONGODB_MIN_POOL_SIZE must not exceed MONGODB_MAX_POOL_SIZE"))
+		}
+		if c.MongoDB.MaxConnIdleTime < 0 {
+			errs = append(errs, errors.New("MONGODB_MAX_CONN_IDLE_TIME must not be negative"))
+		}
+	}
+
+	if c.PostgresDB.Enabled {
+		if c.PostgresDB.Host == "" {
+			errs = append(errs, errors.New("POSTGRES_HOST is required when PostgreSQL is enabled"))
+		}
+		if err := validatePort("POSTGRES_PORT", c.PostgresDB.Port); err != nil {
+			errs = append(errs, err)
+		}
+		if c.PostgresDB.Database == "" {
+			errs = append(errs, errors.New("POSTGRES_DATABASE is required when PostgreSQL is enabled"))
+		}
+		if !oneOf(c.PostgresDB.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full") {
+			errs = append(errs, fmt.Errorf("POSTGRES_SSLMODE: %q is not a valid sslmode", c.PostgresDB.SSLMode))
+		}
+		if c.PostgresDB.QueryTimeout < 0 {
+			errs = append(errs, errors.New("POSTGRES_QUERY_TIMEOUT must not be negative"))
+		}
+		if c.PostgresDB.MaxOpenConns < 0 || c.PostgresDB.MaxIdleConns < 0 {
+			errs = append(errs, errors.New("POSTGRES_MAX_OPEN_CONNS and POSTGRES_MAX_IDLE_CONNS must not be negative"))
+		}
+		if c.PostgresDB.ConnMaxLifetime < 0 || c.PostgresDB.ConnMaxIdleTime < 0 {
+			errs = append(errs, errors.New("POSTGRES_CONN_MAX_LIFETIME and POSTGRES_CONN_MAX_IDLE_TIME must not be negative"))
+		}
+		if !oneOf(c.PostgresDB.UserIDType, "bigserial", "uuid") {
+			errs = append(errs, fmt.Errorf("POSTGRES_USER_ID_TYPE: %q must be bigserial or uuid", c.PostgresDB.UserIDType))
+		}
+		for _, dsn := range c.PostgresDB.ReplicaDSNs {
+			if parsed, err := url.Parse(dsn); err != nil || (parsed.Scheme != "postgres" && parsed.Scheme != "postgresql") || parsed.Host == "" {
+				errs = append(errs, fmt.Errorf("POSTGRES_REPLICA_DSNS: %q is not a postgres:// URL", redactURL(dsn)))
+			}
+		}
+	}
+
+	if c.DBConnect.Attempts < 1 {
+		errs = append(errs, errors.New("DB_CONNECT_ATTEMPTS must be at least 1"))
+	}
+	if c.DBConnect.InitialDelay <= 0 {
+		errs = append(errs, errors.New("DB_CONNECT_INITIAL_DELAY must be greater than zero"))
+	}
+	if c.DBConnect.MaxDelay < c.DBConnect.InitialDelay {
+		errs = append(errs, errors.New("DB_CONNECT_MAX_DELAY must not be less than DB_CONNECT_INITIAL_DELAY"))
+	}
+	if c.DBConnect.CheckInterval < 0 {
+		errs = append(errs, errors.New("DB_HEALTH_CHECK_INTERVAL must not be negative"))
+	}
+	if c.QueryLog.SlowThreshold < 0 {
+		errs = append(errs, errors.New("DB_SLOW_QUERY_THRESHOLD must not be negative"))
+	}
+	if c.QueryLog.WarnPerRequest < 0 {
+		errs = append(errs, errors.New("DB_QUERIES_PER_REQUEST_WARN must not be negative"))
+	}
+	if c.Health.Timeout <= 0 {
+		errs = append(errs, errors.New("HEALTH_CHECK_TIMEOUT must be greater than zero"))
+	}
+	if c.Health.DegradedLatency < 0 {
+		errs = append(errs, errors.New("HEALTH_DEGRADED_LATENCY must not be negative"))
+	}
+	if c.HTTPClient.Timeout <= 0 {
+		errs = append(errs, errors.New("HTTP_CLIENT_TIMEOUT must be greater than zero"))
+	}
+	if c.HTTPClient.MaxRetries < 0 {
+		errs = append(errs, errors.New("HTTP_CLIENT_MAX_RETRIES must not be negative"))
+	}
+	if c.HTTPClient.MaxRetries > 0 && (c.HTTPClient.RetryDelay <= 0 || c.HTTPClient.MaxRetryDelay < c.HTTPClient.RetryDelay) {
+		errs = append(errs, errors.New("HTTP_CLIENT_RETRY_DELAY must be greater than zero and at most HTTP_CLIENT_MAX_RETRY_DELAY"))
+	}
+	if c.HTTPClient.BreakerThreshold < 0 {
+		errs = append(errs, errors.New("HTTP_CLIENT_BREAKER_THRESHOLD must not be negative"))
+	}
+	if c.HTTPClient.BreakerThreshold > 0 && c.HTTPClient.BreakerCooldown <= 0 {
+		errs = append(errs, errors.New("HTTP_CLIENT_BREAKER_COOLDOWN must be greater than zero"))
+	}
+
+	if c.SQLite.Enabled {
+		if c.PostgresDB.Enabled {
+			errs = append(errs, errors.New("SQLITE_ENABLED and POSTGRES_ENABLED cannot both be true"))
+		}
+		if c.SQLite.Path == "" {
+			errs = append(errs, errors.New("SQLITE_PATH is required when SQLite is enabled"))
+		}
+	}
+
+	if c.IsProduction() {
+		if c.SQLite.Enabled {
+			errs = append(errs, errors.New("SQLITE_ENABLED is for development and tests and cannot be used in production"))
+		}
+		if insecureSecrets[c.JWTSecret] {
+			errs = append(errs, errors.New("JWT_SECRET must be changed from the default in production"))
+		} else if len(c.JWTSecret) < minProductionSecretLength {
+			errs = append(errs, fmt.Errorf("JWT_SECRET must be at least %d characters in production", minProductionSecretLength))
+		}
+		if c.MongoDB.Enabled && c.MongoDB.URI == "" && insecurePasswords[c.MongoDB.Password] {
+			errs = append(errs, errors.New("MONGODB_PASSWORD must be changed from the default in production"))
+		}
+		if c.PostgresDB.Enabled && insecurePasswords[c.PostgresDB.Password] {
+			errs = append(errs, errors.New("POSTGRES_PASSWORD must be changed from the default in production"))
+		}
+		if c.OAuth.Enabled && c.OAuth.SigningKeyFile == "" {
+			errs = append(errs, errors.New("OAUTH_SIGNING_KEY_FILE is required in production when OAuth is enabled"))
+		}
+		if c.OAuth.Enabled && strings.HasPrefix(c.OAuth.Issuer, "http:") {
+			errs = append(errs, errors.New("OAUTH_ISSUER must be an https URL in production"))
+		}
+		if c.Profiling.Enabled && c.Profiling.Token == "" {
+			errs = append(errs, errors.New("PPROF_TOKEN is required when PPROF_ENABLED is set in production"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of the configuration with secrets masked, safe to log
+func (c *Config) Redacted() Config {
+	redacted := *c
+	redacted.parseErrors = nil
+	redacted.JWTSecret = redact(c.JWTSecret)
+	redacted.SecurityLog.HTTPToken = redact(c.SecurityLog.HTTPToken)
+	redacted.Analytics.SegmentWriteKey = redact(c.Analytics.SegmentWriteKey)
+	redacted.Analytics.PostHogAPIKey = redact(c.Analytics.PostHogAPIKey)
+	redacted.Secrets.VaultToken = redact(c.Secrets.VaultToken)
+	redacted.Billing.StripeSecretKey = redact(c.Billing.StripeSecretKey)
+	redacted.Billing.StripeWebhookSecret = redact(c.Billing.StripeWebhookSecret)
+	redacted.Backup.EncryptionKey = redact(c.Backup.EncryptionKey)
+	redacted.BotDetection.ChallengeSecret = redact(c.BotDetection.ChallengeSecret)
+	if len(c.Webhooks.Secrets) > 0 {
+		redacted.Webhooks.Secrets = make([]string, len(c.Webhooks.Secrets))
+		for i, entry := range c.Webhooks.Secrets {
+			name, secret, _ := strings.Cut(entry, "=")
+			redacted.Webhooks.Secrets[i] = name + "=" + redact(secret)
+		}
+	}
+	redacted.MongoDB.Password = redact(c.MongoDB.Password)
+	redacted.MongoDB.URI = redactURL(c.MongoDB.URI)
+	redacted.PostgresDB.Password = redact(c.PostgresDB.Password)
+	redacted.Metrics.Token = redact(c.Metrics.Token)
+	redacted.Profiling.Token = redact(c.Profiling.Token)
+	redacted.Bootstrap.AdminPassword = redact(c.Bootstrap.AdminPassword)
+	if len(c.PostgresDB.ReplicaDSNs) > 0 {
+		redacted.PostgresDB.ReplicaDSNs = make([]string, len(c.PostgresDB.ReplicaDSNs))
+		for i, dsn := range c.PostgresDB.ReplicaDSNs {
+			redacted.PostgresDB.ReplicaDSNs[i] = redactURL(dsn)
+		}
+	}
+	return redacted
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "[REDACTED]"
+}
+
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "[REDACTED]"
+	}
+	if parsed.User != nil {
+		parsed.User = url.UserPassword(parsed.User.Username(), "REDACTED")
+	}
+	return parsed.String()
+}
+
+func validatePort(key, value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("%s: %q is not a valid port", key, value)
+	}
+	return nil
+}
+
+func validateURL(key, value string, schemes ...string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("%s: %q is not a valid URL", key, value)
+	}
+	if !oneOf(parsed.Scheme, schemes...) {
+		return fmt.Errorf("%s: scheme must be one of %s", key, strings.Join(schemes, ", "))
+	}
+	return nil
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, candidate := range allowed {
+		if strings.EqualFold(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// validAPIVersion reports whether version names an API version, such as v2
+func validAPIVersion(version string) bool {
+	number, ok := strings.CutPrefix(version, "v")
+	n, err := strconv.Atoi(number)
+	return ok && err == nil && n > 0 && number[0] != '0'
+}
+
+// validDeprecation reports whether entry is version=deprecated[/sunset] with a sunset after the deprecation
+func validDeprecation(entry string) bool {
+	version, dates, _ := strings.Cut(entry, "=")
+	deprecatedDate, sunsetDate, hasSunset := strings.Cut(dates, "/")
+	deprecated, err := time.Parse(time.DateOnly, deprecatedDate)
+	if err != nil || !validAPIVersion(version) {
+		return false
+	}
+	if hasSunset {
+		sunset, err := time.Parse(time.DateOnly, sunsetDate)
+		return err == nil && sunset.After(deprecated)
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+// Package contract checks HTTP responses against the Swagger 2.0 document generated by swag: the status
+// code must be declared for the operation, and a JSON body must match the declared schema, with no
+// undocumented fields. The contract command (go run ./cmd/contract) drives every documented operation
+// through the router and fails when a handler drifts from its annotations.
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Spec is a parsed Swagger 2.0 document
+type Spec struct {
+	basePath    string
+	operations  map[string]operation
+	definitions map[string]interface{}
+
+	mu        sync.Mutex
+	exercised map[string]bool
+}
+
+// operation is the declared responses of one method and path
+type operation struct {
+	responses map[string]interface{}
+}
+
+// Violation is a response that does not match its operation's contract
+type Violation struct {
+	Method   string
+	Path     string
+	Status   int
+	Problems []string
+}
+
+// Error implements error
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s %s returned %d: %s", v.Method, v.Path, v.Status, strings.Join(v.Problems, "; "))
+}
+
+// Load parses a Swagger 2.0 document such as docs/swagger.json
+func Load(swagger []byte) (*Spec, error) {
+	var doc struct {
+		BasePath    string                                       `json:"basePath"`
+		Paths       map[string]map[string]map[string]interface{} `json:"paths"`
+		Definitions map[string]interface{}                       `json:"definitions"`
+	}
+	if err := json.Unmarshal(swagger, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse swagger document: %w", err)
+	}
+
+	spec := &Spec{
+		basePath:    strings.TrimSuffix(doc.BasePath, "/"),
+		operations:  make(map[string]operation),
+		definitions: doc.Definitions,
+		exercised:   make(map[string]bool),
+	}
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			responses, _ := op["responses"].(map[string]interface{})
+			spec.operations[operationKey(method, path)] = operation{responses: responses}
+		}
+	}
+	return spec, nil
+}
+
+// operationKey identifies an operation by its upper-case method and documented path
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// documentedPath converts a gin route such as /api/v1/users/:id to its documented form /users/{id};
+// ok is false for routes outside the base path
+func (s *Spec) documentedPath(route string) (string, bool) {
+	path, ok := strings.CutPrefix(route, s.basePath)
+	if !ok || (path != "" && !strings.HasPrefix(path, "/")) {
+		return "", false
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), true
+}
+
+// Documents reports whether the gin route and method are in the document
+func (s *Spec) Documents(method, route string) bool {
+	path, ok := s.documentedPath(route)
+	if !ok {
+		return false
+	}
+	_, ok = s.operations[operationKey(method, path)]
+	return ok
+}
+
+// Validate checks a response to the gin route. It returns nil for routes that are not documented, and a
+// *Violation when the status is not declared or a JSON body does not match the declared schema.
+func (s *Spec) Validate(method, route string, status int, contentType string, body []byte) error {
+	path, ok := s.documentedPath(route)
+	if !ok {
+		return nil
+	}
+	key := operationKey(method, path)
+	op, ok := s.operations[key]
+	if !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.exercised[key] = true
+	s.mu.Unlock()
+
+	violation := &Violation{Method: strings.ToUpper(method), Path: path, Status: status}
+	response, ok := op.responses[strconv.Itoa(status)].(map[string]interface{})
+	if !ok {
+		response, ok = op.responses["default"].(map[string]interface{})
+	}
+	if !ok {
+		violation.Problems = append(violation.Problems, fmt.Sprintf("status %d is not documented", status))
+		return violation
+	}
+
+	schema, hasSchema := response["schema"]
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !hasSchema || mediaType != "application/json" || len(bytes.TrimSpace(body)) == 0 {
+		if hasSchema && status != 304 && len(bytes.TrimSpace(body)) == 0 {
+			violation.Problems = append(violation.Problems, "the documented body is missing")
+			return violation
+		}
+		return nil
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		violation.Problems = append(violation.Problems, "the body is not valid JSON: "+err.Error())
+		return violation
+	}
+	violation.Problems = s.check(schema, value, "body", violation.Problems)
+	if len(violation.Problems) > 0 {
+		return violation
+	}
+	return nil
+}
+
+// Unexercised lists the documented operations that no validated response has covered
+func (s *Spec) Unexercised() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key := range s.operations {
+		if !s.exercised[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Middleware validates every response of a documented route and passes violations to report. It buffers
+// each response body, so use it in tests and tools rather than in production.
+func (s *Spec) Middleware(report func(*Violation)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		err := s.Validate(c.Request.Method, c.FullPath(), writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes())
+		if violation, ok := err.(*Violation); ok {
+			report(violation)
+		}
+	}
+}
+
+// recordingWriter copies the response body as it is written
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,189 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// check validates value against a schema and appends a problem for each mismatch. It covers the subset
+// of JSON Schema that swag emits: $ref, allOf, type, properties, additionalProperties, items, and enum.
+// Objects without additionalProperties reject undocumented fields; null is accepted for arrays and
+// objects, which Go encodes from nil slices, maps, and pointers.
+func (s *Spec) check(schema interface{}, value interface{}, at string, problems []string) []string {
+	resolved, err := s.resolve(schema)
+	if err != nil {
+		return append(problems, fmt.Sprintf("%s: %v", at, err))
+	}
+
+	typ, _ := resolved["type"].(string)
+	if typ == "" {
+		if _, ok := resolved["properties"]; ok {
+			typ = "object"
+		}
+	}
+	if value == nil {
+		if typ == "" || typ == "array" || typ == "object" {
+			return problems
+		}
+		return append(problems, fmt.Sprintf("%s is null, want %s", at, typ))
+	}
+
+	if enum, ok := resolved["enum"].([]interface{}); ok && !inEnum(enum, value) {
+		problems = append(problems, fmt.Sprintf("%s is %v, want one of %v", at, value, enum))
+	}
+
+	switch typ {
+	case "":
+		return problems
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return append(problems, fmt.Sprintf("%s is %s, want object", at, kindOf(value)))
+		}
+		return s.checkObject(resolved, object, at, problems)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return append(problems, fmt.Sprintf("%s is %s, want array", at, kindOf(value)))
+		}
+		if items, ok := resolved["items"]; ok {
+			for i, item := range array {
+				problems = s.check(items, item, fmt.Sprintf("%s[%d]", at, i), problems)
+			}
+		}
+		return problems
+	case "integer":
+		number, ok := value.(json.Number)
+		if _, err := number.Int64(); !ok || err != nil {
+			return append(problems, fmt.Sprintf("%s is %s, want integer", at, kindOf(value)))
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return append(problems, fmt.Sprintf("%s is %s, want number", at, kindOf(value)))
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return append(problems, fmt.Sprintf("%s is %s, want string", at, kindOf(value)))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return append(problems, fmt.Sprintf("%s is %s, want boolean", at, kindOf(value)))
+		}
+	}
+	return problems
+}
+
+// checkObject validates the documented properties of an object and rejects undocumented ones
+func (s *Spec) checkObject(schema map[string]interface{}, object map[string]interface{}, at string, problems []string) []string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	additional, hasAdditional := schema["additionalProperties"]
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s is required", at, name))
+			}
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := properties[name]; ok {
+			problems = s.check(property, object[name], at+"."+name, problems)
+			continue
+		}
+		switch {
+		case !hasAdditional && len(properties) > 0:
+			problems = append(problems, fmt.Sprintf("%s.%s is not documented", at, name))
+		case hasAdditional && additional != false:
+			if additionalSchema, ok := additional.(map[string]interface{}); ok {
+				problems = s.check(additionalSchema, object[name], at+"."+name, problems)
+			}
+		case hasAdditional:
+			problems = append(problems, fmt.Sprintf("%s.%s is not documented", at, name))
+		}
+	}
+	return problems
+}
+
+// resolve follows $ref and merges allOf into a single schema; later members override the properties of
+// earlier ones, as swag emits {data=...} overrides
+func (s *Spec) resolve(schema interface{}) (map[string]interface{}, error) {
+	object, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid schema %v", schema)
+	}
+
+	if ref, ok := object["$ref"].(string); ok {
+		name, found := strings.CutPrefix(ref, "#/definitions/")
+		definition, exists := s.definitions[name]
+		if !found || !exists {
+			return nil, fmt.Errorf("unknown schema reference %q", ref)
+		}
+		return s.resolve(definition)
+	}
+
+	members, ok := object["allOf"].([]interface{})
+	if !ok {
+		return object, nil
+	}
+
+	merged := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	for _, member := range members {
+		resolved, err := s.resolve(member)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range resolved {
+			if key != "properties" {
+				merged[key] = value
+			}
+		}
+		if memberProperties, ok := resolved["properties"].(map[string]interface{}); ok {
+			for name, property := range memberProperties {
+				properties[name] = property
+			}
+		}
+	}
+	if len(properties) > 0 {
+		merged["properties"] = properties
+	}
+	return merged, nil
+}
+
+// inEnum reports whether value is one of the enum values
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, candidate := range enum {
+		if fmt.Sprint(candidate) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// kindOf names the JSON type of a decoded value
+func kindOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
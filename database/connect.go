@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"go-backend-template/config"
+	"go-backend-template/utils"
+)
+
+// ConnectWithRetry calls connect until it succeeds, waiting with exponential backoff and jitter between
+// attempts, so the service survives starting before its database is ready. It gives up after
+// cfg.Attempts attempts or when ctx is done.
+func ConnectWithRetry[T any](ctx context.Context, cfg config.DBConnectConfig, name string, logger utils.Logger, connect func() (T, error)) (T, error) {
+	var zero T
+	delay := cfg.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		logger.Info("Attempting to connect to "+name, "attempt", attempt)
+
+		conn, err := connect()
+		if err == nil {
+			return conn, nil
+		}
+		if attempt >= cfg.Attempts {
+			return zero, fmt.Errorf("failed to connect to %s after %d attempts: %w", name, attempt, err)
+		}
+
+		// Jitter spreads out instances that were started together
+		wait := delay/2 + rand.N(delay/2+1)
+		logger.Warn("Failed to connect to "+name+", retrying", "attempt", attempt, "delay", wait, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, cfg.MaxDelay)
+	}
+}
@@ -2,9 +2,11 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"gorm.io/driver/postgres"
@@ -12,17 +14,33 @@ import (
 	"gorm.io/gorm/logger"
 
 	"go-backend-template/config"
+	"go-backend-template/models"
 )
 
 // MongoDB represents MongoDB connection
 type MongoDB struct {
 	Client   *mongo.Client
 	Database *mongo.Database
+
+	// transactions is true when the deployment supports multi-document transactions
+	transactions bool
+
+	// pool tracks the connection pool statistics
+	pool *poolMonitor
+
+	// commands counts and times the commands once InstrumentQueries is called
+	commands *commandMonitor
 }
 
 // PostgresDB represents PostgreSQL connection
 type PostgresDB struct {
 	*gorm.DB
+
+	// replicas is nil unless read replicas are configured
+	replicas *replicaSet
+
+	// userIDType is POSTGRES_USER_ID_TYPE; empty means UserIDSerial
+	userIDType string
 }
 
 // NewMongoDB creates a new MongoDB connection
@@ -41,7 +59,18 @@ func NewMongoDB(cfg *config.MongoDBConfig) (*MongoDB, error) {
 		}
 	}
 
-	clientOptions := options.Client().ApplyURI(uri)
+	// Operations without a deadline of their own are bounded by the query timeout
+	pool := &poolMonitor{}
+	commands := &commandMonitor{}
+	clientOptions := options.Client().ApplyURI(uri).
+		SetMaxPoolSize(uint64(cfg.MaxPoolSize)).
+		SetMinPoolSize(uint64(cfg.MinPoolSize)).
+		SetMaxConnIdleTime(cfg.MaxConnIdleTime).
+		SetPoolMonitor(&event.PoolMonitor{Event: pool.handle}).
+		SetMonitor(commands.monitor())
+	if cfg.QueryTimeout > 0 {
+		clientOptions.SetTimeout(cfg.QueryTimeout)
+	}
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
@@ -49,14 +78,19 @@ func NewMongoDB(cfg *config.MongoDBConfig) (*MongoDB, error) {
 
 	// Ping the database
 	if err := client.Ping(ctx, nil); err != nil {
+		// Release the client's monitoring goroutines before the caller retries
+		client.Disconnect(context.Background())
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
 	database := client.Database(cfg.Database)
 
 	return &MongoDB{
-		Client:   client,
-		Database: database,
+		Client:       client,
+		Database:     database,
+		transactions: supportsTransactions(ctx, client),
+		pool:         pool,
+		commands:     commands,
 	}, nil
 }
 
@@ -72,57 +106,125 @@ func (m *MongoDB) Collection(name string) *mongo.Collection {
 	return m.Database.Collection(name)
 }
 
-// NewPostgresDB creates a new PostgreSQL connection
-func NewPostgresDB(cfg *config.PostgresDBConfig) (*PostgresDB, error) {
+// NewPostgresDB creates a new PostgreSQL connection that logs through gormLogger (see NewGormLogger)
+func NewPostgresDB(cfg *config.PostgresDBConfig, gormLogger logger.Interface) (*PostgresDB, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
 		cfg.Host, cfg.Username, cfg.Password, cfg.Database, cfg.Port, cfg.SSLMode)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: gormLogger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
 
+	if cfg.QueryTimeout > 0 {
+		if err := db.Use(queryTimeout(cfg.QueryTimeout)); err != nil {
+			return nil, fmt.Errorf("failed to register query timeout: %w", err)
+		}
+	}
+
 	// Configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get SQL DB: %w", err)
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	configurePool(sqlDB, cfg)
+
+	postgresDB := &PostgresDB{DB: db, userIDType: cfg.UserIDType}
+	if len(cfg.ReplicaDSNs) > 0 {
+		if postgresDB.replicas, err = openReplicas(cfg); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+	}
+
+	return postgresDB, nil
+}
+
+// sqliteModels are the tables created in SQLite; keep in sync with the PostgreSQL migrations
+var sqliteModels = []interface{}{
+	&models.User{},
+	&models.IdempotencyRecord{},
+	&models.Subscription{},
+	&models.UsageRecord{},
+	&models.Post{},
+	&models.Translation{},
+	&models.AnalyticsEvent{},
+	&models.OAuthClient{},
+	&models.OAuthCode{},
+	&models.OAuthConsent{},
+	&models.OAuthRevocation{},
+	&models.OAuthDeviceCode{},
+	&models.AuditLog{},
+	&models.WebhookEvent{},
+	&models.Operation{},
+	&models.Session{},
+}
+
+// NewSQLiteDB opens an embedded SQLite database for local development and tests; a path of ":memory:"
+// keeps it in memory. It returns the handle the SQL code paths use for PostgreSQL, so they run unchanged.
+// The migrations are PostgreSQL-specific, so the schema is created with AutoMigrate instead.
+func NewSQLiteDB(cfg *config.SQLiteConfig, gormLogger logger.Interface) (*PostgresDB, error) {
+	dialector, err := sqliteDialector(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: gormLogger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL DB: %w", err)
+	}
+	// SQLite allows a single writer, and every connection to :memory: would be a separate database
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(sqliteModels...); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
 
 	return &PostgresDB{DB: db}, nil
 }
 
-// Close closes the PostgreSQL connection
+// IsSQLite reports whether the handle was opened by NewSQLiteDB
+func (p *PostgresDB) IsSQLite() bool {
+	return p.Dialector.Name() == "sqlite"
+}
+
+// Close closes the PostgreSQL connection and the replica connections
 func (p *PostgresDB) Close() error {
 	sqlDB, err := p.DB.DB()
 	if err != nil {
 		return err
 	}
+	if p.replicas != nil {
+		return errors.Join(sqlDB.Close(), p.replicas.close())
+	}
 	return sqlDB.Close()
 }
 
-// AutoMigrate runs auto migration for given models
-func (p *PostgresDB) AutoMigrate(dst ...interface{}) error {
-	return p.DB.AutoMigrate(dst...)
-}
-
-// HealthCheck checks database connectivity
-func (m *MongoDB) HealthCheck() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// HealthCheck checks database connectivity, giving up after 5 seconds or when ctx is done
+func (m *MongoDB) HealthCheck(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 	return m.Client.Ping(ctx, nil)
 }
 
-// HealthCheck checks database connectivity
-func (p *PostgresDB) HealthCheck() error {
+// HealthCheck checks database connectivity, giving up after 5 seconds or when ctx is done
+func (p *PostgresDB) HealthCheck(ctx context.Context) error {
 	sqlDB, err := p.DB.DB()
 	if err != nil {
 		return err
 	}
-	return sqlDB.Ping()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
 }
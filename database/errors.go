@@ -0,0 +1,52 @@
+package database
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// pgUniqueViolation is the PostgreSQL SQLSTATE for a unique constraint violation
+const pgUniqueViolation = "23505"
+
+// mongoIndexNotFound is the MongoDB error code of a query that needs an index which does not exist, such
+// as $text without a text index
+const mongoIndexNotFound = 27
+
+// sqliteIndexName extracts the table and column from a SQLite unique constraint error
+var sqliteIndexName = regexp.MustCompile(`UNIQUE constraint failed: (\S+)`)
+
+// mongoIndexName extracts the index name from a MongoDB E11000 error message
+var mongoIndexName = regexp.MustCompile(`index: (\S+)`)
+
+// DuplicateKeyIndex reports whether err is a unique constraint violation from either backend and returns
+// the name of the violated PostgreSQL constraint or MongoDB index, such as "idx_users_email" or "email_1".
+// For SQLite, which does not report index names, it returns the column, such as "users.email".
+func DuplicateKeyIndex(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName, pgErr.Code == pgUniqueViolation
+	}
+	if err == nil {
+		return "", false
+	}
+	if match := sqliteIndexName.FindStringSubmatch(err.Error()); match != nil {
+		return match[1], true
+	}
+
+	if !mongo.IsDuplicateKeyError(err) {
+		return "", false
+	}
+	if match := mongoIndexName.FindStringSubmatch(err.Error()); match != nil {
+		return match[1], true
+	}
+	return "", true
+}
+
+// IsMissingIndex reports whether err is a MongoDB query failing for lack of the index it requires
+func IsMissingIndex(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoIndexNotFound)
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"go-backend-template/utils"
+)
+
+// gormLogger writes GORM's logs to the application logger, tagged with the request ID of the context
+type gormLogger struct {
+	logger utils.Logger
+	level  gormlogger.LogLevel
+}
+
+// NewGormLogger adapts logger for GORM at the given LOG_LEVEL: every statement is logged at debug,
+// failed statements at error. Slow statements are logged by InstrumentQueries instead.
+func NewGormLogger(logger utils.Logger, level string) gormlogger.Interface {
+	gormLevel := gormlogger.Warn
+	switch strings.ToLower(level) {
+	case "debug":
+		gormLevel = gormlogger.Info
+	case "error":
+		gormLevel = gormlogger.Error
+	}
+	return &gormLogger{logger: logger, level: gormLevel}
+}
+
+// LogMode implements gormlogger.Interface; db.Debug() uses it to log a session's statements
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return &gormLogger{logger: l.logger, level: level}
+}
+
+// Info implements gormlogger.Interface
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.Info(fmt.Sprintf(msg, data...), "request_id", requestIDFrom(ctx))
+	}
+}
+
+// Warn implements gormlogger.Interface
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.Warn(fmt.Sprintf(msg, data...), "request_id", requestIDFrom(ctx))
+	}
+}
+
+// Error implements gormlogger.Interface
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.Error(fmt.Sprintf(msg, data...), "request_id", requestIDFrom(ctx))
+	}
+}
+
+// Trace implements gormlogger.Interface. Missing records are expected by callers and not logged as errors.
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		l.logger.Error("Query failed",
+			"duration", time.Since(begin),
+			"rows", rows,
+			"sql", sql,
+			"request_id", requestIDFrom(ctx),
+			"error", err,
+		)
+	case l.level >= gormlogger.Info:
+		sql, rows := fc()
+		l.logger.Debug("Query",
+			"duration", time.Since(begin),
+			"rows", rows,
+			"sql", sql,
+			"request_id", requestIDFrom(ctx),
+		)
+	}
+}
+
+// ParamsFilter implements gorm.ParamsFilter so statements are logged without their values, which may be
+// password hashes or personal data
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// userIndexes are the indexes of the users collection. Unique email and username make concurrent
+// registrations of the same account fail with a duplicate key error; the text index serves search,
+// last_login_at the active user statistics, and the partial deleted_at index counts the soft-deleted users.
+var userIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+	{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+	{Keys: bson.D{{Key: "last_login_at", Value: 1}}},
+	{
+		Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{"deleted_at": bson.M{"$type": "date"}}),
+	},
+	{Keys: bson.D{
+		{Key: "first_name", Value: "text"},
+		{Key: "last_name", Value: "text"},
+		{Key: "email", Value: "text"},
+		{Key: "username", Value: "text"},
+	}},
+}
+
+// postIndexes are the indexes of the posts collection, serving the newest-first listing of all posts and
+// of one owner's posts
+var postIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}},
+}
+
+// EnsureIndexes creates any missing indexes; existing identical indexes are left untouched. It fails if
+// existing documents violate a unique index, for example duplicate emails created before it existed.
+func (m *MongoDB) EnsureIndexes(ctx context.Context) error {
+	if _, err := m.Collection("users").Indexes().CreateMany(ctx, userIndexes); err != nil {
+		return fmt.Errorf("failed to create users indexes: %w", err)
+	}
+	if _, err := m.Collection("posts").Indexes().CreateMany(ctx, postIndexes); err != nil {
+		return fmt.Errorf("failed to create posts indexes: %w", err)
+	}
+	return nil
+}
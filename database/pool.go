@@ -0,0 +1,109 @@
+package database
+
+import (
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+
+	"go-backend-template/config"
+)
+
+// PoolStats is a snapshot of a connection pool
+type PoolStats struct {
+	Node         string
+	MaxOpen      int
+	Open         int
+	InUse        int
+	Idle         int
+	WaitCount    int64
+	WaitDuration time.Duration
+}
+
+// configurePool applies the configured pool limits to a PostgreSQL pool
+func configurePool(db *sql.DB, cfg *config.PostgresDBConfig) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
+// sqlPoolStats converts the statistics of a database/sql pool
+func sqlPoolStats(node string, db *sql.DB) PoolStats {
+	stats := db.Stats()
+	return PoolStats{
+		Node:         node,
+		MaxOpen:      stats.MaxOpenConnections,
+		Open:         stats.OpenConnections,
+		InUse:        stats.InUse,
+		Idle:         stats.Idle,
+		WaitCount:    stats.WaitCount,
+		WaitDuration: stats.WaitDuration,
+	}
+}
+
+// PoolStats returns the statistics of the primary's pool followed by those of each replica
+func (p *PostgresDB) PoolStats() []PoolStats {
+	sqlDB, err := p.DB.DB()
+	if err != nil {
+		return nil
+	}
+
+	stats := []PoolStats{sqlPoolStats("primary", sqlDB)}
+	if p.replicas != nil {
+		for _, node := range p.replicas.nodes {
+			stats = append(stats, sqlPoolStats(node.name, node.db))
+		}
+	}
+	return stats
+}
+
+// poolMonitor keeps MongoDB pool statistics from the driver's pool events, summed over all servers
+type poolMonitor struct {
+	maxOpen   atomic.Int64
+	open      atomic.Int64
+	inUse     atomic.Int64
+	waitCount atomic.Int64
+	waitNanos atomic.Int64
+}
+
+// handle updates the statistics from a pool event. A checkout counts as a wait when no connection was
+// idle as it started, and every checkout's duration counts toward the wait time.
+func (m *poolMonitor) handle(evt *event.PoolEvent) {
+	switch evt.Type {
+	case event.PoolCreated:
+		if evt.PoolOptions != nil {
+			m.maxOpen.Store(int64(evt.PoolOptions.MaxPoolSize))
+		}
+	case event.ConnectionCreated:
+		m.open.Add(1)
+	case event.ConnectionClosed:
+		m.open.Add(-1)
+	case event.GetStarted:
+		if m.open.Load()-m.inUse.Load() <= 0 {
+			m.waitCount.Add(1)
+		}
+	case event.GetSucceeded:
+		m.inUse.Add(1)
+		m.waitNanos.Add(int64(evt.Duration))
+	case event.GetFailed:
+		m.waitNanos.Add(int64(evt.Duration))
+	case event.ConnectionReturned:
+		m.inUse.Add(-1)
+	}
+}
+
+// PoolStats returns the statistics of the MongoDB connection pools, summed over the servers
+func (m *MongoDB) PoolStats() PoolStats {
+	open, inUse := int(m.pool.open.Load()), int(m.pool.inUse.Load())
+	return PoolStats{
+		Node:         "all",
+		MaxOpen:      int(m.pool.maxOpen.Load()),
+		Open:         open,
+		InUse:        inUse,
+		Idle:         max(open-inUse, 0),
+		WaitCount:    m.pool.waitCount.Load(),
+		WaitDuration: time.Duration(m.pool.waitNanos.Load()),
+	}
+}
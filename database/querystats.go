@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-backend-template/utils"
+)
+
+// requestQueriesKey is the context key of a request's query counter
+type requestQueriesKey struct{}
+
+// RequestQueries counts the database queries run on behalf of one request
+type RequestQueries struct {
+	RequestID string
+	count     atomic.Int64
+	nanos     atomic.Int64
+}
+
+// WithRequestQueries returns a context whose queries are counted by the returned counter; the request ID
+// is logged with the context's slow queries
+func WithRequestQueries(ctx context.Context, requestID string) (context.Context, *RequestQueries) {
+	queries := &RequestQueries{RequestID: requestID}
+	return context.WithValue(ctx, requestQueriesKey{}, queries), queries
+}
+
+// Count returns the number of queries run so far
+func (q *RequestQueries) Count() int64 {
+	return q.count.Load()
+}
+
+// Duration returns the time spent in the queries run so far
+func (q *RequestQueries) Duration() time.Duration {
+	return time.Duration(q.nanos.Load())
+}
+
+// recordQuery counts a query against the request of ctx, if any, and returns the request ID
+func recordQuery(ctx context.Context, elapsed time.Duration) string {
+	if queries, ok := ctx.Value(requestQueriesKey{}).(*RequestQueries); ok {
+		queries.count.Add(1)
+		queries.nanos.Add(int64(elapsed))
+	}
+	return requestIDFrom(ctx)
+}
+
+// requestIDFrom returns the request ID of ctx, or "" outside a request
+func requestIDFrom(ctx context.Context) string {
+	if queries, ok := ctx.Value(requestQueriesKey{}).(*RequestQueries); ok && queries.RequestID != "" {
+		return queries.RequestID
+	}
+	return utils.ScopeFrom(ctx).RequestID
+}
+
+// RouteQueries aggregates the queries of the requests to one route
+type RouteQueries struct {
+	Method     string
+	Route      string
+	Requests   int64
+	Queries    int64
+	MaxQueries int64
+	Duration   time.Duration
+}
+
+// QueryStats aggregates the query counts of finished requests per route, so routes that run a query per
+// item (N+1) stand out
+type QueryStats struct {
+	mu     sync.Mutex
+	routes map[string]*RouteQueries
+}
+
+// NewQueryStats creates an empty aggregate
+func NewQueryStats() *QueryStats {
+	return &QueryStats{routes: make(map[string]*RouteQueries)}
+}
+
+// Record adds a finished request's queries to its route
+func (s *QueryStats) Record(method, route string, queries *RequestQueries) {
+	count := queries.Count()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := method + " " + route
+	stats, ok := s.routes[key]
+	if !ok {
+		stats = &RouteQueries{Method: method, Route: route}
+		s.routes[key] = stats
+	}
+	stats.Requests++
+	stats.Queries += count
+	stats.MaxQueries = max(stats.MaxQueries, count)
+	stats.Duration += queries.Duration()
+}
+
+// Snapshot returns the aggregates sorted by route and method
+func (s *QueryStats) Snapshot() []RouteQueries {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make([]RouteQueries, 0, len(s.routes))
+	for _, stats := range s.routes {
+		snapshot = append(snapshot, *stats)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Route != snapshot[j].Route {
+			return snapshot[i].Route < snapshot[j].Route
+		}
+		return snapshot[i].Method < snapshot[j].Method
+	})
+	return snapshot
+}
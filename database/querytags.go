@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// queryTagKey is the statement setting holding the connection pool a tagged statement replaced
+const queryTagKey = "query_tagger"
+
+// maxTaggedRequestID bounds the request ID written into statements; clients choose their own IDs
+const maxTaggedRequestID = 64
+
+// queryTagger is a GORM plugin that prefixes every statement run for a request with a comment carrying the
+// request ID, such as /* request_id=... */ SELECT ..., so the statement can be matched with the request in
+// the database's own logs, pg_stat_activity, and pg_stat_statements samples. The comment is added when the
+// statement is sent, through a connection pool wrapped for the duration of the statement, so statements
+// GORM builds and raw SQL are tagged alike.
+type queryTagger struct{}
+
+// Name implements gorm.Plugin
+func (queryTagger) Name() string {
+	return "query_tagger"
+}
+
+// Initialize registers the callbacks around every kind of statement; writes are tagged inside the
+// transaction GORM opens for them, as it begins and commits it on the statement's pool
+func (queryTagger) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().After("gorm:begin_transaction").Before("gorm:create").Register("query_tagger:start", startQueryTag),
+		callbacks.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").Register("query_tagger:stop", stopQueryTag),
+		callbacks.Query().Before("gorm:query").Register("query_tagger:start", startQueryTag),
+		callbacks.Query().After("gorm:query").Register("query_tagger:stop", stopQueryTag),
+		callbacks.Update().After("gorm:begin_transaction").Before("gorm:update").Register("query_tagger:start", startQueryTag),
+		callbacks.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("query_tagger:stop", stopQueryTag),
+		callbacks.Delete().After("gorm:begin_transaction").Before("gorm:delete").Register("query_tagger:start", startQueryTag),
+		callbacks.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").Register("query_tagger:stop", stopQueryTag),
+		callbacks.Row().Before("gorm:row").Register("query_tagger:start", startQueryTag),
+		callbacks.Row().After("gorm:row").Register("query_tagger:stop", stopQueryTag),
+		callbacks.Raw().Before("gorm:raw").Register("query_tagger:start", startQueryTag),
+		callbacks.Raw().After("gorm:raw").Register("query_tagger:stop", stopQueryTag),
+	)
+}
+
+// startQueryTag sends the statement through a pool that prefixes it with the request ID of its context
+func startQueryTag(db *gorm.DB) {
+	requestID := sanitizeRequestID(requestIDFrom(db.Statement.Context))
+	if requestID == "" {
+		return
+	}
+	db.Statement.Settings.Store(queryTagKey, db.Statement.ConnPool)
+	db.Statement.ConnPool = taggedConnPool{ConnPool: db.Statement.ConnPool, comment: "/* request_id=" + requestID + " */ "}
+}
+
+// stopQueryTag restores the statement's own pool, which GORM commits or rolls back the transaction on
+func stopQueryTag(db *gorm.DB) {
+	if pool, ok := db.Statement.Settings.LoadAndDelete(queryTagKey); ok {
+		db.Statement.ConnPool = pool.(gorm.ConnPool)
+	}
+}
+
+// sanitizeRequestID keeps the characters of a request ID that cannot end the comment it is written in,
+// as the ID may come from the client's X-Request-ID header
+func sanitizeRequestID(requestID string) string {
+	requestID = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.', r == ':':
+			return r
+		}
+		return -1
+	}, requestID)
+	if len(requestID) > maxTaggedRequestID {
+		requestID = requestID[:maxTaggedRequestID]
+	}
+	return requestID
+}
+
+// taggedConnPool prefixes every statement it sends with a comment
+type taggedConnPool struct {
+	gorm.ConnPool
+	comment string
+}
+
+func (p taggedConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.ConnPool.PrepareContext(ctx, p.comment+query)
+}
+
+func (p taggedConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.ConnPool.ExecContext(ctx, p.comment+query, args...)
+}
+
+func (p taggedConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.ConnPool.QueryContext(ctx, p.comment+query, args...)
+}
+
+func (p taggedConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.ConnPool.QueryRowContext(ctx, p.comment+query, args...)
+}
+
+// TagQueries prefixes the statements run for a request with its request ID (see queryTagger)
+func (p *PostgresDB) TagQueries() error {
+	return p.Use(queryTagger{})
+}
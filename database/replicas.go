@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+
+	"go-backend-template/config"
+)
+
+// replica is a read-only PostgreSQL node; healthy is updated by CheckReplicas
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// replicaSet balances reads over the healthy replicas in turn
+type replicaSet struct {
+	nodes []*replica
+	next  atomic.Uint64
+}
+
+// openReplicas opens a pool per replica DSN with the primary's pool limits. A replica that is down is
+// marked unhealthy instead of failing startup, and reads fall back to the primary until it is back.
+func openReplicas(cfg *config.PostgresDBConfig) (*replicaSet, error) {
+	set := &replicaSet{}
+	for i, dsn := range cfg.ReplicaDSNs {
+		db, err := sql.Open("pgx", dsn)
+		if err != nil {
+			set.close()
+			return nil, fmt.Errorf("failed to open PostgreSQL replica %d: %w", i+1, err)
+		}
+		configurePool(db, cfg)
+
+		node := &replica{name: fmt.Sprintf("replica-%d", i+1), db: db}
+		node.check(context.Background())
+		set.nodes = append(set.nodes, node)
+	}
+	return set, nil
+}
+
+// pick returns the next healthy replica, or nil when none is
+func (s *replicaSet) pick() *replica {
+	start := s.next.Add(1)
+	for i := range s.nodes {
+		node := s.nodes[(start+uint64(i))%uint64(len(s.nodes))]
+		if node.healthy.Load() {
+			return node
+		}
+	}
+	return nil
+}
+
+// close closes every replica pool
+func (s *replicaSet) close() error {
+	var errs []error
+	for _, node := range s.nodes {
+		errs = append(errs, node.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Replica returns a handle that reads from one of the healthy replicas, or from the primary when no
+// replicas are configured or none is healthy. Replicas lag behind the primary, so use it only for reads
+// that tolerate slightly stale data, and never for writes or inside a transaction.
+func (p *PostgresDB) Replica() *PostgresDB {
+	if p.replicas == nil {
+		return p
+	}
+	node := p.replicas.pick()
+	if node == nil {
+		return p
+	}
+
+	// Passing the context makes the session clone the statement instead of sharing p's
+	session := p.DB.Session(&gorm.Session{Context: p.Statement.Context})
+	session.Statement.ConnPool = node.db
+	return &PostgresDB{DB: session, userIDType: p.userIDType}
+}
+
+// ReplicaNames returns the names of the configured replicas in order
+func (p *PostgresDB) ReplicaNames() []string {
+	if p.replicas == nil {
+		return nil
+	}
+
+	names := make([]string, len(p.replicas.nodes))
+	for i, node := range p.replicas.nodes {
+		names[i] = node.name
+	}
+	return names
+}
+
+// CheckReplica pings the named replica, giving up after 5 seconds or when ctx is done, and takes it out of
+// rotation while it fails
+func (p *PostgresDB) CheckReplica(ctx context.Context, name string) error {
+	if p.replicas != nil {
+		for _, node := range p.replicas.nodes {
+			if node.name == name {
+				return node.check(ctx)
+			}
+		}
+	}
+	return fmt.Errorf("unknown PostgreSQL replica %q", name)
+}
+
+// check pings the replica and updates its health
+func (r *replica) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	err := r.db.PingContext(ctx)
+	r.healthy.Store(err == nil)
+	return err
+}
+
+// CheckReplicas checks every replica like CheckReplica. The result maps each replica's name to its error.
+func (p *PostgresDB) CheckReplicas(ctx context.Context) map[string]error {
+	if p.replicas == nil {
+		return nil
+	}
+
+	results := make(map[string]error, len(p.replicas.nodes))
+	for _, node := range p.replicas.nodes {
+		results[node.name] = node.check(ctx)
+	}
+	return results
+}
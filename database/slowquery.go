@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"gorm.io/gorm"
+
+	"go-backend-template/utils"
+)
+
+// queryStartKey is the statement setting holding the start time of a running statement
+const queryStartKey = "query_logger_start"
+
+// queryLogger is a GORM plugin that counts every statement against the request of its context and logs
+// statements slower than the threshold
+type queryLogger struct {
+	threshold time.Duration
+	logger    utils.Logger
+}
+
+// Name implements gorm.Plugin
+func (l queryLogger) Name() string {
+	return "query_logger"
+}
+
+// Initialize registers the callbacks around every kind of statement
+func (l queryLogger) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("query_logger:start", startQueryClock),
+		callbacks.Create().After("gorm:create").Register("query_logger:stop", l.stop),
+		callbacks.Query().Before("gorm:query").Register("query_logger:start", startQueryClock),
+		callbacks.Query().After("gorm:query").Register("query_logger:stop", l.stop),
+		callbacks.Update().Before("gorm:update").Register("query_logger:start", startQueryClock),
+		callbacks.Update().After("gorm:update").Register("query_logger:stop", l.stop),
+		callbacks.Delete().Before("gorm:delete").Register("query_logger:start", startQueryClock),
+		callbacks.Delete().After("gorm:delete").Register("query_logger:stop", l.stop),
+		callbacks.Row().Before("gorm:row").Register("query_logger:start", startQueryClock),
+		callbacks.Row().After("gorm:row").Register("query_logger:stop", l.stop),
+		callbacks.Raw().Before("gorm:raw").Register("query_logger:start", startQueryClock),
+		callbacks.Raw().After("gorm:raw").Register("query_logger:stop", l.stop),
+	)
+}
+
+// startQueryClock records when a statement started
+func startQueryClock(db *gorm.DB) {
+	db.Statement.Settings.Store(queryStartKey, time.Now())
+}
+
+// stop counts a finished statement and logs it when it was slow; the SQL is logged without its values
+func (l queryLogger) stop(db *gorm.DB) {
+	value, ok := db.Statement.Settings.LoadAndDelete(queryStartKey)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(value.(time.Time))
+
+	requestID := recordQuery(db.Statement.Context, elapsed)
+	if l.threshold > 0 && elapsed >= l.threshold {
+		l.logger.Warn("Slow query",
+			"database", db.Dialector.Name(),
+			"duration", elapsed,
+			"rows", db.Statement.RowsAffected,
+			"sql", db.Statement.SQL.String(),
+			"request_id", requestID,
+			"error", db.Error,
+		)
+	}
+}
+
+// InstrumentQueries counts every statement against the request of its context and logs statements that
+// take longer than threshold; a threshold of 0 only counts
+func (p *PostgresDB) InstrumentQueries(threshold time.Duration, logger utils.Logger) error {
+	return p.Use(queryLogger{threshold: threshold, logger: logger})
+}
+
+// commandMonitor counts MongoDB commands and logs the slow ones once InstrumentQueries enables it. It is
+// installed when the client is created, as the driver does not allow adding monitors later. The driver
+// cannot add a comment to every command the way queryTagger does for SQL, so the commands of a request are
+// logged at debug with its request ID and the server's connection ID, which the MongoDB logs show as
+// "ctx":"conn<ID>".
+type commandMonitor struct {
+	enabled   atomic.Bool
+	threshold time.Duration
+	logger    utils.Logger
+}
+
+// monitor returns the driver monitor that reports finished commands
+func (m *commandMonitor) monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: m.started,
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			m.finished(ctx, evt.CommandFinishedEvent, "")
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			m.finished(ctx, evt.CommandFinishedEvent, evt.Failure)
+		},
+	}
+}
+
+// started logs a command run for a request at debug
+func (m *commandMonitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	if !m.enabled.Load() {
+		return
+	}
+	if requestID := requestIDFrom(ctx); requestID != "" {
+		m.logger.Debug("MongoDB command",
+			"command", evt.CommandName,
+			"db", evt.DatabaseName,
+			"server_connection", serverConnection(evt.ServerConnectionID64),
+			"request_id", requestID,
+		)
+	}
+}
+
+// finished counts a finished command and logs it when it was slow
+func (m *commandMonitor) finished(ctx context.Context, evt event.CommandFinishedEvent, failure string) {
+	if !m.enabled.Load() {
+		return
+	}
+
+	requestID := recordQuery(ctx, evt.Duration)
+	if m.threshold > 0 && evt.Duration >= m.threshold {
+		m.logger.Warn("Slow query",
+			"database", "mongodb",
+			"duration", evt.Duration,
+			"command", evt.CommandName,
+			"db", evt.DatabaseName,
+			"server_connection", serverConnection(evt.ServerConnectionID64),
+			"request_id", requestID,
+			"error", failure,
+		)
+	}
+}
+
+// serverConnection returns the connection name the MongoDB server logs a command under, or "" when the
+// server did not report its connection ID
+func serverConnection(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return "conn" + strconv.FormatInt(*id, 10)
+}
+
+// InstrumentQueries counts every command against the request of its context and logs commands that
+// take longer than threshold; a threshold of 0 only counts
+func (m *MongoDB) InstrumentQueries(threshold time.Duration, logger utils.Logger) {
+	m.commands.threshold = threshold
+	m.commands.logger = logger
+	m.commands.enabled.Store(true)
+}
@@ -0,0 +1,13 @@
+//go:build sqlite
+
+package database
+
+import (
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteDialector opens path with the pure-Go SQLite driver, which also builds with CGO_ENABLED=0
+func sqliteDialector(path string) (gorm.Dialector, error) {
+	return sqlite.Open(path), nil
+}
@@ -0,0 +1,14 @@
+//go:build !sqlite
+
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// sqliteDialector fails in default builds, which leave the SQLite driver out of production binaries
+func sqliteDialector(path string) (gorm.Dialector, error) {
+	return nil, errors.New("SQLite support is not compiled in; build with -tags sqlite")
+}
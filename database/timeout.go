@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// queryTimeoutKey is the statement setting holding the state of a running statement's timeout
+const queryTimeoutKey = "query_timeout"
+
+// runningTimeout remembers the statement's own context, which chained statements reuse and so must be restored
+type runningTimeout struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// queryTimeout is a GORM plugin that bounds each statement by a timeout on top of its own context, so a
+// slow query fails even when the request context has no deadline
+type queryTimeout time.Duration
+
+// Name implements gorm.Plugin
+func (t queryTimeout) Name() string {
+	return "query_timeout"
+}
+
+// Initialize registers the callbacks around every statement that completes inside its callback chain;
+// Row and Rows are left alone because their results are read after the chain returns
+func (t queryTimeout) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("query_timeout:start", t.start),
+		callbacks.Create().After("gorm:create").Register("query_timeout:stop", stopQueryTimeout),
+		callbacks.Query().Before("gorm:query").Register("query_timeout:start", t.start),
+		callbacks.Query().After("gorm:query").Register("query_timeout:stop", stopQueryTimeout),
+		callbacks.Update().Before("gorm:update").Register("query_timeout:start", t.start),
+		callbacks.Update().After("gorm:update").Register("query_timeout:stop", stopQueryTimeout),
+		callbacks.Delete().Before("gorm:delete").Register("query_timeout:start", t.start),
+		callbacks.Delete().After("gorm:delete").Register("query_timeout:stop", stopQueryTimeout),
+		callbacks.Raw().Before("gorm:raw").Register("query_timeout:start", t.start),
+		callbacks.Raw().After("gorm:raw").Register("query_timeout:stop", stopQueryTimeout),
+	)
+}
+
+// start replaces the statement context with one that expires after the timeout
+func (t queryTimeout) start(db *gorm.DB) {
+	ctx, cancel := context.WithTimeout(db.Statement.Context, time.Duration(t))
+	db.Statement.Settings.Store(queryTimeoutKey, runningTimeout{parent: db.Statement.Context, cancel: cancel})
+	db.Statement.Context = ctx
+}
+
+// stopQueryTimeout releases the timer of a finished statement and restores its own context
+func stopQueryTimeout(db *gorm.DB) {
+	if value, ok := db.Statement.Settings.LoadAndDelete(queryTimeoutKey); ok {
+		running := value.(runningTimeout)
+		running.cancel()
+		db.Statement.Context = running.parent
+	}
+}
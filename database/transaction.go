@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+// WithTransaction runs fn in a transaction that is committed if fn returns nil and rolled back otherwise.
+// Every query inside fn must use tx instead of p.
+func (p *PostgresDB) WithTransaction(ctx context.Context, fn func(tx *PostgresDB) error) error {
+	return p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&PostgresDB{DB: tx, userIDType: p.userIDType})
+	})
+}
+
+// WithTransaction runs fn in a multi-document transaction that is committed if fn returns nil and aborted
+// otherwise; transient errors are retried, so fn may run more than once. Every operation inside fn must
+// use the context it receives. Standalone servers do not support transactions, so there fn runs once
+// without one and its writes are not atomic.
+func (m *MongoDB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !m.transactions {
+		return fn(ctx)
+	}
+
+	session, err := m.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+	return err
+}
+
+// supportsTransactions reports whether the server is a replica set member or mongos
+func supportsTransactions(ctx context.Context, client *mongo.Client) bool {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid"
+}
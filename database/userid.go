@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Types of the users.id column, chosen with POSTGRES_USER_ID_TYPE
+const (
+	// UserIDSerial numbers users from a sequence; SQLite always uses it
+	UserIDSerial = "bigserial"
+	// UserIDUUID assigns random UUIDs from gen_random_uuid(), which do not reveal how many users exist
+	UserIDUUID = "uuid"
+)
+
+// UserIDType returns the type of users.id the database was opened with
+func (p *PostgresDB) UserIDType() string {
+	if p.userIDType == "" {
+		return UserIDSerial
+	}
+	return p.userIDType
+}
+
+// ValidUserID reports whether id has the form of a users.id of this database, so malformed IDs can be
+// rejected before they reach a query, where PostgreSQL would fail on them
+func (p *PostgresDB) ValidUserID(id string) bool {
+	if p.UserIDType() == UserIDUUID {
+		_, err := uuid.Parse(id)
+		return err == nil
+	}
+	_, err := strconv.ParseUint(id, 10, 63)
+	return err == nil
+}
+
+// CheckUserIDType returns an error unless users.id has the configured type, which happens when the
+// migrations ran with a different POSTGRES_USER_ID_TYPE
+func (p *PostgresDB) CheckUserIDType(ctx context.Context) error {
+	var dataType string
+	err := p.WithContext(ctx).Raw(`SELECT data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'id'`).Scan(&dataType).Error
+	if err != nil {
+		return fmt.Errorf("failed to read the type of users.id: %w", err)
+	}
+
+	expected := "bigint"
+	if p.UserIDType() == UserIDUUID {
+		expected = "uuid"
+	}
+	if dataType != expected {
+		return fmt.Errorf("users.id is %s but POSTGRES_USER_ID_TYPE is %s; migration 13 converts the column "+
+			"only when it runs with the matching setting", dataType, p.UserIDType())
+	}
+	return nil
+}
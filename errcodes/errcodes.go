@@ -0,0 +1,175 @@
+// Package errcodes registers the stable, machine-readable codes of the API's error responses. Each code
+// stands for one message key of the translation files and has the HTTP status it is answered with; clients
+// branch on the code, which never changes, rather than on the message, which is translated and may be
+// reworded. A code is never reused for another error once released.
+package errcodes
+
+import (
+	"net/http"
+)
+
+// Codes of the error responses, grouped by the area they come from
+const (
+	// Malformed or refused requests
+	BadRequest         = "REQ001"
+	ValidationFailed   = "REQ002"
+	NotFound           = "REQ003"
+	PreconditionFailed = "REQ004"
+	RequestTooLarge    = "REQ005"
+	RequestTimeout     = "REQ006"
+	RateLimited        = "REQ007"
+	RequestRejected    = "REQ008"
+	ReadOnly           = "REQ009"
+	MethodNotAllowed   = "REQ010"
+
+	// Server-side failures
+	Internal    = "SYS001"
+	Unavailable = "SYS002"
+	Overloaded  = "SYS003"
+
+	// Authentication and authorization
+	AuthorizationRequired = "AUTH001"
+	InvalidAuthHeader     = "AUTH002"
+	InvalidToken          = "AUTH003"
+	InvalidCredentials    = "AUTH004"
+	TooManyLoginAttempts  = "AUTH005"
+	Unauthorized          = "AUTH006"
+	Forbidden             = "AUTH007"
+	InsufficientRole      = "AUTH008"
+	InvalidRefreshToken   = "AUTH009"
+	SessionNotFound       = "AUTH010"
+
+	// Users
+	UserNotFound     = "USER001"
+	EmailExists      = "USER002"
+	UsernameExists   = "USER003"
+	LastSuperadmin   = "USER004"
+	TooManyBulkUsers = "USER005"
+
+	// Organizations
+	OrganizationRequired  = "ORG001"
+	NotOrganizationMember = "ORG002"
+
+	// Billing and quotas
+	PlanRequired  = "BILL001"
+	PlanNotFound  = "BILL002"
+	QuotaExceeded = "BILL003"
+
+	// Idempotency keys
+	IdempotencyKeyReused  = "IDEM001"
+	IdempotencyInProgress = "IDEM002"
+
+	// OpenID Connect provider
+	InvalidAuthorizationRequest = "OAUTH001"
+	OAuthClientNotFound         = "OAUTH002"
+	OAuthConsentNotFound        = "OAUTH003"
+	DeviceCodeNotFound          = "OAUTH004"
+
+	// Inbound webhooks
+	WebhookNotFound         = "HOOK001"
+	WebhookInProgress       = "HOOK002"
+	WebhookAlreadyProcessed = "HOOK003"
+
+	// Background operations, backups, translations, and setup
+	OperationNotFound     = "OP001"
+	BackupInvalid         = "BACKUP001"
+	UnknownTranslationKey = "I18N001"
+	SetupUnavailable      = "SETUP001"
+)
+
+// Entry is a registered error: its code, the message key it is answered with, and its HTTP status. A few
+// errors are also answered with a related status, such as internal_error with 502 when an upstream fails.
+type Entry struct {
+	Code   string
+	Key    string
+	Status int
+}
+
+// registry lists every error in code order; add new errors at the end of their area
+var registry = []Entry{
+	{BadRequest, "bad_request", http.StatusBadRequest},
+	{ValidationFailed, "validation_error", http.StatusBadRequest},
+	{NotFound, "not_found", http.StatusNotFound},
+	{PreconditionFailed, "precondition_failed", http.StatusPreconditionFailed},
+	{RequestTooLarge, "request_too_large", http.StatusRequestEntityTooLarge},
+	{RequestTimeout, "request_timeout", http.StatusRequestTimeout},
+	{RateLimited, "rate_limit_exceeded", http.StatusTooManyRequests},
+	{RequestRejected, "request_rejected", http.StatusForbidden},
+	{ReadOnly, "read_only_mode", http.StatusServiceUnavailable},
+	{MethodNotAllowed, "method_not_allowed", http.StatusMethodNotAllowed},
+
+	{Internal, "internal_error", http.StatusInternalServerError},
+	{Unavailable, "service_unavailable", http.StatusServiceUnavailable},
+	{Overloaded, "server_overloaded", http.StatusServiceUnavailable},
+
+	{AuthorizationRequired, "authorization_required", http.StatusUnauthorized},
+	{InvalidAuthHeader, "invalid_authorization_header", http.StatusUnauthorized},
+	{InvalidToken, "invalid_token", http.StatusUnauthorized},
+	{InvalidCredentials, "invalid_credentials", http.StatusUnauthorized},
+	{TooManyLoginAttempts, "too_many_login_attempts", http.StatusTooManyRequests},
+	{Unauthorized, "unauthorized", http.StatusUnauthorized},
+	{Forbidden, "forbidden", http.StatusForbidden},
+	{InsufficientRole, "insufficient_permissions", http.StatusForbidden},
+	{InvalidRefreshToken, "invalid_refresh_token", http.StatusUnauthorized},
+	{SessionNotFound, "session_not_found", http.StatusNotFound},
+
+	{UserNotFound, "user_not_found", http.StatusNotFound},
+	{EmailExists, "email_exists", http.StatusConflict},
+	{UsernameExists, "username_exists", http.StatusConflict},
+	{LastSuperadmin, "last_superadmin", http.StatusConflict},
+	{TooManyBulkUsers, "bulk_too_many_users", http.StatusBadRequest},
+
+	{OrganizationRequired, "organization_required", http.StatusForbidden},
+	{NotOrganizationMember, "organization_membership_required", http.StatusForbidden},
+
+	{PlanRequired, "plan_required", http.StatusPaymentRequired},
+	{PlanNotFound, "plan_not_found", http.StatusNotFound},
+	{QuotaExceeded, "quota_exceeded", http.StatusTooManyRequests},
+
+	{IdempotencyKeyReused, "idempotency_key_reused", http.StatusUnprocessableEntity},
+	{IdempotencyInProgress, "idempotency_in_progress", http.StatusConflict},
+
+	{InvalidAuthorizationRequest, "invalid_authorization_request", http.StatusBadRequest},
+	{OAuthClientNotFound, "oauth_client_not_found", http.StatusNotFound},
+	{OAuthConsentNotFound, "oauth_consent_not_found", http.StatusNotFound},
+	{DeviceCodeNotFound, "device_code_not_found", http.StatusNotFound},
+
+	{WebhookNotFound, "webhook_not_found", http.StatusNotFound},
+	{WebhookInProgress, "webhook_in_progress", http.StatusConflict},
+	{WebhookAlreadyProcessed, "webhook_already_processed", http.StatusConflict},
+
+	{OperationNotFound, "operation_not_found", http.StatusNotFound},
+	{BackupInvalid, "backup_invalid", http.StatusBadRequest},
+	{UnknownTranslationKey, "unknown_translation_key", http.StatusBadRequest},
+	{SetupUnavailable, "setup_unavailable", http.StatusForbidden},
+}
+
+// byKey indexes the registry by message key
+var byKey = func() map[string]Entry {
+	entries := make(map[string]Entry, len(registry))
+	for _, entry := range registry {
+		entries[entry.Key] = entry
+	}
+	return entries
+}()
+
+// ForKey returns the code of the error answered with the message key, or "" when the key is not an
+// error
+func ForKey(key string) string {
+	return byKey[key].Code
+}
+
+// Lookup returns the registered error with code
+func Lookup(code string) (Entry, bool) {
+	for _, entry := range registry {
+		if entry.Code == code {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// All returns every registered error in code order
+func All() []Entry {
+	return append([]Entry(nil), registry...)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/audit"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// AuditHandler serves the audit log of the admin-only endpoints
+type AuditHandler struct {
+	recorder      *audit.Recorder
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(recorder *audit.Recorder, logger utils.Logger, localizer *utils.Localizer) *AuditHandler {
+	return &AuditHandler{
+		recorder:      recorder,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// List godoc
+// @Summary List audit log entries
+// @ID listAuditLogs
+// @Description Get a page of the calls to admin-only endpoints, newest first: who made each, the route, the response status, and a SHA-256 of the request body. Entries cannot be changed or deleted through the API.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param actor_id query string false "Only calls by this user"
+// @Param method query string false "Only calls with this HTTP method" Enums(GET, HEAD, POST, PUT, PATCH, DELETE)
+// @Param route query string false "Only calls to this route pattern" example(/api/v1/admin/users/:id)
+// @Param from query string false "Only calls at or after this time (RFC 3339)"
+// @Param to query string false "Only calls before this time (RFC 3339)"
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse{data=[]models.AuditLog}}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/audit-logs [get]
+func (h *AuditHandler) List(c *gin.Context) {
+	var query models.ListAuditLogsQuery
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	entries, total, err := h.recorder.List(c.Request.Context(), audit.ListOptions{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+		ActorID:  query.ActorID,
+		Method:   query.Method,
+		Route:    query.Route,
+		From:     query.From,
+		To:       query.To,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list audit log entries", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to list audit log entries",
+		))
+		return
+	}
+
+	pagination := models.Pagination{
+		Page:      query.Page,
+		PageSize:  query.PageSize,
+		Total:     total,
+		TotalPage: int((total + int64(query.PageSize) - 1) / int64(query.PageSize)),
+	}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resources_retrieved"),
+		h.responseUtils.PaginatedResponse(entries, pagination),
+	))
+}
+
+// Verify godoc
+// @Summary Verify the audit log
+// @ID verifyAuditLogs
+// @Description Recompute the hash of every audit log entry and, with AUDIT_LOG_HASH_CHAIN, check that each chained entry links to the one before it without gaps. The first entry that fails is reported. Reads the whole log, so it may take a while.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.APIResponse{data=models.AuditVerification}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/audit-logs/verify [get]
+func (h *AuditHandler) Verify(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	result, err := h.recorder.Verify(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to verify the audit log", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to verify the audit log",
+		))
+		return
+	}
+	if !result.Valid {
+		h.logger.Warn("The audit log failed verification", "entry_id", result.BrokenAt, "reason", result.Reason)
+	}
+
+	key := "audit_log_verified"
+	if !result.Valid {
+		key = "audit_log_tampered"
+	}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(h.localizer.Get(lang, key), result))
+}
+
+// auditLogColumns are the CSV columns of the audit log export
+var auditLogColumns = []string{"id", "seq", "actor_id", "actor_role", "method", "route", "path", "status", "client_ip", "request_id", "payload_hash", "prev_hash", "hash", "created_at"}
+
+// Export godoc
+// @Summary Export audit log entries
+// @ID exportAuditLogs
+// @Description Download every audit log entry matching the filters, newest first, as NDJSON (one JSON object per line) or CSV with a header row. Entries are read and written as the download proceeds, so exports of any size use little memory, and the request has no deadline. Exported entries keep their hashes, so they can be checked against the log later. If the export fails midway, the connection is closed before the end of the response.
+// @Tags admin
+// @Produce application/x-ndjson,text/csv
+// @Security Bearer
+// @Param format query string false "Export format" Enums(ndjson, csv) default(ndjson)
+// @Param actor_id query string false "Only calls by this user"
+// @Param method query string false "Only calls with this HTTP method" Enums(GET, HEAD, POST, PUT, PATCH, DELETE)
+// @Param route query string false "Only calls to this route pattern" example(/api/v1/admin/users/:id)
+// @Param from query string false "Only calls at or after this time (RFC 3339)"
+// @Param to query string false "Only calls before this time (RFC 3339)"
+// @Success 200 {string} string "The entries, one per line"
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/audit-logs/export [get]
+func (h *AuditHandler) Export(c *gin.Context) {
+	var query models.ExportAuditLogsQuery
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	opts := audit.ListOptions{
+		ActorID: query.ActorID,
+		Method:  query.Method,
+		Route:   query.Route,
+		From:    query.From,
+		To:      query.To,
+	}
+	streamExport(c, h.logger, h.localizer, h.responseUtils, query.Format, "audit-logs", auditLogColumns, func(write func(interface{}) error) error {
+		return h.recorder.Each(c.Request.Context(), opts, func(entry *models.AuditLog) error {
+			return write(entry)
+		})
+	})
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/backup"
+	"go-backend-template/jobs"
+	"go-backend-template/models"
+	"go-backend-template/operations"
+	"go-backend-template/transfer"
+	"go-backend-template/utils"
+)
+
+// BackupHandler exports the users of the primary database to encrypted archives and restores them
+type BackupHandler struct {
+	store         transfer.Store
+	key           string
+	operations    *operations.Queue
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewBackupHandler creates a new backup handler for the users of store, encrypting archives with key;
+// backups and restores run in the background on request when queue is not nil
+func NewBackupHandler(store transfer.Store, key string, queue *operations.Queue, logger utils.Logger, localizer *utils.Localizer) *BackupHandler {
+	return &BackupHandler{
+		store:         store,
+		key:           key,
+		operations:    queue,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// Create godoc
+// @Summary Back up the users
+// @ID createBackup
+// @Description Export every user, soft-deleted ones and password hashes included, to a compressed archive encrypted with BACKUP_ENCRYPTION_KEY and returned base64-encoded. The archive is built in memory; use `cli backup` for large user stores. With Prefer: respond-async, the backup runs in the background: the response is 202 with an operation to poll at GET /operations/{id}, whose result is the archive. Superadmins only; served when BACKUP_ENDPOINTS_ENABLED is set.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param Prefer header string false "respond-async to back up in the background"
+// @Success 200 {object} models.APIResponse{data=models.BackupResponse}
+// @Success 202 {object} models.APIResponse{data=models.Operation}
+// @Header 202 {string} Location "The operation to poll"
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
+// @Router /admin/backup [get]
+func (h *BackupHandler) Create(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	if h.operations != nil && prefersAsync(c) {
+		acceptOperation(c, h.operations, h.logger, h.localizer, h.responseUtils, jobs.OperationBackup, "/admin/backup", nil)
+		return
+	}
+
+	archive, manifest, err := backup.Export(c.Request.Context(), h.store, h.key)
+	if err != nil {
+		h.logger.Error("Failed to back up the users", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to back up the users",
+		))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "backup_created"),
+		models.BackupResponse{Archive: archive, Users: manifest.Users, Source: manifest.Source, CreatedAt: manifest.CreatedAt},
+	))
+}
+
+// Restore godoc
+// @Summary Restore the users from a backup
+// @ID restoreBackup
+// @Description Write the users of an archive made by GET /admin/backup or `cli backup` to the primary database, matching them by email: users in the archive are created or overwritten, and users created since are kept. The request body is limited by MAX_FILE_SIZE; use `cli restore` for larger archives. With Prefer: respond-async, the archive is checked right away and restored in the background: the response is 202 with an operation to poll at GET /operations/{id}, whose result is the report. Superadmins only; served when BACKUP_ENDPOINTS_ENABLED is set.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Prefer header string false "respond-async to restore in the background"
+// @Param request body models.RestoreBackupRequest true "Encrypted archive"
+// @Success 200 {object} models.APIResponse{data=models.RestoreBackupResponse}
+// @Success 202 {object} models.APIResponse{data=models.Operation}
+// @Header 202 {string} Location "The operation to poll"
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 413 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
+// @Router /admin/backup/restore [post]
+func (h *BackupHandler) Restore(c *gin.Context) {
+	var req models.RestoreBackupRequest
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	async := h.operations != nil && prefersAsync(c)
+	var result backup.Result
+	var err error
+	if async {
+		_, err = backup.Inspect(req.Archive, h.key)
+	} else {
+		result, err = backup.Restore(c.Request.Context(), h.store, req.Archive, h.key)
+	}
+	if errors.Is(err, backup.ErrFormat) || errors.Is(err, backup.ErrDecrypt) {
+		h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "backup_invalid",
+			err.Error(),
+		))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to restore the users", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to restore the users",
+		))
+		return
+	}
+	if async {
+		acceptOperation(c, h.operations, h.logger, h.localizer, h.responseUtils, jobs.OperationRestore, "/admin/backup/restore", req)
+		return
+	}
+
+	h.logger.Info("Restored users from a backup", "users", result.Manifest.Users, "created", result.Created,
+		"updated", result.Updated, "archive_created_at", result.Manifest.CreatedAt)
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "backup_restored"),
+		models.RestoreBackupResponse{
+			Users:     result.Manifest.Users,
+			Created:   result.Created,
+			Updated:   result.Updated,
+			CreatedAt: result.Manifest.CreatedAt,
+		},
+	))
+}
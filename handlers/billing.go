@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/billing"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// BillingHandler handles plans, checkout, and Stripe webhooks
+type BillingHandler struct {
+	service       *billing.Service
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewBillingHandler creates a new billing handler
+func NewBillingHandler(service *billing.Service, logger utils.Logger, localizer *utils.Localizer) *BillingHandler {
+	return &BillingHandler{
+		service:       service,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// ListPlans godoc
+// @Summary List plans
+// @ID listPlans
+// @Description List the subscription plans, cheapest first
+// @Tags billing
+// @Produce json
+// @Success 200 {object} models.APIResponse{data=[]models.PlanInfo}
+// @Router /billing/plans [get]
+func (h *BillingHandler) ListPlans(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	plans := []models.PlanInfo{}
+	for _, plan := range h.service.Catalog().Plans() {
+		plans = append(plans, models.PlanInfo{ID: plan.ID, Rank: plan.Rank})
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "plans_retrieved"),
+		plans,
+	))
+}
+
+// GetSubscription godoc
+// @Summary Get current subscription
+// @ID getSubscription
+// @Description Get the authenticated user's plan and subscription status
+// @Tags billing
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.APIResponse{data=models.SubscriptionInfo}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /billing/subscription [get]
+func (h *BillingHandler) GetSubscription(c *gin.Context) {
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	subscription, err := h.service.Subscription(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to load subscription", "user_id", userID, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to load subscription",
+		))
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "subscription_retrieved"),
+		models.SubscriptionInfo{
+			PlanID:            subscription.PlanID,
+			Status:            subscription.Status,
+			CurrentPeriodEnd:  subscription.CurrentPeriodEnd,
+			CancelAtPeriodEnd: subscription.CancelAtPeriodEnd,
+		},
+	))
+}
+
+// Checkout godoc
+// @Summary Start a subscription checkout
+// @ID createCheckout
+// @Description Create a Stripe Checkout session for a plan and return the page to redirect the user to
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.CheckoutRequest true "Plan to subscribe to"
+// @Param Idempotency-Key header string false "Client-generated key to make retries safe"
+// @Success 201 {object} models.APIResponse{data=models.CheckoutResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 502 {object} models.APIResponse
+// @Router /billing/checkout [post]
+func (h *BillingHandler) Checkout(c *gin.Context) {
+	var req models.CheckoutRequest
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Checkout validation failed", "error", err)
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	session, err := h.service.Checkout(c.Request.Context(), userID, utils.Scope(c).Email, req.PlanID)
+	if errors.Is(err, billing.ErrUnknownPlan) {
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "plan_not_found",
+			"Unknown plan "+req.PlanID,
+		))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create checkout session", "user_id", userID, "plan", req.PlanID, "error", err)
+		h.responseUtils.Respond(c, http.StatusBadGateway, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to create checkout session",
+		))
+		return
+	}
+
+	h.logger.Info("Checkout session created", "user_id", userID, "plan", req.PlanID, "session_id", session.ID)
+	h.responseUtils.Respond(c, http.StatusCreated, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "checkout_created"),
+		models.CheckoutResponse{SessionID: session.ID, URL: session.URL},
+	))
+}
+
+// Webhook receives Stripe events. The raw body is needed to verify the Stripe-Signature header, and any
+// non-2xx response makes Stripe retry delivery.
+func (h *BillingHandler) Webhook(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	err = h.service.HandleWebhook(c.Request.Context(), payload, c.GetHeader("Stripe-Signature"))
+	if errors.Is(err, billing.ErrInvalidSignature) {
+		h.logger.Warn("Rejected Stripe webhook with invalid signature", "client_ip", c.ClientIP())
+		h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "bad_request",
+			err.Error(),
+		))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to process Stripe webhook", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to process webhook",
+		))
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
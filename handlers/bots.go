@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/models"
+	"go-backend-template/security"
+	"go-backend-template/utils"
+)
+
+// BotChallengeHeader is the request header carrying a challenge token from GET /auth/challenge
+const BotChallengeHeader = "X-Bot-Challenge"
+
+// BotHandler keeps bots away from the auth routes and issues the challenge tokens of the frontend
+type BotHandler struct {
+	detector      *security.BotDetector
+	securityLog   *security.EventLogger
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewBotHandler creates a new bot handler; rejected requests are recorded in securityLog
+func NewBotHandler(detector *security.BotDetector, securityLog *security.EventLogger, logger utils.Logger, localizer *utils.Localizer) *BotHandler {
+	return &BotHandler{
+		detector:      detector,
+		securityLog:   securityLog,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// Guards reports whether route, given without the API version, is checked for bots
+func (h *BotHandler) Guards(route string) bool {
+	return h.detector.Guards(route)
+}
+
+// ChallengeEnabled reports whether GET /auth/challenge is served
+func (h *BotHandler) ChallengeEnabled() bool {
+	return h.detector.ChallengeEnabled()
+}
+
+// Guard rejects requests to route that look automated with 400. The response does not tell which signal
+// gave the bot away; the signals are logged and recorded as a security event.
+func (h *BotHandler) Guard(route string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body []byte
+		if h.detector.HoneypotField() != "" && c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					respondBindError(c, h.localizer, h.responseUtils, utils.Scope(c).Language, err)
+					c.Abort()
+					return
+				}
+				body = nil
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		signals := h.detector.Inspect(route, c.Request, body, c.GetHeader(BotChallengeHeader))
+		if len(signals) == 0 {
+			c.Next()
+			return
+		}
+
+		h.logger.Warn("Rejected a request that looks automated", "route", route, "client_ip", utils.ClientIP(c), "signals", signals)
+		h.securityLog.LogRequest(c, security.Event{
+			Type:    security.EventBotRejected,
+			Outcome: security.OutcomeFailure,
+			Reason:  signals[0],
+			Details: map[string]string{"route": route, "signals": strings.Join(signals, ",")},
+		})
+		lang := utils.Scope(c).Language
+		h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "request_rejected",
+			"The request was refused",
+		))
+		c.Abort()
+	}
+}
+
+// Challenge godoc
+// @Summary Get a bot challenge token
+// @ID getBotChallenge
+// @Description Issue a challenge token for the login and registration routes listed in BOT_CHALLENGE_ROUTES. The frontend fetches it with JavaScript when the form is shown and sends it in the X-Bot-Challenge header on submit. A token is valid for one request, and is refused if used within BOT_CHALLENGE_MIN_AGE of being issued. Only served when BOT_CHALLENGE_ROUTES is set.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.APIResponse{data=models.BotChallengeResponse}
+// @Failure 500 {object} models.APIResponse
+// @Router /auth/challenge [get]
+func (h *BotHandler) Challenge(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	token, expiresAt, err := h.detector.Challenge()
+	if err != nil {
+		h.logger.Error("Failed to issue a bot challenge", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to issue a challenge",
+		))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "bot_challenge_issued"),
+		models.BotChallengeResponse{Token: token, ExpiresAt: expiresAt},
+	))
+}
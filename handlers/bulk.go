@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/jobs"
+	"go-backend-template/models"
+	"go-backend-template/security"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// maxBulkUsers is the most users one bulk action may select
+const maxBulkUsers = 1000
+
+// errTooManyUsers stops the resolution of a bulk filter that selects more than maxBulkUsers users
+var errTooManyUsers = errors.New("the filter selects too many users")
+
+// BulkUsers godoc
+// @Summary Act on many users at once (Admin only)
+// @ID bulkUsers
+// @Description Activate, deactivate, soft-delete, or change the role of up to 1000 users, listed by ID or selected by the search and filters of GET /users, given as query parameter names and values. Users are acted on one at a time, each in its own transaction, in batches of 100; a failure for one user does not undo the others, and the response reports the outcome for each. Inactive users cannot log in. Your own account is never changed, only superadmins can deactivate or delete superadmins, and the last active superadmin cannot be deactivated, deleted, or demoted. The role action is for superadmins only and records the reason with each change in the security log. With Prefer: respond-async, the users are selected right away and the action runs in the background: the response is 202 with an operation to poll at GET /operations/{id}, whose result is the report.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Prefer header string false "respond-async to run the action in the background"
+// @Param request body models.BulkUsersRequest true "Action and the users it applies to"
+// @Success 200 {object} models.APIResponse{data=models.BulkUsersResponse}
+// @Success 202 {object} models.APIResponse{data=models.Operation}
+// @Header 202 {string} Location "The operation to poll"
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
+// @Router /admin/users/bulk [post]
+func (h *UserHandler) BulkUsers(c *gin.Context) {
+	var req models.BulkUsersRequest
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	if err := checkBulkRequest(req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	if req.Action == "role" && utils.Scope(c).Role != "superadmin" {
+		h.responseUtils.Respond(c, http.StatusForbidden, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "insufficient_permissions",
+			"Only superadmins can change roles",
+		))
+		return
+	}
+
+	ids := req.IDs
+	if req.Filter != nil {
+		var err error
+		if ids, err = h.selectBulkUsers(c.Request.Context(), req.Filter); err != nil {
+			if errors.Is(err, errTooManyUsers) {
+				h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+					h.localizer, lang, "bulk_too_many_users",
+					fmt.Sprintf("The filter selects more than %d users", maxBulkUsers),
+				))
+				return
+			}
+			var paramErr *utils.ParamError
+			if errors.As(err, &paramErr) {
+				respondBindError(c, h.localizer, h.responseUtils, lang, err)
+				return
+			}
+			h.respondServiceError(c, lang, err, "Failed to select users")
+			return
+		}
+	}
+
+	job := jobs.BulkUsersJob{
+		Action: req.Action,
+		Role:   req.Role,
+		Reason: req.Reason,
+		IDs:    ids,
+		Actor: security.Event{
+			ActorID:   utils.Scope(c).UserID,
+			ClientIP:  utils.ClientIP(c),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: utils.Scope(c).RequestID,
+		},
+		ActorRole: utils.Scope(c).Role,
+	}
+	if h.operations != nil && prefersAsync(c) {
+		acceptOperation(c, h.operations, h.logger, h.localizer, h.responseUtils, jobs.OperationBulkUsers, "/admin/users/bulk", job)
+		return
+	}
+
+	response := h.bulk.Run(c.Request.Context(), job, nil)
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(h.localizer.Get(lang, "bulk_completed"), response))
+}
+
+// checkBulkRequest checks the rules of a bulk request that span fields: exactly one of IDs and Filter,
+// no ID twice, and a role and reason for the role action
+func checkBulkRequest(req models.BulkUsersRequest) error {
+	switch {
+	case req.IDs == nil && req.Filter == nil:
+		return &utils.ParamError{Field: "ids", Rule: "required", Message: "give ids or filter"}
+	case req.IDs != nil && req.Filter != nil:
+		return &utils.ParamError{Field: "filter", Rule: "invalid", Message: "give ids or filter, not both"}
+	case req.Filter != nil && len(req.Filter) == 0:
+		return &utils.ParamError{Field: "filter", Rule: "required", Message: "the filter must have at least one condition"}
+	case req.Action == "role" && req.Role == "":
+		return &utils.ParamError{Field: "role", Rule: "required", Message: "the role action needs a role"}
+	case req.Action == "role" && req.Reason == "":
+		return &utils.ParamError{Field: "reason", Rule: "required", Message: "the role action needs a reason"}
+	}
+
+	seen := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			return &utils.ParamError{Field: "ids", Rule: "unique", Param: id, Message: fmt.Sprintf("user %q is listed more than once", id)}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// selectBulkUsers returns the IDs of the users matching filter. Unlike GET /users, a parameter that is not
+// a filter is an error, so a misspelled one cannot widen the selection to every user.
+func (h *UserHandler) selectBulkUsers(ctx context.Context, filter map[string]string) ([]string, error) {
+	allowed := h.metadata.Filters(utils.UserFilters)
+	query := services.ListUsersQuery{Fields: utils.FieldSet{"id"}, Sort: utils.DefaultUserSort}
+	for key, value := range filter {
+		if key == "search" {
+			query.Search = value
+			continue
+		}
+		filters, err := utils.ParseFilters(url.Values{key: {value}}, allowed)
+		if err != nil {
+			return nil, err
+		}
+		if len(filters) == 0 {
+			return nil, &utils.ParamError{Field: key, Rule: "filter", Message: fmt.Sprintf("field %q cannot be filtered", key)}
+		}
+		query.Filters = append(query.Filters, filters...)
+	}
+
+	var ids []string
+	err := h.users.EachUser(ctx, query, func(user models.UserInfo) error {
+		if len(ids) == maxBulkUsers {
+			return errTooManyUsers
+		}
+		ids = append(ids, user.ID)
+		return nil
+	})
+	return ids, err
+}
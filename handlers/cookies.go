@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/config"
+	"go-backend-template/models"
+)
+
+// deliverToken sets the auth and refresh cookies when cookie delivery is enabled and strips the tokens
+// from the JSON body when the client should rely on the cookies alone
+func deliverToken(c *gin.Context, authCfg config.AuthConfig, authResponse *models.AuthResponse) {
+	if authCfg.UsesCookies() {
+		setAuthCookie(c, authCfg, authResponse.Token, authResponse.ExpiresAt)
+		if authResponse.RefreshToken != "" {
+			setCookie(c, authCfg, authCfg.RefreshCookieName, authResponse.RefreshToken, *authResponse.RefreshExpiresAt)
+		}
+	}
+	if !authCfg.UsesHeader() {
+		authResponse.Token = ""
+		authResponse.RefreshToken = ""
+	}
+}
+
+// refreshToken returns the refresh token of the request: the one in the body, else in cookie delivery
+// mode the refresh cookie
+func refreshToken(c *gin.Context, authCfg config.AuthConfig, token string) string {
+	if token == "" && authCfg.UsesCookies() {
+		token, _ = c.Cookie(authCfg.RefreshCookieName)
+	}
+	return token
+}
+
+// clearAuthCookies clears the auth and refresh cookies in cookie delivery mode
+func clearAuthCookies(c *gin.Context, authCfg config.AuthConfig) {
+	if authCfg.UsesCookies() {
+		setAuthCookie(c, authCfg, "", time.Unix(0, 0))
+		setCookie(c, authCfg, authCfg.RefreshCookieName, "", time.Unix(0, 0))
+	}
+}
+
+// setAuthCookie writes the httpOnly auth cookie; an empty token with a past expiry clears it
+func setAuthCookie(c *gin.Context, authCfg config.AuthConfig, token string, expiresAt time.Time) {
+	setCookie(c, authCfg, authCfg.CookieName, token, expiresAt)
+}
+
+// setCookie writes an httpOnly cookie with the attributes of the auth cookie; an empty value with a past
+// expiry clears it
+func setCookie(c *gin.Context, authCfg config.AuthConfig, name, token string, expiresAt time.Time) {
+	maxAge := int(time.Until(expiresAt).Seconds())
+	if token == "" {
+		maxAge = -1
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    token,
+		Path:     authCfg.CookiePath,
+		Domain:   authCfg.CookieDomain,
+		Expires:  expiresAt,
+		MaxAge:   maxAge,
+		Secure:   authCfg.CookieSecure,
+		HttpOnly: true,
+		SameSite: sameSiteMode(authCfg.CookieSameSite),
+	})
+}
+
+func sameSiteMode(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
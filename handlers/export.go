@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/utils"
+)
+
+// streamExport writes the records each passes to write as a download named after name, in format, with
+// columns as the CSV header. The records go out as they are written, so nothing holds the whole export.
+// A failure before the first bytes leave is a 500; after that the connection is cut, so the client sees an
+// incomplete response rather than a short export.
+func streamExport(c *gin.Context, logger utils.Logger, localizer *utils.Localizer, responseUtils *utils.ResponseUtils, format, name string, columns []string, each func(write func(record interface{}) error) error) {
+	lang := utils.Scope(c).Language
+
+	c.Header("Content-Type", utils.ExportContentTypes[format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, time.Now().UTC().Format("20060102T150405Z"), format))
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	writer, err := utils.NewExportWriter(c.Writer, format, columns)
+	if err == nil {
+		err = each(writer.Write)
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	logger.Error("Failed to export", "export", name, "error", err)
+	if !c.Writer.Written() {
+		for _, header := range []string{"Content-Type", "Content-Disposition"} {
+			c.Writer.Header().Del(header)
+		}
+		responseUtils.Respond(c, http.StatusInternalServerError, responseUtils.LocalizedErrorResponse(
+			localizer, lang, "internal_error",
+			"Failed to export",
+		))
+		return
+	}
+	if conn, _, err := c.Writer.Hijack(); err == nil {
+		conn.Close()
+	}
+	c.Abort()
+}
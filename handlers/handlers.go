@@ -2,870 +2,1123 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"go-backend-template/analytics"
 	"go-backend-template/config"
 	"go-backend-template/database"
-	"go-backend-template/jwt"
+	"go-backend-template/errcodes"
+	"go-backend-template/hooks"
+	"go-backend-template/jobs"
 	"go-backend-template/models"
+	"go-backend-template/operations"
+	"go-backend-template/pwned"
+	"go-backend-template/security"
+	"go-backend-template/services"
+	"go-backend-template/sessions"
 	"go-backend-template/utils"
 )
 
+// respondBindError writes the response for a request binding failure: 413 for oversized
+// bodies, otherwise 400 with localized per-field details when available
+func respondBindError(c *gin.Context, localizer *utils.Localizer, responseUtils *utils.ResponseUtils, lang string, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		responseUtils.Respond(c, http.StatusRequestEntityTooLarge, responseUtils.LocalizedErrorResponse(
+			localizer, lang, "request_too_large",
+			err.Error(),
+		))
+		return
+	}
+
+	if fieldErrors := localizer.FieldErrors(lang, err); fieldErrors != nil {
+		responseUtils.Respond(c, http.StatusBadRequest, responseUtils.LocalizedValidationErrorResponse(
+			localizer, lang, "validation_error",
+			"One or more fields are invalid",
+			fieldErrors,
+		))
+		return
+	}
+
+	responseUtils.Respond(c, http.StatusBadRequest, responseUtils.LocalizedErrorResponse(
+		localizer, lang, "validation_error",
+		err.Error(),
+	))
+}
+
+// resolveLocale replaces a requested locale with the supported language it selects (pt-BR becomes pt when
+// only pt is supported) and writes a 400 listing the supported languages when there is none; it reports
+// whether the request can proceed
+func resolveLocale(c *gin.Context, localizer *utils.Localizer, responseUtils *utils.ResponseUtils, lang string, locale *string) bool {
+	if *locale == "" {
+		return true
+	}
+	supported, ok := localizer.Lookup(*locale)
+	if !ok {
+		responseUtils.Respond(c, http.StatusBadRequest, responseUtils.LocalizedValidationErrorResponse(
+			localizer, lang, "validation_error",
+			"One or more fields are invalid",
+			[]models.FieldError{localizer.FieldError(lang, "locale", "oneof", strings.Join(localizer.SupportedLanguages(), " "))},
+		))
+		return false
+	}
+	*locale = supported
+	return true
+}
+
+// userLanguage switches the response to the user's stored locale when the client did not ask for a
+// language, and returns the language to respond in
+func userLanguage(c *gin.Context, lang, locale string) string {
+	if locale == "" || c.GetBool("language_requested") {
+		return lang
+	}
+	utils.SetLanguage(c, locale)
+	c.Header("Content-Language", locale)
+	return locale
+}
+
+// respondDuplicateUser writes a localized 409 when err is services.ErrDuplicateEmail or
+// services.ErrDuplicateUsername, and reports whether it did
+func respondDuplicateUser(c *gin.Context, localizer *utils.Localizer, responseUtils *utils.ResponseUtils, lang string, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrDuplicateEmail):
+		responseUtils.Respond(c, http.StatusConflict, responseUtils.LocalizedErrorResponse(localizer, lang, "email_exists", "Email already exists"))
+	case errors.Is(err, services.ErrDuplicateUsername):
+		responseUtils.Respond(c, http.StatusConflict, responseUtils.LocalizedErrorResponse(localizer, lang, "username_exists", "Username already exists"))
+	default:
+		return false
+	}
+	return true
+}
+
+// respondRejection writes a localized 403 with the reason when err is a *hooks.Rejection, and reports
+// whether it did
+func respondRejection(c *gin.Context, localizer *utils.Localizer, responseUtils *utils.ResponseUtils, lang string, err error) bool {
+	var rejection *hooks.Rejection
+	if !errors.As(err, &rejection) {
+		return false
+	}
+	responseUtils.Respond(c, http.StatusForbidden, responseUtils.LocalizedErrorResponse(localizer, lang, "request_rejected", rejection.Reason))
+	return true
+}
+
+// notModified sets the ETag header for data and writes 304 if the client's If-None-Match matches
+func notModified(c *gin.Context, data interface{}) bool {
+	etag, err := utils.ETag(data)
+	if err != nil {
+		return false
+	}
+	c.Header("ETag", etag)
+
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" && utils.ETagMatches(ifNoneMatch, etag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
 // AuthHandler handles authentication-related requests
 type AuthHandler struct {
-	mongoDB       *database.MongoDB
-	postgresDB    *database.PostgresDB
+	auth          services.AuthService
+	sessions      *sessions.Manager
 	logger        utils.Logger
 	localizer     *utils.Localizer
-	passwordUtils *utils.PasswordUtils
-	jwtUtils      *utils.JWTUtils
 	responseUtils *utils.ResponseUtils
+	securityLog   *security.EventLogger
+	throttle      *security.LoginThrottle
+	analytics     *analytics.Tracker
+	authCfg       config.AuthConfig
 }
 
-// NewAuthHandler creates a new auth handler
-func NewAuthHandler(mongoDB *database.MongoDB, postgresDB *database.PostgresDB, logger utils.Logger, localizer *utils.Localizer) *AuthHandler {
-	cfg := config.Load()
+// NewAuthHandler creates a new auth handler; failed logins are slowed down by throttle, and signups and
+// logins are recorded by tracker, both of which may be nil. Users list and end the sessions of
+// sessionManager, which is nil when sessions are disabled.
+func NewAuthHandler(authCfg config.AuthConfig, auth services.AuthService, sessionManager *sessions.Manager, logger utils.Logger, localizer *utils.Localizer, securityLog *security.EventLogger, throttle *security.LoginThrottle, tracker *analytics.Tracker) *AuthHandler {
 	return &AuthHandler{
-		mongoDB:       mongoDB,
-		postgresDB:    postgresDB,
+		auth:          auth,
+		sessions:      sessionManager,
 		logger:        logger,
 		localizer:     localizer,
-		passwordUtils: &utils.PasswordUtils{},
-		jwtUtils:      utils.NewJWTUtils(cfg.JWTSecret),
+		securityLog:   securityLog,
+		throttle:      throttle,
+		analytics:     tracker,
+		authCfg:       authCfg,
 		responseUtils: &utils.ResponseUtils{},
 	}
 }
 
 // Register godoc
 // @Summary Register a new user
+// @ID register
 // @Description Register a new user with email, username, and password
 // @Tags auth
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param request body models.RegisterRequest true "Registration data"
+// @Param X-Bot-Challenge header string false "Challenge token from GET /auth/challenge, required on the routes in BOT_CHALLENGE_ROUTES"
+// @Param Idempotency-Key header string false "Client-generated key to make retries safe"
 // @Success 201 {object} models.APIResponse{data=models.AuthResponse}
 // @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 409 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
-	lang := c.GetString("language")
+	lang := utils.Scope(c).Language
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Registration validation failed", "error", err)
-		c.JSON(http.StatusBadRequest, h.responseUtils.ErrorResponse(
-			h.localizer.Get(lang, "validation_error"),
-			err.Error(),
-		))
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	if !resolveLocale(c, h.localizer, h.responseUtils, lang, &req.Locale) {
 		return
 	}
 
-	// Hash password
-	hashedPassword, err := h.passwordUtils.HashPassword(req.Password)
+	authResponse, err := h.auth.Register(clientContext(c), req)
 	if err != nil {
-		h.logger.Error("Password hashing failed", "error", err)
-		c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-			h.localizer.Get(lang, "internal_error"),
-			"Failed to process password",
-		))
+		h.respondRegisterError(c, lang, err)
 		return
 	}
+	deliverToken(c, h.authCfg, authResponse)
+	lang = userLanguage(c, lang, authResponse.User.Locale)
+
+	h.securityLog.LogRequest(c, security.Event{
+		Type:    security.EventRegistration,
+		Outcome: security.OutcomeSuccess,
+		UserID:  authResponse.User.ID,
+		Email:   authResponse.User.Email,
+	})
+	if !authResponse.User.AnalyticsOptOut {
+		h.analytics.TrackRequest(c, analytics.EventSignup, authResponse.User.ID, nil)
+	}
 
-	// Check if using PostgreSQL
-	if h.postgresDB != nil {
-		user := models.User{
-			Email:     req.Email,
-			Username:  req.Username,
-			Password:  hashedPassword,
-			FirstName: req.FirstName,
-			LastName:  req.LastName,
-			Role:      "user",
-			IsActive:  true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-
-		// Check if user exists
-		var existingUser models.User
-		if err := h.postgresDB.Where("email = ? OR username = ?", req.Email, req.Username).First(&existingUser).Error; err == nil {
-			c.JSON(http.StatusConflict, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "email_exists"),
-				"User already exists",
-			))
-			return
-		}
-
-		// Create user
-		if err := h.postgresDB.Create(&user).Error; err != nil {
-			h.logger.Error("Failed to create user in PostgreSQL", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to create user",
-			))
-			return
-		}
-
-		// Generate token
-		token, expiresAt, err := jwt.GenerateToken(h.jwtUtils.Secret, user.ID, user.Email, user.Username, user.Role)
-		if err != nil {
-			h.logger.Error("Token generation failed", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to generate token",
-			))
-			return
-		}
-
-		userInfo := models.UserInfo{
-			ID:        user.ID,
-			Email:     user.Email,
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		}
-
-		authResponse := models.AuthResponse{
-			Token:     token,
-			User:      userInfo,
-			ExpiresAt: expiresAt,
-		}
+	h.responseUtils.Respond(c, http.StatusCreated, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "user_created"),
+		authResponse,
+	))
+}
 
-		c.JSON(http.StatusCreated, h.responseUtils.SuccessResponse(
-			h.localizer.Get(lang, "user_created"),
-			authResponse,
+// respondRegisterError writes 409 for a duplicate email or username, 400 for a breached password or an
+// email domain without mail servers, 403 for a registration refused by a hook, 503 when the breach check
+// is unavailable, and 500 for any other failure
+func (h *AuthHandler) respondRegisterError(c *gin.Context, lang string, err error) {
+	if respondDuplicateUser(c, h.localizer, h.responseUtils, lang, err) ||
+		respondRejection(c, h.localizer, h.responseUtils, lang, err) {
+		return
+	}
+	if errors.Is(err, services.ErrBreachedPassword) {
+		respondBindError(c, h.localizer, h.responseUtils, lang, &utils.ParamError{
+			Field:   "password",
+			Rule:    "breachedpassword",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrUndeliverableEmail) {
+		respondBindError(c, h.localizer, h.responseUtils, lang, &utils.ParamError{
+			Field:   "email",
+			Rule:    "deliverable",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, pwned.ErrUnavailable) {
+		h.logger.Error("Registration failed", "error", err)
+		h.responseUtils.Respond(c, http.StatusServiceUnavailable, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "service_unavailable",
+			"The password could not be checked; try again later",
 		))
 		return
 	}
 
-	// MongoDB implementation
-	if h.mongoDB != nil {
-		userMongo := models.UserMongo{
-			Email:     req.Email,
-			Username:  req.Username,
-			Password:  hashedPassword,
-			FirstName: req.FirstName,
-			LastName:  req.LastName,
-			Role:      "user",
-			IsActive:  true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-
-		// Check if user exists
-		collection := h.mongoDB.Collection("users")
-		filter := bson.M{
-			"$or": []bson.M{
-				{"email": req.Email},
-				{"username": req.Username},
-			},
-		}
-
-		var existingUser models.UserMongo
-		if err := collection.FindOne(context.Background(), filter).Decode(&existingUser); err == nil {
-			c.JSON(http.StatusConflict, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "email_exists"),
-				"User already exists",
-			))
-			return
-		}
-
-		// Create user
-		result, err := collection.InsertOne(context.Background(), userMongo)
-		if err != nil {
-			h.logger.Error("Failed to create user in MongoDB", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to create user",
-			))
-			return
-		}
-
-		userMongo.ID = result.InsertedID.(primitive.ObjectID)
-
-		// Generate token
-		token, expiresAt, err := jwt.GenerateToken(h.jwtUtils.Secret, userMongo.ID.Hex(), userMongo.Email, userMongo.Username, userMongo.Role)
-		if err != nil {
-			h.logger.Error("Token generation failed", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to generate token",
-			))
-			return
-		}
-
-		userInfo := models.UserInfo{
-			ID:        userMongo.ID.Hex(),
-			Email:     userMongo.Email,
-			Username:  userMongo.Username,
-			FirstName: userMongo.FirstName,
-			LastName:  userMongo.LastName,
-			Role:      userMongo.Role,
-			IsActive:  userMongo.IsActive,
-			CreatedAt: userMongo.CreatedAt,
-			UpdatedAt: userMongo.UpdatedAt,
-		}
-
-		authResponse := models.AuthResponse{
-			Token:     token,
-			User:      userInfo,
-			ExpiresAt: expiresAt,
-		}
-
-		c.JSON(http.StatusCreated, h.responseUtils.SuccessResponse(
-			h.localizer.Get(lang, "user_created"),
-			authResponse,
-		))
+	detail := "Failed to create user"
+	if errors.Is(err, services.ErrTokenGeneration) {
+		detail = "Failed to generate token"
 	}
+	h.logger.Error("Registration failed", "error", err)
+	h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "internal_error",
+		detail,
+	))
 }
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user with email and password
+// @ID login
+// @Description Authenticate user with email and password. Repeated failures for an email from one client are answered with 429 and a Retry-After header until the delay passes, and enough of them block the pair for a while.
 // @Tags auth
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param request body models.LoginRequest true "Login credentials"
+// @Param X-Bot-Challenge header string false "Challenge token from GET /auth/challenge, required on the routes in BOT_CHALLENGE_ROUTES"
 // @Success 200 {object} models.APIResponse{data=models.AuthResponse}
 // @Failure 400 {object} models.APIResponse
 // @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 429 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
-	lang := c.GetString("language")
+	lang := utils.Scope(c).Language
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Login validation failed", "error", err)
-		c.JSON(http.StatusBadRequest, h.responseUtils.ErrorResponse(
-			h.localizer.Get(lang, "validation_error"),
-			err.Error(),
-		))
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
 		return
 	}
-
-	// PostgreSQL implementation
-	if h.postgresDB != nil {
-		var user models.User
-		if err := h.postgresDB.Where("email = ?", req.Email).First(&user).Error; err != nil {
-			h.logger.Error("User not found in PostgreSQL", "email", req.Email)
-			c.JSON(http.StatusUnauthorized, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "invalid_credentials"),
-				"Authentication failed",
-			))
+	clientIP := utils.ClientIP(c)
+	var attempt *security.LoginAttempt
+	if h.throttle != nil {
+		var wait time.Duration
+		if attempt, wait = h.throttle.Allow(req.Email, clientIP); wait > 0 {
+			h.respondThrottled(c, lang, wait)
 			return
 		}
+		// A login that ends in neither success nor failure, such as on a database error, gives its attempt back
+		defer attempt.Cancel()
+	}
 
-		// Verify password
-		if err := h.passwordUtils.VerifyPassword(user.Password, req.Password); err != nil {
-			h.logger.Error("Password verification failed", "email", req.Email)
-			c.JSON(http.StatusUnauthorized, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "invalid_credentials"),
-				"Authentication failed",
-			))
-			return
+	authResponse, err := h.auth.Login(clientContext(c), req)
+	var loginErr *services.LoginError
+	if errors.As(err, &loginErr) {
+		h.logger.Error("Login failed", "email", req.Email, "reason", loginErr.Reason)
+		h.securityLog.LogRequest(c, security.Event{
+			Type:    security.EventLoginFailure,
+			Outcome: security.OutcomeFailure,
+			UserID:  loginErr.UserID,
+			Email:   req.Email,
+			Reason:  loginErr.Reason,
+		})
+		if attempt != nil {
+			wait, blocked := attempt.Failure()
+			if wait > 0 {
+				c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			}
+			if blocked {
+				h.logger.Warn("Login blocked after repeated failures", "email", req.Email, "client_ip", clientIP)
+				h.securityLog.LogRequest(c, security.Event{
+					Type:    security.EventLoginBlocked,
+					Outcome: security.OutcomeFailure,
+					UserID:  loginErr.UserID,
+					Email:   req.Email,
+					Reason:  "too many failed logins",
+				})
+			}
 		}
-
-		// Generate token
-		token, expiresAt, err := jwt.GenerateToken(h.jwtUtils.Secret, user.ID, user.Email, user.Username, user.Role)
-		if err != nil {
-			h.logger.Error("Token generation failed", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to generate token",
-			))
-			return
+		h.responseUtils.Respond(c, http.StatusUnauthorized, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "invalid_credentials",
+			"Authentication failed",
+		))
+		return
+	}
+	if respondRejection(c, h.localizer, h.responseUtils, lang, err) {
+		return
+	}
+	if err != nil {
+		detail := "Failed to log in"
+		if errors.Is(err, services.ErrTokenGeneration) {
+			detail = "Failed to generate token"
 		}
+		h.logger.Error("Login failed", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			detail,
+		))
+		return
+	}
+	deliverToken(c, h.authCfg, authResponse)
+	if attempt != nil {
+		attempt.Success()
+	}
 
-		userInfo := models.UserInfo{
-			ID:        user.ID,
-			Email:     user.Email,
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		}
+	h.securityLog.LogRequest(c, security.Event{
+		Type:    security.EventLoginSuccess,
+		Outcome: security.OutcomeSuccess,
+		UserID:  authResponse.User.ID,
+		Email:   authResponse.User.Email,
+	})
+	if !authResponse.User.AnalyticsOptOut {
+		h.analytics.TrackRequest(c, analytics.EventLogin, authResponse.User.ID, nil)
+	}
 
-		authResponse := models.AuthResponse{
-			Token:     token,
-			User:      userInfo,
-			ExpiresAt: expiresAt,
-		}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "login_successful"),
+		authResponse,
+	))
+}
+
+// respondThrottled writes 429 with the seconds to wait in Retry-After for a throttled login
+func (h *AuthHandler) respondThrottled(c *gin.Context, lang string, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	h.responseUtils.Respond(c, http.StatusTooManyRequests, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "too_many_login_attempts",
+		fmt.Sprintf("Too many failed logins; try again in %d seconds", seconds),
+	))
+}
 
-		c.JSON(http.StatusOK, h.responseUtils.SuccessResponse(
-			h.localizer.Get(lang, "login_successful"),
-			authResponse,
+// Refresh godoc
+// @Summary Refresh the token
+// @ID refreshToken
+// @Description Exchange the refresh token of a session for a new token and refresh token, extending the session by its idle timeout up to its maximum lifetime. Each refresh token works once; in cookie delivery mode it may come from the refresh cookie instead of the body.
+// @Tags auth
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param request body models.RefreshRequest false "Refresh token"
+// @Success 200 {object} models.APIResponse{data=models.AuthResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	lang := utils.Scope(c).Language
+
+	// The body may be empty when the token is in the refresh cookie
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	token := refreshToken(c, h.authCfg, req.RefreshToken)
+	if token == "" {
+		h.respondInvalidRefreshToken(c, lang)
+		return
+	}
+
+	authResponse, err := h.auth.Refresh(c.Request.Context(), token)
+	if errors.Is(err, services.ErrInvalidRefreshToken) {
+		h.respondInvalidRefreshToken(c, lang)
+		return
+	}
+	if respondRejection(c, h.localizer, h.responseUtils, lang, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error("Token refresh failed", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to refresh token",
 		))
 		return
 	}
+	deliverToken(c, h.authCfg, authResponse)
 
-	// MongoDB implementation
-	if h.mongoDB != nil {
-		collection := h.mongoDB.Collection("users")
-		filter := bson.M{"email": req.Email}
-
-		var user models.UserMongo
-		if err := collection.FindOne(context.Background(), filter).Decode(&user); err != nil {
-			h.logger.Error("User not found in MongoDB", "email", req.Email)
-			c.JSON(http.StatusUnauthorized, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "invalid_credentials"),
-				"Authentication failed",
-			))
-			return
-		}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "token_refreshed"),
+		authResponse,
+	))
+}
 
-		// Verify password
-		if err := h.passwordUtils.VerifyPassword(user.Password, req.Password); err != nil {
-			h.logger.Error("Password verification failed", "email", req.Email)
-			c.JSON(http.StatusUnauthorized, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "invalid_credentials"),
-				"Authentication failed",
-			))
-			return
-		}
+// respondInvalidRefreshToken writes 401 for a refresh token that cannot be exchanged, clearing the cookies
+// of its session
+func (h *AuthHandler) respondInvalidRefreshToken(c *gin.Context, lang string) {
+	clearAuthCookies(c, h.authCfg)
+	h.responseUtils.Respond(c, http.StatusUnauthorized, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "invalid_refresh_token",
+		"The refresh token is invalid, was already used, or expired",
+	))
+}
 
-		// Generate token
-		token, expiresAt, err := jwt.GenerateToken(h.jwtUtils.Secret, user.ID.Hex(), user.Email, user.Username, user.Role)
-		if err != nil {
-			h.logger.Error("Token generation failed", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to generate token",
+// Logout godoc
+// @Summary Logout user
+// @ID logout
+// @Description End the session of the refresh token, from the body or in cookie delivery mode the refresh cookie, and clear the cookies set in cookie delivery mode. Access tokens already issued stay valid until they expire.
+// @Tags auth
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param request body models.LogoutRequest false "Refresh token of the session to end"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req models.LogoutRequest
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	// An unknown or expired refresh token has no session left to end
+	if token := refreshToken(c, h.authCfg, req.RefreshToken); token != "" && h.sessions != nil {
+		if err := h.sessions.End(c.Request.Context(), token); err != nil && !errors.Is(err, sessions.ErrNotFound) {
+			h.logger.Error("Failed to end session", "error", err)
+			h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+				h.localizer, lang, "internal_error",
+				"Failed to end session",
 			))
 			return
 		}
-
-		userInfo := models.UserInfo{
-			ID:        user.ID.Hex(),
-			Email:     user.Email,
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		}
-
-		authResponse := models.AuthResponse{
-			Token:     token,
-			User:      userInfo,
-			ExpiresAt: expiresAt,
-		}
-
-		c.JSON(http.StatusOK, h.responseUtils.SuccessResponse(
-			h.localizer.Get(lang, "login_successful"),
-			authResponse,
-		))
 	}
+	clearAuthCookies(c, h.authCfg)
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "logout_successful"),
+		nil,
+	))
 }
 
 // UserHandler handles user-related requests
 type UserHandler struct {
-	mongoDB       *database.MongoDB
-	postgresDB    *database.PostgresDB
+	users         services.UserService
+	metadata      utils.MetadataSchema
 	logger        utils.Logger
 	localizer     *utils.Localizer
+	securityLog   *security.EventLogger
+	bulk          *jobs.BulkUsers
+	operations    *operations.Queue
 	responseUtils *utils.ResponseUtils
 }
 
-// NewUserHandler creates a new user handler
-func NewUserHandler(mongoDB *database.MongoDB, postgresDB *database.PostgresDB, logger utils.Logger, localizer *utils.Localizer) *UserHandler {
+// NewUserHandler creates a new user handler accepting the custom attributes of metadata; role changes
+// are recorded in securityLog. Bulk actions run in the background on request when queue is not nil.
+func NewUserHandler(users services.UserService, metadata utils.MetadataSchema, logger utils.Logger, localizer *utils.Localizer, securityLog *security.EventLogger, queue *operations.Queue) *UserHandler {
 	return &UserHandler{
-		mongoDB:       mongoDB,
-		postgresDB:    postgresDB,
+		users:         users,
+		metadata:      metadata,
 		logger:        logger,
 		localizer:     localizer,
+		securityLog:   securityLog,
+		bulk:          jobs.NewBulkUsers(users, securityLog, logger),
+		operations:    queue,
 		responseUtils: &utils.ResponseUtils{},
 	}
 }
 
+// abortPreconditionFailed writes the 412 response for a stale If-Match or a concurrent update
+func (h *UserHandler) abortPreconditionFailed(c *gin.Context, lang string) {
+	h.responseUtils.Respond(c, http.StatusPreconditionFailed, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "precondition_failed",
+		"The profile was modified since it was last retrieved",
+	))
+}
+
+// parseFields reads the ?fields= sparse fieldset; it writes 400 and returns false for unknown fields
+func (h *UserHandler) parseFields(c *gin.Context, lang, raw string) (utils.FieldSet, bool) {
+	fields, err := utils.ParseFields(raw, utils.UserFields)
+	if err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return nil, false
+	}
+	return fields, true
+}
+
+// respondServiceError writes 400, 404, 409, or 412 for the user service's sentinel errors and 500 with
+// detail for anything else
+func (h *UserHandler) respondServiceError(c *gin.Context, lang string, err error, detail string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidUserID):
+		h.respondInvalidUserID(c, lang)
+	case errors.Is(err, services.ErrUserNotFound):
+		h.respondUserNotFound(c, lang)
+	case errors.Is(err, services.ErrPreconditionFailed):
+		h.abortPreconditionFailed(c, lang)
+	case errors.Is(err, utils.ErrInvalidCursor):
+		h.respondInvalidCursor(c, lang, err)
+	case respondDuplicateUser(c, h.localizer, h.responseUtils, lang, err):
+	case respondRejection(c, h.localizer, h.responseUtils, lang, err):
+	default:
+		h.logger.Error(detail, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			detail,
+		))
+	}
+}
+
 // GetProfile godoc
 // @Summary Get user profile
+// @ID getProfile
 // @Description Get the current user's profile information
 // @Tags users
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Security Bearer
+// @Param fields query string false "Comma-separated fields to return" example(id,email,username)
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.APIResponse{data=models.UserInfo}
+// @Success 304 "Not modified"
+// @Failure 400 {object} models.APIResponse
 // @Failure 401 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /users/profile [get]
 func (h *UserHandler) GetProfile(c *gin.Context) {
-	userID := c.GetString("user_id")
-	lang := c.GetString("language")
-
-	// PostgreSQL implementation
-	if h.postgresDB != nil {
-		var user models.User
-		id, _ := strconv.ParseUint(userID, 10, 32)
-		if err := h.postgresDB.First(&user, uint(id)).Error; err != nil {
-			h.logger.Error("User not found in PostgreSQL", "user_id", userID)
-			c.JSON(http.StatusNotFound, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "user_not_found"),
-				"User not found",
-			))
-			return
-		}
-
-		userInfo := models.UserInfo{
-			ID:        user.ID,
-			Email:     user.Email,
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		}
+	lang := utils.Scope(c).Language
 
-		c.JSON(http.StatusOK, h.responseUtils.SuccessResponse("Profile retrieved successfully", userInfo))
+	fields, ok := h.parseFields(c, lang, c.Query("fields"))
+	if !ok {
 		return
 	}
 
-	// MongoDB implementation
-	if h.mongoDB != nil {
-		collection := h.mongoDB.Collection("users")
-		objectID, err := primitive.ObjectIDFromHex(userID)
-		if err != nil {
-			h.logger.Error("Invalid user ID format", "user_id", userID)
-			c.JSON(http.StatusBadRequest, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "bad_request"),
-				"Invalid user ID format",
-			))
-			return
-		}
-
-		var user models.UserMongo
-		if err := collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&user); err != nil {
-			h.logger.Error("User not found in MongoDB", "user_id", userID)
-			c.JSON(http.StatusNotFound, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "user_not_found"),
-				"User not found",
-			))
-			return
-		}
-
-		userInfo := models.UserInfo{
-			ID:        user.ID.Hex(),
-			Email:     user.Email,
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		}
+	userInfo, err := h.users.GetProfile(c.Request.Context(), utils.Scope(c).UserID, fields)
+	if err != nil {
+		h.respondServiceError(c, lang, err, "Failed to retrieve profile")
+		return
+	}
 
-		c.JSON(http.StatusOK, h.responseUtils.SuccessResponse("Profile retrieved successfully", userInfo))
+	data := fields.Project(userInfo)
+	if notModified(c, data) {
+		return
 	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse("Profile retrieved successfully", data))
 }
 
 // UpdateProfile godoc
 // @Summary Update user profile
+// @ID updateProfile
 // @Description Update the current user's profile information
 // @Tags users
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Security Bearer
 // @Param request body models.UpdateUserRequest true "User update data"
+// @Param If-Match header string false "ETag of the profile being updated; rejects the update if it changed"
 // @Success 200 {object} models.APIResponse{data=models.UserInfo}
 // @Failure 400 {object} models.APIResponse
 // @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 412 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /users/profile [put]
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	var req models.UpdateUserRequest
-	userID := c.GetString("user_id")
-	lang := c.GetString("language")
+	lang := utils.Scope(c).Language
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Profile update validation failed", "error", err)
-		c.JSON(http.StatusBadRequest, h.responseUtils.ErrorResponse(
-			h.localizer.Get(lang, "validation_error"),
-			err.Error(),
-		))
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
 		return
 	}
-
-	// PostgreSQL implementation
-	if h.postgresDB != nil {
-		var user models.User
-		id, _ := strconv.ParseUint(userID, 10, 32)
-		if err := h.postgresDB.First(&user, uint(id)).Error; err != nil {
-			h.logger.Error("User not found in PostgreSQL", "user_id", userID)
-			c.JSON(http.StatusNotFound, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "user_not_found"),
-				"User not found",
-			))
-			return
-		}
-
-		// Update fields
-		if req.FirstName != "" {
-			user.FirstName = req.FirstName
-		}
-		if req.LastName != "" {
-			user.LastName = req.LastName
-		}
-		if req.Email != "" {
-			user.Email = req.Email
-		}
-		user.UpdatedAt = time.Now()
-
-		if err := h.postgresDB.Save(&user).Error; err != nil {
-			h.logger.Error("Failed to update user in PostgreSQL", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to update profile",
-			))
-			return
-		}
-
-		userInfo := models.UserInfo{
-			ID:        user.ID,
-			Email:     user.Email,
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		}
-
-		c.JSON(http.StatusOK, h.responseUtils.SuccessResponse(
-			h.localizer.Get(lang, "user_updated"),
-			userInfo,
-		))
+	if !resolveLocale(c, h.localizer, h.responseUtils, lang, &req.Locale) {
+		return
+	}
+	if err := h.metadata.Validate(req.Metadata); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
 		return
 	}
 
-	// MongoDB implementation
-	if h.mongoDB != nil {
-		collection := h.mongoDB.Collection("users")
-		objectID, err := primitive.ObjectIDFromHex(userID)
-		if err != nil {
-			h.logger.Error("Invalid user ID format", "user_id", userID)
-			c.JSON(http.StatusBadRequest, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "bad_request"),
-				"Invalid user ID format",
-			))
-			return
-		}
-
-		update := bson.M{
-			"$set": bson.M{
-				"updated_at": time.Now(),
-			},
-		}
-
-		if req.FirstName != "" {
-			update["$set"].(bson.M)["first_name"] = req.FirstName
-		}
-		if req.LastName != "" {
-			update["$set"].(bson.M)["last_name"] = req.LastName
-		}
-		if req.Email != "" {
-			update["$set"].(bson.M)["email"] = req.Email
-		}
-
-		_, err = collection.UpdateOne(context.Background(), bson.M{"_id": objectID}, update)
-		if err != nil {
-			h.logger.Error("Failed to update user in MongoDB", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to update profile",
-			))
-			return
-		}
-
-		// Get updated user
-		var user models.UserMongo
-		if err := collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&user); err != nil {
-			h.logger.Error("Failed to retrieve updated user", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to retrieve updated profile",
-			))
-			return
-		}
-
-		userInfo := models.UserInfo{
-			ID:        user.ID.Hex(),
-			Email:     user.Email,
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		}
+	userInfo, err := h.users.UpdateProfile(c.Request.Context(), utils.Scope(c).UserID, req, c.GetHeader("If-Match"))
+	if err != nil {
+		h.respondServiceError(c, lang, err, "Failed to update profile")
+		return
+	}
+	lang = userLanguage(c, lang, userInfo.Locale)
 
-		c.JSON(http.StatusOK, h.responseUtils.SuccessResponse(
-			h.localizer.Get(lang, "user_updated"),
-			userInfo,
-		))
+	if etag, err := utils.ETag(userInfo); err == nil {
+		c.Header("ETag", etag)
 	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "user_updated"),
+		userInfo,
+	))
 }
 
 // GetUsers godoc
 // @Summary Get all users (Admin only)
+// @ID getUsers
 // @Description Get paginated list of all users
 // @Tags users
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Security Bearer
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
-// @Param sort query string false "Sort order" default("created_at:desc")
-// @Param search query string false "Search term"
-// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
+// @Param sort query string false "Comma-separated column:asc|desc pairs; without it, search results are ordered by relevance" default(created_at:desc) example(role:asc,created_at:desc)
+// @Param search query string false "Words to find in the name, username, or email, as word prefixes; a search matching no words is matched as a substring"
+// @Param fields query string false "Comma-separated fields to return" example(id,email,username)
+// @Param role query string false "Filter by role; any filterable field also accepts [ne], [gt], [lt], or [in] (e.g. role[in]=admin,user)"
+// @Param is_active query bool false "Filter by active status"
+// @Param email_status query string false "Filter by email status: the users whose address bounced or complained, to whom no email is sent" Enums(bounced, complained)
+// @Param metadata.name query string false "Filter by the custom attribute name of USER_METADATA_SCHEMA, such as metadata.plan=pro"
+// @Param created_after query string false "Only users created after this date (YYYY-MM-DD or RFC 3339)"
+// @Param created_before query string false "Only users created before this date (YYYY-MM-DD or RFC 3339)"
+// @Param cursor query string false "Keyset pagination cursor from next/prev; pass an empty cursor for the first page"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse{data=[]models.UserInfo}}
+// @Success 304 "Not modified"
+// @Failure 400 {object} models.APIResponse
 // @Failure 401 {object} models.APIResponse
 // @Failure 403 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /users [get]
 func (h *UserHandler) GetUsers(c *gin.Context) {
 	var query models.PaginationQuery
-	lang := c.GetString("language")
+	lang := utils.Scope(c).Language
 
 	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, h.responseUtils.ErrorResponse(
-			h.localizer.Get(lang, "validation_error"),
-			err.Error(),
-		))
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
 		return
 	}
 
-	// PostgreSQL implementation
-	if h.postgresDB != nil {
-		var users []models.User
-		var total int64
+	fields, ok := h.parseFields(c, lang, query.Fields)
+	if !ok {
+		return
+	}
 
-		db := h.postgresDB.Model(&models.User{})
+	filters, err := utils.ParseFilters(c.Request.URL.Query(), h.metadata.Filters(utils.UserFilters))
+	if err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
 
-		// Apply search filter
-		if query.Search != "" {
-			searchPattern := "%" + query.Search + "%"
-			db = db.Where("first_name ILIKE ? OR last_name ILIKE ? OR email ILIKE ? OR username ILIKE ?",
-				searchPattern, searchPattern, searchPattern, searchPattern)
-		}
+	sort, err := utils.ParseSort(query.Sort, utils.UserSortFields, utils.DefaultUserSort)
+	if err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
 
-		// Count total records
-		db.Count(&total)
+	listQuery := services.ListUsersQuery{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+		Search:   query.Search,
+		Fields:   fields,
+		Filters:  filters,
+		Sort:     sort,
+		Rank:     query.Search != "" && strings.TrimSpace(query.Sort) == "",
+	}
 
-		// Apply pagination
-		offset := (query.Page - 1) * query.PageSize
-		db = db.Offset(offset).Limit(query.PageSize)
+	// Keyset pagination when a cursor parameter is present (empty for the first page)
+	if _, ok := c.GetQuery("cursor"); ok {
+		h.getUsersByCursor(c, lang, listQuery, query.Cursor)
+		return
+	}
 
-		// Apply sorting
-		if query.Sort != "" {
-			db = db.Order(query.Sort)
-		} else {
-			db = db.Order("created_at DESC")
-		}
+	userInfos, total, err := h.users.ListUsers(c.Request.Context(), listQuery)
+	if err != nil {
+		h.respondServiceError(c, lang, err, "Failed to retrieve users")
+		return
+	}
 
-		if err := db.Find(&users).Error; err != nil {
-			h.logger.Error("Failed to retrieve users from PostgreSQL", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to retrieve users",
-			))
-			return
-		}
+	pagination := models.Pagination{
+		Page:      query.Page,
+		PageSize:  query.PageSize,
+		Total:     total,
+		TotalPage: int((total + int64(query.PageSize) - 1) / int64(query.PageSize)),
+	}
 
-		// Convert to UserInfo
-		userInfos := make([]models.UserInfo, len(users))
-		for i, user := range users {
-			userInfos[i] = models.UserInfo{
-				ID:        user.ID,
-				Email:     user.Email,
-				Username:  user.Username,
-				FirstName: user.FirstName,
-				LastName:  user.LastName,
-				Role:      user.Role,
-				IsActive:  user.IsActive,
-				CreatedAt: user.CreatedAt,
-				UpdatedAt: user.UpdatedAt,
-			}
-		}
+	response := h.responseUtils.PaginatedResponse(fields.Project(userInfos), pagination)
+	if notModified(c, response) {
+		return
+	}
 
-		pagination := models.Pagination{
-			Page:      query.Page,
-			PageSize:  query.PageSize,
-			Total:     total,
-			TotalPage: int((total + int64(query.PageSize) - 1) / int64(query.PageSize)),
-		}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse("Users retrieved successfully", response))
+}
 
-		response := h.responseUtils.PaginatedResponse(userInfos, pagination)
-		c.JSON(http.StatusOK, h.responseUtils.SuccessResponse("Users retrieved successfully", response))
+// ExportUsers godoc
+// @Summary Export users (Admin only)
+// @ID exportUsers
+// @Description Download every user matching the search and filters of GET /users, as NDJSON (one JSON object per line) or CSV with a header row. Users are read from the database and written as the download proceeds, so exports of any size use little memory, and the request has no deadline. CSV cells of objects such as metadata hold JSON, and text starting with =, +, -, or @ is prefixed with a quote so spreadsheets do not run it. If the export fails midway, the connection is closed before the end of the response.
+// @Tags users
+// @Produce application/x-ndjson,text/csv
+// @Security Bearer
+// @Param format query string false "Export format" Enums(ndjson, csv) default(ndjson)
+// @Param sort query string false "Comma-separated column:asc|desc pairs; without it, search results are ordered by relevance" default(created_at:desc)
+// @Param search query string false "Words to find in the name, username, or email"
+// @Param fields query string false "Comma-separated fields to export, and the CSV columns in order" example(id,email,username)
+// @Param role query string false "Filter by role; the filters of GET /users apply"
+// @Param is_active query bool false "Filter by active status"
+// @Param email_status query string false "Filter by email status" Enums(bounced, complained)
+// @Param created_after query string false "Only users created after this date (YYYY-MM-DD or RFC 3339)"
+// @Param created_before query string false "Only users created before this date (YYYY-MM-DD or RFC 3339)"
+// @Success 200 {string} string "The users, one per line"
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/users/export [get]
+func (h *UserHandler) ExportUsers(c *gin.Context) {
+	var query models.ExportUsersQuery
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
 		return
 	}
 
-	// MongoDB implementation
-	if h.mongoDB != nil {
-		collection := h.mongoDB.Collection("users")
-		ctx := context.Background()
-
-		// Build filter
-		filter := bson.M{}
-		if query.Search != "" {
-			filter = bson.M{
-				"$or": []bson.M{
-					{"first_name": bson.M{"$regex": query.Search, "$options": "i"}},
-					{"last_name": bson.M{"$regex": query.Search, "$options": "i"}},
-					{"email": bson.M{"$regex": query.Search, "$options": "i"}},
-					{"username": bson.M{"$regex": query.Search, "$options": "i"}},
-				},
-			}
-		}
+	fields, ok := h.parseFields(c, lang, query.Fields)
+	if !ok {
+		return
+	}
 
-		// Count total documents
-		total, err := collection.CountDocuments(ctx, filter)
-		if err != nil {
-			h.logger.Error("Failed to count users in MongoDB", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to count users",
-			))
-			return
-		}
+	filters, err := utils.ParseFilters(c.Request.URL.Query(), h.metadata.Filters(utils.UserFilters))
+	if err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
 
-		// Find documents with pagination
-		skip := int64((query.Page - 1) * query.PageSize)
-		limit := int64(query.PageSize)
+	sort, err := utils.ParseSort(query.Sort, utils.UserSortFields, utils.DefaultUserSort)
+	if err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
 
-		cursor, err := collection.Find(ctx, filter, options.Find().SetSkip(skip).SetLimit(limit))
-		if err != nil {
-			h.logger.Error("Failed to retrieve users from MongoDB", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to retrieve users",
-			))
-			return
-		}
-		defer cursor.Close(ctx)
-
-		var users []models.UserMongo
-		if err := cursor.All(ctx, &users); err != nil {
-			h.logger.Error("Failed to decode users from MongoDB", "error", err)
-			c.JSON(http.StatusInternalServerError, h.responseUtils.ErrorResponse(
-				h.localizer.Get(lang, "internal_error"),
-				"Failed to decode users",
-			))
-			return
-		}
+	columns := []string(fields)
+	if len(columns) == 0 {
+		columns = utils.UserFields
+	}
+	listQuery := services.ListUsersQuery{
+		Search:  query.Search,
+		Fields:  fields,
+		Filters: filters,
+		Sort:    sort,
+		Rank:    query.Search != "" && strings.TrimSpace(query.Sort) == "",
+	}
+	streamExport(c, h.logger, h.localizer, h.responseUtils, query.Format, "users", columns, func(write func(interface{}) error) error {
+		return h.users.EachUser(c.Request.Context(), listQuery, func(user models.UserInfo) error {
+			return write(fields.Project(user))
+		})
+	})
+}
 
-		// Convert to UserInfo
-		userInfos := make([]models.UserInfo, len(users))
-		for i, user := range users {
-			userInfos[i] = models.UserInfo{
-				ID:        user.ID.Hex(),
-				Email:     user.Email,
-				Username:  user.Username,
-				FirstName: user.FirstName,
-				LastName:  user.LastName,
-				Role:      user.Role,
-				IsActive:  user.IsActive,
-				CreatedAt: user.CreatedAt,
-				UpdatedAt: user.UpdatedAt,
-			}
-		}
+// ServiceGetUsers godoc
+// @Summary List users for a service
+// @ID serviceGetUsers
+// @Description List users like GET /users, for other backends authenticated with a client credentials token that has the users:read scope
+// @Tags service
+// @Produce json,xml,application/msgpack
+// @Security Bearer
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param sort query string false "Comma-separated column:asc|desc pairs" default(created_at:desc)
+// @Param search query string false "Words to find in the name, username, or email"
+// @Param fields query string false "Comma-separated fields to return" example(id,email,username)
+// @Param cursor query string false "Keyset pagination cursor from next/prev; pass an empty cursor for the first page"
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse{data=[]models.UserInfo}}
+// @Success 304 "Not modified"
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.OAuthError
+// @Failure 403 {object} models.OAuthError
+// @Failure 500 {object} models.APIResponse
+// @Router /service/users [get]
+func (h *UserHandler) ServiceGetUsers(c *gin.Context) {
+	h.GetUsers(c)
+}
 
-		pagination := models.Pagination{
-			Page:      query.Page,
-			PageSize:  query.PageSize,
-			Total:     total,
-			TotalPage: int((total + int64(query.PageSize) - 1) / int64(query.PageSize)),
-		}
+// DeleteUser godoc
+// @Summary Delete a user (Admin only)
+// @ID deleteUser
+// @Description Soft-delete a user: they can no longer log in and are hidden from listings until restored or purged. Only superadmins can delete superadmins, and the last active superadmin cannot be deleted.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	userID := c.Param("id")
+	lang := utils.Scope(c).Language
+
+	if userID == utils.Scope(c).UserID {
+		h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "bad_request",
+			"You cannot delete your own account",
+		))
+		return
+	}
+
+	err := h.users.DeleteUser(c.Request.Context(), userID, utils.Scope(c).Role)
+	if errors.Is(err, services.ErrSuperadminTarget) {
+		h.responseUtils.Respond(c, http.StatusForbidden, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "insufficient_permissions",
+			"Only superadmins can delete superadmins",
+		))
+		return
+	}
+	if errors.Is(err, services.ErrLastSuperadmin) {
+		h.responseUtils.Respond(c, http.StatusConflict, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "last_superadmin",
+			"The last superadmin cannot be deleted",
+		))
+		return
+	}
+	if err != nil {
+		h.respondServiceError(c, lang, err, "Failed to delete user")
+		return
+	}
+
+	h.logger.Info("User soft-deleted", "user_id", userID, "by", utils.Scope(c).UserID)
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(h.localizer.Get(lang, "user_deleted"), nil))
+}
+
+// RestoreUser godoc
+// @Summary Restore a deleted user (Admin only)
+// @ID restoreUser
+// @Description Undo a soft delete that has not been purged yet
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Success 200 {object} models.APIResponse{data=models.UserInfo}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/users/{id}/restore [post]
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	userID := c.Param("id")
+	lang := utils.Scope(c).Language
+
+	userInfo, err := h.users.RestoreUser(c.Request.Context(), userID)
+	if err != nil {
+		h.respondServiceError(c, lang, err, "Failed to restore user")
+		return
+	}
+
+	h.logger.Info("User restored", "user_id", userID, "by", utils.Scope(c).UserID)
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(h.localizer.Get(lang, "user_restored"), userInfo))
+}
+
+// ChangeRole godoc
+// @Summary Change a user's role (Superadmin only)
+// @ID changeUserRole
+// @Description Give a user the user, admin, or superadmin role. The reason is recorded with the change in the security log, and the user receives a role.changed event. Tokens issued before the change keep the old role until they expire. The last active superadmin cannot be demoted.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Param request body models.ChangeRoleRequest true "New role and reason"
+// @Success 200 {object} models.APIResponse{data=models.UserInfo}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/users/{id}/role [patch]
+func (h *UserHandler) ChangeRole(c *gin.Context) {
+	var req models.ChangeRoleRequest
+	userID := c.Param("id")
+	lang := utils.Scope(c).Language
 
-		response := h.responseUtils.PaginatedResponse(userInfos, pagination)
-		c.JSON(http.StatusOK, h.responseUtils.SuccessResponse("Users retrieved successfully", response))
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
 	}
+
+	userInfo, previous, err := h.users.ChangeRole(c.Request.Context(), userID, req.Role)
+	if errors.Is(err, services.ErrLastSuperadmin) {
+		h.securityLog.LogRequest(c, security.Event{
+			Type:    security.EventRoleChange,
+			Outcome: security.OutcomeFailure,
+			UserID:  userID,
+			Reason:  req.Reason,
+			Details: map[string]string{"role": req.Role, "error": "last_superadmin"},
+		})
+		h.responseUtils.Respond(c, http.StatusConflict, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "last_superadmin",
+			"The last superadmin cannot be demoted",
+		))
+		return
+	}
+	if err != nil {
+		h.respondServiceError(c, lang, err, "Failed to change role")
+		return
+	}
+
+	h.securityLog.LogRequest(c, security.Event{
+		Type:    security.EventRoleChange,
+		Outcome: security.OutcomeSuccess,
+		UserID:  userID,
+		Email:   userInfo.Email,
+		Reason:  req.Reason,
+		Details: map[string]string{"previous_role": previous, "role": req.Role},
+	})
+	h.logger.Info("User role changed", "user_id", userID, "from", previous, "to", req.Role, "by", utils.Scope(c).UserID)
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(h.localizer.Get(lang, "role_changed"), userInfo))
+}
+
+// respondInvalidUserID writes 400 for a path ID that is not valid for the active backend
+func (h *UserHandler) respondInvalidUserID(c *gin.Context, lang string) {
+	h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "bad_request",
+		"Invalid user ID format",
+	))
+}
+
+// respondUserNotFound writes 404 for a user that does not exist or is not in the expected state
+func (h *UserHandler) respondUserNotFound(c *gin.Context, lang string) {
+	h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "user_not_found",
+		"User not found",
+	))
 }
 
 // HealthHandler handles health check requests
 type HealthHandler struct {
+	cfg           config.HealthConfig
 	mongoDB       *database.MongoDB
 	postgresDB    *database.PostgresDB
 	logger        utils.Logger
 	responseUtils *utils.ResponseUtils
+
+	mu          sync.Mutex
+	lastSuccess map[string]time.Time
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(mongoDB *database.MongoDB, postgresDB *database.PostgresDB, logger utils.Logger) *HealthHandler {
+func NewHealthHandler(cfg config.HealthConfig, mongoDB *database.MongoDB, postgresDB *database.PostgresDB, logger utils.Logger) *HealthHandler {
 	return &HealthHandler{
+		cfg:           cfg,
 		mongoDB:       mongoDB,
 		postgresDB:    postgresDB,
 		logger:        logger,
 		responseUtils: &utils.ResponseUtils{},
+		lastSuccess:   make(map[string]time.Time),
+	}
+}
+
+// healthCheck is one dependency check; the service is down when a critical check fails and degraded
+// when any other check fails or a check is slow
+type healthCheck struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+// checks lists the checks of the configured dependencies
+func (h *HealthHandler) checks() []healthCheck {
+	var checks []healthCheck
+
+	// PostgreSQL, or SQLite standing in for it; reads fall back to the primary while a replica is down
+	if h.postgresDB != nil {
+		name := "postgresql"
+		if h.postgresDB.IsSQLite() {
+			name = "sqlite"
+		}
+		checks = append(checks, healthCheck{name: name, critical: true, check: h.postgresDB.HealthCheck})
+		for _, replica := range h.postgresDB.ReplicaNames() {
+			checks = append(checks, healthCheck{
+				name: name + "_" + replica,
+				check: func(ctx context.Context) error {
+					return h.postgresDB.CheckReplica(ctx, replica)
+				},
+			})
+		}
+	}
+
+	if h.mongoDB != nil {
+		checks = append(checks, healthCheck{name: "mongodb", critical: true, check: h.mongoDB.HealthCheck})
+	}
+
+	return checks
+}
+
+// run performs a check within the configured timeout
+func (h *HealthHandler) run(ctx context.Context, check healthCheck) models.ServiceHealth {
+	ctx, cancel := context.WithTimeout(ctx, h.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.check(ctx)
+	latency := time.Since(start)
+
+	result := models.ServiceHealth{
+		Status:    "healthy",
+		Critical:  check.critical,
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+	}
+	switch {
+	case err != nil:
+		result.Status = "unhealthy"
+		h.logger.Error("Health check failed", "service", check.name, "latency", latency, "error", err)
+	case h.cfg.DegradedLatency > 0 && latency > h.cfg.DegradedLatency:
+		result.Status = "degraded"
+		h.logger.Warn("Health check is slow", "service", check.name, "latency", latency)
+	}
+
+	h.mu.Lock()
+	if err == nil {
+		h.lastSuccess[check.name] = start
+	}
+	if lastSuccess, ok := h.lastSuccess[check.name]; ok {
+		result.LastSuccess = &lastSuccess
 	}
+	h.mu.Unlock()
+
+	return result
 }
 
 // HealthCheck godoc
 // @Summary Health check
-// @Description Check the health status of the API and connected services
+// @ID healthCheck
+// @Description Check the health of the API and its dependencies. Checks run concurrently; each reports its latency and last success. The status is "degraded" (200) when a non-critical dependency is down or a check is slow, and "unhealthy" (503) when a critical one is down.
 // @Tags health
 // @Accept json
 // @Produce json
 // @Success 200 {object} models.APIResponse{data=models.HealthResponse}
-// @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse{data=models.HealthResponse}
 // @Router /health [get]
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
-	services := make(map[string]string)
-	overallStatus := "healthy"
-
-	// Check PostgreSQL
-	if h.postgresDB != nil {
-		if err := h.postgresDB.HealthCheck(); err != nil {
-			services["postgresql"] = "unhealthy"
-			overallStatus = "unhealthy"
-			h.logger.Error("PostgreSQL health check failed", "error", err)
-		} else {
-			services["postgresql"] = "healthy"
-		}
-	}
-
-	// Check MongoDB
-	if h.mongoDB != nil {
-		if err := h.mongoDB.HealthCheck(); err != nil {
-			services["mongodb"] = "unhealthy"
-			overallStatus = "unhealthy"
-			h.logger.Error("MongoDB health check failed", "error", err)
-		} else {
-			services["mongodb"] = "healthy"
-		}
+	checks := h.checks()
+	results := make([]models.ServiceHealth, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.run(c.Request.Context(), check)
+		}()
 	}
+	wg.Wait()
 
 	healthResponse := models.HealthResponse{
-		Status:    overallStatus,
+		Status:    "healthy",
 		Timestamp: time.Now(),
-		Services:  services,
+		Services:  make(map[string]string, len(checks)),
+		Checks:    make(map[string]models.ServiceHealth, len(checks)),
 		Version:   "1.0.0",
 	}
+	for i, check := range checks {
+		result := results[i]
+		healthResponse.Services[check.name] = result.Status
+		healthResponse.Checks[check.name] = result
+
+		switch {
+		case result.Status == "unhealthy" && check.critical:
+			healthResponse.Status = "unhealthy"
+		case result.Status != "healthy" && healthResponse.Status == "healthy":
+			healthResponse.Status = "degraded"
+		}
+	}
 
-	if overallStatus == "healthy" {
-		c.JSON(http.StatusOK, h.responseUtils.SuccessResponse("System is healthy", healthResponse))
-	} else {
-		c.JSON(http.StatusServiceUnavailable, h.responseUtils.ErrorResponse("System is unhealthy", "One or more services are down"))
+	switch healthResponse.Status {
+	case "healthy":
+		h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse("System is healthy", healthResponse))
+	case "degraded":
+		h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse("System is degraded", healthResponse))
+	default:
+		response := h.responseUtils.ErrorResponse("System is unhealthy", "One or more critical services are down")
+		response.Code, response.Data = errcodes.Unavailable, healthResponse
+		h.responseUtils.Respond(c, http.StatusServiceUnavailable, response)
 	}
 }
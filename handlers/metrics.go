@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/database"
+	"go-backend-template/httpclient"
+	"go-backend-template/middleware"
+	"go-backend-template/security"
+)
+
+// MetricsHandler exposes runtime metrics in the Prometheus text format for scrapers
+type MetricsHandler struct {
+	token       string
+	mongoDB     *database.MongoDB
+	postgresDB  *database.PostgresDB
+	queryStats  *database.QueryStats
+	timeouts    *middleware.TimeoutStats
+	concurrency *middleware.ConcurrencyLimiter
+	throttle    *security.LoginThrottle
+	bots        *security.BotDetector
+}
+
+// NewMetricsHandler creates a new metrics handler; a non-empty token must be sent as a bearer token. The
+// timeout, concurrency, login throttle, and bot detection metrics are left out when timeouts, concurrency,
+// throttle, or bots is nil.
+func NewMetricsHandler(token string, mongoDB *database.MongoDB, postgresDB *database.PostgresDB, queryStats *database.QueryStats, timeouts *middleware.TimeoutStats, concurrency *middleware.ConcurrencyLimiter, throttle *security.LoginThrottle, bots *security.BotDetector) *MetricsHandler {
+	return &MetricsHandler{
+		token:       token,
+		mongoDB:     mongoDB,
+		postgresDB:  postgresDB,
+		queryStats:  queryStats,
+		timeouts:    timeouts,
+		concurrency: concurrency,
+		throttle:    throttle,
+		bots:        bots,
+	}
+}
+
+// poolMetrics lists the exported pool metrics and how to read each from a snapshot
+var poolMetrics = []struct {
+	name, kind, help string
+	value            func(database.PoolStats) float64
+}{
+	{"db_pool_max_open_connections", "gauge", "Maximum number of open connections (per server for MongoDB); 0 is unlimited",
+		func(s database.PoolStats) float64 { return float64(s.MaxOpen) }},
+	{"db_pool_open_connections", "gauge", "Number of established connections, in use and idle",
+		func(s database.PoolStats) float64 { return float64(s.Open) }},
+	{"db_pool_in_use_connections", "gauge", "Number of connections currently in use",
+		func(s database.PoolStats) float64 { return float64(s.InUse) }},
+	{"db_pool_idle_connections", "gauge", "Number of idle connections",
+		func(s database.PoolStats) float64 { return float64(s.Idle) }},
+	{"db_pool_wait_count_total", "counter", "Total number of connection requests that had to wait for a connection",
+		func(s database.PoolStats) float64 { return float64(s.WaitCount) }},
+	{"db_pool_wait_seconds_total", "counter", "Total time spent waiting for connections",
+		func(s database.PoolStats) float64 { return s.WaitDuration.Seconds() }},
+}
+
+// routeMetrics lists the exported per-route query metrics
+var routeMetrics = []struct {
+	name, kind, help string
+	value            func(database.RouteQueries) float64
+}{
+	{"http_route_requests_total", "counter", "Total number of requests to the route",
+		func(r database.RouteQueries) float64 { return float64(r.Requests) }},
+	{"db_route_queries_total", "counter", "Total number of database queries run by requests to the route",
+		func(r database.RouteQueries) float64 { return float64(r.Queries) }},
+	{"db_route_query_seconds_total", "counter", "Total time spent in database queries by requests to the route",
+		func(r database.RouteQueries) float64 { return r.Duration.Seconds() }},
+	{"db_route_max_queries", "gauge", "Largest number of database queries run by a single request to the route",
+		func(r database.RouteQueries) float64 { return float64(r.MaxQueries) }},
+}
+
+// throttleMetrics lists the exported login throttle metrics
+var throttleMetrics = []struct {
+	name, kind, help string
+	value            func(security.ThrottleStats) float64
+}{
+	{"auth_login_delayed_total", "counter", "Total number of login attempts refused because they came before the throttle delay elapsed",
+		func(s security.ThrottleStats) float64 { return float64(s.Delayed) }},
+	{"auth_login_blocked_total", "counter", "Total number of login attempts refused because the email and IP were blocked",
+		func(s security.ThrottleStats) float64 { return float64(s.Blocked) }},
+	{"auth_login_blocks_total", "counter", "Total number of email and IP pairs blocked after repeated failed logins",
+		func(s security.ThrottleStats) float64 { return float64(s.Blocks) }},
+	{"auth_login_blocked_keys", "gauge", "Number of email and IP pairs blocked now",
+		func(s security.ThrottleStats) float64 { return float64(s.BlockedKeys) }},
+}
+
+// concurrencyMetrics lists the exported metrics of the concurrency limiter
+var concurrencyMetrics = []struct {
+	name, kind, help string
+	value            func(middleware.ConcurrencyStats) float64
+}{
+	{"http_requests_in_flight", "gauge", "Number of requests being served, overall or in the route group",
+		func(s middleware.ConcurrencyStats) float64 { return float64(s.InFlight) }},
+	{"http_requests_in_flight_limit", "gauge", "Most requests served at once, overall or in the route group; 0 is unlimited",
+		func(s middleware.ConcurrencyStats) float64 { return float64(s.Limit) }},
+	{"http_requests_in_flight_reserved", "gauge", "Slots of the limit only priority requests are served in",
+		func(s middleware.ConcurrencyStats) float64 { return float64(s.Reserved) }},
+	{"http_requests_shed_total", "counter", "Total number of requests answered 503 because the limit of in-flight requests was reached",
+		func(s middleware.ConcurrencyStats) float64 { return float64(s.Rejected) }},
+}
+
+// concurrencyGroupLabel is the group label of the load of a route group, "all" for the overall load
+func concurrencyGroupLabel(group string) string {
+	if group == "" {
+		return "all"
+	}
+	return group
+}
+
+// clientMetrics lists the exported metrics of the calls to other services
+var clientMetrics = []struct {
+	name, kind, help string
+	value            func(httpclient.ClientStats) float64
+}{
+	{"http_client_requests_total", "counter", "Total number of requests sent to the service, retries included",
+		func(s httpclient.ClientStats) float64 { return float64(s.Requests) }},
+	{"http_client_failures_total", "counter", "Total number of requests that did not reach the service or were answered with a server error",
+		func(s httpclient.ClientStats) float64 { return float64(s.Failures) }},
+	{"http_client_retries_total", "counter", "Total number of retried requests",
+		func(s httpclient.ClientStats) float64 { return float64(s.Retries) }},
+	{"http_client_rejected_total", "counter", "Total number of requests refused while the circuit was open",
+		func(s httpclient.ClientStats) float64 { return float64(s.Rejected) }},
+	{"http_client_request_seconds_total", "counter", "Total time spent in requests to the service",
+		func(s httpclient.ClientStats) float64 { return s.Duration.Seconds() }},
+	{"http_client_circuit_open", "gauge", "Whether the circuit of the service is open (1) or closed (0)",
+		func(s httpclient.ClientStats) float64 {
+			if s.Open {
+				return 1
+			}
+			return 0
+		}},
+}
+
+// Metrics writes the connection pool statistics of every configured database, the query and timeout counts
+// per route, the calls to other services, and the login throttle and bot detection counters
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	if h.token != "" {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	type pool struct {
+		database string
+		stats    database.PoolStats
+	}
+	var pools []pool
+	if h.postgresDB != nil {
+		name := "postgresql"
+		if h.postgresDB.IsSQLite() {
+			name = "sqlite"
+		}
+		for _, stats := range h.postgresDB.PoolStats() {
+			pools = append(pools, pool{database: name, stats: stats})
+		}
+	}
+	if h.mongoDB != nil {
+		pools = append(pools, pool{database: "mongodb", stats: h.mongoDB.PoolStats()})
+	}
+
+	var body strings.Builder
+	for _, metric := range poolMetrics {
+		fmt.Fprintf(&body, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, p := range pools {
+			fmt.Fprintf(&body, "%s{database=%q,node=%q} %g\n", metric.name, p.database, p.stats.Node, metric.value(p.stats))
+		}
+	}
+
+	routes := h.queryStats.Snapshot()
+	for _, metric := range routeMetrics {
+		fmt.Fprintf(&body, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, r := range routes {
+			fmt.Fprintf(&body, "%s{method=%q,route=%q} %g\n", metric.name, r.Method, r.Route, metric.value(r))
+		}
+	}
+
+	if h.timeouts != nil {
+		fmt.Fprintf(&body, "# HELP http_route_timeouts_total Total number of requests to the route whose deadline passed before the handler returned\n# TYPE http_route_timeouts_total counter\n")
+		for _, r := range h.timeouts.Snapshot() {
+			fmt.Fprintf(&body, "http_route_timeouts_total{method=%q,route=%q,timeout=%q} %d\n", r.Method, r.Route, r.Timeout, r.Exceeded)
+		}
+	}
+
+	if h.concurrency != nil {
+		load := h.concurrency.Snapshot()
+		for _, metric := range concurrencyMetrics {
+			fmt.Fprintf(&body, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+			for _, g := range load {
+				fmt.Fprintf(&body, "%s{group=%q} %g\n", metric.name, concurrencyGroupLabel(g.Group), metric.value(g))
+			}
+		}
+	}
+
+	clients := httpclient.Snapshot()
+	for _, metric := range clientMetrics {
+		fmt.Fprintf(&body, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, s := range clients {
+			fmt.Fprintf(&body, "%s{client=%q} %g\n", metric.name, s.Name, metric.value(s))
+		}
+	}
+
+	if h.throttle != nil {
+		stats := h.throttle.Stats()
+		for _, metric := range throttleMetrics {
+			fmt.Fprintf(&body, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", metric.name, metric.help, metric.name, metric.kind, metric.name, metric.value(stats))
+		}
+	}
+
+	if h.bots != nil {
+		stats := h.bots.Stats()
+		signals := make([]string, 0, len(stats.Rejected))
+		for signal := range stats.Rejected {
+			signals = append(signals, signal)
+		}
+		sort.Strings(signals)
+		fmt.Fprintf(&body, "# HELP auth_bot_rejected_total Total number of requests to auth routes rejected as automated, by the first signal\n# TYPE auth_bot_rejected_total counter\n")
+		for _, signal := range signals {
+			fmt.Fprintf(&body, "auth_bot_rejected_total{signal=%q} %d\n", signal, stats.Rejected[signal])
+		}
+		fmt.Fprintf(&body, "# HELP auth_bot_challenges_total Total number of bot challenge tokens issued\n# TYPE auth_bot_challenges_total counter\nauth_bot_challenges_total %d\n", stats.Challenges)
+	}
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(body.String()))
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/migrate"
+	"go-backend-template/utils"
+)
+
+// MigrationHandler reports the PostgreSQL schema version to admins
+type MigrationHandler struct {
+	migrator      *migrate.Migrator
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewMigrationHandler creates a new migration handler
+func NewMigrationHandler(migrator *migrate.Migrator, logger utils.Logger, localizer *utils.Localizer) *MigrationHandler {
+	return &MigrationHandler{
+		migrator:      migrator,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// Status godoc
+// @Summary Get migration status
+// @ID getMigrationStatus
+// @Description Get the applied PostgreSQL schema version and the migrations built into the running binary (admin only)
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.APIResponse{data=models.MigrationStatus}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/migrations [get]
+func (h *MigrationHandler) Status(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	status, err := h.migrator.Status(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to read migration status", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to read migration status",
+		))
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "migrations_retrieved"),
+		status,
+	))
+}
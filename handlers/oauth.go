@@ -0,0 +1,599 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/middleware"
+	"go-backend-template/models"
+	"go-backend-template/oauth"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// OAuthHandler serves the OpenID Connect provider: the protocol endpoints called by clients, the consent
+// API of the frontend's consent screen, and client registration
+type OAuthHandler struct {
+	provider      *oauth.Provider
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewOAuthHandler creates a new OAuth handler
+func NewOAuthHandler(provider *oauth.Provider, logger utils.Logger, localizer *utils.Localizer) *OAuthHandler {
+	return &OAuthHandler{
+		provider:      provider,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// RequireScope authenticates requests with an access token issued to a client with scope
+func (h *OAuthHandler) RequireScope(scope string) gin.HandlerFunc {
+	return middleware.OAuthToken(h.provider.Verify, scope)
+}
+
+// Discovery serves the OpenID Provider metadata
+func (h *OAuthHandler) Discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, h.provider.Discovery())
+}
+
+// JWKS serves the public key that verifies ID tokens
+func (h *OAuthHandler) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, h.provider.JWKS())
+}
+
+// Authorize validates an authorization request and sends the browser to the consent screen with the same
+// parameters. Errors are sent back to the client's redirect URI, unless the client or the redirect URI
+// itself is invalid.
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	var req models.AuthorizationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.OAuthError{Error: "invalid_request", ErrorDescription: "client_id and redirect_uri are required"})
+		return
+	}
+
+	authorization, err := h.provider.Authorize(c.Request.Context(), req)
+	var oauthErr *oauth.Error
+	switch {
+	case err == nil:
+		c.Redirect(http.StatusFound, h.provider.ConsentURL(c.Request.URL.Query()))
+	case !errors.As(err, &oauthErr):
+		h.respondServerError(c, "Failed to validate authorization request", err)
+	case authorization == nil:
+		c.JSON(http.StatusBadRequest, oauthErr.Response())
+	default:
+		c.Redirect(http.StatusFound, authorization.ErrorRedirect(oauthErr))
+	}
+}
+
+// Token exchanges an authorization code, client credentials, or an approved device code for tokens.
+// Clients authenticate with HTTP Basic or with client_id and client_secret in the form.
+func (h *OAuthHandler) Token(c *gin.Context) {
+	var req oauth.TokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.OAuthError{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+	basic := clientCredentials(c, &req.ClientID, &req.ClientSecret)
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	tokens, err := h.provider.Token(c.Request.Context(), req)
+	if err != nil {
+		h.respondProtocolError(c, basic, "Failed to issue tokens", err)
+		return
+	}
+
+	h.logger.Info("OAuth tokens issued", "client_id", req.ClientID, "scope", tokens.Scope)
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Introspect describes a token to a resource server; only confidential clients may call it
+func (h *OAuthHandler) Introspect(c *gin.Context) {
+	var req oauth.TokenActionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.OAuthError{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+	basic := clientCredentials(c, &req.ClientID, &req.ClientSecret)
+
+	c.Header("Cache-Control", "no-store")
+	info, err := h.provider.Introspect(c.Request.Context(), req)
+	if err != nil {
+		h.respondProtocolError(c, basic, "Failed to introspect token", err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// Revoke revokes an access token of the calling client; it answers 200 for tokens that are already invalid
+func (h *OAuthHandler) Revoke(c *gin.Context) {
+	var req oauth.TokenActionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.OAuthError{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+	basic := clientCredentials(c, &req.ClientID, &req.ClientSecret)
+
+	if err := h.provider.Revoke(c.Request.Context(), req); err != nil {
+		h.respondProtocolError(c, basic, "Failed to revoke token", err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// DeviceEnabled reports whether the device authorization grant is enabled
+func (h *OAuthHandler) DeviceEnabled() bool {
+	return h.provider.DeviceEnabled()
+}
+
+// DeviceAuthorization starts the device flow for a CLI tool or another device without a browser; the
+// device shows the user code and polls the token endpoint with the device code
+func (h *OAuthHandler) DeviceAuthorization(c *gin.Context) {
+	var req oauth.DeviceAuthorizationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.OAuthError{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+	basic := clientCredentials(c, &req.ClientID, &req.ClientSecret)
+
+	c.Header("Cache-Control", "no-store")
+	authorization, err := h.provider.DeviceAuthorize(c.Request.Context(), req)
+	if err != nil {
+		h.respondProtocolError(c, basic, "Failed to start device authorization", err)
+		return
+	}
+	c.JSON(http.StatusOK, authorization)
+}
+
+// clientCredentials takes the client credentials from HTTP Basic authentication when present, and
+// reports whether they were
+func clientCredentials(c *gin.Context, clientID, secret *string) bool {
+	id, password, ok := c.Request.BasicAuth()
+	if ok {
+		// RFC 6749 section 2.3.1 form-encodes the credentials before Basic encoding them
+		*clientID, _ = url.QueryUnescape(id)
+		*secret, _ = url.QueryUnescape(password)
+	}
+	return ok
+}
+
+// respondProtocolError answers a client-authenticated protocol endpoint with the OAuth error, challenging
+// for Basic credentials when those were rejected
+func (h *OAuthHandler) respondProtocolError(c *gin.Context, basic bool, message string, err error) {
+	var oauthErr *oauth.Error
+	if !errors.As(err, &oauthErr) {
+		h.respondServerError(c, message, err)
+		return
+	}
+	if oauthErr.Status() == http.StatusUnauthorized && basic {
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	c.JSON(oauthErr.Status(), oauthErr.Response())
+}
+
+// UserInfo returns the claims about the user of the access token
+func (h *OAuthHandler) UserInfo(c *gin.Context) {
+	claims, err := h.provider.UserInfo(c.Request.Context(), middleware.OAuthClaims(c))
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.JSON(http.StatusUnauthorized, models.OAuthError{Error: "invalid_token", ErrorDescription: "the user no longer exists"})
+		return
+	}
+	if err != nil {
+		h.respondServerError(c, "Failed to load user info", err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, claims)
+}
+
+// respondServerError answers a protocol endpoint with server_error
+func (h *OAuthHandler) respondServerError(c *gin.Context, message string, err error) {
+	h.logger.Error(message, "error", err)
+	c.JSON(http.StatusInternalServerError, models.OAuthError{Error: "server_error", ErrorDescription: message})
+}
+
+// GetConsent godoc
+// @Summary Describe an authorization request
+// @ID getOAuthConsent
+// @Description Validate the authorization request the consent screen was opened with, and describe the client and scopes to show. consent_required is false when the user already allowed the scopes or the client is trusted.
+// @Tags oauth
+// @Produce json
+// @Security Bearer
+// @Param response_type query string true "Must be code"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Registered redirect URI"
+// @Param scope query string true "Space-separated scopes"
+// @Param state query string false "Opaque value returned to the client"
+// @Param nonce query string false "Value copied into the ID token"
+// @Param code_challenge query string false "PKCE S256 challenge"
+// @Param code_challenge_method query string false "Must be S256"
+// @Success 200 {object} models.APIResponse{data=models.ConsentInfo}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /oauth/consent [get]
+func (h *OAuthHandler) GetConsent(c *gin.Context) {
+	var req models.AuthorizationRequest
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	authorization, err := h.provider.Authorize(c.Request.Context(), req)
+	if err != nil {
+		h.respondAuthorizationError(c, lang, err)
+		return
+	}
+
+	consent, err := h.provider.Consent(c.Request.Context(), userID, authorization)
+	if err != nil {
+		h.logger.Error("Failed to load consent", "user_id", userID, "client_id", req.ClientID, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to load consent",
+		))
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_retrieved"),
+		consent,
+	))
+}
+
+// DecideConsent godoc
+// @Summary Approve or deny an authorization request
+// @ID decideOAuthConsent
+// @Description Answer the authorization request the consent screen was opened with. Approving remembers the consent and returns the client's redirect URI with an authorization code; denying returns it with error=access_denied. The consent screen then sends the browser to redirect_to.
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.ConsentDecision true "Authorization request and decision"
+// @Success 200 {object} models.APIResponse{data=models.ConsentRedirect}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /oauth/consent [post]
+func (h *OAuthHandler) DecideConsent(c *gin.Context) {
+	var req models.ConsentDecision
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	authorization, err := h.provider.Authorize(c.Request.Context(), req.AuthorizationRequest)
+	if err != nil {
+		h.respondAuthorizationError(c, lang, err)
+		return
+	}
+
+	if !req.Approve {
+		h.logger.Info("OAuth consent denied", "user_id", userID, "client_id", req.ClientID)
+		h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+			h.localizer.Get(lang, "consent_denied"),
+			models.ConsentRedirect{RedirectTo: h.provider.Deny(authorization)},
+		))
+		return
+	}
+
+	redirect, err := h.provider.Approve(c.Request.Context(), userID, authorization)
+	if err != nil {
+		h.logger.Error("Failed to approve authorization", "user_id", userID, "client_id", req.ClientID, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to approve authorization",
+		))
+		return
+	}
+	h.logger.Info("OAuth consent approved", "user_id", userID, "client_id", req.ClientID, "scope", req.Scope)
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "consent_approved"),
+		models.ConsentRedirect{RedirectTo: redirect},
+	))
+}
+
+// respondAuthorizationError answers the consent API for an authorization request that failed validation
+func (h *OAuthHandler) respondAuthorizationError(c *gin.Context, lang string, err error) {
+	var oauthErr *oauth.Error
+	if errors.As(err, &oauthErr) {
+		h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "invalid_authorization_request",
+			oauthErr.Description,
+		))
+		return
+	}
+	h.logger.Error("Failed to validate authorization request", "error", err)
+	h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "internal_error",
+		"Failed to validate authorization request",
+	))
+}
+
+// GetDevice godoc
+// @Summary Describe a device authorization
+// @ID getOAuthDevice
+// @Description Look up the pending device authorization with the user code shown on a device, and describe the client and scopes for the verification screen. Consent is always required, so the user confirms they started the request.
+// @Tags oauth
+// @Produce json
+// @Security Bearer
+// @Param user_code query string true "User code shown on the device, such as WDJB-MJHT"
+// @Success 200 {object} models.APIResponse{data=models.ConsentInfo}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /oauth/device [get]
+func (h *OAuthHandler) GetDevice(c *gin.Context) {
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	consent, err := h.provider.DeviceConsent(c.Request.Context(), c.Query("user_code"))
+	if err != nil {
+		h.respondDeviceError(c, lang, userID, err)
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_retrieved"),
+		consent,
+	))
+}
+
+// DecideDevice godoc
+// @Summary Approve or deny a device authorization
+// @ID decideOAuthDevice
+// @Description Answer the pending device authorization with the user code. Approving remembers the consent; the device receives its tokens on its next poll of the token endpoint.
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.DeviceDecision true "User code and decision"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /oauth/device [post]
+func (h *OAuthHandler) DecideDevice(c *gin.Context) {
+	var req models.DeviceDecision
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	if err := h.provider.DeviceDecide(c.Request.Context(), userID, req.UserCode, req.Approve); err != nil {
+		h.respondDeviceError(c, lang, userID, err)
+		return
+	}
+
+	message := "consent_denied"
+	if req.Approve {
+		message = "device_approved"
+	}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, message),
+		nil,
+	))
+}
+
+// respondDeviceError answers the device API for a user code that failed to resolve
+func (h *OAuthHandler) respondDeviceError(c *gin.Context, lang, userID string, err error) {
+	if errors.Is(err, oauth.ErrDeviceCodeNotFound) {
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "device_code_not_found",
+			"The user code is invalid, expired, or already used",
+		))
+		return
+	}
+	h.logger.Error("Failed to resolve device authorization", "user_id", userID, "error", err)
+	h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "internal_error",
+		"Failed to resolve device authorization",
+	))
+}
+
+// ListConsents godoc
+// @Summary List authorized applications
+// @ID listOAuthConsents
+// @Description List the OAuth clients the current user allowed access to, most recent first
+// @Tags oauth
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.APIResponse{data=[]models.OAuthConsent}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /oauth/consents [get]
+func (h *OAuthHandler) ListConsents(c *gin.Context) {
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	consents, err := h.provider.ListConsents(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list consents", "user_id", userID, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to list consents",
+		))
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resources_retrieved"),
+		consents,
+	))
+}
+
+// RevokeConsent godoc
+// @Summary Revoke an application's access
+// @ID revokeOAuthConsent
+// @Description Withdraw the current user's consent to an OAuth client, so its next sign-in asks again. Access tokens already issued stay valid until they expire.
+// @Tags oauth
+// @Produce json
+// @Security Bearer
+// @Param client_id path string true "Client ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /oauth/consents/{client_id} [delete]
+func (h *OAuthHandler) RevokeConsent(c *gin.Context) {
+	userID := utils.Scope(c).UserID
+	clientID := c.Param("client_id")
+	lang := utils.Scope(c).Language
+
+	err := h.provider.RevokeConsent(c.Request.Context(), userID, clientID)
+	if errors.Is(err, oauth.ErrConsentNotFound) {
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "oauth_consent_not_found",
+			"No consent to client "+clientID,
+		))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to revoke consent", "user_id", userID, "client_id", clientID, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to revoke consent",
+		))
+		return
+	}
+	h.logger.Info("OAuth consent revoked", "user_id", userID, "client_id", clientID)
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_deleted"),
+		nil,
+	))
+}
+
+// CreateClient godoc
+// @Summary Register an OAuth client
+// @ID createOAuthClient
+// @Description Register an application that signs users in with OpenID Connect (admin only). The client_secret is only returned here; public clients get none and must use PKCE.
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.CreateOAuthClientRequest true "Client to register"
+// @Success 201 {object} models.APIResponse{data=models.OAuthClientInfo}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/oauth/clients [post]
+func (h *OAuthHandler) CreateClient(c *gin.Context) {
+	var req models.CreateOAuthClientRequest
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	client, err := h.provider.CreateClient(c.Request.Context(), req)
+	var paramErr *utils.ParamError
+	if errors.As(err, &paramErr) {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to register OAuth client", "name", req.Name, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to register client",
+		))
+		return
+	}
+	h.logger.Info("OAuth client registered", "client_id", client.ClientID, "name", client.Name, "by", utils.Scope(c).UserID)
+
+	h.responseUtils.Respond(c, http.StatusCreated, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_created"),
+		client,
+	))
+}
+
+// ListClients godoc
+// @Summary List OAuth clients
+// @ID listOAuthClients
+// @Description List the registered OAuth clients, oldest first (admin only)
+// @Tags oauth
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.APIResponse{data=[]models.OAuthClientInfo}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/oauth/clients [get]
+func (h *OAuthHandler) ListClients(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	clients, err := h.provider.ListClients(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list OAuth clients", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to list clients",
+		))
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resources_retrieved"),
+		clients,
+	))
+}
+
+// DeleteClient godoc
+// @Summary Delete an OAuth client
+// @ID deleteOAuthClient
+// @Description Delete an OAuth client with its pending codes and consents (admin only). Access tokens already issued stay valid until they expire.
+// @Tags oauth
+// @Produce json
+// @Security Bearer
+// @Param client_id path string true "Client ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/oauth/clients/{client_id} [delete]
+func (h *OAuthHandler) DeleteClient(c *gin.Context) {
+	clientID := c.Param("client_id")
+	lang := utils.Scope(c).Language
+
+	err := h.provider.DeleteClient(c.Request.Context(), clientID)
+	if errors.Is(err, oauth.ErrClientNotFound) {
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "oauth_client_not_found",
+			"Unknown client "+clientID,
+		))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete OAuth client", "client_id", clientID, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to delete client",
+		))
+		return
+	}
+	h.logger.Info("OAuth client deleted", "client_id", clientID, "by", utils.Scope(c).UserID)
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_deleted"),
+		nil,
+	))
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/operations"
+	"go-backend-template/utils"
+)
+
+// OperationHandler serves the status of the operations run in the background
+type OperationHandler struct {
+	queue         *operations.Queue
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewOperationHandler creates a new operation handler
+func NewOperationHandler(queue *operations.Queue, logger utils.Logger, localizer *utils.Localizer) *OperationHandler {
+	return &OperationHandler{
+		queue:         queue,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// Get godoc
+// @Summary Get an operation
+// @ID getOperation
+// @Description Poll an operation started with Prefer: respond-async: its status (queued, running, succeeded, or failed), the items done out of the total, and once it succeeded, the data the synchronous request would have returned. Operations are visible to the user who started them and to admins, and are removed RETENTION_OPERATIONS after they finish.
+// @Tags operations
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Operation ID"
+// @Success 200 {object} models.APIResponse{data=models.Operation}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /operations/{id} [get]
+func (h *OperationHandler) Get(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	op, err := h.queue.Get(c.Request.Context(), c.Param("id"))
+	if err == nil && op.OwnerID != utils.Scope(c).UserID && !isAdmin(utils.Scope(c).Role) {
+		err = operations.ErrNotFound
+	}
+	if errors.Is(err, operations.ErrNotFound) {
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "operation_not_found",
+			"Operation not found",
+		))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get the operation", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to get the operation",
+		))
+		return
+	}
+
+	if !op.Finished() {
+		c.Header("Retry-After", "1")
+	}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(h.localizer.Get(lang, "resource_retrieved"), op))
+}
+
+// isAdmin reports whether the role may see the operations of every user
+func isAdmin(role string) bool {
+	return role == "admin" || role == "superadmin"
+}
+
+// prefersAsync reports whether the request asks to be answered before it is done, with
+// Prefer: respond-async (RFC 7240)
+func prefersAsync(c *gin.Context) bool {
+	for _, header := range c.Request.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			token, _, _ := strings.Cut(preference, ";")
+			if strings.EqualFold(strings.TrimSpace(token), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptOperation queues an operation of kind with params for the current user and writes 202 with the
+// operation, its URL in Location, or 503 when it cannot be queued. route is the path of the handler
+// without the API version, which locates the operations route of the same version.
+func acceptOperation(c *gin.Context, queue *operations.Queue, logger utils.Logger, localizer *utils.Localizer, responseUtils *utils.ResponseUtils, kind, route string, params interface{}) {
+	lang := utils.Scope(c).Language
+
+	op, err := queue.Enqueue(c.Request.Context(), kind, utils.Scope(c).UserID, params)
+	if err != nil {
+		logger.Error("Failed to queue the operation", "kind", kind, "error", err)
+		responseUtils.Respond(c, http.StatusServiceUnavailable, responseUtils.LocalizedErrorResponse(
+			localizer, lang, "service_unavailable",
+			"Failed to queue the operation",
+		))
+		return
+	}
+
+	logger.Info("Operation queued", "operation_id", op.ID, "kind", kind, "by", op.OwnerID)
+	c.Header("Location", strings.TrimSuffix(c.FullPath(), route)+"/operations/"+op.ID)
+	c.Header("Preference-Applied", "respond-async")
+	responseUtils.Respond(c, http.StatusAccepted, responseUtils.SuccessResponse(localizer.Get(lang, "operation_accepted"), op))
+}
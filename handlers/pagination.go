@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/models"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// cursorLink returns the current request URL with cursor replaced
+func cursorLink(c *gin.Context, cursor string) string {
+	link := *c.Request.URL
+	query := link.Query()
+	query.Set("cursor", cursor)
+	query.Del("page")
+	link.RawQuery = query.Encode()
+	return link.RequestURI()
+}
+
+// getUsersByCursor lists users with keyset pagination from rawCursor, which is empty for the first page
+func (h *UserHandler) getUsersByCursor(c *gin.Context, lang string, query services.ListUsersQuery, rawCursor string) {
+	if rawCursor != "" {
+		cursor, err := utils.DecodeCursor(rawCursor, query.Sort)
+		if err != nil {
+			h.respondInvalidCursor(c, lang, err)
+			return
+		}
+		query.Cursor = &cursor
+	}
+
+	userInfos, total, err := h.users.ListUsersByCursor(c.Request.Context(), query)
+	if err != nil {
+		h.respondServiceError(c, lang, err, "Failed to retrieve users")
+		return
+	}
+
+	h.respondCursorPage(c, userInfos, total, query.PageSize, query.Sort, query.Cursor, query.Fields)
+}
+
+// respondCursorPage drops the lookahead row, restores display order, and writes the page with next/prev cursors
+func (h *UserHandler) respondCursorPage(c *gin.Context, rows []models.UserInfo, total int64, pageSize int, sort []utils.SortField, cursor *utils.Cursor, fields utils.FieldSet) {
+	hasMore := len(rows) > pageSize
+	if hasMore {
+		rows = rows[:pageSize]
+	}
+
+	hasNext, hasPrev := hasMore, cursor != nil
+	if cursor != nil && cursor.Before {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+		hasNext, hasPrev = true, hasMore
+	}
+
+	pagination := models.Pagination{
+		PageSize:  pageSize,
+		Total:     total,
+		TotalPage: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}
+	if len(rows) > 0 {
+		if hasNext {
+			if next, err := utils.NewCursor(rows[len(rows)-1], sort, false); err == nil {
+				pagination.NextCursor = next.Encode()
+				pagination.Next = cursorLink(c, pagination.NextCursor)
+			}
+		}
+		if hasPrev {
+			if prev, err := utils.NewCursor(rows[0], sort, true); err == nil {
+				pagination.PrevCursor = prev.Encode()
+				pagination.Prev = cursorLink(c, pagination.PrevCursor)
+			}
+		}
+	}
+
+	response := h.responseUtils.PaginatedResponse(fields.Project(rows), pagination)
+	if notModified(c, response) {
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse("Users retrieved successfully", response))
+}
+
+// respondInvalidCursor writes 400 for a cursor that cannot be used with this request
+func (h *UserHandler) respondInvalidCursor(c *gin.Context, lang string, err error) {
+	h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "bad_request",
+		err.Error(),
+	))
+}
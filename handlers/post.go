@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/models"
+	"go-backend-template/posts"
+	"go-backend-template/utils"
+)
+
+// PostHandler handles post requests. Any signed-in user can read posts; only the owner of a post or an
+// admin can change or delete it.
+type PostHandler struct {
+	store         posts.Store
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewPostHandler creates a new post handler
+func NewPostHandler(store posts.Store, logger utils.Logger, localizer *utils.Localizer) *PostHandler {
+	return &PostHandler{
+		store:         store,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// respondStoreError writes 404 for a missing post and 500 for anything else
+func (h *PostHandler) respondStoreError(c *gin.Context, lang string, err error, detail string) {
+	if errors.Is(err, posts.ErrNotFound) {
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "not_found",
+			"Post not found",
+		))
+		return
+	}
+
+	h.logger.Error(detail, "id", c.Param("id"), "error", err)
+	h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "internal_error",
+		detail,
+	))
+}
+
+// canModify reports whether the current user may change the post: its owner or an admin
+func canModify(c *gin.Context, post *models.PostInfo) bool {
+	role := utils.Scope(c).Role
+	return post.OwnerID == utils.Scope(c).UserID || role == "admin" || role == "superadmin"
+}
+
+// respondForbidden writes 403 for a change to another user's post
+func (h *PostHandler) respondForbidden(c *gin.Context, lang string) {
+	h.responseUtils.Respond(c, http.StatusForbidden, h.responseUtils.LocalizedErrorResponse(
+		h.localizer, lang, "forbidden",
+		"Only the owner of a post can change it",
+	))
+}
+
+// List godoc
+// @Summary List posts
+// @ID listPosts
+// @Description Get a page of posts, newest first, optionally only those of one user
+// @Tags posts
+// @Produce json
+// @Security Bearer
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param search query string false "Only posts whose title or body contains this term"
+// @Param owner_id query string false "Only posts of this user; me for the current user"
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse{data=[]models.PostInfo}}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /posts [get]
+func (h *PostHandler) List(c *gin.Context) {
+	var query models.ListPostsQuery
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	if query.OwnerID == "me" {
+		query.OwnerID = utils.Scope(c).UserID
+	}
+
+	items, total, err := h.store.List(c.Request.Context(), posts.ListOptions{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+		Search:   query.Search,
+		OwnerID:  query.OwnerID,
+	})
+	if err != nil {
+		h.respondStoreError(c, lang, err, "Failed to list posts")
+		return
+	}
+
+	pagination := models.Pagination{
+		Page:      query.Page,
+		PageSize:  query.PageSize,
+		Total:     total,
+		TotalPage: int((total + int64(query.PageSize) - 1) / int64(query.PageSize)),
+	}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resources_retrieved"),
+		h.responseUtils.PaginatedResponse(items, pagination),
+	))
+}
+
+// Create godoc
+// @Summary Create a post
+// @ID createPost
+// @Description Create a new post owned by the current user
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.CreatePostRequest true "Post data"
+// @Success 201 {object} models.APIResponse{data=models.PostInfo}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /posts [post]
+func (h *PostHandler) Create(c *gin.Context) {
+	var req models.CreatePostRequest
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	item, err := h.store.Create(c.Request.Context(), utils.Scope(c).UserID, req)
+	if err != nil {
+		h.respondStoreError(c, lang, err, "Failed to create post")
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusCreated, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_created"),
+		item,
+	))
+}
+
+// Get godoc
+// @Summary Get a post
+// @ID getPost
+// @Description Get a post by ID
+// @Tags posts
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Post ID"
+// @Success 200 {object} models.APIResponse{data=models.PostInfo}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /posts/{id} [get]
+func (h *PostHandler) Get(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	item, err := h.store.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondStoreError(c, lang, err, "Failed to get post")
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_retrieved"),
+		item,
+	))
+}
+
+// Update godoc
+// @Summary Update a post
+// @ID updatePost
+// @Description Update the given fields of a post; omitted fields are left unchanged. Only the owner or an admin can update a post.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Post ID"
+// @Param request body models.UpdatePostRequest true "Fields to update"
+// @Success 200 {object} models.APIResponse{data=models.PostInfo}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /posts/{id} [put]
+func (h *PostHandler) Update(c *gin.Context) {
+	var req models.UpdatePostRequest
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	current, err := h.store.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondStoreError(c, lang, err, "Failed to get post")
+		return
+	}
+	if !canModify(c, current) {
+		h.respondForbidden(c, lang)
+		return
+	}
+
+	item, err := h.store.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		h.respondStoreError(c, lang, err, "Failed to update post")
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_updated"),
+		item,
+	))
+}
+
+// Delete godoc
+// @Summary Delete a post
+// @ID deletePost
+// @Description Delete a post by ID. Only the owner or an admin can delete a post.
+// @Tags posts
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Post ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /posts/{id} [delete]
+func (h *PostHandler) Delete(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	current, err := h.store.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondStoreError(c, lang, err, "Failed to get post")
+		return
+	}
+	if !canModify(c, current) {
+		h.respondForbidden(c, lang)
+		return
+	}
+
+	if err := h.store.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.respondStoreError(c, lang, err, "Failed to delete post")
+		return
+	}
+	h.logger.Info("Post deleted", "post_id", current.ID, "owner_id", current.OwnerID, "by", utils.Scope(c).UserID)
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_deleted"),
+		nil,
+	))
+}
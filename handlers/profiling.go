@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfilingHandler serves the net/http/pprof endpoints under /debug/pprof/
+type ProfilingHandler struct {
+	token string
+}
+
+// NewProfilingHandler creates a new profiling handler; a non-empty token must be sent as a bearer token
+func NewProfilingHandler(token string) *ProfilingHandler {
+	return &ProfilingHandler{token: token}
+}
+
+// Profile serves the pprof index and profiles; the route's *profile parameter selects one. CPU profiles
+// and traces are labelled with the route and method of the requests they sample (see
+// middleware.ProfilerLabels), so go tool pprof -tagfocus=route=/api/v1/users narrows one to a handler.
+func (h *ProfilingHandler) Profile(c *gin.Context) {
+	if h.token != "" {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	switch name := strings.TrimPrefix(c.Param("profile"), "/"); name {
+	case "":
+		pprof.Index(c.Writer, c.Request)
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		// Named runtime profiles: heap, goroutine, allocs, block, mutex, threadcreate
+		pprof.Handler(name).ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/middleware"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// ReadOnlyHandler shows and toggles the read-only mode of the API
+type ReadOnlyHandler struct {
+	mode          *middleware.ReadOnlyMode
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewReadOnlyHandler creates a new read-only mode handler
+func NewReadOnlyHandler(mode *middleware.ReadOnlyMode, logger utils.Logger, localizer *utils.Localizer) *ReadOnlyHandler {
+	return &ReadOnlyHandler{
+		mode:          mode,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// Get godoc
+// @Summary Get the read-only mode
+// @ID getReadOnlyMode
+// @Description Report whether this instance of the API is in read-only mode, in which requests other than GET, HEAD, and OPTIONS are answered with 503, except those to READ_ONLY_EXEMPT_ROUTES.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.APIResponse{data=models.ReadOnlyStatus}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Router /admin/read-only [get]
+func (h *ReadOnlyHandler) Get(c *gin.Context) {
+	lang := utils.Scope(c).Language
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resources_retrieved"),
+		h.status(),
+	))
+}
+
+// Set godoc
+// @Summary Turn the read-only mode on or off
+// @ID setReadOnlyMode
+// @Description Put the API in read-only mode for a maintenance window or a migration, or bring it back. While it is on, requests other than GET, HEAD, and OPTIONS are answered with 503 and the reason, except those to READ_ONLY_EXEMPT_ROUTES and to this endpoint. The mode is kept in memory, so it only changes the instance that serves the request and is reset to READ_ONLY_MODE on restart. Superadmins only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.SetReadOnlyRequest true "Read-only mode"
+// @Success 200 {object} models.APIResponse{data=models.ReadOnlyStatus}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Router /admin/read-only [put]
+func (h *ReadOnlyHandler) Set(c *gin.Context) {
+	var req models.SetReadOnlyRequest
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	h.mode.Set(*req.Enabled, req.Reason)
+	h.logger.Warn("Changed the read-only mode", "enabled", *req.Enabled, "reason", req.Reason, "user_id", utils.Scope(c).UserID)
+
+	key := "read_only_disabled"
+	if *req.Enabled {
+		key = "read_only_enabled"
+	}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(h.localizer.Get(lang, key), h.status()))
+}
+
+// status returns the current mode
+func (h *ReadOnlyHandler) status() models.ReadOnlyStatus {
+	enabled, reason, since := h.mode.Status()
+	status := models.ReadOnlyStatus{Enabled: enabled, Reason: reason}
+	if enabled {
+		status.Since = &since
+	}
+	return status
+}
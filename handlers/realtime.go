@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"go-backend-template/config"
+	"go-backend-template/models"
+	"go-backend-template/realtime"
+	"go-backend-template/utils"
+)
+
+const (
+	// streamWriteWait bounds a single write to a WebSocket or SSE client
+	streamWriteWait = 10 * time.Second
+	// wsMaxMessageSize limits client frames; clients only send control frames
+	wsMaxMessageSize = 512
+	// sseRetry is the reconnect delay, in milliseconds, suggested to EventSource clients
+	sseRetry = 3000
+)
+
+// RealtimeHandler serves the WebSocket and Server-Sent Events streams and admin broadcasts
+type RealtimeHandler struct {
+	hub           *realtime.Hub
+	upgrader      websocket.Upgrader
+	pingInterval  time.Duration
+	heartbeat     time.Duration
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewRealtimeHandler creates a new realtime handler
+func NewRealtimeHandler(cfg config.RealtimeConfig, hub *realtime.Hub, logger utils.Logger, localizer *utils.Localizer) *RealtimeHandler {
+	return &RealtimeHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin(cfg.AllowedOrigins),
+		},
+		pingInterval:  cfg.PingInterval,
+		heartbeat:     cfg.Heartbeat,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// checkOrigin allows same-host and non-browser clients, plus any origin in allowed ("*" allows all)
+func checkOrigin(allowed []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		for _, o := range allowed {
+			if o == "*" || strings.EqualFold(o, origin) {
+				return true
+			}
+		}
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// WebSocket upgrades an authenticated request and pushes the user's events until the client disconnects,
+// falls behind, or the server shuts down
+func (h *RealtimeHandler) WebSocket(c *gin.Context) {
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	sub, err := h.hub.Subscribe(userID)
+	if err != nil {
+		h.responseUtils.Respond(c, http.StatusServiceUnavailable, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "service_unavailable",
+			err.Error(),
+		))
+		return
+	}
+	defer sub.Close()
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written an HTTP error response
+		h.logger.Warn("WebSocket upgrade failed", "user_id", userID, "error", err)
+		return
+	}
+	defer conn.Close()
+
+	h.logger.Info("WebSocket connected", "user_id", userID)
+
+	// Read pump: consumes control frames so pongs are processed and detects disconnects
+	pongWait := 2 * h.pingInterval
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(wsMaxMessageSize)
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-sub.Events():
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				h.logger.Debug("WebSocket write failed", "user_id", userID, "error", err)
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		case <-closed:
+			h.logger.Info("WebSocket disconnected", "user_id", userID)
+			return
+		case <-sub.Done():
+			code := websocket.CloseGoingAway
+			if errors.Is(sub.Err(), realtime.ErrSlowConsumer) {
+				code = websocket.CloseTryAgainLater
+			}
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, sub.Err().Error()), time.Now().Add(streamWriteWait))
+			return
+		}
+	}
+}
+
+// Events streams the user's events as Server-Sent Events. Clients reconnecting with Last-Event-ID
+// (or ?last_event_id=) first receive the retained events they missed; a client that falls behind is
+// disconnected and catches up the same way on reconnect.
+func (h *RealtimeHandler) Events(c *gin.Context) {
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	sub, err := h.hub.Resume(userID, lastEventID)
+	if err != nil {
+		h.responseUtils.Respond(c, http.StatusServiceUnavailable, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "service_unavailable",
+			err.Error(),
+		))
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// Every write has a deadline so a stalled client cannot pin the handler
+	controller := http.NewResponseController(c.Writer)
+	write := func(format string, args ...interface{}) bool {
+		_ = controller.SetWriteDeadline(time.Now().Add(streamWriteWait))
+		if _, err := fmt.Fprintf(c.Writer, format, args...); err != nil {
+			return false
+		}
+		return controller.Flush() == nil
+	}
+
+	if !write("retry: %d\n\n", sseRetry) {
+		return
+	}
+	h.logger.Info("SSE stream opened", "user_id", userID, "last_event_id", lastEventID)
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-sub.Events():
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.logger.Error("Failed to encode event", "type", event.Type, "error", err)
+				continue
+			}
+			if !write("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data) {
+				h.logger.Debug("SSE write failed", "user_id", userID)
+				return
+			}
+		case <-ticker.C:
+			if !write(": heartbeat\n\n") {
+				return
+			}
+		case <-c.Request.Context().Done():
+			h.logger.Info("SSE stream closed", "user_id", userID)
+			return
+		case <-sub.Done():
+			h.logger.Info("SSE stream ended", "user_id", userID, "reason", sub.Err())
+			return
+		}
+	}
+}
+
+// Broadcast godoc
+// @Summary Broadcast a message to all connected clients (Admin only)
+// @ID broadcast
+// @Description Push an admin.broadcast event to every open WebSocket and SSE connection
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.BroadcastRequest true "Broadcast message"
+// @Success 202 {object} models.APIResponse{data=models.BroadcastResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Router /admin/broadcast [post]
+func (h *RealtimeHandler) Broadcast(c *gin.Context) {
+	var req models.BroadcastRequest
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Broadcast validation failed", "error", err)
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	h.hub.Broadcast(realtime.EventBroadcast, req)
+	connections := h.hub.Connections()
+	h.logger.Info("Admin broadcast sent", "user_id", utils.Scope(c).UserID, "connections", connections)
+
+	h.responseUtils.Respond(c, http.StatusAccepted, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "broadcast_sent"),
+		models.BroadcastResponse{Connections: connections},
+	))
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/models"
+	"go-backend-template/sessions"
+	"go-backend-template/utils"
+)
+
+// clientContext returns the context of the request carrying its client, for the session a login starts
+func clientContext(c *gin.Context) context.Context {
+	return sessions.WithClient(c.Request.Context(), sessions.Client{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: utils.ClientIP(c),
+	})
+}
+
+// SessionsEnabled reports whether logins start sessions with refresh tokens
+func (h *AuthHandler) SessionsEnabled() bool {
+	return h.sessions != nil
+}
+
+// ListSessions godoc
+// @Summary List sessions
+// @ID listSessions
+// @Description List the active sessions of the current user, most recently used first. Sessions started with remember_me have remember_me set and the longer lifetimes; current marks the session of the token the request was made with.
+// @Tags auth
+// @Produce json,xml,application/msgpack
+// @Security Bearer
+// @Success 200 {object} models.APIResponse{data=[]models.SessionInfo}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	scope := utils.Scope(c)
+	lang := scope.Language
+
+	list, err := h.sessions.List(c.Request.Context(), scope.UserID)
+	if err != nil {
+		h.logger.Error("Failed to list sessions", "user_id", scope.UserID, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to list sessions",
+		))
+		return
+	}
+	infos := make([]models.SessionInfo, len(list))
+	for i, session := range list {
+		infos[i] = session.Info(session.ID == scope.SessionID)
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resources_retrieved"),
+		infos,
+	))
+}
+
+// RevokeSession godoc
+// @Summary End a session
+// @ID revokeSession
+// @Description End a session of the current user, such as one on a lost device, so its refresh token stops working. Access tokens already issued for it stay valid until they expire.
+// @Tags auth
+// @Produce json,xml,application/msgpack
+// @Security Bearer
+// @Param id path string true "Session ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID := utils.Scope(c).UserID
+	id := c.Param("id")
+	lang := utils.Scope(c).Language
+
+	err := h.sessions.Revoke(c.Request.Context(), userID, id)
+	if errors.Is(err, sessions.ErrNotFound) {
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "session_not_found",
+			"No session "+id,
+		))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to end session", "user_id", userID, "session_id", id, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to end session",
+		))
+		return
+	}
+	h.logger.Info("Session ended", "user_id", userID, "session_id", id)
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resource_deleted"),
+		nil,
+	))
+}
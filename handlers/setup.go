@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/models"
+	"go-backend-template/security"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// SetupHandler creates the first superadmin of a new deployment
+type SetupHandler struct {
+	setup         *services.SetupService
+	securityLog   *security.EventLogger
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewSetupHandler creates a new setup handler
+func NewSetupHandler(setup *services.SetupService, securityLog *security.EventLogger, logger utils.Logger, localizer *utils.Localizer) *SetupHandler {
+	return &SetupHandler{
+		setup:         setup,
+		securityLog:   securityLog,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// Setup godoc
+// @Summary Create the first superadmin
+// @ID setup
+// @Description Create the first superadmin of an empty database with the one-time setup token logged at startup. Only served when the server started without users and without BOOTSTRAP_ADMIN_EMAIL; once a superadmin exists, it responds 403.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.SetupRequest true "Setup token and superadmin account"
+// @Success 201 {object} models.APIResponse{data=models.UserInfo}
+// @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /setup [post]
+func (h *SetupHandler) Setup(c *gin.Context) {
+	var req models.SetupRequest
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	user, err := h.setup.Complete(c.Request.Context(), req.Token, req.RegisterRequest)
+	if errors.Is(err, services.ErrSetupUnavailable) {
+		h.logger.Warn("Rejected setup attempt", "client_ip", utils.ClientIP(c))
+		h.responseUtils.Respond(c, http.StatusForbidden, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "setup_unavailable",
+			"Setup is not available",
+		))
+		return
+	}
+	if err != nil {
+		if respondDuplicateUser(c, h.localizer, h.responseUtils, lang, err) {
+			return
+		}
+		h.logger.Error("Setup failed", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to create the superadmin",
+		))
+		return
+	}
+
+	h.securityLog.LogRequest(c, security.Event{
+		Type:    security.EventRegistration,
+		Outcome: security.OutcomeSuccess,
+		UserID:  user.ID,
+		Email:   user.Email,
+		Details: map[string]string{"role": "superadmin", "via": "setup"},
+	})
+	h.logger.Info("Created the first superadmin through setup", "user_id", user.ID, "email", user.Email)
+	h.responseUtils.Respond(c, http.StatusCreated, h.responseUtils.SuccessResponse(h.localizer.Get(lang, "setup_completed"), user))
+}
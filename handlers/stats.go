@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/models"
+	"go-backend-template/security"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// StatsHandler serves the figures of the admin dashboard
+type StatsHandler struct {
+	stats         services.StatsService
+	securityLog   *security.EventLogger
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewStatsHandler creates a new stats handler; logins are counted from the events of securityLog
+func NewStatsHandler(stats services.StatsService, securityLog *security.EventLogger, logger utils.Logger, localizer *utils.Localizer) *StatsHandler {
+	return &StatsHandler{
+		stats:         stats,
+		securityLog:   securityLog,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// GetStats godoc
+// @Summary Get statistics
+// @ID getAdminStats
+// @Description Get user totals, daily signups, active users, login failures, and database sizes (admin only). The figures are cached for ADMIN_STATS_CACHE_TTL; logins are those seen by the answering instance.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param days query int false "Days of signups to return" default(30) minimum(1) maximum(365)
+// @Success 200 {object} models.APIResponse{data=models.AdminStats}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/stats [get]
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	var query models.StatsQuery
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	stats, err := h.stats.Stats(c.Request.Context(), query.Days)
+	if err != nil {
+		h.logger.Error("Failed to compute statistics", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to compute statistics",
+		))
+		return
+	}
+
+	logins := h.securityLog.LoginCounts()
+	stats.Logins = models.LoginStats{
+		Window:      fmt.Sprintf("%dh", int(security.LoginWindow.Hours())),
+		Successes:   logins.Successes,
+		Failures:    logins.Failures,
+		FailureRate: logins.FailureRate(),
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "stats_retrieved"),
+		stats,
+	))
+}
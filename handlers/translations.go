@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/models"
+	"go-backend-template/translations"
+	"go-backend-template/utils"
+)
+
+// TranslationHandler lets admins manage message texts at runtime. Edits are stored as overrides on top of
+// the translation files and take effect without a deployment.
+type TranslationHandler struct {
+	manager       *translations.Manager
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewTranslationHandler creates a new translation handler
+func NewTranslationHandler(manager *translations.Manager, logger utils.Logger, localizer *utils.Localizer) *TranslationHandler {
+	return &TranslationHandler{
+		manager:       manager,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// language returns the lower-cased language tag of the path or query, writing a 400 and returning false
+// when it is not a valid tag
+func (h *TranslationHandler) language(c *gin.Context, lang, tag string) (string, bool) {
+	tag = strings.ToLower(tag)
+	if tag != "" && !utils.IsValidLocale(tag) {
+		h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "bad_request",
+			fmt.Sprintf("Invalid language tag %q", tag),
+		))
+		return "", false
+	}
+	return tag, true
+}
+
+// respondError writes 400 for unknown keys, 404 for a missing override, and 500 for anything else
+func (h *TranslationHandler) respondError(c *gin.Context, lang string, err error, detail string) {
+	switch {
+	case errors.Is(err, translations.ErrUnknownKey):
+		h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "unknown_translation_key",
+			err.Error(),
+		))
+	case errors.Is(err, translations.ErrNotFound):
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "not_found",
+			"Translation override not found",
+		))
+	default:
+		h.logger.Error(detail, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			detail,
+		))
+	}
+}
+
+// Languages godoc
+// @Summary List languages
+// @ID listLanguages
+// @Description Get the languages responses can be localized in, with their native names and writing directions, for language pickers
+// @Tags languages
+// @Produce json
+// @Success 200 {object} models.APIResponse{data=[]models.LanguageInfo}
+// @Router /languages [get]
+func (h *TranslationHandler) Languages(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	supported := h.localizer.SupportedLanguages()
+	languages := make([]models.LanguageInfo, 0, len(supported))
+	for _, code := range supported {
+		languages = append(languages, h.localizer.LanguageInfo(code))
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "languages_retrieved"),
+		languages,
+	))
+}
+
+// Missing godoc
+// @Summary List missing translations
+// @ID listMissingTranslations
+// @Description Get the message keys of the default language that each other language does not translate (admin only)
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param language query string false "Only this language"
+// @Success 200 {object} models.APIResponse{data=[]models.MissingTranslations}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Router /admin/translations/missing [get]
+func (h *TranslationHandler) Missing(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	language, ok := h.language(c, lang, c.Query("language"))
+	if !ok {
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "translations_retrieved"),
+		h.manager.Missing(language),
+	))
+}
+
+// Overrides godoc
+// @Summary List translation overrides
+// @ID listTranslationOverrides
+// @Description Get the message texts edited at runtime, which take precedence over the translation files (admin only)
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param language query string false "Only this language"
+// @Success 200 {object} models.APIResponse{data=[]models.Translation}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/translations/overrides [get]
+func (h *TranslationHandler) Overrides(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	language, ok := h.language(c, lang, c.Query("language"))
+	if !ok {
+		return
+	}
+
+	items, err := h.manager.Overrides(c.Request.Context(), language)
+	if err != nil {
+		h.respondError(c, lang, err, "Failed to list translation overrides")
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "translations_retrieved"),
+		items,
+	))
+}
+
+// Set godoc
+// @Summary Override a translation
+// @ID setTranslation
+// @Description Set the text of one message key in a language; a new language code adds the language (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param language path string true "Language code"
+// @Param key path string true "Message key"
+// @Param request body models.SetTranslationRequest true "Message text"
+// @Success 200 {object} models.APIResponse{data=models.Translation}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/translations/{language}/{key} [put]
+func (h *TranslationHandler) Set(c *gin.Context) {
+	var req models.SetTranslationRequest
+	lang := utils.Scope(c).Language
+
+	language, ok := h.language(c, lang, c.Param("language"))
+	if !ok {
+		return
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	translation, err := h.manager.Set(c.Request.Context(), language, c.Param("key"), req.Text)
+	if err != nil {
+		h.respondError(c, lang, err, "Failed to save translation")
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "translation_updated"),
+		translation,
+	))
+}
+
+// Delete godoc
+// @Summary Remove a translation override
+// @ID deleteTranslation
+// @Description Remove the override of one message key, restoring the text of the translation files (admin only)
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param language path string true "Language code"
+// @Param key path string true "Message key"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/translations/{language}/{key} [delete]
+func (h *TranslationHandler) Delete(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	language, ok := h.language(c, lang, c.Param("language"))
+	if !ok {
+		return
+	}
+
+	if err := h.manager.Delete(c.Request.Context(), language, c.Param("key")); err != nil {
+		h.respondError(c, lang, err, "Failed to delete translation")
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "translation_deleted"),
+		nil,
+	))
+}
+
+// Export godoc
+// @Summary Export a locale bundle
+// @ID exportTranslations
+// @Description Download the texts of a language, with the overrides applied, as a translation file for LOCALES_DIR (admin only)
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param language path string true "Language code"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Router /admin/translations/{language}/export [get]
+func (h *TranslationHandler) Export(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	language, ok := h.language(c, lang, c.Param("language"))
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, language))
+	c.IndentedJSON(http.StatusOK, h.manager.Export(language))
+}
+
+// Import godoc
+// @Summary Import a locale bundle
+// @ID importTranslations
+// @Description Store the texts of a translation file, flat or nested, as overrides of a language; texts equal to those of the files are skipped (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param language path string true "Language code"
+// @Param replace query bool false "Remove the existing overrides of the language first"
+// @Param request body object true "Message keys to text"
+// @Success 200 {object} models.APIResponse{data=models.TranslationImport}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/translations/{language}/import [post]
+func (h *TranslationHandler) Import(c *gin.Context) {
+	var raw map[string]interface{}
+	lang := utils.Scope(c).Language
+
+	language, ok := h.language(c, lang, c.Param("language"))
+	if !ok {
+		return
+	}
+	if err := c.ShouldBindJSON(&raw); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+	bundle, err := utils.FlattenTranslations(raw)
+	if err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	result, err := h.manager.Import(c.Request.Context(), language, bundle, c.Query("replace") == "true")
+	if err != nil {
+		h.respondError(c, lang, err, "Failed to import translations")
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "translations_imported"),
+		result,
+	))
+}
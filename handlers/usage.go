@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/usage"
+	"go-backend-template/utils"
+)
+
+// UsageHandler reports metered usage to customers
+type UsageHandler struct {
+	meter         *usage.Meter
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(meter *usage.Meter, logger utils.Logger, localizer *utils.Localizer) *UsageHandler {
+	return &UsageHandler{
+		meter:         meter,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// GetUsage godoc
+// @Summary Get usage
+// @ID getUsage
+// @Description Get the authenticated user's request count, transferred bytes, and quota for the current month
+// @Tags users
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.APIResponse{data=models.UsageInfo}
+// @Failure 401 {object} models.APIResponse
+// @Failure 429 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /users/usage [get]
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	userID := utils.Scope(c).UserID
+	lang := utils.Scope(c).Language
+
+	status, err := h.meter.Usage(c.Request.Context(), usage.Subject(userID), userID)
+	if err != nil {
+		h.logger.Error("Failed to load usage", "user_id", userID, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to load usage",
+		))
+		return
+	}
+
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "usage_retrieved"),
+		status.Info(),
+	))
+}
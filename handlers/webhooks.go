@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/models"
+	"go-backend-template/utils"
+	"go-backend-template/webhooks"
+)
+
+// WebhookHandler receives provider callbacks and lets admins inspect and replay the deliveries
+type WebhookHandler struct {
+	receiver      *webhooks.Receiver
+	logger        utils.Logger
+	localizer     *utils.Localizer
+	responseUtils *utils.ResponseUtils
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(receiver *webhooks.Receiver, logger utils.Logger, localizer *utils.Localizer) *WebhookHandler {
+	return &WebhookHandler{
+		receiver:      receiver,
+		logger:        logger,
+		localizer:     localizer,
+		responseUtils: &utils.ResponseUtils{},
+	}
+}
+
+// Receive accepts a delivery from the provider in the path. The raw body is needed to verify the
+// signature. A 2xx response tells the provider to stop retrying, so it is sent once the delivery is
+// processed, already was, or became a dead letter; a failure the provider should retry gets a 500.
+func (h *WebhookHandler) Receive(c *gin.Context) {
+	lang := utils.Scope(c).Language
+	provider := c.Param("provider")
+
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	event, err := h.receiver.Receive(c.Request.Context(), provider, c.Request.Header, payload)
+	switch {
+	case errors.Is(err, webhooks.ErrUnknownProvider):
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "not_found",
+			err.Error(),
+		))
+		return
+	case errors.Is(err, webhooks.ErrInvalidSignature):
+		h.logger.Warn("Rejected webhook with invalid signature", "provider", provider, "client_ip", c.ClientIP())
+		h.responseUtils.Respond(c, http.StatusBadRequest, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "bad_request",
+			err.Error(),
+		))
+		return
+	case errors.Is(err, webhooks.ErrInProgress):
+		h.responseUtils.Respond(c, http.StatusConflict, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "webhook_in_progress",
+			err.Error(),
+		))
+		return
+	case err != nil:
+		h.logger.Error("Failed to receive webhook", "provider", provider, "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to receive webhook",
+		))
+		return
+	}
+
+	if event.Status == models.WebhookFailed {
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to process webhook",
+		))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// List godoc
+// @Summary List webhook deliveries
+// @ID listWebhookEvents
+// @Description Get a page of the webhook deliveries received from providers, newest first. Filter by status=dead for the dead letters: deliveries that failed WEBHOOK_MAX_ATTEMPTS times and were acknowledged to stop the provider's retries. Served when WEBHOOKS_ENABLED is set.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param provider query string false "Only deliveries from this provider" example(stripe)
+// @Param status query string false "Only deliveries with this status" Enums(processing, processed, failed, dead)
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse{data=[]models.WebhookEvent}}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	var query models.ListWebhookEventsQuery
+	lang := utils.Scope(c).Language
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, h.localizer, h.responseUtils, lang, err)
+		return
+	}
+
+	events, total, err := h.receiver.List(c.Request.Context(), webhooks.ListOptions{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+		Provider: query.Provider,
+		Status:   query.Status,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list webhook deliveries", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to list webhook deliveries",
+		))
+		return
+	}
+
+	pagination := models.Pagination{
+		Page:      query.Page,
+		PageSize:  query.PageSize,
+		Total:     total,
+		TotalPage: int((total + int64(query.PageSize) - 1) / int64(query.PageSize)),
+	}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(
+		h.localizer.Get(lang, "resources_retrieved"),
+		h.responseUtils.PaginatedResponse(events, pagination),
+	))
+}
+
+// Replay godoc
+// @Summary Replay a webhook delivery
+// @ID replayWebhookEvent
+// @Description Process a failed or dead webhook delivery again, as stored when it was received, without checking its signature again. The delivery is processed once more whatever its number of attempts; it is processed when the replay succeeds and stays dead when it fails. Served when WEBHOOKS_ENABLED is set.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} models.APIResponse{data=models.WebhookEvent}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/webhooks/{id}/replay [post]
+func (h *WebhookHandler) Replay(c *gin.Context) {
+	lang := utils.Scope(c).Language
+
+	event, err := h.receiver.Replay(c.Request.Context(), c.Param("id"))
+	switch {
+	case errors.Is(err, webhooks.ErrNotFound):
+		h.responseUtils.Respond(c, http.StatusNotFound, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "webhook_not_found",
+			err.Error(),
+		))
+		return
+	case errors.Is(err, webhooks.ErrProcessed):
+		h.responseUtils.Respond(c, http.StatusConflict, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "webhook_already_processed",
+			err.Error(),
+		))
+		return
+	case errors.Is(err, webhooks.ErrInProgress):
+		h.responseUtils.Respond(c, http.StatusConflict, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "webhook_in_progress",
+			err.Error(),
+		))
+		return
+	case err != nil:
+		h.logger.Error("Failed to replay webhook delivery", "error", err)
+		h.responseUtils.Respond(c, http.StatusInternalServerError, h.responseUtils.LocalizedErrorResponse(
+			h.localizer, lang, "internal_error",
+			"Failed to replay webhook delivery",
+		))
+		return
+	}
+
+	key := "webhook_replayed"
+	if event.Status != models.WebhookProcessed {
+		key = "webhook_replay_failed"
+	}
+	h.responseUtils.Respond(c, http.StatusOK, h.responseUtils.SuccessResponse(h.localizer.Get(lang, key), event))
+}
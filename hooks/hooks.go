@@ -0,0 +1,131 @@
+// Package hooks holds the extension points at which packages outside the template customize
+// registration, token issuance, and profile updates without changing handlers or services. Hooks are
+// added to a Registry before the application is wired (app.WithHooks) and run in the order they were
+// added. Before hooks may change the request or refuse it with Reject; after hooks only observe.
+package hooks
+
+import (
+	"context"
+	"fmt"
+
+	"go-backend-template/jwt"
+	"go-backend-template/models"
+)
+
+// Rejection is the error of a before hook that refuses an operation; handlers answer it with 403
+// Forbidden and Reason as the error detail
+type Rejection struct {
+	Reason string
+}
+
+// Error implements error
+func (r *Rejection) Error() string {
+	return fmt.Sprintf("rejected by hook: %s", r.Reason)
+}
+
+// Reject returns the error with which a before hook refuses an operation for reason
+func Reject(reason string) error {
+	return &Rejection{Reason: reason}
+}
+
+// Registry holds the hooks of each extension point. A nil registry has none, so services accept nil.
+type Registry struct {
+	beforeRegister      []func(ctx context.Context, req *models.RegisterRequest) error
+	afterRegister       []func(ctx context.Context, user models.UserInfo)
+	beforeToken         []func(ctx context.Context, user models.UserInfo, claims *jwt.Claims) error
+	beforeProfileUpdate []func(ctx context.Context, userID string, req *models.UpdateUserRequest) error
+	afterProfileUpdate  []func(ctx context.Context, user models.UserInfo)
+}
+
+// NewRegistry creates a registry without hooks
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// BeforeRegister adds a hook that runs before an account is created from a validated request; an error
+// refuses the registration
+func (r *Registry) BeforeRegister(fn func(ctx context.Context, req *models.RegisterRequest) error) {
+	r.beforeRegister = append(r.beforeRegister, fn)
+}
+
+// AfterRegister adds a hook that runs once an account is created, such as to provision resources for it
+func (r *Registry) AfterRegister(fn func(ctx context.Context, user models.UserInfo)) {
+	r.afterRegister = append(r.afterRegister, fn)
+}
+
+// BeforeToken adds a hook that runs before a token is signed at registration or login; it may add
+// claims, such as organization memberships, and an error refuses the token. At registration, a refused
+// token also undoes the account.
+func (r *Registry) BeforeToken(fn func(ctx context.Context, user models.UserInfo, claims *jwt.Claims) error) {
+	r.beforeToken = append(r.beforeToken, fn)
+}
+
+// BeforeProfileUpdate adds a hook that runs before the profile of a user is changed; an error refuses
+// the update
+func (r *Registry) BeforeProfileUpdate(fn func(ctx context.Context, userID string, req *models.UpdateUserRequest) error) {
+	r.beforeProfileUpdate = append(r.beforeProfileUpdate, fn)
+}
+
+// AfterProfileUpdate adds a hook that runs with the updated profile once it is stored
+func (r *Registry) AfterProfileUpdate(fn func(ctx context.Context, user models.UserInfo)) {
+	r.afterProfileUpdate = append(r.afterProfileUpdate, fn)
+}
+
+// RunBeforeRegister runs the before-register hooks until one fails
+func (r *Registry) RunBeforeRegister(ctx context.Context, req *models.RegisterRequest) error {
+	if r == nil {
+		return nil
+	}
+	for _, fn := range r.beforeRegister {
+		if err := fn(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunAfterRegister runs the after-register hooks
+func (r *Registry) RunAfterRegister(ctx context.Context, user models.UserInfo) {
+	if r == nil {
+		return
+	}
+	for _, fn := range r.afterRegister {
+		fn(ctx, user)
+	}
+}
+
+// RunBeforeToken runs the before-token hooks until one fails
+func (r *Registry) RunBeforeToken(ctx context.Context, user models.UserInfo, claims *jwt.Claims) error {
+	if r == nil {
+		return nil
+	}
+	for _, fn := range r.beforeToken {
+		if err := fn(ctx, user, claims); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunBeforeProfileUpdate runs the before-profile-update hooks until one fails
+func (r *Registry) RunBeforeProfileUpdate(ctx context.Context, userID string, req *models.UpdateUserRequest) error {
+	if r == nil {
+		return nil
+	}
+	for _, fn := range r.beforeProfileUpdate {
+		if err := fn(ctx, userID, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunAfterProfileUpdate runs the after-profile-update hooks
+func (r *Registry) RunAfterProfileUpdate(ctx context.Context, user models.UserInfo) {
+	if r == nil {
+		return
+	}
+	for _, fn := range r.afterProfileUpdate {
+		fn(ctx, user)
+	}
+}
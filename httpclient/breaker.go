@@ -0,0 +1,94 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+
+	"go-backend-template/utils"
+)
+
+// breaker is the circuit breaker of a service. It opens after threshold consecutive failures and refuses
+// requests until cooldown has passed; then it lets one request through, which closes it again when it
+// succeeds and reopens it when it fails.
+type breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	logger    utils.Logger
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	open      bool
+	probing   bool
+}
+
+func newBreaker(name string, threshold int, cooldown time.Duration, logger utils.Logger) *breaker {
+	return &breaker{name: name, threshold: threshold, cooldown: cooldown, logger: logger}
+}
+
+// allow reports whether a request may be sent; every allowed request must be followed by success,
+// failure, or release
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return true
+	}
+	if b.probing || time.Now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// success records a request the service answered
+func (b *breaker) success() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open && b.logger != nil {
+		b.logger.Info("Circuit closed, service is back", "client", b.name)
+	}
+	b.failures, b.open, b.probing = 0, false, false
+}
+
+// failure records a request the service failed, opening the circuit at the threshold or when the probe
+// failed
+func (b *breaker) failure() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.probing || (!b.open && b.failures >= b.threshold) {
+		if !b.open && b.logger != nil {
+			b.logger.Warn("Circuit opened, failing calls fast", "client", b.name, "failures", b.failures, "cooldown", b.cooldown)
+		}
+		b.open, b.probing, b.openUntil = true, false, time.Now().Add(b.cooldown)
+	}
+}
+
+// release returns an allowed request whose outcome says nothing about the service, such as one the
+// caller canceled
+func (b *breaker) release() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// isOpen reports whether requests are being refused
+func (b *breaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
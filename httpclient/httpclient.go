@@ -0,0 +1,78 @@
+// Package httpclient builds the HTTP clients of the calls to other services, such as payment providers,
+// secrets managers, and event collectors, so every integration gets the same behavior: a time limit,
+// retries with backoff and jitter for requests that are safe to repeat, a circuit breaker that fails fast
+// while a service is down, the request ID in the X-Request-ID header, and metrics per client (see
+// Snapshot). Integrations create their client with New instead of using http.Get or a bare http.Client.
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-backend-template/utils"
+)
+
+// ErrCircuitOpen is returned, wrapped in a *url.Error, for requests refused while the circuit of their
+// client is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Options are the settings shared by every client
+type Options struct {
+	// Timeout bounds a call, retries included, for clients created without a timeout of their own
+	Timeout time.Duration
+	// MaxRetries is the number of times a failed request is repeated; 0 disables retries
+	MaxRetries int
+	// RetryDelay is the delay before the first retry; it doubles after every retry, up to MaxRetryDelay
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	// BreakerThreshold is the number of consecutive failures that opens the circuit; 0 disables it
+	BreakerThreshold int
+	// BreakerCooldown is how long the circuit stays open before a request is let through to probe
+	BreakerCooldown time.Duration
+}
+
+// DefaultOptions are used until Configure is called
+var DefaultOptions = Options{
+	Timeout:          10 * time.Second,
+	MaxRetries:       2,
+	RetryDelay:       200 * time.Millisecond,
+	MaxRetryDelay:    2 * time.Second,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+}
+
+var (
+	settingsMu sync.RWMutex
+	options    = DefaultOptions
+	logger     utils.Logger
+)
+
+// Configure sets the options of the clients created afterwards, and the logger the circuit breakers
+// report opening and closing to
+func Configure(opts Options, log utils.Logger) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	options, logger = opts, log
+}
+
+// New creates a client of the service called name, which labels its metrics and log lines. The clients of
+// one service share its circuit breaker. A timeout of 0 uses the configured one.
+func New(name string, timeout time.Duration) *http.Client {
+	settingsMu.RLock()
+	opts := options
+	settingsMu.RUnlock()
+
+	if timeout <= 0 {
+		timeout = opts.Timeout
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &transport{
+			base:    utils.PropagateRequestID(http.DefaultTransport),
+			opts:    opts,
+			service: serviceFor(name, opts),
+		},
+	}
+}
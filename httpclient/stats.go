@@ -0,0 +1,89 @@
+package httpclient
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// service holds the circuit breaker and counters of a service, shared by the clients created for it
+type service struct {
+	name     string
+	breaker  *breaker
+	requests atomic.Int64
+	failures atomic.Int64
+	retries  atomic.Int64
+	rejected atomic.Int64
+	nanos    atomic.Int64
+}
+
+// record counts an attempt; failures are the attempts that did not reach the service or were answered
+// with a server error
+func (s *service) record(elapsed time.Duration, resp *http.Response, err error) {
+	s.requests.Add(1)
+	s.nanos.Add(int64(elapsed))
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		s.failures.Add(1)
+	}
+}
+
+var (
+	servicesMu sync.Mutex
+	services   = make(map[string]*service)
+)
+
+// serviceFor returns the service called name, creating it with the options on first use
+func serviceFor(name string, opts Options) *service {
+	servicesMu.Lock()
+	defer servicesMu.Unlock()
+	if s, ok := services[name]; ok {
+		return s
+	}
+	settingsMu.RLock()
+	log := logger
+	settingsMu.RUnlock()
+	s := &service{name: name, breaker: newBreaker(name, opts.BreakerThreshold, opts.BreakerCooldown, log)}
+	services[name] = s
+	return s
+}
+
+// ClientStats are the counters of the calls to one service
+type ClientStats struct {
+	Name string
+	// Requests counts the requests sent, retries included, and Failures those that did not reach the
+	// service or were answered with a server error
+	Requests int64
+	Failures int64
+	Retries  int64
+	// Rejected counts the requests refused while the circuit was open
+	Rejected int64
+	Duration time.Duration
+	Open     bool
+}
+
+// Snapshot returns the counters of every service called so far, sorted by name
+func Snapshot() []ClientStats {
+	servicesMu.Lock()
+	all := make([]*service, 0, len(services))
+	for _, s := range services {
+		all = append(all, s)
+	}
+	servicesMu.Unlock()
+
+	stats := make([]ClientStats, 0, len(all))
+	for _, s := range all {
+		stats = append(stats, ClientStats{
+			Name:     s.name,
+			Requests: s.requests.Load(),
+			Failures: s.failures.Load(),
+			Retries:  s.retries.Load(),
+			Rejected: s.rejected.Load(),
+			Duration: time.Duration(s.nanos.Load()),
+			Open:     s.breaker.isOpen(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
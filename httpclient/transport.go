@@ -0,0 +1,127 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// transport sends the requests of one client, retrying failures and refusing requests while the circuit
+// of the service is open
+type transport struct {
+	base    http.RoundTripper
+	opts    Options
+	service *service
+}
+
+// RoundTrip implements http.RoundTripper. A request is retried when it failed to reach the service or was
+// answered 429, 502, 503, or 504, and repeating it is safe: its method is idempotent or it carries an
+// Idempotency-Key. Retry-After is honored when it does not exceed the maximum retry delay.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	retryable := canRetry(req)
+	delay := t.opts.RetryDelay
+
+	for attempt := 0; ; attempt++ {
+		if !t.service.breaker.allow() {
+			t.service.rejected.Add(1)
+			return nil, ErrCircuitOpen
+		}
+
+		sent := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				t.service.breaker.release()
+				return nil, err
+			}
+			sent = req.Clone(ctx)
+			sent.Body = body
+		}
+
+		start := time.Now()
+		resp, err := t.base.RoundTrip(sent)
+		t.service.record(time.Since(start), resp, err)
+
+		// A request the caller gave up on says nothing about the service
+		switch {
+		case ctx.Err() != nil:
+			t.service.breaker.release()
+			return resp, err
+		case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+			t.service.breaker.failure()
+		default:
+			t.service.breaker.success()
+		}
+
+		if !retryable || attempt >= t.opts.MaxRetries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+		wait := delay/2 + rand.N(delay/2+1)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				if after > t.opts.MaxRetryDelay {
+					return resp, err
+				}
+				wait = max(wait, after)
+			}
+			// Read the body so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		t.service.retries.Add(1)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(delay*2, t.opts.MaxRetryDelay)
+	}
+}
+
+// canRetry reports whether sending req again is safe and possible: its method is idempotent or it
+// carries an Idempotency-Key, and its body, if any, can be read again
+func canRetry(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// shouldRetry reports whether the outcome of an attempt is worth retrying
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the delay the Retry-After header of resp asks for, in seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,125 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// PostgresStore persists idempotency records in PostgreSQL
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the table is created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Get returns the unexpired record for key
+func (s *PostgresStore) Get(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	err := s.db.WithContext(ctx).Where("key = ? AND expires_at > ?", key, time.Now()).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Reserve inserts the record, relying on the primary key to reject concurrent duplicates
+func (s *PostgresStore) Reserve(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	db := s.db.WithContext(ctx)
+
+	// Expired rows would block the insert; clear this key's stale reservation first
+	if err := db.Where("key = ? AND expires_at <= ?", record.Key, time.Now()).Delete(&models.IdempotencyRecord{}).Error; err != nil {
+		return false, err
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Complete stores the final response
+func (s *PostgresStore) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	return s.db.WithContext(ctx).Save(record).Error
+}
+
+// Release removes a reservation
+func (s *PostgresStore) Release(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ?", key).Delete(&models.IdempotencyRecord{}).Error
+}
+
+// MongoStore persists idempotency records in MongoDB with a TTL index for expiry
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store and ensures the TTL index exists
+func NewMongoStore(ctx context.Context, db *database.MongoDB) (*MongoStore, error) {
+	collection := db.Collection("idempotency_keys")
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection}, nil
+}
+
+// Get returns the unexpired record for key
+func (s *MongoStore) Get(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	err := s.collection.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&record)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Reserve inserts the record, relying on the _id uniqueness to reject concurrent duplicates
+func (s *MongoStore) Reserve(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	// The TTL monitor runs periodically, so remove this key's stale document explicitly
+	if _, err := s.collection.DeleteOne(ctx, bson.M{"_id": record.Key, "expires_at": bson.M{"$lte": time.Now()}}); err != nil {
+		return false, err
+	}
+
+	_, err := s.collection.InsertOne(ctx, record)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Complete stores the final response
+func (s *MongoStore) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	_, err := s.collection.ReplaceOne(ctx, bson.M{"_id": record.Key}, record, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Release removes a reservation
+func (s *MongoStore) Release(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-backend-template/models"
+)
+
+// ErrNotFound is returned when no record exists for a key
+var ErrNotFound = errors.New("idempotency record not found")
+
+// Store persists idempotency records
+type Store interface {
+	// Get returns the unexpired record for key or ErrNotFound
+	Get(ctx context.Context, key string) (*models.IdempotencyRecord, error)
+	// Reserve atomically creates an in-progress record; it returns false if the key already exists
+	Reserve(ctx context.Context, record *models.IdempotencyRecord) (bool, error)
+	// Complete stores the final response for a reserved key
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+	// Release deletes a reservation so the request can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryStore is an in-process Store, suitable for single-instance deployments and development
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyRecord
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]*models.IdempotencyRecord)}
+}
+
+// Get returns the unexpired record for key
+func (s *MemoryStore) Get(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if time.Now().After(record.ExpiresAt) {
+		delete(s.records, key)
+		return nil, ErrNotFound
+	}
+
+	copied := *record
+	return &copied, nil
+}
+
+// Reserve creates an in-progress record unless an unexpired one exists
+func (s *MemoryStore) Reserve(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := s.records[record.Key]; ok && now.Before(existing.ExpiresAt) {
+		return false, nil
+	}
+
+	// Opportunistically drop expired entries so the map does not grow without bound
+	for key, existing := range s.records {
+		if now.After(existing.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+
+	copied := *record
+	s.records[record.Key] = &copied
+	return true, nil
+}
+
+// Complete stores the final response
+func (s *MemoryStore) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *record
+	s.records[record.Key] = &copied
+	return nil
+}
+
+// Release removes a reservation
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
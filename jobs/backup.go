@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+
+	"go-backend-template/backup"
+	"go-backend-template/models"
+	"go-backend-template/operations"
+	"go-backend-template/transfer"
+	"go-backend-template/utils"
+)
+
+// BackupTask exports the users of store to an archive encrypted with key, as GET /admin/backup does
+func BackupTask(store transfer.Store, key string) operations.Task {
+	return func(ctx context.Context, params json.RawMessage, progress func(done, total int)) (interface{}, error) {
+		archive, manifest, err := backup.Export(ctx, store, key)
+		if err != nil {
+			return nil, err
+		}
+		return models.BackupResponse{Archive: archive, Users: manifest.Users, Source: manifest.Source, CreatedAt: manifest.CreatedAt}, nil
+	}
+}
+
+// RestoreTask restores the users of an archive to store, as POST /admin/backup/restore does; the archive
+// is checked with backup.Inspect before it is queued
+func RestoreTask(store transfer.Store, key string, logger utils.Logger) operations.Task {
+	return func(ctx context.Context, params json.RawMessage, progress func(done, total int)) (interface{}, error) {
+		var req models.RestoreBackupRequest
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, err
+		}
+		result, err := backup.Restore(ctx, store, req.Archive, key)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("Restored users from a backup", "users", result.Manifest.Users, "created", result.Created,
+			"updated", result.Updated, "archive_created_at", result.Manifest.CreatedAt)
+		return models.RestoreBackupResponse{
+			Users:     result.Manifest.Users,
+			Created:   result.Created,
+			Updated:   result.Updated,
+			CreatedAt: result.Manifest.CreatedAt,
+		}, nil
+	}
+}
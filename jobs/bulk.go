@@ -0,0 +1,148 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"go-backend-template/models"
+	"go-backend-template/operations"
+	"go-backend-template/security"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// Operation kinds run by the background workers
+const (
+	// OperationBulkUsers is a bulk action on users; its params are a BulkUsersJob
+	OperationBulkUsers = "users.bulk"
+	// OperationBackup exports the users to an encrypted archive
+	OperationBackup = "backup.create"
+	// OperationRestore restores the users from an archive; its params are a models.RestoreBackupRequest
+	OperationRestore = "backup.restore"
+)
+
+// bulkBatchSize is the number of users acted on between checks that the job was not canceled
+const bulkBatchSize = 100
+
+// BulkUsersJob is a bulk action on users, detached from the request that asked for it so it can run in
+// the background
+type BulkUsersJob struct {
+	Action string   `json:"action"`
+	Role   string   `json:"role,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+	IDs    []string `json:"ids"`
+	// Actor holds the actor and request metadata of the security events the job records
+	Actor security.Event `json:"actor"`
+	// ActorRole is the role of the actor; only superadmins may deactivate or delete superadmins
+	ActorRole string `json:"actor_role,omitempty"`
+}
+
+// BulkUsers applies bulk actions to users one at a time, each in its own transaction, so a failure for
+// one user does not undo the others. Run from a request, it logs with the logger of the request.
+type BulkUsers struct {
+	users       services.UserService
+	securityLog *security.EventLogger
+	logger      utils.Logger
+}
+
+// NewBulkUsers creates a runner of bulk actions on users; role changes are recorded in securityLog
+func NewBulkUsers(users services.UserService, securityLog *security.EventLogger, logger utils.Logger) *BulkUsers {
+	return &BulkUsers{users: users, securityLog: securityLog, logger: logger}
+}
+
+// Run applies the action of job to its users in batches of bulkBatchSize, reporting the users done through
+// progress when it is not nil. Once ctx is canceled the remaining users fail with canceled.
+func (b *BulkUsers) Run(ctx context.Context, job BulkUsersJob, progress func(done, total int)) models.BulkUsersResponse {
+	response := models.BulkUsersResponse{Action: job.Action, Matched: len(job.IDs), Results: make([]models.BulkUserResult, 0, len(job.IDs))}
+	for start := 0; start < len(job.IDs); start += bulkBatchSize {
+		if ctx.Err() != nil {
+			for _, id := range job.IDs[start:] {
+				response.Results = append(response.Results, models.BulkUserResult{ID: id, Status: "failed", Error: "canceled"})
+			}
+			break
+		}
+		for _, id := range job.IDs[start:min(start+bulkBatchSize, len(job.IDs))] {
+			response.Results = append(response.Results, b.apply(ctx, job, id))
+		}
+		if progress != nil {
+			progress(len(response.Results), len(job.IDs))
+		}
+	}
+
+	for _, result := range response.Results {
+		if result.Status == "succeeded" {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	utils.LoggerFrom(ctx, b.logger).Info("Bulk user action", "action", job.Action, "matched", response.Matched,
+		"succeeded", response.Succeeded, "failed", response.Failed, "by", job.Actor.ActorID)
+	return response
+}
+
+// Task runs bulk jobs as operations
+func (b *BulkUsers) Task() operations.Task {
+	return func(ctx context.Context, params json.RawMessage, progress func(done, total int)) (interface{}, error) {
+		var job BulkUsersJob
+		if err := json.Unmarshal(params, &job); err != nil {
+			return nil, err
+		}
+		return b.Run(ctx, job, progress), nil
+	}
+}
+
+// apply applies the action of job to one user and reports the outcome
+func (b *BulkUsers) apply(ctx context.Context, job BulkUsersJob, userID string) models.BulkUserResult {
+	if userID == job.Actor.ActorID {
+		return models.BulkUserResult{ID: userID, Status: "failed", Error: "self"}
+	}
+
+	var err error
+	switch job.Action {
+	case "activate", "deactivate":
+		_, err = b.users.SetActive(ctx, userID, job.Action == "activate", job.ActorRole)
+	case "delete":
+		err = b.users.DeleteUser(ctx, userID, job.ActorRole)
+	case "role":
+		err = b.changeRole(ctx, job, userID)
+	}
+
+	switch {
+	case err == nil:
+		return models.BulkUserResult{ID: userID, Status: "succeeded"}
+	case errors.Is(err, services.ErrInvalidUserID):
+		return models.BulkUserResult{ID: userID, Status: "failed", Error: "invalid_id"}
+	case errors.Is(err, services.ErrUserNotFound):
+		return models.BulkUserResult{ID: userID, Status: "failed", Error: "not_found"}
+	case errors.Is(err, services.ErrLastSuperadmin):
+		return models.BulkUserResult{ID: userID, Status: "failed", Error: "last_superadmin"}
+	case errors.Is(err, services.ErrSuperadminTarget):
+		return models.BulkUserResult{ID: userID, Status: "failed", Error: "forbidden"}
+	case errors.Is(err, context.Canceled):
+		return models.BulkUserResult{ID: userID, Status: "failed", Error: "canceled"}
+	}
+	utils.LoggerFrom(ctx, b.logger).Error("Failed to apply a bulk action", "action", job.Action, "user_id", userID, "error", err)
+	return models.BulkUserResult{ID: userID, Status: "failed", Error: "internal_error"}
+}
+
+// changeRole gives the user the role of job and records the change, or the refusal to demote the last
+// superadmin, in the security log like PATCH /admin/users/{id}/role
+func (b *BulkUsers) changeRole(ctx context.Context, job BulkUsersJob, userID string) error {
+	userInfo, previous, err := b.users.ChangeRole(ctx, userID, job.Role)
+	event := job.Actor
+	event.Type, event.UserID, event.Reason = security.EventRoleChange, userID, job.Reason
+	switch {
+	case err == nil:
+		event.Outcome, event.Email = security.OutcomeSuccess, userInfo.Email
+		event.Details = map[string]string{"previous_role": previous, "role": job.Role, "bulk": "true"}
+	case errors.Is(err, services.ErrLastSuperadmin):
+		event.Outcome = security.OutcomeFailure
+		event.Details = map[string]string{"role": job.Role, "error": "last_superadmin", "bulk": "true"}
+	default:
+		return err
+	}
+	b.securityLog.Log(ctx, event)
+	return err
+}
@@ -0,0 +1,34 @@
+package jobs_test
+
+import (
+	"context"
+	"testing"
+
+	"go-backend-template/jobs"
+	"go-backend-template/security"
+	"go-backend-template/testutil"
+)
+
+func TestBulkUsersProtectsSuperadmins(t *testing.T) {
+	users := testutil.NewUserRepository(nil)
+	actor := users.Add(testutil.NewUser().Admin().Model())
+	superadmin := users.Add(testutil.NewUser().WithRole("superadmin").Model())
+	user := users.Add(testutil.NewUser().Model())
+	bulk := jobs.NewBulkUsers(users, nil, testutil.Logger())
+
+	for _, action := range []string{"deactivate", "delete"} {
+		job := jobs.BulkUsersJob{
+			Action:    action,
+			IDs:       []string{superadmin.ID, user.ID},
+			Actor:     security.Event{ActorID: actor.ID},
+			ActorRole: actor.Role,
+		}
+		response := bulk.Run(context.Background(), job, nil)
+		if response.Succeeded != 1 || response.Results[0].Error != "forbidden" || response.Results[1].Status != "succeeded" {
+			t.Errorf("%s by an admin = %+v, want the superadmin forbidden and the user done", action, response.Results)
+		}
+	}
+	if stored, _ := users.User(superadmin.ID); !stored.IsActive || stored.DeletedAt.Valid {
+		t.Errorf("superadmin was changed: %+v", stored)
+	}
+}
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go-backend-template/database"
+	"go-backend-template/utils"
+)
+
+// ConnectionMonitor pings the databases periodically and logs when a connection is lost or restored.
+// PostgreSQL replicas that fail the ping are taken out of read rotation until they respond again.
+// Both drivers reconnect on their own; the pings also re-dial the PostgreSQL pool as soon as the
+// database is back instead of on the next request.
+type ConnectionMonitor struct {
+	mongoDB    *database.MongoDB
+	postgresDB *database.PostgresDB
+	interval   time.Duration
+	logger     utils.Logger
+	healthy    map[string]bool
+}
+
+// NewConnectionMonitor creates a monitor for the configured databases, run every interval
+func NewConnectionMonitor(mongoDB *database.MongoDB, postgresDB *database.PostgresDB, interval time.Duration, logger utils.Logger) *ConnectionMonitor {
+	return &ConnectionMonitor{
+		mongoDB:    mongoDB,
+		postgresDB: postgresDB,
+		interval:   interval,
+		logger:     logger,
+		healthy:    map[string]bool{"MongoDB": true, "PostgreSQL": true},
+	}
+}
+
+// Start checks the connections in the background every interval until ctx is canceled; a non-positive
+// interval disables monitoring
+func (m *ConnectionMonitor) Start(ctx context.Context) {
+	if m.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if m.postgresDB != nil {
+					m.report("PostgreSQL", m.postgresDB.HealthCheck(ctx))
+					for replica, err := range m.postgresDB.CheckReplicas(ctx) {
+						m.report("PostgreSQL "+replica, err)
+					}
+				}
+				if m.mongoDB != nil {
+					m.report("MongoDB", m.mongoDB.HealthCheck(ctx))
+				}
+			}
+		}
+	}()
+}
+
+// report logs a change in a database's reachability; a node not seen before is assumed healthy
+func (m *ConnectionMonitor) report(name string, err error) {
+	healthy, seen := m.healthy[name]
+	switch {
+	case err != nil && (healthy || !seen):
+		m.logger.Error("Lost connection to "+name+", waiting for it to come back", "error", err)
+	case err == nil && seen && !healthy:
+		m.logger.Info("Connection to " + name + " restored")
+	}
+	m.healthy[name] = err == nil
+}
@@ -0,0 +1,211 @@
+// Package jobs runs periodic background maintenance
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"go-backend-template/analytics"
+	"go-backend-template/audit"
+	"go-backend-template/config"
+	"go-backend-template/database"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// Retention policies, the kinds of stored data the retention job removes once they are too old
+const (
+	// PolicyDeletedUsers permanently removes users soft-deleted longer ago than USER_PURGE_AFTER
+	PolicyDeletedUsers = "deleted_users"
+	// PolicyAuditLogs removes audit log entries older than RETENTION_AUDIT_LOGS
+	PolicyAuditLogs = "audit_logs"
+	// PolicyLoginHistory removes the login analytics events older than RETENTION_LOGIN_HISTORY
+	PolicyLoginHistory = "login_history"
+	// PolicyAnalyticsEvents removes the other analytics events older than RETENTION_ANALYTICS_EVENTS
+	PolicyAnalyticsEvents = "analytics_events"
+	// PolicyWebhookEvents removes the processed webhook deliveries received longer ago than
+	// RETENTION_WEBHOOK_EVENTS; dead letters are kept
+	PolicyWebhookEvents = "webhook_events"
+	// PolicyOperations removes the operations finished longer ago than RETENTION_OPERATIONS
+	PolicyOperations = "operations"
+)
+
+// RetentionResult is what one policy removed in a cleanup pass, or would have removed in a dry run
+type RetentionResult struct {
+	Policy string
+	Cutoff time.Time
+	Count  int64
+}
+
+// Retention enforces the retention policies on both databases. A policy with a non-positive retention
+// keeps its data forever. In a dry run nothing is removed; the job only reports what it would remove.
+type Retention struct {
+	mongoDB    *database.MongoDB
+	postgresDB *database.PostgresDB
+	auditLog   *audit.Recorder
+	cfg        config.RetentionConfig
+	logger     utils.Logger
+}
+
+// NewRetention creates a retention job for cfg; auditLog is nil when the audit log is disabled
+func NewRetention(mongoDB *database.MongoDB, postgresDB *database.PostgresDB, auditLog *audit.Recorder, cfg config.RetentionConfig, logger utils.Logger) *Retention {
+	return &Retention{
+		mongoDB:    mongoDB,
+		postgresDB: postgresDB,
+		auditLog:   auditLog,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+// Start runs the policies in the background every interval until ctx is canceled, as a dry run when
+// RETENTION_DRY_RUN is set; a non-positive interval disables the job
+func (r *Retention) Start(ctx context.Context) {
+	if r.cfg.Interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(r.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := r.Run(ctx, r.cfg.DryRun); err != nil {
+					r.logger.Error("Failed to enforce the retention policies", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Run applies every enabled policy, or only counts what each would remove when dryRun, and returns the
+// result of each policy that succeeded; a failing policy does not stop the others
+func (r *Retention) Run(ctx context.Context, dryRun bool) ([]RetentionResult, error) {
+	policies := []struct {
+		name      string
+		retention time.Duration
+		apply     func(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error)
+	}{
+		{PolicyDeletedUsers, r.cfg.DeletedUsers, r.pruneDeletedUsers},
+		{PolicyAuditLogs, r.cfg.AuditLogs, r.pruneAuditLogs},
+		{PolicyLoginHistory, r.cfg.LoginHistory, r.pruneLoginHistory},
+		{PolicyAnalyticsEvents, r.cfg.AnalyticsEvents, r.pruneAnalyticsEvents},
+		{PolicyWebhookEvents, r.cfg.WebhookEvents, r.pruneWebhookEvents},
+		{PolicyOperations, r.cfg.Operations, r.pruneOperations},
+	}
+
+	now := time.Now()
+	var results []RetentionResult
+	var errs []error
+	for _, policy := range policies {
+		if policy.retention <= 0 {
+			continue
+		}
+		cutoff := now.Add(-policy.retention)
+		count, err := policy.apply(ctx, cutoff, dryRun)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", policy.name, err))
+			continue
+		}
+		results = append(results, RetentionResult{Policy: policy.name, Cutoff: cutoff, Count: count})
+
+		if dryRun {
+			r.logger.Info("Retention dry run", "policy", policy.name, "would_remove", count, "older_than", cutoff)
+		} else if count > 0 {
+			r.logger.Info("Removed expired data", "policy", policy.name, "count", count, "older_than", cutoff)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// pruneDeletedUsers hard-deletes the users soft-deleted before cutoff
+func (r *Retention) pruneDeletedUsers(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	return r.prune(ctx, dryRun, &models.User{}, "users", bson.M{"deleted_at": bson.M{"$lt": cutoff}},
+		"deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+}
+
+// pruneAuditLogs removes the audit log entries created before cutoff, keeping the rest of the chain
+// verifiable
+func (r *Retention) pruneAuditLogs(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	if r.auditLog == nil {
+		return 0, nil
+	}
+	return r.auditLog.Prune(ctx, cutoff, dryRun)
+}
+
+// pruneLoginHistory removes the login analytics events that occurred before cutoff
+func (r *Retention) pruneLoginHistory(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	return r.prune(ctx, dryRun, &models.AnalyticsEvent{}, "analytics_events",
+		bson.M{"name": analytics.EventLogin, "occurred_at": bson.M{"$lt": cutoff}},
+		"name = ? AND occurred_at < ?", analytics.EventLogin, cutoff)
+}
+
+// pruneAnalyticsEvents removes the analytics events other than logins that occurred before cutoff
+func (r *Retention) pruneAnalyticsEvents(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	return r.prune(ctx, dryRun, &models.AnalyticsEvent{}, "analytics_events",
+		bson.M{"name": bson.M{"$ne": analytics.EventLogin}, "occurred_at": bson.M{"$lt": cutoff}},
+		"name <> ? AND occurred_at < ?", analytics.EventLogin, cutoff)
+}
+
+// pruneWebhookEvents removes the processed webhook deliveries received before cutoff. A provider retrying
+// one after that would have it processed again, so the retention must outlast the provider's retries.
+func (r *Retention) pruneWebhookEvents(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	return r.prune(ctx, dryRun, &models.WebhookEvent{}, "webhook_events",
+		bson.M{"status": models.WebhookProcessed, "received_at": bson.M{"$lt": cutoff}},
+		"status = ? AND received_at < ?", models.WebhookProcessed, cutoff)
+}
+
+// pruneOperations removes the operations finished before cutoff, with their results; queued and running
+// operations are kept
+func (r *Retention) pruneOperations(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	return r.prune(ctx, dryRun, &models.Operation{}, "operations", bson.M{"finished_at": bson.M{"$lt": cutoff}},
+		"finished_at < ?", cutoff)
+}
+
+// prune removes, or only counts when dryRun, the rows of model matching query in PostgreSQL and the
+// documents of collection matching filter in MongoDB
+func (r *Retention) prune(ctx context.Context, dryRun bool, model interface{}, collection string, filter bson.M, query string, args ...interface{}) (int64, error) {
+	var count int64
+
+	if r.postgresDB != nil {
+		db := r.postgresDB.WithContext(ctx).Unscoped().Model(model).Where(query, args...)
+		if dryRun {
+			var matched int64
+			if err := db.Count(&matched).Error; err != nil {
+				return count, err
+			}
+			count += matched
+		} else {
+			result := db.Delete(model)
+			if result.Error != nil {
+				return count, result.Error
+			}
+			count += result.RowsAffected
+		}
+	}
+
+	if r.mongoDB != nil {
+		if dryRun {
+			matched, err := r.mongoDB.Collection(collection).CountDocuments(ctx, filter)
+			if err != nil {
+				return count, err
+			}
+			count += matched
+		} else {
+			result, err := r.mongoDB.Collection(collection).DeleteMany(ctx, filter)
+			if err != nil {
+				return count, err
+			}
+			count += result.DeletedCount
+		}
+	}
+	return count, nil
+}
@@ -1,35 +1,137 @@
 package jwt
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Roles of a user in an organization, from the least to the most privileged. They are separate from the
+// global Role: an organization admin administers one tenant, a global admin the whole deployment.
+const (
+	OrgRoleMember = "member"
+	OrgRoleAdmin  = "admin"
+	OrgRoleOwner  = "owner"
+)
+
+// orgRoleRanks orders the organization roles
+var orgRoleRanks = map[string]int{OrgRoleMember: 1, OrgRoleAdmin: 2, OrgRoleOwner: 3}
+
 // Claims represents JWT claims
 type Claims struct {
-	UserID   interface{} `json:"user_id"`
-	Email    string      `json:"email"`
-	Username string      `json:"username"`
-	Role     string      `json:"role"`
+	UserID   UserID `json:"user_id"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Locale   string `json:"locale,omitempty"`
+	// OrgID is the organization (tenant) the token acts for by default; it must be one of Memberships
+	OrgID string `json:"org_id,omitempty"`
+	// Memberships maps the IDs of the organizations the user belongs to to their role in each
+	Memberships map[string]string `json:"memberships,omitempty"`
+	// ClientID is set on tokens issued to OAuth clients, which act for the user only within Scope
+	ClientID string `json:"client_id,omitempty"`
+	// Scope is the space-separated list of scopes granted to the client
+	Scope string `json:"scope,omitempty"`
+	// SessionID is the refresh token session the token was issued for, empty without sessions
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a JWT token for a user
-func GenerateToken(secret string, userID interface{}, email, username, role string) (string, time.Time, error) {
-	expirationTime := time.Now().Add(24 * time.Hour) // Token expires in 24 hours
+// IsService reports whether the token was issued to a service client for itself rather than for a user
+func (c *Claims) IsService() bool {
+	return c.ClientID != "" && c.UserID == ""
+}
+
+// HasScope reports whether the token was granted scope
+func (c *Claims) HasScope(scope string) bool {
+	for _, granted := range strings.Fields(c.Scope) {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the organization claims; the parser calls it after verifying the signature and times
+func (c *Claims) Validate() error {
+	for orgID, role := range c.Memberships {
+		if orgID == "" {
+			return errors.New("membership without an organization ID")
+		}
+		if !ValidOrgRole(role) {
+			return fmt.Errorf("invalid role %q in organization %s", role, orgID)
+		}
+	}
+	if _, ok := c.Memberships[c.OrgID]; c.OrgID != "" && !ok {
+		return fmt.Errorf("organization %s is not one of the memberships", c.OrgID)
+	}
+	return nil
+}
 
-	claims := &Claims{
-		UserID:   userID,
+// ValidOrgRole reports whether role is an organization role
+func ValidOrgRole(role string) bool {
+	_, ok := orgRoleRanks[role]
+	return ok
+}
+
+// OrgRoleAtLeast reports whether the organization role has the privileges of minimum; owners have those
+// of admins, and admins those of members
+func OrgRoleAtLeast(role, minimum string) bool {
+	rank, ok := orgRoleRanks[role]
+	return ok && rank >= orgRoleRanks[minimum]
+}
+
+// UserID is the user ID claim, signed as a string. Tokens signed before IDs were strings carry PostgreSQL
+// IDs as numbers; those are read as their decimal string.
+type UserID string
+
+// UnmarshalJSON accepts the ID as a string or a number
+func (id *UserID) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*id = UserID(value)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	*id = UserID(number)
+	return nil
+}
+
+// GenerateToken generates a JWT token for a user; locale is the user's preferred language, if any
+func GenerateToken(secret string, userID string, email, username, role, locale string) (string, time.Time, error) {
+	return Sign(secret, NewClaims(userID, email, username, role, locale))
+}
+
+// NewClaims returns the claims of a token for a user that expires in 24 hours; set OrgID and Memberships
+// before signing to scope it to organizations
+func NewClaims(userID, email, username, role, locale string) *Claims {
+	now := time.Now()
+	return &Claims{
+		UserID:   UserID(userID),
 		Email:    email,
 		Username: username,
 		Role:     role,
+		Locale:   locale,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
+}
+
+// Sign validates and signs claims, returning the token and its expiry
+func Sign(secret string, claims *Claims) (string, time.Time, error) {
+	if err := claims.Validate(); err != nil {
+		return "", time.Time{}, err
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(secret))
@@ -37,7 +139,11 @@ func GenerateToken(secret string, userID interface{}, email, username, role stri
 		return "", time.Time{}, err
 	}
 
-	return tokenString, expirationTime, nil
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	return tokenString, expiresAt, nil
 }
 
 // ValidateToken validates a JWT token and returns claims
@@ -0,0 +1,12 @@
+// Package locales embeds the built-in translations. Each file holds one language, named by its code
+// (en.json, de.toml), as a JSON or TOML object of message keys to text; nested objects and tables are
+// flattened with dots, so {"validation": {"len": "..."}} defines validation.len. The language.name and
+// language.direction keys describe the language itself: its native name and ltr or rtl.
+package locales
+
+import "embed"
+
+// FS holds the translation files compiled into the binary; files other than .json and .toml are ignored
+//
+//go:embed *
+var FS embed.FS
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/analytics"
+	"go-backend-template/utils"
+)
+
+// Analytics middleware records a feature_used event for each successful change an authenticated user
+// makes, naming the feature by method and route (POST /api/v1/posts). Reads are not recorded. It must run
+// after JWTAuth; a nil tracker records nothing.
+func Analytics(tracker *analytics.Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		userID := utils.Scope(c).UserID
+		if tracker == nil || userID == "" || c.FullPath() == "" || c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		tracker.TrackRequest(c, analytics.EventFeatureUsed, userID, map[string]interface{}{
+			"feature": c.Request.Method + " " + c.FullPath(),
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/audit"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// Audit middleware records every request to the routes it guards in the audit log after the response:
+// the user, the route, the status, and a hash of the body, which is not stored itself. A failure to
+// record is logged; the response has already been written by then.
+func Audit(recorder *audit.Recorder, logger utils.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payloadHash := ""
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					abortTooLarge(c)
+					return
+				}
+				abortWithError(c, http.StatusBadRequest, "bad_request", "Failed to read request body")
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			if len(body) > 0 {
+				sum := sha256.Sum256(body)
+				payloadHash = hex.EncodeToString(sum[:])
+			}
+		}
+
+		c.Next()
+
+		entry := &models.AuditLog{
+			ActorID:     utils.Scope(c).UserID,
+			ActorRole:   utils.Scope(c).Role,
+			Method:      c.Request.Method,
+			Route:       c.FullPath(),
+			Path:        c.Request.URL.Path,
+			Status:      c.Writer.Status(),
+			ClientIP:    utils.ClientIP(c),
+			RequestID:   utils.Scope(c).RequestID,
+			PayloadHash: payloadHash,
+		}
+		// The request context may be canceled or past its deadline once the response is written
+		if err := recorder.Record(context.WithoutCancel(c.Request.Context()), entry); err != nil {
+			logger.Error("Failed to record the audit log entry", "method", entry.Method, "path", entry.Path, "actor_id", entry.ActorID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// originalBodyKey stores the unwrapped request body so a route-level limit can replace the global one
+const originalBodyKey = "original_body"
+
+// BodyLimit middleware rejects request bodies larger than maxBytes with 413.
+// It can be applied globally and again on specific groups to lower the limit
+// for those routes; use RouteBodyLimit to raise it, as the global limit already
+// refuses a larger Content-Length.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return RouteBodyLimit(func(string) int64 { return maxBytes })
+}
+
+// RouteBodyLimit middleware is BodyLimit with the limit of each request chosen by limitFor from the
+// registered route path, such as a higher limit for upload routes
+func RouteBodyLimit(limitFor func(route string) int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		maxBytes := limitFor(c.FullPath())
+		if c.Request.ContentLength > maxBytes {
+			abortTooLarge(c)
+			return
+		}
+
+		body := c.Request.Body
+		if original, exists := c.Get(originalBodyKey); exists {
+			body = original.(io.ReadCloser)
+		} else {
+			c.Set(originalBodyKey, body)
+		}
+
+		// Chunked bodies have no Content-Length, so enforce the limit while reading as well
+		c.Request.Body = http.MaxBytesReader(c.Writer, body, maxBytes)
+		c.Next()
+	}
+}
+
+// abortTooLarge writes the localized 413 response
+func abortTooLarge(c *gin.Context) {
+	abortWithError(c, http.StatusRequestEntityTooLarge, "request_too_large", "Request body exceeds the maximum allowed size")
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/utils"
+)
+
+// ConcurrencyLimiter caps the requests served at once, overall and per route group, so a traffic spike is
+// answered 503 right away instead of piling up on the database pool until every request times out. The
+// last reserved slots of the overall limit are kept for priority requests, such as health checks and the
+// requests of admins, so probes pass and operators can still act while the other requests are shed.
+type ConcurrencyLimiter struct {
+	overall    *inFlight
+	reserved   int64
+	groups     map[string]*inFlight
+	retryAfter time.Duration
+}
+
+// inFlight counts the requests in progress against a limit; 0 is unlimited
+type inFlight struct {
+	limit    int64
+	current  atomic.Int64
+	rejected atomic.Int64
+}
+
+// acquire counts a request in, or counts it rejected when the limit is reached, or the limit less reserved
+// and priority returns false. priority is only called for the reserved slots.
+func (f *inFlight) acquire(reserved int64, priority func() bool) bool {
+	if current := f.current.Add(1); f.limit > 0 && current > f.limit-reserved && (current > f.limit || !priority()) {
+		f.current.Add(-1)
+		f.rejected.Add(1)
+		return false
+	}
+	return true
+}
+
+// ConcurrencyStats is the load of the limiter overall, when Group is empty, or of a route group
+type ConcurrencyStats struct {
+	Group string
+	// Limit is the most requests served at once, 0 for unlimited, and Reserved how many of those only
+	// priority requests are served
+	Limit    int
+	Reserved int
+	InFlight int64
+	// Rejected counts the requests answered 503 because the limit was reached
+	Rejected int64
+}
+
+// ConcurrencyRoute is how the limiter treats the requests to a route
+type ConcurrencyRoute struct {
+	// Group is the path prefix whose limit applies on top of the overall one, "" for none
+	Group string
+	// Exempt routes are not counted, such as long-lived streams, which would hold their slot for as long
+	// as they stay open
+	Exempt bool
+	// Priority routes may take the reserved slots whoever asks, such as the health check
+	Priority bool
+}
+
+// NewConcurrencyLimiter creates a limiter serving at most limit requests at once, 0 for unlimited, of which
+// reserved only to priority requests, and at most groups[prefix] under each path prefix. Refused requests
+// are told to retry after retryAfter.
+func NewConcurrencyLimiter(limit, reserved int, groups map[string]int, retryAfter time.Duration) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{overall: &inFlight{limit: int64(limit)}, reserved: int64(reserved), groups: make(map[string]*inFlight, len(groups)), retryAfter: retryAfter}
+	for prefix, groupLimit := range groups {
+		l.groups[prefix] = &inFlight{limit: int64(groupLimit)}
+	}
+	return l
+}
+
+// Groups returns the path prefixes with a limit of their own
+func (l *ConcurrencyLimiter) Groups() []string {
+	prefixes := make([]string, 0, len(l.groups))
+	for prefix := range l.groups {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+// acquire counts a request to route in, overall and in its group, or returns false when either is full.
+// Only priority requests take the reserved slots: those to priority routes, and those priority accepts.
+func (l *ConcurrencyLimiter) acquire(route ConcurrencyRoute, priority func() bool) bool {
+	if !l.overall.acquire(l.reserved, func() bool { return route.Priority || priority() }) {
+		return false
+	}
+	if g := l.groups[route.Group]; g != nil && !g.acquire(0, nil) {
+		l.overall.current.Add(-1)
+		return false
+	}
+	return true
+}
+
+// release counts a request to group out
+func (l *ConcurrencyLimiter) release(group string) {
+	if g := l.groups[group]; g != nil {
+		g.current.Add(-1)
+	}
+	l.overall.current.Add(-1)
+}
+
+// Snapshot returns the load overall, then of each group sorted by prefix
+func (l *ConcurrencyLimiter) Snapshot() []ConcurrencyStats {
+	snapshot := []ConcurrencyStats{{Limit: int(l.overall.limit), Reserved: int(l.reserved), InFlight: l.overall.current.Load(), Rejected: l.overall.rejected.Load()}}
+	for _, prefix := range l.Groups() {
+		g := l.groups[prefix]
+		snapshot = append(snapshot, ConcurrencyStats{Group: prefix, Limit: int(g.limit), InFlight: g.current.Load(), Rejected: g.rejected.Load()})
+	}
+	return snapshot
+}
+
+// LimitConcurrency middleware answers 503 with Retry-After when the limiter is full. routeFor returns how
+// the requests to a registered route path are limited, and priority, when not nil, whether a request to
+// any route may take the reserved slots; it is only asked once the others are full, such as PriorityRole.
+func LimitConcurrency(limiter *ConcurrencyLimiter, routeFor func(route string) ConcurrencyRoute, priority func(c *gin.Context) bool) gin.HandlerFunc {
+	retryAfter := strconv.Itoa(max(1, int(math.Ceil(limiter.retryAfter.Seconds()))))
+	return func(c *gin.Context) {
+		route := routeFor(c.FullPath())
+		if route.Exempt {
+			c.Next()
+			return
+		}
+		if !limiter.acquire(route, func() bool { return priority != nil && priority(c) }) {
+			c.Header("Retry-After", retryAfter)
+			abortWithError(c, http.StatusServiceUnavailable, "server_overloaded", "Too many requests in progress, please try again later")
+			return
+		}
+		defer limiter.release(route.Group)
+		c.Next()
+	}
+}
+
+// PriorityRole returns whether a request carries a valid token, in the Authorization header or the cookie
+// named cookieName, of a user with one of roles. The limiter runs before JWTAuth, so it checks the token
+// itself: a path alone would let anonymous requests to the admin routes take the reserved slots.
+func PriorityRole(jwtUtils *utils.JWTUtils, cookieName string, roles ...string) func(c *gin.Context) bool {
+	return func(c *gin.Context) bool {
+		// As in JWTAuth, the cookie is only read without an Authorization header
+		tokenString := c.GetHeader("Authorization")
+		if tokenString != "" {
+			tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+		} else if cookieName != "" {
+			tokenString, _ = c.Cookie(cookieName)
+		}
+		if tokenString == "" {
+			return false
+		}
+		claims, err := verifyToken(jwtUtils, tokenString)
+		return err == nil && claims.ClientID == "" && slices.Contains(roles, claims.Role)
+	}
+}
@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/middleware"
+	"go-backend-template/testutil"
+)
+
+// TestLimitConcurrencyPriority fills the unreserved slot, then checks who may take the reserved one: the
+// priority route and admins, but not anonymous requests to the admin routes or other users
+func TestLimitConcurrencyPriority(t *testing.T) {
+	tokens := testutil.NewTokenFactory()
+	limiter := middleware.NewConcurrencyLimiter(2, 1, nil, time.Second)
+	routeFor := func(route string) middleware.ConcurrencyRoute {
+		return middleware.ConcurrencyRoute{Priority: route == "/health"}
+	}
+
+	held, release := make(chan struct{}), make(chan struct{})
+	router := testutil.NewRouter()
+	router.Use(middleware.LimitConcurrency(limiter, routeFor, middleware.PriorityRole(tokens.JWT, "", "admin", "superadmin")))
+	router.GET("/hold", func(c *gin.Context) {
+		held <- struct{}{}
+		<-release
+	})
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/health", ok)
+	router.GET("/admin/stats", ok)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		testutil.Serve(router, testutil.NewRequest(t, http.MethodGet, "/hold", nil))
+	}()
+	<-held
+	defer func() {
+		close(release)
+		<-done
+	}()
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"priority route", "/health", "", http.StatusOK},
+		{"admin", "/admin/stats", tokens.Token(t, "1", "admin"), http.StatusOK},
+		{"superadmin", "/admin/stats", tokens.Token(t, "2", "superadmin"), http.StatusOK},
+		{"anonymous", "/admin/stats", "", http.StatusServiceUnavailable},
+		{"user", "/admin/stats", tokens.Token(t, "3", "user"), http.StatusServiceUnavailable},
+		{"forged admin", "/admin/stats", testutil.NewTokenFactory().Token(t, "4", "admin") + "x", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testutil.NewRequest(t, http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := testutil.Serve(router, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status == http.StatusServiceUnavailable && rec.Header().Get("Retry-After") != "1" {
+				t.Errorf("Retry-After = %q, want 1", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
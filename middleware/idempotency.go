@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/idempotency"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// IdempotencyKeyHeader is the request header carrying the client-generated idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyRecorder captures the response so it can be stored for replay
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency middleware honors the Idempotency-Key header on unsafe requests. The first
+// response for a key is stored for ttl and replayed for retries with the same payload;
+// reusing a key with a different payload returns 422, and a retry while the first request
+// is still running returns 409. Requests without the header are processed normally.
+func Idempotency(store idempotency.Store, ttl time.Duration, logger utils.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			abortWithError(c, http.StatusBadRequest, "bad_request", "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortTooLarge(c)
+				return
+			}
+			abortWithError(c, http.StatusBadRequest, "bad_request", "Failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped per route and per user so clients cannot collide with each other
+		storageKey := fmt.Sprintf("%s %s %s %s", c.Request.Method, c.FullPath(), utils.Scope(c).UserID, key)
+		fingerprint := sha256.Sum256(body)
+		ctx := c.Request.Context()
+
+		record, err := store.Get(ctx, storageKey)
+		if err != nil && !errors.Is(err, idempotency.ErrNotFound) {
+			logger.Error("Idempotency lookup failed", "error", err)
+			c.Next()
+			return
+		}
+
+		if record == nil {
+			now := time.Now()
+			record = &models.IdempotencyRecord{
+				Key:         storageKey,
+				Fingerprint: hex.EncodeToString(fingerprint[:]),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+			}
+
+			reserved, err := store.Reserve(ctx, record)
+			if err != nil {
+				logger.Error("Idempotency reservation failed", "error", err)
+				c.Next()
+				return
+			}
+			if reserved {
+				processIdempotent(c, store, record, logger)
+				return
+			}
+
+			// Lost a race with a concurrent request using the same key
+			if record, err = store.Get(ctx, storageKey); err != nil {
+				abortIdempotencyInProgress(c)
+				return
+			}
+		}
+
+		if record.Fingerprint != hex.EncodeToString(fingerprint[:]) {
+			abortWithError(c, http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency-Key was already used with a different request payload")
+			return
+		}
+
+		if !record.Completed {
+			abortIdempotencyInProgress(c)
+			return
+		}
+
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(record.StatusCode, record.ContentType, record.Body)
+		c.Abort()
+	}
+}
+
+// processIdempotent runs the handler and stores its response; server errors release the key so the client can retry
+func processIdempotent(c *gin.Context, store idempotency.Store, record *models.IdempotencyRecord, logger utils.Logger) {
+	recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	c.Next()
+
+	// Use a fresh context: the request context may already be cancelled after a timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A handler that missed the request deadline without responding gets a timeout response instead
+	timedOut := !recorder.Written() && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+	if recorder.Status() >= http.StatusInternalServerError || timedOut {
+		if err := store.Release(ctx, record.Key); err != nil {
+			logger.Error("Failed to release idempotency key", "error", err)
+		}
+		return
+	}
+
+	record.Completed = true
+	record.StatusCode = recorder.Status()
+	record.ContentType = recorder.Header().Get("Content-Type")
+	record.Body = recorder.body.Bytes()
+	if err := store.Complete(ctx, record); err != nil {
+		logger.Error("Failed to store idempotent response", "error", err)
+	}
+}
+
+func abortIdempotencyInProgress(c *gin.Context) {
+	abortWithError(c, http.StatusConflict, "idempotency_in_progress", "A request with this Idempotency-Key is still being processed")
+}
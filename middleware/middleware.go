@@ -2,25 +2,31 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"runtime/pprof"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
-	"go-backend-template/config"
+	"go-backend-template/billing"
+	"go-backend-template/errcodes"
 	"go-backend-template/jwt"
-	"go-backend-template/models"
+	"go-backend-template/usage"
 	"go-backend-template/utils"
 )
 
-// Logger middleware for request logging
+// Logger middleware for request logging; the credentials in the query, such as the access token of
+// streams, are redacted from the logged path
 func Logger(logger utils.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
 		logger.Info("HTTP Request",
 			"method", param.Method,
-			"path", param.Path,
+			"path", redactPath(param.Path),
 			"status", param.StatusCode,
 			"latency", param.Latency,
 			"client_ip", param.ClientIP,
@@ -30,16 +36,12 @@ func Logger(logger utils.Logger) gin.HandlerFunc {
 	})
 }
 
-// Recovery middleware for panic recovery
-func Recovery(logger utils.Logger) gin.HandlerFunc {
-	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		logger.Error("Panic recovered", "error", recovered)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Message: "Internal server error",
-			Error:   "Something went wrong",
-		})
-	})
+// RealIP middleware resolves the client IP once per request, honoring trusted proxies only
+func RealIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(utils.ClientIPKey, c.ClientIP())
+		c.Next()
+	}
 }
 
 // CORS middleware for cross-origin requests
@@ -47,7 +49,8 @@ func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, Idempotency-Key, X-Bot-Challenge, If-Match, If-None-Match, X-Org-ID")
+		c.Header("Access-Control-Expose-Headers", "ETag, X-Request-ID")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
@@ -59,8 +62,9 @@ func CORS() gin.HandlerFunc {
 	}
 }
 
-// RequestID middleware adds a unique request ID to each request
-func RequestID() gin.HandlerFunc {
+// RequestID middleware adds a unique request ID to each request and gives its scope (see utils.Scope) a
+// logger that tags every line with the ID
+func RequestID(logger utils.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
 		if requestID == "" {
@@ -68,47 +72,99 @@ func RequestID() gin.HandlerFunc {
 		}
 		c.Header("X-Request-ID", requestID)
 		c.Set("request_id", requestID)
+		scope := utils.Scope(c)
+		scope.RequestID, scope.Logger = requestID, utils.WithFields(logger, "request_id", requestID)
 		c.Next()
 	}
 }
 
-// Localization middleware for language support
-func Localization(localizer *utils.Localizer) gin.HandlerFunc {
+// DisableNegotiation middleware opts a route out of Accept-based content negotiation (always JSON)
+func DisableNegotiation() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get language from header, query parameter, or use default
-		lang := c.GetHeader("Accept-Language")
-		if lang == "" {
-			lang = c.Query("lang")
-		}
-		if lang == "" {
-			lang = localizer.DefaultLanguage
-		}
+		c.Set(utils.NegotiationDisabledKey, true)
+		c.Next()
+	}
+}
 
-		// Extract the primary language code
-		if strings.Contains(lang, ",") {
-			lang = strings.Split(lang, ",")[0]
-		}
-		if strings.Contains(lang, "-") {
-			lang = strings.Split(lang, "-")[0]
+// ProblemDetails middleware renders every error response as RFC 7807 problem+json
+func ProblemDetails() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(utils.ProblemDetailsKey, true)
+		c.Next()
+	}
+}
+
+// Localization middleware selects the response language from the Accept-Language header, or the lang query
+// parameter without one, among the supported languages (see utils.MatchLanguage) and reports it in
+// Content-Language. Without either, JWTAuth switches to the user's stored locale.
+func Localization(localizer *utils.Localizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requested := c.GetHeader("Accept-Language")
+		if requested == "" {
+			requested = c.Query("lang")
 		}
+		lang := localizer.Match(requested)
 
-		c.Set("language", lang)
+		c.Header("Content-Language", lang)
+		utils.SetLanguage(c, lang)
+		c.Set("language_requested", requested != "")
 		c.Set("localizer", localizer)
 		c.Next()
 	}
 }
 
+// localize translates key using the localizer and language set by the Localization middleware
+func localize(c *gin.Context, key string) string {
+	value, _ := c.Get("localizer")
+	localizer, ok := value.(*utils.Localizer)
+	if !ok {
+		return key
+	}
+	return localizer.Get(utils.Scope(c).Language, key)
+}
+
+// abortWithError stops the chain with an error response in the negotiated format, its message localized
+// from key in the request language, its code registered for key, and detail in English
+func abortWithError(c *gin.Context, status int, key, detail string) {
+	responseUtils := &utils.ResponseUtils{}
+	response := responseUtils.ErrorResponse(localize(c, key), detail)
+	response.Code = errcodes.ForKey(key)
+	responseUtils.Respond(c, status, response)
+	c.Abort()
+}
+
+// NotFound answers requests that match no route with a localized 404 in the negotiated format, instead of
+// gin's plain text
+func NotFound() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		abortUnrouted(c, http.StatusNotFound, "not_found", "No route matches the path")
+	}
+}
+
+// MethodNotAllowed answers requests for a path that does not serve their method with a localized 405; gin
+// has set the methods the path serves in Allow
+func MethodNotAllowed() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		abortUnrouted(c, http.StatusMethodNotAllowed, "method_not_allowed", "The path does not serve the "+c.Request.Method+" method")
+	}
+}
+
+// abortUnrouted is abortWithError for requests no handler ran for, such as a mistyped path; the response
+// carries the request ID, as no handler logged anything to find the request by
+func abortUnrouted(c *gin.Context, status int, key, detail string) {
+	responseUtils := &utils.ResponseUtils{}
+	response := responseUtils.ErrorResponse(localize(c, key), detail)
+	response.Code, response.RequestID = errcodes.ForKey(key), utils.Scope(c).RequestID
+	responseUtils.Respond(c, status, response)
+	c.Abort()
+}
+
 // RequireRole middleware for role-based authorization
 func RequireRole(requiredRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("user_role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, models.APIResponse{
-				Success: false,
-				Message: "User role not found",
-				Error:   "Authorization failed",
-			})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, "unauthorized", "User role not found")
 			return
 		}
 
@@ -120,12 +176,7 @@ func RequireRole(requiredRoles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, models.APIResponse{
-			Success: false,
-			Message: "Insufficient permissions",
-			Error:   "You don't have permission to access this resource",
-		})
-		c.Abort()
+		abortWithError(c, http.StatusForbidden, "insufficient_permissions", "You don't have permission to access this resource")
 	}
 }
 
@@ -141,7 +192,7 @@ func RateLimiter() gin.HandlerFunc {
 	window := time.Minute
 
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
+		clientIP := utils.ClientIP(c)
 		now := time.Now()
 
 		if clients[clientIP] == nil {
@@ -161,12 +212,7 @@ func RateLimiter() gin.HandlerFunc {
 		}
 
 		if clients[clientIP].requests >= limit {
-			c.JSON(http.StatusTooManyRequests, models.APIResponse{
-				Success: false,
-				Message: "Rate limit exceeded",
-				Error:   "Too many requests, please try again later",
-			})
-			c.Abort()
+			abortWithError(c, http.StatusTooManyRequests, "rate_limit_exceeded", "Too many requests, please try again later")
 			return
 		}
 
@@ -175,80 +221,283 @@ func RateLimiter() gin.HandlerFunc {
 	}
 }
 
-// Timeout middleware adds timeout to requests
-func Timeout(timeout time.Duration) gin.HandlerFunc {
+// ProfilerLabels labels the request goroutine with the route and method while the handlers run, so CPU
+// profiles and traces from /debug/pprof can be broken down by handler
+func ProfilerLabels() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		labels := pprof.Labels("route", c.FullPath(), "method", c.Request.Method)
+		pprof.Do(c.Request.Context(), labels, func(ctx context.Context) {
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+		})
+	}
+}
+
+// Timeout middleware gives each request a deadline through its context, which database calls and
+// outbound requests honor. The handler runs on the request goroutine, so nothing outlives the request;
+// if the deadline passes before the handler has written a response, its late writes are discarded and
+// the client gets 408 instead. Routes in exemptPaths (matched against the registered route path) are
+// long-lived connections and run without a deadline.
+func Timeout(timeout time.Duration, exemptPaths ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+
+	return RouteTimeout(func(route string) time.Duration {
+		if exempt[route] {
+			return 0
+		}
+		return timeout
+	}, nil)
+}
+
+// RouteTimeout middleware is Timeout with the deadline of each request chosen by timeoutFor from the
+// registered route path; routes it returns zero or less for run without a deadline. Requests whose
+// deadline passes are counted in stats when it is not nil.
+func RouteTimeout(timeoutFor func(route string) time.Duration, stats *TimeoutStats) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := timeoutFor(c.FullPath())
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 		defer cancel()
+		utils.ScopeFrom(ctx).Deadline, _ = ctx.Deadline()
 
 		c.Request = c.Request.WithContext(ctx)
+		writer := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Writer = writer
 
-		done := make(chan bool, 1)
-		go func() {
-			c.Next()
-			done <- true
-		}()
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if stats != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			stats.record(c.Request.Method, c.FullPath(), timeout)
+		}
+		if writer.timedOut() {
+			abortWithError(c, http.StatusRequestTimeout, "request_timeout", "Request took too long to process")
+		}
+	}
+}
+
+// timeoutWriter guards a response against writes after the request deadline. A response started in
+// time is completed normally; one not started by the deadline is dropped so the timeout response can
+// replace it.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx     context.Context
+	expired bool
+}
+
+// allow reports whether a write may go through, latching the timeout on the first late write
+func (w *timeoutWriter) allow() bool {
+	if !w.expired && !w.ResponseWriter.Written() && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.expired = true
+	}
+	return !w.expired
+}
+
+// timedOut reports whether the deadline passed before any response was written
+func (w *timeoutWriter) timedOut() bool {
+	return w.expired || (!w.ResponseWriter.Written() && errors.Is(w.ctx.Err(), context.DeadlineExceeded))
+}
+
+// WriteHeader records the status unless the deadline has passed
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.allow() {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
 
-		select {
-		case <-done:
+// WriteHeaderNow sends the header unless the deadline has passed
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.allow() {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Write writes the body unless the deadline has passed
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if !w.allow() {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes the body unless the deadline has passed
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if !w.allow() {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush flushes the response unless the deadline has passed
+func (w *timeoutWriter) Flush() {
+	if w.allow() {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// StreamToken lets browsers, which cannot set headers on a WebSocket handshake or an EventSource,
+// authenticate with an access_token query parameter. It must run before JWTAuth.
+func StreamToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stream := strings.EqualFold(c.GetHeader("Upgrade"), "websocket") ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+		if c.GetHeader("Authorization") == "" && stream {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+				// Keep the token out of anything that records the URL from here on
+				query := c.Request.URL.Query()
+				query.Del("access_token")
+				c.Request.URL.RawQuery = query.Encode()
+			}
+		}
+		c.Next()
+	}
+}
+
+// JWTAuth middleware for JWT authentication. The token is read from the
+// "Authorization: Bearer" header, or from the auth cookie when cookieName is set.
+func JWTAuth(jwtUtils *utils.JWTUtils, cookieName string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var tokenString string
+
+		authHeader := c.GetHeader("Authorization")
+		if authHeader != "" {
+			// Check if the header starts with "Bearer "
+			tokenString = strings.TrimPrefix(authHeader, "Bearer ")
+			if tokenString == authHeader {
+				abortWithError(c, http.StatusUnauthorized, "invalid_authorization_header", "Authorization header must start with 'Bearer '")
+				return
+			}
+		} else if cookieName != "" {
+			tokenString, _ = c.Cookie(cookieName)
+		}
+
+		if tokenString == "" {
+			abortWithError(c, http.StatusUnauthorized, "authorization_required", "No authorization header provided")
+			return
+		}
+
+		// Tokens issued to OAuth clients only grant access to the OAuth endpoints
+		claims, err := verifyToken(jwtUtils, tokenString)
+		if err != nil || claims.ClientID != "" {
+			abortWithError(c, http.StatusUnauthorized, "invalid_token", "Authentication failed")
 			return
-		case <-ctx.Done():
-			c.AbortWithStatusJSON(http.StatusRequestTimeout, models.APIResponse{
-				Success: false,
-				Message: "Request timeout",
-				Error:   "Request took too long to process",
-			})
+		}
+
+		// Extract claims and set in context
+		utils.SetUser(c, string(claims.UserID), claims.Email, claims.Username, claims.Role)
+		utils.Scope(c).SessionID = claims.SessionID
+		applyUserLocale(c, claims.Locale)
+		if !selectOrg(c, claims) {
+			abortWithError(c, http.StatusForbidden, "organization_membership_required", "You are not a member of the organization in "+OrgHeader)
 			return
 		}
+
+		c.Next()
 	}
 }
 
-// JWTAuth middleware for JWT authentication
-func JWTAuth() gin.HandlerFunc {
+// verifyToken parses and validates a JWT, accepting the previous secret during rotation
+func verifyToken(jwtUtils *utils.JWTUtils, tokenString string) (*jwt.Claims, error) {
+	var claims *jwt.Claims
+	var err error
+	for _, secret := range jwtUtils.VerificationSecrets() {
+		if claims, err = jwt.ValidateToken(secret, tokenString); err == nil {
+			return claims, nil
+		}
+	}
+	return nil, err
+}
+
+// applyUserLocale responds in the user's preferred language when the request did not ask for one and the
+// language is supported
+func applyUserLocale(c *gin.Context, locale string) {
+	if locale == "" || c.GetBool("language_requested") {
+		return
+	}
+	value, _ := c.Get("localizer")
+	localizer, ok := value.(*utils.Localizer)
+	if !ok {
+		return
+	}
+	if lang, ok := localizer.Lookup(locale); ok {
+		utils.SetLanguage(c, lang)
+		c.Header("Content-Language", lang)
+	}
+}
+
+// RequirePlan middleware allows only users whose subscription includes plan (or a higher tier). It must
+// run after JWTAuth. When billing is disabled (nil service) every request passes.
+func RequirePlan(service *billing.Service, plan string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, models.APIResponse{
-				Success: false,
-				Message: "Authorization header required",
-				Error:   "No authorization header provided",
-			})
-			c.Abort()
+		if service == nil {
+			c.Next()
 			return
 		}
 
-		// Check if the header starts with "Bearer "
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, models.APIResponse{
-				Success: false,
-				Message: "Invalid authorization header format",
-				Error:   "Authorization header must start with 'Bearer '",
-			})
-			c.Abort()
+		current, err := service.CurrentPlan(c.Request.Context(), utils.Scope(c).UserID)
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, "internal_error", "Failed to load subscription")
 			return
 		}
+		c.Set("user_plan", current)
 
-		// Parse and validate JWT token
-		cfg := config.Load()
-		claims, err := jwt.ValidateToken(cfg.JWTSecret, tokenString)
+		if !service.Catalog().Includes(current, plan) {
+			abortWithError(c, http.StatusPaymentRequired, "plan_required", "This resource requires the "+plan+" plan")
+			return
+		}
+		c.Next()
+	}
+}
+
+// Quota middleware counts each authenticated request against the user's monthly plan quota and rejects
+// requests beyond it with 429; bytes transferred are recorded after the handler runs. It must run after
+// JWTAuth. A nil meter disables metering, and metering errors let the request through.
+func Quota(meter *usage.Meter, logger utils.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if meter == nil {
+			c.Next()
+			return
+		}
 
+		userID := utils.Scope(c).UserID
+		subject := usage.Subject(userID)
+		status, err := meter.Track(c.Request.Context(), subject, userID)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, models.APIResponse{
-				Success: false,
-				Message: "Invalid or expired token",
-				Error:   "Authentication failed",
-			})
-			c.Abort()
+			logger.Error("Failed to record usage", "user_id", userID, "error", err)
+			c.Next()
 			return
 		}
 
-		// Extract claims and set in context
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
-		c.Set("user_username", claims.Username)
-		c.Set("user_role", claims.Role)
+		if status.Limit > 0 {
+			c.Header("X-Quota-Limit", strconv.FormatInt(status.Limit, 10))
+			c.Header("X-Quota-Remaining", strconv.FormatInt(status.Remaining(), 10))
+			c.Header("X-Quota-Reset", strconv.FormatInt(status.ResetsAt.Unix(), 10))
+		}
+		if status.Exceeded() {
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(status.ResetsAt).Seconds())+1))
+			abortWithError(c, http.StatusTooManyRequests, "quota_exceeded", fmt.Sprintf("The %s plan allows %d requests per month", status.Plan, status.Limit))
+			return
+		}
 
 		c.Next()
+
+		var bytes int64
+		if c.Request.ContentLength > 0 {
+			bytes += c.Request.ContentLength
+		}
+		if size := c.Writer.Size(); size > 0 {
+			bytes += int64(size)
+		}
+		if err := meter.AddBytes(c.Request.Context(), subject, bytes); err != nil {
+			logger.Warn("Failed to record transferred bytes", "user_id", userID, "error", err)
+		}
 	}
 }
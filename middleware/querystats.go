@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/database"
+	"go-backend-template/utils"
+)
+
+// QueryStats middleware counts the database queries run by each request and adds them to its route's
+// aggregate; requests running more than warnAt queries are logged as likely N+1 patterns (0 disables it)
+func QueryStats(stats *database.QueryStats, warnAt int, logger utils.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := utils.Scope(c).RequestID
+		ctx, queries := database.WithRequestQueries(c.Request.Context(), requestID)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		// Unmatched paths are not recorded, so scanners cannot grow the aggregate without bound
+		if c.FullPath() == "" {
+			return
+		}
+		stats.Record(c.Request.Method, c.FullPath(), queries)
+
+		if warnAt > 0 && queries.Count() > int64(warnAt) {
+			logger.Warn("Request ran many database queries, possible N+1 pattern",
+				"method", c.Request.Method,
+				"route", c.FullPath(),
+				"queries", queries.Count(),
+				"query_time", queries.Duration(),
+				"request_id", requestID,
+			)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadOnlyMode switches the API into read-only mode for maintenance windows and migrations. The switch
+// lives in memory: READ_ONLY_MODE sets it at startup, and the admin toggle changes only the instance that
+// serves the request.
+type ReadOnlyMode struct {
+	mu      sync.RWMutex
+	enabled bool
+	reason  string
+	since   time.Time
+}
+
+// NewReadOnlyMode creates a switch, on with reason when enabled
+func NewReadOnlyMode(enabled bool, reason string) *ReadOnlyMode {
+	m := &ReadOnlyMode{}
+	m.Set(enabled, reason)
+	return m
+}
+
+// Set turns read-only mode on with reason, which is shown to clients, or off
+func (m *ReadOnlyMode) Set(enabled bool, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if enabled && !m.enabled {
+		m.since = time.Now()
+	}
+	if !enabled {
+		m.since, reason = time.Time{}, ""
+	}
+	m.enabled, m.reason = enabled, reason
+}
+
+// Status returns whether read-only mode is on, its reason, and since when
+func (m *ReadOnlyMode) Status() (enabled bool, reason string, since time.Time) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled, m.reason, m.since
+}
+
+// ReadOnly middleware answers the requests that may change data, all but GET, HEAD, and OPTIONS, with
+// 503 while mode is on, except those to the route paths exempt reports
+func ReadOnly(mode *ReadOnlyMode, exempt func(route string) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		// Unknown routes keep their 404
+		enabled, reason, _ := mode.Status()
+		if route := c.FullPath(); !enabled || route == "" || exempt(route) {
+			c.Next()
+			return
+		}
+		if reason == "" {
+			reason = "The API is in read-only mode for maintenance"
+		}
+		abortWithError(c, http.StatusServiceUnavailable, "read_only_mode", reason)
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"runtime/debug"
+	"strings"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/errcodes"
+	"go-backend-template/utils"
+)
+
+// redactedHeaders carry credentials and are masked in request dumps
+var redactedHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization", "X-Api-Key", "X-Bot-Challenge", "Idempotency-Key"}
+
+// redactedParams are the query parameters carrying credentials, such as the access token of streams
+var redactedParams = []string{"access_token", "token", "code", "client_secret", "password"}
+
+// Recovery middleware recovers from panics in the handlers: it logs the panic with its stack trace under
+// the request ID and answers 500 with the request ID, so a client reporting the error points at the log
+// line. In development the response also carries the panic, and the log line a dump of the request with
+// the credentials in its headers and query redacted; the body is left out, as the handler has read it.
+func Recovery(logger utils.Logger, development bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// net/http aborts the response silently on this panic; let it
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			scope := utils.Scope(c)
+			args := []interface{}{"error", recovered, "method", c.Request.Method, "route", c.FullPath(),
+				"stack", string(debug.Stack())}
+			if development {
+				args = append(args, "request", dumpRequest(c.Request))
+			}
+			utils.LoggerFrom(c.Request.Context(), logger).Error("Panic recovered", args...)
+
+			// Nothing can be sent once the client is gone or the response has started
+			if err, ok := recovered.(error); ok && (errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)) || c.Writer.Written() {
+				c.Abort()
+				return
+			}
+
+			detail := "Something went wrong"
+			if development {
+				detail = fmt.Sprint(recovered)
+			}
+			responseUtils := &utils.ResponseUtils{}
+			response := responseUtils.ErrorResponse(localize(c, "internal_error"), detail)
+			response.Code, response.RequestID = errcodes.Internal, scope.RequestID
+			responseUtils.Respond(c, http.StatusInternalServerError, response)
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
+
+// redactParams masks the credentials among the query parameters
+func redactParams(query url.Values) {
+	for _, name := range redactedParams {
+		if query.Has(name) {
+			query.Set(name, "[REDACTED]")
+		}
+	}
+}
+
+// redactPath returns a path with its query, as gin logs it, with the credentials in the query masked; a
+// query that does not parse is left out
+func redactPath(path string) string {
+	route, rawQuery, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return route
+	}
+	redactParams(query)
+	return route + "?" + query.Encode()
+}
+
+// dumpRequest returns the request line and headers of req with credentials redacted
+func dumpRequest(req *http.Request) string {
+	sanitized := req.Clone(req.Context())
+	for _, name := range redactedHeaders {
+		if sanitized.Header.Get(name) != "" {
+			sanitized.Header.Set(name, "[REDACTED]")
+		}
+	}
+	query := sanitized.URL.Query()
+	redactParams(query)
+	sanitized.URL.RawQuery = query.Encode()
+	sanitized.RequestURI = sanitized.URL.RequestURI()
+
+	dump, err := httputil.DumpRequest(sanitized, false)
+	if err != nil {
+		return fmt.Sprintf("%s %s", req.Method, req.URL.Path)
+	}
+	return strings.TrimSpace(string(dump))
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/jwt"
+	"go-backend-template/utils"
+)
+
+// OrgHeader selects the organization a request acts for among the memberships of its token, overriding
+// the token's default org_id
+const OrgHeader = "X-Org-ID"
+
+// selectOrg sets org_id, org_role, and org_memberships from the claims and OrgHeader; it returns false
+// when the header names an organization the user does not belong to
+func selectOrg(c *gin.Context, claims *jwt.Claims) bool {
+	orgID := claims.OrgID
+	if requested := c.GetHeader(OrgHeader); requested != "" {
+		if _, ok := claims.Memberships[requested]; !ok {
+			return false
+		}
+		orgID = requested
+	}
+
+	c.Set("org_id", orgID)
+	c.Set("org_role", claims.Memberships[orgID])
+	c.Set("org_memberships", claims.Memberships)
+	return true
+}
+
+// OrgID returns the organization the request acts for, or "" when it acts for none. It requires JWTAuth.
+func OrgID(c *gin.Context) string {
+	return c.GetString("org_id")
+}
+
+// OrgRole returns the user's role in the organization the request acts for, or "" without one
+func OrgRole(c *gin.Context) string {
+	return c.GetString("org_role")
+}
+
+// IsOrgAdmin reports whether the user administers the organization the request acts for, as an admin or
+// owner
+func IsOrgAdmin(c *gin.Context) bool {
+	return jwt.OrgRoleAtLeast(OrgRole(c), jwt.OrgRoleAdmin)
+}
+
+// IsGlobalAdmin reports whether the user administers the whole deployment, whatever their organizations
+func IsGlobalAdmin(c *gin.Context) bool {
+	role := utils.Scope(c).Role
+	return role == "admin" || role == "superadmin"
+}
+
+// RequireOrgRole middleware allows only requests acting for an organization in which the user has at
+// least the minimum role (jwt.OrgRoleMember, jwt.OrgRoleAdmin, or jwt.OrgRoleOwner). Global admins get no
+// exception; combine it with IsGlobalAdmin in the handler where they should. It must run after JWTAuth.
+func RequireOrgRole(minimum string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if OrgID(c) == "" {
+			abortWithError(c, http.StatusForbidden, "organization_required", "Select an organization with the "+OrgHeader+" header")
+			return
+		}
+		if !jwt.OrgRoleAtLeast(OrgRole(c), minimum) {
+			abortWithError(c, http.StatusForbidden, "insufficient_permissions", "This resource requires the "+minimum+" role in the organization")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireOrgAdmin middleware allows only admins and owners of the organization the request acts for
+func RequireOrgAdmin() gin.HandlerFunc {
+	return RequireOrgRole(jwt.OrgRoleAdmin)
+}
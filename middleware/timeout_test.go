@@ -0,0 +1,176 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/idempotency"
+	"go-backend-template/middleware"
+	"go-backend-template/testutil"
+)
+
+// testTimeout is the deadline of the routes under test; handlers that miss it wait for it to pass
+const testTimeout = 50 * time.Millisecond
+
+// timeoutRouter serves handler at GET and POST /slow with the test deadline
+func timeoutRouter(handler gin.HandlerFunc, mw ...gin.HandlerFunc) *gin.Engine {
+	router := testutil.NewRouter()
+	router.Use(middleware.Timeout(testTimeout))
+	router.Use(mw...)
+	router.GET("/slow", handler)
+	router.POST("/slow", handler)
+	return router
+}
+
+// afterDeadline waits for the request deadline to pass
+func afterDeadline(c *gin.Context) {
+	<-c.Request.Context().Done()
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  gin.HandlerFunc
+		status   int
+		body     string
+		excluded string
+	}{
+		{
+			name: "written before the deadline",
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "on time"})
+			},
+			status: http.StatusOK,
+			body:   "on time",
+		},
+		{
+			name: "started before the deadline and finished after it",
+			handler: func(c *gin.Context) {
+				c.Status(http.StatusOK)
+				c.Writer.WriteHeaderNow()
+				afterDeadline(c)
+				c.Writer.WriteString("finished")
+			},
+			status: http.StatusOK,
+			body:   "finished",
+		},
+		{
+			name: "written after the deadline",
+			handler: func(c *gin.Context) {
+				afterDeadline(c)
+				c.JSON(http.StatusOK, gin.H{"message": "too late"})
+			},
+			status:   http.StatusRequestTimeout,
+			body:     "REQ006",
+			excluded: "too late",
+		},
+		{
+			name:    "never written",
+			handler: afterDeadline,
+			status:  http.StatusRequestTimeout,
+			body:    "REQ006",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := testutil.Serve(timeoutRouter(tt.handler), testutil.NewRequest(t, http.MethodGet, "/slow", nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.status, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("body %q does not contain %q", rec.Body.String(), tt.body)
+			}
+			if tt.excluded != "" && strings.Contains(rec.Body.String(), tt.excluded) {
+				t.Errorf("body %q contains the late write %q", rec.Body.String(), tt.excluded)
+			}
+		})
+	}
+}
+
+// TestTimeoutConcurrent serves requests that finish on either side of the deadline at once, so the race
+// detector sees the writer guard under load
+func TestTimeoutConcurrent(t *testing.T) {
+	router := timeoutRouter(func(c *gin.Context) {
+		if c.Query("late") == "true" {
+			afterDeadline(c)
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "done"})
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		late := i%2 == 0
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := testutil.Serve(router, testutil.NewRequest(t, http.MethodGet, fmt.Sprintf("/slow?late=%t", late), nil))
+			want := http.StatusOK
+			if late {
+				want = http.StatusRequestTimeout
+			}
+			if rec.Code != want {
+				t.Errorf("late=%t: status = %d, want %d", late, rec.Code, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestTimeoutReleasesIdempotencyKey checks that a request that timed out does not keep its
+// Idempotency-Key, so the client's retry runs instead of being answered 409 or replayed
+func TestTimeoutReleasesIdempotencyKey(t *testing.T) {
+	for _, late := range []struct {
+		name    string
+		handler gin.HandlerFunc
+	}{
+		{"never written", afterDeadline},
+		{"written after the deadline", func(c *gin.Context) {
+			afterDeadline(c)
+			c.JSON(http.StatusCreated, gin.H{"message": "too late"})
+		}},
+	} {
+		t.Run(late.name, func(t *testing.T) {
+			t.Parallel()
+			store := idempotency.NewMemoryStore()
+			var timedOut bool
+			var mu sync.Mutex
+			router := timeoutRouter(func(c *gin.Context) {
+				mu.Lock()
+				first := !timedOut
+				timedOut = true
+				mu.Unlock()
+				if first {
+					late.handler(c)
+					return
+				}
+				c.JSON(http.StatusCreated, gin.H{"message": "created"})
+			}, middleware.Idempotency(store, time.Hour, testutil.Logger()))
+
+			send := func() int {
+				req := testutil.NewRequest(t, http.MethodPost, "/slow", map[string]string{"name": "retry"})
+				req.Header.Set(middleware.IdempotencyKeyHeader, "key-1")
+				rec := testutil.Serve(router, req)
+				if rec.Header().Get("Idempotent-Replayed") != "" {
+					t.Errorf("response replayed: %q", rec.Body.String())
+				}
+				return rec.Code
+			}
+
+			if status := send(); status != http.StatusRequestTimeout {
+				t.Fatalf("first request: status = %d, want %d", status, http.StatusRequestTimeout)
+			}
+			if status := send(); status != http.StatusCreated {
+				t.Fatalf("retry: status = %d, want %d", status, http.StatusCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// RouteTimeouts counts the requests to one route that ran out of time
+type RouteTimeouts struct {
+	Method string
+	Route  string
+	// Timeout is the deadline of the route's requests
+	Timeout time.Duration
+	// Exceeded counts the requests whose deadline passed before the handler returned, answered 408 or
+	// with the handler's own response
+	Exceeded int64
+}
+
+// TimeoutStats aggregates the requests that ran out of time per route, so deadlines too short for a
+// route, or routes that got slow, stand out
+type TimeoutStats struct {
+	mu     sync.Mutex
+	routes map[string]*RouteTimeouts
+}
+
+// NewTimeoutStats creates an empty aggregate
+func NewTimeoutStats() *TimeoutStats {
+	return &TimeoutStats{routes: make(map[string]*RouteTimeouts)}
+}
+
+// record counts a request to the route whose deadline passed
+func (s *TimeoutStats) record(method, route string, timeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := method + " " + route
+	stats, ok := s.routes[key]
+	if !ok {
+		stats = &RouteTimeouts{Method: method, Route: route}
+		s.routes[key] = stats
+	}
+	stats.Timeout = timeout
+	stats.Exceeded++
+}
+
+// Snapshot returns the aggregates sorted by route and method
+func (s *TimeoutStats) Snapshot() []RouteTimeouts {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make([]RouteTimeouts, 0, len(s.routes))
+	for _, stats := range s.routes {
+		snapshot = append(snapshot, *stats)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Route != snapshot[j].Route {
+			return snapshot[i].Route < snapshot[j].Route
+		}
+		return snapshot[i].Method < snapshot[j].Method
+	})
+	return snapshot
+}
@@ -0,0 +1,317 @@
+// Package migrate applies versioned SQL migrations to PostgreSQL. The version is tracked in a
+// golang-migrate compatible schema_migrations table, so either tool can be used against the same database.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// lockID is the advisory lock key serializing migrations across instances (golang-migrate uses its own)
+const lockID = 7364920411
+
+var (
+	// ErrDirty means a migration failed halfway outside this tool; fix the schema and run force
+	ErrDirty = errors.New("database schema is dirty")
+	// ErrVersionMismatch means the schema is not at the latest migration built into the binary
+	ErrVersionMismatch = errors.New("database schema version does not match the binary")
+)
+
+// fileName matches golang-migrate file names such as 000001_create_users.up.sql
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrator applies migrations to a PostgreSQL database. Each migration runs with the app.user_id_type
+// setting holding POSTGRES_USER_ID_TYPE, which migrations read with current_setting.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	userIDType string
+}
+
+// New creates a migrator for the migrations in source, ordered by version
+func New(db *database.PostgresDB, source fs.FS) (*Migrator, error) {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL DB: %w", err)
+	}
+	migrations, err := Load(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: sqlDB, migrations: migrations, userIDType: db.UserIDType()}, nil
+}
+
+// Load reads the migration files at the root of source
+func Load(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+		contents, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Latest returns the version of the newest migration, or 0 without migrations
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status reports the applied version and which migrations have been applied
+func (m *Migrator) Status(ctx context.Context) (*models.MigrationStatus, error) {
+	if err := ensureTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	version, dirty, err := currentVersion(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.MigrationStatus{
+		Version:    version,
+		Dirty:      dirty,
+		Latest:     m.Latest(),
+		Migrations: make([]models.MigrationInfo, 0, len(m.migrations)),
+	}
+	for _, migration := range m.migrations {
+		status.Migrations = append(status.Migrations, models.MigrationInfo{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Version < version || (migration.Version == version && !dirty),
+		})
+	}
+	return status, nil
+}
+
+// Check returns ErrDirty or ErrVersionMismatch unless the schema is exactly at the latest migration
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w at version %d", ErrDirty, status.Version)
+	}
+	if status.Version != status.Latest {
+		return fmt.Errorf("%w: database is at %d, binary expects %d", ErrVersionMismatch, status.Version, status.Latest)
+	}
+	return nil
+}
+
+// Up applies every pending migration and returns how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *sql.Conn, version uint) error {
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the given number of applied migrations, or all of them if steps is not positive,
+// and returns how many were rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	rolledBack := 0
+	err := m.locked(ctx, func(conn *sql.Conn, version uint) error {
+		for i := len(m.migrations) - 1; i >= 0 && version > 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+			if migration.Version < version {
+				return fmt.Errorf("migration %d is not in this binary", version)
+			}
+			if steps > 0 && rolledBack == steps {
+				break
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+
+			var previous uint
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			version = previous
+			rolledBack++
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Force records version as applied and clean without running any migration; 0 marks an empty schema
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	if err := ensureTable(ctx, m.db); err != nil {
+		return err
+	}
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setVersion(ctx, tx, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// locked runs fn on a dedicated connection holding the migration lock, with the current clean version
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, version uint) error) error {
+	// Advisory locks belong to a session, so lock and unlock must use the same connection
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	version, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w at version %d", ErrDirty, version)
+	}
+	return fn(conn, version)
+}
+
+// apply runs a migration script and records the resulting version in one transaction, so a failed
+// migration leaves the schema and version untouched
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script string, version uint) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('app.user_id_type', $1, true)", m.userIDType); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if err := setVersion(ctx, tx, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// execer is satisfied by *sql.DB, *sql.Conn, and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// ensureTable creates the version table with golang-migrate's layout
+func ensureTable(ctx context.Context, db execer) error {
+	_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// currentVersion reads the recorded version; an empty table means no migration has been applied
+func currentVersion(ctx context.Context, db execer) (uint, bool, error) {
+	var version int64
+	var dirty bool
+	err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version < 0 {
+		return 0, dirty, nil
+	}
+	return uint(version), dirty, nil
+}
+
+// setVersion replaces the recorded version the way golang-migrate does: a single row, none for version 0
+func setVersion(ctx context.Context, tx *sql.Tx, version uint) error {
+	if _, err := tx.ExecContext(ctx, "TRUNCATE schema_migrations"); err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", int64(version))
+	return err
+}
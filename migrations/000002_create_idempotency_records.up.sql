@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS idempotency_records (
+    key          text PRIMARY KEY,
+    fingerprint  text NOT NULL,
+    completed    boolean,
+    status_code  bigint,
+    content_type text,
+    body         bytea,
+    created_at   timestamptz,
+    expires_at   timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_records_expires_at ON idempotency_records (expires_at);
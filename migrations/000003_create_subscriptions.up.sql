@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS subscriptions (
+    user_id              text PRIMARY KEY,
+    customer_id          text,
+    subscription_id      text,
+    plan_id              text,
+    status               text,
+    current_period_end   timestamptz,
+    cancel_at_period_end boolean,
+    updated_at           timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_customer_id ON subscriptions (customer_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS usage_records (
+    subject    text,
+    period     text,
+    requests   bigint,
+    bytes      bigint,
+    updated_at timestamptz,
+    PRIMARY KEY (subject, period)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS posts (
+    id         bigserial PRIMARY KEY,
+    owner_id   bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title      text NOT NULL,
+    body       text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_posts_owner_id_created_at ON posts (owner_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts (deleted_at);
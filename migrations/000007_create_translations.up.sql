@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS translations (
+    language   varchar(35),
+    key        varchar(200),
+    text       text NOT NULL,
+    updated_at timestamptz,
+    PRIMARY KEY (language, key)
+);
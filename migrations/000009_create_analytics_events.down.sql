@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS analytics_events;
+
+ALTER TABLE users DROP COLUMN IF EXISTS analytics_opt_out;
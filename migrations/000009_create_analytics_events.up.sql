@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS analytics_opt_out boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS analytics_events (
+    id          varchar(36) PRIMARY KEY,
+    name        varchar(100) NOT NULL,
+    user_id     varchar(64),
+    properties  jsonb,
+    occurred_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_events_name ON analytics_events (name);
+CREATE INDEX IF NOT EXISTS idx_analytics_events_user_id ON analytics_events (user_id);
+CREATE INDEX IF NOT EXISTS idx_analytics_events_occurred_at ON analytics_events (occurred_at);
@@ -0,0 +1,8 @@
+-- Words of the name, username, and email (both whole and split at punctuation) for full-text search.
+-- The simple configuration neither stems nor drops stop words, which suits names.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(first_name, '') || ' ' || coalesce(last_name, '') || ' ' || username), 'A') ||
+        setweight(to_tsvector('simple', email || ' ' || translate(email, '@.+_-', '     ')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN (search_vector);
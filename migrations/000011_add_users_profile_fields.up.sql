@@ -0,0 +1,7 @@
+-- Optional profile fields; empty strings and NULL mean unset
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS avatar_url text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS bio text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS phone varchar(16) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS birthday date,
+    ADD COLUMN IF NOT EXISTS address jsonb;
@@ -0,0 +1,2 @@
+-- Custom attributes defined by USER_METADATA_SCHEMA
+ALTER TABLE users ADD COLUMN IF NOT EXISTS metadata jsonb;
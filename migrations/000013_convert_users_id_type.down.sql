@@ -0,0 +1,34 @@
+-- Switches uuid users.id back to bigserial, numbering users in the order they were created, and
+-- rewrites the references the up migration rewrote; bigint IDs are left unchanged
+DO $$
+BEGIN
+    IF (
+        SELECT data_type FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'id'
+    ) <> 'uuid' THEN
+        RETURN;
+    END IF;
+
+    ALTER TABLE users ADD COLUMN new_id bigint;
+    UPDATE users SET new_id = numbered.n
+    FROM (SELECT id, row_number() OVER (ORDER BY created_at, id) AS n FROM users) AS numbered
+    WHERE users.id = numbered.id;
+    ALTER TABLE posts ADD COLUMN new_owner_id bigint;
+    UPDATE posts SET new_owner_id = users.new_id FROM users WHERE posts.owner_id = users.id;
+    UPDATE subscriptions SET user_id = users.new_id::text FROM users WHERE subscriptions.user_id = users.id::text;
+    UPDATE analytics_events SET user_id = users.new_id::text FROM users WHERE analytics_events.user_id = users.id::text;
+
+    ALTER TABLE posts DROP COLUMN owner_id;
+    ALTER TABLE posts RENAME COLUMN new_owner_id TO owner_id;
+    ALTER TABLE posts ALTER COLUMN owner_id SET NOT NULL;
+    ALTER TABLE users DROP COLUMN id;
+    ALTER TABLE users RENAME COLUMN new_id TO id;
+    ALTER TABLE users ALTER COLUMN id SET NOT NULL;
+    ALTER TABLE users ADD PRIMARY KEY (id);
+    CREATE SEQUENCE users_id_seq OWNED BY users.id;
+    ALTER TABLE users ALTER COLUMN id SET DEFAULT nextval('users_id_seq');
+    PERFORM setval('users_id_seq', (SELECT coalesce(max(id), 0) + 1 FROM users), false);
+    ALTER TABLE posts ADD FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE;
+    CREATE INDEX idx_posts_owner_id_created_at ON posts (owner_id, created_at);
+END
+$$;
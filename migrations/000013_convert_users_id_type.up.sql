@@ -0,0 +1,29 @@
+-- Switches users.id to random UUIDs when the migrator runs with POSTGRES_USER_ID_TYPE=uuid, which it
+-- passes as the app.user_id_type setting; otherwise nothing changes. Existing users get new IDs, which
+-- posts, subscriptions, and analytics events are rewritten to, so tokens issued before no longer match.
+DO $$
+BEGIN
+    IF coalesce(current_setting('app.user_id_type', true), '') <> 'uuid' OR (
+        SELECT data_type FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'id'
+    ) = 'uuid' THEN
+        RETURN;
+    END IF;
+
+    ALTER TABLE users ADD COLUMN new_id uuid NOT NULL DEFAULT gen_random_uuid();
+    ALTER TABLE posts ADD COLUMN new_owner_id uuid;
+    UPDATE posts SET new_owner_id = users.new_id FROM users WHERE posts.owner_id = users.id;
+    UPDATE subscriptions SET user_id = users.new_id::text FROM users WHERE subscriptions.user_id = users.id::text;
+    UPDATE analytics_events SET user_id = users.new_id::text FROM users WHERE analytics_events.user_id = users.id::text;
+
+    -- Dropping the old columns drops the foreign key, the posts index, and the users_id_seq sequence
+    ALTER TABLE posts DROP COLUMN owner_id;
+    ALTER TABLE posts RENAME COLUMN new_owner_id TO owner_id;
+    ALTER TABLE posts ALTER COLUMN owner_id SET NOT NULL;
+    ALTER TABLE users DROP COLUMN id;
+    ALTER TABLE users RENAME COLUMN new_id TO id;
+    ALTER TABLE users ADD PRIMARY KEY (id);
+    ALTER TABLE posts ADD FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE;
+    CREATE INDEX idx_posts_owner_id_created_at ON posts (owner_id, created_at);
+END
+$$;
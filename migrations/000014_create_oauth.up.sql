@@ -0,0 +1,31 @@
+-- OpenID Connect provider mode: registered clients, pending authorization codes, and user consents
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id            varchar(64) PRIMARY KEY,
+    name          text NOT NULL,
+    secret_hash   text,
+    redirect_uris jsonb NOT NULL,
+    scopes        jsonb,
+    trusted       boolean NOT NULL DEFAULT false,
+    created_at    timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS oauth_codes (
+    code_hash             varchar(64) PRIMARY KEY,
+    client_id             varchar(64) NOT NULL REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    user_id               text NOT NULL,
+    redirect_uri          text NOT NULL,
+    scope                 text NOT NULL,
+    nonce                 text,
+    code_challenge        text,
+    code_challenge_method varchar(10),
+    expires_at            timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_oauth_codes_expires_at ON oauth_codes (expires_at);
+
+CREATE TABLE IF NOT EXISTS oauth_consents (
+    user_id    varchar(64),
+    client_id  varchar(64) REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    scopes     jsonb NOT NULL,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id, client_id)
+);
@@ -0,0 +1,7 @@
+-- Access tokens revoked before they expire; rows are only needed until the token would have expired
+CREATE TABLE IF NOT EXISTS oauth_revocations (
+    token_id   varchar(64) PRIMARY KEY,
+    client_id  varchar(64) NOT NULL,
+    expires_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_oauth_revocations_expires_at ON oauth_revocations (expires_at);
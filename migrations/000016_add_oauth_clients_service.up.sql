@@ -0,0 +1,2 @@
+-- Service clients authenticate with client credentials to act for themselves rather than for a user
+ALTER TABLE oauth_clients ADD COLUMN IF NOT EXISTS service boolean NOT NULL DEFAULT false;
@@ -0,0 +1,13 @@
+-- Device authorization grant: codes shown on headless devices, approved by the user on another screen
+CREATE TABLE IF NOT EXISTS oauth_device_codes (
+    device_code_hash varchar(64) PRIMARY KEY,
+    user_code        varchar(16) NOT NULL,
+    client_id        varchar(64) NOT NULL REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    scope            text NOT NULL,
+    status           varchar(16) NOT NULL DEFAULT 'pending',
+    user_id          text,
+    last_polled_at   timestamptz,
+    expires_at       timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oauth_device_codes_user_code ON oauth_device_codes (user_code);
+CREATE INDEX IF NOT EXISTS idx_oauth_device_codes_expires_at ON oauth_device_codes (expires_at);
@@ -0,0 +1,16 @@
+-- Sign-ins kept alive by refresh tokens; only the hash of each session's current refresh token is stored
+CREATE TABLE IF NOT EXISTS sessions (
+    id             varchar(64) PRIMARY KEY,
+    user_id        varchar(64) NOT NULL,
+    token_hash     varchar(64) NOT NULL,
+    remember_me    boolean     NOT NULL DEFAULT false,
+    user_agent     text        NOT NULL DEFAULT '',
+    ip_address     varchar(64) NOT NULL DEFAULT '',
+    created_at     timestamptz NOT NULL,
+    last_used_at   timestamptz NOT NULL,
+    expires_at     timestamptz NOT NULL,
+    max_expires_at timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_token_hash ON sessions (token_hash);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions (expires_at);
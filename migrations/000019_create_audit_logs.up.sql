@@ -0,0 +1,37 @@
+-- Audit log of the admin-only endpoints; rows are append-only, so the trigger refuses changes and deletes
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id           varchar(36) PRIMARY KEY,
+    seq          bigint,
+    actor_id     text NOT NULL,
+    actor_role   text NOT NULL,
+    method       varchar(16) NOT NULL,
+    route        text NOT NULL,
+    path         text NOT NULL,
+    status       integer NOT NULL,
+    client_ip    text,
+    request_id   text,
+    payload_hash varchar(64),
+    prev_hash    varchar(64),
+    hash         varchar(64) NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+-- Chained entries number the chain; a second entry claiming the same number loses the race and retries
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_logs_seq ON audit_logs (seq);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_route ON audit_logs (route);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+CREATE TRIGGER audit_logs_append_only
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+DROP TRIGGER IF EXISTS audit_logs_no_truncate ON audit_logs;
+CREATE TRIGGER audit_logs_no_truncate
+    BEFORE TRUNCATE ON audit_logs
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
@@ -0,0 +1,10 @@
+-- Let the retention job remove old audit log entries: a DELETE passes the trigger only in a transaction
+-- that ran SET LOCAL audit_logs.retention = 'on'. Changes and TRUNCATE are still refused.
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND current_setting('audit_logs.retention', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,18 @@
+-- Webhook deliveries received from providers, kept to process each delivery once and to replay dead letters
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id           varchar(36) PRIMARY KEY,
+    provider     text NOT NULL,
+    event_id     text NOT NULL,
+    type         text,
+    status       varchar(16) NOT NULL,
+    attempts     integer NOT NULL DEFAULT 0,
+    last_error   text,
+    payload      text NOT NULL,
+    received_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now(),
+    processed_at timestamptz
+);
+-- A retried delivery finds the row of its first delivery instead of inserting a second one
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_events_delivery ON webhook_events (provider, event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_events_status ON webhook_events (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_events_received_at ON webhook_events (received_at);
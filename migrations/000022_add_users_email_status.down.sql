@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_email_status;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_status_at,
+    DROP COLUMN IF EXISTS email_status;
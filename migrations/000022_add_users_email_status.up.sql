@@ -0,0 +1,6 @@
+-- Addresses the email provider reported as bounced or complaining; no more email is sent to them
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_status varchar(16) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS email_status_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_users_email_status ON users (email_status) WHERE email_status <> '';
@@ -0,0 +1,21 @@
+-- Long-running requests accepted with 202 and run by the background workers; clients poll them for their
+-- progress and result
+CREATE TABLE IF NOT EXISTS operations (
+    id          varchar(36) PRIMARY KEY,
+    kind        text NOT NULL,
+    status      varchar(16) NOT NULL,
+    owner_id    text NOT NULL,
+    params      jsonb,
+    attempts    integer NOT NULL DEFAULT 0,
+    done        integer NOT NULL DEFAULT 0,
+    total       integer NOT NULL DEFAULT 0,
+    result      jsonb,
+    error       text,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now(),
+    finished_at timestamptz
+);
+-- Workers claim the oldest queued operation, and stale running ones by their last update
+CREATE INDEX IF NOT EXISTS idx_operations_status ON operations (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_operations_updated_at ON operations (updated_at);
+CREATE INDEX IF NOT EXISTS idx_operations_finished_at ON operations (finished_at);
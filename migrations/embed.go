@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned PostgreSQL schema migrations. Files follow the golang-migrate
+// layout ({version}_{name}.up.sql and .down.sql), so the migrate CLI can apply them as well.
+package migrations
+
+import "embed"
+
+// FS holds the migration files compiled into the binary
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AnalyticsEvent is a product event, such as a signup or the use of a feature, recorded by the analytics
+// package (PostgreSQL and MongoDB sinks)
+type AnalyticsEvent struct {
+	// ID identifies the event to sinks that drop duplicates
+	ID     string `json:"id" gorm:"primaryKey;size:36" bson:"_id"`
+	Name   string `json:"name" gorm:"size:100;not null;index" bson:"name"`
+	UserID string `json:"user_id,omitempty" gorm:"size:64;index" bson:"user_id,omitempty"`
+	// Properties describe the event, such as the feature used
+	Properties map[string]interface{} `json:"properties,omitempty" gorm:"serializer:json;type:jsonb" bson:"properties,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at" gorm:"not null;index" bson:"occurred_at"`
+}
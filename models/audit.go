@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// AuditLog records one call to an admin-only endpoint: who made it, what it did, when, and a hash of
+// its payload. Entries are never changed. With hash chaining, an entry also has the next number of the
+// chain and the hash of the entry before it, so removing or altering an entry breaks the chain.
+type AuditLog struct {
+	ID  string `gorm:"primaryKey;size:36" bson:"_id" json:"id" example:"01912f6e-8a3c-7b2e-9c41-5d2f3a6b7c8d"`
+	Seq *int64 `gorm:"uniqueIndex" bson:"seq,omitempty" json:"seq,omitempty" example:"42"`
+	// ActorID is the admin who made the call
+	ActorID   string `gorm:"index;not null" bson:"actor_id" json:"actor_id" example:"1"`
+	ActorRole string `gorm:"not null" bson:"actor_role" json:"actor_role" example:"admin"`
+	Method    string `gorm:"not null" bson:"method" json:"method" example:"DELETE"`
+	// Route is the route pattern, as in /api/v1/admin/users/:id
+	Route string `gorm:"index;not null" bson:"route" json:"route" example:"/api/v1/admin/users/:id"`
+	Path  string `gorm:"not null" bson:"path" json:"path" example:"/api/v1/admin/users/7"`
+	// Status is the HTTP status of the response
+	Status    int    `gorm:"not null" bson:"status" json:"status" example:"200"`
+	ClientIP  string `bson:"client_ip,omitempty" json:"client_ip,omitempty" example:"203.0.113.7"`
+	RequestID string `bson:"request_id,omitempty" json:"request_id,omitempty" example:"6f1c2d3e-4a5b-4c6d-8e7f-901a2b3c4d5e"`
+	// PayloadHash is the SHA-256 of the request body, empty without one
+	PayloadHash string `bson:"payload_hash,omitempty" json:"payload_hash,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	// PrevHash is the hash of the previous entry of the chain, empty for the first one
+	PrevHash string `bson:"prev_hash,omitempty" json:"prev_hash,omitempty" example:"2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"`
+	// Hash is the SHA-256 of the entry, including PrevHash
+	Hash      string    `gorm:"not null" bson:"hash" json:"hash" example:"fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"`
+	CreatedAt time.Time `gorm:"index" bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// ListAuditLogsQuery represents the query parameters of the audit log listing
+type ListAuditLogsQuery struct {
+	Page     int        `form:"page,default=1" binding:"min=1" example:"1"`
+	PageSize int        `form:"page_size,default=10" binding:"min=1,max=100" example:"10"`
+	ActorID  string     `form:"actor_id" example:"1"`
+	Method   string     `form:"method" binding:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE" example:"DELETE"`
+	Route    string     `form:"route" example:"/api/v1/admin/users/:id"`
+	From     *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-01T00:00:00Z"`
+	To       *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-02-01T00:00:00Z"`
+}
+
+// ExportAuditLogsQuery represents the query parameters of the audit log export
+type ExportAuditLogsQuery struct {
+	Format  string     `form:"format,default=ndjson" binding:"oneof=ndjson csv" example:"csv"`
+	ActorID string     `form:"actor_id" example:"1"`
+	Method  string     `form:"method" binding:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE" example:"DELETE"`
+	Route   string     `form:"route" example:"/api/v1/admin/users/:id"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-01T00:00:00Z"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-02-01T00:00:00Z"`
+}
+
+// AuditVerification is the result of checking the audit log for tampering
+type AuditVerification struct {
+	// Valid is false when an entry does not match its hash or the chain has a gap or a broken link
+	Valid bool `json:"valid" example:"true"`
+	// Checked is the number of entries checked
+	Checked int64 `json:"checked" example:"1280"`
+	// BrokenAt is the ID of the first entry that failed the check
+	BrokenAt string `json:"broken_at,omitempty" example:"01912f6e-8a3c-7b2e-9c41-5d2f3a6b7c8d"`
+	// Reason tells why the entry failed
+	Reason string `json:"reason,omitempty" example:"the entry does not match its hash"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// BackupResponse is an encrypted archive of every user, soft-deleted ones and password hashes included
+type BackupResponse struct {
+	// Archive is the archive encrypted with BACKUP_ENCRYPTION_KEY, base64-encoded
+	Archive   []byte    `json:"archive" swaggertype:"string" format:"byte" example:"R09CVEJBSzE="`
+	Users     int       `json:"users" example:"42"`
+	Source    string    `json:"source" example:"postgres"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// RestoreBackupRequest carries an archive made by GET /admin/backup or the backup command
+type RestoreBackupRequest struct {
+	// Archive is the encrypted archive, base64-encoded
+	Archive []byte `json:"archive" binding:"required" swaggertype:"string" format:"byte" example:"R09CVEJBSzE="`
+}
+
+// RestoreBackupResponse reports what a restore wrote
+type RestoreBackupResponse struct {
+	// Users is the number of users in the archive
+	Users   int `json:"users" example:"42"`
+	Created int `json:"created" example:"3"`
+	Updated int `json:"updated" example:"39"`
+	// CreatedAt is when the archive was made
+	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
@@ -0,0 +1,51 @@
+package models
+
+// Info maps a PostgreSQL user to its public representation
+func (u User) Info() UserInfo {
+	return UserInfo{
+		ID:              u.ID,
+		Email:           u.Email,
+		Username:        u.Username,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		Role:            u.Role,
+		IsActive:        u.IsActive,
+		Locale:          u.Locale,
+		AnalyticsOptOut: u.AnalyticsOptOut,
+		AvatarURL:       u.AvatarURL,
+		Bio:             u.Bio,
+		Phone:           u.Phone,
+		Birthday:        u.Birthday,
+		Address:         u.Address,
+		Metadata:        u.Metadata,
+		EmailStatus:     u.EmailStatus,
+		EmailStatusAt:   u.EmailStatusAt,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+}
+
+// Info maps a MongoDB user to its public representation
+func (u UserMongo) Info() UserInfo {
+	return UserInfo{
+		ID:              u.ID.Hex(),
+		Email:           u.Email,
+		Username:        u.Username,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		Role:            u.Role,
+		IsActive:        u.IsActive,
+		Locale:          u.Locale,
+		AnalyticsOptOut: u.AnalyticsOptOut,
+		AvatarURL:       u.AvatarURL,
+		Bio:             u.Bio,
+		Phone:           u.Phone,
+		Birthday:        u.Birthday,
+		Address:         u.Address,
+		Metadata:        u.Metadata,
+		EmailStatus:     u.EmailStatus,
+		EmailStatusAt:   u.EmailStatusAt,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+}
@@ -7,75 +7,190 @@ import (
 	"gorm.io/gorm"
 )
 
-// User represents user model for PostgreSQL
+// Email statuses of a user whose address no longer receives mail
+const (
+	// EmailBounced is an address mail to which bounced permanently
+	EmailBounced = "bounced"
+	// EmailComplained is an address whose owner reported mail from the API as spam
+	EmailComplained = "complained"
+)
+
+// User represents user model for PostgreSQL. The database assigns ID: a number, or a UUID with
+// POSTGRES_USER_ID_TYPE=uuid; the integer type only applies to the SQLite schema.
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Username  string         `json:"username" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"-" gorm:"not null"`
-	FirstName string         `json:"first_name"`
-	LastName  string         `json:"last_name"`
-	Role      string         `json:"role" gorm:"default:user"`
-	IsActive  bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              string         `json:"id" gorm:"primaryKey;type:integer;default:(-)"`
+	Email           string         `json:"email" gorm:"uniqueIndex;not null"`
+	Username        string         `json:"username" gorm:"uniqueIndex;not null"`
+	Password        string         `json:"-" gorm:"not null"`
+	FirstName       string         `json:"first_name"`
+	LastName        string         `json:"last_name"`
+	Role            string         `json:"role" gorm:"default:user"`
+	IsActive        bool           `json:"is_active" gorm:"default:true"`
+	Locale          string         `json:"locale" gorm:"size:35;not null;default:''"`
+	AnalyticsOptOut bool           `json:"analytics_opt_out" gorm:"not null;default:false"`
+	AvatarURL       string         `json:"avatar_url" gorm:"not null;default:''"`
+	Bio             string         `json:"bio" gorm:"not null;default:''"`
+	Phone           string         `json:"phone" gorm:"size:16;not null;default:''"`
+	Birthday        Date           `json:"birthday" gorm:"type:date"`
+	Address         *Address       `json:"address" gorm:"serializer:json;type:jsonb"`
+	Metadata        Metadata       `json:"metadata" gorm:"serializer:json;type:jsonb"`
+	EmailStatus     string         `json:"email_status" gorm:"size:16;not null;default:''"`
+	EmailStatusAt   *time.Time     `json:"email_status_at"`
+	LastLoginAt     *time.Time     `json:"-" gorm:"index"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // UserMongo represents user model for MongoDB
 type UserMongo struct {
-	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Email     string             `json:"email" bson:"email"`
-	Username  string             `json:"username" bson:"username"`
-	Password  string             `json:"-" bson:"password"`
-	FirstName string             `json:"first_name" bson:"first_name"`
-	LastName  string             `json:"last_name" bson:"last_name"`
-	Role      string             `json:"role" bson:"role"`
-	IsActive  bool               `json:"is_active" bson:"is_active"`
-	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Email           string             `json:"email" bson:"email"`
+	Username        string             `json:"username" bson:"username"`
+	Password        string             `json:"-" bson:"password"`
+	FirstName       string             `json:"first_name" bson:"first_name"`
+	LastName        string             `json:"last_name" bson:"last_name"`
+	Role            string             `json:"role" bson:"role"`
+	IsActive        bool               `json:"is_active" bson:"is_active"`
+	Locale          string             `json:"locale" bson:"locale,omitempty"`
+	AnalyticsOptOut bool               `json:"analytics_opt_out" bson:"analytics_opt_out"`
+	AvatarURL       string             `json:"avatar_url" bson:"avatar_url,omitempty"`
+	Bio             string             `json:"bio" bson:"bio,omitempty"`
+	Phone           string             `json:"phone" bson:"phone,omitempty"`
+	Birthday        Date               `json:"birthday" bson:"birthday,omitempty"`
+	Address         *Address           `json:"address" bson:"address,omitempty"`
+	Metadata        Metadata           `json:"metadata" bson:"metadata,omitempty"`
+	EmailStatus     string             `json:"email_status" bson:"email_status,omitempty"`
+	EmailStatusAt   *time.Time         `json:"email_status_at" bson:"email_status_at,omitempty"`
+	LastLoginAt     *time.Time         `json:"-" bson:"last_login_at,omitempty"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
+	DeletedAt       *time.Time         `json:"-" bson:"deleted_at,omitempty"`
 }
 
 // LoginRequest represents login request payload
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"user@example.com"`
 	Password string `json:"password" binding:"required,min=6" example:"password123"`
+	// RememberMe starts a session with the longer REMEMBER_ME_ lifetimes instead of the SESSION_ ones
+	RememberMe bool `json:"remember_me" example:"true"`
 }
 
 // RegisterRequest represents registration request payload
 type RegisterRequest struct {
-	Email     string `json:"email" binding:"required,email" example:"user@example.com"`
-	Username  string `json:"username" binding:"required,min=3" example:"username"`
-	Password  string `json:"password" binding:"required,min=6" example:"password123"`
+	Email     string `json:"email" binding:"required,email,notdisposable" example:"user@example.com"`
+	Username  string `json:"username" binding:"required,username" example:"username"`
+	Password  string `json:"password" binding:"required,strongpassword" example:"Password123"`
 	FirstName string `json:"first_name" binding:"required" example:"John"`
 	LastName  string `json:"last_name" binding:"required" example:"Doe"`
+	// Locale is the preferred language, used for responses when a request has no Accept-Language
+	Locale string `json:"locale,omitempty" binding:"omitempty,locale" example:"de"`
+	// AnalyticsOptOut excludes the user from product analytics, including the signup event
+	AnalyticsOptOut bool `json:"analytics_opt_out,omitempty" example:"false"`
+}
+
+// SetupRequest creates the first superadmin with the one-time token logged at startup
+type SetupRequest struct {
+	Token string `json:"token" binding:"required" example:"4f1c2a9e0b7d..."`
+	RegisterRequest
 }
 
 // UpdateUserRequest represents user update request payload
 type UpdateUserRequest struct {
 	FirstName string `json:"first_name" example:"John"`
 	LastName  string `json:"last_name" example:"Doe"`
-	Email     string `json:"email" binding:"omitempty,email" example:"user@example.com"`
+	Email     string `json:"email" binding:"omitempty,email,notdisposable" example:"user@example.com"`
+	Locale    string `json:"locale" binding:"omitempty,locale" example:"de"`
+	// AnalyticsOptOut changes the analytics preference when set
+	AnalyticsOptOut *bool `json:"analytics_opt_out,omitempty" example:"true"`
+	// The profile fields below change when set; an empty value (an empty object for Address) clears them
+	AvatarURL *string  `json:"avatar_url,omitempty" binding:"omitempty,max=2048,weburl" example:"https://example.com/avatars/john.png"`
+	Bio       *string  `json:"bio,omitempty" binding:"omitempty,max=500" example:"Backend developer"`
+	Phone     *string  `json:"phone,omitempty" binding:"omitempty,phone" example:"+14155550123"`
+	Birthday  *Date    `json:"birthday,omitempty" binding:"omitempty,birthday" swaggertype:"string" example:"1990-05-17"`
+	Address   *Address `json:"address,omitempty"`
+	// Metadata sets the custom attributes defined by USER_METADATA_SCHEMA; a null value removes one
+	Metadata Metadata `json:"metadata,omitempty" swaggertype:"object"`
+}
+
+// ChangeRoleRequest represents a role change made by a superadmin
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin superadmin" example:"admin"`
+	// Reason is recorded in the security log
+	Reason string `json:"reason" binding:"required,min=3,max=500" example:"Joined the support team"`
+}
+
+// BulkUsersRequest represents an admin action applied to the users listed in IDs or selected by Filter
+type BulkUsersRequest struct {
+	Action string   `json:"action" binding:"required,oneof=activate deactivate delete role" example:"deactivate"`
+	IDs    []string `json:"ids,omitempty" binding:"omitempty,max=1000" example:"6650f1a2b3c4d5e6f7a8b9c0,6650f1a2b3c4d5e6f7a8b9c1"`
+	// Filter holds the search and filter query parameters of GET /users, such as role or created_before
+	Filter map[string]string `json:"filter,omitempty" example:"role:user,created_before:2024-01-01"`
+	// Role is the new role of the role action
+	Role string `json:"role,omitempty" binding:"omitempty,oneof=user admin superadmin" example:"admin"`
+	// Reason is recorded in the security log with each role change
+	Reason string `json:"reason,omitempty" binding:"omitempty,min=3,max=500" example:"Joined the support team"`
+}
+
+// BulkUsersResponse reports a bulk action user by user
+type BulkUsersResponse struct {
+	Action    string           `json:"action" example:"deactivate"`
+	Matched   int              `json:"matched" example:"2"`
+	Succeeded int              `json:"succeeded" example:"1"`
+	Failed    int              `json:"failed" example:"1"`
+	Results   []BulkUserResult `json:"results"`
+}
+
+// BulkUserResult is the outcome of a bulk action for one user
+type BulkUserResult struct {
+	ID     string `json:"id" example:"6650f1a2b3c4d5e6f7a8b9c0"`
+	Status string `json:"status" enums:"succeeded,failed" example:"failed"`
+	// Error says why the action failed for the user
+	Error string `json:"error,omitempty" enums:"invalid_id,not_found,self,forbidden,last_superadmin,canceled,internal_error" example:"last_superadmin"`
 }
 
 // AuthResponse represents authentication response
 type AuthResponse struct {
-	Token     string    `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
+	Token     string    `json:"token,omitempty" example:"eyJhbGciOiJIUzI1NiIs..."`
 	User      UserInfo  `json:"user"`
 	ExpiresAt time.Time `json:"expires_at" example:"2024-01-01T00:00:00Z"`
+	// RefreshToken gets new tokens from POST /auth/refresh until RefreshExpiresAt; omitted without sessions
+	RefreshToken     string     `json:"refresh_token,omitempty" example:"Rk3m9vQ2x8L1pZ7wYc4tN0bH5sJ6dA2eG9uK1fV3oXi"`
+	RefreshExpiresAt *time.Time `json:"refresh_expires_at,omitempty" example:"2024-01-31T00:00:00Z"`
+	// SessionID identifies the session in GET /auth/sessions
+	SessionID string `json:"session_id,omitempty" example:"s_2b7f0c1e9a4d4f3b8e6a"`
+}
+
+// BotChallengeResponse is a challenge token for the bot-guarded auth routes
+type BotChallengeResponse struct {
+	// Token goes in the X-Bot-Challenge header of one login or registration request
+	Token     string    `json:"token" example:"AAABjQ3k2mCxR7yP0wq1dA6mZ2Jc9f1e.Yk9xYl8tQ2h2..."`
+	ExpiresAt time.Time `json:"expires_at" example:"2024-01-01T00:10:00Z"`
 }
 
 // UserInfo represents public user information
 type UserInfo struct {
-	ID        interface{} `json:"id"`
-	Email     string      `json:"email" example:"user@example.com"`
-	Username  string      `json:"username" example:"username"`
-	FirstName string      `json:"first_name" example:"John"`
-	LastName  string      `json:"last_name" example:"Doe"`
-	Role      string      `json:"role" example:"user"`
-	IsActive  bool        `json:"is_active" example:"true"`
-	CreatedAt time.Time   `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt time.Time   `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	// ID is a decimal number or a UUID on PostgreSQL and a hex ObjectID on MongoDB, always a string
+	ID              string   `json:"id" example:"42"`
+	Email           string   `json:"email" example:"user@example.com"`
+	Username        string   `json:"username" example:"username"`
+	FirstName       string   `json:"first_name" example:"John"`
+	LastName        string   `json:"last_name" example:"Doe"`
+	Role            string   `json:"role" example:"user"`
+	IsActive        bool     `json:"is_active" example:"true"`
+	Locale          string   `json:"locale,omitempty" example:"de"`
+	AnalyticsOptOut bool     `json:"analytics_opt_out" example:"false"`
+	AvatarURL       string   `json:"avatar_url,omitempty" example:"https://example.com/avatars/john.png"`
+	Bio             string   `json:"bio,omitempty" example:"Backend developer"`
+	Phone           string   `json:"phone,omitempty" example:"+14155550123"`
+	Birthday        Date     `json:"birthday,omitempty" swaggertype:"string" example:"1990-05-17"`
+	Address         *Address `json:"address,omitempty"`
+	Metadata        Metadata `json:"metadata,omitempty" swaggertype:"object"`
+	// EmailStatus is set once mail to the address bounced or was reported as spam; no email is sent to it until the email changes
+	EmailStatus   string     `json:"email_status,omitempty" enums:"bounced,complained" example:"bounced"`
+	EmailStatusAt *time.Time `json:"email_status_at,omitempty" example:"2024-01-01T00:00:00Z"`
+	CreatedAt     time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt     time.Time  `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 }
 
 // APIResponse represents standard API response
@@ -84,14 +199,48 @@ type APIResponse struct {
 	Message string      `json:"message" example:"Operation successful"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty" example:"Error message"`
+	// Code identifies the error for clients to branch on; the codes are listed in the errcodes package
+	Code   string       `json:"code,omitempty" example:"USER001"`
+	Errors []FieldError `json:"errors,omitempty"`
+	// RequestID identifies the request in the logs; it is set on unexpected server errors and on requests
+	// that match no route
+	RequestID string `json:"request_id,omitempty" example:"3f1c9b1e-7a8d-4c39-9d51-2f0c1e6d8a77"`
+}
+
+// ProblemDetails represents an RFC 7807 problem+json error document
+type ProblemDetails struct {
+	Type      string       `json:"type" example:"about:blank"`
+	Title     string       `json:"title" example:"Validation error"`
+	Status    int          `json:"status" example:"400"`
+	Detail    string       `json:"detail,omitempty" example:"One or more fields are invalid"`
+	Instance  string       `json:"instance,omitempty" example:"/api/v1/auth/register"`
+	Code      string       `json:"code,omitempty" example:"REQ002"`
+	RequestID string       `json:"request_id,omitempty" example:"3f1c9b1e-7a8d-4c39-9d51-2f0c1e6d8a77"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError represents a single field validation failure
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Rule    string `json:"rule" example:"required"`
+	Message string `json:"message" example:"email is required"`
 }
 
 // HealthResponse represents health check response
 type HealthResponse struct {
-	Status    string            `json:"status" example:"healthy"`
-	Timestamp time.Time         `json:"timestamp" example:"2024-01-01T00:00:00Z"`
-	Services  map[string]string `json:"services"`
-	Version   string            `json:"version" example:"1.0.0"`
+	Status    string                   `json:"status" example:"healthy" enums:"healthy,degraded,unhealthy"`
+	Timestamp time.Time                `json:"timestamp" example:"2024-01-01T00:00:00Z"`
+	Services  map[string]string        `json:"services"`
+	Checks    map[string]ServiceHealth `json:"checks"`
+	Version   string                   `json:"version" example:"1.0.0"`
+}
+
+// ServiceHealth represents the result of one dependency's health check
+type ServiceHealth struct {
+	Status      string     `json:"status" example:"healthy" enums:"healthy,degraded,unhealthy"`
+	Critical    bool       `json:"critical" example:"true"`
+	LatencyMs   float64    `json:"latency_ms" example:"1.7"`
+	LastSuccess *time.Time `json:"last_success,omitempty" example:"2024-01-01T00:00:00Z"`
 }
 
 // PaginationQuery represents pagination query parameters
@@ -100,6 +249,16 @@ type PaginationQuery struct {
 	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100" example:"10"`
 	Sort     string `form:"sort" example:"created_at:desc"`
 	Search   string `form:"search" example:"john"`
+	Fields   string `form:"fields" example:"id,email,username"`
+	Cursor   string `form:"cursor" example:"eyJzIjoiY3JlYXRlZF9hdDpkZXNjIn0"`
+}
+
+// ExportUsersQuery represents the query parameters of the user export
+type ExportUsersQuery struct {
+	Format string `form:"format,default=ndjson" binding:"oneof=ndjson csv" example:"csv"`
+	Sort   string `form:"sort" example:"created_at:desc"`
+	Search string `form:"search" example:"john"`
+	Fields string `form:"fields" example:"id,email,username"`
 }
 
 // PaginatedResponse represents paginated response
@@ -110,8 +269,108 @@ type PaginatedResponse struct {
 
 // Pagination represents pagination metadata
 type Pagination struct {
-	Page      int   `json:"page" example:"1"`
-	PageSize  int   `json:"page_size" example:"10"`
-	Total     int64 `json:"total" example:"100"`
-	TotalPage int   `json:"total_page" example:"10"`
+	Page       int    `json:"page,omitempty" example:"1"`
+	PageSize   int    `json:"page_size" example:"10"`
+	Total      int64  `json:"total" example:"100"`
+	TotalPage  int    `json:"total_page" example:"10"`
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzIjoiY3JlYXRlZF9hdDpkZXNjIn0"`
+	PrevCursor string `json:"prev_cursor,omitempty" example:"eyJzIjoiY3JlYXRlZF9hdDpkZXNjIn0"`
+	Next       string `json:"next,omitempty" example:"/api/v1/users?cursor=eyJzIjoiY3JlYXRlZF9hdDpkZXNjIn0"`
+	Prev       string `json:"prev,omitempty" example:"/api/v1/users?cursor=eyJzIjoiY3JlYXRlZF9hdDpkZXNjIn0"`
+}
+
+// BroadcastRequest represents an admin broadcast to every connected client
+type BroadcastRequest struct {
+	Message string                 `json:"message" binding:"required,max=1000" example:"Scheduled maintenance at 22:00 UTC"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// BroadcastResponse reports how many connections a broadcast was queued for
+type BroadcastResponse struct {
+	Connections int `json:"connections" example:"12"`
+}
+
+// Subscription stores a user's Stripe customer and current plan (PostgreSQL and MongoDB)
+type Subscription struct {
+	UserID            string     `json:"user_id" gorm:"primaryKey" bson:"_id"`
+	CustomerID        string     `json:"customer_id" gorm:"index" bson:"customer_id"`
+	SubscriptionID    string     `json:"subscription_id" bson:"subscription_id"`
+	PlanID            string     `json:"plan_id" bson:"plan_id"`
+	Status            string     `json:"status" bson:"status"`
+	CurrentPeriodEnd  *time.Time `json:"current_period_end,omitempty" bson:"current_period_end,omitempty"`
+	CancelAtPeriodEnd bool       `json:"cancel_at_period_end" bson:"cancel_at_period_end"`
+	UpdatedAt         time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// PlanInfo represents a purchasable plan
+type PlanInfo struct {
+	ID   string `json:"id" example:"pro"`
+	Rank int    `json:"rank" example:"1"`
+}
+
+// SubscriptionInfo represents the caller's current plan
+type SubscriptionInfo struct {
+	PlanID            string     `json:"plan_id" example:"pro"`
+	Status            string     `json:"status" example:"active"`
+	CurrentPeriodEnd  *time.Time `json:"current_period_end,omitempty" example:"2024-02-01T00:00:00Z"`
+	CancelAtPeriodEnd bool       `json:"cancel_at_period_end" example:"false"`
+}
+
+// CheckoutRequest represents a request to start a subscription checkout
+type CheckoutRequest struct {
+	PlanID string `json:"plan_id" binding:"required" example:"pro"`
+}
+
+// CheckoutResponse carries the hosted checkout page to redirect the user to
+type CheckoutResponse struct {
+	SessionID string `json:"session_id" example:"cs_test_a1b2c3"`
+	URL       string `json:"url" example:"https://checkout.stripe.com/c/pay/cs_test_a1b2c3"`
+}
+
+// UsageRecord counts a subject's requests and transferred bytes in one monthly period (PostgreSQL and MongoDB)
+type UsageRecord struct {
+	Subject   string    `json:"subject" gorm:"primaryKey" bson:"subject"`
+	Period    string    `json:"period" gorm:"primaryKey" bson:"period"`
+	Requests  int64     `json:"requests" bson:"requests"`
+	Bytes     int64     `json:"bytes" bson:"bytes"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// UsageInfo represents the caller's consumption in the current period; limit and remaining are
+// omitted for unlimited plans
+type UsageInfo struct {
+	Plan      string    `json:"plan" example:"free"`
+	Period    string    `json:"period" example:"2024-01"`
+	Requests  int64     `json:"requests" example:"1234"`
+	Bytes     int64     `json:"bytes" example:"5242880"`
+	Limit     *int64    `json:"limit,omitempty" example:"10000"`
+	Remaining *int64    `json:"remaining,omitempty" example:"8766"`
+	ResetsAt  time.Time `json:"resets_at" example:"2024-02-01T00:00:00Z"`
+}
+
+// IdempotencyRecord stores the first response for an Idempotency-Key (PostgreSQL and MongoDB)
+type IdempotencyRecord struct {
+	Key         string    `json:"key" gorm:"primaryKey" bson:"_id"`
+	Fingerprint string    `json:"fingerprint" gorm:"not null" bson:"fingerprint"`
+	Completed   bool      `json:"completed" bson:"completed"`
+	StatusCode  int       `json:"status_code" bson:"status_code"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Body        []byte    `json:"body" bson:"body"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"index" bson:"expires_at"`
+}
+
+// MigrationStatus reports the PostgreSQL schema version against the migrations built into the binary
+type MigrationStatus struct {
+	Version    uint            `json:"version" example:"4"`
+	Dirty      bool            `json:"dirty" example:"false"`
+	Latest     uint            `json:"latest" example:"4"`
+	Migrations []MigrationInfo `json:"migrations"`
+}
+
+// MigrationInfo describes one schema migration and whether it has been applied
+type MigrationInfo struct {
+	Version uint   `json:"version" example:"1"`
+	Name    string `json:"name" example:"create_users"`
+	Applied bool   `json:"applied" example:"true"`
 }
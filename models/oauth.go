@@ -0,0 +1,223 @@
+package models
+
+import "time"
+
+// OAuthClient is an application that signs users in through this API as an OpenID Connect provider, or a
+// backend service that calls the API for itself
+type OAuthClient struct {
+	ID   string `json:"client_id" gorm:"primaryKey;size:64" bson:"_id"`
+	Name string `json:"name" gorm:"not null" bson:"name"`
+	// SecretHash is the bcrypt hash of the client secret; public clients, such as single-page and mobile
+	// apps, have none and must use PKCE
+	SecretHash   string   `json:"-" bson:"secret_hash,omitempty"`
+	RedirectURIs []string `json:"redirect_uris" gorm:"column:redirect_uris;serializer:json;type:jsonb" bson:"redirect_uris"`
+	Scopes       []string `json:"scopes" gorm:"serializer:json;type:jsonb" bson:"scopes"`
+	Trusted      bool     `json:"trusted" bson:"trusted"`
+	// Service clients get tokens for themselves with the client credentials grant, not for users
+	Service   bool      `json:"service" gorm:"not null;default:false" bson:"service"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// TableName keeps GORM from naming the table o_auth_clients
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+// OAuthCode is an authorization code waiting to be exchanged for tokens; it is stored by the hash of
+// the code and used once
+type OAuthCode struct {
+	CodeHash            string    `gorm:"primaryKey;size:64" bson:"_id"`
+	ClientID            string    `gorm:"not null" bson:"client_id"`
+	UserID              string    `gorm:"not null" bson:"user_id"`
+	RedirectURI         string    `gorm:"not null" bson:"redirect_uri"`
+	Scope               string    `gorm:"not null" bson:"scope"`
+	Nonce               string    `bson:"nonce,omitempty"`
+	CodeChallenge       string    `bson:"code_challenge,omitempty"`
+	CodeChallengeMethod string    `bson:"code_challenge_method,omitempty"`
+	ExpiresAt           time.Time `gorm:"index" bson:"expires_at"`
+}
+
+// TableName keeps GORM from naming the table o_auth_codes
+func (OAuthCode) TableName() string {
+	return "oauth_codes"
+}
+
+// OAuthConsent records the scopes a user allowed a client to access
+type OAuthConsent struct {
+	UserID    string    `json:"-" gorm:"primaryKey;size:64" bson:"user_id"`
+	ClientID  string    `json:"client_id" gorm:"primaryKey;size:64" bson:"client_id" example:"c_8Jd2kQ"`
+	Scopes    []string  `json:"scopes" gorm:"serializer:json;type:jsonb" bson:"scopes" example:"openid,profile"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// TableName keeps GORM from naming the table o_auth_consents
+func (OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
+
+// OAuthRevocation marks an access token, by its ID (the jti claim), as revoked until it expires
+type OAuthRevocation struct {
+	TokenID   string    `gorm:"primaryKey;size:64" bson:"_id"`
+	ClientID  string    `gorm:"not null" bson:"client_id"`
+	ExpiresAt time.Time `gorm:"index" bson:"expires_at"`
+}
+
+// TableName keeps GORM from naming the table o_auth_revocations
+func (OAuthRevocation) TableName() string {
+	return "oauth_revocations"
+}
+
+// Statuses of a device code
+const (
+	DeviceCodePending  = "pending"
+	DeviceCodeApproved = "approved"
+	DeviceCodeDenied   = "denied"
+)
+
+// OAuthDeviceCode is a pending device authorization (RFC 8628): the device polls with the device code,
+// stored by its hash, while the user approves the user code on another screen
+type OAuthDeviceCode struct {
+	DeviceCodeHash string     `gorm:"primaryKey;size:64" bson:"_id"`
+	UserCode       string     `gorm:"uniqueIndex;size:16" bson:"user_code"`
+	ClientID       string     `gorm:"not null" bson:"client_id"`
+	Scope          string     `gorm:"not null" bson:"scope"`
+	Status         string     `gorm:"not null;default:pending" bson:"status"`
+	UserID         string     `bson:"user_id,omitempty"`
+	LastPolledAt   *time.Time `bson:"last_polled_at,omitempty"`
+	ExpiresAt      time.Time  `gorm:"index" bson:"expires_at"`
+}
+
+// TableName keeps GORM from naming the table o_auth_device_codes
+func (OAuthDeviceCode) TableName() string {
+	return "oauth_device_codes"
+}
+
+// CreateOAuthClientRequest registers an OAuth client
+type CreateOAuthClientRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Internal dashboard"`
+	// RedirectURIs are required, except for service clients, which have none
+	RedirectURIs []string `json:"redirect_uris,omitempty" binding:"max=10,dive,url" example:"https://dashboard.example.com/callback"`
+	// Scopes limits what the client may request; empty allows every OpenID Connect scope
+	Scopes []string `json:"scopes,omitempty" example:"openid,profile,email"`
+	// Public clients get no secret and must use PKCE
+	Public bool `json:"public" example:"false"`
+	// Trusted clients, such as first-party apps, are not shown a consent screen
+	Trusted bool `json:"trusted" example:"true"`
+	// Service clients are backends that call the API for themselves with service scopes, such as users:read
+	Service bool `json:"service" example:"false"`
+}
+
+// OAuthClientInfo is a registered client; ClientSecret is only returned when the client is created
+type OAuthClientInfo struct {
+	ClientID     string    `json:"client_id" example:"c_8Jd2kQ"`
+	ClientSecret string    `json:"client_secret,omitempty" example:"s_Xq9..."`
+	Name         string    `json:"name" example:"Internal dashboard"`
+	RedirectURIs []string  `json:"redirect_uris" example:"https://dashboard.example.com/callback"`
+	Scopes       []string  `json:"scopes" example:"openid,profile,email"`
+	Public       bool      `json:"public" example:"false"`
+	Trusted      bool      `json:"trusted" example:"true"`
+	Service      bool      `json:"service" example:"false"`
+	CreatedAt    time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// Info converts the client to its API representation
+func (c *OAuthClient) Info() OAuthClientInfo {
+	return OAuthClientInfo{
+		ClientID:     c.ID,
+		Name:         c.Name,
+		RedirectURIs: c.RedirectURIs,
+		Scopes:       c.Scopes,
+		Public:       c.SecretHash == "",
+		Trusted:      c.Trusted,
+		Service:      c.Service,
+		CreatedAt:    c.CreatedAt,
+	}
+}
+
+// AuthorizationRequest holds the parameters of an OpenID Connect authorization request, as passed to
+// /oauth/authorize and forwarded to the consent screen
+type AuthorizationRequest struct {
+	ResponseType        string `json:"response_type" form:"response_type" example:"code"`
+	ClientID            string `json:"client_id" form:"client_id" binding:"required" example:"c_8Jd2kQ"`
+	RedirectURI         string `json:"redirect_uri" form:"redirect_uri" binding:"required" example:"https://dashboard.example.com/callback"`
+	Scope               string `json:"scope" form:"scope" example:"openid profile email"`
+	State               string `json:"state,omitempty" form:"state" example:"af0ifjsldkj"`
+	Nonce               string `json:"nonce,omitempty" form:"nonce" example:"n-0S6_WzA2Mj"`
+	CodeChallenge       string `json:"code_challenge,omitempty" form:"code_challenge" example:"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty" form:"code_challenge_method" example:"S256"`
+}
+
+// ConsentInfo describes an authorization request for the consent screen
+type ConsentInfo struct {
+	Client OAuthClientSummary `json:"client"`
+	// Scopes are the scopes the client asks for
+	Scopes []string `json:"scopes" example:"openid,profile"`
+	// ConsentRequired is false when the user already allowed these scopes or the client is trusted
+	ConsentRequired bool `json:"consent_required" example:"true"`
+}
+
+// OAuthClientSummary is what the consent screen shows about a client
+type OAuthClientSummary struct {
+	ClientID string `json:"client_id" example:"c_8Jd2kQ"`
+	Name     string `json:"name" example:"Internal dashboard"`
+}
+
+// ConsentDecision answers an authorization request on the consent screen
+type ConsentDecision struct {
+	AuthorizationRequest
+	Approve bool `json:"approve" example:"true"`
+}
+
+// ConsentRedirect is where the consent screen sends the browser: the client's redirect URI with a code,
+// or with an error when the user declined
+type ConsentRedirect struct {
+	RedirectTo string `json:"redirect_to" example:"https://dashboard.example.com/callback?code=Sp1x...&state=af0ifjsldkj"`
+}
+
+// DeviceAuthorizationResponse is the response of the device authorization endpoint (RFC 8628 section
+// 3.2): the device shows the user code and the verification URI, then polls the token endpoint
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceDecision answers a device authorization on the verification screen
+type DeviceDecision struct {
+	UserCode string `json:"user_code" binding:"required" example:"WDJB-MJHT"`
+	Approve  bool   `json:"approve" example:"true"`
+}
+
+// OAuthTokenResponse is the response of the token endpoint (RFC 6749 section 5.1)
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+	IDToken     string `json:"id_token,omitempty"`
+}
+
+// OAuthError is the error response of the OAuth endpoints (RFC 6749 section 5.2)
+type OAuthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OAuthIntrospection is the response of the introspection endpoint (RFC 7662 section 2.2); inactive
+// tokens only carry Active
+type OAuthIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	NotBefore int64  `json:"nbf,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	TokenID   string `json:"jti,omitempty"`
+}
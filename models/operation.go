@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Statuses of an operation
+const (
+	// OperationQueued is an operation waiting for a worker
+	OperationQueued = "queued"
+	// OperationRunning is an operation a worker is running
+	OperationRunning = "running"
+	// OperationSucceeded is an operation that finished with a result
+	OperationSucceeded = "succeeded"
+	// OperationFailed is an operation that failed or was abandoned
+	OperationFailed = "failed"
+)
+
+// Operation is a long-running request accepted with 202 and run by the background workers; clients poll
+// it with GET /operations/{id} for its progress and result (PostgreSQL and MongoDB)
+type Operation struct {
+	ID     string `gorm:"primaryKey;size:36" bson:"_id" json:"id" example:"01912f6e-8a3c-7b2e-9c41-5d2f3a6b7c8d"`
+	Kind   string `gorm:"not null" bson:"kind" json:"kind" example:"users.bulk"`
+	Status string `gorm:"size:16;index;not null" bson:"status" json:"status" enums:"queued,running,succeeded,failed" example:"running"`
+	// OwnerID is the user who started the operation
+	OwnerID string `gorm:"not null" bson:"owner_id" json:"owner_id" example:"42"`
+	// Params are the input of the operation, kept for the worker
+	Params json.RawMessage `gorm:"serializer:json;type:jsonb" bson:"params" json:"-"`
+	// Attempts counts the times a worker took the operation
+	Attempts int `gorm:"not null" bson:"attempts" json:"attempts" example:"1"`
+	// Done and Total are the progress in items, such as users; Total is 0 when unknown
+	Done  int `gorm:"not null" bson:"done" json:"done" example:"250"`
+	Total int `gorm:"not null" bson:"total" json:"total" example:"1000"`
+	// Result is the response data of the operation once it succeeded
+	Result     json.RawMessage `gorm:"serializer:json;type:jsonb" bson:"result,omitempty" json:"result,omitempty" swaggertype:"object"`
+	Error      string          `bson:"error,omitempty" json:"error,omitempty" example:"The operation failed"`
+	CreatedAt  time.Time       `bson:"created_at" json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt  time.Time       `gorm:"index" bson:"updated_at" json:"updated_at" example:"2024-01-01T00:00:05Z"`
+	FinishedAt *time.Time      `gorm:"index" bson:"finished_at,omitempty" json:"finished_at,omitempty" example:"2024-01-01T00:01:00Z"`
+}
+
+// Finished reports whether the operation succeeded or failed
+func (o *Operation) Finished() bool {
+	return o.Status == OperationSucceeded || o.Status == OperationFailed
+}
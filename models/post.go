@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gorm.io/gorm"
+)
+
+// Post represents a post in PostgreSQL
+type Post struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	OwnerID   string         `json:"owner_id" gorm:"type:integer;not null;index:idx_posts_owner_id_created_at,priority:1"`
+	Title     string         `json:"title" gorm:"not null"`
+	Body      string         `json:"body" gorm:"not null"`
+	CreatedAt time.Time      `json:"created_at" gorm:"index:idx_posts_owner_id_created_at,priority:2"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// PostMongo represents a post in MongoDB
+type PostMongo struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OwnerID   primitive.ObjectID `json:"owner_id" bson:"owner_id"`
+	Title     string             `json:"title" bson:"title"`
+	Body      string             `json:"body" bson:"body"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+	DeletedAt *time.Time         `json:"-" bson:"deleted_at,omitempty"`
+}
+
+// PostInfo is the post returned by the API
+type PostInfo struct {
+	ID        string    `json:"id" example:"1"`
+	OwnerID   string    `json:"owner_id" example:"1"`
+	Title     string    `json:"title" example:"Hello, world"`
+	Body      string    `json:"body" example:"My first post"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// CreatePostRequest represents post creation request payload
+type CreatePostRequest struct {
+	Title string `json:"title" binding:"required,max=200" example:"Hello, world"`
+	Body  string `json:"body" binding:"required,max=20000" example:"My first post"`
+}
+
+// UpdatePostRequest represents post update request payload; omitted fields are left unchanged
+type UpdatePostRequest struct {
+	Title *string `json:"title,omitempty" binding:"omitempty,min=1,max=200" example:"Hello, world"`
+	Body  *string `json:"body,omitempty" binding:"omitempty,min=1,max=20000" example:"My first post"`
+}
+
+// ListPostsQuery represents the query parameters of the post listing
+type ListPostsQuery struct {
+	Page     int    `form:"page,default=1" binding:"min=1" example:"1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100" example:"10"`
+	Search   string `form:"search" binding:"max=200" example:"hello"`
+	OwnerID  string `form:"owner_id" example:"1"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Date is a calendar date without time or zone, such as a birthday, written as YYYY-MM-DD in JSON, BSON,
+// and SQL. The empty Date is stored as NULL.
+type Date string
+
+// Time parses the date as midnight UTC
+func (d Date) Time() (time.Time, error) {
+	return time.Parse(time.DateOnly, string(d))
+}
+
+// Value implements driver.Valuer
+func (d Date) Value() (driver.Value, error) {
+	if d == "" {
+		return nil, nil
+	}
+	return string(d), nil
+}
+
+// Scan implements sql.Scanner; drivers return date columns as time.Time or text
+func (d *Date) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = ""
+	case time.Time:
+		*d = Date(v.Format(time.DateOnly))
+	case string:
+		*d = Date(dateOnly(v))
+	case []byte:
+		*d = Date(dateOnly(string(v)))
+	default:
+		return fmt.Errorf("cannot scan %T into a Date", value)
+	}
+	return nil
+}
+
+// dateOnly drops the time part that some drivers append to dates read as text
+func dateOnly(value string) string {
+	if len(value) > len(time.DateOnly) {
+		return value[:len(time.DateOnly)]
+	}
+	return value
+}
+
+// Address is a postal address; Country is an ISO 3166-1 alpha-2 code
+type Address struct {
+	Line1      string `json:"line1,omitempty" bson:"line1,omitempty" binding:"max=200" example:"1 Market St"`
+	Line2      string `json:"line2,omitempty" bson:"line2,omitempty" binding:"max=200" example:"Suite 300"`
+	City       string `json:"city,omitempty" bson:"city,omitempty" binding:"max=100" example:"San Francisco"`
+	Region     string `json:"region,omitempty" bson:"region,omitempty" binding:"max=100" example:"CA"`
+	PostalCode string `json:"postal_code,omitempty" bson:"postal_code,omitempty" binding:"max=20" example:"94105"`
+	Country    string `json:"country,omitempty" bson:"country,omitempty" binding:"omitempty,iso3166_1_alpha2" example:"US"`
+}
+
+// Metadata holds the custom attributes of a user: strings, numbers, and booleans keyed by the names of
+// the metadata schema
+type Metadata map[string]interface{}
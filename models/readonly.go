@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ReadOnlyStatus reports whether the API refuses the requests that change data
+type ReadOnlyStatus struct {
+	Enabled bool `json:"enabled" example:"true"`
+	// Reason is the message of the 503 responses, empty for the default one
+	Reason string `json:"reason,omitempty" example:"Database upgrade until 22:00 UTC"`
+	// Since is when read-only mode was turned on
+	Since *time.Time `json:"since,omitempty" example:"2024-01-01T20:00:00Z"`
+}
+
+// SetReadOnlyRequest turns read-only mode on or off
+type SetReadOnlyRequest struct {
+	Enabled *bool `json:"enabled" binding:"required" example:"true"`
+	// Reason is shown to clients in the 503 responses while read-only mode is on
+	Reason string `json:"reason" binding:"max=200" example:"Database upgrade until 22:00 UTC"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Session is a sign-in of a user on one device, kept alive by refreshing its refresh token. Only the hash
+// of the current refresh token is stored; each refresh replaces it and slides ExpiresAt forward, never
+// past MaxExpiresAt.
+type Session struct {
+	ID        string `gorm:"primaryKey;size:64" bson:"_id"`
+	UserID    string `gorm:"not null;size:64;index" bson:"user_id"`
+	TokenHash string `gorm:"not null;size:64;uniqueIndex" bson:"token_hash"`
+	// RememberMe sessions were started with remember_me and get the longer lifetimes
+	RememberMe   bool      `bson:"remember_me"`
+	UserAgent    string    `bson:"user_agent,omitempty"`
+	IPAddress    string    `gorm:"size:64" bson:"ip_address,omitempty"`
+	CreatedAt    time.Time `bson:"created_at"`
+	LastUsedAt   time.Time `bson:"last_used_at"`
+	ExpiresAt    time.Time `gorm:"index" bson:"expires_at"`
+	MaxExpiresAt time.Time `bson:"max_expires_at"`
+}
+
+// Info returns the session as listed to its user; current marks the session of the request
+func (s Session) Info(current bool) SessionInfo {
+	return SessionInfo{
+		ID:           s.ID,
+		RememberMe:   s.RememberMe,
+		Current:      current,
+		UserAgent:    s.UserAgent,
+		IPAddress:    s.IPAddress,
+		CreatedAt:    s.CreatedAt,
+		LastUsedAt:   s.LastUsedAt,
+		ExpiresAt:    s.ExpiresAt,
+		MaxExpiresAt: s.MaxExpiresAt,
+	}
+}
+
+// SessionInfo is a session in the list of the signed-in user
+type SessionInfo struct {
+	ID string `json:"id" example:"s_2b7f0c1e9a4d4f3b8e6a"`
+	// RememberMe is true for sessions started with remember_me, which stay signed in longer
+	RememberMe bool `json:"remember_me" example:"true"`
+	// Current is true for the session of the token the list was requested with
+	Current    bool      `json:"current" example:"true"`
+	UserAgent  string    `json:"user_agent,omitempty" example:"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4)"`
+	IPAddress  string    `json:"ip_address,omitempty" example:"203.0.113.7"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2024-01-05T00:00:00Z"`
+	// ExpiresAt is when the session ends unless it is refreshed first
+	ExpiresAt time.Time `json:"expires_at" example:"2024-02-04T00:00:00Z"`
+	// MaxExpiresAt is when the session ends however often it is refreshed
+	MaxExpiresAt time.Time `json:"max_expires_at" example:"2024-03-31T00:00:00Z"`
+}
+
+// RefreshRequest exchanges a refresh token, or in cookie delivery mode the refresh cookie, for new tokens
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" example:"Rk3m9vQ2x8L1pZ7wYc4tN0bH5sJ6dA2eG9uK1fV3oXi"`
+}
+
+// LogoutRequest names the refresh token whose session ends; without one, only the auth cookie is cleared
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" example:"Rk3m9vQ2x8L1pZ7wYc4tN0bH5sJ6dA2eG9uK1fV3oXi"`
+}
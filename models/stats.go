@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// StatsQuery represents the query parameters of the admin statistics
+type StatsQuery struct {
+	Days int `form:"days,default=30" binding:"min=1,max=365" example:"30"`
+}
+
+// AdminStats summarizes the users and databases for the admin dashboard
+type AdminStats struct {
+	Users UserStats `json:"users"`
+	// Signups counts the registrations of each of the last days, oldest first, including since deleted users
+	Signups     []DailyCount   `json:"signups"`
+	ActiveUsers ActiveUsers    `json:"active_users"`
+	Logins      LoginStats     `json:"logins"`
+	Databases   []DatabaseSize `json:"databases"`
+	// GeneratedAt is when the figures were computed; they are cached for ADMIN_STATS_CACHE_TTL
+	GeneratedAt time.Time `json:"generated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// UserStats counts the user accounts
+type UserStats struct {
+	// Total counts the users that are not deleted
+	Total    int64       `json:"total" example:"1250"`
+	Active   int64       `json:"active" example:"1200"`
+	Inactive int64       `json:"inactive" example:"50"`
+	Deleted  int64       `json:"deleted" example:"12"`
+	ByRole   []RoleCount `json:"by_role"`
+}
+
+// RoleCount counts the users with a role
+type RoleCount struct {
+	Role  string `json:"role" example:"user"`
+	Count int64  `json:"count" example:"1245"`
+}
+
+// DailyCount is a count for one UTC day
+type DailyCount struct {
+	Date  string `json:"date" example:"2024-01-01"`
+	Count int64  `json:"count" example:"17"`
+}
+
+// ActiveUsers counts the users that signed in within each period
+type ActiveUsers struct {
+	Last24h int64 `json:"last_24h" example:"310"`
+	Last7d  int64 `json:"last_7d" example:"640"`
+	Last30d int64 `json:"last_30d" example:"980"`
+}
+
+// LoginStats counts the logins of the last day seen by the instance that answers
+type LoginStats struct {
+	Window      string  `json:"window" example:"24h"`
+	Successes   int64   `json:"successes" example:"420"`
+	Failures    int64   `json:"failures" example:"38"`
+	FailureRate float64 `json:"failure_rate" example:"0.083"`
+}
+
+// DatabaseSize reports the disk space a database uses
+type DatabaseSize struct {
+	Database  string `json:"database" example:"postgresql" enums:"postgresql,sqlite,mongodb"`
+	SizeBytes int64  `json:"size_bytes" example:"52428800"`
+	// Tables lists the tables or collections, largest first, where the database reports them
+	Tables []TableSize `json:"tables,omitempty"`
+}
+
+// TableSize reports the disk space of a table or collection, including its indexes
+type TableSize struct {
+	Name      string `json:"name" example:"users"`
+	SizeBytes int64  `json:"size_bytes" example:"1048576"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Translation is a message text edited at runtime; it takes precedence over the translation files
+// (PostgreSQL and MongoDB)
+type Translation struct {
+	Language  string    `json:"language" gorm:"primaryKey;size:35" bson:"language" example:"de"`
+	Key       string    `json:"key" gorm:"primaryKey;size:200" bson:"key" example:"welcome"`
+	Text      string    `json:"text" gorm:"not null" bson:"text" example:"Herzlich willkommen"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// SetTranslationRequest represents a request to override one message text
+type SetTranslationRequest struct {
+	Text string `json:"text" binding:"required,max=2000" example:"Herzlich willkommen"`
+}
+
+// MissingTranslations lists the message keys of the default language that a language does not translate
+type MissingTranslations struct {
+	Language string   `json:"language" example:"de"`
+	Keys     []string `json:"keys" example:"welcome"`
+}
+
+// TranslationImport reports the result of importing a locale bundle
+type TranslationImport struct {
+	Language string `json:"language" example:"de"`
+	// Imported counts the texts stored as overrides; texts equal to those of the files are not stored
+	Imported int `json:"imported" example:"12"`
+	// Removed counts the overrides deleted because replace was set
+	Removed int64 `json:"removed" example:"0"`
+}
+
+// LanguageInfo describes a language responses can be localized in, for language pickers
+type LanguageInfo struct {
+	Code string `json:"code" example:"ar"`
+	// Name is the name of the language in the language itself
+	Name string `json:"name" example:"العربية"`
+	// Direction is the writing direction, ltr or rtl
+	Direction string `json:"direction" example:"rtl" enums:"ltr,rtl"`
+	Default   bool   `json:"default" example:"false"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Statuses of a webhook delivery
+const (
+	// WebhookProcessing is a delivery being processed, or whose processing was cut short
+	WebhookProcessing = "processing"
+	// WebhookProcessed is a delivery processed successfully; later deliveries of it are acknowledged
+	// without processing it again
+	WebhookProcessed = "processed"
+	// WebhookFailed is a delivery whose processing failed; the provider's next retry processes it again
+	WebhookFailed = "failed"
+	// WebhookDead is a dead letter: a delivery that failed WEBHOOK_MAX_ATTEMPTS times and is kept for an
+	// admin to replay
+	WebhookDead = "dead"
+)
+
+// WebhookEvent records a webhook delivery from a provider, so a delivery retried by the provider is
+// processed once, and one that keeps failing is kept as a dead letter (PostgreSQL and MongoDB)
+type WebhookEvent struct {
+	ID       string `gorm:"primaryKey;size:36" bson:"_id" json:"id" example:"01912f6e-8a3c-7b2e-9c41-5d2f3a6b7c8d"`
+	Provider string `gorm:"uniqueIndex:idx_webhook_events_delivery;not null" bson:"provider" json:"provider" example:"stripe"`
+	// EventID is the provider's ID of the delivery, the same in each of its retries
+	EventID string `gorm:"uniqueIndex:idx_webhook_events_delivery;not null" bson:"event_id" json:"event_id" example:"evt_1NfQ2aLkdIwHu7ix"`
+	Type    string `bson:"type,omitempty" json:"type,omitempty" example:"customer.subscription.updated"`
+	Status  string `gorm:"index;not null" bson:"status" json:"status" example:"dead"`
+	// Attempts counts the times the delivery was processed
+	Attempts  int    `gorm:"not null" bson:"attempts" json:"attempts" example:"5"`
+	LastError string `bson:"last_error,omitempty" json:"last_error,omitempty" example:"failed to decode subscription"`
+	// Payload is the body of the delivery as received
+	Payload     string     `gorm:"not null" bson:"payload" json:"payload" example:"{\"id\":\"evt_1NfQ2aLkdIwHu7ix\",\"type\":\"customer.subscription.updated\"}"`
+	ReceivedAt  time.Time  `gorm:"index" bson:"received_at" json:"received_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time  `bson:"updated_at" json:"updated_at" example:"2024-01-01T00:05:00Z"`
+	ProcessedAt *time.Time `bson:"processed_at,omitempty" json:"processed_at,omitempty" example:"2024-01-01T00:05:00Z"`
+}
+
+// ListWebhookEventsQuery represents the query parameters of the webhook delivery listing
+type ListWebhookEventsQuery struct {
+	Page     int    `form:"page,default=1" binding:"min=1" example:"1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100" example:"10"`
+	Provider string `form:"provider" example:"stripe"`
+	Status   string `form:"status" binding:"omitempty,oneof=processing processed failed dead" example:"dead"`
+}
@@ -0,0 +1,225 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-backend-template/models"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// GrantTypeDeviceCode is the grant type a device polls the token endpoint with (RFC 8628 section 3.4)
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// userCodeAlphabet has no vowels, so user codes do not spell words, and no letters that are easily
+// confused with each other (RFC 8628 section 6.1)
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// userCodeLength is the number of characters of a user code, shown in two groups of four
+const userCodeLength = 8
+
+// DeviceEnabled reports whether the device authorization grant is enabled, which needs a verification
+// screen on the frontend
+func (p *Provider) DeviceEnabled() bool {
+	return p.cfg.DeviceVerificationURL != ""
+}
+
+// DeviceAuthorizationRequest is a request to the device authorization endpoint; the client credentials
+// come from the form or from HTTP Basic authentication
+type DeviceAuthorizationRequest struct {
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Scope        string `form:"scope"`
+}
+
+// DeviceAuthorize starts a device authorization (RFC 8628 section 3.1): the device gets a device code to
+// poll the token endpoint with and a user code the user enters on the verification screen. Errors the
+// client caused are an *Error.
+func (p *Provider) DeviceAuthorize(ctx context.Context, req DeviceAuthorizationRequest) (*models.DeviceAuthorizationResponse, error) {
+	client, err := p.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if client.Service {
+		return nil, oauthError("unauthorized_client", "service clients cannot sign users in")
+	}
+	scopes, err := p.scopes(client, req.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceCode, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	userCode, err := randomUserCode()
+	if err != nil {
+		return nil, err
+	}
+	err = p.store.SaveDeviceCode(ctx, &models.OAuthDeviceCode{
+		DeviceCodeHash: utils.HashToken(deviceCode),
+		UserCode:       userCode,
+		ClientID:       client.ID,
+		Scope:          strings.Join(scopes, " "),
+		Status:         models.DeviceCodePending,
+		ExpiresAt:      time.Now().Add(p.cfg.DeviceCodeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	separator := "?"
+	if strings.Contains(p.cfg.DeviceVerificationURL, "?") {
+		separator = "&"
+	}
+	display := formatUserCode(userCode)
+	return &models.DeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                display,
+		VerificationURI:         p.cfg.DeviceVerificationURL,
+		VerificationURIComplete: p.cfg.DeviceVerificationURL + separator + url.Values{"user_code": {display}}.Encode(),
+		ExpiresIn:               int(p.cfg.DeviceCodeTTL.Seconds()),
+		Interval:                int(p.cfg.DevicePollInterval.Seconds()),
+	}, nil
+}
+
+// DeviceConsent describes the pending device authorization with the user code for the verification
+// screen, or returns ErrDeviceCodeNotFound. Consent is always required: the user must confirm they started
+// the request on their device, since anyone can send them a user code (RFC 8628 section 5.4).
+func (p *Provider) DeviceConsent(ctx context.Context, userCode string) (models.ConsentInfo, error) {
+	code, client, err := p.pendingDeviceCode(ctx, userCode)
+	if err != nil {
+		return models.ConsentInfo{}, err
+	}
+	return models.ConsentInfo{
+		Client:          models.OAuthClientSummary{ClientID: client.ID, Name: client.Name},
+		Scopes:          strings.Fields(code.Scope),
+		ConsentRequired: true,
+	}, nil
+}
+
+// DeviceDecide records whether the user approved the device authorization with the user code, or returns
+// ErrDeviceCodeNotFound; an approval is remembered as consent to the client
+func (p *Provider) DeviceDecide(ctx context.Context, userID, userCode string, approve bool) error {
+	code, client, err := p.pendingDeviceCode(ctx, userCode)
+	if err != nil {
+		return err
+	}
+	status := models.DeviceCodeDenied
+	if approve {
+		status = models.DeviceCodeApproved
+	}
+	if err := p.store.DecideDeviceCode(ctx, code.UserCode, userID, status); err != nil {
+		return err
+	}
+	if approve {
+		if err := p.saveConsent(ctx, userID, client, strings.Fields(code.Scope)); err != nil {
+			return err
+		}
+	}
+	p.logger.Info("OAuth device authorization decided", "client_id", client.ID, "user_id", userID, "status", status)
+	return nil
+}
+
+// pendingDeviceCode returns the pending device code with the user code, as typed by the user, and its
+// client
+func (p *Provider) pendingDeviceCode(ctx context.Context, userCode string) (*models.OAuthDeviceCode, *models.OAuthClient, error) {
+	code, err := p.store.FindDeviceCode(ctx, normalizeUserCode(userCode))
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := p.store.GetClient(ctx, code.ClientID)
+	if errors.Is(err, ErrClientNotFound) {
+		return nil, nil, ErrDeviceCodeNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return code, client, nil
+}
+
+// deviceToken answers a device polling the token endpoint (RFC 8628 section 3.5) and issues the tokens
+// once the user approved
+func (p *Provider) deviceToken(ctx context.Context, client *models.OAuthClient, deviceCode string) (*models.OAuthTokenResponse, error) {
+	hash := utils.HashToken(deviceCode)
+	code, err := p.store.GetDeviceCode(ctx, hash)
+	if errors.Is(err, ErrDeviceCodeNotFound) {
+		return nil, oauthError("invalid_grant", "the device code is invalid or already used")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if code.ClientID != client.ID {
+		return nil, oauthError("invalid_grant", "the device code was issued to another client")
+	}
+	now := time.Now()
+	if now.After(code.ExpiresAt) {
+		return nil, oauthError("expired_token", "the device code expired")
+	}
+
+	switch code.Status {
+	case models.DeviceCodeDenied:
+		if err := p.store.DeleteDeviceCode(ctx, hash); err != nil && !errors.Is(err, ErrDeviceCodeNotFound) {
+			return nil, err
+		}
+		return nil, oauthError("access_denied", "the user declined the request")
+	case models.DeviceCodePending:
+		tooFast := code.LastPolledAt != nil && now.Sub(*code.LastPolledAt) < p.cfg.DevicePollInterval
+		if err := p.store.TouchDeviceCode(ctx, hash, now); err != nil {
+			return nil, err
+		}
+		if tooFast {
+			return nil, oauthError("slow_down", "poll at most every %d seconds", int(p.cfg.DevicePollInterval.Seconds()))
+		}
+		return nil, oauthError("authorization_pending", "the user has not decided yet")
+	}
+
+	if err := p.store.DeleteDeviceCode(ctx, hash); errors.Is(err, ErrDeviceCodeNotFound) {
+		return nil, oauthError("invalid_grant", "the device code is invalid or already used")
+	} else if err != nil {
+		return nil, err
+	}
+	user, err := p.users.GetProfile(ctx, code.UserID, nil)
+	if errors.Is(err, services.ErrUserNotFound) || err == nil && !user.IsActive {
+		return nil, oauthError("invalid_grant", "the user is no longer active")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.issue(client, user, strings.Fields(code.Scope), "")
+}
+
+// randomUserCode returns a user code from userCodeAlphabet
+func randomUserCode() (string, error) {
+	var b strings.Builder
+	base := big.NewInt(int64(len(userCodeAlphabet)))
+	for range userCodeLength {
+		n, err := rand.Int(rand.Reader, base)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// formatUserCode splits a user code in two groups for display, as in WDJB-MJHT
+func formatUserCode(code string) string {
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+// normalizeUserCode undoes the formatting a user may type: case, dashes, and spaces
+func normalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
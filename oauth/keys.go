@@ -0,0 +1,88 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// signingKey is the RSA key that signs ID tokens; clients verify them with its public half, published
+// as a JSON Web Key Set
+type signingKey struct {
+	private *rsa.PrivateKey
+	id      string
+}
+
+// loadSigningKey reads a PEM-encoded RSA private key (PKCS #1 or PKCS #8) from path, or generates one
+// when path is empty
+func loadSigningKey(path string) (*signingKey, error) {
+	var private *rsa.PrivateKey
+	if path == "" {
+		var err error
+		if private, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			return nil, err
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no PEM block found")
+		}
+		if private, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			key, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if pkcs8Err != nil {
+				return nil, fmt.Errorf("not an RSA private key: %w", pkcs8Err)
+			}
+			var ok bool
+			if private, ok = key.(*rsa.PrivateKey); !ok {
+				return nil, errors.New("not an RSA private key")
+			}
+		}
+	}
+
+	// The key ID is derived from the public key, so it changes exactly when the key does
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+	return &signingKey{private: private, id: base64.RawURLEncoding.EncodeToString(sum[:12])}, nil
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKSet is the document of the JWKS endpoint
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// jwk returns the public key for the JWKS endpoint
+func (k *signingKey) jwk() JWK {
+	public := k.private.PublicKey
+	return JWK{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: "RS256",
+		KeyID:     k.id,
+		Modulus:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+	}
+}
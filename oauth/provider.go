@@ -0,0 +1,656 @@
+// Package oauth makes the API an OpenID Connect provider, so other applications sign users in with their
+// accounts here (single sign-on). It implements the authorization code flow with PKCE (RFC 6749, RFC
+// 7636, and OpenID Connect Core): /oauth/authorize sends the browser to the consent screen of the
+// frontend, which approves through the consent API and returns the browser to the client with a code;
+// the client exchanges the code at /oauth/token for an access token and an ID token signed with an RSA
+// key published at /oauth/jwks, and reads the profile at /oauth/userinfo. Resource servers check access
+// tokens at /oauth/introspect (RFC 7662), and clients revoke them at /oauth/revoke (RFC 7009). Clients are
+// registered by admins; consents are remembered per user and client. Service clients, other backends,
+// get tokens for themselves with the client credentials grant and service scopes such as users:read.
+// Devices without a browser, such as CLI tools, use the device authorization grant (RFC 8628): they show
+// a user code the user approves on the verification screen of the frontend, and poll /oauth/token.
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+
+	"go-backend-template/config"
+	"go-backend-template/jwt"
+	"go-backend-template/models"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// Scopes a client may request
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// SupportedScopes are the scopes of the provider, in the order they are listed
+var SupportedScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail}
+
+// Service scopes of the built-in routes; OAUTH_SERVICE_SCOPES adds those of custom routes
+const (
+	ScopeUsersRead = "users:read"
+)
+
+// ServiceScopes are the built-in scopes of service tokens
+var ServiceScopes = []string{ScopeUsersRead}
+
+// Error is an OAuth error (RFC 6749 sections 4.1.2.1 and 5.2), sent to the client as Code and
+// Description
+type Error struct {
+	Code        string
+	Description string
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+// Status is the HTTP status of the error at the token endpoint
+func (e *Error) Status() int {
+	if e.Code == "invalid_client" {
+		return http.StatusUnauthorized
+	}
+	return http.StatusBadRequest
+}
+
+// Response is the JSON body of the error
+func (e *Error) Response() models.OAuthError {
+	return models.OAuthError{Error: e.Code, ErrorDescription: e.Description}
+}
+
+// oauthError returns an Error with a formatted description
+func oauthError(code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Description: fmt.Sprintf(format, args...)}
+}
+
+// Provider issues codes and tokens to registered clients
+type Provider struct {
+	cfg      config.OAuthConfig
+	issuer   string
+	services []string
+	store    Store
+	users    services.UserService
+	jwtUtils *utils.JWTUtils
+	key      *signingKey
+	logger   utils.Logger
+}
+
+// NewProvider creates a provider for cfg. ID tokens are signed with the key in cfg.SigningKeyFile; without
+// one, a key is generated at startup, so tokens signed before a restart cannot be verified, and each
+// instance of the API signs with its own key.
+func NewProvider(cfg config.OAuthConfig, store Store, users services.UserService, jwtUtils *utils.JWTUtils, logger utils.Logger) (*Provider, error) {
+	key, err := loadSigningKey(cfg.SigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OAUTH_SIGNING_KEY_FILE: %w", err)
+	}
+	if cfg.SigningKeyFile == "" {
+		logger.Warn("OAUTH_SIGNING_KEY_FILE is not set; ID tokens are signed with a key generated at startup")
+	}
+	return &Provider{
+		cfg:      cfg,
+		issuer:   strings.TrimSuffix(cfg.Issuer, "/"),
+		services: mergeScopes(ServiceScopes, cfg.ServiceScopes),
+		store:    store,
+		users:    users,
+		jwtUtils: jwtUtils,
+		key:      key,
+		logger:   logger,
+	}, nil
+}
+
+// Discovery is the OpenID Provider metadata served at /.well-known/openid-configuration
+type Discovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint,omitempty"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// Discovery returns the provider metadata
+func (p *Provider) Discovery() Discovery {
+	discovery := Discovery{
+		Issuer:                            p.issuer,
+		AuthorizationEndpoint:             p.issuer + "/oauth/authorize",
+		TokenEndpoint:                     p.issuer + "/oauth/token",
+		UserInfoEndpoint:                  p.issuer + "/oauth/userinfo",
+		JWKSURI:                           p.issuer + "/oauth/jwks",
+		IntrospectionEndpoint:             p.issuer + "/oauth/introspect",
+		RevocationEndpoint:                p.issuer + "/oauth/revoke",
+		ScopesSupported:                   mergeScopes(SupportedScopes, p.services),
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "client_credentials"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"RS256"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported: []string{
+			"sub", "iss", "aud", "exp", "iat", "nonce", "name", "given_name", "family_name",
+			"preferred_username", "picture", "locale", "updated_at", "email",
+		},
+	}
+	if p.DeviceEnabled() {
+		discovery.DeviceAuthorizationEndpoint = p.issuer + "/oauth/device_authorization"
+		discovery.GrantTypesSupported = append(discovery.GrantTypesSupported, GrantTypeDeviceCode)
+	}
+	return discovery
+}
+
+// JWKS returns the public key that verifies ID tokens
+func (p *Provider) JWKS() JWKSet {
+	return JWKSet{Keys: []JWK{p.key.jwk()}}
+}
+
+// ConsentURL returns the consent screen for an authorization request
+func (p *Provider) ConsentURL(query url.Values) string {
+	separator := "?"
+	if strings.Contains(p.cfg.ConsentURL, "?") {
+		separator = "&"
+	}
+	return p.cfg.ConsentURL + separator + query.Encode()
+}
+
+// Authorization is a validated authorization request
+type Authorization struct {
+	Request models.AuthorizationRequest
+	Client  *models.OAuthClient
+	Scopes  []string
+}
+
+// Redirect returns the redirect URI of the request with params and the state added
+func (a *Authorization) Redirect(params url.Values) string {
+	target, _ := url.Parse(a.Request.RedirectURI)
+	query := target.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	if a.Request.State != "" {
+		query.Set("state", a.Request.State)
+	}
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+// ErrorRedirect returns the redirect URI of the request with the error
+func (a *Authorization) ErrorRedirect(err *Error) string {
+	return a.Redirect(url.Values{"error": {err.Code}, "error_description": {err.Description}})
+}
+
+// Authorize validates an authorization request. When the client or the redirect URI is invalid it returns
+// a nil Authorization, as the user must then not be sent to the redirect URI; otherwise any error is an
+// *Error for the redirect URI.
+func (p *Provider) Authorize(ctx context.Context, req models.AuthorizationRequest) (*Authorization, error) {
+	client, err := p.store.GetClient(ctx, req.ClientID)
+	if errors.Is(err, ErrClientNotFound) {
+		return nil, oauthError("invalid_client", "unknown client_id")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if client.Service {
+		return nil, oauthError("unauthorized_client", "service clients cannot sign users in")
+	}
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		return nil, oauthError("invalid_request", "redirect_uri is not registered for the client")
+	}
+
+	authorization := &Authorization{Request: req, Client: client}
+	if req.ResponseType != "code" {
+		return authorization, oauthError("unsupported_response_type", "response_type must be code")
+	}
+	if authorization.Scopes, err = p.scopes(client, req.Scope); err != nil {
+		return authorization, err
+	}
+	switch {
+	case req.CodeChallenge == "" && client.SecretHash == "":
+		return authorization, oauthError("invalid_request", "public clients must send a PKCE code_challenge")
+	case req.CodeChallenge != "" && req.CodeChallengeMethod != "S256":
+		return authorization, oauthError("invalid_request", "code_challenge_method must be S256")
+	}
+	return authorization, nil
+}
+
+// scopes parses the requested scopes, which must be supported and allowed for the client
+func (p *Provider) scopes(client *models.OAuthClient, scope string) ([]string, error) {
+	requested := strings.Fields(scope)
+	if len(requested) == 0 {
+		return nil, oauthError("invalid_scope", "scope is required")
+	}
+	var scopes []string
+	for _, s := range requested {
+		if !slices.Contains(SupportedScopes, s) || len(client.Scopes) > 0 && !slices.Contains(client.Scopes, s) {
+			return nil, oauthError("invalid_scope", "scope %q is not allowed", s)
+		}
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes, nil
+}
+
+// Consent describes the authorization for the consent screen of the user
+func (p *Provider) Consent(ctx context.Context, userID string, a *Authorization) (models.ConsentInfo, error) {
+	required, err := p.consentRequired(ctx, userID, a)
+	if err != nil {
+		return models.ConsentInfo{}, err
+	}
+	return models.ConsentInfo{
+		Client:          models.OAuthClientSummary{ClientID: a.Client.ID, Name: a.Client.Name},
+		Scopes:          a.Scopes,
+		ConsentRequired: required,
+	}, nil
+}
+
+// consentRequired reports whether the user has yet to allow the scopes of the authorization
+func (p *Provider) consentRequired(ctx context.Context, userID string, a *Authorization) (bool, error) {
+	if a.Client.Trusted {
+		return false, nil
+	}
+	consent, err := p.store.GetConsent(ctx, userID, a.Client.ID)
+	if errors.Is(err, ErrConsentNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, scope := range a.Scopes {
+		if !slices.Contains(consent.Scopes, scope) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Approve records the consent of the user and returns the redirect URI with a new authorization code
+func (p *Provider) Approve(ctx context.Context, userID string, a *Authorization) (string, error) {
+	if err := p.saveConsent(ctx, userID, a.Client, a.Scopes); err != nil {
+		return "", err
+	}
+
+	code, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	err = p.store.SaveCode(ctx, &models.OAuthCode{
+		CodeHash:            utils.HashToken(code),
+		ClientID:            a.Client.ID,
+		UserID:              userID,
+		RedirectURI:         a.Request.RedirectURI,
+		Scope:               strings.Join(a.Scopes, " "),
+		Nonce:               a.Request.Nonce,
+		CodeChallenge:       a.Request.CodeChallenge,
+		CodeChallengeMethod: a.Request.CodeChallengeMethod,
+		ExpiresAt:           time.Now().Add(p.cfg.CodeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return a.Redirect(url.Values{"code": {code}}), nil
+}
+
+// saveConsent adds scopes to the consent of the user to the client; trusted clients need none
+func (p *Provider) saveConsent(ctx context.Context, userID string, client *models.OAuthClient, scopes []string) error {
+	if client.Trusted {
+		return nil
+	}
+	consent := &models.OAuthConsent{UserID: userID, ClientID: client.ID, Scopes: scopes, UpdatedAt: time.Now()}
+	if previous, err := p.store.GetConsent(ctx, userID, client.ID); err == nil {
+		consent.Scopes = mergeScopes(consent.Scopes, previous.Scopes)
+	}
+	return p.store.SaveConsent(ctx, consent)
+}
+
+// Deny returns the redirect URI that tells the client the user declined
+func (p *Provider) Deny(a *Authorization) string {
+	return a.ErrorRedirect(oauthError("access_denied", "the user declined the request"))
+}
+
+// TokenRequest is a request to the token endpoint; the client credentials come from the form or from
+// HTTP Basic authentication
+type TokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	DeviceCode   string `form:"device_code"`
+	// Scope narrows the scopes of a client credentials token; without it the token gets all of them
+	Scope string `form:"scope"`
+}
+
+// Token issues tokens for a grant: an authorization code, or a device code the user approved, is
+// exchanged for an access token and, with the openid scope, an ID token; the client credentials of a
+// service client get it an access token for itself. Errors the client caused are an *Error.
+func (p *Provider) Token(ctx context.Context, req TokenRequest) (*models.OAuthTokenResponse, error) {
+	switch {
+	case req.GrantType == "authorization_code", req.GrantType == "client_credentials":
+	case req.GrantType == GrantTypeDeviceCode && p.DeviceEnabled():
+	default:
+		return nil, oauthError("unsupported_grant_type", "grant_type %q is not supported", req.GrantType)
+	}
+	client, err := p.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if client.Service != (req.GrantType == "client_credentials") {
+		return nil, oauthError("unauthorized_client", "the client may not use the %s grant", req.GrantType)
+	}
+	if client.Service {
+		return p.issueService(client, req.Scope)
+	}
+	if req.GrantType == GrantTypeDeviceCode {
+		return p.deviceToken(ctx, client, req.DeviceCode)
+	}
+
+	code, err := p.store.TakeCode(ctx, utils.HashToken(req.Code))
+	if errors.Is(err, ErrCodeNotFound) {
+		return nil, oauthError("invalid_grant", "the code is invalid, expired, or already used")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if code.ClientID != client.ID || code.RedirectURI != req.RedirectURI {
+		return nil, oauthError("invalid_grant", "the code was issued to another client or redirect_uri")
+	}
+	if code.CodeChallenge != "" && !verifyChallenge(code.CodeChallenge, req.CodeVerifier) {
+		return nil, oauthError("invalid_grant", "code_verifier does not match the code_challenge")
+	}
+
+	user, err := p.users.GetProfile(ctx, code.UserID, nil)
+	if errors.Is(err, services.ErrUserNotFound) || err == nil && !user.IsActive {
+		return nil, oauthError("invalid_grant", "the user is no longer active")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.issue(client, user, strings.Fields(code.Scope), code.Nonce)
+}
+
+// authenticateClient checks the credentials of a client; public clients have no secret
+func (p *Provider) authenticateClient(ctx context.Context, clientID, secret string) (*models.OAuthClient, error) {
+	client, err := p.store.GetClient(ctx, clientID)
+	if errors.Is(err, ErrClientNotFound) {
+		return nil, oauthError("invalid_client", "unknown client")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if client.SecretHash != "" {
+		if secret == "" || (&utils.PasswordUtils{}).VerifyPassword(client.SecretHash, secret) != nil {
+			return nil, oauthError("invalid_client", "client authentication failed")
+		}
+	}
+	return client, nil
+}
+
+// issue signs the tokens of a user for a client
+func (p *Provider) issue(client *models.OAuthClient, user models.UserInfo, scopes []string, nonce string) (*models.OAuthTokenResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(p.cfg.AccessTokenTTL)
+	tokenID, err := utils.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.NewClaims(user.ID, user.Email, user.Username, user.Role, user.Locale)
+	claims.ClientID = client.ID
+	claims.Scope = strings.Join(scopes, " ")
+	claims.Issuer = p.issuer
+	claims.Subject = user.ID
+	claims.ID = tokenID
+	claims.ExpiresAt = gojwt.NewNumericDate(expiresAt)
+	accessToken, _, err := jwt.Sign(p.jwtUtils.Secret(), claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	response := &models.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(p.cfg.AccessTokenTTL.Seconds()),
+		Scope:       claims.Scope,
+	}
+	if slices.Contains(scopes, ScopeOpenID) {
+		idClaims := gojwt.MapClaims(UserClaims(user, scopes))
+		idClaims["iss"] = p.issuer
+		idClaims["aud"] = client.ID
+		idClaims["iat"] = now.Unix()
+		idClaims["exp"] = expiresAt.Unix()
+		if nonce != "" {
+			idClaims["nonce"] = nonce
+		}
+		token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, idClaims)
+		token.Header["kid"] = p.key.id
+		if response.IDToken, err = token.SignedString(p.key.private); err != nil {
+			return nil, fmt.Errorf("failed to sign ID token: %w", err)
+		}
+	}
+	return response, nil
+}
+
+// issueService signs an access token for a service client with the requested scopes, or all of its own
+func (p *Provider) issueService(client *models.OAuthClient, scope string) (*models.OAuthTokenResponse, error) {
+	scopes := client.Scopes
+	if requested := strings.Fields(scope); len(requested) > 0 {
+		for _, s := range requested {
+			if !slices.Contains(client.Scopes, s) {
+				return nil, oauthError("invalid_scope", "scope %q is not allowed", s)
+			}
+		}
+		scopes = requested
+	}
+
+	tokenID, err := utils.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	claims := &jwt.Claims{
+		ClientID: client.ID,
+		Scope:    strings.Join(scopes, " "),
+		RegisteredClaims: gojwt.RegisteredClaims{
+			Issuer:    p.issuer,
+			Subject:   client.ID,
+			ID:        tokenID,
+			ExpiresAt: gojwt.NewNumericDate(now.Add(p.cfg.AccessTokenTTL)),
+			IssuedAt:  gojwt.NewNumericDate(now),
+			NotBefore: gojwt.NewNumericDate(now),
+		},
+	}
+	accessToken, _, err := jwt.Sign(p.jwtUtils.Secret(), claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return &models.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(p.cfg.AccessTokenTTL.Seconds()),
+		Scope:       claims.Scope,
+	}, nil
+}
+
+// UserInfo returns the claims about the user of an access token that its scopes allow
+func (p *Provider) UserInfo(ctx context.Context, claims *jwt.Claims) (map[string]interface{}, error) {
+	user, err := p.users.GetProfile(ctx, string(claims.UserID), nil)
+	if err != nil {
+		return nil, err
+	}
+	return UserClaims(user, strings.Fields(claims.Scope)), nil
+}
+
+// UserClaims returns the standard claims about user (OpenID Connect Core section 5.1) that scopes allow
+func UserClaims(user models.UserInfo, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{"sub": user.ID}
+	if slices.Contains(scopes, ScopeProfile) {
+		claims["name"] = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		claims["given_name"] = user.FirstName
+		claims["family_name"] = user.LastName
+		claims["preferred_username"] = user.Username
+		claims["updated_at"] = user.UpdatedAt.Unix()
+		if user.AvatarURL != "" {
+			claims["picture"] = user.AvatarURL
+		}
+		if user.Locale != "" {
+			claims["locale"] = user.Locale
+		}
+	}
+	if slices.Contains(scopes, ScopeEmail) {
+		claims["email"] = user.Email
+	}
+	return claims
+}
+
+// CreateClient registers a client; the secret of a confidential client is only returned here
+func (p *Provider) CreateClient(ctx context.Context, req models.CreateOAuthClientRequest) (models.OAuthClientInfo, error) {
+	if err := p.validateClient(req); err != nil {
+		return models.OAuthClientInfo{}, err
+	}
+
+	id, err := utils.RandomToken(16)
+	if err != nil {
+		return models.OAuthClientInfo{}, err
+	}
+	client := &models.OAuthClient{
+		ID:           id,
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		Scopes:       req.Scopes,
+		Trusted:      req.Trusted,
+		Service:      req.Service,
+		CreatedAt:    time.Now(),
+	}
+	if client.RedirectURIs == nil {
+		client.RedirectURIs = []string{}
+	}
+	if client.Scopes == nil {
+		client.Scopes = []string{}
+	}
+
+	var secret string
+	if !req.Public {
+		if secret, err = utils.RandomToken(32); err != nil {
+			return models.OAuthClientInfo{}, err
+		}
+		if client.SecretHash, err = (&utils.PasswordUtils{}).HashPassword(secret); err != nil {
+			return models.OAuthClientInfo{}, err
+		}
+	}
+	if err := p.store.CreateClient(ctx, client); err != nil {
+		return models.OAuthClientInfo{}, err
+	}
+
+	info := client.Info()
+	info.ClientSecret = secret
+	return info, nil
+}
+
+// validateClient checks the fields that depend on whether the client is a service
+func (p *Provider) validateClient(req models.CreateOAuthClientRequest) error {
+	allowed := SupportedScopes
+	switch {
+	case req.Service:
+		allowed = p.services
+		if req.Public {
+			return &utils.ParamError{Field: "public", Rule: "eq", Param: "false", Message: "service clients must be confidential"}
+		}
+		if len(req.RedirectURIs) > 0 {
+			return &utils.ParamError{Field: "redirect_uris", Rule: "excluded_with", Param: "service", Message: "service clients have no redirect URIs"}
+		}
+		if len(req.Scopes) == 0 {
+			return &utils.ParamError{Field: "scopes", Rule: "required", Message: "service clients need at least one scope"}
+		}
+	case len(req.RedirectURIs) == 0:
+		return &utils.ParamError{Field: "redirect_uris", Rule: "required", Message: "redirect_uris is required"}
+	}
+
+	for _, scope := range req.Scopes {
+		if !slices.Contains(allowed, scope) {
+			return &utils.ParamError{
+				Field:   "scopes",
+				Rule:    "oneof",
+				Param:   strings.Join(allowed, " "),
+				Message: fmt.Sprintf("scope %q is not supported", scope),
+			}
+		}
+	}
+	return nil
+}
+
+// ListClients returns every registered client
+func (p *Provider) ListClients(ctx context.Context) ([]models.OAuthClientInfo, error) {
+	clients, err := p.store.ListClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]models.OAuthClientInfo, len(clients))
+	for i := range clients {
+		infos[i] = clients[i].Info()
+	}
+	return infos, nil
+}
+
+// DeleteClient removes a client with its codes and consents; tokens already issued stay valid until
+// they expire
+func (p *Provider) DeleteClient(ctx context.Context, clientID string) error {
+	return p.store.DeleteClient(ctx, clientID)
+}
+
+// ListConsents returns the clients the user allowed access to
+func (p *Provider) ListConsents(ctx context.Context, userID string) ([]models.OAuthConsent, error) {
+	return p.store.ListConsents(ctx, userID)
+}
+
+// RevokeConsent withdraws the consent of the user to a client, so its next authorization asks again
+func (p *Provider) RevokeConsent(ctx context.Context, userID, clientID string) error {
+	return p.store.DeleteConsent(ctx, userID, clientID)
+}
+
+// mergeScopes returns the scopes of both lists without duplicates, in order
+func mergeScopes(scopes, more []string) []string {
+	merged := slices.Clone(scopes)
+	for _, scope := range more {
+		if !slices.Contains(merged, scope) {
+			merged = append(merged, scope)
+		}
+	}
+	return merged
+}
+
+// verifyChallenge checks a PKCE code verifier against its S256 challenge
+func verifyChallenge(challenge, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(sum[:])), []byte(challenge)) == 1
+}
@@ -0,0 +1,511 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+var (
+	// ErrClientNotFound is returned for a client that is not registered
+	ErrClientNotFound = errors.New("oauth client not found")
+	// ErrCodeNotFound is returned for an authorization code that does not exist, expired, or was used
+	ErrCodeNotFound = errors.New("authorization code not found")
+	// ErrConsentNotFound is returned when the user has not consented to the client
+	ErrConsentNotFound = errors.New("consent not found")
+	// ErrDeviceCodeNotFound is returned for a device code that does not exist, expired, or was decided
+	ErrDeviceCodeNotFound = errors.New("device code not found")
+)
+
+// Store persists clients, authorization codes, device codes, consents, and revocations
+type Store interface {
+	// CreateClient registers a client
+	CreateClient(ctx context.Context, client *models.OAuthClient) error
+	// GetClient returns the client with the ID or ErrClientNotFound
+	GetClient(ctx context.Context, id string) (*models.OAuthClient, error)
+	// ListClients returns every client, oldest first
+	ListClients(ctx context.Context) ([]models.OAuthClient, error)
+	// DeleteClient removes the client with its codes and consents, or returns ErrClientNotFound
+	DeleteClient(ctx context.Context, id string) error
+	// SaveCode stores an authorization code
+	SaveCode(ctx context.Context, code *models.OAuthCode) error
+	// TakeCode removes and returns the unexpired code with the hash, so it is used once, or returns
+	// ErrCodeNotFound
+	TakeCode(ctx context.Context, codeHash string) (*models.OAuthCode, error)
+	// GetConsent returns the consent of the user to the client or ErrConsentNotFound
+	GetConsent(ctx context.Context, userID, clientID string) (*models.OAuthConsent, error)
+	// SaveConsent creates or replaces a consent
+	SaveConsent(ctx context.Context, consent *models.OAuthConsent) error
+	// ListConsents returns the consents of the user
+	ListConsents(ctx context.Context, userID string) ([]models.OAuthConsent, error)
+	// DeleteConsent withdraws the consent of the user to the client, or returns ErrConsentNotFound
+	DeleteConsent(ctx context.Context, userID, clientID string) error
+	// RevokeToken records the revocation of an access token; revoking a token twice is not an error
+	RevokeToken(ctx context.Context, revocation *models.OAuthRevocation) error
+	// IsRevoked reports whether the access token with the ID was revoked
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+	// SaveDeviceCode stores a pending device code
+	SaveDeviceCode(ctx context.Context, code *models.OAuthDeviceCode) error
+	// GetDeviceCode returns the device code with the hash, which may have expired, or ErrDeviceCodeNotFound
+	GetDeviceCode(ctx context.Context, deviceCodeHash string) (*models.OAuthDeviceCode, error)
+	// FindDeviceCode returns the unexpired pending device code with the user code or ErrDeviceCodeNotFound
+	FindDeviceCode(ctx context.Context, userCode string) (*models.OAuthDeviceCode, error)
+	// DecideDeviceCode records the decision of the user on a pending device code, or returns
+	// ErrDeviceCodeNotFound when it was already decided
+	DecideDeviceCode(ctx context.Context, userCode, userID, status string) error
+	// TouchDeviceCode records when the device last polled
+	TouchDeviceCode(ctx context.Context, deviceCodeHash string, polledAt time.Time) error
+	// DeleteDeviceCode removes the device code, or returns ErrDeviceCodeNotFound so it is exchanged once
+	DeleteDeviceCode(ctx context.Context, deviceCodeHash string) error
+}
+
+// PostgresStore persists OAuth data in PostgreSQL
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the tables are created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// CreateClient inserts the client
+func (s *PostgresStore) CreateClient(ctx context.Context, client *models.OAuthClient) error {
+	return s.db.WithContext(ctx).Create(client).Error
+}
+
+// GetClient returns the client with the ID
+func (s *PostgresStore) GetClient(ctx context.Context, id string) (*models.OAuthClient, error) {
+	var client models.OAuthClient
+	err := s.db.WithContext(ctx).First(&client, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrClientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// ListClients returns every client, oldest first
+func (s *PostgresStore) ListClients(ctx context.Context) ([]models.OAuthClient, error) {
+	var clients []models.OAuthClient
+	err := s.db.WithContext(ctx).Order("created_at, id").Find(&clients).Error
+	return clients, err
+}
+
+// DeleteClient removes the client, its codes, and its consents in one transaction
+func (s *PostgresStore) DeleteClient(ctx context.Context, id string) error {
+	return s.db.WithTransaction(ctx, func(tx *database.PostgresDB) error {
+		result := tx.Where("id = ?", id).Delete(&models.OAuthClient{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrClientNotFound
+		}
+		if err := tx.Where("client_id = ?", id).Delete(&models.OAuthCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("client_id = ?", id).Delete(&models.OAuthDeviceCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("client_id = ?", id).Delete(&models.OAuthConsent{}).Error
+	})
+}
+
+// SaveCode inserts the code
+func (s *PostgresStore) SaveCode(ctx context.Context, code *models.OAuthCode) error {
+	return s.db.WithContext(ctx).Create(code).Error
+}
+
+// TakeCode deletes the code and returns the deleted row, so concurrent exchanges of one code cannot both
+// succeed
+func (s *PostgresStore) TakeCode(ctx context.Context, codeHash string) (*models.OAuthCode, error) {
+	var codes []models.OAuthCode
+	err := s.db.WithContext(ctx).Clauses(clause.Returning{}).Where("code_hash = ?", codeHash).Delete(&codes).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(codes) == 0 || time.Now().After(codes[0].ExpiresAt) {
+		return nil, ErrCodeNotFound
+	}
+	return &codes[0], nil
+}
+
+// GetConsent returns the consent of the user to the client
+func (s *PostgresStore) GetConsent(ctx context.Context, userID, clientID string) (*models.OAuthConsent, error) {
+	var consent models.OAuthConsent
+	err := s.db.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, clientID).First(&consent).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrConsentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+// SaveConsent upserts the consent by user and client
+func (s *PostgresStore) SaveConsent(ctx context.Context, consent *models.OAuthConsent) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(consent).Error
+}
+
+// ListConsents returns the consents of the user, most recent first
+func (s *PostgresStore) ListConsents(ctx context.Context, userID string) ([]models.OAuthConsent, error) {
+	var consents []models.OAuthConsent
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("updated_at DESC").Find(&consents).Error
+	return consents, err
+}
+
+// DeleteConsent removes the consent of the user to the client
+func (s *PostgresStore) DeleteConsent(ctx context.Context, userID, clientID string) error {
+	result := s.db.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, clientID).Delete(&models.OAuthConsent{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConsentNotFound
+	}
+	return nil
+}
+
+// RevokeToken inserts the revocation, first dropping those of tokens that have expired since
+func (s *PostgresStore) RevokeToken(ctx context.Context, revocation *models.OAuthRevocation) error {
+	db := s.db.WithContext(ctx)
+	if err := db.Where("expires_at <= ?", time.Now()).Delete(&models.OAuthRevocation{}).Error; err != nil {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(revocation).Error
+}
+
+// IsRevoked looks up the revocation of the token
+func (s *PostgresStore) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.OAuthRevocation{}).Where("token_id = ?", tokenID).Count(&count).Error
+	return count > 0, err
+}
+
+// SaveDeviceCode inserts the device code, first dropping expired ones
+func (s *PostgresStore) SaveDeviceCode(ctx context.Context, code *models.OAuthDeviceCode) error {
+	db := s.db.WithContext(ctx)
+	if err := db.Where("expires_at <= ?", time.Now()).Delete(&models.OAuthDeviceCode{}).Error; err != nil {
+		return err
+	}
+	return db.Create(code).Error
+}
+
+// GetDeviceCode returns the device code with the hash
+func (s *PostgresStore) GetDeviceCode(ctx context.Context, deviceCodeHash string) (*models.OAuthDeviceCode, error) {
+	var code models.OAuthDeviceCode
+	err := s.db.WithContext(ctx).Where("device_code_hash = ?", deviceCodeHash).First(&code).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeviceCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// FindDeviceCode returns the unexpired pending device code with the user code
+func (s *PostgresStore) FindDeviceCode(ctx context.Context, userCode string) (*models.OAuthDeviceCode, error) {
+	var code models.OAuthDeviceCode
+	err := s.db.WithContext(ctx).
+		Where("user_code = ? AND status = ? AND expires_at > ?", userCode, models.DeviceCodePending, time.Now()).
+		First(&code).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeviceCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// DecideDeviceCode updates the device code only while it is pending, so a code is decided once
+func (s *PostgresStore) DecideDeviceCode(ctx context.Context, userCode, userID, status string) error {
+	result := s.db.WithContext(ctx).Model(&models.OAuthDeviceCode{}).
+		Where("user_code = ? AND status = ? AND expires_at > ?", userCode, models.DeviceCodePending, time.Now()).
+		Updates(map[string]any{"status": status, "user_id": userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDeviceCodeNotFound
+	}
+	return nil
+}
+
+// TouchDeviceCode sets the last poll time of the device code
+func (s *PostgresStore) TouchDeviceCode(ctx context.Context, deviceCodeHash string, polledAt time.Time) error {
+	return s.db.WithContext(ctx).Model(&models.OAuthDeviceCode{}).
+		Where("device_code_hash = ?", deviceCodeHash).
+		Update("last_polled_at", polledAt).Error
+}
+
+// DeleteDeviceCode removes the device code
+func (s *PostgresStore) DeleteDeviceCode(ctx context.Context, deviceCodeHash string) error {
+	result := s.db.WithContext(ctx).Where("device_code_hash = ?", deviceCodeHash).Delete(&models.OAuthDeviceCode{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDeviceCodeNotFound
+	}
+	return nil
+}
+
+// MongoStore persists OAuth data in MongoDB; codes, device codes, and revocations expire through TTL
+// indexes
+type MongoStore struct {
+	clients     *mongo.Collection
+	codes       *mongo.Collection
+	deviceCodes *mongo.Collection
+	consents    *mongo.Collection
+	revocations *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store and ensures its indexes exist
+func NewMongoStore(ctx context.Context, db *database.MongoDB) (*MongoStore, error) {
+	s := &MongoStore{
+		clients:     db.Collection("oauth_clients"),
+		codes:       db.Collection("oauth_codes"),
+		deviceCodes: db.Collection("oauth_device_codes"),
+		consents:    db.Collection("oauth_consents"),
+		revocations: db.Collection("oauth_revocations"),
+	}
+	for _, collection := range []*mongo.Collection{s.codes, s.deviceCodes, s.revocations} {
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err := s.consents.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "client_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.deviceCodes.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_code", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// CreateClient inserts the client
+func (s *MongoStore) CreateClient(ctx context.Context, client *models.OAuthClient) error {
+	_, err := s.clients.InsertOne(ctx, client)
+	return err
+}
+
+// GetClient returns the client with the ID
+func (s *MongoStore) GetClient(ctx context.Context, id string) (*models.OAuthClient, error) {
+	var client models.OAuthClient
+	err := s.clients.FindOne(ctx, bson.M{"_id": id}).Decode(&client)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrClientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// ListClients returns every client, oldest first
+func (s *MongoStore) ListClients(ctx context.Context) ([]models.OAuthClient, error) {
+	cursor, err := s.clients.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	clients := []models.OAuthClient{}
+	if err := cursor.All(ctx, &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// DeleteClient removes the client, then its codes and consents
+func (s *MongoStore) DeleteClient(ctx context.Context, id string) error {
+	result, err := s.clients.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrClientNotFound
+	}
+	if _, err := s.codes.DeleteMany(ctx, bson.M{"client_id": id}); err != nil {
+		return err
+	}
+	if _, err := s.deviceCodes.DeleteMany(ctx, bson.M{"client_id": id}); err != nil {
+		return err
+	}
+	_, err = s.consents.DeleteMany(ctx, bson.M{"client_id": id})
+	return err
+}
+
+// SaveCode inserts the code
+func (s *MongoStore) SaveCode(ctx context.Context, code *models.OAuthCode) error {
+	_, err := s.codes.InsertOne(ctx, code)
+	return err
+}
+
+// TakeCode deletes the code atomically and returns it
+func (s *MongoStore) TakeCode(ctx context.Context, codeHash string) (*models.OAuthCode, error) {
+	var code models.OAuthCode
+	err := s.codes.FindOneAndDelete(ctx, bson.M{"_id": codeHash}).Decode(&code)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The TTL monitor runs periodically, so an expired code may still be stored
+	if time.Now().After(code.ExpiresAt) {
+		return nil, ErrCodeNotFound
+	}
+	return &code, nil
+}
+
+// GetConsent returns the consent of the user to the client
+func (s *MongoStore) GetConsent(ctx context.Context, userID, clientID string) (*models.OAuthConsent, error) {
+	var consent models.OAuthConsent
+	err := s.consents.FindOne(ctx, bson.M{"user_id": userID, "client_id": clientID}).Decode(&consent)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrConsentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+// SaveConsent upserts the consent by user and client
+func (s *MongoStore) SaveConsent(ctx context.Context, consent *models.OAuthConsent) error {
+	_, err := s.consents.ReplaceOne(ctx,
+		bson.M{"user_id": consent.UserID, "client_id": consent.ClientID},
+		consent,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// ListConsents returns the consents of the user, most recent first
+func (s *MongoStore) ListConsents(ctx context.Context, userID string) ([]models.OAuthConsent, error) {
+	cursor, err := s.consents.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	consents := []models.OAuthConsent{}
+	if err := cursor.All(ctx, &consents); err != nil {
+		return nil, err
+	}
+	return consents, nil
+}
+
+// DeleteConsent removes the consent of the user to the client
+func (s *MongoStore) DeleteConsent(ctx context.Context, userID, clientID string) error {
+	result, err := s.consents.DeleteOne(ctx, bson.M{"user_id": userID, "client_id": clientID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrConsentNotFound
+	}
+	return nil
+}
+
+// RevokeToken upserts the revocation
+func (s *MongoStore) RevokeToken(ctx context.Context, revocation *models.OAuthRevocation) error {
+	_, err := s.revocations.ReplaceOne(ctx, bson.M{"_id": revocation.TokenID}, revocation, options.Replace().SetUpsert(true))
+	return err
+}
+
+// IsRevoked looks up the revocation of the token
+func (s *MongoStore) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	count, err := s.revocations.CountDocuments(ctx, bson.M{"_id": tokenID}, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// SaveDeviceCode inserts the device code
+func (s *MongoStore) SaveDeviceCode(ctx context.Context, code *models.OAuthDeviceCode) error {
+	_, err := s.deviceCodes.InsertOne(ctx, code)
+	return err
+}
+
+// GetDeviceCode returns the device code with the hash
+func (s *MongoStore) GetDeviceCode(ctx context.Context, deviceCodeHash string) (*models.OAuthDeviceCode, error) {
+	return s.findDeviceCode(ctx, bson.M{"_id": deviceCodeHash})
+}
+
+// FindDeviceCode returns the unexpired pending device code with the user code; the TTL monitor runs
+// periodically, so expiry is also checked in the filter
+func (s *MongoStore) FindDeviceCode(ctx context.Context, userCode string) (*models.OAuthDeviceCode, error) {
+	return s.findDeviceCode(ctx, bson.M{
+		"user_code":  userCode,
+		"status":     models.DeviceCodePending,
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+}
+
+func (s *MongoStore) findDeviceCode(ctx context.Context, filter bson.M) (*models.OAuthDeviceCode, error) {
+	var code models.OAuthDeviceCode
+	err := s.deviceCodes.FindOne(ctx, filter).Decode(&code)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrDeviceCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// DecideDeviceCode updates the device code only while it is pending, so a code is decided once
+func (s *MongoStore) DecideDeviceCode(ctx context.Context, userCode, userID, status string) error {
+	result, err := s.deviceCodes.UpdateOne(ctx,
+		bson.M{"user_code": userCode, "status": models.DeviceCodePending, "expires_at": bson.M{"$gt": time.Now()}},
+		bson.M{"$set": bson.M{"status": status, "user_id": userID}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrDeviceCodeNotFound
+	}
+	return nil
+}
+
+// TouchDeviceCode sets the last poll time of the device code
+func (s *MongoStore) TouchDeviceCode(ctx context.Context, deviceCodeHash string, polledAt time.Time) error {
+	_, err := s.deviceCodes.UpdateOne(ctx, bson.M{"_id": deviceCodeHash}, bson.M{"$set": bson.M{"last_polled_at": polledAt}})
+	return err
+}
+
+// DeleteDeviceCode removes the device code
+func (s *MongoStore) DeleteDeviceCode(ctx context.Context, deviceCodeHash string) error {
+	result, err := s.deviceCodes.DeleteOne(ctx, bson.M{"_id": deviceCodeHash})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrDeviceCodeNotFound
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+
+	"go-backend-template/jwt"
+	"go-backend-template/models"
+)
+
+// ErrTokenRevoked is returned by Verify for an access token that was revoked before it expired
+var ErrTokenRevoked = errors.New("access token revoked")
+
+// parse verifies the signature and times of a token issued by the API, accepting the previous JWT secret
+// during rotation
+func (p *Provider) parse(token string) (*jwt.Claims, error) {
+	var claims *jwt.Claims
+	var err error
+	for _, secret := range p.jwtUtils.VerificationSecrets() {
+		if claims, err = jwt.ValidateToken(secret, token); err == nil {
+			return claims, nil
+		}
+	}
+	return nil, err
+}
+
+// Verify returns the claims of an unrevoked access token issued to a client
+func (p *Provider) Verify(ctx context.Context, token string) (*jwt.Claims, error) {
+	claims, err := p.parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.ClientID == "" {
+		return nil, errors.New("not an OAuth access token")
+	}
+	revoked, err := p.store.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// TokenActionRequest is a request to the introspection or revocation endpoint; the client credentials
+// come from the form or from HTTP Basic authentication
+type TokenActionRequest struct {
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+// Introspect describes a token issued by the API, either to a client or by signing in, to a resource
+// server (RFC 7662). Only confidential clients may introspect, as the response identifies the user.
+// Errors the caller caused are an *Error.
+func (p *Provider) Introspect(ctx context.Context, req TokenActionRequest) (models.OAuthIntrospection, error) {
+	client, err := p.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return models.OAuthIntrospection{}, err
+	}
+	if client.SecretHash == "" {
+		return models.OAuthIntrospection{}, oauthError("invalid_client", "public clients cannot introspect tokens")
+	}
+	if req.Token == "" {
+		return models.OAuthIntrospection{}, oauthError("invalid_request", "token is required")
+	}
+
+	claims, err := p.parse(req.Token)
+	if err != nil {
+		return models.OAuthIntrospection{Active: false}, nil
+	}
+	if claims.ClientID != "" {
+		revoked, err := p.store.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return models.OAuthIntrospection{}, err
+		}
+		if revoked {
+			return models.OAuthIntrospection{Active: false}, nil
+		}
+	}
+
+	info := models.OAuthIntrospection{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Username:  claims.Username,
+		TokenType: "Bearer",
+		Subject:   claims.Subject,
+		Issuer:    claims.Issuer,
+		TokenID:   claims.ID,
+	}
+	if info.Subject == "" {
+		info.Subject = string(claims.UserID)
+	}
+	if claims.ExpiresAt != nil {
+		info.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		info.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.NotBefore != nil {
+		info.NotBefore = claims.NotBefore.Unix()
+	}
+	return info, nil
+}
+
+// Revoke revokes an access token the client was issued (RFC 7009). Invalid and expired tokens need no
+// revocation and are accepted; tokens from signing in cannot be revoked here, and a client may only
+// revoke its own tokens. Errors the caller caused are an *Error.
+func (p *Provider) Revoke(ctx context.Context, req TokenActionRequest) error {
+	client, err := p.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return err
+	}
+	if req.Token == "" {
+		return oauthError("invalid_request", "token is required")
+	}
+
+	claims, err := p.parse(req.Token)
+	if err != nil {
+		return nil
+	}
+	if claims.ClientID == "" || claims.ID == "" || claims.ExpiresAt == nil {
+		return oauthError("unsupported_token_type", "only access tokens issued to OAuth clients can be revoked")
+	}
+	if claims.ClientID != client.ID {
+		return oauthError("unauthorized_client", "the token was issued to another client")
+	}
+
+	err = p.store.RevokeToken(ctx, &models.OAuthRevocation{
+		TokenID:   claims.ID,
+		ClientID:  claims.ClientID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	if err != nil {
+		return err
+	}
+	p.logger.Info("OAuth access token revoked", "client_id", claims.ClientID, "user_id", string(claims.UserID), "jti", claims.ID)
+	return nil
+}
@@ -0,0 +1,293 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// parameterKeys stay on an OpenAPI 3 parameter; every other Swagger 2.0 key describes the value and moves into its schema
+var parameterKeys = map[string]bool{
+	"name":        true,
+	"in":          true,
+	"description": true,
+	"required":    true,
+}
+
+// Convert translates the Swagger 2.0 document produced by swag into an OpenAPI 3.0 document.
+// It covers the constructs swag emits: body/query/header/path/formData parameters, per-operation
+// consumes/produces, definitions, and API key or basic security schemes.
+func Convert(swagger []byte) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(swagger, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse swagger document: %w", err)
+	}
+	if version, _ := doc["swagger"].(string); version != "2.0" {
+		return nil, fmt.Errorf("unsupported swagger version %q", version)
+	}
+
+	// References move from #/definitions to #/components/schemas
+	doc = rewriteRefs(doc).(map[string]interface{})
+
+	globalConsumes := stringList(doc["consumes"], "application/json")
+	globalProduces := stringList(doc["produces"], "application/json")
+
+	result := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    doc["info"],
+		"servers": servers(doc),
+		"paths":   map[string]interface{}{},
+	}
+	if tags, ok := doc["tags"]; ok {
+		result["tags"] = tags
+	}
+	if security, ok := doc["security"]; ok {
+		result["security"] = security
+	}
+
+	components := map[string]interface{}{}
+	if definitions, ok := doc["definitions"].(map[string]interface{}); ok {
+		components["schemas"] = definitions
+	}
+	if schemes, ok := doc["securityDefinitions"].(map[string]interface{}); ok {
+		components["securitySchemes"] = securitySchemes(schemes)
+	}
+	if len(components) > 0 {
+		result["components"] = components
+	}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	converted := result["paths"].(map[string]interface{})
+	for path, item := range paths {
+		operations, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		convertedItem := map[string]interface{}{}
+		for method, value := range operations {
+			operation, ok := value.(map[string]interface{})
+			if !ok {
+				convertedItem[method] = value
+				continue
+			}
+			convertedItem[method] = convertOperation(operation, globalConsumes, globalProduces)
+		}
+		converted[path] = convertedItem
+	}
+
+	return result, nil
+}
+
+// servers derives the server list from schemes, host, and basePath; without a host the URL is relative
+func servers(doc map[string]interface{}) []interface{} {
+	basePath, _ := doc["basePath"].(string)
+	host, _ := doc["host"].(string)
+	if host == "" {
+		if basePath == "" {
+			basePath = "/"
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+
+	var list []interface{}
+	for _, scheme := range stringList(doc["schemes"], "http") {
+		list = append(list, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return list
+}
+
+// securitySchemes converts Swagger 2.0 security definitions to OpenAPI 3 security schemes
+func securitySchemes(definitions map[string]interface{}) map[string]interface{} {
+	schemes := map[string]interface{}{}
+	for name, value := range definitions {
+		definition, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		scheme := map[string]interface{}{}
+		for key, v := range definition {
+			scheme[key] = v
+		}
+		if definition["type"] == "basic" {
+			scheme = map[string]interface{}{"type": "http", "scheme": "basic"}
+			if description, ok := definition["description"]; ok {
+				scheme["description"] = description
+			}
+		}
+		schemes[name] = scheme
+	}
+	return schemes
+}
+
+// convertOperation moves body and form parameters into requestBody and response schemas into content
+func convertOperation(operation map[string]interface{}, globalConsumes, globalProduces []string) map[string]interface{} {
+	consumes := stringList(operation["consumes"], "")
+	if len(consumes) == 0 {
+		consumes = globalConsumes
+	}
+	produces := stringList(operation["produces"], "")
+	if len(produces) == 0 {
+		produces = globalProduces
+	}
+
+	converted := map[string]interface{}{}
+	for key, value := range operation {
+		switch key {
+		case "consumes", "produces", "parameters", "responses":
+		default:
+			converted[key] = value
+		}
+	}
+
+	var parameters []interface{}
+	formSchema := map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	var formRequired []interface{}
+	hasForm := false
+
+	list, _ := operation["parameters"].([]interface{})
+	for _, value := range list {
+		parameter, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		switch parameter["in"] {
+		case "body":
+			body := map[string]interface{}{
+				"content":  mediaTypes(consumes, parameter["schema"]),
+				"required": parameter["required"] == true,
+			}
+			if description, ok := parameter["description"]; ok {
+				body["description"] = description
+			}
+			converted["requestBody"] = body
+		case "formData":
+			hasForm = true
+			name, _ := parameter["name"].(string)
+			formSchema["properties"].(map[string]interface{})[name] = parameterSchema(parameter)
+			if parameter["required"] == true {
+				formRequired = append(formRequired, name)
+			}
+		default:
+			param := map[string]interface{}{}
+			for key, v := range parameter {
+				if parameterKeys[key] {
+					param[key] = v
+				}
+			}
+			param["schema"] = parameterSchema(parameter)
+			parameters = append(parameters, param)
+		}
+	}
+
+	if hasForm {
+		if len(formRequired) > 0 {
+			formSchema["required"] = formRequired
+		}
+		formTypes := []string{"application/x-www-form-urlencoded"}
+		for _, mediaType := range consumes {
+			if mediaType == "multipart/form-data" {
+				formTypes = []string{mediaType}
+			}
+		}
+		converted["requestBody"] = map[string]interface{}{"content": mediaTypes(formTypes, formSchema)}
+	}
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+
+	responses := map[string]interface{}{}
+	if list, ok := operation["responses"].(map[string]interface{}); ok {
+		for status, value := range list {
+			response, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			convertedResponse := map[string]interface{}{"description": response["description"]}
+			if convertedResponse["description"] == nil {
+				convertedResponse["description"] = ""
+			}
+			if schema, ok := response["schema"]; ok {
+				convertedResponse["content"] = mediaTypes(produces, schema)
+			}
+			if headers, ok := response["headers"].(map[string]interface{}); ok {
+				convertedHeaders := map[string]interface{}{}
+				for name, header := range headers {
+					if h, ok := header.(map[string]interface{}); ok {
+						convertedHeaders[name] = map[string]interface{}{"description": h["description"], "schema": parameterSchema(h)}
+					}
+				}
+				convertedResponse["headers"] = convertedHeaders
+			}
+			responses[status] = convertedResponse
+		}
+	}
+	converted["responses"] = responses
+
+	return converted
+}
+
+// parameterSchema collects the value-describing keys of a non-body parameter into a schema
+func parameterSchema(parameter map[string]interface{}) map[string]interface{} {
+	schema := map[string]interface{}{}
+	for key, value := range parameter {
+		if !parameterKeys[key] && key != "collectionFormat" && key != "allowEmptyValue" {
+			schema[key] = value
+		}
+	}
+	if schema["type"] == "file" {
+		schema["type"] = "string"
+		schema["format"] = "binary"
+	}
+	return schema
+}
+
+// mediaTypes maps each media type to the same schema
+func mediaTypes(types []string, schema interface{}) map[string]interface{} {
+	content := map[string]interface{}{}
+	for _, mediaType := range types {
+		// swag reports XML as text/xml; the API negotiates application/xml
+		if mediaType == "text/xml" {
+			mediaType = "application/xml"
+		}
+		content[mediaType] = map[string]interface{}{"schema": schema}
+	}
+	return content
+}
+
+// rewriteRefs points every $ref at components/schemas
+func rewriteRefs(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				v[key] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			v[key] = rewriteRefs(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = rewriteRefs(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// stringList reads a JSON string array, falling back to a single default when absent
+func stringList(value interface{}, fallback string) []string {
+	items, _ := value.([]interface{})
+	var list []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	if len(list) == 0 && fallback != "" {
+		list = []string{fallback}
+	}
+	return list
+}
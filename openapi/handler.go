@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
+)
+
+// redocPage renders the OpenAPI document with Redoc
+const redocPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>API Reference</title>
+  <meta charset="utf-8"/>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="/openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>`
+
+// Spec converts the registered swag document to OpenAPI 3 once and serves it on every request
+type Spec struct {
+	once     sync.Once
+	document map[string]interface{}
+	err      error
+}
+
+// NewSpec creates a lazily converted OpenAPI 3 document for the swag-registered spec
+func NewSpec() *Spec {
+	return &Spec{}
+}
+
+// Document returns the converted OpenAPI 3 document
+func (s *Spec) Document() (map[string]interface{}, error) {
+	s.once.Do(func() {
+		raw, err := swag.ReadDoc()
+		if err != nil {
+			s.err = err
+			return
+		}
+		s.document, s.err = Convert([]byte(raw))
+	})
+	return s.document, s.err
+}
+
+// Handler serves the OpenAPI 3 document as JSON
+func (s *Spec) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		document, err := s.Document()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, document)
+	}
+}
+
+// Redoc serves a Redoc page for /openapi.json
+func Redoc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(redocPage))
+	}
+}
@@ -0,0 +1,245 @@
+// Package operations runs long requests in the background: the API stores each as a queued operation and
+// answers 202, the workers of the all and worker run modes claim and run it, and the client polls it for
+// its progress and result. Operations survive restarts; one whose worker stops is taken over by another.
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// progressInterval is how often a running operation saves its progress, which also shows its worker is
+// alive
+const progressInterval = time.Second
+
+var (
+	// ErrNotFound is returned when no operation has the ID
+	ErrNotFound = errors.New("operation not found")
+	// ErrLost is returned when saving an operation another worker has claimed since
+	ErrLost = errors.New("operation claimed by another worker")
+	// ErrUnknownKind is returned when enqueuing an operation of a kind without a task
+	ErrUnknownKind = errors.New("unknown operation kind")
+)
+
+// Task runs an operation from its params and returns the result, which is marshaled to JSON. It reports
+// the items done out of total through progress. A task may run more than once when its worker stops
+// midway, so it must tolerate repeats.
+type Task func(ctx context.Context, params json.RawMessage, progress func(done, total int)) (interface{}, error)
+
+// Options configure a Queue
+type Options struct {
+	// Workers is the number of operations run at once by this process
+	Workers int
+	// PollInterval is how often an idle worker looks for queued operations
+	PollInterval time.Duration
+	// StaleAfter is how long a running operation may go without saving its progress before another
+	// worker takes it over
+	StaleAfter time.Duration
+	// MaxAttempts is how many times an operation is taken over before it fails
+	MaxAttempts int
+}
+
+// Queue enqueues operations and runs them with the tasks registered for their kinds
+type Queue struct {
+	store  Store
+	opts   Options
+	logger utils.Logger
+	tasks  map[string]Task
+	wg     sync.WaitGroup
+}
+
+// NewQueue creates a queue of the operations in store
+func NewQueue(store Store, opts Options, logger utils.Logger) *Queue {
+	opts.Workers = max(opts.Workers, 1)
+	opts.MaxAttempts = max(opts.MaxAttempts, 1)
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.StaleAfter <= progressInterval {
+		opts.StaleAfter = time.Minute
+	}
+	return &Queue{store: store, opts: opts, logger: logger, tasks: make(map[string]Task)}
+}
+
+// Register sets the task that runs the operations of kind; register every kind before Start
+func (q *Queue) Register(kind string, task Task) {
+	q.tasks[kind] = task
+}
+
+// Enqueue stores a queued operation of kind started by ownerID, with params marshaled to JSON for the task
+func (q *Queue) Enqueue(ctx context.Context, kind, ownerID string, params interface{}) (*models.Operation, error) {
+	if _, ok := q.tasks[kind]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	op := &models.Operation{
+		ID:        id.String(),
+		Kind:      kind,
+		Status:    models.OperationQueued,
+		OwnerID:   ownerID,
+		Params:    data,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := q.store.Create(ctx, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// Get returns the operation with id or ErrNotFound
+func (q *Queue) Get(ctx context.Context, id string) (*models.Operation, error) {
+	return q.store.Get(ctx, id)
+}
+
+// Start runs the workers in the background until ctx is canceled. An operation running then is put back
+// in the queue for another worker.
+func (q *Queue) Start(ctx context.Context) {
+	for range q.opts.Workers {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.work(ctx)
+		}()
+	}
+}
+
+// Wait blocks until the workers have stopped after their context was canceled
+func (q *Queue) Wait() {
+	q.wg.Wait()
+}
+
+// work claims and runs operations, waiting PollInterval whenever the queue is empty
+func (q *Queue) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := time.Now().UTC()
+		op, err := q.store.Claim(ctx, now, now.Add(-q.opts.StaleAfter))
+		if err == nil {
+			q.run(ctx, op)
+			continue
+		}
+		if !errors.Is(err, ErrNotFound) && ctx.Err() == nil {
+			q.logger.Error("Failed to claim an operation", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(q.opts.PollInterval):
+		}
+	}
+}
+
+// run runs a claimed operation, saving its progress every progressInterval, and saves the outcome
+func (q *Queue) run(ctx context.Context, op *models.Operation) {
+	claimed := op.Attempts
+	task, ok := q.tasks[op.Kind]
+	switch {
+	case !ok:
+		q.finish(op, claimed, nil, fmt.Errorf("%w: %s", ErrUnknownKind, op.Kind))
+		return
+	case op.Attempts > q.opts.MaxAttempts:
+		q.finish(op, claimed, nil, fmt.Errorf("abandoned after %d attempts", q.opts.MaxAttempts))
+		return
+	}
+
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	progress := func(done, total int) {
+		mu.Lock()
+		op.Done, op.Total = done, total
+		mu.Unlock()
+	}
+
+	stopped, exited := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopped:
+				return
+			case <-ticker.C:
+				mu.Lock()
+				op.UpdatedAt = time.Now().UTC()
+				snapshot := *op
+				mu.Unlock()
+				if err := q.store.Save(ctx, &snapshot, claimed); errors.Is(err, ErrLost) {
+					q.logger.Warn("Another worker took over the operation", "operation_id", op.ID)
+					cancel()
+					return
+				} else if err != nil && ctx.Err() == nil {
+					q.logger.Error("Failed to save the progress of an operation", "operation_id", op.ID, "error", err)
+				}
+			}
+		}
+	}()
+
+	result, err := task(taskCtx, op.Params, progress)
+	close(stopped)
+	<-exited
+
+	mu.Lock()
+	defer mu.Unlock()
+	if ctx.Err() != nil {
+		q.release(op, claimed)
+		return
+	}
+	if taskCtx.Err() != nil {
+		return
+	}
+	q.finish(op, claimed, result, err)
+}
+
+// finish saves the result, or the failure, of an operation
+func (q *Queue) finish(op *models.Operation, claimed int, result interface{}, err error) {
+	now := time.Now().UTC()
+	op.UpdatedAt, op.FinishedAt = now, &now
+	op.Status = models.OperationSucceeded
+	if err == nil {
+		op.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		q.logger.Error("Operation failed", "operation_id", op.ID, "kind", op.Kind, "error", err)
+		op.Status, op.Result, op.Error = models.OperationFailed, nil, "The operation failed"
+	} else {
+		q.logger.Info("Operation succeeded", "operation_id", op.ID, "kind", op.Kind, "attempts", op.Attempts)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := q.store.Save(ctx, op, claimed); err != nil {
+		q.logger.Error("Failed to save the outcome of an operation", "operation_id", op.ID, "error", err)
+	}
+}
+
+// release puts an operation interrupted by shutdown back in the queue, without counting the attempt
+func (q *Queue) release(op *models.Operation, claimed int) {
+	op.Status, op.Attempts, op.UpdatedAt = models.OperationQueued, op.Attempts-1, time.Now().UTC()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := q.store.Save(ctx, op, claimed); err != nil {
+		q.logger.Error("Failed to requeue an interrupted operation", "operation_id", op.ID, "error", err)
+	}
+}
@@ -0,0 +1,261 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// claimRetries bounds the candidates a PostgreSQL claim tries when other workers take them first
+const claimRetries = 5
+
+// Store persists operations. Every claim increments Attempts, which then identifies the claim: a save
+// only applies while the operation has the attempts it was claimed with.
+type Store interface {
+	// Create inserts a queued operation
+	Create(ctx context.Context, op *models.Operation) error
+	// Get returns the operation with id or ErrNotFound
+	Get(ctx context.Context, id string) (*models.Operation, error)
+	// Claim takes the oldest queued operation, or a running one not updated since staleBefore, whose
+	// worker is taken to have stopped; it sets it running with one more attempt and returns it, or
+	// returns ErrNotFound when there is none
+	Claim(ctx context.Context, now, staleBefore time.Time) (*models.Operation, error)
+	// Save updates the status, attempts, progress, result, error, and times of an operation while its
+	// attempts are still claimed, or returns ErrLost when another worker has claimed it since
+	Save(ctx context.Context, op *models.Operation, claimed int) error
+}
+
+// MemoryStore is an in-process Store, suitable for development and tests; operations are lost on restart
+type MemoryStore struct {
+	mu         sync.Mutex
+	operations []*models.Operation
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Create adds the operation
+func (s *MemoryStore) Create(ctx context.Context, op *models.Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *op
+	s.operations = append(s.operations, &stored)
+	return nil
+}
+
+// Get returns the operation with id
+func (s *MemoryStore) Get(ctx context.Context, id string) (*models.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stored := range s.operations {
+		if stored.ID == id {
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Claim takes the oldest claimable operation as Store describes
+func (s *MemoryStore) Claim(ctx context.Context, now, staleBefore time.Time) (*models.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stored := range s.operations {
+		if claimable(stored, staleBefore) {
+			stored.Status = models.OperationRunning
+			stored.Attempts++
+			stored.UpdatedAt = now
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Save replaces the stored operation while it has the claimed attempts
+func (s *MemoryStore) Save(ctx context.Context, op *models.Operation, claimed int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stored := range s.operations {
+		if stored.ID == op.ID {
+			if stored.Attempts != claimed {
+				return ErrLost
+			}
+			*stored = *op
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// claimable reports whether a worker may claim the operation
+func claimable(op *models.Operation, staleBefore time.Time) bool {
+	return op.Status == models.OperationQueued || op.Status == models.OperationRunning && op.UpdatedAt.Before(staleBefore)
+}
+
+// PostgresStore persists operations in PostgreSQL
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the table is created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Create inserts the operation
+func (s *PostgresStore) Create(ctx context.Context, op *models.Operation) error {
+	return s.db.WithContext(ctx).Create(op).Error
+}
+
+// Get returns the operation with id
+func (s *PostgresStore) Get(ctx context.Context, id string) (*models.Operation, error) {
+	var op models.Operation
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&op).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// Claim reads the oldest claimable operation and takes it with an update conditional on its attempts,
+// so of two workers reading the same operation only one takes it; the other tries the next candidate
+func (s *PostgresStore) Claim(ctx context.Context, now, staleBefore time.Time) (*models.Operation, error) {
+	db := s.db.WithContext(ctx)
+	for range claimRetries {
+		var op models.Operation
+		err := db.Where("status = ? OR (status = ? AND updated_at < ?)", models.OperationQueued, models.OperationRunning, staleBefore).
+			Order("created_at, id").First(&op).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result := db.Model(&models.Operation{}).Where("id = ? AND attempts = ?", op.ID, op.Attempts).
+			Updates(map[string]interface{}{"status": models.OperationRunning, "attempts": op.Attempts + 1, "updated_at": now})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			op.Status, op.Attempts, op.UpdatedAt = models.OperationRunning, op.Attempts+1, now
+			return &op, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Save updates the operation while it has the claimed attempts
+func (s *PostgresStore) Save(ctx context.Context, op *models.Operation, claimed int) error {
+	result := s.db.WithContext(ctx).Model(op).Where("attempts = ?", claimed).
+		Select("status", "attempts", "done", "total", "result", "error", "updated_at", "finished_at").Updates(op)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLost
+	}
+	return nil
+}
+
+// MongoStore persists operations in MongoDB
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store and ensures its indexes exist
+func NewMongoStore(ctx context.Context, db *database.MongoDB) (*MongoStore, error) {
+	collection := db.Collection("operations")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "finished_at", Value: 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection}, nil
+}
+
+// Create inserts the operation
+func (s *MongoStore) Create(ctx context.Context, op *models.Operation) error {
+	_, err := s.collection.InsertOne(ctx, op)
+	return err
+}
+
+// Get returns the operation with id
+func (s *MongoStore) Get(ctx context.Context, id string) (*models.Operation, error) {
+	var op models.Operation
+	err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&op)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// Claim takes the oldest claimable operation in one atomic update
+func (s *MongoStore) Claim(ctx context.Context, now, staleBefore time.Time) (*models.Operation, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": models.OperationQueued},
+		bson.M{"status": models.OperationRunning, "updated_at": bson.M{"$lt": staleBefore}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": models.OperationRunning, "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var op models.Operation
+	err := s.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&op)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// Save updates the operation while it has the claimed attempts
+func (s *MongoStore) Save(ctx context.Context, op *models.Operation, claimed int) error {
+	result, err := s.collection.UpdateOne(ctx, bson.M{"_id": op.ID, "attempts": claimed}, bson.M{"$set": bson.M{
+		"status":      op.Status,
+		"attempts":    op.Attempts,
+		"done":        op.Done,
+		"total":       op.Total,
+		"result":      op.Result,
+		"error":       op.Error,
+		"updated_at":  op.UpdatedAt,
+		"finished_at": op.FinishedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLost
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+// Package posts stores the posts users publish, in memory, PostgreSQL, or MongoDB. It is the reference
+// for resources owned by users: the store records the owner, and the handler checks it before changes.
+package posts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// ErrNotFound is returned for a post that does not exist or was deleted
+var ErrNotFound = errors.New("post not found")
+
+// ListOptions selects a page of posts
+type ListOptions struct {
+	Page     int
+	PageSize int
+	// Search matches posts whose title or body contains it, ignoring case
+	Search string
+	// OwnerID restricts the page to the posts of one user when set
+	OwnerID string
+}
+
+// Store persists posts
+type Store interface {
+	// Create stores a new post owned by ownerID
+	Create(ctx context.Context, ownerID string, req models.CreatePostRequest) (*models.PostInfo, error)
+	// Get returns the post with the given ID
+	Get(ctx context.Context, id string) (*models.PostInfo, error)
+	// List returns a page of the matching posts, newest first, and the number of matches
+	List(ctx context.Context, opts ListOptions) ([]models.PostInfo, int64, error)
+	// Update changes the fields set in req and returns the updated post
+	Update(ctx context.Context, id string, req models.UpdatePostRequest) (*models.PostInfo, error)
+	// Delete soft-deletes the post
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore is an in-process Store, suitable for development and tests
+type MemoryStore struct {
+	mu     sync.Mutex
+	nextID int
+	items  map[string]*models.PostInfo
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: make(map[string]*models.PostInfo)}
+}
+
+// Create stores a new post with the next sequential ID
+func (s *MemoryStore) Create(ctx context.Context, ownerID string, req models.CreatePostRequest) (*models.PostInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	now := time.Now()
+	item := &models.PostInfo{
+		ID:        strconv.Itoa(s.nextID),
+		OwnerID:   ownerID,
+		Title:     req.Title,
+		Body:      req.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.items[item.ID] = item
+
+	copied := *item
+	return &copied, nil
+}
+
+// Get returns the post
+func (s *MemoryStore) Get(ctx context.Context, id string) (*models.PostInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *item
+	return &copied, nil
+}
+
+// List returns a page of matching posts
+func (s *MemoryStore) List(ctx context.Context, opts ListOptions) ([]models.PostInfo, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	search := strings.ToLower(opts.Search)
+	matches := []models.PostInfo{}
+	for _, item := range s.items {
+		if opts.OwnerID != "" && item.OwnerID != opts.OwnerID {
+			continue
+		}
+		if strings.Contains(strings.ToLower(item.Title), search) || strings.Contains(strings.ToLower(item.Body), search) {
+			matches = append(matches, *item)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, _ := strconv.Atoi(matches[i].ID)
+		b, _ := strconv.Atoi(matches[j].ID)
+		return a > b
+	})
+
+	total := int64(len(matches))
+	start := min((opts.Page-1)*opts.PageSize, len(matches))
+	end := min(start+opts.PageSize, len(matches))
+	return matches[start:end], total, nil
+}
+
+// Update changes the fields set in req
+func (s *MemoryStore) Update(ctx context.Context, id string, req models.UpdatePostRequest) (*models.PostInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if req.Title != nil {
+		item.Title = *req.Title
+	}
+	if req.Body != nil {
+		item.Body = *req.Body
+	}
+	item.UpdatedAt = time.Now()
+
+	copied := *item
+	return &copied, nil
+}
+
+// Delete removes the post
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.items, id)
+	return nil
+}
+
+// PostgresStore persists posts in PostgreSQL
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the table is created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// postgresInfo converts a row to its API representation
+func postgresInfo(row *models.Post) *models.PostInfo {
+	return &models.PostInfo{
+		ID:        strconv.FormatUint(uint64(row.ID), 10),
+		OwnerID:   row.OwnerID,
+		Title:     row.Title,
+		Body:      row.Body,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+// Create inserts the row; ownerID is the ID of a PostgreSQL user
+func (s *PostgresStore) Create(ctx context.Context, ownerID string, req models.CreatePostRequest) (*models.PostInfo, error) {
+	if !s.db.ValidUserID(ownerID) {
+		return nil, fmt.Errorf("invalid owner ID %q", ownerID)
+	}
+
+	row := models.Post{OwnerID: ownerID, Title: req.Title, Body: req.Body}
+	if err := s.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return nil, err
+	}
+	return postgresInfo(&row), nil
+}
+
+// Get loads the row; IDs that are not numbers cannot exist
+func (s *PostgresStore) Get(ctx context.Context, id string) (*models.PostInfo, error) {
+	key, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	var row models.Post
+	if err := s.db.WithContext(ctx).First(&row, key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return postgresInfo(&row), nil
+}
+
+// List reads the page from a replica when one is configured
+func (s *PostgresStore) List(ctx context.Context, opts ListOptions) ([]models.PostInfo, int64, error) {
+	db := s.db.Replica().WithContext(ctx).Model(&models.Post{})
+	if opts.OwnerID != "" {
+		if !s.db.ValidUserID(opts.OwnerID) {
+			// No post can belong to an ID that no user can have
+			return []models.PostInfo{}, 0, nil
+		}
+		db = db.Where("owner_id = ?", opts.OwnerID)
+	}
+	if opts.Search != "" {
+		operator := "ILIKE"
+		if s.db.IsSQLite() {
+			// SQLite has no ILIKE; its LIKE is already case-insensitive for ASCII
+			operator = "LIKE"
+		}
+		pattern := "%" + opts.Search + "%"
+		db = db.Where("title "+operator+" ? OR body "+operator+" ?", pattern, pattern)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []models.Post
+	err := db.Order("created_at DESC, id DESC").Offset((opts.Page - 1) * opts.PageSize).Limit(opts.PageSize).Find(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]models.PostInfo, len(rows))
+	for i := range rows {
+		items[i] = *postgresInfo(&rows[i])
+	}
+	return items, total, nil
+}
+
+// Update changes the fields set in req and reads the row back
+func (s *PostgresStore) Update(ctx context.Context, id string, req models.UpdatePostRequest) (*models.PostInfo, error) {
+	key, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	updates := map[string]interface{}{}
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Body != nil {
+		updates["body"] = *req.Body
+	}
+
+	var row models.Post
+	err = s.db.WithTransaction(ctx, func(tx *database.PostgresDB) error {
+		if len(updates) > 0 {
+			result := tx.Model(&models.Post{}).Where("id = ?", key).Updates(updates)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrNotFound
+			}
+		}
+		return tx.First(&row, key).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return postgresInfo(&row), nil
+}
+
+// Delete soft-deletes the row
+func (s *PostgresStore) Delete(ctx context.Context, id string) error {
+	key, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	result := s.db.WithContext(ctx).Delete(&models.Post{}, key)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MongoStore persists posts in MongoDB
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store
+func NewMongoStore(db *database.MongoDB) *MongoStore {
+	return &MongoStore{collection: db.Collection("posts")}
+}
+
+// mongoInfo converts a document to its API representation
+func mongoInfo(doc *models.PostMongo) *models.PostInfo {
+	return &models.PostInfo{
+		ID:        doc.ID.Hex(),
+		OwnerID:   doc.OwnerID.Hex(),
+		Title:     doc.Title,
+		Body:      doc.Body,
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	}
+}
+
+// mongoFilter matches the live document with the given ID; IDs that are not ObjectIDs cannot exist
+func mongoFilter(id string) (bson.M, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return bson.M{"_id": objectID, "deleted_at": nil}, nil
+}
+
+// Create inserts the document; ownerID is the ObjectID of a MongoDB user
+func (s *MongoStore) Create(ctx context.Context, ownerID string, req models.CreatePostRequest) (*models.PostInfo, error) {
+	owner, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner ID %q: %w", ownerID, err)
+	}
+
+	now := time.Now()
+	doc := models.PostMongo{
+		ID:        primitive.NewObjectID(),
+		OwnerID:   owner,
+		Title:     req.Title,
+		Body:      req.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := s.collection.InsertOne(ctx, doc); err != nil {
+		return nil, err
+	}
+	return mongoInfo(&doc), nil
+}
+
+// Get loads the document
+func (s *MongoStore) Get(ctx context.Context, id string) (*models.PostInfo, error) {
+	filter, err := mongoFilter(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc models.PostMongo
+	if err := s.collection.FindOne(ctx, filter).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return mongoInfo(&doc), nil
+}
+
+// List finds the page of matching documents
+func (s *MongoStore) List(ctx context.Context, opts ListOptions) ([]models.PostInfo, int64, error) {
+	filter := bson.M{"deleted_at": nil}
+	if opts.OwnerID != "" {
+		owner, err := primitive.ObjectIDFromHex(opts.OwnerID)
+		if err != nil {
+			// No post can belong to an ID that is not an ObjectID
+			return []models.PostInfo{}, 0, nil
+		}
+		filter["owner_id"] = owner
+	}
+	if opts.Search != "" {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(opts.Search), "$options": "i"}
+		filter["$or"] = bson.A{bson.M{"title": pattern}, bson.M{"body": pattern}}
+	}
+
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((opts.Page - 1) * opts.PageSize)).
+		SetLimit(int64(opts.PageSize))
+	cursor, err := s.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []models.PostMongo
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]models.PostInfo, len(docs))
+	for i := range docs {
+		items[i] = *mongoInfo(&docs[i])
+	}
+	return items, total, nil
+}
+
+// Update sets the fields in req and returns the updated document
+func (s *MongoStore) Update(ctx context.Context, id string, req models.UpdatePostRequest) (*models.PostInfo, error) {
+	filter, err := mongoFilter(id)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{"updated_at": time.Now()}
+	if req.Title != nil {
+		set["title"] = *req.Title
+	}
+	if req.Body != nil {
+		set["body"] = *req.Body
+	}
+
+	var doc models.PostMongo
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := s.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": set}, opts).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return mongoInfo(&doc), nil
+}
+
+// Delete soft-deletes the document
+func (s *MongoStore) Delete(ctx context.Context, id string) error {
+	filter, err := mongoFilter(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
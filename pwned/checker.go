@@ -0,0 +1,88 @@
+// Package pwned checks passwords against the Pwned Passwords corpus of Have I Been Pwned with
+// k-anonymity: only the first five characters of the password's SHA-1 hash leave the server, and the
+// suffixes returned for that prefix are matched locally
+package pwned
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go-backend-template/config"
+	"go-backend-template/httpclient"
+	"go-backend-template/utils"
+)
+
+// ErrUnavailable is returned by Breached when the API cannot be reached and the check fails closed
+var ErrUnavailable = errors.New("password breach check unavailable")
+
+// Checker looks passwords up in the Pwned Passwords range API
+type Checker struct {
+	baseURL  string
+	client   *http.Client
+	failOpen bool
+	logger   utils.Logger
+}
+
+// NewChecker creates a checker for cfg; requests time out after cfg.Timeout
+func NewChecker(cfg config.PasswordBreachConfig, logger utils.Logger) *Checker {
+	return &Checker{
+		baseURL:  strings.TrimSuffix(cfg.APIURL, "/"),
+		client:   httpclient.New("pwned_passwords", cfg.Timeout),
+		failOpen: cfg.FailOpen,
+		logger:   logger,
+	}
+}
+
+// Breached reports whether password appears in a known data breach. When the API fails, the password
+// is accepted if the checker fails open; otherwise the error is ErrUnavailable.
+func (c *Checker) Breached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	breached, err := c.lookup(ctx, hash[:5], hash[5:])
+	if err != nil {
+		if c.failOpen {
+			c.logger.Warn("Password breach check failed; accepting the password", "error", err)
+			return false, nil
+		}
+		return false, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return breached, nil
+}
+
+// lookup fetches the hash suffixes of prefix and reports whether suffix is among them
+func (c *Checker) lookup(ctx context.Context, prefix, suffix string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// Padding hides the number of suffixes sharing the prefix; padded entries have a count of 0
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to reach Pwned Passwords: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("pwned Passwords returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if found && strings.EqualFold(candidate, suffix) {
+			return count != "0", nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read Pwned Passwords response: %w", err)
+	}
+	return false, nil
+}
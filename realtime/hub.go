@@ -0,0 +1,287 @@
+// Package realtime fans out per-user and broadcast events to connected WebSocket and SSE clients
+package realtime
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-backend-template/utils"
+)
+
+// Event types pushed to clients
+const (
+	EventProfileUpdated = "profile.updated"
+	EventBroadcast      = "admin.broadcast"
+	EventRoleChanged    = "role.changed"
+)
+
+var (
+	// ErrShutdown is the reason a subscription ends when the hub is shutting down
+	ErrShutdown = errors.New("server is shutting down")
+	// ErrSlowConsumer is the reason a subscription ends when its client cannot keep up
+	ErrSlowConsumer = errors.New("client is not consuming events fast enough")
+)
+
+// Event is a single notification delivered to subscribers
+type Event struct {
+	ID   string      `json:"id"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+	Time time.Time   `json:"time"`
+}
+
+// Hub routes events to the subscriptions of each user; every method is safe for concurrent use
+// and a nil *Hub discards events
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*Subscription]struct{}
+	closed      bool
+	bufferSize  int
+	sequence    uint64
+	history     []record
+	historySize int
+	active      sync.WaitGroup
+	logger      utils.Logger
+}
+
+// record is a published event kept for replay; an empty userID marks a broadcast
+type record struct {
+	userID string
+	seq    uint64
+	event  Event
+}
+
+// NewHub creates a hub whose subscriptions buffer up to bufferSize undelivered events and which
+// keeps the last historySize events for clients resuming with Resume
+func NewHub(bufferSize, historySize int, logger utils.Logger) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = 64
+	}
+	return &Hub{
+		subscribers: map[string]map[*Subscription]struct{}{},
+		bufferSize:  bufferSize,
+		historySize: historySize,
+		logger:      logger,
+	}
+}
+
+// Subscribe registers a new connection for userID; callers must Close it when the connection ends
+func (h *Hub) Subscribe(userID string) (*Subscription, error) {
+	return h.Resume(userID, "")
+}
+
+// Resume registers a new connection for userID whose queue starts with the retained events published
+// after lastEventID, so a reconnecting client misses nothing that is still in history. Unknown or empty
+// IDs replay nothing; at most the subscription buffer's worth of the newest events is replayed.
+func (h *Hub) Resume(userID, lastEventID string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrShutdown
+	}
+
+	sub := &Subscription{
+		UserID: userID,
+		hub:    h,
+		events: make(chan Event, h.bufferSize),
+		done:   make(chan struct{}),
+	}
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = map[*Subscription]struct{}{}
+	}
+	h.subscribers[userID][sub] = struct{}{}
+	h.active.Add(1)
+
+	for _, event := range h.replay(userID, lastEventID) {
+		sub.events <- event
+	}
+	return sub, nil
+}
+
+// Publish sends an event to every connection of userID
+func (h *Hub) Publish(userID, eventType string, data interface{}) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	event := h.record(userID, eventType, data)
+	for sub := range h.subscribers[userID] {
+		h.deliver(sub, event)
+	}
+}
+
+// Broadcast sends an event to every connected user
+func (h *Hub) Broadcast(eventType string, data interface{}) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	event := h.record("", eventType, data)
+	for _, subs := range h.subscribers {
+		for sub := range subs {
+			h.deliver(sub, event)
+		}
+	}
+}
+
+// Connections returns the number of open subscriptions
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := 0
+	for _, subs := range h.subscribers {
+		count += len(subs)
+	}
+	return count
+}
+
+// Shutdown stops accepting subscriptions, tells every open connection to close, and waits until they
+// have closed or ctx expires
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	for _, subs := range h.subscribers {
+		for sub := range subs {
+			sub.end(ErrShutdown)
+		}
+	}
+	h.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		h.active.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// record stamps an event with the next sequence number and appends it to the history. Callers hold h.mu.
+func (h *Hub) record(userID, eventType string, data interface{}) Event {
+	h.sequence++
+	event := Event{
+		ID:   strconv.FormatUint(h.sequence, 10),
+		Type: eventType,
+		Data: data,
+		Time: time.Now().UTC(),
+	}
+
+	if h.historySize > 0 {
+		if len(h.history) == h.historySize {
+			copy(h.history, h.history[1:])
+			h.history = h.history[:len(h.history)-1]
+		}
+		h.history = append(h.history, record{userID: userID, seq: h.sequence, event: event})
+	}
+	return event
+}
+
+// replay returns userID's retained events published after lastEventID, oldest first, capped to the
+// subscription buffer. Callers hold h.mu.
+func (h *Hub) replay(userID, lastEventID string) []Event {
+	if lastEventID == "" {
+		return nil
+	}
+	last, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	var events []Event
+	for _, r := range h.history {
+		if r.seq > last && (r.userID == "" || r.userID == userID) {
+			events = append(events, r.event)
+		}
+	}
+	if len(events) > h.bufferSize {
+		events = events[len(events)-h.bufferSize:]
+	}
+	return events
+}
+
+// deliver queues event without blocking; a subscriber whose buffer is full is disconnected rather than
+// slowing down the publisher. Callers hold h.mu.
+func (h *Hub) deliver(sub *Subscription, event Event) {
+	select {
+	case sub.events <- event:
+	default:
+		h.logger.Warn("Dropping slow realtime subscriber", "user_id", sub.UserID)
+		sub.end(ErrSlowConsumer)
+	}
+}
+
+// remove unregisters sub
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if subs := h.subscribers[sub.UserID]; subs != nil {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.subscribers, sub.UserID)
+		}
+	}
+}
+
+// Subscription is one client connection's view of the hub
+type Subscription struct {
+	UserID string
+
+	hub       *Hub
+	events    chan Event
+	done      chan struct{}
+	err       error
+	endOnce   sync.Once
+	closeOnce sync.Once
+}
+
+// Events delivers queued events in publish order
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Done is closed when the connection should end; Err reports why
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns ErrShutdown or ErrSlowConsumer once Done is closed
+func (s *Subscription) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Close unregisters the subscription; it must be called exactly when the connection has finished
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		s.end(nil)
+		s.hub.remove(s)
+		s.hub.active.Done()
+	})
+}
+
+// end records the reason and signals Done
+func (s *Subscription) end(err error) {
+	s.endOnce.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+	"go-backend-template/middleware"
+)
+
+// readOnlyRoute is the path of the read-only mode toggle, without the API version; it stays writable in
+// read-only mode so the mode can be turned off
+const readOnlyRoute = "/admin/read-only"
+
+// AdminRoutes mounts the admin statistics and, when migration, audit, backup, or readOnly is not nil,
+// the migration status, the audit log, the user backups, and the read-only mode toggle; backups and
+// toggling read-only mode are for superadmins only
+func AdminRoutes(stats *handlers.StatsHandler, migration *handlers.MigrationHandler, audit *handlers.AuditHandler, backup *handlers.BackupHandler, readOnly *handlers.ReadOnlyHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		admin := g.Admin.Group("/admin")
+		{
+			admin.GET("/stats", stats.GetStats)
+			if migration != nil {
+				admin.GET("/migrations", migration.Status)
+			}
+			if audit != nil {
+				admin.GET("/audit-logs", audit.List)
+				admin.GET("/audit-logs/verify", audit.Verify)
+				admin.GET("/audit-logs/export", audit.Export)
+			}
+			if backup != nil {
+				superadmin := admin.Group("/backup", middleware.RequireRole("superadmin"))
+				superadmin.GET("", backup.Create)
+				superadmin.POST("/restore", backup.Restore)
+			}
+		}
+		if readOnly != nil {
+			g.Admin.GET(readOnlyRoute, readOnly.Get)
+			g.Admin.PUT(readOnlyRoute, middleware.RequireRole("superadmin"), readOnly.Set)
+		}
+	})
+}
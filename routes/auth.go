@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/handlers"
+)
+
+// AuthRoutes mounts registration, login, and logout, token refresh and the session list when sessions are
+// enabled, and the creation of the first superadmin of an empty database while setup, which may be nil,
+// has a token pending. The routes bots, which may also be nil, guards are checked for bots first.
+func AuthRoutes(handler *handlers.AuthHandler, setup *handlers.SetupHandler, bots *handlers.BotHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		auth := g.Public.Group("/auth")
+		{
+			auth.POST("/register", botGuarded(bots, "/auth/register", g.Idempotent, handler.Register)...)
+			auth.POST("/login", botGuarded(bots, "/auth/login", handler.Login)...)
+			auth.POST("/logout", handler.Logout)
+			if handler.SessionsEnabled() {
+				auth.POST("/refresh", handler.Refresh)
+			}
+			if bots != nil && bots.ChallengeEnabled() {
+				auth.GET("/challenge", bots.Challenge)
+			}
+		}
+
+		if handler.SessionsEnabled() {
+			sessions := g.Protected.Group("/auth/sessions")
+			{
+				sessions.GET("", handler.ListSessions)
+				sessions.DELETE("/:id", handler.RevokeSession)
+			}
+		}
+
+		if setup != nil {
+			g.Public.POST("/setup", setup.Setup)
+		}
+	})
+}
+
+// botGuarded puts the bot guard of route in front of chain when bots guards it
+func botGuarded(bots *handlers.BotHandler, route string, chain ...gin.HandlerFunc) []gin.HandlerFunc {
+	if bots == nil || !bots.Guards(route) {
+		return chain
+	}
+	return append([]gin.HandlerFunc{bots.Guard(route)}, chain...)
+}
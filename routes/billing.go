@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+)
+
+// BillingRoutes mounts the plans, the subscription and checkout of the current user, and the Stripe
+// webhook, which authenticates with its signature
+func BillingRoutes(handler *handlers.BillingHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		g.Public.GET("/billing/plans", handler.ListPlans)
+		g.Public.POST("/billing/webhook", handler.Webhook)
+
+		billing := g.Protected.Group("/billing")
+		{
+			billing.GET("/subscription", handler.GetSubscription)
+			billing.POST("/checkout", g.Idempotent, handler.Checkout)
+		}
+	})
+}
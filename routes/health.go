@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+	"go-backend-template/middleware"
+)
+
+// HealthRoutes mounts the health check
+func HealthRoutes(handler *handlers.HealthHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		g.Public.GET("/health", middleware.DisableNegotiation(), handler.HealthCheck)
+	})
+}
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+	"go-backend-template/oauth"
+)
+
+// OAuthRoutes mounts the OpenID Connect provider: discovery and the protocol endpoints called by clients
+// and resource servers, the consent and device verification APIs of the frontend, and client registration
+// for admins
+func OAuthRoutes(handler *handlers.OAuthHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		g.Public.GET("/.well-known/openid-configuration", handler.Discovery)
+		g.Public.GET("/oauth/jwks", handler.JWKS)
+		g.Public.GET("/oauth/authorize", handler.Authorize)
+		g.Public.POST("/oauth/token", handler.Token)
+		g.Public.POST("/oauth/introspect", handler.Introspect)
+		g.Public.POST("/oauth/revoke", handler.Revoke)
+		if handler.DeviceEnabled() {
+			g.Public.POST("/oauth/device_authorization", handler.DeviceAuthorization)
+		}
+		g.Public.GET("/oauth/userinfo", handler.RequireScope(oauth.ScopeOpenID), handler.UserInfo)
+		g.Public.POST("/oauth/userinfo", handler.RequireScope(oauth.ScopeOpenID), handler.UserInfo)
+
+		consent := g.Protected.Group("/oauth")
+		{
+			consent.GET("/consent", handler.GetConsent)
+			consent.POST("/consent", handler.DecideConsent)
+			consent.GET("/consents", handler.ListConsents)
+			consent.DELETE("/consents/:client_id", handler.RevokeConsent)
+			if handler.DeviceEnabled() {
+				consent.GET("/device", handler.GetDevice)
+				consent.POST("/device", handler.DecideDevice)
+			}
+		}
+
+		clients := g.Admin.Group("/admin/oauth/clients")
+		{
+			clients.POST("", handler.CreateClient)
+			clients.GET("", handler.ListClients)
+			clients.DELETE("/:client_id", handler.DeleteClient)
+		}
+	})
+}
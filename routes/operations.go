@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+)
+
+// OperationRoutes mounts the status of the operations that requests with Prefer: respond-async started
+func OperationRoutes(handler *handlers.OperationHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		g.Protected.GET("/operations/:id", handler.Get)
+	})
+}
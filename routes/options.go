@@ -0,0 +1,231 @@
+package routes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/config"
+	"go-backend-template/middleware"
+)
+
+// DefaultTimeout is the deadline of a request when Options sets none
+const DefaultTimeout = 30 * time.Second
+
+// BackupTimeout is the deadline of the backup routes, which read or write every user, when
+// GroupTimeouts sets none
+const BackupTimeout = 5 * time.Minute
+
+// DefaultPriorityRoutes are the routes served first under load when Options sets none: the health check,
+// so the instance is not taken out of the load balancer for being busy
+var DefaultPriorityRoutes = []string{"/health"}
+
+// DefaultPriorityRoles are the roles whose requests are served first under load when Options sets none,
+// so operators can still act
+var DefaultPriorityRoles = []string{"admin", "superadmin"}
+
+// Options customizes the middleware of the routes SetupRoutes configures, so applications built on the
+// template change it without editing this package. The zero value is the default routing.
+type Options struct {
+	// DisableRateLimit turns off the global rate limiter, for instance behind a gateway that limits
+	DisableRateLimit bool
+	// Timeout is the deadline of each request; zero is DefaultTimeout and a negative value none
+	Timeout time.Duration
+	// GroupTimeouts overrides Timeout for the routes under a path prefix, given without the API version,
+	// as in "/admin" or "/users/profile". The longest matching prefix wins, and a negative value removes
+	// the deadline. The /ws and /events streams, the admin exports, and the bulk user actions have
+	// none unless set here, and the backups BackupTimeout.
+	GroupTimeouts map[string]time.Duration
+	// TimeoutStats, when not nil, counts the requests whose deadline passed per route
+	TimeoutStats *middleware.TimeoutStats
+	// Concurrency, when not nil, caps the requests served at once, answering the others 503. Its groups
+	// are path prefixes without the API version, the longest matching prefix winning. The /ws and
+	// /events streams, /metrics, and the profiles are not counted.
+	Concurrency *middleware.ConcurrencyLimiter
+	// PriorityRoutes are the path prefixes, without the API version, of the routes that may take the
+	// slots Concurrency reserves, so they are served while the other requests are shed; nil is
+	// DefaultPriorityRoutes. Anyone may call them, so they should be cheap and unauthenticated.
+	PriorityRoutes []string
+	// PriorityRoles are the roles of the users whose requests to any route may take the reserved slots,
+	// checked from their token; nil is DefaultPriorityRoles
+	PriorityRoles []string
+	// Middleware runs on every route after the built-in middleware
+	Middleware []gin.HandlerFunc
+	// ProtectedMiddleware runs on the authenticated API routes, except the event streams, after
+	// authentication, so it may read the user from the context
+	ProtectedMiddleware []gin.HandlerFunc
+	// AdminMiddleware runs on the admin-only API routes after the role check
+	AdminMiddleware []gin.HandlerFunc
+	// UploadRoutes are the path prefixes, without the API version, of the routes whose body may be up to
+	// MAX_FILE_SIZE instead of MAX_BODY_SIZE; the backup restore is one
+	UploadRoutes []string
+	// ReadOnly, when not nil, refuses the requests that change data with 503 while it is on, except those
+	// to READ_ONLY_EXEMPT_ROUTES and to the toggle
+	ReadOnly *middleware.ReadOnlyMode
+}
+
+// readOnlyExempt returns whether a registered route path stays writable in read-only mode: the routes,
+// given without the API version, under any version, and the toggle
+func readOnlyExempt(versions *Versions, routes []string) func(route string) bool {
+	exempt := make(map[string]bool)
+	for _, route := range append([]string{readOnlyRoute}, routes...) {
+		for _, path := range versions.Paths(route) {
+			exempt[path] = true
+		}
+	}
+	return func(route string) bool {
+		return exempt[route]
+	}
+}
+
+// ParseGroupTimeouts parses ROUTE_TIMEOUTS entries of the form prefix=duration, such as
+// "/admin/reports=2m", into GroupTimeouts; a duration of 0 removes the deadline
+func ParseGroupTimeouts(entries []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		prefix, value, ok := strings.Cut(entry, "=")
+		timeout, err := time.ParseDuration(value)
+		if !ok || !strings.HasPrefix(prefix, "/") || err != nil || timeout < 0 {
+			return nil, fmt.Errorf("ROUTE_TIMEOUTS: %q must be /path=duration", entry)
+		}
+		if timeout == 0 {
+			timeout = -1
+		}
+		timeouts[prefix] = timeout
+	}
+	return timeouts, nil
+}
+
+// ParseGroupLimits parses GROUP_MAX_IN_FLIGHT_REQUESTS entries of the form prefix=limit, such as
+// "/admin/reports=5", into the groups of a middleware.ConcurrencyLimiter
+func ParseGroupLimits(entries []string) (map[string]int, error) {
+	limits := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		prefix, value, ok := strings.Cut(entry, "=")
+		limit, err := strconv.Atoi(value)
+		if !ok || !strings.HasPrefix(prefix, "/") || err != nil || limit < 1 {
+			return nil, fmt.Errorf("GROUP_MAX_IN_FLIGHT_REQUESTS: %q must be /path=limit", entry)
+		}
+		limits["/"+strings.Trim(prefix, "/")] = limit
+	}
+	return limits, nil
+}
+
+// routeTimeout is the deadline of the routes under prefix
+type routeTimeout struct {
+	prefix  string
+	timeout time.Duration
+}
+
+// timeouts returns the deadline of each registered route path: the longest prefix of GroupTimeouts
+// under any version that matches it, or else Timeout
+func (o Options) timeouts(versions *Versions) func(route string) time.Duration {
+	timeout := o.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	groups := map[string]time.Duration{"/ws": -1, "/events": -1, "/admin/users/export": -1, "/admin/audit-logs/export": -1, "/admin/users/bulk": -1,
+		"/admin/backup": BackupTimeout}
+	for prefix, groupTimeout := range o.GroupTimeouts {
+		groups["/"+strings.Trim(prefix, "/")] = groupTimeout
+	}
+
+	// Profiles run for as long as the client asks
+	prefixes := []routeTimeout{{prefix: "/debug/pprof", timeout: -1}}
+	for group, groupTimeout := range groups {
+		for _, prefix := range versions.Paths(group) {
+			prefixes = append(prefixes, routeTimeout{prefix: strings.TrimSuffix(prefix, "/"), timeout: groupTimeout})
+		}
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i].prefix) > len(prefixes[j].prefix) })
+
+	return func(route string) time.Duration {
+		for _, p := range prefixes {
+			if route == p.prefix || strings.HasPrefix(route, p.prefix+"/") {
+				return p.timeout
+			}
+		}
+		return timeout
+	}
+}
+
+// priorityRoles returns the roles whose requests may take the reserved slots
+func (o Options) priorityRoles() []string {
+	if o.PriorityRoles == nil {
+		return DefaultPriorityRoles
+	}
+	return o.PriorityRoles
+}
+
+// concurrencyRoutes returns how the concurrency limiter treats each registered route path: its group is
+// the longest group prefix of Concurrency under any version that matches it, and it is a priority route
+// when a prefix of PriorityRoutes matches it
+func (o Options) concurrencyRoutes(versions *Versions) func(route string) middleware.ConcurrencyRoute {
+	exempt := []string{"/metrics", "/debug/pprof"}
+	for _, group := range []string{"/ws", "/events"} {
+		exempt = append(exempt, versionedPrefixes(versions, group)...)
+	}
+	priorityRoutes := o.PriorityRoutes
+	if priorityRoutes == nil {
+		priorityRoutes = DefaultPriorityRoutes
+	}
+	var priority []string
+	for _, group := range priorityRoutes {
+		priority = append(priority, versionedPrefixes(versions, "/"+strings.Trim(group, "/"))...)
+	}
+	groups := make(map[string]string)
+	var prefixes []string
+	for _, group := range o.Concurrency.Groups() {
+		for _, prefix := range versionedPrefixes(versions, group) {
+			groups[prefix] = group
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	return func(route string) middleware.ConcurrencyRoute {
+		if matchPrefix(route, exempt) != "" {
+			return middleware.ConcurrencyRoute{Exempt: true}
+		}
+		return middleware.ConcurrencyRoute{Group: groups[matchPrefix(route, prefixes)], Priority: matchPrefix(route, priority) != ""}
+	}
+}
+
+// versionedPrefixes returns the path prefix without the API version under every version
+func versionedPrefixes(versions *Versions, prefix string) []string {
+	paths := versions.Paths(prefix)
+	for i, path := range paths {
+		paths[i] = strings.TrimSuffix(path, "/")
+	}
+	return paths
+}
+
+// matchPrefix returns the first of prefixes that route is or is under, or ""
+func matchPrefix(route string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if route == prefix || strings.HasPrefix(route, prefix+"/") {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// bodyLimits returns the body size limit of each registered route path: limits.MaxUploadSize under the
+// upload routes of any version, or else limits.MaxBodySize
+func (o Options) bodyLimits(versions *Versions, limits config.LimitsConfig) func(route string) int64 {
+	var uploads []string
+	for _, route := range append([]string{"/admin/backup/restore"}, o.UploadRoutes...) {
+		uploads = append(uploads, versionedPrefixes(versions, "/"+strings.Trim(route, "/"))...)
+	}
+	return func(route string) int64 {
+		if matchPrefix(route, uploads) != "" {
+			return limits.MaxUploadSize
+		}
+		return limits.MaxBodySize
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+)
+
+// PostsRoutes mounts the post endpoints, which require authentication; handlers check ownership before
+// changes
+func PostsRoutes(handler *handlers.PostHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		posts := g.Protected.Group("/posts")
+		{
+			posts.GET("", handler.List)
+			posts.POST("", handler.Create)
+			posts.GET("/:id", handler.Get)
+			posts.PUT("/:id", handler.Update)
+			posts.DELETE("/:id", handler.Delete)
+		}
+	})
+}
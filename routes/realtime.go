@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+)
+
+// RealtimeRoutes mounts the realtime events over WebSocket or Server-Sent Events and the admin broadcast
+func RealtimeRoutes(handler *handlers.RealtimeHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		g.Streams.GET("/ws", handler.WebSocket)
+		g.Streams.GET("/events", handler.Events)
+
+		g.Admin.POST("/admin/broadcast", handler.Broadcast)
+	})
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Groups are the router groups of an API version that feature modules mount their routes on. Paths are
+// relative to the version, as in /users/profile.
+type Groups struct {
+	// Public routes need no authentication
+	Public *gin.RouterGroup
+	// Protected routes require a valid token; quotas and analytics apply to them
+	Protected *gin.RouterGroup
+	// Admin routes are protected routes that require the admin or superadmin role
+	Admin *gin.RouterGroup
+	// Streams are long-lived connections without a deadline; they also accept the token as ?access_token=
+	// for browsers, which cannot set headers on WebSocket and EventSource requests
+	Streams *gin.RouterGroup
+	// Service routes, under /service, are called by other backends with client credentials tokens instead
+	// of user tokens; guard each with middleware.RequireScope
+	Service *gin.RouterGroup
+	// Idempotent replays the response of unsafe requests retried with the same Idempotency-Key
+	Idempotent gin.HandlerFunc
+}
+
+// RouteRegistrar is a feature module that mounts its own routes. RegisterRoutes is called once for every
+// prefix the API version is served at, so it must only register routes.
+type RouteRegistrar interface {
+	RegisterRoutes(groups Groups)
+}
+
+// RegistrarFunc adapts a function to a RouteRegistrar
+type RegistrarFunc func(groups Groups)
+
+// RegisterRoutes implements RouteRegistrar
+func (f RegistrarFunc) RegisterRoutes(groups Groups) {
+	f(groups)
+}
@@ -1,67 +1,123 @@
 package routes
 
 import (
-	"time"
-
 	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"go-backend-template/analytics"
+	"go-backend-template/config"
 	"go-backend-template/handlers"
+	"go-backend-template/idempotency"
 	"go-backend-template/middleware"
+	"go-backend-template/openapi"
+	"go-backend-template/usage"
 	"go-backend-template/utils"
 )
 
-// SetupRoutes configures all API routes
+// SetupRoutes configures all API routes: each registrar mounts the routes of one feature module on every
+// API version, with the middleware customized by opts. It fails when the API version configuration names
+// unknown versions.
 func SetupRoutes(
 	router *gin.Engine,
-	authHandler *handlers.AuthHandler,
-	userHandler *handlers.UserHandler,
-	healthHandler *handlers.HealthHandler,
+	cfg *config.Config,
+	opts Options,
+	jwtUtils *utils.JWTUtils,
+	serviceTokens middleware.TokenVerifier,
+	idempotencyStore idempotency.Store,
+	meter *usage.Meter,
+	tracker *analytics.Tracker,
+	registrars []RouteRegistrar,
+	metricsHandler *handlers.MetricsHandler,
+	profilingHandler *handlers.ProfilingHandler,
 	logger utils.Logger,
-) {
-	// Add rate limiting and timeout middleware
-	router.Use(middleware.RateLimiter())
-	router.Use(middleware.Timeout(30 * time.Second))
-
-	// API version 1 group
-	v1 := router.Group("/api/v1")
-
-	// Public routes
-	{
-		// Health check
-		v1.GET("/health", healthHandler.HealthCheck)
-
-		// Authentication routes
-		auth := v1.Group("/auth")
-		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-		}
+) error {
+	// Render errors as RFC 7807 problem+json for all clients; otherwise only on Accept: application/problem+json
+	if cfg.ErrorFormat == "problem" {
+		router.Use(middleware.ProblemDetails())
 	}
 
-	// Protected routes (require authentication)
-	{
-		protected := v1.Group("/")
-		protected.Use(middleware.JWTAuth())
-
-		// User routes
-		users := protected.Group("/users")
-		{
-			users.GET("/profile", userHandler.GetProfile)
-			users.PUT("/profile", userHandler.UpdateProfile)
-
-			// Admin only routes
-			adminUsers := users.Group("/")
-			adminUsers.Use(middleware.RequireRole("admin", "superadmin"))
-			{
-				adminUsers.GET("", userHandler.GetUsers)
-			}
-		}
+	// Idempotency-Key support for unsafe endpoints that must not run twice on retry
+	idempotent := middleware.Idempotency(idempotencyStore, cfg.Idempotency.TTL, logger)
+
+	// API versions; each registers its routes, and the default one is also served without a version segment
+	cookieName := ""
+	if cfg.Auth.UsesCookies() {
+		cookieName = cfg.Auth.CookieName
 	}
+	versions := NewVersions()
+	versions.Version("v1").Routes(func(v1 *gin.RouterGroup) {
+		groups := Groups{Public: v1, Idempotent: idempotent}
+
+		// Protected routes (require authentication)
+		groups.Protected = v1.Group("/")
+		groups.Protected.Use(middleware.JWTAuth(jwtUtils, cookieName))
+		groups.Protected.Use(middleware.Quota(meter, logger))
+		groups.Protected.Use(middleware.Analytics(tracker))
+		groups.Protected.Use(opts.ProtectedMiddleware...)
+
+		// Admin only routes
+		groups.Admin = groups.Protected.Group("/")
+		groups.Admin.Use(middleware.RequireRole("admin", "superadmin"))
+		groups.Admin.Use(opts.AdminMiddleware...)
 
-	// API documentation route (development only)
-	router.GET("/", func(c *gin.Context) {
-		c.Redirect(302, "/swagger/index.html")
+		// Realtime events over WebSocket or Server-Sent Events; browsers may pass the token as ?access_token=
+		groups.Streams = v1.Group("/")
+		groups.Streams.Use(middleware.StreamToken(), middleware.JWTAuth(jwtUtils, cookieName))
+
+		// Service-to-service routes; nil serviceTokens, without the OAuth provider, rejects every token
+		groups.Service = v1.Group("/service")
+		groups.Service.Use(middleware.ServiceAuth(serviceTokens))
+
+		for _, registrar := range registrars {
+			registrar.RegisterRoutes(groups)
+		}
 	})
+	if err := versions.Configure(cfg.APIVersions); err != nil {
+		return err
+	}
+
+	// Limit request body size; upload routes get MAX_FILE_SIZE instead
+	router.Use(middleware.RouteBodyLimit(opts.bodyLimits(versions, cfg.Limits)))
+
+	// Add rate limiting and timeout middleware; long-lived event streams and profiles are exempt from the timeout
+	if !opts.DisableRateLimit {
+		router.Use(middleware.RateLimiter())
+	}
+	// Shed the requests over the concurrency limits before they reach the database
+	if opts.Concurrency != nil {
+		router.Use(middleware.LimitConcurrency(opts.Concurrency, opts.concurrencyRoutes(versions), middleware.PriorityRole(jwtUtils, cookieName, opts.priorityRoles()...)))
+	}
+	router.Use(middleware.RouteTimeout(opts.timeouts(versions), opts.TimeoutStats))
+	// Refuse the requests that change data while the API is in read-only mode
+	if opts.ReadOnly != nil {
+		router.Use(middleware.ReadOnly(opts.ReadOnly, readOnlyExempt(versions, cfg.ReadOnly.ExemptRoutes)))
+	}
+	router.Use(opts.Middleware...)
+
+	versions.Mount(router)
+
+	// Prometheus metrics (disabled with METRICS_ENABLED=false)
+	if metricsHandler != nil {
+		router.GET("/metrics", metricsHandler.Metrics)
+	}
+
+	// pprof profiles (enabled with PPROF_ENABLED=true)
+	if profilingHandler != nil {
+		router.GET("/debug/pprof/*profile", profilingHandler.Profile)
+	}
+
+	// API documentation routes (disabled in production unless API_DOCS_ENABLED is set)
+	if cfg.APIDocs {
+		spec := openapi.NewSpec()
+		router.GET("/openapi.json", spec.Handler())
+		router.GET("/redoc", openapi.Redoc())
+		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json")))
+		router.GET("/", func(c *gin.Context) {
+			c.Redirect(302, "/swagger/index.html")
+		})
+	}
 
 	logger.Info("Routes configured successfully")
+	return nil
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+)
+
+// TranslationRoutes mounts the languages for language pickers and the runtime translation overrides,
+// which are layered over the translation files
+func TranslationRoutes(handler *handlers.TranslationHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		g.Public.GET("/languages", handler.Languages)
+
+		translations := g.Admin.Group("/admin/translations")
+		{
+			translations.GET("/missing", handler.Missing)
+			translations.GET("/overrides", handler.Overrides)
+			translations.GET("/:language/export", handler.Export)
+			translations.POST("/:language/import", handler.Import)
+			translations.PUT("/:language/:key", handler.Set)
+			translations.DELETE("/:language/:key", handler.Delete)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+	"go-backend-template/middleware"
+	"go-backend-template/oauth"
+)
+
+// UserRoutes mounts the profile of the current user, the user administration, and the user listing for
+// services; usage, which may be nil, adds the API usage of the current user
+func UserRoutes(handler *handlers.UserHandler, usage *handlers.UsageHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		users := g.Protected.Group("/users")
+		{
+			users.GET("/profile", handler.GetProfile)
+			users.PUT("/profile", handler.UpdateProfile)
+			if usage != nil {
+				users.GET("/usage", usage.GetUsage)
+			}
+		}
+
+		// Admin only routes
+		g.Admin.GET("/users", handler.GetUsers)
+		admin := g.Admin.Group("/admin/users")
+		{
+			admin.GET("/export", handler.ExportUsers)
+			admin.POST("/bulk", handler.BulkUsers)
+			admin.DELETE("/:id", handler.DeleteUser)
+			admin.POST("/:id/restore", handler.RestoreUser)
+			admin.PATCH("/:id/role", middleware.RequireRole("superadmin"), handler.ChangeRole)
+		}
+
+		g.Service.GET("/users", middleware.RequireScope(oauth.ScopeUsersRead), handler.ServiceGetUsers)
+	})
+}
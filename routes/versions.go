@@ -0,0 +1,151 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/config"
+)
+
+// Version is an API version, served under /api/<name>. Versions coexist: each mounts its own routes, which
+// may reuse handlers of older versions, and clients move between them at their own pace.
+type Version struct {
+	name       string
+	deprecated time.Time
+	sunset     time.Time
+	register   []func(api *gin.RouterGroup)
+}
+
+// Routes adds a function that mounts routes of the version on its group
+func (v *Version) Routes(register func(api *gin.RouterGroup)) *Version {
+	v.register = append(v.register, register)
+	return v
+}
+
+// Deprecate marks the version deprecated since the given time; sunset is when it stops being served, or
+// zero while not announced
+func (v *Version) Deprecate(since, sunset time.Time) *Version {
+	v.deprecated, v.sunset = since, sunset
+	return v
+}
+
+// headers tags responses with the version, and with Deprecation (RFC 9745) and Sunset (RFC 8594) once
+// the version is deprecated
+func (v *Version) headers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("API-Version", v.name)
+		if !v.deprecated.IsZero() {
+			c.Header("Deprecation", "@"+strconv.FormatInt(v.deprecated.Unix(), 10))
+			if !v.sunset.IsZero() {
+				c.Header("Sunset", v.sunset.UTC().Format(http.TimeFormat))
+			}
+		}
+		c.Next()
+	}
+}
+
+// Versions registers the API versions. The default version is also served without a version segment,
+// so /api/users/profile is the default version's /api/v1/users/profile.
+type Versions struct {
+	versions       []*Version
+	defaultVersion string
+}
+
+// NewVersions creates an empty registry
+func NewVersions() *Versions {
+	return &Versions{}
+}
+
+// Version returns the version with the name, such as v2, registering it on first use; the first
+// registered version is the default until Configure or SetDefault changes it
+func (r *Versions) Version(name string) *Version {
+	for _, version := range r.versions {
+		if version.name == name {
+			return version
+		}
+	}
+	version := &Version{name: name}
+	r.versions = append(r.versions, version)
+	if r.defaultVersion == "" {
+		r.defaultVersion = name
+	}
+	return version
+}
+
+// lookup returns the registered version with the name
+func (r *Versions) lookup(name string) (*Version, error) {
+	for _, version := range r.versions {
+		if version.name == name {
+			return version, nil
+		}
+	}
+	return nil, fmt.Errorf("API version %s is not registered", name)
+}
+
+// SetDefault makes a registered version the one served without a version segment
+func (r *Versions) SetDefault(name string) error {
+	if _, err := r.lookup(name); err != nil {
+		return err
+	}
+	r.defaultVersion = name
+	return nil
+}
+
+// Configure applies API_DEFAULT_VERSION and the deprecations of API_DEPRECATED_VERSIONS, whose entries are
+// version=deprecated[/sunset] with dates as YYYY-MM-DD, such as v1=2026-10-01/2027-04-01
+func (r *Versions) Configure(cfg config.APIVersionsConfig) error {
+	for _, entry := range cfg.Deprecated {
+		name, dates, _ := strings.Cut(entry, "=")
+		deprecatedDate, sunsetDate, hasSunset := strings.Cut(dates, "/")
+		since, err := time.Parse(time.DateOnly, deprecatedDate)
+		if err != nil {
+			return fmt.Errorf("invalid API version deprecation %q: %w", entry, err)
+		}
+		var sunset time.Time
+		if hasSunset {
+			if sunset, err = time.Parse(time.DateOnly, sunsetDate); err != nil {
+				return fmt.Errorf("invalid API version deprecation %q: %w", entry, err)
+			}
+		}
+		version, err := r.lookup(name)
+		if err != nil {
+			return err
+		}
+		version.Deprecate(since, sunset)
+	}
+	if cfg.Default != "" {
+		return r.SetDefault(cfg.Default)
+	}
+	return nil
+}
+
+// Paths returns path under every prefix it is served at, such as /api/v1/ws and /api/ws
+func (r *Versions) Paths(path string) []string {
+	paths := []string{"/api" + path}
+	for _, version := range r.versions {
+		paths = append(paths, "/api/"+version.name+path)
+	}
+	return paths
+}
+
+// Mount registers the routes of every version on router, and those of the default version again under
+// /api
+func (r *Versions) Mount(router gin.IRouter) {
+	for _, version := range r.versions {
+		group := router.Group("/api/"+version.name, version.headers())
+		for _, register := range version.register {
+			register(group)
+		}
+		if version.name == r.defaultVersion {
+			group := router.Group("/api", version.headers())
+			for _, register := range version.register {
+				register(group)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"go-backend-template/handlers"
+)
+
+// WebhookRoutes mounts the webhook receiver, whose deliveries authenticate with the signature of their
+// provider, and the admin listing and replay of the deliveries
+func WebhookRoutes(handler *handlers.WebhookHandler) RouteRegistrar {
+	return RegistrarFunc(func(g Groups) {
+		g.Public.POST("/webhooks/:provider", handler.Receive)
+
+		admin := g.Admin.Group("/admin/webhooks")
+		{
+			admin.GET("", handler.List)
+			admin.POST("/:id/replay", handler.Replay)
+		}
+	})
+}
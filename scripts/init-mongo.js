@@ -6,5 +6,6 @@ db = db.getSiblingDB('backend_template');
 // Create collections and indexes
 db.users.createIndex({ "email": 1 }, { unique: true });
 db.users.createIndex({ "username": 1 }, { unique: true });
+db.users.createIndex({ "first_name": "text", "last_name": "text", "email": "text", "username": "text" });
 
 // You can add more initialization here
\ No newline at end of file
@@ -0,0 +1,116 @@
+// k6 load test for login, registration, and the admin user list.
+//
+//   k6 run -e BASE_URL=http://localhost:8080 -e ADMIN_EMAIL=admin@example.com -e ADMIN_PASSWORD=... scripts/loadtest/k6.js
+//
+// The server's rate limiter allows 100 requests per minute per client IP. Start the server with
+// TRUSTED_PROXIES=127.0.0.1 (or the load generator's address) so the X-Forwarded-For header set below
+// spreads requests over many simulated clients. Without ADMIN_EMAIL the list_users scenario is skipped.
+// The thresholds are the performance budget; k6 exits non-zero when one is exceeded.
+
+import http from 'k6/http';
+import { check, fail } from 'k6';
+
+const BASE_URL = (__ENV.BASE_URL || 'http://localhost:8080') + '/api/v1';
+const PASSWORD = 'LoadTest123';
+const RUN_ID = Date.now().toString(36);
+
+export const options = {
+  scenarios: {
+    login: {
+      executor: 'constant-arrival-rate',
+      exec: 'login',
+      rate: 20,
+      timeUnit: '1s',
+      duration: __ENV.DURATION || '1m',
+      preAllocatedVUs: 20,
+    },
+    register: {
+      executor: 'constant-arrival-rate',
+      exec: 'register',
+      rate: 5,
+      timeUnit: '1s',
+      duration: __ENV.DURATION || '1m',
+      preAllocatedVUs: 10,
+    },
+    list_users: {
+      executor: 'constant-arrival-rate',
+      exec: 'listUsers',
+      rate: 100,
+      timeUnit: '1s',
+      duration: __ENV.DURATION || '1m',
+      preAllocatedVUs: 20,
+    },
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{scenario:login}': ['p(95)<250'],
+    'http_req_duration{scenario:register}': ['p(95)<300'],
+    'http_req_duration{scenario:list_users}': ['p(95)<100'],
+  },
+};
+
+// headers spreads requests over simulated client addresses for the per-IP rate limiter
+function headers(token) {
+  const n = Math.floor(Math.random() * 16777216);
+  const h = {
+    'Content-Type': 'application/json',
+    'X-Forwarded-For': `10.${(n >> 16) & 255}.${(n >> 8) & 255}.${n & 255}`,
+  };
+  if (token) {
+    h.Authorization = `Bearer ${token}`;
+  }
+  return { headers: h };
+}
+
+// setup registers the account the login scenario uses and signs the admin in
+export function setup() {
+  const email = `loadtest-${RUN_ID}@example.com`;
+  const res = http.post(`${BASE_URL}/auth/register`, JSON.stringify({
+    email,
+    username: `loadtest_${RUN_ID}`,
+    password: PASSWORD,
+    first_name: 'Load',
+    last_name: 'Test',
+  }), headers());
+  if (res.status !== 201) {
+    fail(`registration failed with ${res.status}: ${res.body}`);
+  }
+
+  let adminToken = '';
+  if (__ENV.ADMIN_EMAIL) {
+    const admin = http.post(`${BASE_URL}/auth/login`, JSON.stringify({
+      email: __ENV.ADMIN_EMAIL,
+      password: __ENV.ADMIN_PASSWORD,
+    }), headers());
+    if (admin.status !== 200) {
+      fail(`admin login failed with ${admin.status}: ${admin.body}`);
+    }
+    adminToken = admin.json('data.token');
+  }
+  return { email, adminToken };
+}
+
+export function login(data) {
+  const res = http.post(`${BASE_URL}/auth/login`, JSON.stringify({ email: data.email, password: PASSWORD }), headers());
+  check(res, { 'login returns 200': (r) => r.status === 200 });
+}
+
+export function register() {
+  const id = `${RUN_ID}_${__VU}_${__ITER}`;
+  const res = http.post(`${BASE_URL}/auth/register`, JSON.stringify({
+    email: `loadtest-${id}@example.com`,
+    username: `lt_${id}`,
+    password: PASSWORD,
+    first_name: 'Load',
+    last_name: 'Test',
+  }), headers());
+  check(res, { 'register returns 201': (r) => r.status === 201 });
+}
+
+export function listUsers(data) {
+  if (!data.adminToken) {
+    return;
+  }
+  const res = http.get(`${BASE_URL}/users?page_size=20`, headers(data.adminToken));
+  check(res, { 'list users returns 200': (r) => r.status === 200 });
+}
@@ -0,0 +1,1888 @@
+// Code generated by sdkgen from the OpenAPI document. DO NOT EDIT.
+
+// Package client is a typed client for Backend API Template (version 1.0).
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// basePath is prefixed to every operation path
+const basePath = "/api/v1"
+
+// Client calls the API
+type Client struct {
+	// BaseURL is the server origin, for example "http://localhost:8080"
+	BaseURL string
+	// HTTPClient sends the requests; http.DefaultClient is used when nil
+	HTTPClient *http.Client
+	// Token is sent as a Bearer token when set
+	Token string
+	// Language is sent as Accept-Language when set
+	Language string
+}
+
+// New creates a client for the server at baseURL
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Error is returned for responses outside the 2xx range
+type Error struct {
+	StatusCode int
+	Message    string
+	Body       []byte
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("api: %d %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("api: unexpected status %d", e.StatusCode)
+}
+
+// Ptr returns a pointer to v, for optional parameters
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	endpoint := c.BaseURL + basePath + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.Language != "" {
+		req.Header.Set("Accept-Language", c.Language)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: resp.StatusCode, Body: data}
+		var envelope struct {
+			Message string `json:"message"`
+			Title   string `json:"title"`
+		}
+		if json.Unmarshal(data, &envelope) == nil {
+			apiErr.Message = envelope.Message
+			if apiErr.Message == "" {
+				apiErr.Message = envelope.Title
+			}
+		}
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if raw, ok := out.(*[]byte); ok {
+		*raw = data
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+func addQuery[T any](query url.Values, name string, value *T) {
+	if value != nil {
+		query.Set(name, fmt.Sprint(*value))
+	}
+}
+
+func addHeader[T any](header http.Header, name string, value *T) {
+	if value != nil {
+		header.Set(name, fmt.Sprint(*value))
+	}
+}
+
+// APIResponse is the APIResponse schema
+type APIResponse[T any] struct {
+	// Code identifies the error for clients to branch on; the codes are listed in the errcodes package
+	Code    string       `json:"code,omitempty"`
+	Data    T            `json:"data,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+	Message string       `json:"message,omitempty"`
+	// RequestID identifies the request in the logs; it is set on unexpected server errors and on requests that match no route
+	RequestID string `json:"request_id,omitempty"`
+	Success   bool   `json:"success,omitempty"`
+}
+
+// ActiveUsers is the ActiveUsers schema
+type ActiveUsers struct {
+	Last24h int `json:"last_24h,omitempty"`
+	Last30d int `json:"last_30d,omitempty"`
+	Last7d  int `json:"last_7d,omitempty"`
+}
+
+// Address is the Address schema
+type Address struct {
+	City       string `json:"city,omitempty"`
+	Country    string `json:"country,omitempty"`
+	Line1      string `json:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Region     string `json:"region,omitempty"`
+}
+
+// AdminStats is the AdminStats schema
+type AdminStats struct {
+	ActiveUsers ActiveUsers    `json:"active_users,omitempty"`
+	Databases   []DatabaseSize `json:"databases,omitempty"`
+	// GeneratedAt is when the figures were computed; they are cached for ADMIN_STATS_CACHE_TTL
+	GeneratedAt string     `json:"generated_at,omitempty"`
+	Logins      LoginStats `json:"logins,omitempty"`
+	// Signups counts the registrations of each of the last days, oldest first, including since deleted users
+	Signups []DailyCount `json:"signups,omitempty"`
+	Users   UserStats    `json:"users,omitempty"`
+}
+
+// AuditLog is the AuditLog schema
+type AuditLog struct {
+	// ActorID is the admin who made the call
+	ActorID   string `json:"actor_id,omitempty"`
+	ActorRole string `json:"actor_role,omitempty"`
+	ClientIP  string `json:"client_ip,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	// Hash is the SHA-256 of the entry, including PrevHash
+	Hash   string `json:"hash,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path,omitempty"`
+	// PayloadHash is the SHA-256 of the request body, empty without one
+	PayloadHash string `json:"payload_hash,omitempty"`
+	// PrevHash is the hash of the previous entry of the chain, empty for the first one
+	PrevHash  string `json:"prev_hash,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Route is the route pattern, as in /api/v1/admin/users/:id
+	Route string `json:"route,omitempty"`
+	Seq   int    `json:"seq,omitempty"`
+	// Status is the HTTP status of the response
+	Status int `json:"status,omitempty"`
+}
+
+// AuditVerification is the AuditVerification schema
+type AuditVerification struct {
+	// BrokenAt is the ID of the first entry that failed the check
+	BrokenAt string `json:"broken_at,omitempty"`
+	// Checked is the number of entries checked
+	Checked int `json:"checked,omitempty"`
+	// Reason tells why the entry failed
+	Reason string `json:"reason,omitempty"`
+	// Valid is false when an entry does not match its hash or the chain has a gap or a broken link
+	Valid bool `json:"valid,omitempty"`
+}
+
+// AuthResponse is the AuthResponse schema
+type AuthResponse struct {
+	ExpiresAt        string `json:"expires_at,omitempty"`
+	RefreshExpiresAt string `json:"refresh_expires_at,omitempty"`
+	// RefreshToken gets new tokens from POST /auth/refresh until RefreshExpiresAt; omitted without sessions
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// SessionID identifies the session in GET /auth/sessions
+	SessionID string   `json:"session_id,omitempty"`
+	Token     string   `json:"token,omitempty"`
+	User      UserInfo `json:"user,omitempty"`
+}
+
+// BackupResponse is the BackupResponse schema
+type BackupResponse struct {
+	// Archive is the archive encrypted with BACKUP_ENCRYPTION_KEY, base64-encoded
+	Archive   string `json:"archive,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	Source    string `json:"source,omitempty"`
+	Users     int    `json:"users,omitempty"`
+}
+
+// BotChallengeResponse is the BotChallengeResponse schema
+type BotChallengeResponse struct {
+	ExpiresAt string `json:"expires_at,omitempty"`
+	// Token goes in the X-Bot-Challenge header of one login or registration request
+	Token string `json:"token,omitempty"`
+}
+
+// BroadcastRequest is the BroadcastRequest schema
+type BroadcastRequest struct {
+	Data    map[string]interface{} `json:"data,omitempty"`
+	Message string                 `json:"message"`
+}
+
+// BroadcastResponse is the BroadcastResponse schema
+type BroadcastResponse struct {
+	Connections int `json:"connections,omitempty"`
+}
+
+// BulkUserResult is the BulkUserResult schema
+type BulkUserResult struct {
+	// Error says why the action failed for the user
+	Error  string `json:"error,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// BulkUsersRequest is the BulkUsersRequest schema
+type BulkUsersRequest struct {
+	Action string `json:"action"`
+	// Filter holds the search and filter query parameters of GET /users, such as role or created_before
+	Filter map[string]string `json:"filter,omitempty"`
+	Ids    []string          `json:"ids,omitempty"`
+	// Reason is recorded in the security log with each role change
+	Reason string `json:"reason,omitempty"`
+	// Role is the new role of the role action
+	Role string `json:"role,omitempty"`
+}
+
+// BulkUsersResponse is the BulkUsersResponse schema
+type BulkUsersResponse struct {
+	Action    string           `json:"action,omitempty"`
+	Failed    int              `json:"failed,omitempty"`
+	Matched   int              `json:"matched,omitempty"`
+	Results   []BulkUserResult `json:"results,omitempty"`
+	Succeeded int              `json:"succeeded,omitempty"`
+}
+
+// ChangeRoleRequest is the ChangeRoleRequest schema
+type ChangeRoleRequest struct {
+	// Reason is recorded in the security log
+	Reason string `json:"reason"`
+	Role   string `json:"role"`
+}
+
+// CheckoutRequest is the CheckoutRequest schema
+type CheckoutRequest struct {
+	PlanID string `json:"plan_id"`
+}
+
+// CheckoutResponse is the CheckoutResponse schema
+type CheckoutResponse struct {
+	SessionID string `json:"session_id,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// ConsentDecision is the ConsentDecision schema
+type ConsentDecision struct {
+	Approve             bool   `json:"approve,omitempty"`
+	ClientID            string `json:"client_id"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+	Nonce               string `json:"nonce,omitempty"`
+	RedirectUri         string `json:"redirect_uri"`
+	ResponseType        string `json:"response_type,omitempty"`
+	Scope               string `json:"scope,omitempty"`
+	State               string `json:"state,omitempty"`
+}
+
+// ConsentInfo is the ConsentInfo schema
+type ConsentInfo struct {
+	Client OAuthClientSummary `json:"client,omitempty"`
+	// ConsentRequired is false when the user already allowed these scopes or the client is trusted
+	ConsentRequired bool `json:"consent_required,omitempty"`
+	// Scopes are the scopes the client asks for
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// ConsentRedirect is the ConsentRedirect schema
+type ConsentRedirect struct {
+	RedirectTo string `json:"redirect_to,omitempty"`
+}
+
+// CreateOAuthClientRequest is the CreateOAuthClientRequest schema
+type CreateOAuthClientRequest struct {
+	Name string `json:"name"`
+	// Public clients get no secret and must use PKCE
+	Public bool `json:"public,omitempty"`
+	// RedirectURIs are required, except for service clients, which have none
+	RedirectUris []string `json:"redirect_uris,omitempty"`
+	// Scopes limits what the client may request; empty allows every OpenID Connect scope
+	Scopes []string `json:"scopes,omitempty"`
+	// Service clients are backends that call the API for themselves with service scopes, such as users:read
+	Service bool `json:"service,omitempty"`
+	// Trusted clients, such as first-party apps, are not shown a consent screen
+	Trusted bool `json:"trusted,omitempty"`
+}
+
+// CreatePostRequest is the CreatePostRequest schema
+type CreatePostRequest struct {
+	Body  string `json:"body"`
+	Title string `json:"title"`
+}
+
+// DailyCount is the DailyCount schema
+type DailyCount struct {
+	Count int    `json:"count,omitempty"`
+	Date  string `json:"date,omitempty"`
+}
+
+// DatabaseSize is the DatabaseSize schema
+type DatabaseSize struct {
+	Database  string `json:"database,omitempty"`
+	SizeBytes int    `json:"size_bytes,omitempty"`
+	// Tables lists the tables or collections, largest first, where the database reports them
+	Tables []TableSize `json:"tables,omitempty"`
+}
+
+// DeviceDecision is the DeviceDecision schema
+type DeviceDecision struct {
+	Approve  bool   `json:"approve,omitempty"`
+	UserCode string `json:"user_code"`
+}
+
+// FieldError is the FieldError schema
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+}
+
+// HealthResponse is the HealthResponse schema
+type HealthResponse struct {
+	Checks    map[string]ServiceHealth `json:"checks,omitempty"`
+	Services  map[string]string        `json:"services,omitempty"`
+	Status    string                   `json:"status,omitempty"`
+	Timestamp string                   `json:"timestamp,omitempty"`
+	Version   string                   `json:"version,omitempty"`
+}
+
+// LanguageInfo is the LanguageInfo schema
+type LanguageInfo struct {
+	Code    string `json:"code,omitempty"`
+	Default bool   `json:"default,omitempty"`
+	// Direction is the writing direction, ltr or rtl
+	Direction string `json:"direction,omitempty"`
+	// Name is the name of the language in the language itself
+	Name string `json:"name,omitempty"`
+}
+
+// LoginRequest is the LoginRequest schema
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	// RememberMe starts a session with the longer REMEMBER_ME_ lifetimes instead of the SESSION_ ones
+	RememberMe bool `json:"remember_me,omitempty"`
+}
+
+// LoginStats is the LoginStats schema
+type LoginStats struct {
+	FailureRate float64 `json:"failure_rate,omitempty"`
+	Failures    int     `json:"failures,omitempty"`
+	Successes   int     `json:"successes,omitempty"`
+	Window      string  `json:"window,omitempty"`
+}
+
+// LogoutRequest is the LogoutRequest schema
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// MigrationInfo is the MigrationInfo schema
+type MigrationInfo struct {
+	Applied bool   `json:"applied,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Version int    `json:"version,omitempty"`
+}
+
+// MigrationStatus is the MigrationStatus schema
+type MigrationStatus struct {
+	Dirty      bool            `json:"dirty,omitempty"`
+	Latest     int             `json:"latest,omitempty"`
+	Migrations []MigrationInfo `json:"migrations,omitempty"`
+	Version    int             `json:"version,omitempty"`
+}
+
+// MissingTranslations is the MissingTranslations schema
+type MissingTranslations struct {
+	Keys     []string `json:"keys,omitempty"`
+	Language string   `json:"language,omitempty"`
+}
+
+// OAuthClientInfo is the OAuthClientInfo schema
+type OAuthClientInfo struct {
+	ClientID     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	CreatedAt    string   `json:"created_at,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Public       bool     `json:"public,omitempty"`
+	RedirectUris []string `json:"redirect_uris,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	Service      bool     `json:"service,omitempty"`
+	Trusted      bool     `json:"trusted,omitempty"`
+}
+
+// OAuthClientSummary is the OAuthClientSummary schema
+type OAuthClientSummary struct {
+	ClientID string `json:"client_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// OAuthConsent is the OAuthConsent schema
+type OAuthConsent struct {
+	ClientID  string   `json:"client_id,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
+	UpdatedAt string   `json:"updated_at,omitempty"`
+}
+
+// OAuthError is the OAuthError schema
+type OAuthError struct {
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// Operation is the Operation schema
+type Operation struct {
+	// Attempts counts the times a worker took the operation
+	Attempts  int    `json:"attempts,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	// Done and Total are the progress in items, such as users; Total is 0 when unknown
+	Done       int    `json:"done,omitempty"`
+	Error      string `json:"error,omitempty"`
+	FinishedAt string `json:"finished_at,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	// OwnerID is the user who started the operation
+	OwnerID string `json:"owner_id,omitempty"`
+	// Result is the response data of the operation once it succeeded
+	Result    map[string]interface{} `json:"result,omitempty"`
+	Status    string                 `json:"status,omitempty"`
+	Total     int                    `json:"total,omitempty"`
+	UpdatedAt string                 `json:"updated_at,omitempty"`
+}
+
+// PaginatedResponse is the PaginatedResponse schema
+type PaginatedResponse[T any] struct {
+	Data       T          `json:"data,omitempty"`
+	Pagination Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination is the Pagination schema
+type Pagination struct {
+	Next       string `json:"next,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      int    `json:"total,omitempty"`
+	TotalPage  int    `json:"total_page,omitempty"`
+}
+
+// PlanInfo is the PlanInfo schema
+type PlanInfo struct {
+	ID   string `json:"id,omitempty"`
+	Rank int    `json:"rank,omitempty"`
+}
+
+// PostInfo is the PostInfo schema
+type PostInfo struct {
+	Body      string `json:"body,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	ID        string `json:"id,omitempty"`
+	OwnerID   string `json:"owner_id,omitempty"`
+	Title     string `json:"title,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// ReadOnlyStatus is the ReadOnlyStatus schema
+type ReadOnlyStatus struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Reason is the message of the 503 responses, empty for the default one
+	Reason string `json:"reason,omitempty"`
+	// Since is when read-only mode was turned on
+	Since string `json:"since,omitempty"`
+}
+
+// RefreshRequest is the RefreshRequest schema
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// RegisterRequest is the RegisterRequest schema
+type RegisterRequest struct {
+	// AnalyticsOptOut excludes the user from product analytics, including the signup event
+	AnalyticsOptOut bool   `json:"analytics_opt_out,omitempty"`
+	Email           string `json:"email"`
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name"`
+	// Locale is the preferred language, used for responses when a request has no Accept-Language
+	Locale   string `json:"locale,omitempty"`
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+// RestoreBackupRequest is the RestoreBackupRequest schema
+type RestoreBackupRequest struct {
+	// Archive is the encrypted archive, base64-encoded
+	Archive string `json:"archive"`
+}
+
+// RestoreBackupResponse is the RestoreBackupResponse schema
+type RestoreBackupResponse struct {
+	Created int `json:"created,omitempty"`
+	// CreatedAt is when the archive was made
+	CreatedAt string `json:"created_at,omitempty"`
+	Updated   int    `json:"updated,omitempty"`
+	// Users is the number of users in the archive
+	Users int `json:"users,omitempty"`
+}
+
+// RoleCount is the RoleCount schema
+type RoleCount struct {
+	Count int    `json:"count,omitempty"`
+	Role  string `json:"role,omitempty"`
+}
+
+// ServiceHealth is the ServiceHealth schema
+type ServiceHealth struct {
+	Critical    bool    `json:"critical,omitempty"`
+	LastSuccess string  `json:"last_success,omitempty"`
+	LatencyMs   float64 `json:"latency_ms,omitempty"`
+	Status      string  `json:"status,omitempty"`
+}
+
+// SessionInfo is the SessionInfo schema
+type SessionInfo struct {
+	CreatedAt string `json:"created_at,omitempty"`
+	// Current is true for the session of the token the list was requested with
+	Current bool `json:"current,omitempty"`
+	// ExpiresAt is when the session ends unless it is refreshed first
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	ID         string `json:"id,omitempty"`
+	IPAddress  string `json:"ip_address,omitempty"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	// MaxExpiresAt is when the session ends however often it is refreshed
+	MaxExpiresAt string `json:"max_expires_at,omitempty"`
+	// RememberMe is true for sessions started with remember_me, which stay signed in longer
+	RememberMe bool   `json:"remember_me,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+}
+
+// SetReadOnlyRequest is the SetReadOnlyRequest schema
+type SetReadOnlyRequest struct {
+	Enabled bool `json:"enabled"`
+	// Reason is shown to clients in the 503 responses while read-only mode is on
+	Reason string `json:"reason,omitempty"`
+}
+
+// SetTranslationRequest is the SetTranslationRequest schema
+type SetTranslationRequest struct {
+	Text string `json:"text"`
+}
+
+// SetupRequest is the SetupRequest schema
+type SetupRequest struct {
+	// AnalyticsOptOut excludes the user from product analytics, including the signup event
+	AnalyticsOptOut bool   `json:"analytics_opt_out,omitempty"`
+	Email           string `json:"email"`
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name"`
+	// Locale is the preferred language, used for responses when a request has no Accept-Language
+	Locale   string `json:"locale,omitempty"`
+	Password string `json:"password"`
+	Token    string `json:"token"`
+	Username string `json:"username"`
+}
+
+// SubscriptionInfo is the SubscriptionInfo schema
+type SubscriptionInfo struct {
+	CancelAtPeriodEnd bool   `json:"cancel_at_period_end,omitempty"`
+	CurrentPeriodEnd  string `json:"current_period_end,omitempty"`
+	PlanID            string `json:"plan_id,omitempty"`
+	Status            string `json:"status,omitempty"`
+}
+
+// TableSize is the TableSize schema
+type TableSize struct {
+	Name      string `json:"name,omitempty"`
+	SizeBytes int    `json:"size_bytes,omitempty"`
+}
+
+// Translation is the Translation schema
+type Translation struct {
+	Key       string `json:"key,omitempty"`
+	Language  string `json:"language,omitempty"`
+	Text      string `json:"text,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// TranslationImport is the TranslationImport schema
+type TranslationImport struct {
+	// Imported counts the texts stored as overrides; texts equal to those of the files are not stored
+	Imported int    `json:"imported,omitempty"`
+	Language string `json:"language,omitempty"`
+	// Removed counts the overrides deleted because replace was set
+	Removed int `json:"removed,omitempty"`
+}
+
+// UpdatePostRequest is the UpdatePostRequest schema
+type UpdatePostRequest struct {
+	Body  string `json:"body,omitempty"`
+	Title string `json:"title,omitempty"`
+}
+
+// UpdateUserRequest is the UpdateUserRequest schema
+type UpdateUserRequest struct {
+	Address Address `json:"address,omitempty"`
+	// AnalyticsOptOut changes the analytics preference when set
+	AnalyticsOptOut bool `json:"analytics_opt_out,omitempty"`
+	// The profile fields below change when set; an empty value (an empty object for Address) clears them
+	AvatarURL string `json:"avatar_url,omitempty"`
+	Bio       string `json:"bio,omitempty"`
+	Birthday  string `json:"birthday,omitempty"`
+	Email     string `json:"email,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Locale    string `json:"locale,omitempty"`
+	// Metadata sets the custom attributes defined by USER_METADATA_SCHEMA; a null value removes one
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Phone    string                 `json:"phone,omitempty"`
+}
+
+// UsageInfo is the UsageInfo schema
+type UsageInfo struct {
+	Bytes     int    `json:"bytes,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+	Period    string `json:"period,omitempty"`
+	Plan      string `json:"plan,omitempty"`
+	Remaining int    `json:"remaining,omitempty"`
+	Requests  int    `json:"requests,omitempty"`
+	ResetsAt  string `json:"resets_at,omitempty"`
+}
+
+// UserInfo is the UserInfo schema
+type UserInfo struct {
+	Address         Address `json:"address,omitempty"`
+	AnalyticsOptOut bool    `json:"analytics_opt_out,omitempty"`
+	AvatarURL       string  `json:"avatar_url,omitempty"`
+	Bio             string  `json:"bio,omitempty"`
+	Birthday        string  `json:"birthday,omitempty"`
+	CreatedAt       string  `json:"created_at,omitempty"`
+	Email           string  `json:"email,omitempty"`
+	// EmailStatus is set once mail to the address bounced or was reported as spam; no email is sent to it until the email changes
+	EmailStatus   string `json:"email_status,omitempty"`
+	EmailStatusAt string `json:"email_status_at,omitempty"`
+	FirstName     string `json:"first_name,omitempty"`
+	// ID is a decimal number or a UUID on PostgreSQL and a hex ObjectID on MongoDB, always a string
+	ID        string                 `json:"id,omitempty"`
+	IsActive  bool                   `json:"is_active,omitempty"`
+	LastName  string                 `json:"last_name,omitempty"`
+	Locale    string                 `json:"locale,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Phone     string                 `json:"phone,omitempty"`
+	Role      string                 `json:"role,omitempty"`
+	UpdatedAt string                 `json:"updated_at,omitempty"`
+	Username  string                 `json:"username,omitempty"`
+}
+
+// UserStats is the UserStats schema
+type UserStats struct {
+	Active   int         `json:"active,omitempty"`
+	ByRole   []RoleCount `json:"by_role,omitempty"`
+	Deleted  int         `json:"deleted,omitempty"`
+	Inactive int         `json:"inactive,omitempty"`
+	// Total counts the users that are not deleted
+	Total int `json:"total,omitempty"`
+}
+
+// WebhookEvent is the WebhookEvent schema
+type WebhookEvent struct {
+	// Attempts counts the times the delivery was processed
+	Attempts int `json:"attempts,omitempty"`
+	// EventID is the provider's ID of the delivery, the same in each of its retries
+	EventID   string `json:"event_id,omitempty"`
+	ID        string `json:"id,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+	// Payload is the body of the delivery as received
+	Payload     string `json:"payload,omitempty"`
+	ProcessedAt string `json:"processed_at,omitempty"`
+	Provider    string `json:"provider,omitempty"`
+	ReceivedAt  string `json:"received_at,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Type        string `json:"type,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+// Broadcast calls POST /admin/broadcast
+//
+// Broadcast a message to all connected clients (Admin only)
+func (c *Client) Broadcast(ctx context.Context, body BroadcastRequest) (*APIResponse[BroadcastResponse], error) {
+	path := "/admin/broadcast"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[BroadcastResponse]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BulkUsersParams holds the query and header parameters of BulkUsers
+type BulkUsersParams struct {
+	// respond-async to run the action in the background
+	Prefer *string
+}
+
+// BulkUsers calls POST /admin/users/bulk
+//
+// Act on many users at once (Admin only)
+func (c *Client) BulkUsers(ctx context.Context, body BulkUsersRequest, params *BulkUsersParams) (*APIResponse[BulkUsersResponse], error) {
+	path := "/admin/users/bulk"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addHeader(header, "Prefer", params.Prefer)
+	}
+	var out APIResponse[BulkUsersResponse]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ChangeUserRole calls PATCH /admin/users/{id}/role
+//
+// Change a user's role (Superadmin only)
+func (c *Client) ChangeUserRole(ctx context.Context, id string, body ChangeRoleRequest) (*APIResponse[UserInfo], error) {
+	path := "/admin/users/{id}/role"
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[UserInfo]
+	if err := c.do(ctx, "PATCH", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBackupParams holds the query and header parameters of CreateBackup
+type CreateBackupParams struct {
+	// respond-async to back up in the background
+	Prefer *string
+}
+
+// CreateBackup calls GET /admin/backup
+//
+// Back up the users
+func (c *Client) CreateBackup(ctx context.Context, params *CreateBackupParams) (*APIResponse[BackupResponse], error) {
+	path := "/admin/backup"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addHeader(header, "Prefer", params.Prefer)
+	}
+	var out APIResponse[BackupResponse]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateCheckoutParams holds the query and header parameters of CreateCheckout
+type CreateCheckoutParams struct {
+	// Client-generated key to make retries safe
+	IdempotencyKey *string
+}
+
+// CreateCheckout calls POST /billing/checkout
+//
+// Start a subscription checkout
+func (c *Client) CreateCheckout(ctx context.Context, body CheckoutRequest, params *CreateCheckoutParams) (*APIResponse[CheckoutResponse], error) {
+	path := "/billing/checkout"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addHeader(header, "Idempotency-Key", params.IdempotencyKey)
+	}
+	var out APIResponse[CheckoutResponse]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateOAuthClient calls POST /admin/oauth/clients
+//
+// Register an OAuth client
+func (c *Client) CreateOAuthClient(ctx context.Context, body CreateOAuthClientRequest) (*APIResponse[OAuthClientInfo], error) {
+	path := "/admin/oauth/clients"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[OAuthClientInfo]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePost calls POST /posts
+//
+// Create a post
+func (c *Client) CreatePost(ctx context.Context, body CreatePostRequest) (*APIResponse[PostInfo], error) {
+	path := "/posts"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[PostInfo]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DecideOAuthConsent calls POST /oauth/consent
+//
+// Approve or deny an authorization request
+func (c *Client) DecideOAuthConsent(ctx context.Context, body ConsentDecision) (*APIResponse[ConsentRedirect], error) {
+	path := "/oauth/consent"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[ConsentRedirect]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DecideOAuthDevice calls POST /oauth/device
+//
+// Approve or deny a device authorization
+func (c *Client) DecideOAuthDevice(ctx context.Context, body DeviceDecision) (*APIResponse[json.RawMessage], error) {
+	path := "/oauth/device"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[json.RawMessage]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteOAuthClient calls DELETE /admin/oauth/clients/{client_id}
+//
+// Delete an OAuth client
+func (c *Client) DeleteOAuthClient(ctx context.Context, clientID string) (*APIResponse[json.RawMessage], error) {
+	path := "/admin/oauth/clients/{client_id}"
+	path = strings.ReplaceAll(path, "{client_id}", url.PathEscape(fmt.Sprint(clientID)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[json.RawMessage]
+	if err := c.do(ctx, "DELETE", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeletePost calls DELETE /posts/{id}
+//
+// Delete a post
+func (c *Client) DeletePost(ctx context.Context, id string) (*APIResponse[json.RawMessage], error) {
+	path := "/posts/{id}"
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[json.RawMessage]
+	if err := c.do(ctx, "DELETE", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTranslation calls DELETE /admin/translations/{language}/{key}
+//
+// Remove a translation override
+func (c *Client) DeleteTranslation(ctx context.Context, language string, key string) (*APIResponse[json.RawMessage], error) {
+	path := "/admin/translations/{language}/{key}"
+	path = strings.ReplaceAll(path, "{language}", url.PathEscape(fmt.Sprint(language)))
+	path = strings.ReplaceAll(path, "{key}", url.PathEscape(fmt.Sprint(key)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[json.RawMessage]
+	if err := c.do(ctx, "DELETE", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteUser calls DELETE /admin/users/{id}
+//
+// Delete a user (Admin only)
+func (c *Client) DeleteUser(ctx context.Context, id string) (*APIResponse[json.RawMessage], error) {
+	path := "/admin/users/{id}"
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[json.RawMessage]
+	if err := c.do(ctx, "DELETE", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportAuditLogsParams holds the query and header parameters of ExportAuditLogs
+type ExportAuditLogsParams struct {
+	// Export format
+	Format *string
+	// Only calls by this user
+	ActorID *string
+	// Only calls with this HTTP method
+	Method *string
+	// Only calls to this route pattern
+	Route *string
+	// Only calls at or after this time (RFC 3339)
+	From *string
+	// Only calls before this time (RFC 3339)
+	To *string
+}
+
+// ExportAuditLogs calls GET /admin/audit-logs/export
+//
+// Export audit log entries
+func (c *Client) ExportAuditLogs(ctx context.Context, params *ExportAuditLogsParams) (*[]byte, error) {
+	path := "/admin/audit-logs/export"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "format", params.Format)
+		addQuery(query, "actor_id", params.ActorID)
+		addQuery(query, "method", params.Method)
+		addQuery(query, "route", params.Route)
+		addQuery(query, "from", params.From)
+		addQuery(query, "to", params.To)
+	}
+	var out []byte
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportTranslations calls GET /admin/translations/{language}/export
+//
+// Export a locale bundle
+func (c *Client) ExportTranslations(ctx context.Context, language string) (*map[string]string, error) {
+	path := "/admin/translations/{language}/export"
+	path = strings.ReplaceAll(path, "{language}", url.PathEscape(fmt.Sprint(language)))
+	query := url.Values{}
+	header := http.Header{}
+	var out map[string]string
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportUsersParams holds the query and header parameters of ExportUsers
+type ExportUsersParams struct {
+	// Export format
+	Format *string
+	// Comma-separated column:asc|desc pairs; without it, search results are ordered by relevance
+	Sort *string
+	// Words to find in the name, username, or email
+	Search *string
+	// Comma-separated fields to export, and the CSV columns in order
+	Fields *string
+	// Filter by role; the filters of GET /users apply
+	Role *string
+	// Filter by active status
+	IsActive *bool
+	// Filter by email status
+	EmailStatus *string
+	// Only users created after this date (YYYY-MM-DD or RFC 3339)
+	CreatedAfter *string
+	// Only users created before this date (YYYY-MM-DD or RFC 3339)
+	CreatedBefore *string
+}
+
+// ExportUsers calls GET /admin/users/export
+//
+// Export users (Admin only)
+func (c *Client) ExportUsers(ctx context.Context, params *ExportUsersParams) (*[]byte, error) {
+	path := "/admin/users/export"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "format", params.Format)
+		addQuery(query, "sort", params.Sort)
+		addQuery(query, "search", params.Search)
+		addQuery(query, "fields", params.Fields)
+		addQuery(query, "role", params.Role)
+		addQuery(query, "is_active", params.IsActive)
+		addQuery(query, "email_status", params.EmailStatus)
+		addQuery(query, "created_after", params.CreatedAfter)
+		addQuery(query, "created_before", params.CreatedBefore)
+	}
+	var out []byte
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAdminStatsParams holds the query and header parameters of GetAdminStats
+type GetAdminStatsParams struct {
+	// Days of signups to return
+	Days *int
+}
+
+// GetAdminStats calls GET /admin/stats
+//
+// Get statistics
+func (c *Client) GetAdminStats(ctx context.Context, params *GetAdminStatsParams) (*APIResponse[AdminStats], error) {
+	path := "/admin/stats"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "days", params.Days)
+	}
+	var out APIResponse[AdminStats]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBotChallenge calls GET /auth/challenge
+//
+// Get a bot challenge token
+func (c *Client) GetBotChallenge(ctx context.Context) (*APIResponse[BotChallengeResponse], error) {
+	path := "/auth/challenge"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[BotChallengeResponse]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMigrationStatus calls GET /admin/migrations
+//
+// Get migration status
+func (c *Client) GetMigrationStatus(ctx context.Context) (*APIResponse[MigrationStatus], error) {
+	path := "/admin/migrations"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[MigrationStatus]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOAuthConsentParams holds the query and header parameters of GetOAuthConsent
+type GetOAuthConsentParams struct {
+	// Must be code
+	ResponseType string
+	// Client ID
+	ClientID string
+	// Registered redirect URI
+	RedirectUri string
+	// Space-separated scopes
+	Scope string
+	// Opaque value returned to the client
+	State *string
+	// Value copied into the ID token
+	Nonce *string
+	// PKCE S256 challenge
+	CodeChallenge *string
+	// Must be S256
+	CodeChallengeMethod *string
+}
+
+// GetOAuthConsent calls GET /oauth/consent
+//
+// Describe an authorization request
+func (c *Client) GetOAuthConsent(ctx context.Context, params *GetOAuthConsentParams) (*APIResponse[ConsentInfo], error) {
+	path := "/oauth/consent"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "response_type", &params.ResponseType)
+		addQuery(query, "client_id", &params.ClientID)
+		addQuery(query, "redirect_uri", &params.RedirectUri)
+		addQuery(query, "scope", &params.Scope)
+		addQuery(query, "state", params.State)
+		addQuery(query, "nonce", params.Nonce)
+		addQuery(query, "code_challenge", params.CodeChallenge)
+		addQuery(query, "code_challenge_method", params.CodeChallengeMethod)
+	}
+	var out APIResponse[ConsentInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOAuthDeviceParams holds the query and header parameters of GetOAuthDevice
+type GetOAuthDeviceParams struct {
+	// User code shown on the device, such as WDJB-MJHT
+	UserCode string
+}
+
+// GetOAuthDevice calls GET /oauth/device
+//
+// Describe a device authorization
+func (c *Client) GetOAuthDevice(ctx context.Context, params *GetOAuthDeviceParams) (*APIResponse[ConsentInfo], error) {
+	path := "/oauth/device"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "user_code", &params.UserCode)
+	}
+	var out APIResponse[ConsentInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOperation calls GET /operations/{id}
+//
+// Get an operation
+func (c *Client) GetOperation(ctx context.Context, id string) (*APIResponse[Operation], error) {
+	path := "/operations/{id}"
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[Operation]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPost calls GET /posts/{id}
+//
+// Get a post
+func (c *Client) GetPost(ctx context.Context, id string) (*APIResponse[PostInfo], error) {
+	path := "/posts/{id}"
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[PostInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProfileParams holds the query and header parameters of GetProfile
+type GetProfileParams struct {
+	// Comma-separated fields to return
+	Fields *string
+	// ETag from a previous response
+	IfNoneMatch *string
+}
+
+// GetProfile calls GET /users/profile
+//
+// Get user profile
+func (c *Client) GetProfile(ctx context.Context, params *GetProfileParams) (*APIResponse[UserInfo], error) {
+	path := "/users/profile"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "fields", params.Fields)
+		addHeader(header, "If-None-Match", params.IfNoneMatch)
+	}
+	var out APIResponse[UserInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetReadOnlyMode calls GET /admin/read-only
+//
+// Get the read-only mode
+func (c *Client) GetReadOnlyMode(ctx context.Context) (*APIResponse[ReadOnlyStatus], error) {
+	path := "/admin/read-only"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[ReadOnlyStatus]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSubscription calls GET /billing/subscription
+//
+// Get current subscription
+func (c *Client) GetSubscription(ctx context.Context) (*APIResponse[SubscriptionInfo], error) {
+	path := "/billing/subscription"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[SubscriptionInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUsage calls GET /users/usage
+//
+// Get usage
+func (c *Client) GetUsage(ctx context.Context) (*APIResponse[UsageInfo], error) {
+	path := "/users/usage"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[UsageInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUsersParams holds the query and header parameters of GetUsers
+type GetUsersParams struct {
+	// Page number
+	Page *int
+	// Page size
+	PageSize *int
+	// Comma-separated column:asc|desc pairs; without it, search results are ordered by relevance
+	Sort *string
+	// Words to find in the name, username, or email, as word prefixes; a search matching no words is matched as a substring
+	Search *string
+	// Comma-separated fields to return
+	Fields *string
+	// Filter by role; any filterable field also accepts [ne], [gt], [lt], or [in] (e.g. role[in]=admin,user)
+	Role *string
+	// Filter by active status
+	IsActive *bool
+	// Filter by email status: the users whose address bounced or complained, to whom no email is sent
+	EmailStatus *string
+	// Filter by the custom attribute name of USER_METADATA_SCHEMA, such as metadata.plan=pro
+	MetadataName *string
+	// Only users created after this date (YYYY-MM-DD or RFC 3339)
+	CreatedAfter *string
+	// Only users created before this date (YYYY-MM-DD or RFC 3339)
+	CreatedBefore *string
+	// Keyset pagination cursor from next/prev; pass an empty cursor for the first page
+	Cursor *string
+	// ETag from a previous response
+	IfNoneMatch *string
+}
+
+// GetUsers calls GET /users
+//
+// Get all users (Admin only)
+func (c *Client) GetUsers(ctx context.Context, params *GetUsersParams) (*APIResponse[PaginatedResponse[[]UserInfo]], error) {
+	path := "/users"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "page", params.Page)
+		addQuery(query, "page_size", params.PageSize)
+		addQuery(query, "sort", params.Sort)
+		addQuery(query, "search", params.Search)
+		addQuery(query, "fields", params.Fields)
+		addQuery(query, "role", params.Role)
+		addQuery(query, "is_active", params.IsActive)
+		addQuery(query, "email_status", params.EmailStatus)
+		addQuery(query, "metadata.name", params.MetadataName)
+		addQuery(query, "created_after", params.CreatedAfter)
+		addQuery(query, "created_before", params.CreatedBefore)
+		addQuery(query, "cursor", params.Cursor)
+		addHeader(header, "If-None-Match", params.IfNoneMatch)
+	}
+	var out APIResponse[PaginatedResponse[[]UserInfo]]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HealthCheck calls GET /health
+//
+// Health check
+func (c *Client) HealthCheck(ctx context.Context) (*APIResponse[HealthResponse], error) {
+	path := "/health"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[HealthResponse]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ImportTranslationsParams holds the query and header parameters of ImportTranslations
+type ImportTranslationsParams struct {
+	// Remove the existing overrides of the language first
+	Replace *bool
+}
+
+// ImportTranslations calls POST /admin/translations/{language}/import
+//
+// Import a locale bundle
+func (c *Client) ImportTranslations(ctx context.Context, language string, body map[string]interface{}, params *ImportTranslationsParams) (*APIResponse[TranslationImport], error) {
+	path := "/admin/translations/{language}/import"
+	path = strings.ReplaceAll(path, "{language}", url.PathEscape(fmt.Sprint(language)))
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "replace", params.Replace)
+	}
+	var out APIResponse[TranslationImport]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAuditLogsParams holds the query and header parameters of ListAuditLogs
+type ListAuditLogsParams struct {
+	// Page number
+	Page *int
+	// Page size
+	PageSize *int
+	// Only calls by this user
+	ActorID *string
+	// Only calls with this HTTP method
+	Method *string
+	// Only calls to this route pattern
+	Route *string
+	// Only calls at or after this time (RFC 3339)
+	From *string
+	// Only calls before this time (RFC 3339)
+	To *string
+}
+
+// ListAuditLogs calls GET /admin/audit-logs
+//
+// List audit log entries
+func (c *Client) ListAuditLogs(ctx context.Context, params *ListAuditLogsParams) (*APIResponse[PaginatedResponse[[]AuditLog]], error) {
+	path := "/admin/audit-logs"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "page", params.Page)
+		addQuery(query, "page_size", params.PageSize)
+		addQuery(query, "actor_id", params.ActorID)
+		addQuery(query, "method", params.Method)
+		addQuery(query, "route", params.Route)
+		addQuery(query, "from", params.From)
+		addQuery(query, "to", params.To)
+	}
+	var out APIResponse[PaginatedResponse[[]AuditLog]]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListLanguages calls GET /languages
+//
+// List languages
+func (c *Client) ListLanguages(ctx context.Context) (*APIResponse[[]LanguageInfo], error) {
+	path := "/languages"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[[]LanguageInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListMissingTranslationsParams holds the query and header parameters of ListMissingTranslations
+type ListMissingTranslationsParams struct {
+	// Only this language
+	Language *string
+}
+
+// ListMissingTranslations calls GET /admin/translations/missing
+//
+// List missing translations
+func (c *Client) ListMissingTranslations(ctx context.Context, params *ListMissingTranslationsParams) (*APIResponse[[]MissingTranslations], error) {
+	path := "/admin/translations/missing"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "language", params.Language)
+	}
+	var out APIResponse[[]MissingTranslations]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListOAuthClients calls GET /admin/oauth/clients
+//
+// List OAuth clients
+func (c *Client) ListOAuthClients(ctx context.Context) (*APIResponse[[]OAuthClientInfo], error) {
+	path := "/admin/oauth/clients"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[[]OAuthClientInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListOAuthConsents calls GET /oauth/consents
+//
+// List authorized applications
+func (c *Client) ListOAuthConsents(ctx context.Context) (*APIResponse[[]OAuthConsent], error) {
+	path := "/oauth/consents"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[[]OAuthConsent]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPlans calls GET /billing/plans
+//
+// List plans
+func (c *Client) ListPlans(ctx context.Context) (*APIResponse[[]PlanInfo], error) {
+	path := "/billing/plans"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[[]PlanInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPostsParams holds the query and header parameters of ListPosts
+type ListPostsParams struct {
+	// Page number
+	Page *int
+	// Page size
+	PageSize *int
+	// Only posts whose title or body contains this term
+	Search *string
+	// Only posts of this user; me for the current user
+	OwnerID *string
+}
+
+// ListPosts calls GET /posts
+//
+// List posts
+func (c *Client) ListPosts(ctx context.Context, params *ListPostsParams) (*APIResponse[PaginatedResponse[[]PostInfo]], error) {
+	path := "/posts"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "page", params.Page)
+		addQuery(query, "page_size", params.PageSize)
+		addQuery(query, "search", params.Search)
+		addQuery(query, "owner_id", params.OwnerID)
+	}
+	var out APIResponse[PaginatedResponse[[]PostInfo]]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSessions calls GET /auth/sessions
+//
+// List sessions
+func (c *Client) ListSessions(ctx context.Context) (*APIResponse[[]SessionInfo], error) {
+	path := "/auth/sessions"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[[]SessionInfo]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTranslationOverridesParams holds the query and header parameters of ListTranslationOverrides
+type ListTranslationOverridesParams struct {
+	// Only this language
+	Language *string
+}
+
+// ListTranslationOverrides calls GET /admin/translations/overrides
+//
+// List translation overrides
+func (c *Client) ListTranslationOverrides(ctx context.Context, params *ListTranslationOverridesParams) (*APIResponse[[]Translation], error) {
+	path := "/admin/translations/overrides"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "language", params.Language)
+	}
+	var out APIResponse[[]Translation]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWebhookEventsParams holds the query and header parameters of ListWebhookEvents
+type ListWebhookEventsParams struct {
+	// Page number
+	Page *int
+	// Page size
+	PageSize *int
+	// Only deliveries from this provider
+	Provider *string
+	// Only deliveries with this status
+	Status *string
+}
+
+// ListWebhookEvents calls GET /admin/webhooks
+//
+// List webhook deliveries
+func (c *Client) ListWebhookEvents(ctx context.Context, params *ListWebhookEventsParams) (*APIResponse[PaginatedResponse[[]WebhookEvent]], error) {
+	path := "/admin/webhooks"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "page", params.Page)
+		addQuery(query, "page_size", params.PageSize)
+		addQuery(query, "provider", params.Provider)
+		addQuery(query, "status", params.Status)
+	}
+	var out APIResponse[PaginatedResponse[[]WebhookEvent]]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LoginParams holds the query and header parameters of Login
+type LoginParams struct {
+	// Challenge token from GET /auth/challenge, required on the routes in BOT_CHALLENGE_ROUTES
+	XBotChallenge *string
+}
+
+// Login calls POST /auth/login
+//
+// Login user
+func (c *Client) Login(ctx context.Context, body LoginRequest, params *LoginParams) (*APIResponse[AuthResponse], error) {
+	path := "/auth/login"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addHeader(header, "X-Bot-Challenge", params.XBotChallenge)
+	}
+	var out APIResponse[AuthResponse]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Logout calls POST /auth/logout
+//
+// Logout user
+func (c *Client) Logout(ctx context.Context, body LogoutRequest) (*APIResponse[json.RawMessage], error) {
+	path := "/auth/logout"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[json.RawMessage]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RefreshToken calls POST /auth/refresh
+//
+// Refresh the token
+func (c *Client) RefreshToken(ctx context.Context, body RefreshRequest) (*APIResponse[AuthResponse], error) {
+	path := "/auth/refresh"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[AuthResponse]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RegisterParams holds the query and header parameters of Register
+type RegisterParams struct {
+	// Challenge token from GET /auth/challenge, required on the routes in BOT_CHALLENGE_ROUTES
+	XBotChallenge *string
+	// Client-generated key to make retries safe
+	IdempotencyKey *string
+}
+
+// Register calls POST /auth/register
+//
+// Register a new user
+func (c *Client) Register(ctx context.Context, body RegisterRequest, params *RegisterParams) (*APIResponse[AuthResponse], error) {
+	path := "/auth/register"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addHeader(header, "X-Bot-Challenge", params.XBotChallenge)
+		addHeader(header, "Idempotency-Key", params.IdempotencyKey)
+	}
+	var out APIResponse[AuthResponse]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReplayWebhookEvent calls POST /admin/webhooks/{id}/replay
+//
+// Replay a webhook delivery
+func (c *Client) ReplayWebhookEvent(ctx context.Context, id string) (*APIResponse[WebhookEvent], error) {
+	path := "/admin/webhooks/{id}/replay"
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[WebhookEvent]
+	if err := c.do(ctx, "POST", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestoreBackupParams holds the query and header parameters of RestoreBackup
+type RestoreBackupParams struct {
+	// respond-async to restore in the background
+	Prefer *string
+}
+
+// RestoreBackup calls POST /admin/backup/restore
+//
+// Restore the users from a backup
+func (c *Client) RestoreBackup(ctx context.Context, body RestoreBackupRequest, params *RestoreBackupParams) (*APIResponse[RestoreBackupResponse], error) {
+	path := "/admin/backup/restore"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addHeader(header, "Prefer", params.Prefer)
+	}
+	var out APIResponse[RestoreBackupResponse]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestoreUser calls POST /admin/users/{id}/restore
+//
+// Restore a deleted user (Admin only)
+func (c *Client) RestoreUser(ctx context.Context, id string) (*APIResponse[UserInfo], error) {
+	path := "/admin/users/{id}/restore"
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[UserInfo]
+	if err := c.do(ctx, "POST", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeOAuthConsent calls DELETE /oauth/consents/{client_id}
+//
+// Revoke an application's access
+func (c *Client) RevokeOAuthConsent(ctx context.Context, clientID string) (*APIResponse[json.RawMessage], error) {
+	path := "/oauth/consents/{client_id}"
+	path = strings.ReplaceAll(path, "{client_id}", url.PathEscape(fmt.Sprint(clientID)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[json.RawMessage]
+	if err := c.do(ctx, "DELETE", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeSession calls DELETE /auth/sessions/{id}
+//
+// End a session
+func (c *Client) RevokeSession(ctx context.Context, id string) (*APIResponse[json.RawMessage], error) {
+	path := "/auth/sessions/{id}"
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[json.RawMessage]
+	if err := c.do(ctx, "DELETE", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ServiceGetUsersParams holds the query and header parameters of ServiceGetUsers
+type ServiceGetUsersParams struct {
+	// Page number
+	Page *int
+	// Page size
+	PageSize *int
+	// Comma-separated column:asc|desc pairs
+	Sort *string
+	// Words to find in the name, username, or email
+	Search *string
+	// Comma-separated fields to return
+	Fields *string
+	// Keyset pagination cursor from next/prev; pass an empty cursor for the first page
+	Cursor *string
+}
+
+// ServiceGetUsers calls GET /service/users
+//
+// List users for a service
+func (c *Client) ServiceGetUsers(ctx context.Context, params *ServiceGetUsersParams) (*APIResponse[PaginatedResponse[[]UserInfo]], error) {
+	path := "/service/users"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addQuery(query, "page", params.Page)
+		addQuery(query, "page_size", params.PageSize)
+		addQuery(query, "sort", params.Sort)
+		addQuery(query, "search", params.Search)
+		addQuery(query, "fields", params.Fields)
+		addQuery(query, "cursor", params.Cursor)
+	}
+	var out APIResponse[PaginatedResponse[[]UserInfo]]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetReadOnlyMode calls PUT /admin/read-only
+//
+// Turn the read-only mode on or off
+func (c *Client) SetReadOnlyMode(ctx context.Context, body SetReadOnlyRequest) (*APIResponse[ReadOnlyStatus], error) {
+	path := "/admin/read-only"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[ReadOnlyStatus]
+	if err := c.do(ctx, "PUT", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetTranslation calls PUT /admin/translations/{language}/{key}
+//
+// Override a translation
+func (c *Client) SetTranslation(ctx context.Context, language string, key string, body SetTranslationRequest) (*APIResponse[Translation], error) {
+	path := "/admin/translations/{language}/{key}"
+	path = strings.ReplaceAll(path, "{language}", url.PathEscape(fmt.Sprint(language)))
+	path = strings.ReplaceAll(path, "{key}", url.PathEscape(fmt.Sprint(key)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[Translation]
+	if err := c.do(ctx, "PUT", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Setup calls POST /setup
+//
+// Create the first superadmin
+func (c *Client) Setup(ctx context.Context, body SetupRequest) (*APIResponse[UserInfo], error) {
+	path := "/setup"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[UserInfo]
+	if err := c.do(ctx, "POST", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdatePost calls PUT /posts/{id}
+//
+// Update a post
+func (c *Client) UpdatePost(ctx context.Context, id string, body UpdatePostRequest) (*APIResponse[PostInfo], error) {
+	path := "/posts/{id}"
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[PostInfo]
+	if err := c.do(ctx, "PUT", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateProfileParams holds the query and header parameters of UpdateProfile
+type UpdateProfileParams struct {
+	// ETag of the profile being updated; rejects the update if it changed
+	IfMatch *string
+}
+
+// UpdateProfile calls PUT /users/profile
+//
+// Update user profile
+func (c *Client) UpdateProfile(ctx context.Context, body UpdateUserRequest, params *UpdateProfileParams) (*APIResponse[UserInfo], error) {
+	path := "/users/profile"
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		addHeader(header, "If-Match", params.IfMatch)
+	}
+	var out APIResponse[UserInfo]
+	if err := c.do(ctx, "PUT", path, query, header, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// VerifyAuditLogs calls GET /admin/audit-logs/verify
+//
+// Verify the audit log
+func (c *Client) VerifyAuditLogs(ctx context.Context) (*APIResponse[AuditVerification], error) {
+	path := "/admin/audit-logs/verify"
+	query := url.Values{}
+	header := http.Header{}
+	var out APIResponse[AuditVerification]
+	if err := c.do(ctx, "GET", path, query, header, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
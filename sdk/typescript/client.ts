@@ -0,0 +1,1116 @@
+// Code generated by sdkgen from the OpenAPI document. DO NOT EDIT.
+// Typed client for Backend API Template (version 1.0).
+
+const BASE_PATH = "/api/v1";
+
+export interface APIResponse<T = unknown> {
+  /** Code identifies the error for clients to branch on; the codes are listed in the errcodes package */
+  code?: string;
+  data?: T;
+  error?: string;
+  errors?: FieldError[];
+  message?: string;
+  /** RequestID identifies the request in the logs; it is set on unexpected server errors and on requests that match no route */
+  request_id?: string;
+  success?: boolean;
+}
+
+export interface ActiveUsers {
+  last_24h?: number;
+  last_30d?: number;
+  last_7d?: number;
+}
+
+export interface Address {
+  city?: string;
+  country?: string;
+  line1?: string;
+  line2?: string;
+  postal_code?: string;
+  region?: string;
+}
+
+export interface AdminStats {
+  active_users?: ActiveUsers;
+  databases?: DatabaseSize[];
+  /** GeneratedAt is when the figures were computed; they are cached for ADMIN_STATS_CACHE_TTL */
+  generated_at?: string;
+  logins?: LoginStats;
+  /** Signups counts the registrations of each of the last days, oldest first, including since deleted users */
+  signups?: DailyCount[];
+  users?: UserStats;
+}
+
+export interface AuditLog {
+  /** ActorID is the admin who made the call */
+  actor_id?: string;
+  actor_role?: string;
+  client_ip?: string;
+  created_at?: string;
+  /** Hash is the SHA-256 of the entry, including PrevHash */
+  hash?: string;
+  id?: string;
+  method?: string;
+  path?: string;
+  /** PayloadHash is the SHA-256 of the request body, empty without one */
+  payload_hash?: string;
+  /** PrevHash is the hash of the previous entry of the chain, empty for the first one */
+  prev_hash?: string;
+  request_id?: string;
+  /** Route is the route pattern, as in /api/v1/admin/users/:id */
+  route?: string;
+  seq?: number;
+  /** Status is the HTTP status of the response */
+  status?: number;
+}
+
+export interface AuditVerification {
+  /** BrokenAt is the ID of the first entry that failed the check */
+  broken_at?: string;
+  /** Checked is the number of entries checked */
+  checked?: number;
+  /** Reason tells why the entry failed */
+  reason?: string;
+  /** Valid is false when an entry does not match its hash or the chain has a gap or a broken link */
+  valid?: boolean;
+}
+
+export interface AuthResponse {
+  expires_at?: string;
+  refresh_expires_at?: string;
+  /** RefreshToken gets new tokens from POST /auth/refresh until RefreshExpiresAt; omitted without sessions */
+  refresh_token?: string;
+  /** SessionID identifies the session in GET /auth/sessions */
+  session_id?: string;
+  token?: string;
+  user?: UserInfo;
+}
+
+export interface BackupResponse {
+  /** Archive is the archive encrypted with BACKUP_ENCRYPTION_KEY, base64-encoded */
+  archive?: string;
+  created_at?: string;
+  source?: string;
+  users?: number;
+}
+
+export interface BotChallengeResponse {
+  expires_at?: string;
+  /** Token goes in the X-Bot-Challenge header of one login or registration request */
+  token?: string;
+}
+
+export interface BroadcastRequest {
+  data?: Record<string, unknown>;
+  message: string;
+}
+
+export interface BroadcastResponse {
+  connections?: number;
+}
+
+export interface BulkUserResult {
+  /** Error says why the action failed for the user */
+  error?: string;
+  id?: string;
+  status?: string;
+}
+
+export interface BulkUsersRequest {
+  action: string;
+  /** Filter holds the search and filter query parameters of GET /users, such as role or created_before */
+  filter?: Record<string, string>;
+  ids?: string[];
+  /** Reason is recorded in the security log with each role change */
+  reason?: string;
+  /** Role is the new role of the role action */
+  role?: string;
+}
+
+export interface BulkUsersResponse {
+  action?: string;
+  failed?: number;
+  matched?: number;
+  results?: BulkUserResult[];
+  succeeded?: number;
+}
+
+export interface ChangeRoleRequest {
+  /** Reason is recorded in the security log */
+  reason: string;
+  role: string;
+}
+
+export interface CheckoutRequest {
+  plan_id: string;
+}
+
+export interface CheckoutResponse {
+  session_id?: string;
+  url?: string;
+}
+
+export interface ConsentDecision {
+  approve?: boolean;
+  client_id: string;
+  code_challenge?: string;
+  code_challenge_method?: string;
+  nonce?: string;
+  redirect_uri: string;
+  response_type?: string;
+  scope?: string;
+  state?: string;
+}
+
+export interface ConsentInfo {
+  client?: OAuthClientSummary;
+  /** ConsentRequired is false when the user already allowed these scopes or the client is trusted */
+  consent_required?: boolean;
+  /** Scopes are the scopes the client asks for */
+  scopes?: string[];
+}
+
+export interface ConsentRedirect {
+  redirect_to?: string;
+}
+
+export interface CreateOAuthClientRequest {
+  name: string;
+  /** Public clients get no secret and must use PKCE */
+  public?: boolean;
+  /** RedirectURIs are required, except for service clients, which have none */
+  redirect_uris?: string[];
+  /** Scopes limits what the client may request; empty allows every OpenID Connect scope */
+  scopes?: string[];
+  /** Service clients are backends that call the API for themselves with service scopes, such as users:read */
+  service?: boolean;
+  /** Trusted clients, such as first-party apps, are not shown a consent screen */
+  trusted?: boolean;
+}
+
+export interface CreatePostRequest {
+  body: string;
+  title: string;
+}
+
+export interface DailyCount {
+  count?: number;
+  date?: string;
+}
+
+export interface DatabaseSize {
+  database?: string;
+  size_bytes?: number;
+  /** Tables lists the tables or collections, largest first, where the database reports them */
+  tables?: TableSize[];
+}
+
+export interface DeviceDecision {
+  approve?: boolean;
+  user_code: string;
+}
+
+export interface FieldError {
+  field?: string;
+  message?: string;
+  rule?: string;
+}
+
+export interface HealthResponse {
+  checks?: Record<string, ServiceHealth>;
+  services?: Record<string, string>;
+  status?: string;
+  timestamp?: string;
+  version?: string;
+}
+
+export interface LanguageInfo {
+  code?: string;
+  default?: boolean;
+  /** Direction is the writing direction, ltr or rtl */
+  direction?: string;
+  /** Name is the name of the language in the language itself */
+  name?: string;
+}
+
+export interface LoginRequest {
+  email: string;
+  password: string;
+  /** RememberMe starts a session with the longer REMEMBER_ME_ lifetimes instead of the SESSION_ ones */
+  remember_me?: boolean;
+}
+
+export interface LoginStats {
+  failure_rate?: number;
+  failures?: number;
+  successes?: number;
+  window?: string;
+}
+
+export interface LogoutRequest {
+  refresh_token?: string;
+}
+
+export interface MigrationInfo {
+  applied?: boolean;
+  name?: string;
+  version?: number;
+}
+
+export interface MigrationStatus {
+  dirty?: boolean;
+  latest?: number;
+  migrations?: MigrationInfo[];
+  version?: number;
+}
+
+export interface MissingTranslations {
+  keys?: string[];
+  language?: string;
+}
+
+export interface OAuthClientInfo {
+  client_id?: string;
+  client_secret?: string;
+  created_at?: string;
+  name?: string;
+  public?: boolean;
+  redirect_uris?: string[];
+  scopes?: string[];
+  service?: boolean;
+  trusted?: boolean;
+}
+
+export interface OAuthClientSummary {
+  client_id?: string;
+  name?: string;
+}
+
+export interface OAuthConsent {
+  client_id?: string;
+  scopes?: string[];
+  updated_at?: string;
+}
+
+export interface OAuthError {
+  error?: string;
+  error_description?: string;
+}
+
+export interface Operation {
+  /** Attempts counts the times a worker took the operation */
+  attempts?: number;
+  created_at?: string;
+  /** Done and Total are the progress in items, such as users; Total is 0 when unknown */
+  done?: number;
+  error?: string;
+  finished_at?: string;
+  id?: string;
+  kind?: string;
+  /** OwnerID is the user who started the operation */
+  owner_id?: string;
+  /** Result is the response data of the operation once it succeeded */
+  result?: Record<string, unknown>;
+  status?: string;
+  total?: number;
+  updated_at?: string;
+}
+
+export interface PaginatedResponse<T = unknown> {
+  data?: T;
+  pagination?: Pagination;
+}
+
+export interface Pagination {
+  next?: string;
+  next_cursor?: string;
+  page?: number;
+  page_size?: number;
+  prev?: string;
+  prev_cursor?: string;
+  total?: number;
+  total_page?: number;
+}
+
+export interface PlanInfo {
+  id?: string;
+  rank?: number;
+}
+
+export interface PostInfo {
+  body?: string;
+  created_at?: string;
+  id?: string;
+  owner_id?: string;
+  title?: string;
+  updated_at?: string;
+}
+
+export interface ReadOnlyStatus {
+  enabled?: boolean;
+  /** Reason is the message of the 503 responses, empty for the default one */
+  reason?: string;
+  /** Since is when read-only mode was turned on */
+  since?: string;
+}
+
+export interface RefreshRequest {
+  refresh_token?: string;
+}
+
+export interface RegisterRequest {
+  /** AnalyticsOptOut excludes the user from product analytics, including the signup event */
+  analytics_opt_out?: boolean;
+  email: string;
+  first_name: string;
+  last_name: string;
+  /** Locale is the preferred language, used for responses when a request has no Accept-Language */
+  locale?: string;
+  password: string;
+  username: string;
+}
+
+export interface RestoreBackupRequest {
+  /** Archive is the encrypted archive, base64-encoded */
+  archive: string;
+}
+
+export interface RestoreBackupResponse {
+  created?: number;
+  /** CreatedAt is when the archive was made */
+  created_at?: string;
+  updated?: number;
+  /** Users is the number of users in the archive */
+  users?: number;
+}
+
+export interface RoleCount {
+  count?: number;
+  role?: string;
+}
+
+export interface ServiceHealth {
+  critical?: boolean;
+  last_success?: string;
+  latency_ms?: number;
+  status?: string;
+}
+
+export interface SessionInfo {
+  created_at?: string;
+  /** Current is true for the session of the token the list was requested with */
+  current?: boolean;
+  /** ExpiresAt is when the session ends unless it is refreshed first */
+  expires_at?: string;
+  id?: string;
+  ip_address?: string;
+  last_used_at?: string;
+  /** MaxExpiresAt is when the session ends however often it is refreshed */
+  max_expires_at?: string;
+  /** RememberMe is true for sessions started with remember_me, which stay signed in longer */
+  remember_me?: boolean;
+  user_agent?: string;
+}
+
+export interface SetReadOnlyRequest {
+  enabled: boolean;
+  /** Reason is shown to clients in the 503 responses while read-only mode is on */
+  reason?: string;
+}
+
+export interface SetTranslationRequest {
+  text: string;
+}
+
+export interface SetupRequest {
+  /** AnalyticsOptOut excludes the user from product analytics, including the signup event */
+  analytics_opt_out?: boolean;
+  email: string;
+  first_name: string;
+  last_name: string;
+  /** Locale is the preferred language, used for responses when a request has no Accept-Language */
+  locale?: string;
+  password: string;
+  token: string;
+  username: string;
+}
+
+export interface SubscriptionInfo {
+  cancel_at_period_end?: boolean;
+  current_period_end?: string;
+  plan_id?: string;
+  status?: string;
+}
+
+export interface TableSize {
+  name?: string;
+  size_bytes?: number;
+}
+
+export interface Translation {
+  key?: string;
+  language?: string;
+  text?: string;
+  updated_at?: string;
+}
+
+export interface TranslationImport {
+  /** Imported counts the texts stored as overrides; texts equal to those of the files are not stored */
+  imported?: number;
+  language?: string;
+  /** Removed counts the overrides deleted because replace was set */
+  removed?: number;
+}
+
+export interface UpdatePostRequest {
+  body?: string;
+  title?: string;
+}
+
+export interface UpdateUserRequest {
+  address?: Address;
+  /** AnalyticsOptOut changes the analytics preference when set */
+  analytics_opt_out?: boolean;
+  /** The profile fields below change when set; an empty value (an empty object for Address) clears them */
+  avatar_url?: string;
+  bio?: string;
+  birthday?: string;
+  email?: string;
+  first_name?: string;
+  last_name?: string;
+  locale?: string;
+  /** Metadata sets the custom attributes defined by USER_METADATA_SCHEMA; a null value removes one */
+  metadata?: Record<string, unknown>;
+  phone?: string;
+}
+
+export interface UsageInfo {
+  bytes?: number;
+  limit?: number;
+  period?: string;
+  plan?: string;
+  remaining?: number;
+  requests?: number;
+  resets_at?: string;
+}
+
+export interface UserInfo {
+  address?: Address;
+  analytics_opt_out?: boolean;
+  avatar_url?: string;
+  bio?: string;
+  birthday?: string;
+  created_at?: string;
+  email?: string;
+  /** EmailStatus is set once mail to the address bounced or was reported as spam; no email is sent to it until the email changes */
+  email_status?: string;
+  email_status_at?: string;
+  first_name?: string;
+  /** ID is a decimal number or a UUID on PostgreSQL and a hex ObjectID on MongoDB, always a string */
+  id?: string;
+  is_active?: boolean;
+  last_name?: string;
+  locale?: string;
+  metadata?: Record<string, unknown>;
+  phone?: string;
+  role?: string;
+  updated_at?: string;
+  username?: string;
+}
+
+export interface UserStats {
+  active?: number;
+  by_role?: RoleCount[];
+  deleted?: number;
+  inactive?: number;
+  /** Total counts the users that are not deleted */
+  total?: number;
+}
+
+export interface WebhookEvent {
+  /** Attempts counts the times the delivery was processed */
+  attempts?: number;
+  /** EventID is the provider's ID of the delivery, the same in each of its retries */
+  event_id?: string;
+  id?: string;
+  last_error?: string;
+  /** Payload is the body of the delivery as received */
+  payload?: string;
+  processed_at?: string;
+  provider?: string;
+  received_at?: string;
+  status?: string;
+  type?: string;
+  updated_at?: string;
+}
+
+export interface BulkUsersParams {
+  /** respond-async to run the action in the background */
+  Prefer?: string;
+}
+
+export interface CreateBackupParams {
+  /** respond-async to back up in the background */
+  Prefer?: string;
+}
+
+export interface CreateCheckoutParams {
+  /** Client-generated key to make retries safe */
+  "Idempotency-Key"?: string;
+}
+
+export interface ExportAuditLogsParams {
+  /** Export format */
+  format?: string;
+  /** Only calls by this user */
+  actor_id?: string;
+  /** Only calls with this HTTP method */
+  method?: string;
+  /** Only calls to this route pattern */
+  route?: string;
+  /** Only calls at or after this time (RFC 3339) */
+  from?: string;
+  /** Only calls before this time (RFC 3339) */
+  to?: string;
+}
+
+export interface ExportUsersParams {
+  /** Export format */
+  format?: string;
+  /** Comma-separated column:asc|desc pairs; without it, search results are ordered by relevance */
+  sort?: string;
+  /** Words to find in the name, username, or email */
+  search?: string;
+  /** Comma-separated fields to export, and the CSV columns in order */
+  fields?: string;
+  /** Filter by role; the filters of GET /users apply */
+  role?: string;
+  /** Filter by active status */
+  is_active?: boolean;
+  /** Filter by email status */
+  email_status?: string;
+  /** Only users created after this date (YYYY-MM-DD or RFC 3339) */
+  created_after?: string;
+  /** Only users created before this date (YYYY-MM-DD or RFC 3339) */
+  created_before?: string;
+}
+
+export interface GetAdminStatsParams {
+  /** Days of signups to return */
+  days?: number;
+}
+
+export interface GetOAuthConsentParams {
+  /** Must be code */
+  response_type: string;
+  /** Client ID */
+  client_id: string;
+  /** Registered redirect URI */
+  redirect_uri: string;
+  /** Space-separated scopes */
+  scope: string;
+  /** Opaque value returned to the client */
+  state?: string;
+  /** Value copied into the ID token */
+  nonce?: string;
+  /** PKCE S256 challenge */
+  code_challenge?: string;
+  /** Must be S256 */
+  code_challenge_method?: string;
+}
+
+export interface GetOAuthDeviceParams {
+  /** User code shown on the device, such as WDJB-MJHT */
+  user_code: string;
+}
+
+export interface GetProfileParams {
+  /** Comma-separated fields to return */
+  fields?: string;
+  /** ETag from a previous response */
+  "If-None-Match"?: string;
+}
+
+export interface GetUsersParams {
+  /** Page number */
+  page?: number;
+  /** Page size */
+  page_size?: number;
+  /** Comma-separated column:asc|desc pairs; without it, search results are ordered by relevance */
+  sort?: string;
+  /** Words to find in the name, username, or email, as word prefixes; a search matching no words is matched as a substring */
+  search?: string;
+  /** Comma-separated fields to return */
+  fields?: string;
+  /** Filter by role; any filterable field also accepts [ne], [gt], [lt], or [in] (e.g. role[in]=admin,user) */
+  role?: string;
+  /** Filter by active status */
+  is_active?: boolean;
+  /** Filter by email status: the users whose address bounced or complained, to whom no email is sent */
+  email_status?: string;
+  /** Filter by the custom attribute name of USER_METADATA_SCHEMA, such as metadata.plan=pro */
+  "metadata.name"?: string;
+  /** Only users created after this date (YYYY-MM-DD or RFC 3339) */
+  created_after?: string;
+  /** Only users created before this date (YYYY-MM-DD or RFC 3339) */
+  created_before?: string;
+  /** Keyset pagination cursor from next/prev; pass an empty cursor for the first page */
+  cursor?: string;
+  /** ETag from a previous response */
+  "If-None-Match"?: string;
+}
+
+export interface ImportTranslationsParams {
+  /** Remove the existing overrides of the language first */
+  replace?: boolean;
+}
+
+export interface ListAuditLogsParams {
+  /** Page number */
+  page?: number;
+  /** Page size */
+  page_size?: number;
+  /** Only calls by this user */
+  actor_id?: string;
+  /** Only calls with this HTTP method */
+  method?: string;
+  /** Only calls to this route pattern */
+  route?: string;
+  /** Only calls at or after this time (RFC 3339) */
+  from?: string;
+  /** Only calls before this time (RFC 3339) */
+  to?: string;
+}
+
+export interface ListMissingTranslationsParams {
+  /** Only this language */
+  language?: string;
+}
+
+export interface ListPostsParams {
+  /** Page number */
+  page?: number;
+  /** Page size */
+  page_size?: number;
+  /** Only posts whose title or body contains this term */
+  search?: string;
+  /** Only posts of this user; me for the current user */
+  owner_id?: string;
+}
+
+export interface ListTranslationOverridesParams {
+  /** Only this language */
+  language?: string;
+}
+
+export interface ListWebhookEventsParams {
+  /** Page number */
+  page?: number;
+  /** Page size */
+  page_size?: number;
+  /** Only deliveries from this provider */
+  provider?: string;
+  /** Only deliveries with this status */
+  status?: string;
+}
+
+export interface LoginParams {
+  /** Challenge token from GET /auth/challenge, required on the routes in BOT_CHALLENGE_ROUTES */
+  "X-Bot-Challenge"?: string;
+}
+
+export interface RegisterParams {
+  /** Challenge token from GET /auth/challenge, required on the routes in BOT_CHALLENGE_ROUTES */
+  "X-Bot-Challenge"?: string;
+  /** Client-generated key to make retries safe */
+  "Idempotency-Key"?: string;
+}
+
+export interface RestoreBackupParams {
+  /** respond-async to restore in the background */
+  Prefer?: string;
+}
+
+export interface ServiceGetUsersParams {
+  /** Page number */
+  page?: number;
+  /** Page size */
+  page_size?: number;
+  /** Comma-separated column:asc|desc pairs */
+  sort?: string;
+  /** Words to find in the name, username, or email */
+  search?: string;
+  /** Comma-separated fields to return */
+  fields?: string;
+  /** Keyset pagination cursor from next/prev; pass an empty cursor for the first page */
+  cursor?: string;
+}
+
+export interface UpdateProfileParams {
+  /** ETag of the profile being updated; rejects the update if it changed */
+  "If-Match"?: string;
+}
+
+export class ApiError extends Error {
+  constructor(
+    public readonly status: number,
+    public readonly body: unknown,
+  ) {
+    const payload = body as { message?: string; title?: string } | undefined;
+    super(payload?.message || payload?.title || `unexpected status ${status}`);
+    this.name = "ApiError";
+  }
+}
+
+export interface ClientOptions {
+  /** Sent as a Bearer token when set */
+  token?: string;
+  /** Sent as Accept-Language when set */
+  language?: string;
+  /** Fetch implementation; defaults to the global fetch */
+  fetch?: typeof fetch;
+}
+
+type Query = Record<string, unknown>;
+
+export class ApiClient {
+  private readonly baseUrl: string;
+  private readonly fetchImpl: typeof fetch;
+  token?: string;
+  language?: string;
+
+  constructor(baseUrl: string, options: ClientOptions = {}) {
+    this.baseUrl = baseUrl.replace(/\/$/, "");
+    this.fetchImpl = options.fetch ?? fetch.bind(globalThis);
+    this.token = options.token;
+    this.language = options.language;
+  }
+
+  private async request<T>(method: string, path: string, query: Query = {}, headers: Query = {}, body?: unknown, raw = false): Promise<T> {
+    const url = new URL(this.baseUrl + BASE_PATH + path);
+    for (const [name, value] of Object.entries(query)) {
+      if (value !== undefined && value !== null) {
+        url.searchParams.set(name, String(value));
+      }
+    }
+
+    const requestHeaders: Record<string, string> = { Accept: "application/json" };
+    for (const [name, value] of Object.entries(headers)) {
+      if (value !== undefined && value !== null) {
+        requestHeaders[name] = String(value);
+      }
+    }
+    if (body !== undefined) {
+      requestHeaders["Content-Type"] = "application/json";
+    }
+    if (this.token) {
+      requestHeaders["Authorization"] = `Bearer ${this.token}`;
+    }
+    if (this.language) {
+      requestHeaders["Accept-Language"] = this.language;
+    }
+
+    const response = await this.fetchImpl(url.toString(), {
+      method,
+      headers: requestHeaders,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+
+    const text = await response.text();
+    if (raw && response.ok) {
+      return text as T;
+    }
+    let data: unknown = undefined;
+    if (text) {
+      try {
+        data = JSON.parse(text);
+      } catch {
+        data = text;
+      }
+    }
+    if (!response.ok) {
+      throw new ApiError(response.status, data);
+    }
+    return data as T;
+  }
+
+  /** Broadcast a message to all connected clients (Admin only) (POST /admin/broadcast) */
+  broadcast(body: BroadcastRequest): Promise<APIResponse<BroadcastResponse>> {
+    return this.request<APIResponse<BroadcastResponse>>("POST", "/admin/broadcast", {}, {}, body);
+  }
+
+  /** Act on many users at once (Admin only) (POST /admin/users/bulk) */
+  bulkUsers(body: BulkUsersRequest, params: BulkUsersParams = {}): Promise<APIResponse<BulkUsersResponse>> {
+    return this.request<APIResponse<BulkUsersResponse>>("POST", "/admin/users/bulk", {}, { Prefer: params.Prefer }, body);
+  }
+
+  /** Change a user's role (Superadmin only) (PATCH /admin/users/{id}/role) */
+  changeUserRole(iD: string, body: ChangeRoleRequest): Promise<APIResponse<UserInfo>> {
+    return this.request<APIResponse<UserInfo>>("PATCH", "/admin/users/" + encodeURIComponent(String(iD)) + "/role", {}, {}, body);
+  }
+
+  /** Back up the users (GET /admin/backup) */
+  createBackup(params: CreateBackupParams = {}): Promise<APIResponse<BackupResponse>> {
+    return this.request<APIResponse<BackupResponse>>("GET", "/admin/backup", {}, { Prefer: params.Prefer });
+  }
+
+  /** Start a subscription checkout (POST /billing/checkout) */
+  createCheckout(body: CheckoutRequest, params: CreateCheckoutParams = {}): Promise<APIResponse<CheckoutResponse>> {
+    return this.request<APIResponse<CheckoutResponse>>("POST", "/billing/checkout", {}, { "Idempotency-Key": params["Idempotency-Key"] }, body);
+  }
+
+  /** Register an OAuth client (POST /admin/oauth/clients) */
+  createOAuthClient(body: CreateOAuthClientRequest): Promise<APIResponse<OAuthClientInfo>> {
+    return this.request<APIResponse<OAuthClientInfo>>("POST", "/admin/oauth/clients", {}, {}, body);
+  }
+
+  /** Create a post (POST /posts) */
+  createPost(body: CreatePostRequest): Promise<APIResponse<PostInfo>> {
+    return this.request<APIResponse<PostInfo>>("POST", "/posts", {}, {}, body);
+  }
+
+  /** Approve or deny an authorization request (POST /oauth/consent) */
+  decideOAuthConsent(body: ConsentDecision): Promise<APIResponse<ConsentRedirect>> {
+    return this.request<APIResponse<ConsentRedirect>>("POST", "/oauth/consent", {}, {}, body);
+  }
+
+  /** Approve or deny a device authorization (POST /oauth/device) */
+  decideOAuthDevice(body: DeviceDecision): Promise<APIResponse<unknown>> {
+    return this.request<APIResponse<unknown>>("POST", "/oauth/device", {}, {}, body);
+  }
+
+  /** Delete an OAuth client (DELETE /admin/oauth/clients/{client_id}) */
+  deleteOAuthClient(clientID: string): Promise<APIResponse<unknown>> {
+    return this.request<APIResponse<unknown>>("DELETE", "/admin/oauth/clients/" + encodeURIComponent(String(clientID)) + "", {}, {});
+  }
+
+  /** Delete a post (DELETE /posts/{id}) */
+  deletePost(iD: string): Promise<APIResponse<unknown>> {
+    return this.request<APIResponse<unknown>>("DELETE", "/posts/" + encodeURIComponent(String(iD)) + "", {}, {});
+  }
+
+  /** Remove a translation override (DELETE /admin/translations/{language}/{key}) */
+  deleteTranslation(language: string, key: string): Promise<APIResponse<unknown>> {
+    return this.request<APIResponse<unknown>>("DELETE", "/admin/translations/" + encodeURIComponent(String(language)) + "/" + encodeURIComponent(String(key)) + "", {}, {});
+  }
+
+  /** Delete a user (Admin only) (DELETE /admin/users/{id}) */
+  deleteUser(iD: string): Promise<APIResponse<unknown>> {
+    return this.request<APIResponse<unknown>>("DELETE", "/admin/users/" + encodeURIComponent(String(iD)) + "", {}, {});
+  }
+
+  /** Export audit log entries (GET /admin/audit-logs/export) */
+  exportAuditLogs(params: ExportAuditLogsParams = {}): Promise<string> {
+    return this.request<string>("GET", "/admin/audit-logs/export", { format: params.format, actor_id: params.actor_id, method: params.method, route: params.route, from: params.from, to: params.to }, {}, undefined, true);
+  }
+
+  /** Export a locale bundle (GET /admin/translations/{language}/export) */
+  exportTranslations(language: string): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("GET", "/admin/translations/" + encodeURIComponent(String(language)) + "/export", {}, {});
+  }
+
+  /** Export users (Admin only) (GET /admin/users/export) */
+  exportUsers(params: ExportUsersParams = {}): Promise<string> {
+    return this.request<string>("GET", "/admin/users/export", { format: params.format, sort: params.sort, search: params.search, fields: params.fields, role: params.role, is_active: params.is_active, email_status: params.email_status, created_after: params.created_after, created_before: params.created_before }, {}, undefined, true);
+  }
+
+  /** Get statistics (GET /admin/stats) */
+  getAdminStats(params: GetAdminStatsParams = {}): Promise<APIResponse<AdminStats>> {
+    return this.request<APIResponse<AdminStats>>("GET", "/admin/stats", { days: params.days }, {});
+  }
+
+  /** Get a bot challenge token (GET /auth/challenge) */
+  getBotChallenge(): Promise<APIResponse<BotChallengeResponse>> {
+    return this.request<APIResponse<BotChallengeResponse>>("GET", "/auth/challenge", {}, {});
+  }
+
+  /** Get migration status (GET /admin/migrations) */
+  getMigrationStatus(): Promise<APIResponse<MigrationStatus>> {
+    return this.request<APIResponse<MigrationStatus>>("GET", "/admin/migrations", {}, {});
+  }
+
+  /** Describe an authorization request (GET /oauth/consent) */
+  getOAuthConsent(params: GetOAuthConsentParams): Promise<APIResponse<ConsentInfo>> {
+    return this.request<APIResponse<ConsentInfo>>("GET", "/oauth/consent", { response_type: params.response_type, client_id: params.client_id, redirect_uri: params.redirect_uri, scope: params.scope, state: params.state, nonce: params.nonce, code_challenge: params.code_challenge, code_challenge_method: params.code_challenge_method }, {});
+  }
+
+  /** Describe a device authorization (GET /oauth/device) */
+  getOAuthDevice(params: GetOAuthDeviceParams): Promise<APIResponse<ConsentInfo>> {
+    return this.request<APIResponse<ConsentInfo>>("GET", "/oauth/device", { user_code: params.user_code }, {});
+  }
+
+  /** Get an operation (GET /operations/{id}) */
+  getOperation(iD: string): Promise<APIResponse<Operation>> {
+    return this.request<APIResponse<Operation>>("GET", "/operations/" + encodeURIComponent(String(iD)) + "", {}, {});
+  }
+
+  /** Get a post (GET /posts/{id}) */
+  getPost(iD: string): Promise<APIResponse<PostInfo>> {
+    return this.request<APIResponse<PostInfo>>("GET", "/posts/" + encodeURIComponent(String(iD)) + "", {}, {});
+  }
+
+  /** Get user profile (GET /users/profile) */
+  getProfile(params: GetProfileParams = {}): Promise<APIResponse<UserInfo>> {
+    return this.request<APIResponse<UserInfo>>("GET", "/users/profile", { fields: params.fields }, { "If-None-Match": params["If-None-Match"] });
+  }
+
+  /** Get the read-only mode (GET /admin/read-only) */
+  getReadOnlyMode(): Promise<APIResponse<ReadOnlyStatus>> {
+    return this.request<APIResponse<ReadOnlyStatus>>("GET", "/admin/read-only", {}, {});
+  }
+
+  /** Get current subscription (GET /billing/subscription) */
+  getSubscription(): Promise<APIResponse<SubscriptionInfo>> {
+    return this.request<APIResponse<SubscriptionInfo>>("GET", "/billing/subscription", {}, {});
+  }
+
+  /** Get usage (GET /users/usage) */
+  getUsage(): Promise<APIResponse<UsageInfo>> {
+    return this.request<APIResponse<UsageInfo>>("GET", "/users/usage", {}, {});
+  }
+
+  /** Get all users (Admin only) (GET /users) */
+  getUsers(params: GetUsersParams = {}): Promise<APIResponse<PaginatedResponse<UserInfo[]>>> {
+    return this.request<APIResponse<PaginatedResponse<UserInfo[]>>>("GET", "/users", { page: params.page, page_size: params.page_size, sort: params.sort, search: params.search, fields: params.fields, role: params.role, is_active: params.is_active, email_status: params.email_status, "metadata.name": params["metadata.name"], created_after: params.created_after, created_before: params.created_before, cursor: params.cursor }, { "If-None-Match": params["If-None-Match"] });
+  }
+
+  /** Health check (GET /health) */
+  healthCheck(): Promise<APIResponse<HealthResponse>> {
+    return this.request<APIResponse<HealthResponse>>("GET", "/health", {}, {});
+  }
+
+  /** Import a locale bundle (POST /admin/translations/{language}/import) */
+  importTranslations(language: string, body: Record<string, unknown>, params: ImportTranslationsParams = {}): Promise<APIResponse<TranslationImport>> {
+    return this.request<APIResponse<TranslationImport>>("POST", "/admin/translations/" + encodeURIComponent(String(language)) + "/import", { replace: params.replace }, {}, body);
+  }
+
+  /** List audit log entries (GET /admin/audit-logs) */
+  listAuditLogs(params: ListAuditLogsParams = {}): Promise<APIResponse<PaginatedResponse<AuditLog[]>>> {
+    return this.request<APIResponse<PaginatedResponse<AuditLog[]>>>("GET", "/admin/audit-logs", { page: params.page, page_size: params.page_size, actor_id: params.actor_id, method: params.method, route: params.route, from: params.from, to: params.to }, {});
+  }
+
+  /** List languages (GET /languages) */
+  listLanguages(): Promise<APIResponse<LanguageInfo[]>> {
+    return this.request<APIResponse<LanguageInfo[]>>("GET", "/languages", {}, {});
+  }
+
+  /** List missing translations (GET /admin/translations/missing) */
+  listMissingTranslations(params: ListMissingTranslationsParams = {}): Promise<APIResponse<MissingTranslations[]>> {
+    return this.request<APIResponse<MissingTranslations[]>>("GET", "/admin/translations/missing", { language: params.language }, {});
+  }
+
+  /** List OAuth clients (GET /admin/oauth/clients) */
+  listOAuthClients(): Promise<APIResponse<OAuthClientInfo[]>> {
+    return this.request<APIResponse<OAuthClientInfo[]>>("GET", "/admin/oauth/clients", {}, {});
+  }
+
+  /** List authorized applications (GET /oauth/consents) */
+  listOAuthConsents(): Promise<APIResponse<OAuthConsent[]>> {
+    return this.request<APIResponse<OAuthConsent[]>>("GET", "/oauth/consents", {}, {});
+  }
+
+  /** List plans (GET /billing/plans) */
+  listPlans(): Promise<APIResponse<PlanInfo[]>> {
+    return this.request<APIResponse<PlanInfo[]>>("GET", "/billing/plans", {}, {});
+  }
+
+  /** List posts (GET /posts) */
+  listPosts(params: ListPostsParams = {}): Promise<APIResponse<PaginatedResponse<PostInfo[]>>> {
+    return this.request<APIResponse<PaginatedResponse<PostInfo[]>>>("GET", "/posts", { page: params.page, page_size: params.page_size, search: params.search, owner_id: params.owner_id }, {});
+  }
+
+  /** List sessions (GET /auth/sessions) */
+  listSessions(): Promise<APIResponse<SessionInfo[]>> {
+    return this.request<APIResponse<SessionInfo[]>>("GET", "/auth/sessions", {}, {});
+  }
+
+  /** List translation overrides (GET /admin/translations/overrides) */
+  listTranslationOverrides(params: ListTranslationOverridesParams = {}): Promise<APIResponse<Translation[]>> {
+    return this.request<APIResponse<Translation[]>>("GET", "/admin/translations/overrides", { language: params.language }, {});
+  }
+
+  /** List webhook deliveries (GET /admin/webhooks) */
+  listWebhookEvents(params: ListWebhookEventsParams = {}): Promise<APIResponse<PaginatedResponse<WebhookEvent[]>>> {
+    return this.request<APIResponse<PaginatedResponse<WebhookEvent[]>>>("GET", "/admin/webhooks", { page: params.page, page_size: params.page_size, provider: params.provider, status: params.status }, {});
+  }
+
+  /** Login user (POST /auth/login) */
+  login(body: LoginRequest, params: LoginParams = {}): Promise<APIResponse<AuthResponse>> {
+    return this.request<APIResponse<AuthResponse>>("POST", "/auth/login", {}, { "X-Bot-Challenge": params["X-Bot-Challenge"] }, body);
+  }
+
+  /** Logout user (POST /auth/logout) */
+  logout(body: LogoutRequest): Promise<APIResponse<unknown>> {
+    return this.request<APIResponse<unknown>>("POST", "/auth/logout", {}, {}, body);
+  }
+
+  /** Refresh the token (POST /auth/refresh) */
+  refreshToken(body: RefreshRequest): Promise<APIResponse<AuthResponse>> {
+    return this.request<APIResponse<AuthResponse>>("POST", "/auth/refresh", {}, {}, body);
+  }
+
+  /** Register a new user (POST /auth/register) */
+  register(body: RegisterRequest, params: RegisterParams = {}): Promise<APIResponse<AuthResponse>> {
+    return this.request<APIResponse<AuthResponse>>("POST", "/auth/register", {}, { "X-Bot-Challenge": params["X-Bot-Challenge"], "Idempotency-Key": params["Idempotency-Key"] }, body);
+  }
+
+  /** Replay a webhook delivery (POST /admin/webhooks/{id}/replay) */
+  replayWebhookEvent(iD: string): Promise<APIResponse<WebhookEvent>> {
+    return this.request<APIResponse<WebhookEvent>>("POST", "/admin/webhooks/" + encodeURIComponent(String(iD)) + "/replay", {}, {});
+  }
+
+  /** Restore the users from a backup (POST /admin/backup/restore) */
+  restoreBackup(body: RestoreBackupRequest, params: RestoreBackupParams = {}): Promise<APIResponse<RestoreBackupResponse>> {
+    return this.request<APIResponse<RestoreBackupResponse>>("POST", "/admin/backup/restore", {}, { Prefer: params.Prefer }, body);
+  }
+
+  /** Restore a deleted user (Admin only) (POST /admin/users/{id}/restore) */
+  restoreUser(iD: string): Promise<APIResponse<UserInfo>> {
+    return this.request<APIResponse<UserInfo>>("POST", "/admin/users/" + encodeURIComponent(String(iD)) + "/restore", {}, {});
+  }
+
+  /** Revoke an application's access (DELETE /oauth/consents/{client_id}) */
+  revokeOAuthConsent(clientID: string): Promise<APIResponse<unknown>> {
+    return this.request<APIResponse<unknown>>("DELETE", "/oauth/consents/" + encodeURIComponent(String(clientID)) + "", {}, {});
+  }
+
+  /** End a session (DELETE /auth/sessions/{id}) */
+  revokeSession(iD: string): Promise<APIResponse<unknown>> {
+    return this.request<APIResponse<unknown>>("DELETE", "/auth/sessions/" + encodeURIComponent(String(iD)) + "", {}, {});
+  }
+
+  /** List users for a service (GET /service/users) */
+  serviceGetUsers(params: ServiceGetUsersParams = {}): Promise<APIResponse<PaginatedResponse<UserInfo[]>>> {
+    return this.request<APIResponse<PaginatedResponse<UserInfo[]>>>("GET", "/service/users", { page: params.page, page_size: params.page_size, sort: params.sort, search: params.search, fields: params.fields, cursor: params.cursor }, {});
+  }
+
+  /** Turn the read-only mode on or off (PUT /admin/read-only) */
+  setReadOnlyMode(body: SetReadOnlyRequest): Promise<APIResponse<ReadOnlyStatus>> {
+    return this.request<APIResponse<ReadOnlyStatus>>("PUT", "/admin/read-only", {}, {}, body);
+  }
+
+  /** Override a translation (PUT /admin/translations/{language}/{key}) */
+  setTranslation(language: string, key: string, body: SetTranslationRequest): Promise<APIResponse<Translation>> {
+    return this.request<APIResponse<Translation>>("PUT", "/admin/translations/" + encodeURIComponent(String(language)) + "/" + encodeURIComponent(String(key)) + "", {}, {}, body);
+  }
+
+  /** Create the first superadmin (POST /setup) */
+  setup(body: SetupRequest): Promise<APIResponse<UserInfo>> {
+    return this.request<APIResponse<UserInfo>>("POST", "/setup", {}, {}, body);
+  }
+
+  /** Update a post (PUT /posts/{id}) */
+  updatePost(iD: string, body: UpdatePostRequest): Promise<APIResponse<PostInfo>> {
+    return this.request<APIResponse<PostInfo>>("PUT", "/posts/" + encodeURIComponent(String(iD)) + "", {}, {}, body);
+  }
+
+  /** Update user profile (PUT /users/profile) */
+  updateProfile(body: UpdateUserRequest, params: UpdateProfileParams = {}): Promise<APIResponse<UserInfo>> {
+    return this.request<APIResponse<UserInfo>>("PUT", "/users/profile", {}, { "If-Match": params["If-Match"] }, body);
+  }
+
+  /** Verify the audit log (GET /admin/audit-logs/verify) */
+  verifyAuditLogs(): Promise<APIResponse<AuditVerification>> {
+    return this.request<APIResponse<AuditVerification>>("GET", "/admin/audit-logs/verify", {}, {});
+  }
+}
@@ -0,0 +1,396 @@
+// Package sdkgen generates typed API clients from the service's OpenAPI 3 document
+package sdkgen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Kind classifies a TypeRef
+type Kind int
+
+const (
+	KindAny Kind = iota
+	KindString
+	KindInteger
+	KindNumber
+	KindBoolean
+	KindArray
+	KindMap
+	KindRef
+	KindParam
+	// KindBytes is a response body that is not JSON, such as a CSV download, returned as it is
+	KindBytes
+)
+
+// TypeRef is a reference to a primitive, collection, or named type. Elem is the element of arrays and maps;
+// Arg is the type argument of a generic named type.
+type TypeRef struct {
+	Kind Kind
+	Name string
+	Elem *TypeRef
+	Arg  *TypeRef
+}
+
+// Field is a property of a named type
+type Field struct {
+	JSONName    string
+	Type        TypeRef
+	Required    bool
+	Description string
+}
+
+// Type is a named object schema. Generic types carry the envelope's payload as a type parameter in place
+// of an untyped "data" property.
+type Type struct {
+	Name        string
+	Description string
+	Generic     bool
+	Fields      []Field
+}
+
+// Param is a path, query, or header parameter
+type Param struct {
+	Name        string
+	In          string
+	Type        TypeRef
+	Required    bool
+	Description string
+}
+
+// Operation is a single endpoint
+type Operation struct {
+	ID          string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Params      []Param
+	Body        *TypeRef
+	Result      TypeRef
+	Secured     bool
+}
+
+// API is the language-neutral model both generators render
+type API struct {
+	Title      string
+	Version    string
+	BasePath   string
+	Types      []Type
+	Operations []Operation
+}
+
+// Load builds the API model from an OpenAPI 3 document; output is sorted for stable generation
+func Load(doc map[string]interface{}) (*API, error) {
+	api := &API{}
+	if info, ok := doc["info"].(map[string]interface{}); ok {
+		api.Title, _ = info["title"].(string)
+		api.Version, _ = info["version"].(string)
+	}
+	if servers, ok := doc["servers"].([]interface{}); ok && len(servers) > 0 {
+		if server, ok := servers[0].(map[string]interface{}); ok {
+			url, _ := server["url"].(string)
+			api.BasePath = basePath(url)
+		}
+	}
+
+	schemas := map[string]interface{}{}
+	if components, ok := doc["components"].(map[string]interface{}); ok {
+		if list, ok := components["schemas"].(map[string]interface{}); ok {
+			schemas = list
+		}
+	}
+
+	generic := map[string]bool{}
+	for name, value := range schemas {
+		schema, _ := value.(map[string]interface{})
+		if isGeneric(schema) {
+			generic[typeName(name)] = true
+		}
+	}
+	resolver := &resolver{generic: generic}
+
+	for name, value := range schemas {
+		schema, _ := value.(map[string]interface{})
+		api.Types = append(api.Types, resolver.namedType(typeName(name), schema))
+	}
+	sort.Slice(api.Types, func(i, j int) bool { return api.Types[i].Name < api.Types[j].Name })
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for path, item := range paths {
+		operations, _ := item.(map[string]interface{})
+		for method, value := range operations {
+			operation, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			op, err := resolver.operation(strings.ToUpper(method), path, operation)
+			if err != nil {
+				return nil, err
+			}
+			api.Operations = append(api.Operations, op)
+		}
+	}
+	sort.Slice(api.Operations, func(i, j int) bool { return api.Operations[i].ID < api.Operations[j].ID })
+
+	seen := map[string]string{}
+	for _, op := range api.Operations {
+		if other, ok := seen[op.ID]; ok {
+			return nil, fmt.Errorf("operations %s and %s %s share the ID %q", other, op.Method, op.Path, op.ID)
+		}
+		seen[op.ID] = op.Method + " " + op.Path
+	}
+
+	return api, nil
+}
+
+// resolver converts schemas into TypeRefs, knowing which named types are generic
+type resolver struct {
+	generic map[string]bool
+}
+
+// namedType converts a component schema into a Type
+func (r *resolver) namedType(name string, schema map[string]interface{}) Type {
+	t := Type{Name: name, Generic: r.generic[name]}
+	t.Description = description(schema)
+
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, item := range list {
+			if field, ok := item.(string); ok {
+				required[field] = true
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, jsonName := range sortedKeys(properties) {
+		property, _ := properties[jsonName].(map[string]interface{})
+		field := Field{JSONName: jsonName, Required: required[jsonName]}
+		field.Description = description(property)
+		if t.Generic && jsonName == "data" {
+			field.Type = TypeRef{Kind: KindParam}
+		} else {
+			field.Type = r.ref(property)
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	return t
+}
+
+// ref converts an inline schema into a TypeRef
+func (r *resolver) ref(schema map[string]interface{}) TypeRef {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := typeName(ref)
+		if r.generic[name] {
+			return TypeRef{Kind: KindRef, Name: name, Arg: &TypeRef{Kind: KindAny}}
+		}
+		return TypeRef{Kind: KindRef, Name: name}
+	}
+
+	// swag renders overridden envelope fields as allOf: [base, {properties: {data: ...}}]
+	if allOf, ok := schema["allOf"].([]interface{}); ok && len(allOf) > 0 {
+		base, _ := allOf[0].(map[string]interface{})
+		ref := r.ref(base)
+		if ref.Kind == KindRef && ref.Arg != nil && len(allOf) > 1 {
+			override, _ := allOf[1].(map[string]interface{})
+			properties, _ := override["properties"].(map[string]interface{})
+			if data, ok := properties["data"].(map[string]interface{}); ok {
+				arg := r.ref(data)
+				ref.Arg = &arg
+			}
+		}
+		return ref
+	}
+
+	switch schema["type"] {
+	case "string":
+		return TypeRef{Kind: KindString}
+	case "integer":
+		return TypeRef{Kind: KindInteger}
+	case "number":
+		return TypeRef{Kind: KindNumber}
+	case "boolean":
+		return TypeRef{Kind: KindBoolean}
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		elem := r.ref(items)
+		return TypeRef{Kind: KindArray, Elem: &elem}
+	case "object":
+		elem := TypeRef{Kind: KindAny}
+		if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			elem = r.ref(additional)
+		}
+		return TypeRef{Kind: KindMap, Elem: &elem}
+	default:
+		return TypeRef{Kind: KindAny}
+	}
+}
+
+// operation converts a path item operation
+func (r *resolver) operation(method, path string, operation map[string]interface{}) (Operation, error) {
+	op := Operation{Method: method, Path: path, Result: TypeRef{Kind: KindAny}}
+	op.ID, _ = operation["operationId"].(string)
+	if op.ID == "" {
+		op.ID = defaultOperationID(method, path)
+	}
+	op.Summary, _ = operation["summary"].(string)
+	op.Description = description(operation)
+	_, op.Secured = operation["security"]
+
+	parameters, _ := operation["parameters"].([]interface{})
+	for _, value := range parameters {
+		parameter, _ := value.(map[string]interface{})
+		param := Param{}
+		param.Name, _ = parameter["name"].(string)
+		param.In, _ = parameter["in"].(string)
+		param.Required, _ = parameter["required"].(bool)
+		param.Description = description(parameter)
+		schema, _ := parameter["schema"].(map[string]interface{})
+		param.Type = r.ref(schema)
+		if param.In == "path" {
+			param.Required = true
+		}
+		op.Params = append(op.Params, param)
+	}
+
+	if body, ok := operation["requestBody"].(map[string]interface{}); ok {
+		content, _ := body["content"].(map[string]interface{})
+		media, ok := content["application/json"].(map[string]interface{})
+		if !ok {
+			return op, fmt.Errorf("%s %s: only application/json request bodies are supported", method, path)
+		}
+		schema, _ := media["schema"].(map[string]interface{})
+		ref := r.ref(schema)
+		op.Body = &ref
+	}
+
+	responses, _ := operation["responses"].(map[string]interface{})
+	for _, status := range sortedKeys(responses) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		response, _ := responses[status].(map[string]interface{})
+		content, _ := response["content"].(map[string]interface{})
+		if media, ok := content["application/json"].(map[string]interface{}); ok {
+			schema, _ := media["schema"].(map[string]interface{})
+			op.Result = r.ref(schema)
+			break
+		}
+		if len(content) > 0 {
+			op.Result = TypeRef{Kind: KindBytes}
+			break
+		}
+	}
+
+	return op, nil
+}
+
+// description returns the description of a schema, property, operation, or parameter on one line; swag
+// keeps the line breaks of wrapped doc comments, which would end the comments of the generated code
+func description(object map[string]interface{}) string {
+	text, _ := object["description"].(string)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// isGeneric reports whether a schema is an envelope whose "data" property is untyped
+func isGeneric(schema map[string]interface{}) bool {
+	properties, _ := schema["properties"].(map[string]interface{})
+	data, ok := properties["data"].(map[string]interface{})
+	return ok && len(data) == 0
+}
+
+// typeName strips the $ref prefix and Go package qualifier: "#/components/schemas/models.UserInfo" -> "UserInfo"
+func typeName(ref string) string {
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// basePath returns the path component of a server URL
+func basePath(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+		if j := strings.Index(url, "/"); j >= 0 {
+			return strings.TrimSuffix(url[j:], "/")
+		}
+		return ""
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+// defaultOperationID derives an ID such as "getUsersProfile" for operations without one
+func defaultOperationID(method, path string) string {
+	words := []string{strings.ToLower(method)}
+	for _, segment := range strings.Split(path, "/") {
+		segment = strings.Trim(segment, "{}")
+		if segment != "" {
+			words = append(words, segment)
+		}
+	}
+	return camel(words, false)
+}
+
+// camel joins words into camelCase (or PascalCase when exported), splitting on non-alphanumerics
+func camel(words []string, exported bool) string {
+	var b strings.Builder
+	first := true
+	for _, word := range words {
+		for _, part := range strings.FieldsFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			runes := []rune(part)
+			if first && !exported {
+				runes[0] = unicode.ToLower(runes[0])
+			} else {
+				runes[0] = unicode.ToUpper(runes[0])
+			}
+			b.WriteString(string(runes))
+			first = false
+		}
+	}
+	return b.String()
+}
+
+// goName converts a JSON, parameter, or operation name to an exported Go identifier, honoring common initialisms
+func goName(name string) string {
+	initialisms := map[string]string{"id": "ID", "url": "URL", "api": "API", "http": "HTTP", "json": "JSON", "ip": "IP"}
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		words = append(words, splitCamel(part)...)
+	}
+	var b strings.Builder
+	for _, word := range words {
+		if initialism, ok := initialisms[strings.ToLower(word)]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// splitCamel splits "getUsersByID" into ["get", "Users", "By", "ID"]
+func splitCamel(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,310 @@
+package sdkgen
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// goRuntime is the hand-written part of the Go client shared by every generated method
+const goRuntime = `
+// Client calls the API
+type Client struct {
+	// BaseURL is the server origin, for example "http://localhost:8080"
+	BaseURL string
+	// HTTPClient sends the requests; http.DefaultClient is used when nil
+	HTTPClient *http.Client
+	// Token is sent as a Bearer token when set
+	Token string
+	// Language is sent as Accept-Language when set
+	Language string
+}
+
+// New creates a client for the server at baseURL
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Error is returned for responses outside the 2xx range
+type Error struct {
+	StatusCode int
+	Message    string
+	Body       []byte
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("api: %d %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("api: unexpected status %d", e.StatusCode)
+}
+
+// Ptr returns a pointer to v, for optional parameters
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	endpoint := c.BaseURL + basePath + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.Language != "" {
+		req.Header.Set("Accept-Language", c.Language)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: resp.StatusCode, Body: data}
+		var envelope struct {
+			Message string ` + "`json:\"message\"`" + `
+			Title   string ` + "`json:\"title\"`" + `
+		}
+		if json.Unmarshal(data, &envelope) == nil {
+			apiErr.Message = envelope.Message
+			if apiErr.Message == "" {
+				apiErr.Message = envelope.Title
+			}
+		}
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if raw, ok := out.(*[]byte); ok {
+		*raw = data
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+func addQuery[T any](query url.Values, name string, value *T) {
+	if value != nil {
+		query.Set(name, fmt.Sprint(*value))
+	}
+}
+
+func addHeader[T any](header http.Header, name string, value *T) {
+	if value != nil {
+		header.Set(name, fmt.Sprint(*value))
+	}
+}
+`
+
+// GenerateGo renders a typed Go client package
+func GenerateGo(api *API, pkg string) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("// Code generated by sdkgen from the OpenAPI document. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Package %s is a typed client for %s (version %s).\n", pkg, api.Title, api.Version)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n\t\"bytes\"\n\t\"context\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n\t\"net/url\"\n\t\"strings\"\n)\n\n")
+	fmt.Fprintf(&b, "// basePath is prefixed to every operation path\nconst basePath = %s\n", strconv.Quote(api.BasePath))
+	b.WriteString(goRuntime)
+
+	for _, t := range api.Types {
+		b.WriteString("\n")
+		description := t.Description
+		if description == "" {
+			description = "is the " + t.Name + " schema"
+		}
+		fmt.Fprintf(&b, "// %s %s\n", t.Name, description)
+		if t.Generic {
+			fmt.Fprintf(&b, "type %s[T any] struct {\n", t.Name)
+		} else {
+			fmt.Fprintf(&b, "type %s struct {\n", t.Name)
+		}
+		for _, field := range t.Fields {
+			if field.Description != "" {
+				fmt.Fprintf(&b, "\t// %s\n", field.Description)
+			}
+			tag := field.JSONName
+			if !field.Required {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:%s`\n", goName(field.JSONName), goType(field.Type), strconv.Quote(tag))
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, op := range api.Operations {
+		writeGoOperation(&b, op)
+	}
+
+	source, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated Go client does not compile: %w", err)
+	}
+	return source, nil
+}
+
+// writeGoOperation renders the params struct (if any) and method for one operation
+func writeGoOperation(b *strings.Builder, op Operation) {
+	name := goName(op.ID)
+	var pathParams, otherParams []Param
+	for _, param := range op.Params {
+		if param.In == "path" {
+			pathParams = append(pathParams, param)
+		} else {
+			otherParams = append(otherParams, param)
+		}
+	}
+
+	paramsType := name + "Params"
+	if len(otherParams) > 0 {
+		fmt.Fprintf(b, "\n// %s holds the query and header parameters of %s\n", paramsType, name)
+		fmt.Fprintf(b, "type %s struct {\n", paramsType)
+		for _, param := range otherParams {
+			if param.Description != "" {
+				fmt.Fprintf(b, "\t// %s\n", param.Description)
+			}
+			typ := goType(param.Type)
+			if !param.Required {
+				typ = "*" + typ
+			}
+			fmt.Fprintf(b, "\t%s %s\n", goName(param.Name), typ)
+		}
+		b.WriteString("}\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, param := range pathParams {
+		args = append(args, goArg(param.Name)+" "+goType(param.Type))
+	}
+	if op.Body != nil {
+		args = append(args, "body "+goType(*op.Body))
+	}
+	if len(otherParams) > 0 {
+		args = append(args, "params *"+paramsType)
+	}
+
+	result := goResultType(op.Result)
+	fmt.Fprintf(b, "\n// %s calls %s %s\n", name, op.Method, op.Path)
+	if op.Summary != "" {
+		fmt.Fprintf(b, "//\n// %s\n", op.Summary)
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(%s) (*%s, error) {\n", name, strings.Join(args, ", "), result)
+
+	fmt.Fprintf(b, "\tpath := %s\n", strconv.Quote(op.Path))
+	for _, param := range pathParams {
+		fmt.Fprintf(b, "\tpath = strings.ReplaceAll(path, %s, url.PathEscape(fmt.Sprint(%s)))\n", strconv.Quote("{"+param.Name+"}"), goArg(param.Name))
+	}
+	b.WriteString("\tquery := url.Values{}\n\theader := http.Header{}\n")
+	if len(otherParams) > 0 {
+		b.WriteString("\tif params != nil {\n")
+		for _, param := range otherParams {
+			helper := "addQuery(query"
+			if param.In == "header" {
+				helper = "addHeader(header"
+			}
+			value := "params." + goName(param.Name)
+			if param.Required {
+				value = "&" + value
+			}
+			fmt.Fprintf(b, "\t\t%s, %s, %s)\n", helper, strconv.Quote(param.Name), value)
+		}
+		b.WriteString("\t}\n")
+	}
+
+	body := "nil"
+	if op.Body != nil {
+		body = "body"
+	}
+	fmt.Fprintf(b, "\tvar out %s\n", result)
+	fmt.Fprintf(b, "\tif err := c.do(ctx, %s, path, query, header, %s, &out); err != nil {\n\t\treturn nil, err\n\t}\n", strconv.Quote(op.Method), body)
+	b.WriteString("\treturn &out, nil\n}\n")
+}
+
+// goType renders a TypeRef as Go source
+func goType(ref TypeRef) string {
+	switch ref.Kind {
+	case KindString:
+		return "string"
+	case KindInteger:
+		return "int"
+	case KindNumber:
+		return "float64"
+	case KindBoolean:
+		return "bool"
+	case KindArray:
+		return "[]" + goType(*ref.Elem)
+	case KindMap:
+		return "map[string]" + goType(*ref.Elem)
+	case KindParam:
+		return "T"
+	case KindBytes:
+		return "[]byte"
+	case KindRef:
+		if ref.Arg != nil {
+			return ref.Name + "[" + goResultType(*ref.Arg) + "]"
+		}
+		return ref.Name
+	default:
+		return "interface{}"
+	}
+}
+
+// goResultType renders a response or type argument; untyped payloads stay raw so callers can decode them
+func goResultType(ref TypeRef) string {
+	if ref.Kind == KindAny {
+		return "json.RawMessage"
+	}
+	return goType(ref)
+}
+
+// goArg converts a parameter name into an unexported Go identifier
+func goArg(name string) string {
+	ident := goName(name)
+	if strings.ToUpper(ident) == ident {
+		return strings.ToLower(ident)
+	}
+	return lowerFirst(ident)
+}
+
+// lowerFirst lower-cases the first letter of s
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
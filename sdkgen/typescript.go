@@ -0,0 +1,272 @@
+package sdkgen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tsIdentifier matches property names that need no quoting
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsRuntime is the hand-written part of the TypeScript client shared by every generated method
+const tsRuntime = `
+export class ApiError extends Error {
+  constructor(
+    public readonly status: number,
+    public readonly body: unknown,
+  ) {
+    const payload = body as { message?: string; title?: string } | undefined;
+    super(payload?.message || payload?.title || ` + "`unexpected status ${status}`" + `);
+    this.name = "ApiError";
+  }
+}
+
+export interface ClientOptions {
+  /** Sent as a Bearer token when set */
+  token?: string;
+  /** Sent as Accept-Language when set */
+  language?: string;
+  /** Fetch implementation; defaults to the global fetch */
+  fetch?: typeof fetch;
+}
+
+type Query = Record<string, unknown>;
+
+export class ApiClient {
+  private readonly baseUrl: string;
+  private readonly fetchImpl: typeof fetch;
+  token?: string;
+  language?: string;
+
+  constructor(baseUrl: string, options: ClientOptions = {}) {
+    this.baseUrl = baseUrl.replace(/\/$/, "");
+    this.fetchImpl = options.fetch ?? fetch.bind(globalThis);
+    this.token = options.token;
+    this.language = options.language;
+  }
+
+  private async request<T>(method: string, path: string, query: Query = {}, headers: Query = {}, body?: unknown, raw = false): Promise<T> {
+    const url = new URL(this.baseUrl + BASE_PATH + path);
+    for (const [name, value] of Object.entries(query)) {
+      if (value !== undefined && value !== null) {
+        url.searchParams.set(name, String(value));
+      }
+    }
+
+    const requestHeaders: Record<string, string> = { Accept: "application/json" };
+    for (const [name, value] of Object.entries(headers)) {
+      if (value !== undefined && value !== null) {
+        requestHeaders[name] = String(value);
+      }
+    }
+    if (body !== undefined) {
+      requestHeaders["Content-Type"] = "application/json";
+    }
+    if (this.token) {
+      requestHeaders["Authorization"] = ` + "`Bearer ${this.token}`" + `;
+    }
+    if (this.language) {
+      requestHeaders["Accept-Language"] = this.language;
+    }
+
+    const response = await this.fetchImpl(url.toString(), {
+      method,
+      headers: requestHeaders,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+
+    const text = await response.text();
+    if (raw && response.ok) {
+      return text as T;
+    }
+    let data: unknown = undefined;
+    if (text) {
+      try {
+        data = JSON.parse(text);
+      } catch {
+        data = text;
+      }
+    }
+    if (!response.ok) {
+      throw new ApiError(response.status, data);
+    }
+    return data as T;
+  }
+`
+
+// GenerateTypeScript renders a typed TypeScript client module using fetch
+func GenerateTypeScript(api *API) []byte {
+	var b strings.Builder
+	b.WriteString("// Code generated by sdkgen from the OpenAPI document. DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "// Typed client for %s (version %s).\n\n", api.Title, api.Version)
+	fmt.Fprintf(&b, "const BASE_PATH = %s;\n", strconv.Quote(api.BasePath))
+
+	for _, t := range api.Types {
+		b.WriteString("\n")
+		if t.Description != "" {
+			fmt.Fprintf(&b, "/** %s */\n", t.Description)
+		}
+		if t.Generic {
+			fmt.Fprintf(&b, "export interface %s<T = unknown> {\n", t.Name)
+		} else {
+			fmt.Fprintf(&b, "export interface %s {\n", t.Name)
+		}
+		for _, field := range t.Fields {
+			if field.Description != "" {
+				fmt.Fprintf(&b, "  /** %s */\n", field.Description)
+			}
+			optional := "?"
+			if field.Required {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsProperty(field.JSONName), optional, tsType(field.Type))
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, op := range api.Operations {
+		if params := queryAndHeaderParams(op); len(params) > 0 {
+			fmt.Fprintf(&b, "\nexport interface %sParams {\n", goName(op.ID))
+			for _, param := range params {
+				if param.Description != "" {
+					fmt.Fprintf(&b, "  /** %s */\n", param.Description)
+				}
+				optional := "?"
+				if param.Required {
+					optional = ""
+				}
+				fmt.Fprintf(&b, "  %s%s: %s;\n", tsProperty(param.Name), optional, tsType(param.Type))
+			}
+			b.WriteString("}\n")
+		}
+	}
+
+	b.WriteString(tsRuntime)
+	for _, op := range api.Operations {
+		writeTSOperation(&b, op)
+	}
+	b.WriteString("}\n")
+
+	return []byte(b.String())
+}
+
+// writeTSOperation renders the ApiClient method for one operation
+func writeTSOperation(b *strings.Builder, op Operation) {
+	params := queryAndHeaderParams(op)
+	var args []string
+	path := strconv.Quote(op.Path)
+	for _, param := range op.Params {
+		if param.In == "path" {
+			arg := lowerFirst(goName(param.Name))
+			args = append(args, arg+": "+tsType(param.Type))
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", `" + encodeURIComponent(String(`+arg+`)) + "`)
+		}
+	}
+	if op.Body != nil {
+		args = append(args, "body: "+tsType(*op.Body))
+	}
+	if len(params) > 0 {
+		paramsArg := "params: " + goName(op.ID) + "Params"
+		required := false
+		for _, param := range params {
+			required = required || param.Required
+		}
+		if !required {
+			paramsArg += " = {}"
+		}
+		args = append(args, paramsArg)
+	}
+
+	var query, headers []string
+	for _, param := range params {
+		entry := fmt.Sprintf("%s: params%s", tsProperty(param.Name), tsAccess(param.Name))
+		if param.In == "header" {
+			headers = append(headers, entry)
+		} else {
+			query = append(query, entry)
+		}
+	}
+
+	summary := op.Summary
+	if summary == "" {
+		summary = op.Method + " " + op.Path
+	}
+	fmt.Fprintf(b, "\n  /** %s (%s %s) */\n", summary, op.Method, op.Path)
+	fmt.Fprintf(b, "  %s(%s): Promise<%s> {\n", lowerFirst(goName(op.ID)), strings.Join(args, ", "), tsType(op.Result))
+
+	callArgs := []string{strconv.Quote(op.Method), path, tsObject(query), tsObject(headers)}
+	if op.Body != nil {
+		callArgs = append(callArgs, "body")
+	}
+	if op.Result.Kind == KindBytes {
+		if op.Body == nil {
+			callArgs = append(callArgs, "undefined")
+		}
+		callArgs = append(callArgs, "true")
+	}
+	fmt.Fprintf(b, "    return this.request<%s>(%s);\n  }\n", tsType(op.Result), strings.Join(callArgs, ", "))
+}
+
+// queryAndHeaderParams returns an operation's non-path parameters
+func queryAndHeaderParams(op Operation) []Param {
+	var params []Param
+	for _, param := range op.Params {
+		if param.In == "query" || param.In == "header" {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// tsType renders a TypeRef as TypeScript
+func tsType(ref TypeRef) string {
+	switch ref.Kind {
+	case KindString:
+		return "string"
+	case KindInteger, KindNumber:
+		return "number"
+	case KindBoolean:
+		return "boolean"
+	case KindArray:
+		return tsType(*ref.Elem) + "[]"
+	case KindMap:
+		return "Record<string, " + tsType(*ref.Elem) + ">"
+	case KindParam:
+		return "T"
+	case KindBytes:
+		return "string"
+	case KindRef:
+		if ref.Arg != nil {
+			return ref.Name + "<" + tsType(*ref.Arg) + ">"
+		}
+		return ref.Name
+	default:
+		return "unknown"
+	}
+}
+
+// tsProperty quotes property names that are not valid identifiers
+func tsProperty(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsAccess renders a property access for name
+func tsAccess(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return "." + name
+	}
+	return "[" + strconv.Quote(name) + "]"
+}
+
+// tsObject renders an object literal from entries
+func tsObject(entries []string) string {
+	if len(entries) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(entries, ", ") + " }"
+}
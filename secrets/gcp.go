@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go-backend-template/httpclient"
+)
+
+const gcpMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// GCPProvider reads secrets from Google Secret Manager, authenticating via GCP_ACCESS_TOKEN or the metadata server
+type GCPProvider struct {
+	project string
+	client  *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGCPProvider creates a GCP provider for the given project
+func NewGCPProvider(project string) (*GCPProvider, error) {
+	if project == "" {
+		return nil, fmt.Errorf("GCP secrets provider requires SECRETS_GCP_PROJECT")
+	}
+	return &GCPProvider{
+		project: project,
+		client:  httpclient.New("gcp_secret_manager", 10*time.Second),
+	}, nil
+}
+
+// Fetch returns the latest version of the named secret; a full "projects/.../versions/N" name is used as-is
+func (p *GCPProvider) Fetch(ctx context.Context, name string) (string, error) {
+	resource := name
+	if !strings.HasPrefix(name, "projects/") {
+		resource = fmt.Sprintf("projects/%s/secrets/%s/versions/latest", p.project, name)
+	}
+
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://secretmanager.googleapis.com/v1/"+resource+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach GCP Secret Manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GCP Secret Manager returned status %d for %s", resp.StatusCode, name)
+	}
+
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode GCP response: %w", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode GCP secret payload: %w", err)
+	}
+	return string(data), nil
+}
+
+// accessToken returns a static token from the environment or a cached metadata-server token
+func (p *GCPProvider) accessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GCP_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach GCP metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GCP metadata server returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode metadata token: %w", err)
+	}
+
+	p.token = body.AccessToken
+	// Refresh a minute early to avoid using a token that expires mid-request
+	p.tokenExpiry = time.Now().Add(time.Duration(body.ExpiresIn)*time.Second - time.Minute)
+	return p.token, nil
+}
@@ -0,0 +1,194 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-backend-template/config"
+	"go-backend-template/utils"
+)
+
+// Provider fetches raw secret values from an external secrets manager
+type Provider interface {
+	Fetch(ctx context.Context, name string) (string, error)
+}
+
+// NewProviderFromConfig builds the provider selected in configuration; nil means secrets come from env only
+func NewProviderFromConfig(cfg *config.SecretsConfig) (Provider, error) {
+	switch cfg.Provider {
+	case "", "none":
+		return nil, nil
+	case "vault":
+		return NewVaultProvider(cfg.VaultAddress, cfg.VaultToken, cfg.VaultNamespace), nil
+	case "aws":
+		return NewAWSProvider(cfg.AWSRegion)
+	case "gcp":
+		return NewGCPProvider(cfg.GCPProject)
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q", cfg.Provider)
+	}
+}
+
+// Manager caches secrets from a provider and refreshes them periodically
+type Manager struct {
+	provider Provider
+	ttl      time.Duration
+	logger   utils.Logger
+
+	mu       sync.RWMutex
+	cache    map[string]cachedSecret
+	watchers map[string][]func(string)
+}
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// NewManager creates a caching secrets manager; ttl controls both cache expiry and refresh interval
+func NewManager(provider Provider, ttl time.Duration, logger utils.Logger) *Manager {
+	return &Manager{
+		provider: provider,
+		ttl:      ttl,
+		logger:   logger,
+		cache:    make(map[string]cachedSecret),
+		watchers: make(map[string][]func(string)),
+	}
+}
+
+// Get returns the secret for ref, using the cache while it is fresh.
+// A ref has the form "name" or "name#key", where key selects a field of a JSON secret.
+func (m *Manager) Get(ctx context.Context, ref string) (string, error) {
+	m.mu.RLock()
+	cached, ok := m.cache[ref]
+	m.mu.RUnlock()
+
+	if ok && (m.ttl <= 0 || time.Since(cached.fetchedAt) < m.ttl) {
+		return cached.value, nil
+	}
+
+	value, err := m.fetch(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	m.cache[ref] = cachedSecret{value: value, fetchedAt: time.Now()}
+	m.mu.Unlock()
+
+	return value, nil
+}
+
+// Watch registers a callback invoked when a refresh observes a new value for ref
+func (m *Manager) Watch(ref string, fn func(string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watchers[ref] = append(m.watchers[ref], fn)
+}
+
+// Start refreshes all cached secrets every ttl until ctx is cancelled
+func (m *Manager) Start(ctx context.Context) {
+	if m.ttl <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(m.ttl)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.refresh(ctx)
+			}
+		}
+	}()
+}
+
+// refresh re-fetches every cached secret and notifies watchers of changes
+func (m *Manager) refresh(ctx context.Context) {
+	m.mu.RLock()
+	refs := make([]string, 0, len(m.cache))
+	for ref := range m.cache {
+		refs = append(refs, ref)
+	}
+	m.mu.RUnlock()
+
+	for _, ref := range refs {
+		value, err := m.fetch(ctx, ref)
+		if err != nil {
+			// Keep serving the cached value; a transient outage must not drop credentials
+			m.logger.Warn("Failed to refresh secret", "ref", ref, "error", err)
+			continue
+		}
+
+		m.mu.Lock()
+		changed := m.cache[ref].value != value
+		m.cache[ref] = cachedSecret{value: value, fetchedAt: time.Now()}
+		watchers := append([]func(string){}, m.watchers[ref]...)
+		m.mu.Unlock()
+
+		if changed {
+			m.logger.Info("Secret rotated", "ref", ref)
+			for _, fn := range watchers {
+				fn(value)
+			}
+		}
+	}
+}
+
+// fetch loads ref from the provider and extracts the JSON key if one is given
+func (m *Manager) fetch(ctx context.Context, ref string) (string, error) {
+	name, key, _ := strings.Cut(ref, "#")
+
+	raw, err := m.provider.Fetch(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return raw, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object: %w", name, err)
+	}
+
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no key %q", name, key)
+	}
+	return fmt.Sprint(value), nil
+}
+
+// Resolve overrides credentials in cfg with values from the secrets manager for every configured ref
+func (m *Manager) Resolve(ctx context.Context, cfg *config.Config) error {
+	targets := []struct {
+		ref   string
+		value *string
+	}{
+		{cfg.Secrets.JWTSecretRef, &cfg.JWTSecret},
+		{cfg.Secrets.MongoPasswordRef, &cfg.MongoDB.Password},
+		{cfg.Secrets.MongoURIRef, &cfg.MongoDB.URI},
+		{cfg.Secrets.PostgresPasswordRef, &cfg.PostgresDB.Password},
+	}
+
+	for _, target := range targets {
+		if target.ref == "" {
+			continue
+		}
+		value, err := m.Get(ctx, target.ref)
+		if err != nil {
+			return fmt.Errorf("failed to resolve secret %s: %w", target.ref, err)
+		}
+		*target.value = value
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-backend-template/httpclient"
+)
+
+// VaultProvider reads secrets from HashiCorp Vault's KV engine (v1 or v2) over HTTP
+type VaultProvider struct {
+	address   string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// NewVaultProvider creates a Vault provider authenticated with a token
+func NewVaultProvider(address, token, namespace string) *VaultProvider {
+	return &VaultProvider{
+		address:   strings.TrimRight(address, "/"),
+		token:     token,
+		namespace: namespace,
+		client:    httpclient.New("vault", 10*time.Second),
+	}
+}
+
+// Fetch reads the secret at path (e.g. "secret/data/app") and returns its data as a JSON object
+func (p *VaultProvider) Fetch(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.address+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d for %s", resp.StatusCode, path)
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode Vault response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data; KV v1 returns it directly under data
+	if nested, ok := body.Data["data"]; ok {
+		return string(nested), nil
+	}
+
+	data, err := json.Marshal(body.Data)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
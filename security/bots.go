@@ -0,0 +1,252 @@
+package security
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go-backend-template/config"
+)
+
+// Bot signals, the reasons a request is taken for a bot
+const (
+	// SignalHoneypot is a filled-in honeypot field, which is hidden from people
+	SignalHoneypot = "honeypot"
+	// SignalNoUserAgent is a request without a User-Agent header
+	SignalNoUserAgent = "no_user_agent"
+	// SignalBotUserAgent is a User-Agent of a scripting library or headless browser
+	SignalBotUserAgent = "bot_user_agent"
+	// SignalNoAccept is a request without an Accept header, which browsers and HTTP libraries send
+	SignalNoAccept = "no_accept"
+	// SignalNoChallenge is a request without a challenge token on a route that requires one
+	SignalNoChallenge = "no_challenge"
+	// SignalBadChallenge is a challenge token that is forged, expired, or already used
+	SignalBadChallenge = "bad_challenge"
+	// SignalFastChallenge is a challenge token used sooner after it was issued than a person could
+	SignalFastChallenge = "fast_challenge"
+)
+
+// challengeNonceSize is the number of random bytes that make each challenge token unique
+const challengeNonceSize = 16
+
+// BotDetector spots automated requests to the auth routes with cheap signals: a honeypot form field,
+// request headers, and an optional challenge token the frontend fetches with JavaScript before submitting.
+// Spent challenge tokens are remembered in memory, so each instance of the API only refuses the tokens
+// used against it a second time.
+type BotDetector struct {
+	cfg        config.BotDetectionConfig
+	routes     map[string]bool
+	userAgents []string
+
+	mu        sync.Mutex
+	spent     map[string]time.Time
+	lastSweep time.Time
+	rejected  map[string]int64
+	issued    int64
+}
+
+// BotStats are the counters of a BotDetector since the process started
+type BotStats struct {
+	// Rejected counts the rejected requests by their first signal
+	Rejected map[string]int64
+	// Challenges counts the challenge tokens issued
+	Challenges int64
+}
+
+// NewBotDetector creates a detector for cfg
+func NewBotDetector(cfg config.BotDetectionConfig) *BotDetector {
+	d := &BotDetector{
+		cfg:      cfg,
+		routes:   make(map[string]bool),
+		spent:    make(map[string]time.Time),
+		rejected: make(map[string]int64),
+	}
+	for _, route := range cfg.Routes {
+		d.routes[route] = false
+	}
+	for _, route := range cfg.ChallengeRoutes {
+		d.routes[route] = true
+	}
+	for _, agent := range cfg.BlockedUserAgents {
+		if agent = strings.ToLower(strings.TrimSpace(agent)); agent != "" {
+			d.userAgents = append(d.userAgents, agent)
+		}
+	}
+	return d
+}
+
+// Guards reports whether route, given without the API version, is checked for bots
+func (d *BotDetector) Guards(route string) bool {
+	_, ok := d.routes[route]
+	return ok
+}
+
+// ChallengeEnabled reports whether any route requires a challenge token
+func (d *BotDetector) ChallengeEnabled() bool {
+	return len(d.cfg.ChallengeRoutes) > 0
+}
+
+// HoneypotField returns the name of the honeypot field, or "" when there is none
+func (d *BotDetector) HoneypotField() string {
+	return d.cfg.HoneypotField
+}
+
+// Inspect returns the bot signals of a request to route with body, whose challenge token, if any, is
+// spent; no signals means the request looks like it comes from a person
+func (d *BotDetector) Inspect(route string, r *http.Request, body []byte, challenge string) []string {
+	var signals []string
+	if d.cfg.HoneypotField != "" && honeypotFilled(r.Header.Get("Content-Type"), body, d.cfg.HoneypotField) {
+		signals = append(signals, SignalHoneypot)
+	}
+	if d.cfg.HeaderChecks {
+		agent := strings.ToLower(r.UserAgent())
+		switch {
+		case agent == "":
+			signals = append(signals, SignalNoUserAgent)
+		case d.blockedUserAgent(agent):
+			signals = append(signals, SignalBotUserAgent)
+		}
+		if r.Header.Get("Accept") == "" {
+			signals = append(signals, SignalNoAccept)
+		}
+	}
+	if d.routes[route] {
+		if signal := d.redeem(challenge); signal != "" {
+			signals = append(signals, signal)
+		}
+	}
+
+	if len(signals) > 0 {
+		d.mu.Lock()
+		d.rejected[signals[0]]++
+		d.mu.Unlock()
+	}
+	return signals
+}
+
+// blockedUserAgent reports whether the lowercase agent contains one of the blocked user agents
+func (d *BotDetector) blockedUserAgent(agent string) bool {
+	for _, blocked := range d.userAgents {
+		if strings.Contains(agent, blocked) {
+			return true
+		}
+	}
+	return false
+}
+
+// Challenge issues a challenge token, valid for one request from ChallengeMinAge after now until it
+// expires
+func (d *BotDetector) Challenge() (string, time.Time, error) {
+	now := time.Now()
+	payload := make([]byte, 8+challengeNonceSize)
+	binary.BigEndian.PutUint64(payload, uint64(now.UnixMilli()))
+	if _, err := rand.Read(payload[8:]); err != nil {
+		return "", time.Time{}, err
+	}
+
+	d.mu.Lock()
+	d.issued++
+	d.mu.Unlock()
+
+	token := base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(d.sign(payload))
+	return token, now.Add(d.cfg.ChallengeTTL), nil
+}
+
+// redeem checks a challenge token and spends it, returning the signal it raises or ""
+func (d *BotDetector) redeem(token string) string {
+	if token == "" {
+		return SignalNoChallenge
+	}
+	encoded, signature, found := strings.Cut(token, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if !found || err != nil || len(payload) != 8+challengeNonceSize {
+		return SignalBadChallenge
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, d.sign(payload)) {
+		return SignalBadChallenge
+	}
+
+	now := time.Now()
+	issuedAt := time.UnixMilli(int64(binary.BigEndian.Uint64(payload)))
+	expiresAt := issuedAt.Add(d.cfg.ChallengeTTL)
+	if now.After(expiresAt) {
+		return SignalBadChallenge
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sweep(now)
+	if _, used := d.spent[encoded]; used {
+		return SignalBadChallenge
+	}
+	d.spent[encoded] = expiresAt
+	if now.Sub(issuedAt) < d.cfg.ChallengeMinAge {
+		return SignalFastChallenge
+	}
+	return ""
+}
+
+// sign returns the MAC of a challenge payload
+func (d *BotDetector) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(d.cfg.ChallengeSecret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// sweep forgets, at most once a minute, the spent tokens that expired since, as they are refused anyway
+func (d *BotDetector) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < time.Minute {
+		return
+	}
+	d.lastSweep = now
+	for token, expiresAt := range d.spent {
+		if now.After(expiresAt) {
+			delete(d.spent, token)
+		}
+	}
+}
+
+// Stats returns the counters of the detector
+func (d *BotDetector) Stats() BotStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stats := BotStats{Rejected: make(map[string]int64, len(d.rejected)), Challenges: d.issued}
+	for signal, count := range d.rejected {
+		stats.Rejected[signal] = count
+	}
+	return stats
+}
+
+// honeypotFilled reports whether a JSON or URL-encoded body has a non-empty value for field. Bots that
+// fill in every field of a form also fill in the honeypot, which the frontend hides from people.
+func honeypotFilled(contentType string, body []byte, field string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		return err == nil && values.Get(field) != ""
+	case "application/json", "":
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(body, &fields) != nil {
+			return false
+		}
+		value, ok := fields[field]
+		if !ok {
+			return false
+		}
+		value = bytes.TrimSpace(value)
+		return !bytes.Equal(value, []byte(`""`)) && !bytes.Equal(value, []byte("null")) && !bytes.Equal(value, []byte("false"))
+	}
+	return false
+}
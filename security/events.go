@@ -0,0 +1,42 @@
+package security
+
+import (
+	"time"
+)
+
+// EventType identifies the kind of security event
+type EventType string
+
+// Security event types
+const (
+	EventLoginSuccess    EventType = "login_success"
+	EventLoginFailure    EventType = "login_failure"
+	EventLoginBlocked    EventType = "login_blocked"
+	EventBotRejected     EventType = "bot_rejected"
+	EventAdminAction     EventType = "admin_action"
+	EventRegistration    EventType = "registration"
+	EventPasswordChange  EventType = "password_change"
+	EventRoleChange      EventType = "role_change"
+	EventTokenRevocation EventType = "token_revocation"
+)
+
+// Event outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Event represents a single audit-quality security event
+type Event struct {
+	Type      EventType         `json:"type"`
+	Outcome   string            `json:"outcome"`
+	Timestamp time.Time         `json:"timestamp"`
+	UserID    string            `json:"user_id,omitempty"`
+	Email     string            `json:"email,omitempty"`
+	ActorID   string            `json:"actor_id,omitempty"`
+	ClientIP  string            `json:"client_ip,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+	Reason    string            `json:"reason,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+}
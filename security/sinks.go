@@ -0,0 +1,140 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-backend-template/httpclient"
+)
+
+// Sink is a destination for security events
+type Sink interface {
+	Write(ctx context.Context, event Event) error
+	Close() error
+}
+
+// NoopSink discards all events
+type NoopSink struct{}
+
+// Write discards the event
+func (NoopSink) Write(ctx context.Context, event Event) error { return nil }
+
+// Close is a no-op
+func (NoopSink) Close() error { return nil }
+
+// WriterSink writes events as JSON lines to an io.Writer (e.g. a rotating file)
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a sink writing JSON lines to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Write encodes the event as a single JSON line
+func (s *WriterSink) Write(ctx context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the underlying writer if it supports closing
+func (s *WriterSink) Close() error {
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// SyslogSink forwards events to a local or remote syslog daemon
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink connects to syslog; an empty network/addr uses the local daemon
+func NewSyslogSink(network, addr, tag string) (*SyslogSink, error) {
+	writer, err := syslog.Dial(network, addr, syslog.LOG_AUTH|syslog.LOG_NOTICE, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+// Write sends the event as a JSON syslog message
+func (s *SyslogSink) Write(ctx context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if event.Outcome == OutcomeFailure {
+		return s.writer.Warning(string(line))
+	}
+	return s.writer.Notice(string(line))
+}
+
+// Close closes the syslog connection
+func (s *SyslogSink) Close() error {
+	return s.writer.Close()
+}
+
+// HTTPSink posts events as JSON to a SIEM collector endpoint
+type HTTPSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPSink creates a sink posting to url with an optional bearer token
+func NewHTTPSink(url, token string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		token:  token,
+		client: httpclient.New("security_events", timeout),
+	}
+}
+
+// Write posts the event to the collector
+func (s *HTTPSink) Write(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send security event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("security event collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close is a no-op for the HTTP sink
+func (s *HTTPSink) Close() error { return nil }
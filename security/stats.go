@@ -0,0 +1,69 @@
+package security
+
+import (
+	"sync"
+	"time"
+)
+
+// LoginWindow is the period LoginCounts covers
+const LoginWindow = 24 * time.Hour
+
+// LoginCounts counts the successful and failed logins of the last LoginWindow
+type LoginCounts struct {
+	Successes int64
+	Failures  int64
+}
+
+// FailureRate returns the share of failed logins, or zero without logins
+func (c LoginCounts) FailureRate() float64 {
+	if c.Successes+c.Failures == 0 {
+		return 0
+	}
+	return float64(c.Failures) / float64(c.Successes+c.Failures)
+}
+
+// loginCounter counts login events in hourly buckets, so old hours drop out without keeping every event.
+// The zero value is ready to use.
+type loginCounter struct {
+	mu      sync.Mutex
+	buckets [24]loginBucket
+}
+
+// loginBucket holds the counts of one hour, identified by its Unix hour
+type loginBucket struct {
+	hour int64
+	LoginCounts
+}
+
+// record counts a login event at t
+func (c *loginCounter) record(eventType EventType, t time.Time) {
+	hour := t.Unix() / 3600
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bucket := &c.buckets[hour%int64(len(c.buckets))]
+	if bucket.hour != hour {
+		*bucket = loginBucket{hour: hour}
+	}
+	if eventType == EventLoginSuccess {
+		bucket.Successes++
+	} else {
+		bucket.Failures++
+	}
+}
+
+// counts sums the buckets of the hours within LoginWindow of now
+func (c *loginCounter) counts(now time.Time) LoginCounts {
+	current := now.Unix() / 3600
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var counts LoginCounts
+	for _, bucket := range c.buckets {
+		if current-bucket.hour < int64(len(c.buckets)) {
+			counts.Successes += bucket.Successes
+			counts.Failures += bucket.Failures
+		}
+	}
+	return counts
+}
@@ -0,0 +1,193 @@
+package security
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-backend-template/config"
+)
+
+// LoginThrottle slows down password guessing against one account from one client. Failed logins are
+// counted per email and IP: after the free attempts, each failure makes the client wait twice as long
+// before the next attempt, and enough failures block the pair for a while. Counts live in memory, so
+// each instance of the API throttles on its own; a success or a quiet Window clears them.
+type LoginThrottle struct {
+	cfg config.LoginThrottleConfig
+
+	mu        sync.Mutex
+	attempts  map[string]*loginAttempts
+	lastSweep time.Time
+
+	delayed atomic.Int64
+	blocked atomic.Int64
+	blocks  atomic.Int64
+}
+
+// loginAttempts are the recent failures of one email and IP. An attempt counts as a failure from the
+// moment Allow lets it through, so concurrent attempts cannot all slip past the delay before the first
+// of them fails; Success clears the count and Cancel gives the attempt back.
+type loginAttempts struct {
+	failures     int
+	pending      int
+	lastFailure  time.Time
+	nextAttempt  time.Time
+	blockedUntil time.Time
+}
+
+// ThrottleStats are the counters of a LoginThrottle since the process started
+type ThrottleStats struct {
+	// Delayed counts attempts refused because they came before the delay elapsed
+	Delayed int64
+	// Blocked counts attempts refused because the email and IP were blocked
+	Blocked int64
+	// Blocks counts the blocks imposed
+	Blocks int64
+	// BlockedKeys is the number of email and IP pairs blocked now
+	BlockedKeys int
+}
+
+// NewLoginThrottle creates a throttle for cfg
+func NewLoginThrottle(cfg config.LoginThrottleConfig) *LoginThrottle {
+	return &LoginThrottle{cfg: cfg, attempts: make(map[string]*loginAttempts)}
+}
+
+// loginKey identifies the attempts of one client against one account
+func loginKey(email, ip string) string {
+	return strings.ToLower(strings.TrimSpace(email)) + "|" + ip
+}
+
+// LoginAttempt is a login let through by Allow. It is settled by Success, Failure, or Cancel; only the
+// first of them counts, so Cancel can be deferred to give back an attempt that ended in neither.
+type LoginAttempt struct {
+	throttle *LoginThrottle
+	key      string
+	settled  bool
+}
+
+// Allow reserves an attempt at email from ip, or reports how long the client must wait before trying
+// again. Past the free attempts, a client tries one login at a time.
+func (t *LoginThrottle) Allow(email, ip string) (*LoginAttempt, time.Duration) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sweep(now)
+
+	key := loginKey(email, ip)
+	attempts := t.attempts[key]
+	switch {
+	case attempts == nil || attempts.pending == 0 && now.Sub(attempts.lastFailure) > t.cfg.Window && now.After(attempts.blockedUntil):
+		attempts = &loginAttempts{}
+		t.attempts[key] = attempts
+	case now.Before(attempts.blockedUntil):
+		t.blocked.Add(1)
+		return nil, attempts.blockedUntil.Sub(now)
+	case now.Before(attempts.nextAttempt):
+		t.delayed.Add(1)
+		return nil, attempts.nextAttempt.Sub(now)
+	case attempts.pending > 0 && attempts.failures >= t.cfg.FreeAttempts:
+		t.delayed.Add(1)
+		return nil, t.cfg.BaseDelay
+	}
+
+	attempts.failures++
+	attempts.pending++
+	attempts.lastFailure = now
+	return &LoginAttempt{throttle: t, key: key}, 0
+}
+
+// Failure settles a failed login and returns how long the client must wait before the next attempt, and
+// whether the failure blocked the email and IP
+func (a *LoginAttempt) Failure() (time.Duration, bool) {
+	if a.settled {
+		return 0, false
+	}
+	a.settled = true
+
+	t, now := a.throttle, time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	attempts := t.attempts[a.key]
+	if attempts == nil {
+		// A concurrent success cleared the count this attempt was part of
+		attempts = &loginAttempts{failures: 1, pending: 1}
+		t.attempts[a.key] = attempts
+	}
+	attempts.pending--
+	attempts.lastFailure = now
+
+	if attempts.failures >= t.cfg.BlockAfter {
+		attempts.blockedUntil = now.Add(t.cfg.BlockDuration)
+		attempts.failures = attempts.pending
+		t.blocks.Add(1)
+		return t.cfg.BlockDuration, true
+	}
+	if extra := attempts.failures - t.cfg.FreeAttempts; extra > 0 {
+		delay := t.cfg.BaseDelay
+		for i := 1; i < extra && delay < t.cfg.MaxDelay; i++ {
+			delay *= 2
+		}
+		delay = min(delay, t.cfg.MaxDelay)
+		attempts.nextAttempt = now.Add(delay)
+		return delay, false
+	}
+	return 0, false
+}
+
+// Success settles a successful login, clearing the failures of its email and IP
+func (a *LoginAttempt) Success() {
+	if a.settled {
+		return
+	}
+	a.settled = true
+
+	a.throttle.mu.Lock()
+	defer a.throttle.mu.Unlock()
+	delete(a.throttle.attempts, a.key)
+}
+
+// Cancel gives back an attempt that neither succeeded nor failed, such as one that hit a database error
+func (a *LoginAttempt) Cancel() {
+	if a.settled {
+		return
+	}
+	a.settled = true
+
+	a.throttle.mu.Lock()
+	defer a.throttle.mu.Unlock()
+	if attempts := a.throttle.attempts[a.key]; attempts != nil {
+		attempts.failures = max(0, attempts.failures-1)
+		attempts.pending--
+	}
+}
+
+// Stats returns the counters of the throttle
+func (t *LoginThrottle) Stats() ThrottleStats {
+	now := time.Now()
+	stats := ThrottleStats{Delayed: t.delayed.Load(), Blocked: t.blocked.Load(), Blocks: t.blocks.Load()}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, attempts := range t.attempts {
+		if now.Before(attempts.blockedUntil) {
+			stats.BlockedKeys++
+		}
+	}
+	return stats
+}
+
+// sweep drops, at most once a minute, the attempts that neither block nor count any more, so clients
+// that never come back do not accumulate
+func (t *LoginThrottle) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < time.Minute {
+		return
+	}
+	t.lastSweep = now
+	for key, attempts := range t.attempts {
+		if attempts.pending == 0 && now.Sub(attempts.lastFailure) > t.cfg.Window && now.After(attempts.blockedUntil) {
+			delete(t.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package security
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go-backend-template/config"
+)
+
+func testThrottle() *LoginThrottle {
+	return NewLoginThrottle(config.LoginThrottleConfig{
+		Enabled:       true,
+		FreeAttempts:  3,
+		BaseDelay:     time.Second,
+		MaxDelay:      time.Minute,
+		BlockAfter:    10,
+		BlockDuration: time.Hour,
+		Window:        time.Hour,
+	})
+}
+
+// TestLoginThrottleConcurrentAttempts checks that attempts started together cannot all pass Allow before
+// any of them fails: only the free attempts get through
+func TestLoginThrottleConcurrentAttempts(t *testing.T) {
+	throttle := testThrottle()
+
+	var mu sync.Mutex
+	var allowed []*LoginAttempt
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if attempt, wait := throttle.Allow("alice@example.com", "10.0.0.1"); wait == 0 {
+				mu.Lock()
+				allowed = append(allowed, attempt)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(allowed) != 3 {
+		t.Fatalf("allowed %d concurrent attempts, want the 3 free ones", len(allowed))
+	}
+	for _, attempt := range allowed {
+		attempt.Failure()
+	}
+
+	// The next attempt is the first past the free ones; its failure delays the one after
+	attempt, wait := throttle.Allow("alice@example.com", "10.0.0.1")
+	if wait != 0 {
+		t.Fatalf("attempt after the free ones waited %s", wait)
+	}
+	if delay, _ := attempt.Failure(); delay != time.Second {
+		t.Fatalf("delay after the first failure past the free attempts = %s, want 1s", delay)
+	}
+	if _, wait := throttle.Allow("alice@example.com", "10.0.0.1"); wait <= 0 {
+		t.Fatal("attempt within the delay was allowed")
+	}
+}
+
+// TestLoginThrottleSettle checks that only the first settlement of an attempt counts
+func TestLoginThrottleSettle(t *testing.T) {
+	throttle := testThrottle()
+
+	for i := 0; i < 5; i++ {
+		attempt, wait := throttle.Allow("bob@example.com", "10.0.0.2")
+		if wait != 0 {
+			t.Fatalf("canceled attempt %d waited %s", i, wait)
+		}
+		attempt.Cancel()
+		attempt.Failure()
+	}
+
+	attempt, _ := throttle.Allow("bob@example.com", "10.0.0.2")
+	attempt.Success()
+	attempt.Cancel()
+	if _, ok := throttle.attempts[loginKey("bob@example.com", "10.0.0.2")]; ok {
+		t.Fatal("success did not clear the attempts")
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// AdminService manages accounts outside the HTTP API, for operators and the CLI
+type AdminService interface {
+	// CreateUser creates an active account with the role. A taken email or username is ErrDuplicateEmail
+	// or ErrDuplicateUsername.
+	CreateUser(ctx context.Context, req models.RegisterRequest, role string) (models.UserInfo, error)
+	// FindByEmail returns the user with the email, or ErrUserNotFound
+	FindByEmail(ctx context.Context, email string) (models.UserInfo, error)
+	// ResetPassword replaces the password of the user with the email, or returns ErrUserNotFound
+	ResetPassword(ctx context.Context, email, password string) error
+	// HasUsers reports whether any user exists, counting soft-deleted users
+	HasUsers(ctx context.Context) (bool, error)
+}
+
+// adminService implements AdminService on the configured database
+type adminService struct {
+	mongoDB       *database.MongoDB
+	postgresDB    *database.PostgresDB
+	passwordUtils *utils.PasswordUtils
+}
+
+// NewAdminService creates an admin service; PostgreSQL is used when both databases are configured
+func NewAdminService(mongoDB *database.MongoDB, postgresDB *database.PostgresDB) AdminService {
+	return &adminService{
+		mongoDB:       mongoDB,
+		postgresDB:    postgresDB,
+		passwordUtils: &utils.PasswordUtils{},
+	}
+}
+
+// CreateUser relies on the unique indexes to reject duplicates
+func (s *adminService) CreateUser(ctx context.Context, req models.RegisterRequest, role string) (models.UserInfo, error) {
+	hashedPassword, err := s.passwordUtils.HashPassword(req.Password)
+	if err != nil {
+		return models.UserInfo{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+	now := time.Now()
+
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		user := models.User{
+			Email:           req.Email,
+			Username:        req.Username,
+			Password:        hashedPassword,
+			FirstName:       req.FirstName,
+			LastName:        req.LastName,
+			Locale:          req.Locale,
+			AnalyticsOptOut: req.AnalyticsOptOut,
+			Role:            role,
+			IsActive:        true,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		if err := s.postgresDB.WithContext(ctx).Create(&user).Error; err != nil {
+			return models.UserInfo{}, duplicateUserError(err)
+		}
+		return user.Info(), nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		user := models.UserMongo{
+			Email:           req.Email,
+			Username:        req.Username,
+			Password:        hashedPassword,
+			FirstName:       req.FirstName,
+			LastName:        req.LastName,
+			Locale:          req.Locale,
+			AnalyticsOptOut: req.AnalyticsOptOut,
+			Role:            role,
+			IsActive:        true,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		result, err := s.mongoDB.Collection("users").InsertOne(ctx, user)
+		if err != nil {
+			return models.UserInfo{}, duplicateUserError(err)
+		}
+		user.ID = result.InsertedID.(primitive.ObjectID)
+		return user.Info(), nil
+	}
+
+	return models.UserInfo{}, errNoDatabase
+}
+
+// FindByEmail ignores soft-deleted users
+func (s *adminService) FindByEmail(ctx context.Context, email string) (models.UserInfo, error) {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		var user models.User
+		if err := s.postgresDB.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.UserInfo{}, ErrUserNotFound
+			}
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		var user models.UserMongo
+		err := s.mongoDB.Collection("users").FindOne(ctx, notDeleted(bson.M{"email": email})).Decode(&user)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return models.UserInfo{}, ErrUserNotFound
+			}
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	return models.UserInfo{}, errNoDatabase
+}
+
+// ResetPassword stores the new hash and bumps updated_at, which changes the profile's ETag
+func (s *adminService) ResetPassword(ctx context.Context, email, password string) error {
+	hashedPassword, err := s.passwordUtils.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	now := time.Now()
+
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		result := s.postgresDB.WithContext(ctx).Model(&models.User{}).
+			Where("email = ?", email).
+			Updates(map[string]interface{}{"password": hashedPassword, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		result, err := s.mongoDB.Collection("users").UpdateOne(ctx,
+			notDeleted(bson.M{"email": email}),
+			bson.M{"$set": bson.M{"password": hashedPassword, "updated_at": now}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	}
+
+	return errNoDatabase
+}
+
+// HasUsers reads at most one row, so it stays cheap on large tables
+func (s *adminService) HasUsers(ctx context.Context) (bool, error) {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		var ids []string
+		if err := s.postgresDB.WithContext(ctx).Unscoped().Model(&models.User{}).Limit(1).Pluck("id", &ids).Error; err != nil {
+			return false, err
+		}
+		return len(ids) > 0, nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		count, err := s.mongoDB.Collection("users").CountDocuments(ctx, bson.M{}, options.Count().SetLimit(1))
+		if err != nil {
+			return false, err
+		}
+		return count > 0, nil
+	}
+
+	return false, errNoDatabase
+}
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+
+	"go-backend-template/database"
+	"go-backend-template/hooks"
+	"go-backend-template/jwt"
+	"go-backend-template/models"
+	"go-backend-template/sessions"
+	"go-backend-template/utils"
+)
+
+// AuthService registers accounts and signs users in
+type AuthService interface {
+	// Register creates an active user account and signs its first token. A taken email or username is
+	// ErrDuplicateEmail or ErrDuplicateUsername, a breached password is ErrBreachedPassword, an email
+	// domain without mail servers is ErrUndeliverableEmail, and a hook refusing it is a *hooks.Rejection.
+	Register(ctx context.Context, req models.RegisterRequest) (*models.AuthResponse, error)
+	// Login verifies the credentials and signs a token; wrong credentials are a *LoginError, and a hook
+	// refusing the token is a *hooks.Rejection
+	Login(ctx context.Context, req models.LoginRequest) (*models.AuthResponse, error)
+	// Refresh exchanges the refresh token of a session for a new token and refresh token; an unknown,
+	// exchanged, or expired refresh token, or one of a deactivated or deleted user, is
+	// ErrInvalidRefreshToken
+	Refresh(ctx context.Context, refreshToken string) (*models.AuthResponse, error)
+}
+
+// PasswordChecker reports whether a password appears in a known data breach; *pwned.Checker implements it
+type PasswordChecker interface {
+	Breached(ctx context.Context, password string) (bool, error)
+}
+
+// EmailChecker reports whether an email address can receive mail; *utils.MXChecker implements it
+type EmailChecker interface {
+	Deliverable(ctx context.Context, email string) bool
+}
+
+// RegistrationChecks are the optional checks of new accounts that need network lookups; nil ones are
+// skipped
+type RegistrationChecks struct {
+	Passwords PasswordChecker
+	Emails    EmailChecker
+}
+
+// authService implements AuthService on the configured database
+type authService struct {
+	mongoDB       *database.MongoDB
+	postgresDB    *database.PostgresDB
+	passwordUtils *utils.PasswordUtils
+	jwtUtils      *utils.JWTUtils
+	hooks         *hooks.Registry
+	checks        RegistrationChecks
+	sessions      *sessions.Manager
+}
+
+// NewAuthService creates an auth service; PostgreSQL is used when both databases are configured. The
+// registration and token hooks of registry, which may be nil, run around its operations, and checks
+// vet registrations. Each token starts a session of sessionManager, with a refresh token; a nil
+// sessionManager disables refresh tokens.
+func NewAuthService(mongoDB *database.MongoDB, postgresDB *database.PostgresDB, jwtUtils *utils.JWTUtils, registry *hooks.Registry, checks RegistrationChecks, sessionManager *sessions.Manager) AuthService {
+	return &authService{
+		mongoDB:       mongoDB,
+		postgresDB:    postgresDB,
+		passwordUtils: &utils.PasswordUtils{},
+		jwtUtils:      jwtUtils,
+		hooks:         registry,
+		checks:        checks,
+		sessions:      sessionManager,
+	}
+}
+
+// Register creates the user and issues its token in one transaction, so a failure leaves no
+// half-registered account; the unique indexes reject duplicates atomically, even for concurrent
+// registrations
+func (s *authService) Register(ctx context.Context, req models.RegisterRequest) (*models.AuthResponse, error) {
+	if err := s.hooks.RunBeforeRegister(ctx, &req); err != nil {
+		return nil, err
+	}
+	if s.checks.Emails != nil && !s.checks.Emails.Deliverable(ctx, req.Email) {
+		return nil, ErrUndeliverableEmail
+	}
+	if s.checks.Passwords != nil {
+		breached, err := s.checks.Passwords.Breached(ctx, req.Password)
+		if err != nil {
+			return nil, err
+		}
+		if breached {
+			return nil, ErrBreachedPassword
+		}
+	}
+	hashedPassword, err := s.passwordUtils.HashPassword(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		user := models.User{
+			Email:           req.Email,
+			Username:        req.Username,
+			Password:        hashedPassword,
+			FirstName:       req.FirstName,
+			LastName:        req.LastName,
+			Locale:          req.Locale,
+			AnalyticsOptOut: req.AnalyticsOptOut,
+			Role:            "user",
+			IsActive:        true,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+
+		var response *models.AuthResponse
+		var session *sessionStart
+		err := s.postgresDB.WithTransaction(ctx, func(tx *database.PostgresDB) error {
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+			var err error
+			if session, err = s.newSession(ctx, user.ID, false); err != nil {
+				return err
+			}
+			response, err = s.issueToken(ctx, user.Info(), session)
+			return err
+		})
+		if err != nil {
+			return nil, duplicateUserError(err)
+		}
+		if err := s.startSession(ctx, session); err != nil {
+			return nil, err
+		}
+		s.hooks.RunAfterRegister(ctx, response.User)
+		return response, nil
+	}
+
+	// MongoDB implementation; the transaction is used where the deployment supports it
+	if s.mongoDB != nil {
+		userMongo := models.UserMongo{
+			Email:           req.Email,
+			Username:        req.Username,
+			Password:        hashedPassword,
+			FirstName:       req.FirstName,
+			LastName:        req.LastName,
+			Locale:          req.Locale,
+			AnalyticsOptOut: req.AnalyticsOptOut,
+			Role:            "user",
+			IsActive:        true,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+
+		var response *models.AuthResponse
+		var session *sessionStart
+		err := s.mongoDB.WithTransaction(ctx, func(ctx context.Context) error {
+			result, err := s.mongoDB.Collection("users").InsertOne(ctx, userMongo)
+			if err != nil {
+				return err
+			}
+			userMongo.ID = result.InsertedID.(primitive.ObjectID)
+			if session, err = s.newSession(ctx, userMongo.ID.Hex(), false); err != nil {
+				return err
+			}
+			response, err = s.issueToken(ctx, userMongo.Info(), session)
+			return err
+		})
+		if err != nil {
+			return nil, duplicateUserError(err)
+		}
+		if err := s.startSession(ctx, session); err != nil {
+			return nil, err
+		}
+		s.hooks.RunAfterRegister(ctx, response.User)
+		return response, nil
+	}
+
+	return nil, errNoDatabase
+}
+
+// Login looks the user up by email, verifies the password, and records the time of the login
+func (s *authService) Login(ctx context.Context, req models.LoginRequest) (*models.AuthResponse, error) {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		var user models.User
+		if err := s.postgresDB.WithContext(ctx).Where("email = ?", req.Email).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, &LoginError{Reason: "unknown_email"}
+			}
+			return nil, err
+		}
+
+		if err := s.passwordUtils.VerifyPassword(user.Password, req.Password); err != nil {
+			return nil, &LoginError{UserID: user.ID, Reason: "invalid_password"}
+		}
+		if !user.IsActive {
+			return nil, &LoginError{UserID: user.ID, Reason: "inactive"}
+		}
+		// UpdateColumn leaves updated_at, and with it the ETag of the profile, unchanged
+		if err := s.postgresDB.WithContext(ctx).Model(&user).UpdateColumn("last_login_at", time.Now()).Error; err != nil {
+			return nil, fmt.Errorf("failed to record login: %w", err)
+		}
+		return s.signIn(ctx, user.Info(), req.RememberMe)
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		var user models.UserMongo
+		err := s.mongoDB.Collection("users").FindOne(ctx, notDeleted(bson.M{"email": req.Email})).Decode(&user)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, &LoginError{Reason: "unknown_email"}
+			}
+			return nil, err
+		}
+
+		if err := s.passwordUtils.VerifyPassword(user.Password, req.Password); err != nil {
+			return nil, &LoginError{UserID: user.ID.Hex(), Reason: "invalid_password"}
+		}
+		if !user.IsActive {
+			return nil, &LoginError{UserID: user.ID.Hex(), Reason: "inactive"}
+		}
+		_, err = s.mongoDB.Collection("users").UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": bson.M{"last_login_at": time.Now()}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record login: %w", err)
+		}
+		return s.signIn(ctx, user.Info(), req.RememberMe)
+	}
+
+	return nil, errNoDatabase
+}
+
+// Refresh rotates the refresh token first, so a token is exchanged once even by concurrent requests, then
+// checks that the user may still sign in; the session of a user who may not is ended
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*models.AuthResponse, error) {
+	if s.sessions == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	token, session, err := s.sessions.Refresh(ctx, refreshToken)
+	if errors.Is(err, sessions.ErrNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.activeUser(ctx, session.UserID)
+	if errors.Is(err, ErrUserNotFound) {
+		if err := s.sessions.Revoke(ctx, session.UserID, session.ID); err != nil && !errors.Is(err, sessions.ErrNotFound) {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.issueToken(ctx, user, &sessionStart{token: token, session: session})
+}
+
+// activeUser returns the user of a session, or ErrUserNotFound for one that was deleted or deactivated
+func (s *authService) activeUser(ctx context.Context, userID string) (models.UserInfo, error) {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		id, err := postgresUserID(s.postgresDB, userID)
+		if err != nil {
+			return models.UserInfo{}, ErrUserNotFound
+		}
+		var user models.User
+		if err := s.postgresDB.WithContext(ctx).First(&user, "id = ? AND is_active = ?", id, true).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.UserInfo{}, ErrUserNotFound
+			}
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		objectID, err := mongoUserID(userID)
+		if err != nil {
+			return models.UserInfo{}, ErrUserNotFound
+		}
+		var user models.UserMongo
+		err = s.mongoDB.Collection("users").FindOne(ctx, notDeleted(bson.M{"_id": objectID, "is_active": true})).Decode(&user)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return models.UserInfo{}, ErrUserNotFound
+			}
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	return models.UserInfo{}, errNoDatabase
+}
+
+// sessionStart is a session that was built but, until the token is issued, not stored, with its refresh
+// token
+type sessionStart struct {
+	token   string
+	session *models.Session
+}
+
+// newSession builds a session of the user for the token being issued, or returns nil when sessions are
+// disabled
+func (s *authService) newSession(ctx context.Context, userID string, rememberMe bool) (*sessionStart, error) {
+	if s.sessions == nil {
+		return nil, nil
+	}
+	token, session, err := s.sessions.New(ctx, userID, rememberMe)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionStart{token: token, session: session}, nil
+}
+
+// startSession stores the session a token was issued for
+func (s *authService) startSession(ctx context.Context, session *sessionStart) error {
+	if session == nil {
+		return nil
+	}
+	if err := s.sessions.Create(ctx, session.session); err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	return nil
+}
+
+// signIn issues a token for the user in a new session
+func (s *authService) signIn(ctx context.Context, user models.UserInfo, rememberMe bool) (*models.AuthResponse, error) {
+	session, err := s.newSession(ctx, user.ID, rememberMe)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.issueToken(ctx, user, session)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.startSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// issueToken signs a token for the user once the token hooks accept its claims; the token belongs to the
+// session, which may be nil, and the response carries its refresh token
+func (s *authService) issueToken(ctx context.Context, user models.UserInfo, session *sessionStart) (*models.AuthResponse, error) {
+	claims := jwt.NewClaims(user.ID, user.Email, user.Username, user.Role, user.Locale)
+	if session != nil {
+		claims.SessionID = session.session.ID
+	}
+	if err := s.hooks.RunBeforeToken(ctx, user, claims); err != nil {
+		return nil, err
+	}
+	token, expiresAt, err := jwt.Sign(s.jwtUtils.Secret(), claims)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenGeneration, err)
+	}
+	response := &models.AuthResponse{Token: token, User: user, ExpiresAt: expiresAt}
+	if session != nil {
+		response.RefreshToken = session.token
+		response.RefreshExpiresAt = &session.session.ExpiresAt
+		response.SessionID = session.session.ID
+	}
+	return response, nil
+}
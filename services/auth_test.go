@@ -0,0 +1,127 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"go-backend-template/config"
+	"go-backend-template/database"
+	"go-backend-template/jwt"
+	"go-backend-template/models"
+	"go-backend-template/services"
+	"go-backend-template/sessions"
+	"go-backend-template/testutil"
+)
+
+// sqliteAuth returns the auth service on an empty in-memory SQLite database, its sessions kept there by
+// the PostgreSQL store
+func sqliteAuth(t *testing.T) (services.AuthService, *database.PostgresDB, *testutil.TokenFactory) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.User{}, &models.Session{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	pg := &database.PostgresDB{DB: db}
+	manager := sessions.NewManager(config.SessionsConfig{
+		Enabled:               true,
+		IdleTimeout:           time.Hour,
+		MaxLifetime:           2 * time.Hour,
+		RememberMeIdleTimeout: 24 * time.Hour,
+		RememberMeMaxLifetime: 72 * time.Hour,
+	}, sessions.NewPostgresStore(pg))
+	tokens := testutil.NewTokenFactory()
+	return services.NewAuthService(nil, pg, tokens.JWT, nil, services.RegistrationChecks{}, manager), pg, tokens
+}
+
+// TestAuthSessions signs in with and without remember me and refreshes the tokens until the user is
+// deactivated
+func TestAuthSessions(t *testing.T) {
+	ctx := context.Background()
+	auth, pg, tokens := sqliteAuth(t)
+
+	registered, err := auth.Register(ctx, models.RegisterRequest{
+		Email: "ada@example.com", Username: "ada", Password: "Password123", FirstName: "Ada", LastName: "Lovelace",
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if registered.RefreshToken == "" || registered.SessionID == "" {
+		t.Fatalf("register returned no session: %+v", registered)
+	}
+
+	client := sessions.WithClient(ctx, sessions.Client{UserAgent: "test-agent", IPAddress: "203.0.113.7"})
+	login := func(rememberMe bool) *models.AuthResponse {
+		t.Helper()
+		response, err := auth.Login(client, models.LoginRequest{Email: "ada@example.com", Password: "Password123", RememberMe: rememberMe})
+		if err != nil {
+			t.Fatalf("login: %v", err)
+		}
+		return response
+	}
+	session := login(false)
+	remembered := login(true)
+	if until := time.Until(*session.RefreshExpiresAt); until <= 0 || until > time.Hour {
+		t.Errorf("refresh token expires in %v, want at most 1h", until)
+	}
+	if until := time.Until(*remembered.RefreshExpiresAt); until <= time.Hour || until > 24*time.Hour {
+		t.Errorf("remembered refresh token expires in %v, want at most 24h", until)
+	}
+
+	var stored models.Session
+	if err := pg.First(&stored, "id = ?", remembered.SessionID).Error; err != nil {
+		t.Fatalf("find session: %v", err)
+	}
+	if !stored.RememberMe || stored.UserAgent != "test-agent" || stored.IPAddress != "203.0.113.7" {
+		t.Errorf("stored session = %+v, want remembered with the client of the login", stored)
+	}
+
+	claims, err := jwt.ValidateToken(tokens.JWT.Secret(), remembered.Token)
+	if err != nil {
+		t.Fatalf("validate token: %v", err)
+	}
+	if claims.SessionID != remembered.SessionID {
+		t.Errorf("token session = %q, want %q", claims.SessionID, remembered.SessionID)
+	}
+
+	refreshed, err := auth.Refresh(ctx, remembered.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if refreshed.Token == "" || refreshed.RefreshToken == remembered.RefreshToken || refreshed.SessionID != remembered.SessionID {
+		t.Errorf("refresh = %+v, want a new token and refresh token of session %q", refreshed, remembered.SessionID)
+	}
+	if refreshed.User.Email != "ada@example.com" {
+		t.Errorf("refresh user = %q, want ada@example.com", refreshed.User.Email)
+	}
+	if _, err := auth.Refresh(ctx, remembered.RefreshToken); !errors.Is(err, services.ErrInvalidRefreshToken) {
+		t.Errorf("reused refresh token: err = %v, want ErrInvalidRefreshToken", err)
+	}
+
+	// A deactivated user's sessions stop refreshing and end
+	if err := pg.Model(&models.User{}).Where("id = ?", refreshed.User.ID).UpdateColumn("is_active", false).Error; err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if _, err := auth.Refresh(ctx, session.RefreshToken); !errors.Is(err, services.ErrInvalidRefreshToken) {
+		t.Errorf("refresh of deactivated user: err = %v, want ErrInvalidRefreshToken", err)
+	}
+	var count int64
+	if err := pg.Model(&models.Session{}).Where("id = ?", session.SessionID).Count(&count).Error; err != nil || count != 0 {
+		t.Errorf("deactivated user's session remains (count %d, err %v)", count, err)
+	}
+}
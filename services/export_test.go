@@ -0,0 +1,7 @@
+package services
+
+// Exported for the tests of package services_test, which use the testutil fakes
+var (
+	MongoSort   = mongoSort
+	KeysetOrder = keysetOrder
+)
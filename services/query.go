@@ -0,0 +1,265 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend-template/utils"
+)
+
+// sqlOperators maps filter operators to SQL; columns come from the filter allowlist
+var sqlOperators = map[string]string{
+	utils.FilterEq: "= ?",
+	utils.FilterNe: "<> ?",
+	utils.FilterGt: "> ?",
+	utils.FilterLt: "< ?",
+	utils.FilterIn: "IN ?",
+}
+
+// mongoOperators maps filter operators to MongoDB query operators
+var mongoOperators = map[string]string{
+	utils.FilterEq: "$eq",
+	utils.FilterNe: "$ne",
+	utils.FilterGt: "$gt",
+	utils.FilterLt: "$lt",
+	utils.FilterIn: "$in",
+}
+
+// searchWords splits a search into lowercase words, dropping punctuation, so they are safe to place in a
+// tsquery or quote in a MongoDB $text search
+func searchWords(search string) []string {
+	return strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// userTSQuery builds a tsquery matching users that have every word of search as a word prefix, so "jo
+// sm" finds John Smith; it is empty when search has no words
+func userTSQuery(search string) string {
+	words := searchWords(search)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// applyUserTextSearch matches search against the search_vector column with the GIN index, ordering by
+// relevance first when rank is set. It reports false, leaving db unchanged, when full-text search cannot
+// serve the search: on SQLite, which has no search_vector, or when search has no words.
+func applyUserTextSearch(db *gorm.DB, search string, rank bool) (*gorm.DB, bool) {
+	tsquery := userTSQuery(search)
+	if tsquery == "" || db.Dialector.Name() == "sqlite" {
+		return db, false
+	}
+	db = db.Where("search_vector @@ to_tsquery('simple', ?)", tsquery)
+	if rank {
+		db = db.Order(clause.Expr{SQL: "ts_rank(search_vector, to_tsquery('simple', ?)) DESC", Vars: []interface{}{tsquery}})
+	}
+	return db, true
+}
+
+// mongoUserTextSearch builds a $text filter requiring every word of search, served by the users text
+// index; it is nil when search has no words
+func mongoUserTextSearch(search string) bson.M {
+	words := searchWords(search)
+	if len(words) == 0 {
+		return nil
+	}
+	// Quoted words are all required; unquoted ones would match any of them
+	return bson.M{"$text": bson.M{"$search": `"` + strings.Join(words, `" "`) + `"`}}
+}
+
+// mongoTextScore is the sort key ordering $text matches by relevance
+var mongoTextScore = bson.E{Key: "score", Value: bson.M{"$meta": "textScore"}}
+
+// applyUserSearch matches search case-insensitively against the user's name, email, and username
+func applyUserSearch(db *gorm.DB, search string) *gorm.DB {
+	if search == "" {
+		return db
+	}
+	searchPattern := "%" + search + "%"
+	if db.Dialector.Name() == "sqlite" {
+		// SQLite has no ILIKE; its LIKE is already case-insensitive for ASCII
+		return db.Where("first_name LIKE ? OR last_name LIKE ? OR email LIKE ? OR username LIKE ?",
+			searchPattern, searchPattern, searchPattern, searchPattern)
+	}
+	return db.Where("first_name ILIKE ? OR last_name ILIKE ? OR email ILIKE ? OR username ILIKE ?",
+		searchPattern, searchPattern, searchPattern, searchPattern)
+}
+
+// mongoUserSearch builds the MongoDB equivalent of applyUserSearch
+func mongoUserSearch(search string) bson.M {
+	if search == "" {
+		return bson.M{}
+	}
+	return bson.M{
+		"$or": []bson.M{
+			{"first_name": bson.M{"$regex": search, "$options": "i"}},
+			{"last_name": bson.M{"$regex": search, "$options": "i"}},
+			{"email": bson.M{"$regex": search, "$options": "i"}},
+			{"username": bson.M{"$regex": search, "$options": "i"}},
+		},
+	}
+}
+
+// notDeleted excludes soft-deleted MongoDB users, whose deleted_at is set; GORM does this for PostgreSQL
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// applyFilters adds each filter to a GORM query as an AND condition
+func applyFilters(db *gorm.DB, filters []utils.Filter) *gorm.DB {
+	for _, filter := range filters {
+		if name, ok := utils.MetadataAttribute(filter.Column); ok {
+			db = applyMetadataFilter(db, name, filter)
+			continue
+		}
+		db = db.Where(filter.Column+" "+sqlOperators[filter.Operator], filter.Value)
+	}
+	return db
+}
+
+// applyMetadataFilter compares a custom attribute in the metadata column, cast to the type of the filter
+// value. Users without the attribute match ne, as they do on MongoDB. The name comes from the metadata
+// schema, whose names are plain, so it is safe to interpolate.
+func applyMetadataFilter(db *gorm.DB, name string, filter utils.Filter) *gorm.DB {
+	value := filter.Value
+	if items, ok := value.([]interface{}); ok && len(items) > 0 {
+		value = items[0]
+	}
+
+	sqlite := db.Dialector.Name() == "sqlite"
+	var expr string
+	switch {
+	case sqlite:
+		// json_extract returns JSON numbers and booleans as SQLite numbers, which compare with the values
+		expr = "json_extract(metadata, '$." + name + "')"
+	case isBool(value):
+		expr = "(metadata->>'" + name + "')::boolean"
+	case isNumber(value):
+		expr = "(metadata->>'" + name + "')::numeric"
+	default:
+		expr = "(metadata->>'" + name + "')"
+	}
+
+	operator := sqlOperators[filter.Operator]
+	if filter.Operator == utils.FilterNe {
+		operator = "IS DISTINCT FROM ?"
+		if sqlite {
+			operator = "IS NOT ?"
+		}
+	}
+	return db.Where(expr+" "+operator, filter.Value)
+}
+
+// isBool reports whether a filter value is a boolean
+func isBool(value interface{}) bool {
+	_, ok := value.(bool)
+	return ok
+}
+
+// isNumber reports whether a filter value is a number
+func isNumber(value interface{}) bool {
+	_, ok := value.(float64)
+	return ok
+}
+
+// applyMongoFilters merges each filter into a MongoDB filter document; conditions on the same field are combined
+func applyMongoFilters(filter bson.M, filters []utils.Filter) bson.M {
+	for _, f := range filters {
+		conditions, ok := filter[f.Column].(bson.M)
+		if !ok {
+			conditions = bson.M{}
+			filter[f.Column] = conditions
+		}
+		conditions[mongoOperators[f.Operator]] = f.Value
+	}
+	return filter
+}
+
+// applySort adds ORDER BY columns to a GORM query in the given order
+func applySort(db *gorm.DB, sort []utils.SortField) *gorm.DB {
+	for _, field := range sort {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: field.Column}, Desc: field.Descending})
+	}
+	return db
+}
+
+// mongoSort builds an ordered MongoDB sort document
+func mongoSort(sort []utils.SortField) bson.D {
+	document := make(bson.D, 0, len(sort))
+	for _, field := range sort {
+		direction := 1
+		if field.Descending {
+			direction = -1
+		}
+		document = append(document, bson.E{Key: field.Column, Value: direction})
+	}
+	return document
+}
+
+// keysetOrder extends a sort order with the primary key so every row has a unique position
+func keysetOrder(sort []utils.SortField, idColumn string) []utils.SortField {
+	return append(append([]utils.SortField{}, sort...), utils.SortField{Column: idColumn})
+}
+
+// reverseOrder flips every direction, used to walk backwards from a cursor
+func reverseOrder(order []utils.SortField) []utils.SortField {
+	reversed := make([]utils.SortField, len(order))
+	for i, field := range order {
+		reversed[i] = utils.SortField{Column: field.Column, Descending: !field.Descending}
+	}
+	return reversed
+}
+
+// keysetAfter reports whether rows beyond the cursor compare greater than it in this column
+func keysetAfter(field utils.SortField, before bool) bool {
+	return field.Descending == before
+}
+
+// applyKeyset restricts a GORM query to rows after (or before) the cursor position:
+// (a > ?) OR (a = ? AND b > ?) OR ... with each comparison following its column's direction
+func applyKeyset(db *gorm.DB, order []utils.SortField, values []interface{}, before bool) *gorm.DB {
+	var branches []string
+	var args []interface{}
+	for i, field := range order {
+		var conditions []string
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, order[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+
+		operator := "<"
+		if keysetAfter(field, before) {
+			operator = ">"
+		}
+		conditions = append(conditions, field.Column+" "+operator+" ?")
+		args = append(args, values[i])
+		branches = append(branches, "("+strings.Join(conditions, " AND ")+")")
+	}
+	return db.Where(strings.Join(branches, " OR "), args...)
+}
+
+// mongoKeyset builds the MongoDB equivalent of applyKeyset
+func mongoKeyset(order []utils.SortField, values []interface{}, before bool) bson.M {
+	branches := make([]bson.M, 0, len(order))
+	for i, field := range order {
+		branch := bson.M{}
+		for j := 0; j < i; j++ {
+			branch[order[j].Column] = values[j]
+		}
+
+		operator := "$lt"
+		if keysetAfter(field, before) {
+			operator = "$gt"
+		}
+		branch[field.Column] = bson.M{operator: values[i]}
+		branches = append(branches, branch)
+	}
+	return bson.M{"$or": branches}
+}
@@ -0,0 +1,90 @@
+// Package services holds the business logic behind the HTTP handlers: account registration and login, and
+// reading and changing users, on PostgreSQL or MongoDB. Services take plain values and return sentinel
+// errors, so they can be used and tested without gin; handlers map the errors to HTTP responses.
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"go-backend-template/database"
+	"go-backend-template/utils"
+)
+
+var (
+	// ErrUserNotFound is returned for a user that does not exist or is not in the expected state
+	ErrUserNotFound = errors.New("user not found")
+	// ErrInvalidUserID is returned for an ID that is not valid for the active backend
+	ErrInvalidUserID = errors.New("invalid user ID format")
+	// ErrDuplicateEmail is returned when another user already has the email
+	ErrDuplicateEmail = errors.New("email already exists")
+	// ErrDuplicateUsername is returned when another user already has the username
+	ErrDuplicateUsername = errors.New("username already exists")
+	// ErrInvalidCredentials is returned, wrapped in a *LoginError, for a failed login
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrTokenGeneration is returned when signing a token fails
+	ErrTokenGeneration = errors.New("token generation failed")
+	// ErrPreconditionFailed is returned when the profile changed since the version the client sent
+	ErrPreconditionFailed = errors.New("the profile was modified since it was last retrieved")
+	// ErrLastSuperadmin is returned when a role change, deactivation, or deletion would leave no active
+	// superadmin
+	ErrLastSuperadmin = errors.New("the last superadmin cannot be demoted, deactivated, or deleted")
+	// ErrSuperadminTarget is returned when an admin who is not a superadmin deactivates or deletes a
+	// superadmin
+	ErrSuperadminTarget = errors.New("only superadmins can deactivate or delete a superadmin")
+	// ErrBreachedPassword is returned when a new password appears in a known data breach
+	ErrBreachedPassword = errors.New("password appears in a known data breach")
+	// ErrUndeliverableEmail is returned when the domain of a new email address does not accept mail
+	ErrUndeliverableEmail = errors.New("email domain does not accept mail")
+	// ErrInvalidRefreshToken is returned for a refresh token that cannot be exchanged
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+)
+
+// errNoDatabase is returned when neither database is configured
+var errNoDatabase = errors.New("no database is configured")
+
+// LoginError describes a failed login for the security log; it matches ErrInvalidCredentials
+type LoginError struct {
+	// UserID is set when the email belongs to a user
+	UserID string
+	// Reason is unknown_email, invalid_password, or inactive for a deactivated user
+	Reason string
+}
+
+// Error implements error
+func (e *LoginError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalidCredentials, e.Reason)
+}
+
+// Unwrap makes errors.Is(err, ErrInvalidCredentials) hold
+func (e *LoginError) Unwrap() error {
+	return ErrInvalidCredentials
+}
+
+// duplicateUserError converts a unique index violation on the users table or collection to
+// ErrDuplicateEmail or ErrDuplicateUsername and returns any other error unchanged
+func duplicateUserError(err error) error {
+	index, ok := database.DuplicateKeyIndex(err)
+	if !ok {
+		return err
+	}
+	if strings.Contains(index, "username") {
+		return ErrDuplicateUsername
+	}
+	return ErrDuplicateEmail
+}
+
+// mongoProjection builds a find projection for a sparse fieldset plus any extra columns; nil selects every field
+func mongoProjection(fields utils.FieldSet, extra ...string) interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	projection := bson.M{}
+	for _, column := range fields.Columns("_id", extra...) {
+		projection[column] = 1
+	}
+	return projection
+}
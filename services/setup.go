@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// ErrSetupUnavailable is returned when setup is attempted with a wrong token, after the token was used, or
+// once the database has users
+var ErrSetupUnavailable = errors.New("setup is not available")
+
+// SetupService creates the first superadmin of an empty database, so a new deployment can reach the admin
+// routes: from configured credentials at startup, or through POST /setup with a one-time token that is
+// logged at startup. Each instance has its own token; the first setup to succeed completes it for all.
+type SetupService struct {
+	admin  AdminService
+	logger utils.Logger
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewSetupService creates a setup service creating accounts through admin
+func NewSetupService(admin AdminService, logger utils.Logger) *SetupService {
+	return &SetupService{admin: admin, logger: logger}
+}
+
+// Bootstrap runs at startup and does nothing when any user exists. On an empty database it creates a
+// superadmin from req when its email is set; otherwise, when token is true, it generates the setup token
+// and logs it. A superadmin created meanwhile by another instance is not an error.
+func (s *SetupService) Bootstrap(ctx context.Context, req models.RegisterRequest, token bool) error {
+	hasUsers, err := s.admin.HasUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check for users: %w", err)
+	}
+	if hasUsers {
+		return nil
+	}
+
+	if req.Email != "" {
+		if !utils.IsValidEmail(req.Email) || !utils.IsValidUsername(req.Username) || !utils.IsStrongPassword(req.Password) {
+			return errors.New("the bootstrap superadmin needs a valid email and username and a strong password")
+		}
+		user, err := s.admin.CreateUser(ctx, req, "superadmin")
+		if errors.Is(err, ErrDuplicateEmail) || errors.Is(err, ErrDuplicateUsername) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create the bootstrap superadmin: %w", err)
+		}
+		s.logger.Info("Created the bootstrap superadmin", "user_id", user.ID, "email", user.Email)
+		return nil
+	}
+	if !token {
+		s.logger.Warn("The database has no users; create a superadmin with the CLI or set BOOTSTRAP_ADMIN_EMAIL")
+		return nil
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return fmt.Errorf("failed to generate the setup token: %w", err)
+	}
+	s.mu.Lock()
+	s.token = hex.EncodeToString(raw)
+	s.mu.Unlock()
+	s.logger.Warn("The database has no users; create the first superadmin with POST /api/v1/setup and this one-time token",
+		"setup_token", s.token)
+	return nil
+}
+
+// Pending reports whether a setup token is waiting to be used
+func (s *SetupService) Pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token != ""
+}
+
+// Complete creates the superadmin when token is the setup token and the database still has no users, and
+// discards the token. Failures other than ErrSetupUnavailable, such as a duplicate email, keep the token.
+func (s *SetupService) Complete(ctx context.Context, token string, req models.RegisterRequest) (models.UserInfo, error) {
+	// Held throughout so concurrent setups on this instance cannot both create a superadmin
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return models.UserInfo{}, ErrSetupUnavailable
+	}
+	hasUsers, err := s.admin.HasUsers(ctx)
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	if hasUsers {
+		s.token = ""
+		return models.UserInfo{}, ErrSetupUnavailable
+	}
+
+	user, err := s.admin.CreateUser(ctx, req, "superadmin")
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	s.token = ""
+	return user, nil
+}
@@ -0,0 +1,260 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+	"go-backend-template/services"
+	"go-backend-template/testutil"
+	"go-backend-template/utils"
+)
+
+// sortUsers are users that tie on every sortable column but email and username, in some combination, so
+// each order depends on the later columns and finally on the ID
+func sortUsers() []models.User {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstNames := []string{"Ada", "Grace", "Alan"}
+	lastNames := []string{"Lovelace", "Hopper"}
+	var users []models.User
+	for i := 1; i <= 12; i++ {
+		builder := testutil.NewUser().
+			WithEmail(fmt.Sprintf("user%02d@example.com", 13-i)).
+			WithUsername(fmt.Sprintf("user%02d", (i*5)%13)).
+			WithName(firstNames[i%3], lastNames[i%2]).
+			CreatedAt(created.Add(time.Duration(i/3) * time.Minute))
+		if i%4 == 0 {
+			builder = builder.Admin()
+		}
+		if i%3 == 0 {
+			builder = builder.Inactive()
+		}
+		user := builder.Model()
+		user.ID = strconv.Itoa(i)
+		user.UpdatedAt = created.Add(time.Duration(i%2) * time.Hour)
+		users = append(users, user)
+	}
+	return users
+}
+
+// sortQueries are single and combined sort orders over every sortable column, both directions
+func sortQueries() [][]utils.SortField {
+	var orders [][]utils.SortField
+	for _, column := range utils.UserSortFields {
+		orders = append(orders,
+			[]utils.SortField{{Column: column}},
+			[]utils.SortField{{Column: column, Descending: true}})
+	}
+	return append(orders,
+		utils.DefaultUserSort,
+		[]utils.SortField{{Column: "role"}, {Column: "is_active", Descending: true}},
+		[]utils.SortField{{Column: "last_name"}, {Column: "first_name", Descending: true}, {Column: "created_at"}},
+		[]utils.SortField{{Column: "updated_at", Descending: true}, {Column: "role", Descending: true}},
+	)
+}
+
+func orderName(order []utils.SortField) string {
+	names := make([]string, len(order))
+	for i, field := range order {
+		names[i] = field.Column
+		if field.Descending {
+			names[i] += ":desc"
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// sqliteUsers returns the PostgreSQL user service over an in-memory SQLite database holding users, so the
+// query builders run against a real SQL engine
+func sqliteUsers(t *testing.T, users []models.User) services.UserService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db: %v", err)
+	}
+	// Every connection to :memory: would be a separate database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Create(slices.Clone(users)).Error; err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	// GORM inserts the is_active default in place of false
+	for _, user := range users {
+		if !user.IsActive {
+			if err := db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("is_active", false).Error; err != nil {
+				t.Fatalf("deactivate: %v", err)
+			}
+		}
+	}
+	return services.NewUserService(nil, &database.PostgresDB{DB: db}, nil, nil)
+}
+
+// mongoUser stores user as the MongoDB service does, with an ObjectID that sorts like the numeric ID, as
+// ObjectIDs follow creation
+func mongoUser(user models.User) bson.Raw {
+	id, _ := strconv.ParseUint(user.ID, 10, 64)
+	var objectID primitive.ObjectID
+	binary.BigEndian.PutUint64(objectID[4:], id)
+	document, _ := bson.Marshal(models.UserMongo{
+		ID:        objectID,
+		Email:     user.Email,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Role:      user.Role,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+	return document
+}
+
+// compareBSON orders two values of the same BSON type as MongoDB does
+func compareBSON(a, b bson.RawValue) int {
+	switch a.Type {
+	case bsontype.String:
+		return strings.Compare(a.StringValue(), b.StringValue())
+	case bsontype.Boolean:
+		x, y := a.Boolean(), b.Boolean()
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		default:
+			return 1
+		}
+	case bsontype.DateTime:
+		x, y := a.DateTime(), b.DateTime()
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case bsontype.ObjectID:
+		x, y := a.ObjectID(), b.ObjectID()
+		return bytes.Compare(x[:], y[:])
+	}
+	return 0
+}
+
+// mongoOrder applies a sort document the way MongoDB does and returns the IDs in that order
+func mongoOrder(t *testing.T, users []models.User, document bson.D) []string {
+	t.Helper()
+	documents := make([]bson.Raw, len(users))
+	for i, user := range users {
+		documents[i] = mongoUser(user)
+	}
+	sort.SliceStable(documents, func(i, j int) bool {
+		for _, key := range document {
+			c := compareBSON(documents[i].Lookup(key.Key), documents[j].Lookup(key.Key))
+			if key.Value == -1 {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	ids := make([]string, len(documents))
+	for i, document := range documents {
+		objectID := document.Lookup("_id").ObjectID()
+		ids[i] = strconv.FormatUint(binary.BigEndian.Uint64(objectID[4:]), 10)
+	}
+	return ids
+}
+
+// listAll pages through the users 5 at a time and returns the IDs in order and the totals reported
+func listAll(t *testing.T, service services.UserService, order []utils.SortField) ([]string, []int64) {
+	t.Helper()
+	var ids []string
+	var totals []int64
+	for page := 1; ; page++ {
+		users, total, err := service.ListUsers(context.Background(), services.ListUsersQuery{Page: page, PageSize: 5, Sort: order})
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		totals = append(totals, total)
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+		if len(users) < 5 {
+			return ids, totals
+		}
+	}
+}
+
+func TestKeysetOrder(t *testing.T) {
+	sort := []utils.SortField{{Column: "role", Descending: true}}
+	order := services.KeysetOrder(sort, "_id")
+
+	want := []utils.SortField{{Column: "role", Descending: true}, {Column: "_id"}}
+	if !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if len(sort) != 1 {
+		t.Errorf("the requested sort was changed to %v", sort)
+	}
+	if document := services.MongoSort(order); !slices.Equal(document, bson.D{{Key: "role", Value: -1}, {Key: "_id", Value: 1}}) {
+		t.Errorf("sort document = %v", document)
+	}
+}
+
+// TestListUsersSortParity checks that the PostgreSQL query builders, the MongoDB sort document, and the
+// testutil fake the handler tests run against order and page the users alike, ties included
+func TestListUsersSortParity(t *testing.T) {
+	users := sortUsers()
+	fake := testutil.NewUserRepository(nil)
+	for _, user := range users {
+		fake.Add(user)
+	}
+	postgres := sqliteUsers(t, users)
+
+	for _, order := range sortQueries() {
+		t.Run(orderName(order), func(t *testing.T) {
+			want, wantTotals := listAll(t, fake, order)
+			if len(want) != len(users) {
+				t.Fatalf("fake listed %d users, want %d", len(want), len(users))
+			}
+
+			got, totals := listAll(t, postgres, order)
+			if !slices.Equal(got, want) {
+				t.Errorf("PostgreSQL order = %v, fake %v", got, want)
+			}
+			if !slices.Equal(totals, wantTotals) {
+				t.Errorf("PostgreSQL totals = %v, fake %v", totals, wantTotals)
+			}
+
+			if got := mongoOrder(t, users, services.MongoSort(services.KeysetOrder(order, "_id"))); !slices.Equal(got, want) {
+				t.Errorf("MongoDB order = %v, fake %v", got, want)
+			}
+		})
+	}
+}
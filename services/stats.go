@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// StatsService computes the figures of the admin dashboard
+type StatsService interface {
+	// Stats returns the user totals, the signups of each of the last days, the active users, and the
+	// database sizes. Logins are left zero; they are counted by the security event logger.
+	Stats(ctx context.Context, days int) (*models.AdminStats, error)
+}
+
+// statsService implements StatsService with one grouped query or aggregation per figure, caching the
+// result of each number of days for cacheTTL
+type statsService struct {
+	mongoDB    *database.MongoDB
+	postgresDB *database.PostgresDB
+	cacheTTL   time.Duration
+
+	mu    sync.Mutex
+	cache map[int]*models.AdminStats
+}
+
+// NewStatsService creates a stats service; PostgreSQL is used when both databases are configured, and a
+// zero cacheTTL computes the figures on every call
+func NewStatsService(mongoDB *database.MongoDB, postgresDB *database.PostgresDB, cacheTTL time.Duration) StatsService {
+	return &statsService{
+		mongoDB:    mongoDB,
+		postgresDB: postgresDB,
+		cacheTTL:   cacheTTL,
+		cache:      make(map[int]*models.AdminStats),
+	}
+}
+
+// Stats returns a copy of the cached figures while they are fresh
+func (s *statsService) Stats(ctx context.Context, days int) (*models.AdminStats, error) {
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	cached, ok := s.cache[days]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.GeneratedAt) < s.cacheTTL {
+		return copyStats(cached), nil
+	}
+
+	var stats *models.AdminStats
+	var err error
+	switch {
+	case s.postgresDB != nil:
+		stats, err = s.postgresStats(ctx, days, now)
+	case s.mongoDB != nil:
+		stats, err = s.mongoStats(ctx, days, now)
+	default:
+		return nil, errNoDatabase
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stats.GeneratedAt = now
+	if s.cacheTTL > 0 {
+		s.mu.Lock()
+		s.cache[days] = stats
+		s.mu.Unlock()
+	}
+	return copyStats(stats), nil
+}
+
+// postgresStats counts the users in one pass over the table and groups the roles and signups in SQL
+func (s *statsService) postgresStats(ctx context.Context, days int, now time.Time) (*models.AdminStats, error) {
+	db := s.postgresDB.Replica().WithContext(ctx)
+	stats := &models.AdminStats{}
+
+	var totals struct {
+		Total, Active, Deleted, Last24h, Last7d, Last30d int64
+	}
+	err := db.Unscoped().Model(&models.User{}).Select(`
+		COALESCE(SUM(CASE WHEN deleted_at IS NULL THEN 1 ELSE 0 END), 0) AS total,
+		COALESCE(SUM(CASE WHEN deleted_at IS NULL AND is_active THEN 1 ELSE 0 END), 0) AS active,
+		COALESCE(SUM(CASE WHEN deleted_at IS NOT NULL THEN 1 ELSE 0 END), 0) AS deleted,
+		COALESCE(SUM(CASE WHEN deleted_at IS NULL AND last_login_at >= ? THEN 1 ELSE 0 END), 0) AS last24h,
+		COALESCE(SUM(CASE WHEN deleted_at IS NULL AND last_login_at >= ? THEN 1 ELSE 0 END), 0) AS last7d,
+		COALESCE(SUM(CASE WHEN deleted_at IS NULL AND last_login_at >= ? THEN 1 ELSE 0 END), 0) AS last30d`,
+		now.Add(-24*time.Hour), now.AddDate(0, 0, -7), now.AddDate(0, 0, -30),
+	).Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.Users = models.UserStats{
+		Total:    totals.Total,
+		Active:   totals.Active,
+		Inactive: totals.Total - totals.Active,
+		Deleted:  totals.Deleted,
+	}
+	stats.ActiveUsers = models.ActiveUsers{Last24h: totals.Last24h, Last7d: totals.Last7d, Last30d: totals.Last30d}
+
+	stats.Users.ByRole = []models.RoleCount{}
+	err = db.Model(&models.User{}).Select("COALESCE(role, '') AS role, COUNT(*) AS count").
+		Group("role").Order("role").Scan(&stats.Users.ByRole).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Days are UTC; SQLite stores times as text that its date functions convert to UTC
+	day := "to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+	if s.postgresDB.IsSQLite() {
+		day = "strftime('%Y-%m-%d', created_at)"
+	}
+	var signups []models.DailyCount
+	err = db.Unscoped().Model(&models.User{}).Select(day+" AS date, COUNT(*) AS count").
+		Where("created_at >= ?", firstDay(now, days)).Group("date").Scan(&signups).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.Signups = fillDays(signups, now, days)
+
+	size, err := s.postgresSize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.Databases = []models.DatabaseSize{size}
+	return stats, nil
+}
+
+// postgresSize reports the size of the database and of its tables; SQLite reports the file size only
+func (s *statsService) postgresSize(ctx context.Context) (models.DatabaseSize, error) {
+	db := s.postgresDB.WithContext(ctx)
+
+	if s.postgresDB.IsSQLite() {
+		size := models.DatabaseSize{Database: "sqlite"}
+		err := db.Raw("SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()").Scan(&size.SizeBytes).Error
+		return size, err
+	}
+
+	size := models.DatabaseSize{Database: "postgresql"}
+	if err := db.Raw("SELECT pg_database_size(current_database())").Scan(&size.SizeBytes).Error; err != nil {
+		return size, err
+	}
+	err := db.Raw(`SELECT relname AS name, pg_total_relation_size(relid) AS size_bytes
+		FROM pg_catalog.pg_statio_user_tables ORDER BY size_bytes DESC, name`).Scan(&size.Tables).Error
+	return size, err
+}
+
+// mongoStats counts the users, their roles, and the signups in one aggregation with a facet per figure
+func (s *statsService) mongoStats(ctx context.Context, days int, now time.Time) (*models.AdminStats, error) {
+	deleted := bson.M{"$gt": bson.A{"$deleted_at", nil}}
+	countIf := func(conditions ...interface{}) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$and": conditions}, 1, 0}}}
+	}
+	notDeletedExpr := bson.M{"$not": bson.A{deleted}}
+	loggedInSince := func(since time.Time) bson.M {
+		return bson.M{"$gte": bson.A{"$last_login_at", since}}
+	}
+
+	pipeline := bson.A{
+		bson.M{"$facet": bson.M{
+			"totals": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":      nil,
+					"total":    countIf(notDeletedExpr),
+					"active":   countIf(notDeletedExpr, "$is_active"),
+					"deleted":  countIf(deleted),
+					"last_24h": countIf(notDeletedExpr, loggedInSince(now.Add(-24*time.Hour))),
+					"last_7d":  countIf(notDeletedExpr, loggedInSince(now.AddDate(0, 0, -7))),
+					"last_30d": countIf(notDeletedExpr, loggedInSince(now.AddDate(0, 0, -30))),
+				}},
+			},
+			"roles": bson.A{
+				bson.M{"$match": notDeleted(bson.M{})},
+				bson.M{"$group": bson.M{"_id": bson.M{"$ifNull": bson.A{"$role", ""}}, "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+			"signups": bson.A{
+				bson.M{"$match": bson.M{"created_at": bson.M{"$gte": firstDay(now, days)}}},
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$created_at"}},
+					"count": bson.M{"$sum": 1},
+				}},
+			},
+		}},
+	}
+
+	cursor, err := s.mongoDB.Collection("users").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		Totals []struct {
+			Total   int64 `bson:"total"`
+			Active  int64 `bson:"active"`
+			Deleted int64 `bson:"deleted"`
+			Last24h int64 `bson:"last_24h"`
+			Last7d  int64 `bson:"last_7d"`
+			Last30d int64 `bson:"last_30d"`
+		} `bson:"totals"`
+		Roles []struct {
+			Role  string `bson:"_id"`
+			Count int64  `bson:"count"`
+		} `bson:"roles"`
+		Signups []struct {
+			Date  string `bson:"_id"`
+			Count int64  `bson:"count"`
+		} `bson:"signups"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	stats := &models.AdminStats{Users: models.UserStats{ByRole: []models.RoleCount{}}}
+	var signups []models.DailyCount
+	if len(results) > 0 {
+		result := results[0]
+		if len(result.Totals) > 0 {
+			totals := result.Totals[0]
+			stats.Users.Total = totals.Total
+			stats.Users.Active = totals.Active
+			stats.Users.Inactive = totals.Total - totals.Active
+			stats.Users.Deleted = totals.Deleted
+			stats.ActiveUsers = models.ActiveUsers{Last24h: totals.Last24h, Last7d: totals.Last7d, Last30d: totals.Last30d}
+		}
+		for _, role := range result.Roles {
+			stats.Users.ByRole = append(stats.Users.ByRole, models.RoleCount{Role: role.Role, Count: role.Count})
+		}
+		for _, signup := range result.Signups {
+			signups = append(signups, models.DailyCount{Date: signup.Date, Count: signup.Count})
+		}
+	}
+	stats.Signups = fillDays(signups, now, days)
+
+	size, err := s.mongoSize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.Databases = []models.DatabaseSize{size}
+	return stats, nil
+}
+
+// mongoSize reports the storage and index size of the database and of each collection
+func (s *statsService) mongoSize(ctx context.Context) (models.DatabaseSize, error) {
+	size := models.DatabaseSize{Database: "mongodb"}
+
+	// Sizes are doubles or integers depending on the server version
+	var dbStats struct {
+		StorageSize float64 `bson:"storageSize"`
+		IndexSize   float64 `bson:"indexSize"`
+	}
+	if err := s.mongoDB.Database.RunCommand(ctx, bson.D{{Key: "dbStats", Value: 1}}).Decode(&dbStats); err != nil {
+		return size, err
+	}
+	size.SizeBytes = int64(dbStats.StorageSize + dbStats.IndexSize)
+
+	names, err := s.mongoDB.Database.ListCollectionNames(ctx, bson.M{"type": "collection"})
+	if err != nil {
+		return size, err
+	}
+	for _, name := range names {
+		var collStats struct {
+			StorageSize    float64 `bson:"storageSize"`
+			TotalIndexSize float64 `bson:"totalIndexSize"`
+		}
+		if err := s.mongoDB.Database.RunCommand(ctx, bson.D{{Key: "collStats", Value: name}}).Decode(&collStats); err != nil {
+			return size, err
+		}
+		size.Tables = append(size.Tables, models.TableSize{Name: name, SizeBytes: int64(collStats.StorageSize + collStats.TotalIndexSize)})
+	}
+	sort.Slice(size.Tables, func(i, j int) bool {
+		if size.Tables[i].SizeBytes != size.Tables[j].SizeBytes {
+			return size.Tables[i].SizeBytes > size.Tables[j].SizeBytes
+		}
+		return size.Tables[i].Name < size.Tables[j].Name
+	})
+	return size, nil
+}
+
+// firstDay returns the start of the first of the last days UTC days, today included
+func firstDay(now time.Time, days int) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return today.AddDate(0, 0, 1-days)
+}
+
+// fillDays returns a count for each of the last days days, oldest first, with zero for days missing from
+// counts
+func fillDays(counts []models.DailyCount, now time.Time, days int) []models.DailyCount {
+	byDate := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		byDate[count.Date] += count.Count
+	}
+	filled := make([]models.DailyCount, 0, days)
+	for day := firstDay(now, days); len(filled) < days; day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		filled = append(filled, models.DailyCount{Date: date, Count: byDate[date]})
+	}
+	return filled
+}
+
+// copyStats copies the slices of cached figures, so callers can change the result
+func copyStats(stats *models.AdminStats) *models.AdminStats {
+	copied := *stats
+	copied.Users.ByRole = slices.Clone(stats.Users.ByRole)
+	copied.Signups = slices.Clone(stats.Signups)
+	copied.Databases = slices.Clone(stats.Databases)
+	for i := range copied.Databases {
+		copied.Databases[i].Tables = slices.Clone(copied.Databases[i].Tables)
+	}
+	return &copied
+}
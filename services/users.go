@@ -0,0 +1,1077 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend-template/database"
+	"go-backend-template/hooks"
+	"go-backend-template/models"
+	"go-backend-template/realtime"
+	"go-backend-template/utils"
+)
+
+// ListUsersQuery selects a page of users; the handler validates each part against the user allowlists
+type ListUsersQuery struct {
+	Page     int
+	PageSize int
+	Search   string
+	Fields   utils.FieldSet
+	Filters  []utils.Filter
+	Sort     []utils.SortField
+	// Rank orders full-text matches by relevance before Sort; ListUsersByCursor ignores it
+	Rank bool
+	// Cursor selects keyset pagination from the boundary row when set; Page is then ignored
+	Cursor *utils.Cursor
+}
+
+// UserService reads and changes user accounts
+type UserService interface {
+	// GetProfile returns the user, with only fields loaded when fields is not empty
+	GetProfile(ctx context.Context, userID string, fields utils.FieldSet) (models.UserInfo, error)
+	// UpdateProfile changes the non-empty fields of req. A non-empty ifMatch must match the ETag of the
+	// current profile, and the update fails with ErrPreconditionFailed if the profile changes meanwhile. A
+	// hook refusing the update is a *hooks.Rejection.
+	UpdateProfile(ctx context.Context, userID string, req models.UpdateUserRequest, ifMatch string) (models.UserInfo, error)
+	// ListUsers returns a page of users and the number of matches
+	ListUsers(ctx context.Context, query ListUsersQuery) ([]models.UserInfo, int64, error)
+	// ListUsersByCursor returns up to PageSize+1 users after (or before) query.Cursor, in the order they
+	// are read, and the number of matches; the extra row tells whether another page exists
+	ListUsersByCursor(ctx context.Context, query ListUsersQuery) ([]models.UserInfo, int64, error)
+	// EachUser calls fn with every user matching query, in its order, reading the users as fn consumes
+	// them; Page, PageSize, and Cursor are ignored. An error from fn stops the iteration and is returned.
+	EachUser(ctx context.Context, query ListUsersQuery, fn func(models.UserInfo) error) error
+	// ChangeRole gives the user the role and returns the user and the role they had before. Demoting the
+	// only active superadmin fails with ErrLastSuperadmin.
+	ChangeRole(ctx context.Context, userID, role string) (models.UserInfo, string, error)
+	// SetActive activates or deactivates the user and returns the user. Inactive users cannot log in.
+	// Deactivating a superadmin fails with ErrSuperadminTarget unless actorRole, the role of the admin
+	// acting, is superadmin, and deactivating the only active superadmin fails with ErrLastSuperadmin.
+	SetActive(ctx context.Context, userID string, active bool, actorRole string) (models.UserInfo, error)
+	// DeleteUser soft-deletes the user; superadmins are protected as by SetActive
+	DeleteUser(ctx context.Context, userID, actorRole string) error
+	// RestoreUser undoes a soft delete and returns the restored user
+	RestoreUser(ctx context.Context, userID string) (models.UserInfo, error)
+}
+
+// userService implements UserService on the configured database
+type userService struct {
+	mongoDB    *database.MongoDB
+	postgresDB *database.PostgresDB
+	events     *realtime.Hub
+	hooks      *hooks.Registry
+}
+
+// NewUserService creates a user service; profile changes are pushed to the user's connections through
+// events, and run the profile hooks of registry; both may be nil
+func NewUserService(mongoDB *database.MongoDB, postgresDB *database.PostgresDB, events *realtime.Hub, registry *hooks.Registry) UserService {
+	return &userService{
+		mongoDB:    mongoDB,
+		postgresDB: postgresDB,
+		events:     events,
+		hooks:      registry,
+	}
+}
+
+// postgresUserID checks that userID has the form of the database's user IDs
+func postgresUserID(db *database.PostgresDB, userID string) (string, error) {
+	if !db.ValidUserID(userID) {
+		return "", ErrInvalidUserID
+	}
+	return userID, nil
+}
+
+// mongoUserID parses a MongoDB user ID
+func mongoUserID(userID string) (primitive.ObjectID, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidUserID
+	}
+	return objectID, nil
+}
+
+// GetProfile reads the user from a replica when one is configured
+func (s *userService) GetProfile(ctx context.Context, userID string, fields utils.FieldSet) (models.UserInfo, error) {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		id, err := postgresUserID(s.postgresDB, userID)
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+
+		db := s.postgresDB.Replica().WithContext(ctx)
+		if len(fields) > 0 {
+			db = db.Select(fields.Columns("id"))
+		}
+		var user models.User
+		if err := db.First(&user, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.UserInfo{}, ErrUserNotFound
+			}
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		objectID, err := mongoUserID(userID)
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+
+		var user models.UserMongo
+		findOptions := options.FindOne().SetProjection(mongoProjection(fields))
+		err = s.mongoDB.Collection("users").FindOne(ctx, notDeleted(bson.M{"_id": objectID}), findOptions).Decode(&user)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return models.UserInfo{}, ErrUserNotFound
+			}
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	return models.UserInfo{}, errNoDatabase
+}
+
+// matchesETag reports whether ifMatch is empty or matches the ETag of current
+func matchesETag(ifMatch string, current models.UserInfo) bool {
+	if ifMatch == "" {
+		return true
+	}
+	etag, err := utils.ETag(current)
+	return err == nil && utils.ETagMatches(ifMatch, etag)
+}
+
+// profileAddress returns the address to store for an update, nil when every field of it is empty
+func profileAddress(address *models.Address) *models.Address {
+	if *address == (models.Address{}) {
+		return nil
+	}
+	return address
+}
+
+// mergeMetadata applies changes to the custom attributes of a user, removing those set to null; it
+// returns nil when none are left
+func mergeMetadata(current, changes models.Metadata) models.Metadata {
+	merged := make(models.Metadata, len(current)+len(changes))
+	for name, value := range current {
+		merged[name] = value
+	}
+	for name, value := range changes {
+		if value == nil {
+			delete(merged, name)
+		} else {
+			merged[name] = value
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// UpdateProfile updates conditionally on updated_at, so a concurrent write between read and update is
+// detected, and publishes the updated profile
+func (s *userService) UpdateProfile(ctx context.Context, userID string, req models.UpdateUserRequest, ifMatch string) (models.UserInfo, error) {
+	if err := s.hooks.RunBeforeProfileUpdate(ctx, userID, &req); err != nil {
+		return models.UserInfo{}, err
+	}
+	var userInfo models.UserInfo
+
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		id, err := postgresUserID(s.postgresDB, userID)
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+
+		var user models.User
+		if err := s.postgresDB.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.UserInfo{}, ErrUserNotFound
+			}
+			return models.UserInfo{}, err
+		}
+		if !matchesETag(ifMatch, user.Info()) {
+			return models.UserInfo{}, ErrPreconditionFailed
+		}
+		previousUpdatedAt := user.UpdatedAt
+
+		if req.FirstName != "" {
+			user.FirstName = req.FirstName
+		}
+		if req.LastName != "" {
+			user.LastName = req.LastName
+		}
+		if req.Email != "" {
+			// A new address has not bounced
+			if req.Email != user.Email {
+				user.EmailStatus, user.EmailStatusAt = "", nil
+			}
+			user.Email = req.Email
+		}
+		if req.Locale != "" {
+			user.Locale = req.Locale
+		}
+		if req.AnalyticsOptOut != nil {
+			user.AnalyticsOptOut = *req.AnalyticsOptOut
+		}
+		if req.AvatarURL != nil {
+			user.AvatarURL = *req.AvatarURL
+		}
+		if req.Bio != nil {
+			user.Bio = *req.Bio
+		}
+		if req.Phone != nil {
+			user.Phone = *req.Phone
+		}
+		if req.Birthday != nil {
+			user.Birthday = *req.Birthday
+		}
+		if req.Address != nil {
+			user.Address = profileAddress(req.Address)
+		}
+		if req.Metadata != nil {
+			user.Metadata = mergeMetadata(user.Metadata, req.Metadata)
+		}
+		// PostgreSQL stores microseconds; truncate so the returned ETag matches later reads
+		user.UpdatedAt = time.Now().Truncate(time.Microsecond)
+
+		result := s.postgresDB.WithContext(ctx).Model(&user).
+			Where("updated_at = ?", previousUpdatedAt).
+			Select("first_name", "last_name", "email", "email_status", "email_status_at", "locale", "analytics_opt_out",
+				"avatar_url", "bio", "phone", "birthday", "address", "metadata", "updated_at").
+			Updates(&user)
+		if result.Error != nil {
+			return models.UserInfo{}, duplicateUserError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return models.UserInfo{}, ErrPreconditionFailed
+		}
+		userInfo = user.Info()
+	} else if s.mongoDB != nil {
+		// MongoDB implementation
+		collection := s.mongoDB.Collection("users")
+		objectID, err := mongoUserID(userID)
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+
+		var current models.UserMongo
+		if err := collection.FindOne(ctx, notDeleted(bson.M{"_id": objectID})).Decode(&current); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return models.UserInfo{}, ErrUserNotFound
+			}
+			return models.UserInfo{}, err
+		}
+		if !matchesETag(ifMatch, current.Info()) {
+			return models.UserInfo{}, ErrPreconditionFailed
+		}
+
+		set := bson.M{"updated_at": time.Now()}
+		if req.FirstName != "" {
+			set["first_name"] = req.FirstName
+		}
+		if req.LastName != "" {
+			set["last_name"] = req.LastName
+		}
+		if req.Email != "" {
+			set["email"] = req.Email
+			if req.Email != current.Email {
+				set["email_status"], set["email_status_at"] = "", nil
+			}
+		}
+		if req.Locale != "" {
+			set["locale"] = req.Locale
+		}
+		if req.AnalyticsOptOut != nil {
+			set["analytics_opt_out"] = *req.AnalyticsOptOut
+		}
+		if req.AvatarURL != nil {
+			set["avatar_url"] = *req.AvatarURL
+		}
+		if req.Bio != nil {
+			set["bio"] = *req.Bio
+		}
+		if req.Phone != nil {
+			set["phone"] = *req.Phone
+		}
+		if req.Birthday != nil {
+			set["birthday"] = *req.Birthday
+		}
+		if req.Address != nil {
+			set["address"] = profileAddress(req.Address)
+		}
+		if req.Metadata != nil {
+			set["metadata"] = mergeMetadata(current.Metadata, req.Metadata)
+		}
+
+		var user models.UserMongo
+		err = collection.FindOneAndUpdate(ctx,
+			notDeleted(bson.M{"_id": objectID, "updated_at": current.UpdatedAt}),
+			bson.M{"$set": set},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&user)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.UserInfo{}, ErrPreconditionFailed
+		}
+		if err != nil {
+			return models.UserInfo{}, duplicateUserError(err)
+		}
+		userInfo = user.Info()
+	} else {
+		return models.UserInfo{}, errNoDatabase
+	}
+
+	if s.events != nil {
+		s.events.Publish(userID, realtime.EventProfileUpdated, userInfo)
+	}
+	s.hooks.RunAfterProfileUpdate(ctx, userInfo)
+	return userInfo, nil
+}
+
+// ListUsers reads the page from a replica when one is configured
+func (s *userService) ListUsers(ctx context.Context, query ListUsersQuery) ([]models.UserInfo, int64, error) {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		db, total, err := searchPostgresUsers(s.postgresDB.Replica().WithContext(ctx).Model(&models.User{}), query, query.Rank)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// Ties are broken by ID, as on MongoDB, so pages neither repeat nor skip users
+		db = applySort(db.Offset((query.Page-1)*query.PageSize).Limit(query.PageSize), keysetOrder(query.Sort, "id"))
+		// Select only the requested columns; applied after Count so the count query is unaffected
+		if len(query.Fields) > 0 {
+			db = db.Select(query.Fields.Columns("id"))
+		}
+
+		var users []models.User
+		if err := db.Find(&users).Error; err != nil {
+			return nil, 0, err
+		}
+
+		userInfos := make([]models.UserInfo, len(users))
+		for i, user := range users {
+			userInfos[i] = user.Info()
+		}
+		return userInfos, total, nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		collection := s.mongoDB.Collection("users")
+		if query.Search == "" && len(query.Filters) == 0 {
+			return listAllMongoUsers(ctx, collection, query)
+		}
+
+		if text := mongoUserTextSearch(query.Search); text != nil {
+			userInfos, total, err := facetMongoUsers(ctx, collection, notDeleted(applyMongoFilters(text, query.Filters)), query, query.Rank)
+			if err != nil && !database.IsMissingIndex(err) {
+				return nil, 0, err
+			}
+			if err == nil && total > 0 {
+				return userInfos, total, nil
+			}
+		}
+		filter := notDeleted(applyMongoFilters(mongoUserSearch(query.Search), query.Filters))
+		return facetMongoUsers(ctx, collection, filter, query, false)
+	}
+
+	return nil, 0, errNoDatabase
+}
+
+// searchPostgresUsers applies the search and filters of query to db and counts the matches. The search is
+// full-text, ordered by relevance when rank is set; when full-text search cannot serve it or its words
+// match nobody, the substring match of applyUserSearch is used instead, which also finds text inside words.
+func searchPostgresUsers(db *gorm.DB, query ListUsersQuery, rank bool) (*gorm.DB, int64, error) {
+	var total int64
+	if text, ok := applyUserTextSearch(db, query.Search, rank); ok {
+		text = applyFilters(text, query.Filters)
+		if err := text.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		if total > 0 {
+			return text, total, nil
+		}
+	}
+
+	db = applyFilters(applyUserSearch(db, query.Search), query.Filters)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	return db, total, nil
+}
+
+// filterPostgresUsers applies the search and filters of query to db like searchPostgresUsers, without
+// counting the matches: whether the full-text search matches anybody is checked by reading a single row
+func filterPostgresUsers(db *gorm.DB, query ListUsersQuery, rank bool) (*gorm.DB, error) {
+	if text, ok := applyUserTextSearch(db, query.Search, rank); ok {
+		text = applyFilters(text, query.Filters)
+		var found []int
+		if err := text.Session(&gorm.Session{}).Select("1").Limit(1).Find(&found).Error; err != nil {
+			return nil, err
+		}
+		if len(found) > 0 {
+			return text, nil
+		}
+	}
+	return applyFilters(applyUserSearch(db, query.Search), query.Filters), nil
+}
+
+// facetMongoUsers fetches the page of the users matching filter and counts them in one aggregation, with a
+// facet for each. The text score leads the order when rank is set, and filter must then be a text search.
+func facetMongoUsers(ctx context.Context, collection *mongo.Collection, filter bson.M, query ListUsersQuery, rank bool) ([]models.UserInfo, int64, error) {
+	pipeline := bson.A{bson.M{"$match": filter}}
+	// Ties are broken by _id, which follows creation like the PostgreSQL id
+	sort := mongoSort(keysetOrder(query.Sort, "_id"))
+	if rank {
+		// The score is copied to a field, as the facets do not see the metadata of the documents
+		pipeline = append(pipeline, bson.M{"$addFields": bson.M{"_score": bson.M{"$meta": "textScore"}}})
+		sort = append(bson.D{{Key: "_score", Value: -1}}, sort...)
+	}
+	page := bson.A{
+		bson.M{"$sort": sort},
+		bson.M{"$skip": int64((query.Page - 1) * query.PageSize)},
+		bson.M{"$limit": int64(query.PageSize)},
+	}
+	if projection := mongoProjection(query.Fields); projection != nil {
+		page = append(page, bson.M{"$project": projection})
+	}
+
+	pipeline = append(pipeline, bson.M{"$facet": bson.M{
+		"users": page,
+		"total": bson.A{bson.M{"$count": "count"}},
+	}})
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	var results []struct {
+		Users []models.UserMongo `bson:"users"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+
+	userInfos := []models.UserInfo{}
+	var total int64
+	if len(results) > 0 {
+		for _, user := range results[0].Users {
+			userInfos = append(userInfos, user.Info())
+		}
+		if len(results[0].Total) > 0 {
+			total = results[0].Total[0].Count
+		}
+	}
+	return userInfos, total, nil
+}
+
+// listAllMongoUsers fetches a page of all the users without search or filters. Counting every document
+// would scan the collection, so the total is estimated from its metadata, less the soft-deleted users,
+// which the partial deleted_at index counts without reading a document. That is three commands where a
+// $facet would be one, but the facet's count reads the whole collection; the counts run alongside the
+// find, so the page costs one round trip of latency. The estimate may be briefly off after an unclean
+// shutdown.
+func listAllMongoUsers(ctx context.Context, collection *mongo.Collection, query ListUsersQuery) ([]models.UserInfo, int64, error) {
+	var estimated, deleted int64
+	var countErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if estimated, countErr = collection.EstimatedDocumentCount(ctx); countErr != nil {
+			return
+		}
+		deleted, countErr = collection.CountDocuments(ctx, bson.M{"deleted_at": bson.M{"$type": "date"}})
+	}()
+
+	users, err := findMongoUsers(ctx, collection, query)
+	wg.Wait()
+	if err != nil {
+		return nil, 0, err
+	}
+	if countErr != nil {
+		return nil, 0, countErr
+	}
+
+	userInfos := make([]models.UserInfo, len(users))
+	for i, user := range users {
+		userInfos[i] = user.Info()
+	}
+	return userInfos, max(estimated-deleted, 0), nil
+}
+
+// findMongoUsers fetches a page of the users that are not deleted
+func findMongoUsers(ctx context.Context, collection *mongo.Collection, query ListUsersQuery) ([]models.UserMongo, error) {
+	findOptions := options.Find().
+		SetSkip(int64((query.Page - 1) * query.PageSize)).
+		SetLimit(int64(query.PageSize)).
+		SetSort(mongoSort(keysetOrder(query.Sort, "_id"))).
+		SetProjection(mongoProjection(query.Fields))
+	cursor, err := collection.Find(ctx, notDeleted(bson.M{}), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.UserMongo
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// searchMongoUsers builds the MongoDB filter of query's search and filters and counts the matches, falling
+// back to the regex search like searchPostgresUsers does. The fallback is also used when the users text
+// index is missing, as happens with MONGODB_ENSURE_INDEXES off before make mongo-indexes has run.
+func searchMongoUsers(ctx context.Context, collection *mongo.Collection, query ListUsersQuery) (bson.M, int64, error) {
+	if text := mongoUserTextSearch(query.Search); text != nil {
+		filter := notDeleted(applyMongoFilters(text, query.Filters))
+		total, err := collection.CountDocuments(ctx, filter)
+		if err != nil && !database.IsMissingIndex(err) {
+			return nil, 0, err
+		}
+		if err == nil && total > 0 {
+			return filter, total, nil
+		}
+	}
+
+	filter := notDeleted(applyMongoFilters(mongoUserSearch(query.Search), query.Filters))
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return filter, total, nil
+}
+
+// filterMongoUsers builds the MongoDB filter of query's search and filters like searchMongoUsers, without
+// counting the matches: whether the text search matches anybody is checked by finding a single user
+func filterMongoUsers(ctx context.Context, collection *mongo.Collection, query ListUsersQuery) (bson.M, error) {
+	if text := mongoUserTextSearch(query.Search); text != nil {
+		filter := notDeleted(applyMongoFilters(text, query.Filters))
+		err := collection.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"_id": 1})).Err()
+		if err == nil {
+			return filter, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) && !database.IsMissingIndex(err) {
+			return nil, err
+		}
+	}
+	return notDeleted(applyMongoFilters(mongoUserSearch(query.Search), query.Filters)), nil
+}
+
+// ListUsersByCursor fetches the page with one lookahead row; pages before a cursor are fetched in
+// reverse, and the caller flips them back. A cursor that does not fit the query is utils.ErrInvalidCursor.
+func (s *userService) ListUsersByCursor(ctx context.Context, query ListUsersQuery) ([]models.UserInfo, int64, error) {
+	var values []interface{}
+	if query.Cursor != nil {
+		var err error
+		if values, err = query.Cursor.SortValues(query.Sort, utils.UserFilters); err != nil {
+			return nil, 0, err
+		}
+	}
+	before := query.Cursor != nil && query.Cursor.Before
+
+	// Sort columns must be loaded even when excluded from the fieldset so the next cursor can be built
+	sortColumns := make([]string, len(query.Sort))
+	for i, field := range query.Sort {
+		sortColumns[i] = field.Column
+	}
+
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		order := keysetOrder(query.Sort, "id")
+		db, total, err := searchPostgresUsers(s.postgresDB.WithContext(ctx).Model(&models.User{}), query, false)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if query.Cursor != nil {
+			if !s.postgresDB.ValidUserID(query.Cursor.ID) {
+				return nil, 0, utils.ErrInvalidCursor
+			}
+			db = applyKeyset(db, order, append(values, query.Cursor.ID), before)
+		}
+		if before {
+			order = reverseOrder(order)
+		}
+		db = applySort(db, order).Limit(query.PageSize + 1)
+		if len(query.Fields) > 0 {
+			db = db.Select(query.Fields.Columns("id", sortColumns...))
+		}
+
+		var users []models.User
+		if err := db.Find(&users).Error; err != nil {
+			return nil, 0, err
+		}
+
+		userInfos := make([]models.UserInfo, len(users))
+		for i, user := range users {
+			userInfos[i] = user.Info()
+		}
+		return userInfos, total, nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		collection := s.mongoDB.Collection("users")
+		order := keysetOrder(query.Sort, "_id")
+		filter, total, err := searchMongoUsers(ctx, collection, query)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if query.Cursor != nil {
+			objectID, err := primitive.ObjectIDFromHex(query.Cursor.ID)
+			if err != nil {
+				return nil, 0, utils.ErrInvalidCursor
+			}
+			filter = bson.M{"$and": []bson.M{filter, mongoKeyset(order, append(values, objectID), before)}}
+		}
+		if before {
+			order = reverseOrder(order)
+		}
+
+		findOptions := options.Find().
+			SetLimit(int64(query.PageSize + 1)).
+			SetSort(mongoSort(order)).
+			SetProjection(mongoProjection(query.Fields, sortColumns...))
+		cursor, err := collection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer cursor.Close(ctx)
+
+		var users []models.UserMongo
+		if err := cursor.All(ctx, &users); err != nil {
+			return nil, 0, err
+		}
+
+		userInfos := make([]models.UserInfo, len(users))
+		for i, user := range users {
+			userInfos[i] = user.Info()
+		}
+		return userInfos, total, nil
+	}
+
+	return nil, 0, errNoDatabase
+}
+
+// eachUserBatch is the number of users EachUser reads from MongoDB at a time
+const eachUserBatch = 500
+
+// EachUser streams the users from a single query, on a replica when one is configured; the matches are
+// not counted, and a full-text search first reads one row to check that it matches anybody. The query
+// runs without the statement timeout, so it lasts as long as the request.
+func (s *userService) EachUser(ctx context.Context, query ListUsersQuery, fn func(models.UserInfo) error) error {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		db, err := filterPostgresUsers(s.postgresDB.Replica().WithContext(ctx).Model(&models.User{}), query, query.Rank)
+		if err != nil {
+			return err
+		}
+		db = applySort(db, keysetOrder(query.Sort, "id"))
+		if len(query.Fields) > 0 {
+			db = db.Select(query.Fields.Columns("id"))
+		}
+
+		rows, err := db.Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var user models.User
+			if err := db.ScanRows(rows, &user); err != nil {
+				return err
+			}
+			if err := fn(user.Info()); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		collection := s.mongoDB.Collection("users")
+		filter, err := filterMongoUsers(ctx, collection, query)
+		if err != nil {
+			return err
+		}
+
+		sort := mongoSort(keysetOrder(query.Sort, "_id"))
+		if _, text := filter["$text"]; text && query.Rank {
+			sort = append(bson.D{mongoTextScore}, sort...)
+		}
+		findOptions := options.Find().
+			SetSort(sort).
+			SetBatchSize(eachUserBatch).
+			SetProjection(mongoProjection(query.Fields))
+		cursor, err := collection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var user models.UserMongo
+			if err := cursor.Decode(&user); err != nil {
+				return err
+			}
+			if err := fn(user.Info()); err != nil {
+				return err
+			}
+		}
+		return cursor.Err()
+	}
+
+	return errNoDatabase
+}
+
+// ChangeRole counts the other superadmins in the same transaction as the update. On PostgreSQL the
+// superadmin rows are locked, so two superadmins cannot demote each other at once; MongoDB gives the same
+// guarantee only where it supports transactions. The user's events receive role.changed; tokens issued
+// before the change keep the old role until they expire.
+func (s *userService) ChangeRole(ctx context.Context, userID, role string) (models.UserInfo, string, error) {
+	var userInfo models.UserInfo
+	var previous string
+
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		id, err := postgresUserID(s.postgresDB, userID)
+		if err != nil {
+			return models.UserInfo{}, "", err
+		}
+
+		var user models.User
+		err = s.postgresDB.WithTransaction(ctx, func(tx *database.PostgresDB) error {
+			if err := tx.First(&user, "id = ?", id).Error; err != nil {
+				return err
+			}
+			previous = user.Role
+			if previous == role {
+				return nil
+			}
+
+			if previous == "superadmin" && user.IsActive {
+				if err := keepPostgresSuperadmin(tx); err != nil {
+					return err
+				}
+			}
+
+			user.Role = role
+			user.UpdatedAt = time.Now()
+			return tx.Model(&user).Updates(map[string]interface{}{"role": user.Role, "updated_at": user.UpdatedAt}).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.UserInfo{}, "", ErrUserNotFound
+		}
+		if err != nil {
+			return models.UserInfo{}, "", err
+		}
+		userInfo = user.Info()
+	} else if s.mongoDB != nil {
+		// MongoDB implementation
+		objectID, err := mongoUserID(userID)
+		if err != nil {
+			return models.UserInfo{}, "", err
+		}
+
+		collection := s.mongoDB.Collection("users")
+		var user models.UserMongo
+		err = s.mongoDB.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := collection.FindOne(ctx, notDeleted(bson.M{"_id": objectID})).Decode(&user); err != nil {
+				return err
+			}
+			previous = user.Role
+			if previous == role {
+				return nil
+			}
+
+			if previous == "superadmin" && user.IsActive {
+				if err := keepMongoSuperadmin(ctx, collection); err != nil {
+					return err
+				}
+			}
+
+			user.Role = role
+			user.UpdatedAt = time.Now()
+			_, err := collection.UpdateOne(ctx, bson.M{"_id": objectID},
+				bson.M{"$set": bson.M{"role": user.Role, "updated_at": user.UpdatedAt}})
+			return err
+		})
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.UserInfo{}, "", ErrUserNotFound
+		}
+		if err != nil {
+			return models.UserInfo{}, "", err
+		}
+		userInfo = user.Info()
+	} else {
+		return models.UserInfo{}, "", errNoDatabase
+	}
+
+	if s.events != nil && previous != role {
+		s.events.Publish(userID, realtime.EventRoleChanged, map[string]string{"previous": previous, "role": role})
+	}
+	return userInfo, previous, nil
+}
+
+// SetActive checks for the last superadmin in the same transaction as the update, as ChangeRole does.
+// Tokens issued before a deactivation keep working until they expire.
+func (s *userService) SetActive(ctx context.Context, userID string, active bool, actorRole string) (models.UserInfo, error) {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		id, err := postgresUserID(s.postgresDB, userID)
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+
+		var user models.User
+		err = s.postgresDB.WithTransaction(ctx, func(tx *database.PostgresDB) error {
+			if err := tx.First(&user, "id = ?", id).Error; err != nil {
+				return err
+			}
+			if user.IsActive == active {
+				return nil
+			}
+			if user.Role == "superadmin" && !active {
+				if actorRole != "superadmin" {
+					return ErrSuperadminTarget
+				}
+				if err := keepPostgresSuperadmin(tx); err != nil {
+					return err
+				}
+			}
+
+			user.IsActive = active
+			user.UpdatedAt = time.Now()
+			return tx.Model(&user).Updates(map[string]interface{}{"is_active": user.IsActive, "updated_at": user.UpdatedAt}).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.UserInfo{}, ErrUserNotFound
+		}
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		objectID, err := mongoUserID(userID)
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+
+		collection := s.mongoDB.Collection("users")
+		var user models.UserMongo
+		err = s.mongoDB.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := collection.FindOne(ctx, notDeleted(bson.M{"_id": objectID})).Decode(&user); err != nil {
+				return err
+			}
+			if user.IsActive == active {
+				return nil
+			}
+			if user.Role == "superadmin" && !active {
+				if actorRole != "superadmin" {
+					return ErrSuperadminTarget
+				}
+				if err := keepMongoSuperadmin(ctx, collection); err != nil {
+					return err
+				}
+			}
+
+			user.IsActive = active
+			user.UpdatedAt = time.Now()
+			_, err := collection.UpdateOne(ctx, bson.M{"_id": objectID},
+				bson.M{"$set": bson.M{"is_active": user.IsActive, "updated_at": user.UpdatedAt}})
+			return err
+		})
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.UserInfo{}, ErrUserNotFound
+		}
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	return models.UserInfo{}, errNoDatabase
+}
+
+// keepPostgresSuperadmin returns ErrLastSuperadmin unless another active superadmin remains, locking the
+// superadmin rows so two transactions cannot each leave the other as the last one
+func keepPostgresSuperadmin(tx *database.PostgresDB) error {
+	superadmins := tx.Model(&models.User{}).Where("role = ? AND is_active", "superadmin")
+	if !tx.IsSQLite() {
+		superadmins = superadmins.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	var ids []string
+	if err := superadmins.Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) <= 1 {
+		return ErrLastSuperadmin
+	}
+	return nil
+}
+
+// keepMongoSuperadmin returns ErrLastSuperadmin unless another active superadmin remains
+func keepMongoSuperadmin(ctx context.Context, collection *mongo.Collection) error {
+	count, err := collection.CountDocuments(ctx, notDeleted(bson.M{"role": "superadmin", "is_active": true}))
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return ErrLastSuperadmin
+	}
+	return nil
+}
+
+// DeleteUser marks the user deleted; they can no longer log in and are hidden until restored or purged.
+// It checks for the last superadmin in the same transaction as the delete, as SetActive does.
+func (s *userService) DeleteUser(ctx context.Context, userID, actorRole string) error {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		id, err := postgresUserID(s.postgresDB, userID)
+		if err != nil {
+			return err
+		}
+
+		err = s.postgresDB.WithTransaction(ctx, func(tx *database.PostgresDB) error {
+			var user models.User
+			if err := tx.First(&user, "id = ?", id).Error; err != nil {
+				return err
+			}
+			if user.Role == "superadmin" {
+				if actorRole != "superadmin" {
+					return ErrSuperadminTarget
+				}
+				if user.IsActive {
+					if err := keepPostgresSuperadmin(tx); err != nil {
+						return err
+					}
+				}
+			}
+
+			// GORM sets deleted_at because the model has a gorm.DeletedAt field
+			return tx.Delete(&user).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		objectID, err := mongoUserID(userID)
+		if err != nil {
+			return err
+		}
+
+		collection := s.mongoDB.Collection("users")
+		err = s.mongoDB.WithTransaction(ctx, func(ctx context.Context) error {
+			var user models.UserMongo
+			if err := collection.FindOne(ctx, notDeleted(bson.M{"_id": objectID})).Decode(&user); err != nil {
+				return err
+			}
+			if user.Role == "superadmin" {
+				if actorRole != "superadmin" {
+					return ErrSuperadminTarget
+				}
+				if user.IsActive {
+					if err := keepMongoSuperadmin(ctx, collection); err != nil {
+						return err
+					}
+				}
+			}
+
+			now := time.Now()
+			_, err := collection.UpdateOne(ctx, bson.M{"_id": objectID},
+				bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}})
+			return err
+		})
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	return errNoDatabase
+}
+
+// RestoreUser clears deleted_at of a soft-deleted user that has not been purged yet
+func (s *userService) RestoreUser(ctx context.Context, userID string) (models.UserInfo, error) {
+	// PostgreSQL implementation
+	if s.postgresDB != nil {
+		id, err := postgresUserID(s.postgresDB, userID)
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+
+		// Restore and reload in one transaction so the result reflects exactly the restored row
+		var user models.User
+		err = s.postgresDB.WithTransaction(ctx, func(tx *database.PostgresDB) error {
+			result := tx.Unscoped().Model(&models.User{}).
+				Where("id = ? AND deleted_at IS NOT NULL", id).
+				Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+			return tx.First(&user, "id = ?", id).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.UserInfo{}, ErrUserNotFound
+		}
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	// MongoDB implementation
+	if s.mongoDB != nil {
+		objectID, err := mongoUserID(userID)
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+
+		var user models.UserMongo
+		err = s.mongoDB.Collection("users").FindOneAndUpdate(ctx,
+			bson.M{"_id": objectID, "deleted_at": bson.M{"$ne": nil}},
+			bson.M{"$unset": bson.M{"deleted_at": ""}, "$set": bson.M{"updated_at": time.Now()}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&user)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.UserInfo{}, ErrUserNotFound
+		}
+		if err != nil {
+			return models.UserInfo{}, err
+		}
+		return user.Info(), nil
+	}
+
+	return models.UserInfo{}, errNoDatabase
+}
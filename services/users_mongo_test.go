@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// testMongoUsers connects to MONGODB_TEST_URI and returns a users collection with its indexes in a database
+// of its own, dropped when the test ends. The test is skipped when MONGODB_TEST_URI is not set, such as
+// mongodb://localhost:27017 with the docker-compose MongoDB.
+func testMongoUsers(t *testing.T) *mongo.Collection {
+	t.Helper()
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	db := &database.MongoDB{Client: client, Database: client.Database(fmt.Sprintf("services_test_%d", time.Now().UnixNano()))}
+	t.Cleanup(func() {
+		db.Database.Drop(context.Background())
+		client.Disconnect(context.Background())
+	})
+	if err := db.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("indexes: %v", err)
+	}
+	return db.Collection("users")
+}
+
+// seedMongoUsers inserts users whose roles and creation times tie, so the order depends on the _id
+// tiebreak, and soft-deletes every fifth one
+func seedMongoUsers(t *testing.T, collection *mongo.Collection) {
+	t.Helper()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	deleted := created.Add(time.Hour)
+	var documents []interface{}
+	for i := 0; i < 25; i++ {
+		user := models.UserMongo{
+			ID:        primitive.NewObjectID(),
+			Email:     fmt.Sprintf("user%02d@example.com", i),
+			Username:  fmt.Sprintf("user%02d", i),
+			FirstName: "Ada",
+			LastName:  fmt.Sprintf("Tester%d", i%3),
+			Role:      []string{"user", "admin"}[i%2],
+			IsActive:  true,
+			CreatedAt: created.Add(time.Duration(i/4) * time.Minute),
+			UpdatedAt: created,
+		}
+		if i%5 == 4 {
+			user.DeletedAt = &deleted
+		}
+		documents = append(documents, user)
+	}
+	if _, err := collection.InsertMany(context.Background(), documents); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+}
+
+// findMongoPage is the reference the listings are checked against: a plain find and count of filter
+func findMongoPage(t *testing.T, collection *mongo.Collection, filter bson.M, query ListUsersQuery) ([]string, int64) {
+	t.Helper()
+	ctx := context.Background()
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(mongoSort(keysetOrder(query.Sort, "_id"))).
+		SetSkip(int64((query.Page-1)*query.PageSize)).
+		SetLimit(int64(query.PageSize)))
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	var users []models.UserMongo
+	if err := cursor.All(ctx, &users); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.ID.Hex()
+	}
+	return ids, total
+}
+
+func userIDs(users []models.UserInfo) []string {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return ids
+}
+
+func checkPage(t *testing.T, got []models.UserInfo, gotTotal int64, want []string, wantTotal int64) {
+	t.Helper()
+	if gotTotal != wantTotal {
+		t.Errorf("total = %d, want %d", gotTotal, wantTotal)
+	}
+	if ids := userIDs(got); fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("page = %v, want %v", ids, want)
+	}
+}
+
+var mongoListQueries = []ListUsersQuery{
+	{Page: 1, PageSize: 7, Sort: []utils.SortField{{Column: "created_at", Descending: true}}},
+	{Page: 2, PageSize: 7, Sort: []utils.SortField{{Column: "created_at", Descending: true}}},
+	{Page: 3, PageSize: 7, Sort: []utils.SortField{{Column: "role"}, {Column: "last_name", Descending: true}}},
+	{Page: 1, PageSize: 50, Sort: []utils.SortField{{Column: "role"}}},
+}
+
+// TestListAllMongoUsers checks the page of all users against a find, and the estimated total against an
+// exact count of the users that are not deleted
+func TestListAllMongoUsers(t *testing.T) {
+	collection := testMongoUsers(t)
+	seedMongoUsers(t, collection)
+
+	for _, query := range mongoListQueries {
+		t.Run(fmt.Sprintf("page %d by %v", query.Page, query.Sort), func(t *testing.T) {
+			users, total, err := listAllMongoUsers(context.Background(), collection, query)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			want, wantTotal := findMongoPage(t, collection, notDeleted(bson.M{}), query)
+			checkPage(t, users, total, want, wantTotal)
+		})
+	}
+}
+
+// TestFacetMongoUsers runs the $facet aggregation and checks its page and total against a find and count
+// of the same filter, with and without the text score leading the order
+func TestFacetMongoUsers(t *testing.T) {
+	collection := testMongoUsers(t)
+	seedMongoUsers(t, collection)
+
+	filters := []struct {
+		name   string
+		filter func() bson.M
+		rank   bool
+	}{
+		{"filters", func() bson.M { return notDeleted(bson.M{"role": "admin"}) }, false},
+		{"text search", func() bson.M { return notDeleted(mongoUserTextSearch("Tester1")) }, false},
+		// Every match scores the same, so the ranked order is the requested one
+		{"ranked text search", func() bson.M { return notDeleted(mongoUserTextSearch("Tester1")) }, true},
+		{"no match", func() bson.M { return notDeleted(bson.M{"role": "nobody"}) }, false},
+	}
+	for _, tt := range filters {
+		for _, query := range mongoListQueries {
+			t.Run(fmt.Sprintf("%s page %d by %v", tt.name, query.Page, query.Sort), func(t *testing.T) {
+				users, total, err := facetMongoUsers(context.Background(), collection, tt.filter(), query, tt.rank)
+				if err != nil {
+					t.Fatalf("aggregate: %v", err)
+				}
+				want, wantTotal := findMongoPage(t, collection, tt.filter(), query)
+				checkPage(t, users, total, want, wantTotal)
+			})
+		}
+	}
+
+	t.Run("projection", func(t *testing.T) {
+		query := ListUsersQuery{Page: 1, PageSize: 3, Fields: utils.FieldSet{"email"}, Sort: []utils.SortField{{Column: "email"}}}
+		users, _, err := facetMongoUsers(context.Background(), collection, notDeleted(bson.M{}), query, false)
+		if err != nil {
+			t.Fatalf("aggregate: %v", err)
+		}
+		if len(users) != 3 || users[0].Email != "user00@example.com" || users[0].Username != "" {
+			t.Errorf("projected page = %+v, want emails only from user00", users)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"go-backend-template/models"
+	"go-backend-template/services"
+	"go-backend-template/testutil"
+)
+
+// TestSuperadminProtection checks that only superadmins deactivate or delete superadmins, and never the
+// last active one
+func TestSuperadminProtection(t *testing.T) {
+	ctx := context.Background()
+	var users []models.User
+	for i, role := range []string{"superadmin", "superadmin", "admin"} {
+		user := testutil.NewUser().WithRole(role).Model()
+		user.ID = strconv.Itoa(i + 1)
+		users = append(users, user)
+	}
+	service := sqliteUsers(t, users)
+
+	if err := service.DeleteUser(ctx, "2", "admin"); !errors.Is(err, services.ErrSuperadminTarget) {
+		t.Errorf("admin deletes superadmin: err = %v, want ErrSuperadminTarget", err)
+	}
+	if _, err := service.SetActive(ctx, "2", false, "admin"); !errors.Is(err, services.ErrSuperadminTarget) {
+		t.Errorf("admin deactivates superadmin: err = %v, want ErrSuperadminTarget", err)
+	}
+	if err := service.DeleteUser(ctx, "3", "admin"); err != nil {
+		t.Errorf("admin deletes admin: %v", err)
+	}
+
+	if err := service.DeleteUser(ctx, "2", "superadmin"); err != nil {
+		t.Fatalf("superadmin deletes superadmin: %v", err)
+	}
+	if err := service.DeleteUser(ctx, "1", "superadmin"); !errors.Is(err, services.ErrLastSuperadmin) {
+		t.Errorf("delete last superadmin: err = %v, want ErrLastSuperadmin", err)
+	}
+	if _, err := service.SetActive(ctx, "1", false, "superadmin"); !errors.Is(err, services.ErrLastSuperadmin) {
+		t.Errorf("deactivate last superadmin: err = %v, want ErrLastSuperadmin", err)
+	}
+	if _, err := service.GetProfile(ctx, "1", nil); err != nil {
+		t.Errorf("last superadmin is gone: %v", err)
+	}
+}
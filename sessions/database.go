@@ -0,0 +1,171 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// PostgresStore persists sessions in PostgreSQL
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the table is created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Create inserts the session, first dropping the expired ones
+func (s *PostgresStore) Create(ctx context.Context, session *models.Session) error {
+	db := s.db.WithContext(ctx)
+	if err := db.Where("expires_at <= ?", time.Now()).Delete(&models.Session{}).Error; err != nil {
+		return err
+	}
+	return db.Create(session).Error
+}
+
+// Get returns the unexpired session with the token hash
+func (s *PostgresStore) Get(ctx context.Context, tokenHash string) (*models.Session, error) {
+	var session models.Session
+	err := s.db.WithContext(ctx).Where("token_hash = ? AND expires_at > ?", tokenHash, time.Now()).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Rotate updates the session in one statement conditioned on its token hash, so of two concurrent
+// refreshes with the same token only one matches
+func (s *PostgresStore) Rotate(ctx context.Context, session *models.Session, previousHash string) error {
+	result := s.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND token_hash = ? AND expires_at > ?", session.ID, previousHash, time.Now()).
+		Updates(map[string]interface{}{
+			"token_hash":   session.TokenHash,
+			"last_used_at": session.LastUsedAt,
+			"expires_at":   session.ExpiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// List returns the unexpired sessions of the user, most recently used first
+func (s *PostgresStore) List(ctx context.Context, userID string) ([]models.Session, error) {
+	sessions := []models.Session{}
+	err := s.db.WithContext(ctx).Where("user_id = ? AND expires_at > ?", userID, time.Now()).Order("last_used_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// Delete removes the session of the user
+func (s *PostgresStore) Delete(ctx context.Context, userID, id string) error {
+	result := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.Session{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MongoStore persists sessions in MongoDB; they expire through a TTL index
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store and ensures its indexes exist
+func NewMongoStore(ctx context.Context, db *database.MongoDB) (*MongoStore, error) {
+	collection := db.Collection("sessions")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_used_at", Value: -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection}, nil
+}
+
+// Create inserts the session
+func (s *MongoStore) Create(ctx context.Context, session *models.Session) error {
+	_, err := s.collection.InsertOne(ctx, session)
+	return err
+}
+
+// Get returns the unexpired session with the token hash; the TTL monitor runs periodically, so expiry is
+// also checked in the filter
+func (s *MongoStore) Get(ctx context.Context, tokenHash string) (*models.Session, error) {
+	var session models.Session
+	err := s.collection.FindOne(ctx, bson.M{"token_hash": tokenHash, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Rotate updates the session only while its token hash is previousHash
+func (s *MongoStore) Rotate(ctx context.Context, session *models.Session, previousHash string) error {
+	result, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": session.ID, "token_hash": previousHash, "expires_at": bson.M{"$gt": time.Now()}},
+		bson.M{"$set": bson.M{
+			"token_hash":   session.TokenHash,
+			"last_used_at": session.LastUsedAt,
+			"expires_at":   session.ExpiresAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// List returns the unexpired sessions of the user, most recently used first
+func (s *MongoStore) List(ctx context.Context, userID string) ([]models.Session, error) {
+	cursor, err := s.collection.Find(ctx,
+		bson.M{"user_id": userID, "expires_at": bson.M{"$gt": time.Now()}},
+		options.Find().SetSort(bson.D{{Key: "last_used_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []models.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Delete removes the session of the user
+func (s *MongoStore) Delete(ctx context.Context, userID, id string) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Package sessions keeps users signed in with refresh tokens. Each login starts a session on the device;
+// the client exchanges the session's refresh token for a new access token before the old one expires,
+// getting a new refresh token each time, and the user lists and ends their sessions. Sessions started
+// with remember me last longer.
+package sessions
+
+import (
+	"context"
+	"time"
+
+	"go-backend-template/config"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// Client describes the device a session is started on, as listed to its user
+type Client struct {
+	UserAgent string
+	IPAddress string
+}
+
+type clientKey struct{}
+
+// WithClient returns ctx carrying the client of the request, for the sessions started with it
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// clientFrom returns the client ctx carries, or none
+func clientFrom(ctx context.Context) Client {
+	client, _ := ctx.Value(clientKey{}).(Client)
+	return client
+}
+
+// Manager starts, refreshes, and ends sessions
+type Manager struct {
+	store Store
+	cfg   config.SessionsConfig
+}
+
+// NewManager creates a manager keeping sessions in store for the lifetimes of cfg
+func NewManager(cfg config.SessionsConfig, store Store) *Manager {
+	return &Manager{store: store, cfg: cfg}
+}
+
+// lifetimes returns how long a session may go unused and how long it may last at most
+func (m *Manager) lifetimes(rememberMe bool) (idle, max time.Duration) {
+	if rememberMe {
+		return m.cfg.RememberMeIdleTimeout, m.cfg.RememberMeMaxLifetime
+	}
+	return m.cfg.IdleTimeout, m.cfg.MaxLifetime
+}
+
+// New returns a session of the user on the client ctx carries, with its refresh token. The session is only
+// stored by Create, so its ID can go into the access token signed first.
+func (m *Manager) New(ctx context.Context, userID string, rememberMe bool) (string, *models.Session, error) {
+	id, err := utils.RandomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	client := clientFrom(ctx)
+	idle, max := m.lifetimes(rememberMe)
+	now := time.Now()
+	session := &models.Session{
+		ID:           id,
+		UserID:       userID,
+		TokenHash:    utils.HashToken(token),
+		RememberMe:   rememberMe,
+		UserAgent:    client.UserAgent,
+		IPAddress:    client.IPAddress,
+		CreatedAt:    now,
+		LastUsedAt:   now,
+		ExpiresAt:    now.Add(idle),
+		MaxExpiresAt: now.Add(max),
+	}
+	return token, session, nil
+}
+
+// Create stores a session from New, which starts it
+func (m *Manager) Create(ctx context.Context, session *models.Session) error {
+	return m.store.Create(ctx, session)
+}
+
+// Refresh exchanges a refresh token for a new one, sliding the expiry of its session by the idle timeout
+// up to the session's maximum lifetime. A token that was already exchanged, or whose session ended or
+// expired, is ErrNotFound.
+func (m *Manager) Refresh(ctx context.Context, token string) (string, *models.Session, error) {
+	previousHash := utils.HashToken(token)
+	session, err := m.store.Get(ctx, previousHash)
+	if err != nil {
+		return "", nil, err
+	}
+
+	next, err := utils.RandomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+	idle, _ := m.lifetimes(session.RememberMe)
+	now := time.Now()
+	session.TokenHash = utils.HashToken(next)
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(idle)
+	if session.ExpiresAt.After(session.MaxExpiresAt) {
+		session.ExpiresAt = session.MaxExpiresAt
+	}
+	if err := m.store.Rotate(ctx, session, previousHash); err != nil {
+		return "", nil, err
+	}
+	return next, session, nil
+}
+
+// List returns the active sessions of the user, most recently used first
+func (m *Manager) List(ctx context.Context, userID string) ([]models.Session, error) {
+	return m.store.List(ctx, userID)
+}
+
+// Revoke ends the session of the user with the ID, or returns ErrNotFound. Access tokens already issued
+// for it stay valid until they expire.
+func (m *Manager) Revoke(ctx context.Context, userID, id string) error {
+	return m.store.Delete(ctx, userID, id)
+}
+
+// End ends the session of a refresh token, as on logout, or returns ErrNotFound
+func (m *Manager) End(ctx context.Context, token string) error {
+	session, err := m.store.Get(ctx, utils.HashToken(token))
+	if err != nil {
+		return err
+	}
+	return m.store.Delete(ctx, session.UserID, session.ID)
+}
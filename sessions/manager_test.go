@@ -0,0 +1,159 @@
+package sessions_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-backend-template/config"
+	"go-backend-template/models"
+	"go-backend-template/sessions"
+)
+
+var lifetimes = config.SessionsConfig{
+	Enabled:               true,
+	IdleTimeout:           time.Hour,
+	MaxLifetime:           2 * time.Hour,
+	RememberMeIdleTimeout: 24 * time.Hour,
+	RememberMeMaxLifetime: 24 * time.Hour,
+}
+
+// start starts a session of the user and returns its refresh token
+func start(t *testing.T, manager *sessions.Manager, userID string, rememberMe bool) (string, *models.Session) {
+	t.Helper()
+	token, session, err := manager.New(context.Background(), userID, rememberMe)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := manager.Create(context.Background(), session); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	return token, session
+}
+
+// within reports whether got is at most a second away from want, the time the test takes
+func within(got, want time.Time) bool {
+	return got.Sub(want).Abs() < time.Second
+}
+
+func TestLifetimes(t *testing.T) {
+	manager := sessions.NewManager(lifetimes, sessions.NewMemoryStore())
+	now := time.Now()
+
+	_, session := start(t, manager, "1", false)
+	if session.RememberMe || !within(session.ExpiresAt, now.Add(time.Hour)) || !within(session.MaxExpiresAt, now.Add(2*time.Hour)) {
+		t.Errorf("session expires %v, at most %v; want in 1h and 2h", session.ExpiresAt, session.MaxExpiresAt)
+	}
+	_, remembered := start(t, manager, "1", true)
+	if !remembered.RememberMe || !within(remembered.ExpiresAt, now.Add(24*time.Hour)) || !within(remembered.MaxExpiresAt, now.Add(24*time.Hour)) {
+		t.Errorf("remembered session expires %v, at most %v; want in 24h", remembered.ExpiresAt, remembered.MaxExpiresAt)
+	}
+
+	list, err := manager.List(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != remembered.ID || !list[0].RememberMe || list[1].RememberMe {
+		t.Errorf("list = %+v, want the remembered session first, then the other", list)
+	}
+}
+
+// TestRefresh checks that a refresh token works once and that refreshing slides the expiry, but never past
+// the maximum lifetime
+func TestRefresh(t *testing.T) {
+	manager := sessions.NewManager(lifetimes, sessions.NewMemoryStore())
+	token, session := start(t, manager, "1", false)
+
+	time.Sleep(10 * time.Millisecond)
+	next, refreshed, err := manager.Refresh(context.Background(), token)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if next == token || refreshed.ID != session.ID {
+		t.Errorf("refresh returned token %q of session %q; want a new token of %q", next, refreshed.ID, session.ID)
+	}
+	if !refreshed.ExpiresAt.After(session.ExpiresAt) || !refreshed.MaxExpiresAt.Equal(session.MaxExpiresAt) {
+		t.Errorf("refreshed session expires %v, at most %v; want after %v, at most %v",
+			refreshed.ExpiresAt, refreshed.MaxExpiresAt, session.ExpiresAt, session.MaxExpiresAt)
+	}
+
+	if _, _, err := manager.Refresh(context.Background(), token); !errors.Is(err, sessions.ErrNotFound) {
+		t.Errorf("reused refresh token: err = %v, want ErrNotFound", err)
+	}
+	if _, _, err := manager.Refresh(context.Background(), next); err != nil {
+		t.Errorf("new refresh token: %v", err)
+	}
+
+	// With the idle timeout as long as the maximum lifetime, every refresh reaches the cap
+	capped := lifetimes
+	capped.MaxLifetime = capped.IdleTimeout
+	manager = sessions.NewManager(capped, sessions.NewMemoryStore())
+	token, session = start(t, manager, "1", false)
+	time.Sleep(10 * time.Millisecond)
+	_, refreshed, err = manager.Refresh(context.Background(), token)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if !refreshed.ExpiresAt.Equal(session.MaxExpiresAt) {
+		t.Errorf("refreshed session expires %v, want its maximum %v", refreshed.ExpiresAt, session.MaxExpiresAt)
+	}
+}
+
+func TestRefreshConcurrent(t *testing.T) {
+	manager := sessions.NewManager(lifetimes, sessions.NewMemoryStore())
+	token, _ := start(t, manager, "1", false)
+
+	var wg sync.WaitGroup
+	results := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := manager.Refresh(context.Background(), token)
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, sessions.ErrNotFound):
+			t.Errorf("refresh: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d refreshes with the same token succeeded, want 1", succeeded)
+	}
+}
+
+func TestRevokeAndEnd(t *testing.T) {
+	manager := sessions.NewManager(lifetimes, sessions.NewMemoryStore())
+	token, session := start(t, manager, "1", false)
+	other, _ := start(t, manager, "1", true)
+
+	if err := manager.Revoke(context.Background(), "2", session.ID); !errors.Is(err, sessions.ErrNotFound) {
+		t.Errorf("revoke by another user: err = %v, want ErrNotFound", err)
+	}
+	if err := manager.Revoke(context.Background(), "1", session.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, _, err := manager.Refresh(context.Background(), token); !errors.Is(err, sessions.ErrNotFound) {
+		t.Errorf("refresh of revoked session: err = %v, want ErrNotFound", err)
+	}
+
+	if err := manager.End(context.Background(), other); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	if err := manager.End(context.Background(), other); !errors.Is(err, sessions.ErrNotFound) {
+		t.Errorf("end twice: err = %v, want ErrNotFound", err)
+	}
+	if list, _ := manager.List(context.Background(), "1"); len(list) != 0 {
+		t.Errorf("list = %+v, want no sessions", list)
+	}
+}
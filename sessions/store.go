@@ -0,0 +1,114 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go-backend-template/models"
+)
+
+// ErrNotFound is returned for a session that does not exist or expired, or whose refresh token was
+// replaced by a refresh
+var ErrNotFound = errors.New("session not found")
+
+// Store persists sessions
+type Store interface {
+	// Create stores a new session
+	Create(ctx context.Context, session *models.Session) error
+	// Get returns the unexpired session whose current refresh token has the hash, or ErrNotFound
+	Get(ctx context.Context, tokenHash string) (*models.Session, error)
+	// Rotate sets the token hash, last use, and expiry of the unexpired session only while its token hash
+	// is still previousHash, so a refresh token is exchanged once, or returns ErrNotFound
+	Rotate(ctx context.Context, session *models.Session, previousHash string) error
+	// List returns the unexpired sessions of the user, most recently used first
+	List(ctx context.Context, userID string) ([]models.Session, error)
+	// Delete ends the session of the user with the ID, or returns ErrNotFound
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// MemoryStore is an in-process Store, suitable for single-instance deployments and tests; sessions are
+// lost on restart
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]*models.Session
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]*models.Session)}
+}
+
+// Create stores the session, first dropping the expired ones so the map does not grow without bound
+func (s *MemoryStore) Create(ctx context.Context, session *models.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, existing := range s.sessions {
+		if !now.Before(existing.ExpiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	copied := *session
+	s.sessions[session.ID] = &copied
+	return nil
+}
+
+// Get returns the unexpired session with the token hash
+func (s *MemoryStore) Get(ctx context.Context, tokenHash string) (*models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, session := range s.sessions {
+		if session.TokenHash == tokenHash && now.Before(session.ExpiresAt) {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Rotate updates the session while its token hash is previousHash
+func (s *MemoryStore) Rotate(ctx context.Context, session *models.Session, previousHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.sessions[session.ID]
+	if !ok || existing.TokenHash != previousHash || !time.Now().Before(existing.ExpiresAt) {
+		return ErrNotFound
+	}
+	existing.TokenHash, existing.LastUsedAt, existing.ExpiresAt = session.TokenHash, session.LastUsedAt, session.ExpiresAt
+	return nil
+}
+
+// List returns the unexpired sessions of the user, most recently used first
+func (s *MemoryStore) List(ctx context.Context, userID string) ([]models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	sessions := []models.Session{}
+	for _, session := range s.sessions {
+		if session.UserID == userID && now.Before(session.ExpiresAt) {
+			sessions = append(sessions, *session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt) })
+	return sessions, nil
+}
+
+// Delete removes the session of the user
+func (s *MemoryStore) Delete(ctx context.Context, userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session, ok := s.sessions[id]; !ok || session.UserID != userID {
+		return ErrNotFound
+	}
+	delete(s.sessions, id)
+	return nil
+}
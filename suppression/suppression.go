@@ -0,0 +1,141 @@
+// Package suppression keeps email away from addresses that no longer receive it. The bounce and
+// complaint webhooks of the email provider mark the users with those addresses, and code that sends email
+// checks Suppressed first.
+package suppression
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+	"go-backend-template/utils"
+	"go-backend-template/webhooks"
+)
+
+// List marks and looks up the email status of the users in the primary database. Addresses are matched
+// without regard to case, and deleted users are included, so restoring one does not lift the suppression.
+type List struct {
+	mongoDB    *database.MongoDB
+	postgresDB *database.PostgresDB
+	logger     utils.Logger
+}
+
+// NewList uses PostgreSQL when both databases are configured
+func NewList(mongoDB *database.MongoDB, postgresDB *database.PostgresDB, logger utils.Logger) *List {
+	return &List{mongoDB: mongoDB, postgresDB: postgresDB, logger: logger}
+}
+
+// Mark sets the email status of the users with email to status, models.EmailBounced or
+// models.EmailComplained, as of at, and returns the number of users changed. A complaint is not replaced
+// by a bounce, and marking a user again keeps the first time.
+func (l *List) Mark(ctx context.Context, email, status string, at time.Time) (int64, error) {
+	// PostgreSQL implementation
+	if l.postgresDB != nil {
+		db := l.postgresDB.WithContext(ctx).Unscoped().Model(&models.User{}).Where("LOWER(email) = LOWER(?)", email)
+		if status == models.EmailComplained {
+			db = db.Where("email_status <> ?", models.EmailComplained)
+		} else {
+			db = db.Where("email_status = ''")
+		}
+		result := db.Updates(map[string]interface{}{"email_status": status, "email_status_at": at})
+		return result.RowsAffected, result.Error
+	}
+
+	// MongoDB implementation
+	if l.mongoDB != nil {
+		filter := bson.M{"email": addressPattern(email), "email_status": bson.M{"$in": bson.A{"", nil}}}
+		if status == models.EmailComplained {
+			filter["email_status"] = bson.M{"$ne": models.EmailComplained}
+		}
+		result, err := l.mongoDB.Collection("users").UpdateMany(ctx, filter,
+			bson.M{"$set": bson.M{"email_status": status, "email_status_at": at}})
+		if err != nil {
+			return 0, err
+		}
+		return result.ModifiedCount, nil
+	}
+
+	return 0, nil
+}
+
+// Suppressed reports whether no email may be sent to email because a user with the address bounced or
+// complained
+func (l *List) Suppressed(ctx context.Context, email string) (bool, error) {
+	// PostgreSQL implementation
+	if l.postgresDB != nil {
+		var count int64
+		err := l.postgresDB.WithContext(ctx).Unscoped().Model(&models.User{}).
+			Where("LOWER(email) = LOWER(?) AND email_status <> ''", email).Count(&count).Error
+		return count > 0, err
+	}
+
+	// MongoDB implementation
+	if l.mongoDB != nil {
+		count, err := l.mongoDB.Collection("users").CountDocuments(ctx,
+			bson.M{"email": addressPattern(email), "email_status": bson.M{"$nin": bson.A{"", nil}}})
+		return count > 0, err
+	}
+
+	return false, nil
+}
+
+// sendGridEvent is the part of a SendGrid Event Webhook event that tells a bounce or complaint
+type sendGridEvent struct {
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// HandleSendGrid marks the addresses of the bounce and spam report events of a SendGrid delivery. Bounces
+// of type blocked are temporary refusals and are left alone, as are the other events. Marking is
+// idempotent, so a delivery may be processed again.
+func (l *List) HandleSendGrid(ctx context.Context, delivery webhooks.Delivery) error {
+	var events []sendGridEvent
+	if err := json.Unmarshal(delivery.Payload, &events); err != nil {
+		return fmt.Errorf("failed to decode SendGrid events: %w", err)
+	}
+
+	var errs []error
+	for _, event := range events {
+		var status string
+		switch {
+		case event.Event == "bounce" && event.Type != "blocked":
+			status = models.EmailBounced
+		case event.Event == "spamreport":
+			status = models.EmailComplained
+		default:
+			continue
+		}
+		if event.Email == "" {
+			continue
+		}
+
+		at := time.Now().UTC()
+		if event.Timestamp > 0 {
+			at = time.Unix(event.Timestamp, 0).UTC()
+		}
+		marked, err := l.Mark(ctx, event.Email, status, at)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if marked > 0 {
+			l.logger.Info("Suppressed email to a user address", "status", status, "users", marked)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// addressPattern matches email in MongoDB without regard to case
+func addressPattern(email string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(email) + "$", Options: "i"}
+}
@@ -0,0 +1,105 @@
+package testutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend-template/app"
+	"go-backend-template/audit"
+	"go-backend-template/config"
+	"go-backend-template/handlers"
+	"go-backend-template/idempotency"
+	"go-backend-template/jobs"
+	"go-backend-template/middleware"
+	"go-backend-template/operations"
+	"go-backend-template/posts"
+	"go-backend-template/realtime"
+	"go-backend-template/routes"
+	"go-backend-template/translations"
+	"go-backend-template/usage"
+	"go-backend-template/utils"
+	"go-backend-template/webhooks"
+)
+
+// API is the full route table of routes.SetupRoutes served from in-memory fakes: users from a
+// UserRepository, posts, usage, translation overrides, the audit log, webhook deliveries, and idempotency
+// keys from the memory stores, and the read-only mode switch. The webhook receiver has no providers until
+// the caller registers some. Bulk user actions requested with Prefer: respond-async run on workers of the
+// process, which run until it exits.
+// Billing, migrations, backups, metrics, and profiling are left out because they need external services
+// or real databases.
+type API struct {
+	Router       *gin.Engine
+	Tokens       *TokenFactory
+	Users        *UserRepository
+	Posts        *posts.MemoryStore
+	Translations *translations.Manager
+	Audit        *audit.MemoryStore
+	ReadOnly     *middleware.ReadOnlyMode
+	Webhooks     *webhooks.Receiver
+	Operations   *operations.Queue
+}
+
+// NewAPI wires the routes for cfg, such as one from config.Load. The middleware runs first on every
+// request, ahead of the localization and request ID middleware the application installs.
+func NewAPI(cfg *config.Config, mw ...gin.HandlerFunc) (*API, error) {
+	logger := Logger()
+	localizer, err := utils.NewLocalizer(utils.LocalizerOptions{DefaultLanguage: "en"})
+	if err != nil {
+		return nil, err
+	}
+	meter, err := usage.NewMeter(usage.NewMemoryStore(), cfg.Usage.Quotas, nil)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := utils.ParseMetadataSchema(cfg.UserMetadata.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	api := &API{Router: NewRouter(), Tokens: NewTokenFactory(), Posts: posts.NewMemoryStore(), Audit: audit.NewMemoryStore(),
+		ReadOnly: middleware.NewReadOnlyMode(cfg.ReadOnly.Enabled, cfg.ReadOnly.Message)}
+	api.Users = NewUserRepository(api.Tokens.JWT)
+	api.Translations = translations.NewManager(translations.NewMemoryStore(), localizer, 0, logger)
+	hub := realtime.NewHub(cfg.Realtime.BufferSize, cfg.Realtime.HistorySize, logger)
+	securityLog := SecurityLog()
+	recorder := audit.NewRecorder(api.Audit, audit.Options{HashChain: true})
+	api.Webhooks = webhooks.NewReceiver(webhooks.NewMemoryStore(), cfg.Webhooks.MaxAttempts, logger)
+	api.Operations = operations.NewQueue(operations.NewMemoryStore(), operations.Options{PollInterval: 10 * time.Millisecond}, logger)
+	api.Operations.Register(jobs.OperationBulkUsers, jobs.NewBulkUsers(api.Users, securityLog, logger).Task())
+	api.Operations.Start(context.Background())
+
+	apiCfg := *cfg
+	apiCfg.APIDocs = false
+
+	api.Router.Use(mw...)
+	api.Router.Use(middleware.Localization(localizer))
+	api.Router.Use(middleware.RequestID(logger))
+	h := app.Handlers{
+		Auth:        handlers.NewAuthHandler(cfg.Auth, api.Users, nil, logger, localizer, securityLog, nil, nil),
+		User:        handlers.NewUserHandler(api.Users, metadata, logger, localizer, securityLog, api.Operations),
+		Post:        handlers.NewPostHandler(api.Posts, logger, localizer),
+		Health:      handlers.NewHealthHandler(cfg.Health, nil, nil, logger),
+		Realtime:    handlers.NewRealtimeHandler(cfg.Realtime, hub, logger, localizer),
+		Usage:       handlers.NewUsageHandler(meter, logger, localizer),
+		Translation: handlers.NewTranslationHandler(api.Translations, logger, localizer),
+		Stats:       handlers.NewStatsHandler(api.Users, securityLog, logger, localizer),
+		Audit:       handlers.NewAuditHandler(recorder, logger, localizer),
+		ReadOnly:    handlers.NewReadOnlyHandler(api.ReadOnly, logger, localizer),
+		Webhook:     handlers.NewWebhookHandler(api.Webhooks, logger, localizer),
+		Operation:   handlers.NewOperationHandler(api.Operations, logger, localizer),
+	}
+	opts := routes.Options{AdminMiddleware: []gin.HandlerFunc{middleware.Audit(recorder, logger)}, ReadOnly: api.ReadOnly}
+	err = routes.SetupRoutes(api.Router, &apiCfg, opts, api.Tokens.JWT, nil, idempotency.NewMemoryStore(), meter, nil,
+		h.Registrars(), nil, nil, logger)
+	if err != nil {
+		return nil, err
+	}
+	api.Router.RemoveExtraSlash = cfg.Router.RemoveExtraSlash
+	api.Router.HandleMethodNotAllowed = cfg.Router.MethodNotAllowed
+	api.Router.NoRoute(middleware.NotFound())
+	api.Router.NoMethod(middleware.MethodNotAllowed())
+	return api, nil
+}
@@ -0,0 +1,628 @@
+package testutil
+
+import (
+	"cmp"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"go-backend-template/jwt"
+	"go-backend-template/models"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// UserRepository is an in-memory fake of the user database. It implements services.AuthService,
+// services.UserService, and services.StatsService with the same errors as the real services: IDs are decimal strings, emails and
+// usernames are unique across soft-deleted users too, and deleted users are hidden until restored.
+// Sparse fieldsets are not applied; handlers project the result anyway.
+type UserRepository struct {
+	mu       sync.Mutex
+	users    []*models.User
+	nextID   uint
+	jwtUtils *utils.JWTUtils
+}
+
+var (
+	_ services.AuthService  = (*UserRepository)(nil)
+	_ services.UserService  = (*UserRepository)(nil)
+	_ services.StatsService = (*UserRepository)(nil)
+)
+
+// NewUserRepository creates an empty repository whose tokens are signed with jwtUtils' secret
+func NewUserRepository(jwtUtils *utils.JWTUtils) *UserRepository {
+	return &UserRepository{nextID: 1, jwtUtils: jwtUtils}
+}
+
+// Add stores a user, such as one from UserBuilder.Model, and returns it as the services return it. A zero
+// ID is assigned the next free one.
+func (r *UserRepository) Add(user models.User) models.UserInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = strconv.FormatUint(uint64(r.nextID), 10)
+	}
+	r.nextID = max(r.nextID, numericID(user.ID)+1)
+	r.users = append(r.users, &user)
+	return user.Info()
+}
+
+// User returns the stored user with the ID, including a soft-deleted one
+func (r *UserRepository) User(userID string) (models.User, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.ID == userID {
+			return *user, true
+		}
+	}
+	return models.User{}, false
+}
+
+// Register implements services.AuthService
+func (r *UserRepository) Register(ctx context.Context, req models.RegisterRequest) (*models.AuthResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkUnique("", req.Email, req.Username); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Truncate(time.Microsecond)
+	user := &models.User{
+		ID:              strconv.FormatUint(uint64(r.nextID), 10),
+		Email:           req.Email,
+		Username:        req.Username,
+		Password:        hashPassword(req.Password),
+		FirstName:       req.FirstName,
+		LastName:        req.LastName,
+		Role:            "user",
+		IsActive:        true,
+		Locale:          req.Locale,
+		AnalyticsOptOut: req.AnalyticsOptOut,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	r.nextID++
+	r.users = append(r.users, user)
+	return r.issueToken(user)
+}
+
+// Login implements services.AuthService
+func (r *UserRepository) Login(ctx context.Context, req models.LoginRequest) (*models.AuthResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email != req.Email || user.DeletedAt.Valid {
+			continue
+		}
+		if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+			return nil, &services.LoginError{UserID: user.ID, Reason: "invalid_password"}
+		}
+		if !user.IsActive {
+			return nil, &services.LoginError{UserID: user.ID, Reason: "inactive"}
+		}
+		now := time.Now()
+		user.LastLoginAt = &now
+		return r.issueToken(user)
+	}
+	return nil, &services.LoginError{Reason: "unknown_email"}
+}
+
+// Refresh implements services.AuthService; the repository issues no refresh tokens
+func (r *UserRepository) Refresh(ctx context.Context, refreshToken string) (*models.AuthResponse, error) {
+	return nil, services.ErrInvalidRefreshToken
+}
+
+// GetProfile implements services.UserService
+func (r *UserRepository) GetProfile(ctx context.Context, userID string, fields utils.FieldSet) (models.UserInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, err := r.find(userID, false)
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	return user.Info(), nil
+}
+
+// UpdateProfile implements services.UserService
+func (r *UserRepository) UpdateProfile(ctx context.Context, userID string, req models.UpdateUserRequest, ifMatch string) (models.UserInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, err := r.find(userID, false)
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	if ifMatch != "" {
+		etag, err := utils.ETag(user.Info())
+		if err != nil || !utils.ETagMatches(ifMatch, etag) {
+			return models.UserInfo{}, services.ErrPreconditionFailed
+		}
+	}
+	if req.Email != "" {
+		if err := r.checkUnique(user.ID, req.Email, ""); err != nil {
+			return models.UserInfo{}, err
+		}
+		if req.Email != user.Email {
+			user.EmailStatus, user.EmailStatusAt = "", nil
+		}
+		user.Email = req.Email
+	}
+	if req.FirstName != "" {
+		user.FirstName = req.FirstName
+	}
+	if req.LastName != "" {
+		user.LastName = req.LastName
+	}
+	if req.Locale != "" {
+		user.Locale = req.Locale
+	}
+	if req.AnalyticsOptOut != nil {
+		user.AnalyticsOptOut = *req.AnalyticsOptOut
+	}
+	if req.AvatarURL != nil {
+		user.AvatarURL = *req.AvatarURL
+	}
+	if req.Bio != nil {
+		user.Bio = *req.Bio
+	}
+	if req.Phone != nil {
+		user.Phone = *req.Phone
+	}
+	if req.Birthday != nil {
+		user.Birthday = *req.Birthday
+	}
+	if req.Address != nil {
+		user.Address = req.Address
+		if *req.Address == (models.Address{}) {
+			user.Address = nil
+		}
+	}
+	for name, value := range req.Metadata {
+		if user.Metadata == nil {
+			user.Metadata = models.Metadata{}
+		}
+		if value == nil {
+			delete(user.Metadata, name)
+		} else {
+			user.Metadata[name] = value
+		}
+	}
+	if len(user.Metadata) == 0 {
+		user.Metadata = nil
+	}
+	user.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	return user.Info(), nil
+}
+
+// ListUsers implements services.UserService
+func (r *UserRepository) ListUsers(ctx context.Context, query services.ListUsersQuery) ([]models.UserInfo, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	matches := r.matching(query)
+	sortUsers(matches, query.Sort)
+
+	start := min(max(query.Page-1, 0)*query.PageSize, len(matches))
+	end := min(start+query.PageSize, len(matches))
+	return userInfos(matches[start:end]), int64(len(matches)), nil
+}
+
+// ListUsersByCursor implements services.UserService
+func (r *UserRepository) ListUsersByCursor(ctx context.Context, query services.ListUsersQuery) ([]models.UserInfo, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order := append(append([]utils.SortField{}, query.Sort...), utils.SortField{Column: "id"})
+	matches := r.matching(query)
+	total := int64(len(matches))
+
+	before := false
+	if query.Cursor != nil {
+		values, err := query.Cursor.SortValues(query.Sort, utils.UserFilters)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err := strconv.ParseUint(query.Cursor.ID, 10, 63); err != nil {
+			return nil, 0, utils.ErrInvalidCursor
+		}
+		values = append(values, numericID(query.Cursor.ID))
+		before = query.Cursor.Before
+
+		var page []*models.User
+		for _, user := range matches {
+			position := compareKey(user, order, values)
+			if (before && position < 0) || (!before && position > 0) {
+				page = append(page, user)
+			}
+		}
+		matches = page
+	}
+
+	if before {
+		for i := range order {
+			order[i].Descending = !order[i].Descending
+		}
+	}
+	sortUsers(matches, order)
+	return userInfos(matches[:min(query.PageSize+1, len(matches))]), total, nil
+}
+
+// EachUser implements services.UserService; fn is called with copies after the lock is released
+func (r *UserRepository) EachUser(ctx context.Context, query services.ListUsersQuery, fn func(models.UserInfo) error) error {
+	r.mu.Lock()
+	matches := r.matching(query)
+	sortUsers(matches, query.Sort)
+	infos := userInfos(matches)
+	r.mu.Unlock()
+
+	for _, info := range infos {
+		if err := fn(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChangeRole implements services.UserService
+func (r *UserRepository) ChangeRole(ctx context.Context, userID, role string) (models.UserInfo, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, err := r.find(userID, false)
+	if err != nil {
+		return models.UserInfo{}, "", err
+	}
+	previous := user.Role
+	if previous == role {
+		return user.Info(), previous, nil
+	}
+
+	if previous == "superadmin" && user.IsActive && r.lastSuperadmin() {
+		return models.UserInfo{}, "", services.ErrLastSuperadmin
+	}
+
+	user.Role = role
+	user.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	return user.Info(), previous, nil
+}
+
+// SetActive implements services.UserService
+func (r *UserRepository) SetActive(ctx context.Context, userID string, active bool, actorRole string) (models.UserInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, err := r.find(userID, false)
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	if user.IsActive == active {
+		return user.Info(), nil
+	}
+	if user.Role == "superadmin" && !active {
+		if actorRole != "superadmin" {
+			return models.UserInfo{}, services.ErrSuperadminTarget
+		}
+		if r.lastSuperadmin() {
+			return models.UserInfo{}, services.ErrLastSuperadmin
+		}
+	}
+
+	user.IsActive = active
+	user.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	return user.Info(), nil
+}
+
+// lastSuperadmin reports whether at most one active superadmin remains; the caller holds the lock
+func (r *UserRepository) lastSuperadmin() bool {
+	superadmins := 0
+	for _, other := range r.users {
+		if other.Role == "superadmin" && other.IsActive && !other.DeletedAt.Valid {
+			superadmins++
+		}
+	}
+	return superadmins <= 1
+}
+
+// DeleteUser implements services.UserService
+func (r *UserRepository) DeleteUser(ctx context.Context, userID, actorRole string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, err := r.find(userID, false)
+	if err != nil {
+		return err
+	}
+	if user.Role == "superadmin" {
+		if actorRole != "superadmin" {
+			return services.ErrSuperadminTarget
+		}
+		if user.IsActive && r.lastSuperadmin() {
+			return services.ErrLastSuperadmin
+		}
+	}
+	user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return nil
+}
+
+// RestoreUser implements services.UserService
+func (r *UserRepository) RestoreUser(ctx context.Context, userID string) (models.UserInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, err := r.find(userID, true)
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	user.DeletedAt = gorm.DeletedAt{}
+	user.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	return user.Info(), nil
+}
+
+// Stats implements services.StatsService without caching; there are no databases to report
+func (r *UserRepository) Stats(ctx context.Context, days int) (*models.AdminStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	stats := &models.AdminStats{Users: models.UserStats{ByRole: []models.RoleCount{}}, Databases: []models.DatabaseSize{}, GeneratedAt: now}
+	first := today.AddDate(0, 0, 1-days)
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		stats.Signups = append(stats.Signups, models.DailyCount{Date: day.Format(time.DateOnly)})
+	}
+
+	roles := map[string]int64{}
+	for _, user := range r.users {
+		if created := user.CreatedAt.UTC(); !created.Before(first) {
+			if offset := int(created.Sub(first) / (24 * time.Hour)); offset < days {
+				stats.Signups[offset].Count++
+			}
+		}
+		if user.DeletedAt.Valid {
+			stats.Users.Deleted++
+			continue
+		}
+		stats.Users.Total++
+		if user.IsActive {
+			stats.Users.Active++
+		} else {
+			stats.Users.Inactive++
+		}
+		roles[user.Role]++
+		if user.LastLoginAt != nil {
+			since := now.Sub(*user.LastLoginAt)
+			stats.ActiveUsers.Last24h += boolCount(since <= 24*time.Hour)
+			stats.ActiveUsers.Last7d += boolCount(since <= 7*24*time.Hour)
+			stats.ActiveUsers.Last30d += boolCount(since <= 30*24*time.Hour)
+		}
+	}
+	for role, count := range roles {
+		stats.Users.ByRole = append(stats.Users.ByRole, models.RoleCount{Role: role, Count: count})
+	}
+	sort.Slice(stats.Users.ByRole, func(i, j int) bool { return stats.Users.ByRole[i].Role < stats.Users.ByRole[j].Role })
+	return stats, nil
+}
+
+// boolCount is 1 for true and 0 for false
+func boolCount(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// find returns the user with the ID in the given deleted state; callers hold mu
+func (r *UserRepository) find(id string, deleted bool) (*models.User, error) {
+	if _, err := strconv.ParseUint(id, 10, 63); err != nil {
+		return nil, services.ErrInvalidUserID
+	}
+	for _, user := range r.users {
+		if user.ID == id && user.DeletedAt.Valid == deleted {
+			return user, nil
+		}
+	}
+	return nil, services.ErrUserNotFound
+}
+
+// checkUnique rejects an email or username taken by a user other than id; callers hold mu
+func (r *UserRepository) checkUnique(id string, email, username string) error {
+	for _, user := range r.users {
+		if user.ID == id {
+			continue
+		}
+		if user.Email == email {
+			return services.ErrDuplicateEmail
+		}
+		if username != "" && user.Username == username {
+			return services.ErrDuplicateUsername
+		}
+	}
+	return nil
+}
+
+// matching returns the users that are not deleted and pass the query's search and filters; callers hold mu
+func (r *UserRepository) matching(query services.ListUsersQuery) []*models.User {
+	search := strings.ToLower(query.Search)
+	var matches []*models.User
+	for _, user := range r.users {
+		if user.DeletedAt.Valid {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(user.FirstName), search) &&
+			!strings.Contains(strings.ToLower(user.LastName), search) &&
+			!strings.Contains(strings.ToLower(user.Email), search) &&
+			!strings.Contains(strings.ToLower(user.Username), search) {
+			continue
+		}
+		if matchesFilters(user, query.Filters) {
+			matches = append(matches, user)
+		}
+	}
+	return matches
+}
+
+// issueToken signs a token for the user the way the real auth service does; callers hold mu
+func (r *UserRepository) issueToken(user *models.User) (*models.AuthResponse, error) {
+	token, expiresAt, err := jwt.GenerateToken(r.jwtUtils.Secret(), user.ID, user.Email, user.Username, user.Role, user.Locale)
+	if err != nil {
+		return nil, services.ErrTokenGeneration
+	}
+	return &models.AuthResponse{Token: token, User: user.Info(), ExpiresAt: expiresAt}, nil
+}
+
+// matchesFilters reports whether the user passes every filter
+func matchesFilters(user *models.User, filters []utils.Filter) bool {
+	for _, filter := range filters {
+		value := userField(user, filter.Column)
+		if value == nil {
+			// A user without the custom attribute only matches ne, as in the databases
+			if filter.Operator != utils.FilterNe {
+				return false
+			}
+			continue
+		}
+		var ok bool
+		switch filter.Operator {
+		case utils.FilterEq:
+			ok = compareValues(value, filter.Value) == 0
+		case utils.FilterNe:
+			ok = compareValues(value, filter.Value) != 0
+		case utils.FilterGt:
+			ok = compareValues(value, filter.Value) > 0
+		case utils.FilterLt:
+			ok = compareValues(value, filter.Value) < 0
+		case utils.FilterIn:
+			candidates, _ := filter.Value.([]interface{})
+			for _, candidate := range candidates {
+				if compareValues(value, candidate) == 0 {
+					ok = true
+					break
+				}
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sortUsers orders users by the sort fields, breaking ties by ID
+func sortUsers(users []*models.User, order []utils.SortField) {
+	sort.SliceStable(users, func(i, j int) bool {
+		for _, field := range order {
+			c := compareValues(userField(users[i], field.Column), userField(users[j], field.Column))
+			if field.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return numericID(users[i].ID) < numericID(users[j].ID)
+	})
+}
+
+// compareKey compares the user's position in order with a cursor's values
+func compareKey(user *models.User, order []utils.SortField, values []interface{}) int {
+	for i, field := range order {
+		c := compareValues(userField(user, field.Column), values[i])
+		if field.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// userField returns the value of a filterable or sortable column
+func userField(user *models.User, column string) interface{} {
+	switch column {
+	case "id":
+		return numericID(user.ID)
+	case "email":
+		return user.Email
+	case "username":
+		return user.Username
+	case "first_name":
+		return user.FirstName
+	case "last_name":
+		return user.LastName
+	case "role":
+		return user.Role
+	case "locale":
+		return user.Locale
+	case "analytics_opt_out":
+		return user.AnalyticsOptOut
+	case "email_status":
+		return user.EmailStatus
+	case "is_active":
+		return user.IsActive
+	case "created_at":
+		return user.CreatedAt
+	case "updated_at":
+		return user.UpdatedAt
+	}
+	if name, ok := utils.MetadataAttribute(column); ok {
+		return user.Metadata[name]
+	}
+	return nil
+}
+
+// compareValues orders two values of the same column type; false sorts before true
+func compareValues(a, b interface{}) int {
+	switch x := a.(type) {
+	case string:
+		y, _ := b.(string)
+		return strings.Compare(x, y)
+	case uint:
+		y, _ := b.(uint)
+		return cmp.Compare(x, y)
+	case float64:
+		y, _ := b.(float64)
+		return cmp.Compare(x, y)
+	case bool:
+		y, _ := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		default:
+			return 1
+		}
+	case time.Time:
+		y, _ := b.(time.Time)
+		return x.Compare(y)
+	}
+	return 0
+}
+
+// numericID parses an ID the repository assigned; like bigserial keys, IDs sort as numbers
+func numericID(id string) uint {
+	parsed, _ := strconv.ParseUint(id, 10, 63)
+	return uint(parsed)
+}
+
+// userInfos maps a page of users
+func userInfos(users []*models.User) []models.UserInfo {
+	infos := make([]models.UserInfo, len(users))
+	for i, user := range users {
+		infos[i] = user.Info()
+	}
+	return infos
+}
@@ -0,0 +1,166 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+
+	"go-backend-template/models"
+)
+
+// maxListed is the most missing or different users a report names; the counts cover all of them
+const maxListed = 100
+
+// Report describes a copy or a verification of the users of Source in Target
+type Report struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	// Read is the number of users read from Source
+	Read int `json:"read"`
+	// Created and Updated count the users a copy inserted into and overwrote in Target
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	// Missing and Different count the users of Source that Target lacks or holds with other values
+	Missing   int          `json:"missing"`
+	Different int          `json:"different"`
+	Examples  []Difference `json:"examples,omitempty"`
+	// SourceCount and TargetCount are the number of users of each database after the run
+	SourceCount int64 `json:"source_count"`
+	TargetCount int64 `json:"target_count"`
+}
+
+// Difference names a user whose copy is missing or differs, and the fields that differ
+type Difference struct {
+	Email   string   `json:"email"`
+	Missing bool     `json:"missing,omitempty"`
+	Fields  []string `json:"fields,omitempty"`
+}
+
+// Verified reports whether Target holds every user of Source, with the same values, and no others
+func (r *Report) Verified() bool {
+	return r.Missing == 0 && r.Different == 0 && r.SourceCount == r.TargetCount
+}
+
+// WriteText prints the report for a terminal
+func (r *Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "%s -> %s: %d users read", r.Source, r.Target, r.Read)
+	if r.Created > 0 || r.Updated > 0 {
+		fmt.Fprintf(w, ", %d created, %d updated", r.Created, r.Updated)
+	}
+	fmt.Fprintf(w, "\n%s has %d users, %s has %d\n", r.Source, r.SourceCount, r.Target, r.TargetCount)
+	fmt.Fprintf(w, "%d missing, %d different\n", r.Missing, r.Different)
+	for _, example := range r.Examples {
+		if example.Missing {
+			fmt.Fprintf(w, "  %s: missing\n", example.Email)
+		} else {
+			fmt.Fprintf(w, "  %s: %v\n", example.Email, example.Fields)
+		}
+	}
+	if r.Verified() {
+		fmt.Fprintln(w, "Verified: both databases hold the same users")
+	} else {
+		fmt.Fprintln(w, "Not verified")
+	}
+}
+
+// Copy upserts every user of source into target in batches, matching users by email, and then verifies
+// the copy. Running it again brings target up to date.
+func Copy(ctx context.Context, source, target Store, batchSize int) (*Report, error) {
+	report := &Report{Source: source.Name(), Target: target.Name()}
+	err := source.Scan(ctx, max(batchSize, 1), func(users []models.User) error {
+		created, updated, err := target.Upsert(ctx, users)
+		if err != nil {
+			return fmt.Errorf("failed to copy users to %s: %w", target.Name(), err)
+		}
+		report.Created += created
+		report.Updated += updated
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	verification, err := Verify(ctx, source, target, batchSize)
+	if err != nil {
+		return report, err
+	}
+	verification.Created, verification.Updated = report.Created, report.Updated
+	return verification, nil
+}
+
+// Verify compares every user of source with the user of the same email in target, without changing
+// either database
+func Verify(ctx context.Context, source, target Store, batchSize int) (*Report, error) {
+	report := &Report{Source: source.Name(), Target: target.Name()}
+	err := source.Scan(ctx, max(batchSize, 1), func(users []models.User) error {
+		emails := make([]string, len(users))
+		for i, user := range users {
+			emails[i] = user.Email
+		}
+		copies, err := target.Find(ctx, emails)
+		if err != nil {
+			return fmt.Errorf("failed to read users from %s: %w", target.Name(), err)
+		}
+
+		for _, user := range users {
+			report.Read++
+			copied, ok := copies[user.Email]
+			if !ok {
+				report.Missing++
+				report.example(Difference{Email: user.Email, Missing: true})
+			} else if fields := differences(user, copied); len(fields) > 0 {
+				report.Different++
+				report.example(Difference{Email: user.Email, Fields: fields})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if report.SourceCount, err = source.Count(ctx); err != nil {
+		return report, err
+	}
+	if report.TargetCount, err = target.Count(ctx); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// example lists a difference while fewer than maxListed are listed
+func (r *Report) example(difference Difference) {
+	if len(r.Examples) < maxListed {
+		r.Examples = append(r.Examples, difference)
+	}
+}
+
+// differences names the fields in which two copies of a user differ; IDs are not compared, and times
+// are compared to the millisecond
+func differences(a, b models.User) []string {
+	var fields []string
+	compare := func(field string, equal bool) {
+		if !equal {
+			fields = append(fields, field)
+		}
+	}
+	compare("username", a.Username == b.Username)
+	compare("password", a.Password == b.Password)
+	compare("first_name", a.FirstName == b.FirstName)
+	compare("last_name", a.LastName == b.LastName)
+	compare("role", a.Role == b.Role)
+	compare("is_active", a.IsActive == b.IsActive)
+	compare("locale", a.Locale == b.Locale)
+	compare("analytics_opt_out", a.AnalyticsOptOut == b.AnalyticsOptOut)
+	compare("avatar_url", a.AvatarURL == b.AvatarURL)
+	compare("bio", a.Bio == b.Bio)
+	compare("phone", a.Phone == b.Phone)
+	compare("birthday", a.Birthday == b.Birthday)
+	compare("address", reflect.DeepEqual(a.Address, b.Address))
+	compare("metadata", len(a.Metadata) == 0 && len(b.Metadata) == 0 || reflect.DeepEqual(a.Metadata, b.Metadata))
+	compare("email_status", a.EmailStatus == b.EmailStatus)
+	compare("created_at", sameTime(a.CreatedAt, b.CreatedAt))
+	compare("deleted_at", a.DeletedAt.Valid == b.DeletedAt.Valid && sameTime(a.DeletedAt.Time, b.DeletedAt.Time))
+	return fields
+}
@@ -0,0 +1,137 @@
+package transfer
+
+import (
+	"context"
+	"time"
+
+	"go-backend-template/models"
+	"go-backend-template/services"
+	"go-backend-template/utils"
+)
+
+// mirrorTimeout bounds the write to the secondary database after a request succeeded
+const mirrorTimeout = 10 * time.Second
+
+// Mirror copies each user changed through the wrapped services from the primary database to the
+// secondary one, so the secondary stays current while a team switches storage. The primary stays the
+// source of truth: a user that fails to mirror is logged and the request still succeeds; the next Copy
+// reconciles it. Logins and purges are not mirrored.
+type Mirror struct {
+	primary   Store
+	secondary Store
+	logger    utils.Logger
+}
+
+// NewMirror creates a mirror from primary, which the services use, to secondary
+func NewMirror(primary, secondary Store, logger utils.Logger) *Mirror {
+	return &Mirror{primary: primary, secondary: secondary, logger: logger}
+}
+
+// Sync copies the user with the email from the primary database to the secondary one
+func (m *Mirror) Sync(ctx context.Context, email string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mirrorTimeout)
+	defer cancel()
+
+	users, err := m.primary.Find(ctx, []string{email})
+	if err == nil {
+		if user, ok := users[email]; ok {
+			_, _, err = m.secondary.Upsert(ctx, []models.User{user})
+		}
+	}
+	if err != nil {
+		m.logger.Error("Failed to mirror user", "target", m.secondary.Name(), "email", email, "error", err)
+	}
+}
+
+// rename changes the email of the secondary copy before the user is synced under the new one
+func (m *Mirror) rename(ctx context.Context, from, to string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mirrorTimeout)
+	defer cancel()
+
+	if err := m.secondary.Rename(ctx, from, to); err != nil {
+		m.logger.Error("Failed to mirror email change", "target", m.secondary.Name(), "email", to, "error", err)
+	}
+}
+
+// AuthService wraps auth so registrations are mirrored
+func (m *Mirror) AuthService(auth services.AuthService) services.AuthService {
+	return &mirroredAuth{AuthService: auth, mirror: m}
+}
+
+// UserService wraps users so profile, role, and deletion changes are mirrored
+func (m *Mirror) UserService(users services.UserService) services.UserService {
+	return &mirroredUsers{UserService: users, mirror: m}
+}
+
+// mirroredAuth mirrors the accounts an AuthService creates
+type mirroredAuth struct {
+	services.AuthService
+	mirror *Mirror
+}
+
+// Register implements services.AuthService
+func (a *mirroredAuth) Register(ctx context.Context, req models.RegisterRequest) (*models.AuthResponse, error) {
+	response, err := a.AuthService.Register(ctx, req)
+	if err == nil {
+		a.mirror.Sync(ctx, response.User.Email)
+	}
+	return response, err
+}
+
+// mirroredUsers mirrors the users a UserService changes
+type mirroredUsers struct {
+	services.UserService
+	mirror *Mirror
+}
+
+// UpdateProfile implements services.UserService, renaming the secondary copy when the email changes
+func (u *mirroredUsers) UpdateProfile(ctx context.Context, userID string, req models.UpdateUserRequest, ifMatch string) (models.UserInfo, error) {
+	var previous models.UserInfo
+	if req.Email != "" {
+		var err error
+		if previous, err = u.UserService.GetProfile(ctx, userID, nil); err != nil {
+			return models.UserInfo{}, err
+		}
+	}
+
+	userInfo, err := u.UserService.UpdateProfile(ctx, userID, req, ifMatch)
+	if err != nil {
+		return userInfo, err
+	}
+	if previous.Email != "" && previous.Email != userInfo.Email {
+		u.mirror.rename(ctx, previous.Email, userInfo.Email)
+	}
+	u.mirror.Sync(ctx, userInfo.Email)
+	return userInfo, nil
+}
+
+// ChangeRole implements services.UserService
+func (u *mirroredUsers) ChangeRole(ctx context.Context, userID, role string) (models.UserInfo, string, error) {
+	userInfo, previous, err := u.UserService.ChangeRole(ctx, userID, role)
+	if err == nil {
+		u.mirror.Sync(ctx, userInfo.Email)
+	}
+	return userInfo, previous, err
+}
+
+// DeleteUser implements services.UserService
+func (u *mirroredUsers) DeleteUser(ctx context.Context, userID, actorRole string) error {
+	userInfo, err := u.UserService.GetProfile(ctx, userID, nil)
+	if err != nil {
+		return err
+	}
+	if err := u.UserService.DeleteUser(ctx, userID, actorRole); err != nil {
+		return err
+	}
+	u.mirror.Sync(ctx, userInfo.Email)
+	return nil
+}
+
+// RestoreUser implements services.UserService
+func (u *mirroredUsers) RestoreUser(ctx context.Context, userID string) (models.UserInfo, error) {
+	userInfo, err := u.UserService.RestoreUser(ctx, userID)
+	if err == nil {
+		u.mirror.Sync(ctx, userInfo.Email)
+	}
+	return userInfo, err
+}
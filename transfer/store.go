@@ -0,0 +1,313 @@
+// Package transfer moves user accounts between MongoDB and PostgreSQL for teams switching storage: Copy
+// upserts every user of one database into the other, Verify reports where the two differ, and Mirror
+// keeps the second database current while both are in use (dual-write mode). Users are matched by email,
+// as their IDs differ between the databases, so data keyed by user ID, such as posts, is not moved.
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// Store reads and writes the users of one database, including soft-deleted ones
+type Store interface {
+	// Name identifies the database in reports
+	Name() string
+	// Scan calls fn with batches of at most batchSize users, each user once
+	Scan(ctx context.Context, batchSize int, fn func([]models.User) error) error
+	// Find returns the users with the emails, keyed by email
+	Find(ctx context.Context, emails []string) (map[string]models.User, error)
+	// Upsert stores the users by email, keeping their own IDs, and reports how many were new
+	Upsert(ctx context.Context, users []models.User) (created, updated int, err error)
+	// Rename changes the email of a user
+	Rename(ctx context.Context, from, to string) error
+	// Count returns the number of users
+	Count(ctx context.Context) (int64, error)
+}
+
+// NewStore returns the store of the database named postgres or mongodb, which must be connected
+func NewStore(name string, mongoDB *database.MongoDB, postgresDB *database.PostgresDB) (Store, error) {
+	switch name {
+	case "postgres":
+		if postgresDB != nil {
+			return NewPostgresStore(postgresDB), nil
+		}
+	case "mongodb":
+		if mongoDB != nil {
+			return NewMongoStore(mongoDB), nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown database %q; use postgres or mongodb", name)
+	}
+	return nil, fmt.Errorf("%s is not enabled", name)
+}
+
+// PostgresStore is the users table of PostgreSQL or SQLite
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a store on the users table
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Name implements Store
+func (s *PostgresStore) Name() string {
+	if s.db.IsSQLite() {
+		return "sqlite"
+	}
+	return "postgres"
+}
+
+// Scan implements Store, paging by primary key
+func (s *PostgresStore) Scan(ctx context.Context, batchSize int, fn func([]models.User) error) error {
+	var lastID string
+	for {
+		db := s.db.WithContext(ctx).Unscoped()
+		if lastID != "" {
+			db = db.Where("id > ?", lastID)
+		}
+		var users []models.User
+		if err := db.Order("id").Limit(batchSize).Find(&users).Error; err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		if err := fn(users); err != nil {
+			return err
+		}
+		lastID = users[len(users)-1].ID
+	}
+}
+
+// Find implements Store
+func (s *PostgresStore) Find(ctx context.Context, emails []string) (map[string]models.User, error) {
+	var users []models.User
+	if err := s.db.WithContext(ctx).Unscoped().Where("email IN ?", emails).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	found := make(map[string]models.User, len(users))
+	for _, user := range users {
+		found[user.Email] = user
+	}
+	return found, nil
+}
+
+// Upsert implements Store with one INSERT ... ON CONFLICT (email) DO UPDATE
+func (s *PostgresStore) Upsert(ctx context.Context, users []models.User) (int, int, error) {
+	emails := make([]string, len(users))
+	rows := make([]models.User, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+		user.ID = ""
+		rows[i] = user
+	}
+
+	var created, updated int
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Unscoped().Model(&models.User{}).Where("email IN ?", emails).Count(&existing).Error; err != nil {
+			return err
+		}
+		// Select every column so zero values, such as an inactive user, are written instead of the defaults
+		err := tx.Select("*").Omit("id").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "email"}},
+			DoUpdates: clause.AssignmentColumns(copiedColumns),
+		}).Create(&rows).Error
+		if err != nil {
+			return err
+		}
+		updated = int(existing)
+		created = len(rows) - updated
+		return nil
+	})
+	return created, updated, err
+}
+
+// copiedColumns are the users columns an upsert overwrites; the ID and email of the stored user stay
+var copiedColumns = []string{
+	"username", "password", "first_name", "last_name", "role", "is_active", "locale", "analytics_opt_out",
+	"avatar_url", "bio", "phone", "birthday", "address", "metadata", "email_status", "email_status_at",
+	"last_login_at", "created_at", "updated_at", "deleted_at",
+}
+
+// Rename implements Store
+func (s *PostgresStore) Rename(ctx context.Context, from, to string) error {
+	return s.db.WithContext(ctx).Unscoped().Model(&models.User{}).Where("email = ?", from).Update("email", to).Error
+}
+
+// Count implements Store
+func (s *PostgresStore) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Unscoped().Model(&models.User{}).Count(&count).Error
+	return count, err
+}
+
+// MongoStore is the users collection of MongoDB
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a store on the users collection
+func NewMongoStore(db *database.MongoDB) *MongoStore {
+	return &MongoStore{collection: db.Collection("users")}
+}
+
+// Name implements Store
+func (s *MongoStore) Name() string {
+	return "mongodb"
+}
+
+// Scan implements Store in _id order, which follows creation time
+func (s *MongoStore) Scan(ctx context.Context, batchSize int, fn func([]models.User) error) error {
+	cursor, err := s.collection.Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetBatchSize(int32(batchSize)))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	batch := make([]models.User, 0, batchSize)
+	for cursor.Next(ctx) {
+		var user models.UserMongo
+		if err := cursor.Decode(&user); err != nil {
+			return err
+		}
+		batch = append(batch, fromMongo(user))
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// Find implements Store
+func (s *MongoStore) Find(ctx context.Context, emails []string) (map[string]models.User, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{"email": bson.M{"$in": emails}})
+	if err != nil {
+		return nil, err
+	}
+	var users []models.UserMongo
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	found := make(map[string]models.User, len(users))
+	for _, user := range users {
+		found[user.Email] = fromMongo(user)
+	}
+	return found, nil
+}
+
+// Upsert implements Store with one unordered bulk write of replacements
+func (s *MongoStore) Upsert(ctx context.Context, users []models.User) (int, int, error) {
+	writes := make([]mongo.WriteModel, len(users))
+	for i, user := range users {
+		writes[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"email": user.Email}).
+			SetReplacement(toMongo(user)).
+			SetUpsert(true)
+	}
+	result, err := s.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(result.UpsertedCount), int(result.MatchedCount), nil
+}
+
+// Rename implements Store
+func (s *MongoStore) Rename(ctx context.Context, from, to string) error {
+	_, err := s.collection.UpdateOne(ctx, bson.M{"email": from}, bson.M{"$set": bson.M{"email": to}})
+	return err
+}
+
+// Count implements Store
+func (s *MongoStore) Count(ctx context.Context) (int64, error) {
+	return s.collection.CountDocuments(ctx, bson.M{})
+}
+
+// fromMongo converts a MongoDB user, dropping its ID
+func fromMongo(user models.UserMongo) models.User {
+	converted := models.User{
+		Email:           user.Email,
+		Username:        user.Username,
+		Password:        user.Password,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Role:            user.Role,
+		IsActive:        user.IsActive,
+		Locale:          user.Locale,
+		AnalyticsOptOut: user.AnalyticsOptOut,
+		AvatarURL:       user.AvatarURL,
+		Bio:             user.Bio,
+		Phone:           user.Phone,
+		Birthday:        user.Birthday,
+		Address:         user.Address,
+		Metadata:        user.Metadata,
+		EmailStatus:     user.EmailStatus,
+		EmailStatusAt:   user.EmailStatusAt,
+		LastLoginAt:     user.LastLoginAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+	if user.DeletedAt != nil {
+		converted.DeletedAt = gorm.DeletedAt{Time: *user.DeletedAt, Valid: true}
+	}
+	return converted
+}
+
+// toMongo converts a user to a MongoDB document without an ID, so an upsert keeps or assigns one
+func toMongo(user models.User) models.UserMongo {
+	converted := models.UserMongo{
+		Email:           user.Email,
+		Username:        user.Username,
+		Password:        user.Password,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Role:            user.Role,
+		IsActive:        user.IsActive,
+		Locale:          user.Locale,
+		AnalyticsOptOut: user.AnalyticsOptOut,
+		AvatarURL:       user.AvatarURL,
+		Bio:             user.Bio,
+		Phone:           user.Phone,
+		Birthday:        user.Birthday,
+		Address:         user.Address,
+		Metadata:        user.Metadata,
+		EmailStatus:     user.EmailStatus,
+		EmailStatusAt:   user.EmailStatusAt,
+		LastLoginAt:     user.LastLoginAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+	if user.DeletedAt.Valid {
+		deletedAt := user.DeletedAt.Time
+		converted.DeletedAt = &deletedAt
+	}
+	return converted
+}
+
+// sameTime compares times at the millisecond precision MongoDB stores
+func sameTime(a, b time.Time) bool {
+	return a.Truncate(time.Millisecond).Equal(b.Truncate(time.Millisecond))
+}
@@ -0,0 +1,185 @@
+package translations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// ErrUnknownKey is returned for a message key the default language does not define; such texts would
+// never be shown
+var ErrUnknownKey = errors.New("unknown translation key")
+
+// Manager edits the overrides in the store and keeps the localizer in step with them
+type Manager struct {
+	store     Store
+	localizer *utils.Localizer
+	interval  time.Duration
+	logger    utils.Logger
+}
+
+// NewManager creates a manager. Overrides are applied to the localizer by Load; Start reloads them every
+// interval to pick up changes made by other instances.
+func NewManager(store Store, localizer *utils.Localizer, interval time.Duration, logger utils.Logger) *Manager {
+	return &Manager{store: store, localizer: localizer, interval: interval, logger: logger}
+}
+
+// Load reads the overrides from the store and applies them to the localizer
+func (m *Manager) Load(ctx context.Context) error {
+	items, err := m.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load translation overrides: %w", err)
+	}
+	overrides := make(map[string]map[string]string)
+	for _, item := range items {
+		if overrides[item.Language] == nil {
+			overrides[item.Language] = make(map[string]string)
+		}
+		overrides[item.Language][item.Key] = item.Text
+	}
+	m.localizer.SetOverrides(overrides)
+	return nil
+}
+
+// Start reloads the overrides in the background every interval until ctx is canceled; it does nothing
+// when the interval is zero
+func (m *Manager) Start(ctx context.Context) {
+	if m.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.Load(ctx); err != nil && ctx.Err() == nil {
+					m.logger.Error("Failed to sync translation overrides", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Overrides returns the stored overrides, only those of one language when it is set
+func (m *Manager) Overrides(ctx context.Context, language string) ([]models.Translation, error) {
+	items, err := m.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if language == "" {
+		return items, nil
+	}
+	filtered := []models.Translation{}
+	for _, item := range items {
+		if item.Language == language {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+// Missing returns the untranslated keys of every language except the default one, or of one language
+// when it is set
+func (m *Manager) Missing(language string) []models.MissingTranslations {
+	languages := []string{language}
+	if language == "" {
+		languages = m.localizer.Languages()
+	}
+	missing := []models.MissingTranslations{}
+	for _, lang := range languages {
+		if lang == m.localizer.DefaultLanguage {
+			continue
+		}
+		missing = append(missing, models.MissingTranslations{Language: lang, Keys: m.localizer.Missing(lang)})
+	}
+	return missing
+}
+
+// Set overrides the text of one key
+func (m *Manager) Set(ctx context.Context, language, key, text string) (*models.Translation, error) {
+	if err := m.checkKeys([]string{key}); err != nil {
+		return nil, err
+	}
+	translation := models.Translation{Language: language, Key: key, Text: text, UpdatedAt: now()}
+	if err := m.store.Set(ctx, []models.Translation{translation}); err != nil {
+		return nil, err
+	}
+	return &translation, m.Load(ctx)
+}
+
+// Delete removes the override of one key, restoring the text of the files
+func (m *Manager) Delete(ctx context.Context, language, key string) error {
+	if err := m.store.Delete(ctx, language, key); err != nil {
+		return err
+	}
+	return m.Load(ctx)
+}
+
+// Export returns the texts of a language as a flat locale bundle, with the overrides applied
+func (m *Manager) Export(language string) map[string]string {
+	return m.localizer.Bundle(language, false)
+}
+
+// Import stores the texts of a locale bundle that differ from the translation files as overrides. With
+// replace, the existing overrides of the language are removed first, so texts left out of the bundle
+// revert to the files.
+func (m *Manager) Import(ctx context.Context, language string, bundle map[string]string, replace bool) (*models.TranslationImport, error) {
+	keys := make([]string, 0, len(bundle))
+	for key := range bundle {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if err := m.checkKeys(keys); err != nil {
+		return nil, err
+	}
+
+	result := &models.TranslationImport{Language: language}
+	if replace {
+		removed, err := m.store.DeleteLanguage(ctx, language)
+		if err != nil {
+			return nil, err
+		}
+		result.Removed = removed
+	}
+
+	files := m.localizer.Bundle(language, true)
+	updatedAt := now()
+	items := []models.Translation{}
+	for _, key := range keys {
+		if text, ok := files[key]; ok && text == bundle[key] {
+			continue
+		}
+		items = append(items, models.Translation{Language: language, Key: key, Text: bundle[key], UpdatedAt: updatedAt})
+	}
+	if err := m.store.Set(ctx, items); err != nil {
+		return nil, err
+	}
+	result.Imported = len(items)
+	return result, m.Load(ctx)
+}
+
+// checkKeys returns ErrUnknownKey, naming the keys, when the default language does not define some of them
+func (m *Manager) checkKeys(keys []string) error {
+	known := m.localizer.Bundle(m.localizer.DefaultLanguage, false)
+	var unknown []string
+	for _, key := range keys {
+		if _, ok := known[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, strings.Join(unknown, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Package translations lets admins manage message texts at runtime. Overrides are stored in memory,
+// PostgreSQL, or MongoDB and applied to the localizer on top of the translation files.
+package translations
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm/clause"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// ErrNotFound is returned when deleting an override that does not exist
+var ErrNotFound = errors.New("translation not found")
+
+// Store persists translation overrides
+type Store interface {
+	// List returns every override, ordered by language and key
+	List(ctx context.Context) ([]models.Translation, error)
+	// Set creates or replaces the overrides
+	Set(ctx context.Context, translations []models.Translation) error
+	// Delete removes the override of one key
+	Delete(ctx context.Context, language, key string) error
+	// DeleteLanguage removes every override of a language and returns how many there were
+	DeleteLanguage(ctx context.Context, language string) (int64, error)
+}
+
+// MemoryStore is an in-process Store, suitable for single-instance deployments and development
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[[2]string]models.Translation
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: make(map[[2]string]models.Translation)}
+}
+
+// List returns the overrides
+func (s *MemoryStore) List(ctx context.Context) ([]models.Translation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := make([]models.Translation, 0, len(s.items))
+	for _, item := range s.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Language != items[j].Language {
+			return items[i].Language < items[j].Language
+		}
+		return items[i].Key < items[j].Key
+	})
+	return items, nil
+}
+
+// Set stores the overrides
+func (s *MemoryStore) Set(ctx context.Context, translations []models.Translation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, translation := range translations {
+		s.items[[2]string{translation.Language, translation.Key}] = translation
+	}
+	return nil
+}
+
+// Delete removes an override
+func (s *MemoryStore) Delete(ctx context.Context, language, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[[2]string{language, key}]; !ok {
+		return ErrNotFound
+	}
+	delete(s.items, [2]string{language, key})
+	return nil
+}
+
+// DeleteLanguage removes the overrides of a language
+func (s *MemoryStore) DeleteLanguage(ctx context.Context, language string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed int64
+	for id := range s.items {
+		if id[0] == language {
+			delete(s.items, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// PostgresStore persists overrides in PostgreSQL
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the table is created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// List returns the overrides
+func (s *PostgresStore) List(ctx context.Context) ([]models.Translation, error) {
+	var items []models.Translation
+	if err := s.db.WithContext(ctx).Order("language, key").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Set upserts the overrides in one statement
+func (s *PostgresStore) Set(ctx context.Context, translations []models.Translation) error {
+	if len(translations) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "language"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "updated_at"}),
+	}).Create(&translations).Error
+}
+
+// Delete removes an override
+func (s *PostgresStore) Delete(ctx context.Context, language, key string) error {
+	result := s.db.WithContext(ctx).Where("language = ? AND key = ?", language, key).Delete(&models.Translation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteLanguage removes the overrides of a language
+func (s *PostgresStore) DeleteLanguage(ctx context.Context, language string) (int64, error) {
+	result := s.db.WithContext(ctx).Where("language = ?", language).Delete(&models.Translation{})
+	return result.RowsAffected, result.Error
+}
+
+// MongoStore persists overrides in MongoDB
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store and ensures the unique language/key index exists
+func NewMongoStore(ctx context.Context, db *database.MongoDB) (*MongoStore, error) {
+	collection := db.Collection("translations")
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "language", Value: 1}, {Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection}, nil
+}
+
+// List returns the overrides
+func (s *MongoStore) List(ctx context.Context) ([]models.Translation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "language", Value: 1}, {Key: "key", Value: 1}})
+	cursor, err := s.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	items := []models.Translation{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Set upserts the overrides in one bulk write
+func (s *MongoStore) Set(ctx context.Context, translations []models.Translation) error {
+	if len(translations) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, 0, len(translations))
+	for _, translation := range translations {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"language": translation.Language, "key": translation.Key}).
+			SetUpdate(bson.M{"$set": bson.M{"text": translation.Text, "updated_at": translation.UpdatedAt}}).
+			SetUpsert(true))
+	}
+	_, err := s.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// Delete removes an override
+func (s *MongoStore) Delete(ctx context.Context, language, key string) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"language": language, "key": key})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteLanguage removes the overrides of a language
+func (s *MongoStore) DeleteLanguage(ctx context.Context, language string) (int64, error) {
+	result, err := s.collection.DeleteMany(ctx, bson.M{"language": language})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// now is the time stamped on overrides, truncated like the databases store it
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
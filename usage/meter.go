@@ -0,0 +1,134 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-backend-template/billing"
+	"go-backend-template/models"
+)
+
+// Status is a subject's standing against its quota in the current period
+type Status struct {
+	Plan     string
+	Period   string
+	Record   *models.UsageRecord
+	Limit    int64 // 0 means unlimited
+	ResetsAt time.Time
+}
+
+// Exceeded reports whether the subject has used more requests than its plan allows
+func (s Status) Exceeded() bool {
+	return s.Limit > 0 && s.Record.Requests > s.Limit
+}
+
+// Remaining returns the requests left in the period, never negative
+func (s Status) Remaining() int64 {
+	if remaining := s.Limit - s.Record.Requests; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Info converts the status to its API representation
+func (s Status) Info() models.UsageInfo {
+	info := models.UsageInfo{
+		Plan:     s.Plan,
+		Period:   s.Period,
+		Requests: s.Record.Requests,
+		Bytes:    s.Record.Bytes,
+		ResetsAt: s.ResetsAt,
+	}
+	if s.Limit > 0 {
+		limit, remaining := s.Limit, s.Remaining()
+		info.Limit, info.Remaining = &limit, &remaining
+	}
+	return info
+}
+
+// Meter counts usage and resolves each user's monthly request quota from their plan
+type Meter struct {
+	store   Store
+	quotas  map[string]int64
+	billing *billing.Service
+}
+
+// NewMeter creates a meter from "plan=monthly_request_limit" entries; plans without an entry, or with a
+// limit of 0, are unlimited. Without a billing service every user is on the free plan.
+func NewMeter(store Store, quotas []string, billingService *billing.Service) (*Meter, error) {
+	limits := make(map[string]int64, len(quotas))
+	for _, entry := range quotas {
+		plan, value, ok := strings.Cut(entry, "=")
+		limit, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if !ok || err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid quota %q: expected plan=monthly_request_limit", entry)
+		}
+		limits[strings.TrimSpace(plan)] = limit
+	}
+	return &Meter{store: store, quotas: limits, billing: billingService}, nil
+}
+
+// Subject returns the metering key of a user; API keys would use their own prefix
+func Subject(userID string) string {
+	return "user:" + userID
+}
+
+// Track counts one request for subject and returns the updated status
+func (m *Meter) Track(ctx context.Context, subject, userID string) (Status, error) {
+	return m.status(ctx, subject, userID, func(period string) (*models.UsageRecord, error) {
+		return m.store.Add(ctx, subject, period, 1, 0)
+	})
+}
+
+// AddBytes adds transferred bytes to subject's usage without counting a request
+func (m *Meter) AddBytes(ctx context.Context, subject string, bytes int64) error {
+	if bytes <= 0 {
+		return nil
+	}
+	period, _ := currentPeriod(time.Now())
+	_, err := m.store.Add(ctx, subject, period, 0, bytes)
+	return err
+}
+
+// Usage returns subject's status without counting a request
+func (m *Meter) Usage(ctx context.Context, subject, userID string) (Status, error) {
+	return m.status(ctx, subject, userID, func(period string) (*models.UsageRecord, error) {
+		return m.store.Get(ctx, subject, period)
+	})
+}
+
+// status resolves the user's plan and quota around loading the record for the current period
+func (m *Meter) status(ctx context.Context, subject, userID string, load func(period string) (*models.UsageRecord, error)) (Status, error) {
+	plan := billing.FreePlan
+	if m.billing != nil {
+		current, err := m.billing.CurrentPlan(ctx, userID)
+		if err != nil {
+			return Status{}, err
+		}
+		plan = current
+	}
+
+	period, resetsAt := currentPeriod(time.Now())
+	record, err := load(period)
+	if err != nil {
+		return Status{}, err
+	}
+
+	return Status{
+		Plan:     plan,
+		Period:   period,
+		Record:   record,
+		Limit:    m.quotas[plan],
+		ResetsAt: resetsAt,
+	}, nil
+}
+
+// currentPeriod returns the calendar month (UTC) containing now and when the next one starts
+func currentPeriod(now time.Time) (string, time.Time) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01"), start.AddDate(0, 1, 0)
+}
@@ -0,0 +1,163 @@
+// Package usage meters requests per user and enforces plan-based monthly quotas
+package usage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// Store persists usage counters
+type Store interface {
+	// Add atomically adds to the subject's counters for period and returns the new totals
+	Add(ctx context.Context, subject, period string, requests, bytes int64) (*models.UsageRecord, error)
+	// Get returns the subject's counters for period; a subject without usage has zero counters
+	Get(ctx context.Context, subject, period string) (*models.UsageRecord, error)
+}
+
+// MemoryStore is an in-process Store, suitable for single-instance deployments and development
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[[2]string]*models.UsageRecord
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[[2]string]*models.UsageRecord)}
+}
+
+// Add increments the counters
+func (s *MemoryStore) Add(ctx context.Context, subject, period string, requests, bytes int64) (*models.UsageRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := [2]string{subject, period}
+	record, ok := s.records[key]
+	if !ok {
+		// Only the current period is ever written, so older periods can be dropped
+		for existing := range s.records {
+			if existing[1] != period {
+				delete(s.records, existing)
+			}
+		}
+		record = &models.UsageRecord{Subject: subject, Period: period}
+		s.records[key] = record
+	}
+	record.Requests += requests
+	record.Bytes += bytes
+	record.UpdatedAt = time.Now()
+
+	copied := *record
+	return &copied, nil
+}
+
+// Get returns the counters
+func (s *MemoryStore) Get(ctx context.Context, subject, period string) (*models.UsageRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[[2]string{subject, period}]; ok {
+		copied := *record
+		return &copied, nil
+	}
+	return &models.UsageRecord{Subject: subject, Period: period}, nil
+}
+
+// PostgresStore persists usage counters in PostgreSQL
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the table is created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Add upserts the row, incrementing the counters in the database so concurrent requests are not lost
+func (s *PostgresStore) Add(ctx context.Context, subject, period string, requests, bytes int64) (*models.UsageRecord, error) {
+	record := &models.UsageRecord{Subject: subject, Period: period, Requests: requests, Bytes: bytes, UpdatedAt: time.Now()}
+	err := s.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "subject"}, {Name: "period"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"requests":   gorm.Expr("usage_records.requests + EXCLUDED.requests"),
+				"bytes":      gorm.Expr("usage_records.bytes + EXCLUDED.bytes"),
+				"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+			}),
+		},
+		clause.Returning{},
+	).Create(record).Error
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Get returns the counters
+func (s *PostgresStore) Get(ctx context.Context, subject, period string) (*models.UsageRecord, error) {
+	record := &models.UsageRecord{Subject: subject, Period: period}
+	err := s.db.WithContext(ctx).Where("subject = ? AND period = ?", subject, period).Limit(1).Find(record).Error
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// MongoStore persists usage counters in MongoDB
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store and ensures the unique subject/period index exists
+func NewMongoStore(ctx context.Context, db *database.MongoDB) (*MongoStore, error) {
+	collection := db.Collection("usage_records")
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "subject", Value: 1}, {Key: "period", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection}, nil
+}
+
+// Add increments the counters with an upserting $inc
+func (s *MongoStore) Add(ctx context.Context, subject, period string, requests, bytes int64) (*models.UsageRecord, error) {
+	filter := bson.M{"subject": subject, "period": period}
+	update := bson.M{
+		"$inc": bson.M{"requests": requests, "bytes": bytes},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var record models.UsageRecord
+	err := s.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&record)
+	if mongo.IsDuplicateKeyError(err) {
+		// Two first requests raced to insert; the loser's retry updates the winner's document
+		err = s.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&record)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Get returns the counters
+func (s *MongoStore) Get(ctx context.Context, subject, period string) (*models.UsageRecord, error) {
+	record := &models.UsageRecord{Subject: subject, Period: period}
+	err := s.collection.FindOne(ctx, bson.M{"subject": subject, "period": period}).Decode(record)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIPKey is the gin context key holding the resolved client IP
+const ClientIPKey = "client_ip"
+
+// ClientIP returns the real client IP for the request. It prefers the value resolved once by the
+// RealIP middleware and falls back to gin's resolution, which only honors forwarding headers
+// from configured trusted proxies.
+func ClientIP(c *gin.Context) string {
+	if ip := c.GetString(ClientIPKey); ip != "" {
+		return ip
+	}
+	return c.ClientIP()
+}
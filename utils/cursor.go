@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed or were issued for a different sort order
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Cursor is the decoded form of an opaque keyset pagination cursor. It records the sort values and ID
+// of the boundary row; Before selects the page preceding that row instead of the one following it.
+type Cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	ID     string   `json:"i"`
+	Before bool     `json:"b,omitempty"`
+}
+
+// SortKey returns a canonical string for a sort order, used to tie cursors to the order they were issued for
+func SortKey(sort []SortField) string {
+	parts := make([]string, len(sort))
+	for i, field := range sort {
+		direction := "asc"
+		if field.Descending {
+			direction = "desc"
+		}
+		parts[i] = field.Column + ":" + direction
+	}
+	return strings.Join(parts, ",")
+}
+
+// NewCursor builds a cursor pointing at row, reading the sort columns and "id" from its JSON representation
+func NewCursor(row interface{}, sort []SortField, before bool) (Cursor, error) {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return Cursor{}, err
+	}
+
+	cursor := Cursor{Sort: SortKey(sort), Before: before, ID: cursorValue(fields["id"])}
+	for _, field := range sort {
+		cursor.Values = append(cursor.Values, cursorValue(fields[field.Column]))
+	}
+	return cursor, nil
+}
+
+// cursorValue formats a decoded JSON value as its string form
+func cursorValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Encode returns the opaque URL-safe form of the cursor
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses an opaque cursor and checks it was issued for sort
+func DecodeCursor(raw string, sort []SortField) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if cursor.Sort != SortKey(sort) || len(cursor.Values) != len(sort) || cursor.ID == "" {
+		return Cursor{}, fmt.Errorf("%w: it does not match the requested sort order", ErrInvalidCursor)
+	}
+	return cursor, nil
+}
+
+// SortValues converts the cursor's sort values back to typed values using the column kinds
+func (c Cursor) SortValues(sort []SortField, kinds map[string]FilterKind) ([]interface{}, error) {
+	values := make([]interface{}, len(sort))
+	for i, field := range sort {
+		value, err := parseFilterValue(field.Column, c.Values[i], kinds[field.Column])
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values[i] = value
+	}
+	return values, nil
+}
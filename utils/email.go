@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/mail"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// disposableEmailDomains lists common throwaway email providers; BlockEmailDomains adds to it
+var disposableEmailDomains = map[string]bool{
+	"10minutemail.com":  true,
+	"guerrillamail.com": true,
+	"mailinator.com":    true,
+	"maildrop.cc":       true,
+	"sharklasers.com":   true,
+	"temp-mail.org":     true,
+	"tempmail.com":      true,
+	"throwawaymail.com": true,
+	"trashmail.com":     true,
+	"yopmail.com":       true,
+}
+
+var disposableEmailMu sync.RWMutex
+
+// IsValidEmail checks that email is a bare RFC 5322 address that SMTP can deliver to (RFC 5321): a local
+// part of at most 64 octets and a domain name with a top-level domain, 254 octets in all. Quoted local
+// parts and IP address literals are refused, as mail providers do not hand them out.
+func IsValidEmail(email string) bool {
+	if len(email) > 254 {
+		return false
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return false
+	}
+
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], email[at+1:]
+	return len(local) <= 64 && !strings.HasPrefix(local, `"`) && IsValidDomain(domain)
+}
+
+// IsValidDomain checks that domain is a host name of at least two labels, each of letters, digits, and
+// inner hyphens; letters may be non-ASCII for internationalized domains. The top-level domain is not
+// numeric, which rules out IP addresses.
+func IsValidDomain(domain string) bool {
+	if len(domain) > 253 {
+		return false
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return false
+			}
+		}
+	}
+	return strings.IndexFunc(labels[len(labels)-1], unicode.IsLetter) >= 0
+}
+
+// IsDisposableEmail reports whether the email belongs to a blocked disposable provider or a subdomain of one
+func IsDisposableEmail(email string) bool {
+	_, domain, found := strings.Cut(email, "@")
+	if !found {
+		return false
+	}
+
+	disposableEmailMu.RLock()
+	defer disposableEmailMu.RUnlock()
+	for domain = strings.ToLower(domain); domain != ""; {
+		if disposableEmailDomains[domain] {
+			return true
+		}
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+	return false
+}
+
+// BlockEmailDomains adds domains to the disposable-domain blocklist enforced by the notdisposable rule
+func BlockEmailDomains(domains ...string) {
+	disposableEmailMu.Lock()
+	defer disposableEmailMu.Unlock()
+	for _, domain := range domains {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			disposableEmailDomains[domain] = true
+		}
+	}
+}
+
+// LoadEmailBlocklist reads a blocklist file with one domain per line, such as the lists maintained by the
+// disposable-email-domains project; blank lines and lines starting with # are skipped
+func LoadEmailBlocklist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var domains []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			domains = append(domains, line)
+		}
+	}
+	return domains, scanner.Err()
+}
+
+// MXChecker checks in DNS that the domain of an email address accepts mail
+type MXChecker struct {
+	resolver *net.Resolver
+	timeout  time.Duration
+}
+
+// NewMXChecker creates a checker whose lookups time out after timeout
+func NewMXChecker(timeout time.Duration) *MXChecker {
+	return &MXChecker{resolver: net.DefaultResolver, timeout: timeout}
+}
+
+// Deliverable reports whether the domain of email has MX records other than a null MX (RFC 7505), or
+// address records that serve as its implicit MX (RFC 5321 section 5.1). Timeouts and DNS server failures
+// accept the address, so a DNS outage does not block signups.
+func (m *MXChecker) Deliverable(ctx context.Context, email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	records, err := m.resolver.LookupMX(ctx, domain)
+	if err == nil && len(records) > 0 {
+		return !(len(records) == 1 && records[0].Host == ".")
+	}
+	if err != nil && !isNotFound(err) {
+		return true
+	}
+
+	addrs, err := m.resolver.LookupHost(ctx, domain)
+	if err != nil {
+		return !isNotFound(err)
+	}
+	return len(addrs) > 0
+}
+
+// isNotFound reports whether a DNS lookup failed because the name or record does not exist
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// ETag returns a strong entity tag for the JSON representation of data. Only the resource data is
+// hashed, not the localized response envelope, so the tag is stable across languages.
+func ETag(data interface{}) (string, error) {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bytes)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// ETagMatches reports whether etag satisfies an If-Match / If-None-Match header value,
+// which may be "*" or a comma-separated list of tags (weak prefixes are ignored)
+func ETagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return etag != ""
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
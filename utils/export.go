@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Export formats of the streaming admin exports
+const (
+	// ExportNDJSON writes one JSON object per line
+	ExportNDJSON = "ndjson"
+	// ExportCSV writes a header row and one row per record
+	ExportCSV = "csv"
+)
+
+// ExportContentTypes maps each export format to its media type
+var ExportContentTypes = map[string]string{
+	ExportNDJSON: "application/x-ndjson",
+	ExportCSV:    "text/csv; charset=utf-8",
+}
+
+// ExportWriter writes records one at a time in an export format, so an export of any size needs only the
+// memory of one record and the write buffer
+type ExportWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+	csv     *csv.Writer
+	columns []string
+}
+
+// NewExportWriter writes records to w in format. CSV rows hold columns, the JSON names of the record
+// fields, in order; NDJSON writes each record whole.
+func NewExportWriter(w io.Writer, format string, columns []string) (*ExportWriter, error) {
+	buffer := bufio.NewWriter(w)
+	switch format {
+	case ExportNDJSON:
+		return &ExportWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}, nil
+	case ExportCSV:
+		writer := &ExportWriter{buffer: buffer, csv: csv.NewWriter(buffer), columns: columns}
+		return writer, writer.csv.Write(columns)
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// Write adds a record, a value that marshals to a JSON object
+func (w *ExportWriter) Write(record interface{}) error {
+	if w.encoder != nil {
+		return w.encoder.Encode(record)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	row := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		row[i] = csvValue(fields[column])
+	}
+	return w.csv.Write(row)
+}
+
+// Flush writes out the buffered records
+func (w *ExportWriter) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return w.buffer.Flush()
+}
+
+// csvValue renders a JSON value as a CSV cell: strings unquoted, null empty, and objects and arrays as
+// JSON. Text a spreadsheet would run as a formula is prefixed with a quote.
+func csvValue(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return ""
+	}
+	if raw[0] != '"' {
+		return string(raw)
+	}
+
+	var value string
+	if json.Unmarshal(raw, &value) != nil {
+		return string(raw)
+	}
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UserFields lists the user attributes that can be requested with ?fields=; JSON names match column names
+var UserFields = []string{"id", "email", "username", "first_name", "last_name", "role", "is_active", "locale", "analytics_opt_out", "avatar_url", "bio", "phone", "birthday", "address", "metadata", "email_status", "email_status_at", "created_at", "updated_at"}
+
+// FieldSet is a validated sparse fieldset; an empty set means all fields
+type FieldSet []string
+
+// ParseFields parses a comma-separated ?fields= value, rejecting names not in allowed
+func ParseFields(raw string, allowed []string) (FieldSet, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	permitted := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		permitted[name] = true
+	}
+
+	var fields FieldSet
+	seen := map[string]bool{}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if !permitted[name] {
+			return nil, &ParamError{
+				Field:   "fields",
+				Rule:    "oneof",
+				Param:   strings.Join(allowed, " "),
+				Message: fmt.Sprintf("unknown field %q; allowed fields are %s", name, strings.Join(allowed, ", ")),
+			}
+		}
+		seen[name] = true
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// Columns returns the database columns to select, always including the primary key under idColumn.
+// extra names columns the query needs regardless of the fieldset, such as keyset sort columns.
+func (f FieldSet) Columns(idColumn string, extra ...string) []string {
+	columns := []string{idColumn}
+	seen := map[string]bool{"id": true, idColumn: true}
+	for _, name := range append(append([]string{}, f...), extra...) {
+		if !seen[name] {
+			seen[name] = true
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+// Project reduces data (a struct or slice of structs) to the requested fields; data is returned
+// unchanged when the set is empty
+func (f FieldSet) Project(data interface{}) interface{} {
+	if len(f) == 0 {
+		return data
+	}
+
+	keep := make(map[string]bool, len(f))
+	for _, name := range f {
+		keep[name] = true
+	}
+
+	var project func(value interface{}) interface{}
+	project = func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key := range v {
+				if !keep[key] {
+					delete(v, key)
+				}
+			}
+			return v
+		case []interface{}:
+			for i, item := range v {
+				v[i] = project(item)
+			}
+			return v
+		default:
+			return v
+		}
+	}
+
+	return project(toGeneric(data))
+}
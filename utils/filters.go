@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterKind is the value type of a filterable field
+type FilterKind int
+
+const (
+	FilterString FilterKind = iota
+	FilterBool
+	FilterTime
+	FilterNumber
+)
+
+// Filter operators
+const (
+	FilterEq = "eq"
+	FilterNe = "ne"
+	FilterGt = "gt"
+	FilterLt = "lt"
+	FilterIn = "in"
+)
+
+// UserFilters is the allowlist of user fields that GET /users may filter on, keyed by column name
+var UserFilters = map[string]FilterKind{
+	"email":             FilterString,
+	"username":          FilterString,
+	"first_name":        FilterString,
+	"last_name":         FilterString,
+	"role":              FilterString,
+	"locale":            FilterString,
+	"is_active":         FilterBool,
+	"analytics_opt_out": FilterBool,
+	"email_status":      FilterString,
+	"created_at":        FilterTime,
+	"updated_at":        FilterTime,
+}
+
+// filterAliases maps shorthand query parameters to a column and operator
+var filterAliases = map[string][2]string{
+	"created_after":  {"created_at", FilterGt},
+	"created_before": {"created_at", FilterLt},
+	"updated_after":  {"updated_at", FilterGt},
+	"updated_before": {"updated_at", FilterLt},
+}
+
+// Filter is a single typed condition; Value is a []interface{} for the in operator
+type Filter struct {
+	Column   string
+	Operator string
+	Value    interface{}
+}
+
+// ParseFilters reads conditions of the form field=value or field[op]=value (op is eq, ne, gt, lt, or in,
+// with comma-separated values for in). Column names are only ever taken from allowed, so they are safe
+// to interpolate into queries. Parameters that are not filters, such as page or sort, are ignored.
+func ParseFilters(query url.Values, allowed map[string]FilterKind) ([]Filter, error) {
+	var filters []Filter
+	for key, values := range query {
+		column, operator := key, FilterEq
+		if alias, ok := filterAliases[key]; ok {
+			column, operator = alias[0], alias[1]
+		} else if open := strings.Index(key, "["); open > 0 && strings.HasSuffix(key, "]") {
+			column, operator = key[:open], strings.ToLower(key[open+1:len(key)-1])
+			if _, ok := allowed[column]; !ok {
+				return nil, &ParamError{Field: column, Rule: "filter", Message: fmt.Sprintf("field %q cannot be filtered", column)}
+			}
+		}
+
+		kind, ok := allowed[column]
+		if !ok {
+			continue
+		}
+
+		for _, raw := range values {
+			filter, err := parseFilter(column, operator, raw, kind)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, filter)
+		}
+	}
+	return filters, nil
+}
+
+// parseFilter converts a raw value to the field's type and checks the operator applies to it
+func parseFilter(column, operator, raw string, kind FilterKind) (Filter, error) {
+	switch operator {
+	case FilterEq, FilterNe, FilterIn:
+	case FilterGt, FilterLt:
+		if kind == FilterBool {
+			return Filter{}, &ParamError{
+				Field:   column,
+				Rule:    "operator",
+				Param:   operator,
+				Message: fmt.Sprintf("operator %q is not supported for boolean field %q", operator, column),
+			}
+		}
+	default:
+		return Filter{}, &ParamError{
+			Field:   column,
+			Rule:    "operator",
+			Param:   operator,
+			Message: fmt.Sprintf("unknown filter operator %q; use eq, ne, gt, lt, or in", operator),
+		}
+	}
+
+	if operator == FilterIn {
+		var items []interface{}
+		for _, part := range strings.Split(raw, ",") {
+			value, err := parseFilterValue(column, strings.TrimSpace(part), kind)
+			if err != nil {
+				return Filter{}, err
+			}
+			items = append(items, value)
+		}
+		return Filter{Column: column, Operator: operator, Value: items}, nil
+	}
+
+	value, err := parseFilterValue(column, raw, kind)
+	if err != nil {
+		return Filter{}, err
+	}
+	return Filter{Column: column, Operator: operator, Value: value}, nil
+}
+
+// parseFilterValue parses raw as a string, boolean, number, or RFC 3339 / YYYY-MM-DD time
+func parseFilterValue(column, raw string, kind FilterKind) (interface{}, error) {
+	switch kind {
+	case FilterBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, &ParamError{Field: column, Rule: "boolean", Message: fmt.Sprintf("%s: %q is not a valid boolean", column, raw)}
+		}
+		return value, nil
+	case FilterNumber:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, &ParamError{Field: column, Rule: "number", Message: fmt.Sprintf("%s: %q is not a valid number", column, raw)}
+		}
+		return value, nil
+	case FilterTime:
+		if value, err := time.Parse(time.RFC3339, raw); err == nil {
+			return value, nil
+		}
+		value, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, &ParamError{Field: column, Rule: "date", Message: fmt.Sprintf("%s: %q is not a valid date (use YYYY-MM-DD or RFC 3339)", column, raw)}
+		}
+		return value, nil
+	default:
+		return raw, nil
+	}
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// languageRange is one entry of an Accept-Language header
+type languageRange struct {
+	tag string
+	q   float64
+}
+
+// MatchLanguage picks the language for an Accept-Language header with the lookup scheme of RFC 4647: the
+// ranges are tried by descending quality (q=0 excludes a range), and each is progressively truncated
+// (zh-hant-tw, zh-hant, zh) until it equals a supported tag. Matching ignores case; the supported tag is
+// returned as given, or fallback when nothing matches.
+func MatchLanguage(acceptLanguage string, supported []string, fallback string) string {
+	for _, r := range parseAcceptLanguage(acceptLanguage) {
+		for tag := r.tag; tag != ""; tag = truncateTag(tag) {
+			for _, candidate := range supported {
+				if strings.EqualFold(candidate, tag) {
+					return candidate
+				}
+			}
+		}
+	}
+	return fallback
+}
+
+// parseAcceptLanguage returns the acceptable ranges of an Accept-Language header, highest quality first
+// and in header order among equal qualities. The wildcard is dropped, since lookup falls back to the
+// default anyway.
+func parseAcceptLanguage(header string) []languageRange {
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := parseQuality(value); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, languageRange{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	return ranges
+}
+
+// truncateTag removes the last subtag of a language tag, and a single-character subtag (such as the
+// extension marker in de-a-x) left in front of it; it returns "" for a primary tag
+func truncateTag(tag string) string {
+	i := strings.LastIndexAny(tag, "-_")
+	if i < 0 {
+		return ""
+	}
+	tag = tag[:i]
+	if j := strings.LastIndexAny(tag, "-_"); j >= 0 && len(tag)-j == 2 {
+		tag = tag[:j]
+	}
+	return tag
+}
@@ -0,0 +1,364 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"go-backend-template/locales"
+	"go-backend-template/models"
+)
+
+// LocalizerOptions configures a Localizer
+type LocalizerOptions struct {
+	DefaultLanguage string
+	// Supported lists the language tags requests can select; empty allows every loaded language
+	Supported []string
+	// Dir holds translation files in the layout of the locales package. They are loaded after the embedded
+	// ones: a new language code adds a language, and keys of an existing one override its embedded text.
+	Dir string
+	// ReloadInterval is how often Start checks Dir for changed files; zero disables hot reload
+	ReloadInterval time.Duration
+	// Logger reports reloads and reload failures; nil discards them
+	Logger Logger
+}
+
+// Localizer handles internationalization
+type Localizer struct {
+	DefaultLanguage string
+
+	supported      []string
+	dir            string
+	reloadInterval time.Duration
+	logger         Logger
+
+	mu           sync.RWMutex
+	files        map[string]map[string]string
+	overrides    map[string]map[string]string
+	translations map[string]map[string]string
+}
+
+// NewLocalizer loads the embedded translations and those in opts.Dir. The default language must have
+// translations.
+func NewLocalizer(opts LocalizerOptions) (*Localizer, error) {
+	localizer := &Localizer{
+		DefaultLanguage: strings.ToLower(opts.DefaultLanguage),
+		dir:             opts.Dir,
+		reloadInterval:  opts.ReloadInterval,
+		logger:          opts.Logger,
+	}
+	for _, tag := range opts.Supported {
+		localizer.supported = append(localizer.supported, strings.ToLower(tag))
+	}
+
+	// Load translations
+	if err := localizer.Reload(); err != nil {
+		return nil, err
+	}
+
+	return localizer, nil
+}
+
+// Reload reads the translation files again. On error the current translations are kept.
+func (l *Localizer) Reload() error {
+	translations := make(map[string]map[string]string)
+	if err := loadTranslations(locales.FS, "embedded locales", translations); err != nil {
+		return err
+	}
+	if l.dir != "" {
+		if err := loadTranslations(os.DirFS(l.dir), l.dir, translations); err != nil {
+			return err
+		}
+	}
+	if _, ok := translations[l.DefaultLanguage]; !ok {
+		return fmt.Errorf("no translations for the default language %q", l.DefaultLanguage)
+	}
+
+	l.mu.Lock()
+	l.files = translations
+	l.translations = mergeTranslations(l.files, l.overrides)
+	l.mu.Unlock()
+	return nil
+}
+
+// SetOverrides replaces the translations that take precedence over the files, such as those edited at
+// runtime, keyed by language and message key
+func (l *Localizer) SetOverrides(overrides map[string]map[string]string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.overrides = overrides
+	l.translations = mergeTranslations(l.files, l.overrides)
+}
+
+// mergeTranslations copies files and applies overrides on top
+func mergeTranslations(files, overrides map[string]map[string]string) map[string]map[string]string {
+	merged := make(map[string]map[string]string, len(files)+len(overrides))
+	for _, layer := range []map[string]map[string]string{files, overrides} {
+		for lang, texts := range layer {
+			if merged[lang] == nil {
+				merged[lang] = make(map[string]string, len(texts))
+			}
+			for key, text := range texts {
+				merged[lang][key] = text
+			}
+		}
+	}
+	return merged
+}
+
+// Start reloads the translations in the background when the files in Dir change, checking every
+// ReloadInterval until ctx is canceled; it does nothing without a Dir or an interval
+func (l *Localizer) Start(ctx context.Context) {
+	if l.dir == "" || l.reloadInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(l.reloadInterval)
+		defer ticker.Stop()
+
+		last := l.fingerprint()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current := l.fingerprint()
+				if current == last {
+					continue
+				}
+				last = current
+				if err := l.Reload(); err != nil {
+					if l.logger != nil {
+						l.logger.Error("Failed to reload translations", "dir", l.dir, "error", err)
+					}
+					continue
+				}
+				if l.logger != nil {
+					l.logger.Info("Reloaded translations", "dir", l.dir, "languages", l.Languages())
+				}
+			}
+		}
+	}()
+}
+
+// fingerprint summarizes the names, sizes, and modification times of the files in Dir
+func (l *Localizer) fingerprint() string {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return err.Error()
+	}
+	var b strings.Builder
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
+
+// Languages returns the codes of the loaded languages in alphabetical order
+func (l *Localizer) Languages() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	languages := make([]string, 0, len(l.translations))
+	for lang := range l.translations {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// SupportedLanguages returns the languages requests can select: the configured ones, or every loaded one
+func (l *Localizer) SupportedLanguages() []string {
+	if len(l.supported) > 0 {
+		return l.supported
+	}
+	return l.Languages()
+}
+
+// Match returns the supported language that best matches an Accept-Language header, or the default
+// language when none does
+func (l *Localizer) Match(acceptLanguage string) string {
+	return MatchLanguage(acceptLanguage, l.SupportedLanguages(), l.DefaultLanguage)
+}
+
+// Lookup returns the supported language a single language tag selects, falling back from regional tags
+// (pt-br to pt), and whether there is one
+func (l *Localizer) Lookup(tag string) (string, bool) {
+	lang := MatchLanguage(tag, l.SupportedLanguages(), "")
+	return lang, lang != ""
+}
+
+// LanguageInfo describes a language with the language.name and language.direction texts of its
+// translations, without falling back to the default language: the code stands in for a missing name, and
+// the direction is ltr unless it is rtl
+func (l *Localizer) LanguageInfo(lang string) models.LanguageInfo {
+	lang = strings.ToLower(lang)
+	info := models.LanguageInfo{Code: lang, Default: lang == l.DefaultLanguage}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var name, direction string
+	for tag := lang; tag != ""; tag = truncateTag(tag) {
+		if name == "" {
+			name = l.translations[tag]["language.name"]
+		}
+		if direction == "" {
+			direction = l.translations[tag]["language.direction"]
+		}
+	}
+
+	info.Name, info.Direction = name, "ltr"
+	if name == "" {
+		info.Name = lang
+	}
+	if strings.EqualFold(direction, "rtl") {
+		info.Direction = "rtl"
+	}
+	return info
+}
+
+// Bundle returns a copy of the translations of one language, with the overrides applied unless
+// filesOnly is set; it is empty for a language without translations
+func (l *Localizer) Bundle(lang string, filesOnly bool) map[string]string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	source := l.translations
+	if filesOnly {
+		source = l.files
+	}
+	bundle := make(map[string]string, len(source[strings.ToLower(lang)]))
+	for key, text := range source[strings.ToLower(lang)] {
+		bundle[key] = text
+	}
+	return bundle
+}
+
+// Missing returns the keys of the default language that a language does not translate, in alphabetical
+// order; a regional language counts the translations of its parent (pt-br those of pt)
+func (l *Localizer) Missing(lang string) []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	missing := []string{}
+	for key := range l.translations[l.DefaultLanguage] {
+		found := false
+		for tag := strings.ToLower(lang); tag != "" && !found; tag = truncateTag(tag) {
+			_, found = l.translations[tag][key]
+		}
+		if !found {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Get returns translated text for the given key and language. A regional language falls back to its
+// parent (pt-br to pt), then to the default language.
+func (l *Localizer) Get(lang, key string) string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for tag := strings.ToLower(lang); tag != ""; tag = truncateTag(tag) {
+		if translations, exists := l.translations[tag]; exists {
+			if text, exists := translations[key]; exists {
+				return text
+			}
+		}
+	}
+
+	// Fallback to default language
+	if translations, exists := l.translations[l.DefaultLanguage]; exists {
+		if text, exists := translations[key]; exists {
+			return text
+		}
+	}
+
+	// Return key if no translation found
+	return key
+}
+
+// loadTranslations merges the .json and .toml files at the root of fsys into translations, in file name
+// order; source names fsys in errors
+func loadTranslations(fsys fs.FS, source string, translations map[string]map[string]string) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("failed to read translations from %s: %w", source, err)
+	}
+
+	for _, entry := range entries {
+		ext := strings.ToLower(path.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read translations %s/%s: %w", source, entry.Name(), err)
+		}
+
+		raw := map[string]interface{}{}
+		if ext == ".toml" {
+			err = toml.Unmarshal(data, &raw)
+		} else {
+			err = json.Unmarshal(data, &raw)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse translations %s/%s: %w", source, entry.Name(), err)
+		}
+
+		lang := strings.ToLower(strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+		if translations[lang] == nil {
+			translations[lang] = make(map[string]string)
+		}
+		if err := flattenTranslations("", raw, translations[lang]); err != nil {
+			return fmt.Errorf("invalid translations %s/%s: %w", source, entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// FlattenTranslations converts a decoded translation file, whose nested objects are joined with dots, to
+// a map of message keys to text
+func FlattenTranslations(raw map[string]interface{}) (map[string]string, error) {
+	flat := make(map[string]string, len(raw))
+	if err := flattenTranslations("", raw, flat); err != nil {
+		return nil, err
+	}
+	return flat, nil
+}
+
+// flattenTranslations joins the keys of nested objects with dots
+func flattenTranslations(prefix string, raw map[string]interface{}, into map[string]string) error {
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case string:
+			into[key] = v
+		case map[string]interface{}:
+			if err := flattenTranslations(key, v, into); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: value must be a string", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MetadataPrefix starts the names under which custom user attributes are filtered, as in metadata.plan=pro
+const MetadataPrefix = "metadata."
+
+// MaxMetadataString is the most characters a string attribute may hold
+const MaxMetadataString = 500
+
+// metadataNamePattern matches attribute names; they end up in JSON paths of queries, so they stay plain
+var metadataNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// metadataKinds maps the type names of a schema entry to the value kind
+var metadataKinds = map[string]FilterKind{
+	"string":  FilterString,
+	"number":  FilterNumber,
+	"boolean": FilterBool,
+}
+
+// MetadataSchema maps the custom attributes users may store to their value kind. An empty schema allows
+// no attributes.
+type MetadataSchema map[string]FilterKind
+
+// ParseMetadataSchema parses name=type entries such as plan=string, where type is string, number, or
+// boolean and name is lower-case letters, digits, and underscores
+func ParseMetadataSchema(entries []string) (MetadataSchema, error) {
+	schema := make(MetadataSchema, len(entries))
+	for _, entry := range entries {
+		name, kindName, _ := strings.Cut(entry, "=")
+		kind, ok := metadataKinds[strings.ToLower(strings.TrimSpace(kindName))]
+		name = strings.TrimSpace(name)
+		if !ok || !metadataNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid metadata attribute %q; use name=string, name=number, or name=boolean", entry)
+		}
+		if _, ok := schema[name]; ok {
+			return nil, fmt.Errorf("metadata attribute %q is defined more than once", name)
+		}
+		schema[name] = kind
+	}
+	return schema, nil
+}
+
+// Validate checks that every attribute of metadata is in the schema and has its type; a null value, which
+// removes the attribute, is allowed for any of them
+func (s MetadataSchema) Validate(metadata map[string]interface{}) error {
+	for name, value := range metadata {
+		field := MetadataPrefix + name
+		kind, ok := s[name]
+		if !ok {
+			return &ParamError{Field: field, Rule: "metadata", Message: fmt.Sprintf("%q is not a defined metadata attribute", name)}
+		}
+
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			ok = kind == FilterString
+			if ok && utf8.RuneCountInString(v) > MaxMetadataString {
+				return &ParamError{
+					Field:   field,
+					Rule:    "max",
+					Param:   fmt.Sprint(MaxMetadataString),
+					Message: fmt.Sprintf("%s must be at most %d characters", field, MaxMetadataString),
+				}
+			}
+		case float64:
+			ok = kind == FilterNumber
+		case bool:
+			ok = kind == FilterBool
+		default:
+			ok = false
+		}
+		if !ok {
+			return &ParamError{Field: field, Rule: "type", Message: fmt.Sprintf("%s has an invalid type", field)}
+		}
+	}
+	return nil
+}
+
+// Filters returns allowed extended with each attribute as a metadata.<name> filter
+func (s MetadataSchema) Filters(allowed map[string]FilterKind) map[string]FilterKind {
+	if len(s) == 0 {
+		return allowed
+	}
+	filters := make(map[string]FilterKind, len(allowed)+len(s))
+	for column, kind := range allowed {
+		filters[column] = kind
+	}
+	for name, kind := range s {
+		filters[MetadataPrefix+name] = kind
+	}
+	return filters
+}
+
+// MetadataAttribute returns the attribute name of a metadata.<name> filter column
+func MetadataAttribute(column string) (string, bool) {
+	return strings.CutPrefix(column, MetadataPrefix)
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+
+	"go-backend-template/models"
+)
+
+// NegotiationDisabledKey marks routes that always respond with JSON regardless of Accept
+const NegotiationDisabledKey = "negotiation_disabled"
+
+// ProblemDetailsKey enables RFC 7807 problem+json error responses for every client
+const ProblemDetailsKey = "problem_details"
+
+// Supported response media types
+const (
+	MIMEJSON    = "application/json"
+	MIMEXML     = "application/xml"
+	MIMEMsgPack = "application/msgpack"
+	MIMEProblem = "application/problem+json"
+)
+
+// Respond writes response in the format requested by the Accept header (JSON, XML, or
+// MessagePack), falling back to JSON for unknown types or routes that opted out
+func (r *ResponseUtils) Respond(c *gin.Context, status int, response interface{}) {
+	if apiResponse, ok := response.(models.APIResponse); ok && !apiResponse.Success && wantsProblemDetails(c) {
+		c.Header("Content-Type", MIMEProblem)
+		c.Render(status, render.JSON{Data: r.ProblemDetails(c, status, apiResponse)})
+		return
+	}
+
+	if c.GetBool(NegotiationDisabledKey) {
+		c.JSON(status, response)
+		return
+	}
+
+	switch NegotiateFormat(c.GetHeader("Accept")) {
+	case MIMEXML:
+		c.Render(status, render.XML{Data: xmlDocument{value: toGeneric(response)}})
+	case MIMEMsgPack:
+		c.Render(status, render.MsgPack{Data: toGeneric(response)})
+	default:
+		c.JSON(status, response)
+	}
+}
+
+// ProblemDetails converts an error APIResponse into an RFC 7807 document
+func (r *ResponseUtils) ProblemDetails(c *gin.Context, status int, response models.APIResponse) models.ProblemDetails {
+	return models.ProblemDetails{
+		Type:      "about:blank",
+		Title:     response.Message,
+		Status:    status,
+		Detail:    response.Error,
+		Instance:  c.Request.URL.Path,
+		Code:      response.Code,
+		RequestID: Scope(c).RequestID,
+		Errors:    response.Errors,
+	}
+}
+
+// wantsProblemDetails reports whether problem+json is enabled by configuration or requested via Accept
+func wantsProblemDetails(c *gin.Context) bool {
+	if c.GetBool(ProblemDetailsKey) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(c.GetHeader("Accept")), MIMEProblem)
+}
+
+// NegotiateFormat picks the best supported media type from an Accept header, honoring q-values
+func NegotiateFormat(accept string) string {
+	best, bestQ := MIMEJSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := parseQuality(value); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		var format string
+		switch mediaType {
+		case "application/json", "*/*", "application/*":
+			format = MIMEJSON
+		case "application/xml", "text/xml":
+			format = MIMEXML
+		case "application/msgpack", "application/x-msgpack", "application/vnd.msgpack":
+			format = MIMEMsgPack
+		default:
+			continue
+		}
+
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	return best
+}
+
+func parseQuality(value string) (float64, error) {
+	var q float64
+	err := json.Unmarshal([]byte(value), &q)
+	return q, err
+}
+
+// toGeneric converts a value to maps/slices/scalars using its JSON representation, so
+// XML and MessagePack output use the same field names as the JSON API
+func toGeneric(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return value
+	}
+	return generic
+}
+
+// xmlDocument renders a generic value as <response>…</response>; arrays become repeated <item> elements
+type xmlDocument struct {
+	value interface{}
+}
+
+// MarshalXML implements xml.Marshaler
+func (d xmlDocument) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXMLElement(e, "response", d.value)
+}
+
+func encodeXMLElement(e *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := encodeXMLElement(e, key, v[key]); err != nil {
+				return err
+			}
+		}
+		return e.EncodeToken(start.End())
+	case []interface{}:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err := encodeXMLElement(e, "item", item); err != nil {
+				return err
+			}
+		}
+		return e.EncodeToken(start.End())
+	case nil:
+		return e.EncodeElement("", start)
+	default:
+		return e.EncodeElement(v, start)
+	}
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestScope is what a request carries through its context: its ID, the response language, the
+// authenticated user, its deadline, and a logger that tags every line with the request ID. The middleware
+// fills it in as they run, so code below the handlers, such as services and jobs, reads it from a plain
+// context.Context without depending on gin. Logger is nil until the RequestID middleware has run; use
+// LoggerFrom to fall back to another logger.
+type RequestScope struct {
+	RequestID string
+	Language  string
+	// UserID, Email, Username, and Role are the claims of the token, empty for anonymous requests, and
+	// SessionID the session it was issued for, if any
+	UserID    string
+	Email     string
+	Username  string
+	Role      string
+	SessionID string
+	// Deadline is when the request times out, zero for routes without a deadline
+	Deadline time.Time
+	Logger   Logger
+}
+
+type scopeKey struct{}
+
+// WithScope returns a copy of ctx carrying scope
+func WithScope(ctx context.Context, scope *RequestScope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFrom returns the scope carried by ctx, or an empty scope when ctx does not belong to a request,
+// such as in background jobs; it never returns nil
+func ScopeFrom(ctx context.Context) *RequestScope {
+	if scope, ok := ctx.Value(scopeKey{}).(*RequestScope); ok {
+		return scope
+	}
+	return &RequestScope{}
+}
+
+// LoggerFrom returns the logger of the request ctx belongs to, or fallback outside requests
+func LoggerFrom(ctx context.Context, fallback Logger) Logger {
+	if logger := ScopeFrom(ctx).Logger; logger != nil {
+		return logger
+	}
+	return fallback
+}
+
+// Scope returns the scope of the request, attaching one built from the gin context keys (request_id,
+// language, user_id, user_email, user_username, and user_role) when the middleware have not
+func Scope(c *gin.Context) *RequestScope {
+	if scope, ok := c.Request.Context().Value(scopeKey{}).(*RequestScope); ok {
+		return scope
+	}
+	scope := &RequestScope{
+		RequestID: c.GetString("request_id"),
+		Language:  c.GetString("language"),
+		UserID:    c.GetString("user_id"),
+		Email:     c.GetString("user_email"),
+		Username:  c.GetString("user_username"),
+		Role:      c.GetString("user_role"),
+	}
+	c.Request = c.Request.WithContext(WithScope(c.Request.Context(), scope))
+	return scope
+}
+
+// SetLanguage sets the response language of the request in its scope and the language context key
+func SetLanguage(c *gin.Context, lang string) {
+	c.Set("language", lang)
+	Scope(c).Language = lang
+}
+
+// SetUser marks the request as authenticated as a user, in its scope and the user_* context keys
+func SetUser(c *gin.Context, userID, email, username, role string) {
+	c.Set("user_id", userID)
+	c.Set("user_email", email)
+	c.Set("user_username", username)
+	c.Set("user_role", role)
+
+	scope := Scope(c)
+	scope.UserID, scope.Email, scope.Username, scope.Role = userID, email, username, role
+}
+
+// WithFields returns a logger that adds the key-value pairs of args to every line of logger
+func WithFields(logger Logger, args ...interface{}) Logger {
+	return fieldLogger{logger: logger, fields: args}
+}
+
+// fieldLogger prepends fixed key-value pairs to the arguments of every line
+type fieldLogger struct {
+	logger Logger
+	fields []interface{}
+}
+
+func (l fieldLogger) with(args []interface{}) []interface{} {
+	return append(append(make([]interface{}, 0, len(l.fields)+len(args)), l.fields...), args...)
+}
+
+func (l fieldLogger) Info(msg string, args ...interface{})  { l.logger.Info(msg, l.with(args)...) }
+func (l fieldLogger) Error(msg string, args ...interface{}) { l.logger.Error(msg, l.with(args)...) }
+func (l fieldLogger) Warn(msg string, args ...interface{})  { l.logger.Warn(msg, l.with(args)...) }
+func (l fieldLogger) Debug(msg string, args ...interface{}) { l.logger.Debug(msg, l.with(args)...) }
+func (l fieldLogger) Fatal(msg string, args ...interface{}) { l.logger.Fatal(msg, l.with(args)...) }
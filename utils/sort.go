@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UserSortFields is the allowlist of columns GET /users may sort by
+var UserSortFields = []string{"email", "username", "first_name", "last_name", "role", "is_active", "created_at", "updated_at"}
+
+// DefaultUserSort orders users newest first
+var DefaultUserSort = []SortField{{Column: "created_at", Descending: true}}
+
+// SortField is a single validated sort column
+type SortField struct {
+	Column     string
+	Descending bool
+}
+
+// ParseSort parses a comma-separated list of column[:asc|desc] pairs such as "role:asc,created_at:desc".
+// Columns are only ever taken from allowed, so they are safe to pass to the query builders. An empty
+// value yields defaults.
+func ParseSort(raw string, allowed []string, defaults []SortField) ([]SortField, error) {
+	if strings.TrimSpace(raw) == "" {
+		return defaults, nil
+	}
+
+	permitted := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		permitted[name] = true
+	}
+
+	var fields []SortField
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		column, direction, _ := strings.Cut(part, ":")
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !permitted[column] {
+			return nil, &ParamError{
+				Field:   "sort",
+				Rule:    "oneof",
+				Param:   strings.Join(allowed, " "),
+				Message: fmt.Sprintf("cannot sort by %q; allowed fields are %s", column, strings.Join(allowed, ", ")),
+			}
+		}
+		if seen[column] {
+			return nil, &ParamError{Field: "sort", Rule: "unique", Param: column, Message: fmt.Sprintf("sort field %q is listed more than once", column)}
+		}
+		seen[column] = true
+
+		switch strings.ToLower(strings.TrimSpace(direction)) {
+		case "", "asc":
+			fields = append(fields, SortField{Column: column})
+		case "desc":
+			fields = append(fields, SortField{Column: column, Descending: true})
+		default:
+			return nil, &ParamError{Field: "sort", Rule: "direction", Param: column, Message: fmt.Sprintf("sort direction for %q must be asc or desc", column)}
+		}
+	}
+
+	if len(fields) == 0 {
+		return defaults, nil
+	}
+	return fields, nil
+}
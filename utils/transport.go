@@ -0,0 +1,34 @@
+package utils
+
+import "net/http"
+
+// RequestIDHeader carries the request ID to and from other services
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDTransport sets the request ID header on outgoing requests
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+// PropagateRequestID returns a transport that sends the ID of the request an outgoing request is made for
+// (see ScopeFrom) in the X-Request-ID header, so the logs of the called service can be matched with ours.
+// Requests made outside a request, and those that set the header themselves, are sent unchanged. A nil
+// base uses http.DefaultTransport.
+func PropagateRequestID(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return requestIDTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestID := ScopeFrom(req.Context()).RequestID
+	if requestID == "" || req.Header.Get(RequestIDHeader) != "" {
+		return t.base.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the request it was given
+	req = req.Clone(req.Context())
+	req.Header.Set(RequestIDHeader, requestID)
+	return t.base.RoundTrip(req)
+}
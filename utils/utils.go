@@ -3,13 +3,17 @@ package utils
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
+	"go-backend-template/errcodes"
 	"go-backend-template/models"
 )
 
@@ -27,10 +31,22 @@ type SlogLogger struct {
 	logger *slog.Logger
 }
 
+// LoggerOptions configures logger output, format, and static fields
+type LoggerOptions struct {
+	Level      string
+	Format     string // "json" or "text"
+	Output     string // "stdout", "stderr", "file", or "both"
+	FilePath   string
+	MaxSizeMB  int
+	MaxAgeDays int
+	MaxBackups int
+	Fields     map[string]string
+}
+
 // NewLogger creates a new logger instance
-func NewLogger(level string) Logger {
+func NewLogger(opts LoggerOptions) (Logger, error) {
 	var logLevel slog.Level
-	switch strings.ToLower(level) {
+	switch strings.ToLower(opts.Level) {
 	case "debug":
 		logLevel = slog.LevelDebug
 	case "info":
@@ -43,14 +59,50 @@ func NewLogger(level string) Logger {
 		logLevel = slog.LevelInfo
 	}
 
-	opts := &slog.HandlerOptions{
+	var out io.Writer
+	switch strings.ToLower(opts.Output) {
+	case "stderr":
+		out = os.Stderr
+	case "file", "both":
+		fileWriter, err := NewRotatingFileWriter(opts.FilePath, opts.MaxSizeMB, opts.MaxAgeDays, opts.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		out = fileWriter
+		if strings.ToLower(opts.Output) == "both" {
+			out = io.MultiWriter(os.Stdout, fileWriter)
+		}
+	default:
+		out = os.Stdout
+	}
+
+	handlerOpts := &slog.HandlerOptions{
 		Level: logLevel,
 	}
 
-	handler := slog.NewJSONHandler(os.Stdout, opts)
-	logger := slog.New(handler)
+	var handler slog.Handler
+	if strings.ToLower(opts.Format) == "text" {
+		handler = slog.NewTextHandler(out, handlerOpts)
+	} else {
+		handler = slog.NewJSONHandler(out, handlerOpts)
+	}
+
+	// Attach static fields (service name, environment, ...) to every record
+	keys := make([]string, 0, len(opts.Fields))
+	for key := range opts.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-	return &SlogLogger{logger: logger}
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.String(key, opts.Fields[key]))
+	}
+	if len(attrs) > 0 {
+		handler = handler.WithAttrs(attrs)
+	}
+
+	return &SlogLogger{logger: slog.New(handler)}, nil
 }
 
 func (l *SlogLogger) Info(msg string, args ...interface{}) {
@@ -74,111 +126,6 @@ func (l *SlogLogger) Fatal(msg string, args ...interface{}) {
 	os.Exit(1)
 }
 
-// Localizer handles internationalization
-type Localizer struct {
-	DefaultLanguage string
-	translations    map[string]map[string]string
-}
-
-// NewLocalizer creates a new localizer instance
-func NewLocalizer(defaultLang string) (*Localizer, error) {
-	localizer := &Localizer{
-		DefaultLanguage: defaultLang,
-		translations:    make(map[string]map[string]string),
-	}
-
-	// Load translations
-	if err := localizer.loadTranslations(); err != nil {
-		return nil, err
-	}
-
-	return localizer, nil
-}
-
-// loadTranslations loads translation files
-func (l *Localizer) loadTranslations() error {
-	// English translations
-	l.translations["en"] = map[string]string{
-		"welcome":             "Welcome",
-		"user_not_found":      "User not found",
-		"invalid_credentials": "Invalid credentials",
-		"user_created":        "User created successfully",
-		"login_successful":    "Login successful",
-		"logout_successful":   "Logout successful",
-		"user_updated":        "User updated successfully",
-		"user_deleted":        "User deleted successfully",
-		"email_exists":        "Email already exists",
-		"username_exists":     "Username already exists",
-		"validation_error":    "Validation error",
-		"internal_error":      "Internal server error",
-		"unauthorized":        "Unauthorized access",
-		"forbidden":           "Access forbidden",
-		"not_found":           "Resource not found",
-		"bad_request":         "Bad request",
-	}
-
-	// Arabic translations
-	l.translations["ar"] = map[string]string{
-		"welcome":             "أهلا وسهلا",
-		"user_not_found":      "المستخدم غير موجود",
-		"invalid_credentials": "بيانات الاعتماد غير صحيحة",
-		"user_created":        "تم إنشاء المستخدم بنجاح",
-		"login_successful":    "تم تسجيل الدخول بنجاح",
-		"logout_successful":   "تم تسجيل الخروج بنجاح",
-		"user_updated":        "تم تحديث المستخدم بنجاح",
-		"user_deleted":        "تم حذف المستخدم بنجاح",
-		"email_exists":        "البريد الإلكتروني موجود بالفعل",
-		"username_exists":     "اسم المستخدم موجود بالفعل",
-		"validation_error":    "خطأ في التحقق",
-		"internal_error":      "خطأ في الخادم الداخلي",
-		"unauthorized":        "الوصول غير مصرح",
-		"forbidden":           "الوصول محظور",
-		"not_found":           "المورد غير موجود",
-		"bad_request":         "طلب خاطئ",
-	}
-
-	// German translations
-	l.translations["de"] = map[string]string{
-		"welcome":             "Willkommen",
-		"user_not_found":      "Benutzer nicht gefunden",
-		"invalid_credentials": "Ungültige Anmeldedaten",
-		"user_created":        "Benutzer erfolgreich erstellt",
-		"login_successful":    "Anmeldung erfolgreich",
-		"logout_successful":   "Abmeldung erfolgreich",
-		"user_updated":        "Benutzer erfolgreich aktualisiert",
-		"user_deleted":        "Benutzer erfolgreich gelöscht",
-		"email_exists":        "E-Mail bereits vorhanden",
-		"username_exists":     "Benutzername bereits vorhanden",
-		"validation_error":    "Validierungsfehler",
-		"internal_error":      "Interner Serverfehler",
-		"unauthorized":        "Nicht autorisierter Zugriff",
-		"forbidden":           "Zugriff verboten",
-		"not_found":           "Ressource nicht gefunden",
-		"bad_request":         "Fehlerhafte Anfrage",
-	}
-
-	return nil
-}
-
-// Get returns translated text for the given key and language
-func (l *Localizer) Get(lang, key string) string {
-	if translations, exists := l.translations[lang]; exists {
-		if text, exists := translations[key]; exists {
-			return text
-		}
-	}
-
-	// Fallback to default language
-	if translations, exists := l.translations[l.DefaultLanguage]; exists {
-		if text, exists := translations[key]; exists {
-			return text
-		}
-	}
-
-	// Return key if no translation found
-	return key
-}
-
 // PasswordUtils provides password hashing and verification
 type PasswordUtils struct{}
 
@@ -196,14 +143,45 @@ func (p *PasswordUtils) VerifyPassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
 
-// JWTUtils provides JWT token operations
+// JWTUtils provides JWT token operations and holds the signing secret, which may rotate at runtime
 type JWTUtils struct {
-	Secret string
+	mu       sync.RWMutex
+	secret   string
+	previous string
 }
 
 // NewJWTUtils creates a new JWT utils instance
 func NewJWTUtils(secret string) *JWTUtils {
-	return &JWTUtils{Secret: secret}
+	return &JWTUtils{secret: secret}
+}
+
+// Secret returns the current signing secret
+func (j *JWTUtils) Secret() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.secret
+}
+
+// VerificationSecrets returns the secrets accepted for verification: the current one,
+// then the previous one so tokens issued before a rotation stay valid until they expire
+func (j *JWTUtils) VerificationSecrets() []string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.previous == "" {
+		return []string{j.secret}
+	}
+	return []string{j.secret, j.previous}
+}
+
+// SetSecret rotates the signing secret, keeping the old one for verification
+func (j *JWTUtils) SetSecret(secret string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if secret == j.secret {
+		return
+	}
+	j.previous = j.secret
+	j.secret = secret
 }
 
 // GenerateToken generates a JWT token for a user
@@ -241,6 +219,31 @@ func (r *ResponseUtils) ErrorResponse(message, error string) models.APIResponse
 	}
 }
 
+// ValidationErrorResponse creates an error response with per-field details
+func (r *ResponseUtils) ValidationErrorResponse(message, error string, fieldErrors []models.FieldError) models.APIResponse {
+	return models.APIResponse{
+		Success: false,
+		Message: message,
+		Error:   error,
+		Errors:  fieldErrors,
+	}
+}
+
+// LocalizedErrorResponse creates an error response with the message of key in lang and the code
+// errcodes registers for key
+func (r *ResponseUtils) LocalizedErrorResponse(localizer *Localizer, lang, key, error string) models.APIResponse {
+	response := r.ErrorResponse(localizer.Get(lang, key), error)
+	response.Code = errcodes.ForKey(key)
+	return response
+}
+
+// LocalizedValidationErrorResponse is LocalizedErrorResponse with per-field details
+func (r *ResponseUtils) LocalizedValidationErrorResponse(localizer *Localizer, lang, key, error string, fieldErrors []models.FieldError) models.APIResponse {
+	response := r.ValidationErrorResponse(localizer.Get(lang, key), error, fieldErrors)
+	response.Code = errcodes.ForKey(key)
+	return response
+}
+
 // PaginatedResponse creates a paginated response
 func (r *ResponseUtils) PaginatedResponse(data interface{}, pagination models.Pagination) models.PaginatedResponse {
 	return models.PaginatedResponse{
@@ -254,12 +257,12 @@ type ValidationUtils struct{}
 
 // IsValidEmail checks if an email is valid
 func (v *ValidationUtils) IsValidEmail(email string) bool {
-	return strings.Contains(email, "@") && strings.Contains(email, ".")
+	return IsValidEmail(email)
 }
 
 // IsValidPassword checks if a password meets requirements
 func (v *ValidationUtils) IsValidPassword(password string) bool {
-	return len(password) >= 6
+	return IsStrongPassword(password)
 }
 
 // SanitizeString removes dangerous characters from string
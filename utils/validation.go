@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"go-backend-template/models"
+)
+
+// SetupValidator configures gin's validator so field errors use JSON/form names instead of Go field names
+func SetupValidator() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Replaces the validator's email rule, which accepts addresses that cannot receive mail
+	validate.RegisterValidation("email", func(fl validator.FieldLevel) bool {
+		return IsValidEmail(fl.Field().String())
+	})
+	validate.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return IsValidUsername(fl.Field().String())
+	})
+	validate.RegisterValidation("strongpassword", func(fl validator.FieldLevel) bool {
+		return IsStrongPassword(fl.Field().String())
+	})
+	validate.RegisterValidation("notdisposable", func(fl validator.FieldLevel) bool {
+		return !IsDisposableEmail(fl.Field().String())
+	})
+	validate.RegisterValidation("locale", func(fl validator.FieldLevel) bool {
+		return IsValidLocale(fl.Field().String())
+	})
+	// The profile fields below accept an empty value, which clears them
+	validate.RegisterValidation("birthday", func(fl validator.FieldLevel) bool {
+		return fl.Field().String() == "" || IsValidBirthday(fl.Field().String())
+	})
+	validate.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		return fl.Field().String() == "" || IsValidPhone(fl.Field().String())
+	})
+	validate.RegisterValidation("weburl", func(fl validator.FieldLevel) bool {
+		return fl.Field().String() == "" || IsWebURL(fl.Field().String())
+	})
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// FieldErrors translates binding errors and ParamErrors into localized per-field details; it returns nil
+// when err is none of these or a JSON type error
+func (l *Localizer) FieldErrors(lang string, err error) []models.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrors := make([]models.FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   fieldErr.Field(),
+				Rule:    fieldErr.Tag(),
+				Message: l.validationMessage(lang, fieldErr.Tag(), fieldErr.Field(), fieldErr.Param()),
+			})
+		}
+		return fieldErrors
+	}
+
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) {
+		return []models.FieldError{l.FieldError(lang, paramErr.Field, paramErr.Rule, paramErr.Param)}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []models.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: l.validationMessage(lang, "type", typeErr.Field, ""),
+		}}
+	}
+
+	return nil
+}
+
+// ParamError is a query parameter that failed a rule checked outside the validator, such as an unknown
+// sort field. FieldErrors reports it like a validation error, localized from the validation.<rule> key;
+// Message is the English description.
+type ParamError struct {
+	Field   string
+	Rule    string
+	Param   string
+	Message string
+}
+
+// Error returns the English description
+func (e *ParamError) Error() string {
+	return e.Message
+}
+
+// FieldError returns the localized detail for a rule that a handler checks itself
+func (l *Localizer) FieldError(lang, field, rule, param string) models.FieldError {
+	return models.FieldError{Field: field, Rule: rule, Message: l.validationMessage(lang, rule, field, param)}
+}
+
+// validationMessage renders the localized message for a validation rule, falling back to a generic one
+func (l *Localizer) validationMessage(lang, rule, field, param string) string {
+	key := "validation." + rule
+	template := l.Get(lang, key)
+	if template == key {
+		template = l.Get(lang, "validation.invalid")
+	}
+
+	return strings.NewReplacer("{field}", field, "{param}", param).Replace(template)
+}
+
+// usernamePattern allows 3-32 letters, digits, dots, underscores, and hyphens, starting and ending alphanumerically
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9._-]{1,30})[A-Za-z0-9]$`)
+
+// phonePattern matches E.164 numbers: a plus sign and up to 15 digits without a leading zero
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// localePattern matches language tags such as en, pt-BR, or zh-Hant-TW
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(?:[-_][A-Za-z0-9]{1,8})*$`)
+
+// IsValidUsername checks the username charset and length
+func IsValidUsername(username string) bool {
+	return usernamePattern.MatchString(username)
+}
+
+// IsValidLocale checks the syntax of a language tag; whether the language is supported is up to the Localizer
+func IsValidLocale(locale string) bool {
+	return len(locale) <= 35 && localePattern.MatchString(locale)
+}
+
+// IsValidPhone checks that phone is an E.164 number such as +14155550123
+func IsValidPhone(phone string) bool {
+	return phonePattern.MatchString(phone)
+}
+
+// IsWebURL checks that rawURL is an absolute http or https URL
+func IsWebURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// IsValidBirthday checks that birthday is a YYYY-MM-DD date between 1900 and today
+func IsValidBirthday(birthday string) bool {
+	date, err := time.Parse(time.DateOnly, birthday)
+	return err == nil && date.Year() >= 1900 && !date.After(time.Now().UTC())
+}
+
+// IsStrongPassword requires at least 8 characters with upper-case, lower-case, and numeric characters
+func IsStrongPassword(password string) bool {
+	if len(password) < 8 {
+		return false
+	}
+
+	var hasUpper, hasLower, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	return hasUpper && hasLower && hasDigit
+}
@@ -0,0 +1,171 @@
+package webhooks
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-backend-template/billing"
+)
+
+// tolerance is how far the signed timestamp of a delivery may be from now before it is rejected as a
+// replay, the same as for Stripe
+const tolerance = 5 * time.Minute
+
+// Delivery is a verified webhook request
+type Delivery struct {
+	Provider string
+	// ID identifies the delivery across its retries
+	ID string
+	// Type is the kind of event, empty when the provider does not tell
+	Type    string
+	Payload []byte
+}
+
+// Provider verifies the deliveries of one webhook provider
+type Provider interface {
+	// Verify checks the signature of a delivery and returns its ID and type, or ErrInvalidSignature
+	Verify(header http.Header, payload []byte) (id, eventType string, err error)
+}
+
+// StripeProvider verifies the Stripe-Signature header of Stripe events
+type StripeProvider struct {
+	secret string
+}
+
+// NewStripeProvider creates a provider for the webhook endpoint secret of Stripe
+func NewStripeProvider(secret string) *StripeProvider {
+	return &StripeProvider{secret: secret}
+}
+
+// Verify checks the Stripe-Signature header and returns the event ID and type
+func (p *StripeProvider) Verify(header http.Header, payload []byte) (string, string, error) {
+	event, err := billing.ConstructEvent(payload, header.Get("Stripe-Signature"), p.secret)
+	if errors.Is(err, billing.ErrInvalidSignature) {
+		return "", "", ErrInvalidSignature
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if event.ID == "" {
+		return "", "", errors.New("the Stripe event has no ID")
+	}
+	return event.ID, event.Type, nil
+}
+
+// SendGridProvider verifies the signed Event Webhook of SendGrid, which posts batches of email events
+// with an ECDSA signature of the timestamp and body
+type SendGridProvider struct {
+	key *ecdsa.PublicKey
+}
+
+// NewSendGridProvider creates a provider for the base64 verification key shown in the SendGrid settings
+// of the signed Event Webhook
+func NewSendGridProvider(publicKey string) (*SendGridProvider, error) {
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the SendGrid verification key: %w", err)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the SendGrid verification key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("the SendGrid verification key is not an ECDSA key")
+	}
+	return &SendGridProvider{key: key}, nil
+}
+
+// Verify checks the X-Twilio-Email-Event-Webhook-Signature header. A batch carries no ID of its own, so
+// it is identified by the hash of its body, which SendGrid sends unchanged when it retries.
+func (p *SendGridProvider) Verify(header http.Header, payload []byte) (string, string, error) {
+	timestamp := header.Get("X-Twilio-Email-Event-Webhook-Timestamp")
+	signature, err := base64.StdEncoding.DecodeString(header.Get("X-Twilio-Email-Event-Webhook-Signature"))
+	if err != nil || len(signature) == 0 || !fresh(timestamp) {
+		return "", "", ErrInvalidSignature
+	}
+
+	digest := sha256.New()
+	digest.Write([]byte(timestamp))
+	digest.Write(payload)
+	if !ecdsa.VerifyASN1(p.key, digest.Sum(nil), signature) {
+		return "", "", ErrInvalidSignature
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), "", nil
+}
+
+// StandardProvider verifies deliveries signed as the Standard Webhooks specification describes: the
+// webhook-signature header holds "v1,<base64 HMAC-SHA256 of id.timestamp.body>", and webhook-id and
+// webhook-timestamp the signed ID and time. Many providers sign this way, and the secret is the
+// "whsec_" secret they issue.
+type StandardProvider struct {
+	key []byte
+}
+
+// NewStandardProvider creates a provider for secret; a "whsec_" secret is base64, any other is used as is
+func NewStandardProvider(secret string) (*StandardProvider, error) {
+	encoded, ok := strings.CutPrefix(secret, "whsec_")
+	if !ok {
+		return &StandardProvider{key: []byte(secret)}, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the webhook secret: %w", err)
+	}
+	return &StandardProvider{key: key}, nil
+}
+
+// Verify checks the webhook-signature header and returns the webhook-id and the type field of the body
+func (p *StandardProvider) Verify(header http.Header, payload []byte) (string, string, error) {
+	id := header.Get("webhook-id")
+	timestamp := header.Get("webhook-timestamp")
+	if id == "" || !fresh(timestamp) {
+		return "", "", ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	verified := false
+	for _, signature := range strings.Fields(header.Get("webhook-signature")) {
+		version, encoded, _ := strings.Cut(signature, ",")
+		if decoded, err := base64.StdEncoding.DecodeString(encoded); version == "v1" && err == nil && hmac.Equal(decoded, expected) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return "", "", ErrInvalidSignature
+	}
+
+	var event struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(payload, &event)
+	return id, event.Type, nil
+}
+
+// fresh reports whether timestamp, in Unix seconds, is within tolerance of now
+func fresh(timestamp string) bool {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(unix, 0))
+	return age <= tolerance && age >= -tolerance
+}
@@ -0,0 +1,206 @@
+// Package webhooks receives provider callbacks: it verifies each delivery with the signature scheme of
+// its provider, processes a delivery once however often the provider retries it, and keeps the deliveries
+// that keep failing as dead letters for an admin to replay
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend-template/models"
+	"go-backend-template/utils"
+)
+
+// processingLease is how long a delivery stays with the instance processing it; a retry after that
+// processes it again, as the instance is taken to have stopped
+const processingLease = 5 * time.Minute
+
+var (
+	// ErrUnknownProvider is returned for deliveries to a provider that is not configured
+	ErrUnknownProvider = errors.New("unknown webhook provider")
+	// ErrInvalidSignature is returned for deliveries whose signature does not verify
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInProgress is returned for a delivery another request is processing
+	ErrInProgress = errors.New("webhook delivery is being processed")
+	// ErrNotFound is returned when no delivery has the ID
+	ErrNotFound = errors.New("webhook delivery not found")
+	// ErrProcessed is returned when replaying a delivery that was processed successfully
+	ErrProcessed = errors.New("webhook delivery already processed")
+)
+
+// HandlerFunc processes a verified delivery. A delivery can be processed more than once when an
+// instance stops midway, so handlers must tolerate repeats.
+type HandlerFunc func(ctx context.Context, delivery Delivery) error
+
+// Receiver verifies and processes the webhook deliveries of the registered providers
+type Receiver struct {
+	store       Store
+	maxAttempts int
+	logger      utils.Logger
+
+	mu        sync.RWMutex
+	providers map[string]Provider
+	handlers  map[string]HandlerFunc
+}
+
+// NewReceiver creates a receiver storing deliveries in store; a delivery failing maxAttempts times
+// becomes a dead letter
+func NewReceiver(store Store, maxAttempts int, logger utils.Logger) *Receiver {
+	return &Receiver{
+		store:       store,
+		maxAttempts: maxAttempts,
+		logger:      logger,
+		providers:   make(map[string]Provider),
+		handlers:    make(map[string]HandlerFunc),
+	}
+}
+
+// Register accepts the deliveries of provider name, verified by provider
+func (r *Receiver) Register(name string, provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[name] = provider
+}
+
+// Handle processes the deliveries of provider name with handler. The deliveries of a provider without a
+// handler are acknowledged and recorded as processed.
+func (r *Receiver) Handle(name string, handler HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[name] = handler
+}
+
+// Providers returns the names of the registered providers, sorted
+func (r *Receiver) Providers() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Receive verifies a delivery to provider and processes it unless it was processed before. The returned
+// delivery is failed when processing failed and the provider should retry, and dead when it failed for
+// the last time.
+func (r *Receiver) Receive(ctx context.Context, provider string, header http.Header, payload []byte) (*models.WebhookEvent, error) {
+	r.mu.RLock()
+	verifier, ok := r.providers[provider]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	id, eventType, err := verifier.Verify(header, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	event := &models.WebhookEvent{
+		ID:         eventID.String(),
+		Provider:   provider,
+		EventID:    id,
+		Type:       eventType,
+		Status:     models.WebhookProcessing,
+		Attempts:   1,
+		Payload:    string(payload),
+		ReceivedAt: now,
+		UpdatedAt:  now,
+	}
+	stored, acquired, err := r.store.Acquire(ctx, event, now.Add(-processingLease), models.WebhookFailed)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		if stored.Status == models.WebhookProcessing {
+			return nil, ErrInProgress
+		}
+		r.logger.Debug("Acknowledged a repeated webhook delivery", "provider", provider, "event_id", id, "status", stored.Status)
+		return stored, nil
+	}
+	return stored, r.process(ctx, stored)
+}
+
+// Replay processes a failed or dead delivery again, without waiting for the provider to retry it
+func (r *Receiver) Replay(ctx context.Context, id string) (*models.WebhookEvent, error) {
+	event, err := r.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == models.WebhookProcessed {
+		return nil, ErrProcessed
+	}
+
+	now := time.Now().UTC()
+	event.UpdatedAt = now
+	stored, acquired, err := r.store.Acquire(ctx, event, now.Add(-processingLease), models.WebhookFailed, models.WebhookDead)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		if stored.Status == models.WebhookProcessed {
+			return nil, ErrProcessed
+		}
+		return nil, ErrInProgress
+	}
+	return stored, r.process(ctx, stored)
+}
+
+// List returns a page of the matching deliveries, newest first, and the number of matches
+func (r *Receiver) List(ctx context.Context, opts ListOptions) ([]models.WebhookEvent, int64, error) {
+	return r.store.List(ctx, opts)
+}
+
+// process runs the handler of an acquired delivery and records the outcome. A failure after the last
+// attempt makes the delivery a dead letter; only an error saving the outcome is returned.
+func (r *Receiver) process(ctx context.Context, event *models.WebhookEvent) error {
+	r.mu.RLock()
+	handler := r.handlers[event.Provider]
+	r.mu.RUnlock()
+
+	var err error
+	if handler != nil {
+		err = r.run(ctx, handler, Delivery{Provider: event.Provider, ID: event.EventID, Type: event.Type, Payload: []byte(event.Payload)})
+	} else {
+		r.logger.Debug("No handler for the webhook delivery", "provider", event.Provider, "event_id", event.EventID, "type", event.Type)
+	}
+
+	now := time.Now().UTC()
+	event.UpdatedAt = now
+	switch {
+	case err == nil:
+		event.Status, event.LastError, event.ProcessedAt = models.WebhookProcessed, "", &now
+	case event.Attempts >= r.maxAttempts:
+		event.Status, event.LastError = models.WebhookDead, err.Error()
+		r.logger.Error("Webhook delivery failed for the last time and was kept as a dead letter",
+			"provider", event.Provider, "event_id", event.EventID, "id", event.ID, "attempts", event.Attempts, "error", err)
+	default:
+		event.Status, event.LastError = models.WebhookFailed, err.Error()
+		r.logger.Warn("Webhook delivery failed", "provider", event.Provider, "event_id", event.EventID, "attempts", event.Attempts, "error", err)
+	}
+	return r.store.Save(ctx, event)
+}
+
+// run calls handler, turning a panic into an error so the delivery is recorded as failed
+func (r *Receiver) run(ctx context.Context, handler HandlerFunc, delivery Delivery) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("webhook handler panicked: %v", recovered)
+		}
+	}()
+	return handler(ctx, delivery)
+}
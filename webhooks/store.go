@@ -0,0 +1,329 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend-template/database"
+	"go-backend-template/models"
+)
+
+// ListOptions selects a page of deliveries
+type ListOptions struct {
+	Page     int
+	PageSize int
+	Provider string
+	Status   string
+}
+
+// Store persists webhook deliveries, one per provider and event ID
+type Store interface {
+	// Acquire inserts event, which is processing, unless a delivery with its provider and event ID is
+	// stored. A stored delivery is taken over, set processing with one more attempt, when its status is
+	// one of from, or it has been processing since before staleBefore. It returns the stored delivery and
+	// whether it was inserted or taken over, in which case the caller processes it.
+	Acquire(ctx context.Context, event *models.WebhookEvent, staleBefore time.Time, from ...string) (*models.WebhookEvent, bool, error)
+	// Get returns the delivery with id or ErrNotFound
+	Get(ctx context.Context, id string) (*models.WebhookEvent, error)
+	// Save updates the status, attempts, error, and times of a delivery
+	Save(ctx context.Context, event *models.WebhookEvent) error
+	// List returns a page of the matching deliveries, newest first, and the number of matches
+	List(ctx context.Context, opts ListOptions) ([]models.WebhookEvent, int64, error)
+}
+
+// MemoryStore is an in-process Store, suitable for development and tests; deliveries are lost on restart
+type MemoryStore struct {
+	mu     sync.Mutex
+	events []*models.WebhookEvent
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Acquire inserts or takes over the delivery as Store describes
+func (s *MemoryStore) Acquire(ctx context.Context, event *models.WebhookEvent, staleBefore time.Time, from ...string) (*models.WebhookEvent, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stored := range s.events {
+		if stored.Provider != event.Provider || stored.EventID != event.EventID {
+			continue
+		}
+		if !slices.Contains(from, stored.Status) && !(stored.Status == models.WebhookProcessing && stored.UpdatedAt.Before(staleBefore)) {
+			copied := *stored
+			return &copied, false, nil
+		}
+		stored.Status = models.WebhookProcessing
+		stored.Attempts++
+		stored.UpdatedAt = event.UpdatedAt
+		copied := *stored
+		return &copied, true, nil
+	}
+
+	stored := *event
+	s.events = append(s.events, &stored)
+	return event, true, nil
+}
+
+// Get returns the delivery with id
+func (s *MemoryStore) Get(ctx context.Context, id string) (*models.WebhookEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stored := range s.events {
+		if stored.ID == id {
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Save replaces the stored delivery with the ID of event
+func (s *MemoryStore) Save(ctx context.Context, event *models.WebhookEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stored := range s.events {
+		if stored.ID == event.ID {
+			*stored = *event
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// List returns a page of the matching deliveries
+func (s *MemoryStore) List(ctx context.Context, opts ListOptions) ([]models.WebhookEvent, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matches := []models.WebhookEvent{}
+	for i := len(s.events) - 1; i >= 0; i-- {
+		event := s.events[i]
+		if opts.Provider != "" && event.Provider != opts.Provider || opts.Status != "" && event.Status != opts.Status {
+			continue
+		}
+		matches = append(matches, *event)
+	}
+
+	total := int64(len(matches))
+	start := min((opts.Page-1)*opts.PageSize, len(matches))
+	end := min(start+opts.PageSize, len(matches))
+	return matches[start:end], total, nil
+}
+
+// PostgresStore persists deliveries in PostgreSQL
+type PostgresStore struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresStore creates a PostgreSQL-backed store; the table is created by the migrations
+func NewPostgresStore(db *database.PostgresDB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Acquire inserts the delivery, relying on the unique index on provider and event ID to find a stored
+// one, and takes that over with a conditional update, so two instances never process it at once
+func (s *PostgresStore) Acquire(ctx context.Context, event *models.WebhookEvent, staleBefore time.Time, from ...string) (*models.WebhookEvent, bool, error) {
+	db := s.db.WithContext(ctx)
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return event, true, nil
+	}
+
+	// The empty status matches no delivery and keeps the IN list valid when from is empty
+	statuses := append([]string{""}, from...)
+	result = db.Model(&models.WebhookEvent{}).
+		Where("provider = ? AND event_id = ?", event.Provider, event.EventID).
+		Where("status IN ? OR (status = ? AND updated_at < ?)", statuses, models.WebhookProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     models.WebhookProcessing,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"updated_at": event.UpdatedAt,
+		})
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+
+	var stored models.WebhookEvent
+	err := db.Where("provider = ? AND event_id = ?", event.Provider, event.EventID).First(&stored).Error
+	if err != nil {
+		return nil, false, err
+	}
+	return &stored, result.RowsAffected == 1, nil
+}
+
+// Get returns the delivery with id
+func (s *PostgresStore) Get(ctx context.Context, id string) (*models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Save updates the delivery with the ID of event
+func (s *PostgresStore) Save(ctx context.Context, event *models.WebhookEvent) error {
+	return s.db.WithContext(ctx).Model(event).Select("status", "attempts", "last_error", "updated_at", "processed_at").Updates(event).Error
+}
+
+// List returns a page of the matching deliveries
+func (s *PostgresStore) List(ctx context.Context, opts ListOptions) ([]models.WebhookEvent, int64, error) {
+	db := s.db.Replica().WithContext(ctx).Model(&models.WebhookEvent{})
+	if opts.Provider != "" {
+		db = db.Where("provider = ?", opts.Provider)
+	}
+	if opts.Status != "" {
+		db = db.Where("status = ?", opts.Status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	events := []models.WebhookEvent{}
+	err := db.Order("received_at DESC, id DESC").Offset((opts.Page - 1) * opts.PageSize).Limit(opts.PageSize).Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// MongoStore persists deliveries in MongoDB
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a MongoDB-backed store and ensures its indexes exist
+func NewMongoStore(ctx context.Context, db *database.MongoDB) (*MongoStore, error) {
+	collection := db.Collection("webhook_events")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "provider", Value: 1}, {Key: "event_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "received_at", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection}, nil
+}
+
+// Acquire inserts the delivery, relying on the unique index on provider and event ID to find a stored
+// one, and takes that over with a conditional update, so two instances never process it at once
+func (s *MongoStore) Acquire(ctx context.Context, event *models.WebhookEvent, staleBefore time.Time, from ...string) (*models.WebhookEvent, bool, error) {
+	_, err := s.collection.InsertOne(ctx, event)
+	if err == nil {
+		return event, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, false, err
+	}
+
+	delivery := bson.M{"provider": event.Provider, "event_id": event.EventID}
+	filter := bson.M{
+		"provider": event.Provider,
+		"event_id": event.EventID,
+		"$or": bson.A{
+			// Copied so that an empty from encodes as an array rather than null
+			bson.M{"status": bson.M{"$in": append([]string{}, from...)}},
+			bson.M{"status": models.WebhookProcessing, "updated_at": bson.M{"$lt": staleBefore}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": models.WebhookProcessing, "updated_at": event.UpdatedAt},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	var stored models.WebhookEvent
+	err = s.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&stored)
+	if err == nil {
+		return &stored, true, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, false, err
+	}
+	if err := s.collection.FindOne(ctx, delivery).Decode(&stored); err != nil {
+		return nil, false, err
+	}
+	return &stored, false, nil
+}
+
+// Get returns the delivery with id
+func (s *MongoStore) Get(ctx context.Context, id string) (*models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Save updates the delivery with the ID of event
+func (s *MongoStore) Save(ctx context.Context, event *models.WebhookEvent) error {
+	_, err := s.collection.UpdateByID(ctx, event.ID, bson.M{"$set": bson.M{
+		"status":       event.Status,
+		"attempts":     event.Attempts,
+		"last_error":   event.LastError,
+		"updated_at":   event.UpdatedAt,
+		"processed_at": event.ProcessedAt,
+	}})
+	return err
+}
+
+// List returns a page of the matching deliveries
+func (s *MongoStore) List(ctx context.Context, opts ListOptions) ([]models.WebhookEvent, int64, error) {
+	filter := bson.M{}
+	if opts.Provider != "" {
+		filter["provider"] = opts.Provider
+	}
+	if opts.Status != "" {
+		filter["status"] = opts.Status
+	}
+
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "received_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((opts.Page - 1) * opts.PageSize)).
+		SetLimit(int64(opts.PageSize))
+	cursor, err := s.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.WebhookEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}